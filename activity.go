@@ -0,0 +1,272 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const salesforceDateFormat = "2006-01-02"
+
+// Key prefixes of the sObjects that can be the WhoId of an activity
+const (
+	contactKeyPrefix = "003"
+	leadKeyPrefix    = "00Q"
+)
+
+// RecurrenceType is the pattern of a recurring task or event
+type RecurrenceType string
+
+const (
+	RecursDaily        RecurrenceType = "RecursDaily"
+	RecursEveryWeekday RecurrenceType = "RecursEveryWeekday"
+	RecursWeekly       RecurrenceType = "RecursWeekly"
+	RecursMonthly      RecurrenceType = "RecursMonthly"    // on DayOfMonth
+	RecursMonthlyNth   RecurrenceType = "RecursMonthlyNth" // on the Instance of DaysOfWeek
+	RecursYearly       RecurrenceType = "RecursYearly"     // on DayOfMonth of MonthOfYear
+	RecursYearlyNth    RecurrenceType = "RecursYearlyNth"  // on the Instance of DaysOfWeek in MonthOfYear
+)
+
+// Instances of a day of the week in a month, for RecursMonthlyNth and RecursYearlyNth
+const (
+	RecurrenceFirst  = "First"
+	RecurrenceSecond = "Second"
+	RecurrenceThird  = "Third"
+	RecurrenceFourth = "Fourth"
+	RecurrenceLast   = "Last"
+)
+
+// Recurrence is the recurrence pattern of a task or event. Only the fields of its Type are sent.
+type Recurrence struct {
+	Type        RecurrenceType
+	Interval    int            // every Interval days, weeks, or months; 1 if empty
+	DaysOfWeek  []time.Weekday // for RecursWeekly, RecursMonthlyNth, and RecursYearlyNth
+	DayOfMonth  int            // for RecursMonthly and RecursYearly
+	Instance    string         // for RecursMonthlyNth and RecursYearlyNth, such as RecurrenceFirst
+	MonthOfYear time.Month     // for RecursYearly and RecursYearlyNth
+	Start       time.Time
+	End         time.Time // date of the last occurrence
+	TimeZone    string    // time zone sid key, such as America/New_York; the user's if empty
+}
+
+// TaskRecord is a task to create with CreateTask
+type TaskRecord struct {
+	Subject          string
+	Description      string
+	Status           string // the org default, such as Not Started, if empty
+	Priority         string // the org default, such as Normal, if empty
+	OwnerId          string // the running user if empty
+	ActivityDate     time.Time
+	WhoId            string // contact or lead the task is about
+	WhatId           string // non-human record the task is about, such as an account or opportunity
+	ReminderDateTime time.Time
+	Recurrence       *Recurrence
+	Fields           map[string]any // other fields, such as custom fields
+}
+
+// EventRecord is an event to create with CreateEvent. An event needs an EndDateTime or a
+// DurationInMinutes unless it is an all day event.
+type EventRecord struct {
+	Subject           string
+	Description       string
+	Location          string
+	OwnerId           string // the running user if empty
+	StartDateTime     time.Time
+	EndDateTime       time.Time
+	DurationInMinutes int
+	IsAllDayEvent     bool // only the date of StartDateTime and EndDateTime is used
+	ShowAs            string
+	WhoId             string // contact or lead the event is about
+	WhatId            string // non-human record the event is about, such as an account or opportunity
+	ReminderDateTime  time.Time
+	Recurrence        *Recurrence
+	Fields            map[string]any // other fields, such as custom fields
+}
+
+// CreateTask creates a task, validating that WhoId is a contact or lead and WhatId is not, setting
+// IsReminderSet with ReminderDateTime, and sending the fields of its recurrence pattern
+func (sf *Salesforce) CreateTask(task TaskRecord) (SalesforceResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResult{}, authErr
+	}
+	record, err := activityRecord(task.WhoId, task.WhatId, task.ReminderDateTime, task.Fields)
+	if err != nil {
+		return SalesforceResult{}, err
+	}
+	setNonEmptyField(record, "Subject", task.Subject)
+	setNonEmptyField(record, "Description", task.Description)
+	setNonEmptyField(record, "Status", task.Status)
+	setNonEmptyField(record, "Priority", task.Priority)
+	setNonEmptyField(record, "OwnerId", task.OwnerId)
+	if !task.ActivityDate.IsZero() {
+		record["ActivityDate"] = task.ActivityDate.Format(salesforceDateFormat)
+	}
+	if task.Recurrence != nil {
+		if err := setRecurrenceFields(record, *task.Recurrence, false); err != nil {
+			return SalesforceResult{}, err
+		}
+	}
+	return sf.InsertOne("Task", record)
+}
+
+// CreateEvent creates an event, validating that WhoId is a contact or lead and WhatId is not, setting
+// IsReminderSet with ReminderDateTime, and sending the fields of its recurrence pattern
+func (sf *Salesforce) CreateEvent(event EventRecord) (SalesforceResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResult{}, authErr
+	}
+	if event.StartDateTime.IsZero() {
+		return SalesforceResult{}, errors.New("event start date time is required")
+	}
+	if !event.IsAllDayEvent && event.EndDateTime.IsZero() && event.DurationInMinutes <= 0 {
+		return SalesforceResult{}, errors.New(
+			"event end date time or duration is required unless it is an all day event",
+		)
+	}
+	if !event.EndDateTime.IsZero() && event.EndDateTime.Before(event.StartDateTime) {
+		return SalesforceResult{}, errors.New("event end date time is before its start")
+	}
+	record, err := activityRecord(event.WhoId, event.WhatId, event.ReminderDateTime, event.Fields)
+	if err != nil {
+		return SalesforceResult{}, err
+	}
+	setNonEmptyField(record, "Subject", event.Subject)
+	setNonEmptyField(record, "Description", event.Description)
+	setNonEmptyField(record, "Location", event.Location)
+	setNonEmptyField(record, "OwnerId", event.OwnerId)
+	setNonEmptyField(record, "ShowAs", event.ShowAs)
+	if event.IsAllDayEvent {
+		record["IsAllDayEvent"] = true
+		record["ActivityDate"] = event.StartDateTime.Format(salesforceDateFormat)
+		if !event.EndDateTime.IsZero() {
+			record["EndDate"] = event.EndDateTime.Format(salesforceDateFormat)
+		}
+	} else {
+		record["StartDateTime"] = event.StartDateTime.UTC().Format(soqlDateTimeFormat)
+		if !event.EndDateTime.IsZero() {
+			record["EndDateTime"] = event.EndDateTime.UTC().Format(soqlDateTimeFormat)
+		} else {
+			record["DurationInMinutes"] = event.DurationInMinutes
+		}
+	}
+	if event.Recurrence != nil {
+		if err := setRecurrenceFields(record, *event.Recurrence, true); err != nil {
+			return SalesforceResult{}, err
+		}
+	}
+	return sf.InsertOne("Event", record)
+}
+
+// activityRecord returns the fields shared by tasks and events after validating their polymorphic
+// WhoId and WhatId
+func activityRecord(
+	whoId string,
+	whatId string,
+	reminder time.Time,
+	fields map[string]any,
+) (map[string]any, error) {
+	if whoId != "" {
+		prefix, err := IdKeyPrefix(whoId)
+		if err != nil {
+			return nil, err
+		}
+		if prefix != contactKeyPrefix && prefix != leadKeyPrefix {
+			return nil, fmt.Errorf("WhoId must be a contact or lead: %s", whoId)
+		}
+		if prefix == leadKeyPrefix && whatId != "" {
+			return nil, errors.New("an activity of a lead cannot have a WhatId")
+		}
+	}
+	if whatId != "" {
+		prefix, err := IdKeyPrefix(whatId)
+		if err != nil {
+			return nil, err
+		}
+		if prefix == contactKeyPrefix || prefix == leadKeyPrefix {
+			return nil, fmt.Errorf("WhatId cannot be a contact or lead, use WhoId: %s", whatId)
+		}
+	}
+
+	record := map[string]any{}
+	for name, value := range fields {
+		record[name] = value
+	}
+	setNonEmptyField(record, "WhoId", whoId)
+	setNonEmptyField(record, "WhatId", whatId)
+	if !reminder.IsZero() {
+		record["IsReminderSet"] = true
+		record["ReminderDateTime"] = reminder.UTC().Format(soqlDateTimeFormat)
+	}
+	return record, nil
+}
+
+func setNonEmptyField(record map[string]any, name string, value string) {
+	if value != "" {
+		record[name] = value
+	}
+}
+
+// setRecurrenceFields sets the recurrence fields of a task or event, whose recurrence starts with
+// a datetime for events and a date for tasks
+func setRecurrenceFields(record map[string]any, recurrence Recurrence, isEvent bool) error {
+	if recurrence.Start.IsZero() || recurrence.End.IsZero() {
+		return errors.New("recurrence start and end are required")
+	}
+	if recurrence.End.Before(recurrence.Start) {
+		return errors.New("recurrence end is before its start")
+	}
+	interval := max(recurrence.Interval, 1)
+	mask := 0
+	for _, day := range recurrence.DaysOfWeek {
+		mask |= 1 << day // Sunday is 1, Monday is 2, through Saturday as 64
+	}
+
+	switch recurrence.Type {
+	case RecursDaily:
+		record["RecurrenceInterval"] = interval
+	case RecursEveryWeekday:
+		mask = 0b0111110 // Monday through Friday
+	case RecursWeekly:
+		if mask == 0 {
+			return errors.New("weekly recurrence requires days of the week")
+		}
+		record["RecurrenceInterval"] = interval
+	case RecursMonthly, RecursYearly:
+		if recurrence.DayOfMonth < 1 || recurrence.DayOfMonth > 31 {
+			return fmt.Errorf("%s requires a day of the month", recurrence.Type)
+		}
+		record["RecurrenceDayOfMonth"] = recurrence.DayOfMonth
+	case RecursMonthlyNth, RecursYearlyNth:
+		if mask == 0 || recurrence.Instance == "" {
+			return fmt.Errorf("%s requires days of the week and an instance", recurrence.Type)
+		}
+		record["RecurrenceInstance"] = recurrence.Instance
+	default:
+		return fmt.Errorf("invalid recurrence type: %q", recurrence.Type)
+	}
+	switch recurrence.Type {
+	case RecursMonthly, RecursMonthlyNth:
+		record["RecurrenceInterval"] = interval
+	case RecursYearly, RecursYearlyNth:
+		if recurrence.MonthOfYear == 0 {
+			return fmt.Errorf("%s requires a month of the year", recurrence.Type)
+		}
+		record["RecurrenceMonthOfYear"] = recurrence.MonthOfYear.String()
+	}
+	if mask != 0 {
+		record["RecurrenceDayOfWeekMask"] = mask
+	}
+
+	record["IsRecurrence"] = true
+	record["RecurrenceType"] = string(recurrence.Type)
+	record["RecurrenceEndDateOnly"] = recurrence.End.Format(salesforceDateFormat)
+	if isEvent {
+		record["RecurrenceStartDateTime"] = recurrence.Start.UTC().Format(soqlDateTimeFormat)
+	} else {
+		record["RecurrenceStartDateOnly"] = recurrence.Start.Format(salesforceDateFormat)
+	}
+	setNonEmptyField(record, "RecurrenceTimeZoneSidKey", recurrence.TimeZone)
+	return nil
+}
@@ -0,0 +1,206 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_activityRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		whoId   string
+		whatId  string
+		wantErr bool
+	}{
+		{name: "contact_and_account", whoId: "003000000000001AAA", whatId: "001000000000001AAA"},
+		{name: "lead", whoId: "00Q000000000001EAA"},
+		{
+			name:    "lead_with_what",
+			whoId:   "00Q000000000001EAA",
+			whatId:  "001000000000001AAA",
+			wantErr: true,
+		},
+		{name: "account_as_who", whoId: "001000000000001AAA", wantErr: true},
+		{name: "contact_as_what", whatId: "003000000000001AAA", wantErr: true},
+		{name: "invalid_id", whatId: "bad", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := activityRecord(tt.whoId, tt.whatId, time.Time{}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("activityRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_setRecurrenceFields(t *testing.T) {
+	start := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		isEvent    bool
+		want       map[string]any
+		wantErr    bool
+	}{
+		{
+			name: "weekly_task",
+			recurrence: Recurrence{
+				Type:       RecursWeekly,
+				Interval:   2,
+				DaysOfWeek: []time.Weekday{time.Monday, time.Wednesday},
+				Start:      start,
+				End:        end,
+			},
+			want: map[string]any{
+				"IsRecurrence":            true,
+				"RecurrenceType":          "RecursWeekly",
+				"RecurrenceInterval":      2,
+				"RecurrenceDayOfWeekMask": 10,
+				"RecurrenceStartDateOnly": "2024-03-04",
+				"RecurrenceEndDateOnly":   "2024-12-31",
+			},
+		},
+		{
+			name: "every_weekday_event",
+			recurrence: Recurrence{
+				Type:     RecursEveryWeekday,
+				Start:    start,
+				End:      end,
+				TimeZone: "America/New_York",
+			},
+			isEvent: true,
+			want: map[string]any{
+				"IsRecurrence":             true,
+				"RecurrenceType":           "RecursEveryWeekday",
+				"RecurrenceDayOfWeekMask":  62,
+				"RecurrenceStartDateTime":  "2024-03-04T15:00:00Z",
+				"RecurrenceEndDateOnly":    "2024-12-31",
+				"RecurrenceTimeZoneSidKey": "America/New_York",
+			},
+		},
+		{
+			name: "yearly_nth",
+			recurrence: Recurrence{
+				Type:        RecursYearlyNth,
+				DaysOfWeek:  []time.Weekday{time.Thursday},
+				Instance:    RecurrenceFourth,
+				MonthOfYear: time.November,
+				Start:       start,
+				End:         end,
+			},
+			want: map[string]any{
+				"IsRecurrence":            true,
+				"RecurrenceType":          "RecursYearlyNth",
+				"RecurrenceDayOfWeekMask": 16,
+				"RecurrenceInstance":      "Fourth",
+				"RecurrenceMonthOfYear":   "November",
+				"RecurrenceStartDateOnly": "2024-03-04",
+				"RecurrenceEndDateOnly":   "2024-12-31",
+			},
+		},
+		{
+			name:       "monthly_without_day",
+			recurrence: Recurrence{Type: RecursMonthly, Start: start, End: end},
+			wantErr:    true,
+		},
+		{
+			name:       "weekly_without_days",
+			recurrence: Recurrence{Type: RecursWeekly, Start: start, End: end},
+			wantErr:    true,
+		},
+		{
+			name:       "end_before_start",
+			recurrence: Recurrence{Type: RecursDaily, Start: end, End: start},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid_type",
+			recurrence: Recurrence{Type: "RecursHourly", Start: start, End: end},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := map[string]any{}
+			err := setRecurrenceFields(record, tt.recurrence, tt.isEvent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setRecurrenceFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(record, tt.want) {
+				t.Errorf("record = %v, want %v", record, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_CreateTaskAndEvent(t *testing.T) {
+	var paths []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		record := map[string]any{}
+		_ = json.Unmarshal(body, &record)
+		delete(record, "attributes")
+		bodies = append(bodies, record)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"00T000000000001AAA","success":true,"errors":[]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	due := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	result, err := sf.CreateTask(TaskRecord{
+		Subject:          "Follow up",
+		WhoId:            "003000000000001AAA",
+		WhatId:           "006000000000001AAA",
+		ActivityDate:     due,
+		ReminderDateTime: due.Add(9 * time.Hour),
+		Fields:           map[string]any{"Region__c": "EMEA"},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("CreateTask() = %v, %v", result, err)
+	}
+	_, err = sf.CreateEvent(EventRecord{
+		Subject:           "Demo",
+		WhoId:             "00Q000000000001EAA",
+		StartDateTime:     time.Date(2024, 3, 8, 10, 0, 0, 0, time.FixedZone("CET", 3600)),
+		DurationInMinutes: 30,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+	if _, err := sf.CreateEvent(EventRecord{Subject: "No end", StartDateTime: due}); err == nil {
+		t.Error("CreateEvent() expected an error without an end or duration")
+	}
+
+	prefix := "/services/data/" + apiVersion + "/sobjects/"
+	if !reflect.DeepEqual(paths, []string{prefix + "Task", prefix + "Event"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	wantTask := map[string]any{
+		"Subject":          "Follow up",
+		"WhoId":            "003000000000001AAA",
+		"WhatId":           "006000000000001AAA",
+		"ActivityDate":     "2024-03-08",
+		"IsReminderSet":    true,
+		"ReminderDateTime": "2024-03-08T09:00:00Z",
+		"Region__c":        "EMEA",
+	}
+	wantEvent := map[string]any{
+		"Subject":           "Demo",
+		"WhoId":             "00Q000000000001EAA",
+		"StartDateTime":     "2024-03-08T09:00:00Z",
+		"DurationInMinutes": float64(30),
+	}
+	if !reflect.DeepEqual(bodies, []map[string]any{wantTask, wantEvent}) {
+		t.Errorf("bodies = %v, want %v", bodies, []map[string]any{wantTask, wantEvent})
+	}
+}
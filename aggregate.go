@@ -0,0 +1,102 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// AggregateResult is a row of an aggregate query. Aggregate functions are keyed by their alias, or by
+// expr0, expr1, and so on when they have none. Grouped fields are keyed by their field name.
+type AggregateResult map[string]any
+
+// Int returns an aggregate value as an int
+func (r AggregateResult) Int(alias string) (int, bool) {
+	if value, ok := r.value(alias).(int64); ok {
+		return int(value), true
+	}
+	value, ok := r.Float(alias)
+	if !ok || value != math.Trunc(value) {
+		return 0, false
+	}
+	return int(value), true
+}
+
+// Float returns an aggregate value as a float64
+func (r AggregateResult) Float(alias string) (float64, bool) {
+	return numberValue(r.value(alias))
+}
+
+// String returns a grouped field or aggregate value as a string, such as the result of MAX on a date
+func (r AggregateResult) String(alias string) (string, bool) {
+	value, ok := r.value(alias).(string)
+	return value, ok
+}
+
+func (r AggregateResult) value(alias string) any {
+	if value, ok := r[alias]; ok {
+		return value
+	}
+	for key, value := range r {
+		if strings.EqualFold(key, alias) {
+			return value
+		}
+	}
+	return nil
+}
+
+// QueryAggregate performs an aggregate SOQL query, such as one with GROUP BY, and returns its rows.
+// Rows can also be decoded into structs with Query by tagging fields with their alias or exprN.
+func (sf *Salesforce) QueryAggregate(query string) ([]AggregateResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	records, err := queryAllRecords(context.Background(), sf, query)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]AggregateResult, len(records))
+	for i, record := range records {
+		delete(record, "attributes")
+		results[i] = AggregateResult(record)
+	}
+	return results, nil
+}
+
+// Count performs a count query and returns the number of records. The query can use COUNT(),
+// which reports the count as the query's total size, or a single aggregate such as COUNT(Id).
+func (sf *Salesforce) Count(query string) (int, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return 0, authErr
+	}
+
+	queryResp, err := getQueryPage(context.Background(), sf, "/query/?q="+url.QueryEscape(query))
+	if err != nil {
+		return 0, err
+	}
+	switch len(queryResp.Records) {
+	case 0:
+		return queryResp.TotalSize, nil
+	case 1:
+		record := queryResp.Records[0]
+		delete(record, "attributes")
+		if len(record) == 1 {
+			for alias := range record {
+				if count, ok := AggregateResult(record).Int(alias); ok {
+					return count, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("count query must return a single aggregate value, got %v", record)
+	default:
+		return 0, fmt.Errorf(
+			"count query returned %d rows, use QueryAggregate for grouped counts",
+			len(queryResp.Records),
+		)
+	}
+}
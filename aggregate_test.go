@@ -0,0 +1,127 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSalesforce_Count(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    queryResponse
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "count_function",
+			resp: queryResponse{TotalSize: 42, Done: true, Records: []map[string]any{}},
+			want: 42,
+		},
+		{
+			name: "count_field",
+			resp: queryResponse{TotalSize: 1, Done: true, Records: []map[string]any{
+				{"attributes": map[string]any{"type": "AggregateResult"}, "expr0": float64(17)},
+			}},
+			want: 17,
+		},
+		{
+			name: "multiple_aggregates",
+			resp: queryResponse{TotalSize: 1, Done: true, Records: []map[string]any{
+				{"expr0": float64(17), "expr1": float64(3)},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "grouped",
+			resp: queryResponse{TotalSize: 2, Done: true, Records: []map[string]any{
+				{"expr0": float64(17)},
+				{"expr0": float64(3)},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sfAuth := setupTestServer(tt.resp, http.StatusOK)
+			defer server.Close()
+			sf := buildSalesforceStruct(&sfAuth)
+
+			got, err := sf.Count("SELECT COUNT() FROM Account")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Count() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryAggregate(t *testing.T) {
+	resp := queryResponse{TotalSize: 2, Done: true, Records: []map[string]any{
+		{
+			"attributes":  map[string]any{"type": "AggregateResult"},
+			"Industry":    "Energy",
+			"expr0":       float64(4),
+			"total":       1250.5,
+			"LastCreated": "2024-03-01T00:00:00.000+0000",
+		},
+		{
+			"attributes":  nil,
+			"Industry":    nil,
+			"expr0":       float64(1),
+			"total":       float64(0),
+			"LastCreated": nil,
+		},
+	}}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	got, err := sf.QueryAggregate(
+		"SELECT Industry, COUNT(Id), SUM(AnnualRevenue) total, MAX(CreatedDate) LastCreated FROM Account GROUP BY Industry",
+	)
+	if err != nil {
+		t.Fatalf("QueryAggregate() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("QueryAggregate() returned %d rows, want 2", len(got))
+	}
+	if _, ok := got[0]["attributes"]; ok {
+		t.Errorf("QueryAggregate() kept attributes")
+	}
+	if industry, ok := got[0].String("industry"); !ok || industry != "Energy" {
+		t.Errorf("String() = %v, %v", industry, ok)
+	}
+	if count, ok := got[0].Int("expr0"); !ok || count != 4 {
+		t.Errorf("Int() = %v, %v", count, ok)
+	}
+	if _, ok := got[0].Int("total"); ok {
+		t.Errorf("Int() ok for fractional value")
+	}
+	if total, ok := got[0].Float("total"); !ok || total != 1250.5 {
+		t.Errorf("Float() = %v, %v", total, ok)
+	}
+	if _, ok := got[1].String("Industry"); ok {
+		t.Errorf("String() ok for null value")
+	}
+
+	type industryTotal struct {
+		Industry    string
+		Count       int     `salesforce:"expr0"`
+		Total       float64 `salesforce:"total"`
+		LastCreated string
+	}
+	rows := []industryTotal{}
+	if err := sf.Query("SELECT Industry, COUNT(Id), SUM(AnnualRevenue) total FROM Account GROUP BY Industry", &rows); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	want := []industryTotal{
+		{Industry: "Energy", Count: 4, Total: 1250.5, LastCreated: "2024-03-01T00:00:00.000+0000"},
+		{Count: 1},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Query() = %v, want %v", rows, want)
+	}
+}
@@ -0,0 +1,300 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Test levels of an asynchronous Apex test run
+const (
+	TestLevelRunSpecifiedTests = "RunSpecifiedTests"
+	TestLevelRunLocalTests     = "RunLocalTests"
+	TestLevelRunAllTestsInOrg  = "RunAllTestsInOrg"
+)
+
+// Statuses of an ApexTestQueueItem
+const (
+	ApexTestStatusQueued     = "Queued"
+	ApexTestStatusProcessing = "Processing"
+	ApexTestStatusHolding    = "Holding"
+	ApexTestStatusCompleted  = "Completed"
+	ApexTestStatusFailed     = "Failed"
+	ApexTestStatusAborted    = "Aborted"
+)
+
+// Outcomes of an ApexTestResult
+const (
+	ApexTestOutcomePass        = "Pass"
+	ApexTestOutcomeFail        = "Fail"
+	ApexTestOutcomeCompileFail = "CompileFail"
+	ApexTestOutcomeSkip        = "Skip"
+)
+
+// ApexTestRequest selects the tests of an asynchronous Apex test run
+type ApexTestRequest struct {
+	ClassNames       []string
+	SuiteNames       []string
+	TestLevel        string // defaults to RunSpecifiedTests
+	SkipCodeCoverage bool
+}
+
+type apexTestRequestBody struct {
+	ClassNames       string `json:"classNames,omitempty"`
+	SuiteNames       string `json:"suiteNames,omitempty"`
+	TestLevel        string `json:"testLevel"`
+	SkipCodeCoverage bool   `json:"skipCodeCoverage"`
+}
+
+type apexClassName struct {
+	Name string
+}
+
+// ApexTestQueueItem is the status of one test class of a test run
+type ApexTestQueueItem struct {
+	Id             string
+	ApexClassId    string
+	ApexClass      apexClassName
+	Status         string
+	ExtendedStatus string
+}
+
+// ApexTestResult is the outcome of one test method
+type ApexTestResult struct {
+	Id          string
+	ApexClassId string
+	ApexClass   apexClassName
+	MethodName  string
+	Outcome     string
+	Message     string
+	StackTrace  string
+	RunTime     int
+}
+
+// ApexCodeCoverage is the aggregate line coverage of an Apex class or trigger
+type ApexCodeCoverage struct {
+	ApexClassOrTriggerId string
+	ApexClassOrTrigger   apexClassName
+	NumLinesCovered      int
+	NumLinesUncovered    int
+}
+
+// Percent returns the covered share of lines from 0 to 100
+func (c ApexCodeCoverage) Percent() float64 {
+	total := c.NumLinesCovered + c.NumLinesUncovered
+	if total == 0 {
+		return 0
+	}
+	return float64(c.NumLinesCovered) * 100 / float64(total)
+}
+
+// ApexTestRun is the progress and results of an asynchronous Apex test run
+type ApexTestRun struct {
+	JobId      string
+	QueueItems []ApexTestQueueItem
+	Results    []ApexTestResult
+	Coverage   []ApexCodeCoverage // set by WaitForApexTests once the run is done
+}
+
+// Done reports whether every test class of the run has finished
+func (r ApexTestRun) Done() bool {
+	for _, item := range r.QueueItems {
+		switch item.Status {
+		case ApexTestStatusCompleted, ApexTestStatusFailed, ApexTestStatusAborted:
+		default:
+			return false
+		}
+	}
+	return len(r.QueueItems) > 0
+}
+
+// Passed reports whether the run is done and every test passed or was skipped
+func (r ApexTestRun) Passed() bool {
+	if !r.Done() {
+		return false
+	}
+	for _, item := range r.QueueItems {
+		if item.Status != ApexTestStatusCompleted {
+			return false
+		}
+	}
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the results of tests that failed or did not compile
+func (r ApexTestRun) Failures() []ApexTestResult {
+	var failures []ApexTestResult
+	for _, result := range r.Results {
+		if result.Outcome == ApexTestOutcomeFail || result.Outcome == ApexTestOutcomeCompileFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// RunApexTestsAsync enqueues Apex tests with the Tooling API and returns the id of the test run job
+func (sf *Salesforce) RunApexTestsAsync(request ApexTestRequest) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if request.TestLevel == "" {
+		request.TestLevel = TestLevelRunSpecifiedTests
+	}
+	if request.TestLevel == TestLevelRunSpecifiedTests &&
+		len(request.ClassNames) == 0 && len(request.SuiteNames) == 0 {
+		return "", errors.New("class names or suite names are required to run specified tests")
+	}
+
+	body, err := json.Marshal(apexTestRequestBody{
+		ClassNames:       strings.Join(request.ClassNames, ","),
+		SuiteNames:       strings.Join(request.SuiteNames, ","),
+		TestLevel:        request.TestLevel,
+		SkipCodeCoverage: request.SkipCodeCoverage,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/tooling/runTestsAsynchronous",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var jobId string
+	if err := decodeJSONResponse(resp, &jobId); err != nil {
+		return "", err
+	}
+	return jobId, nil
+}
+
+// GetApexTestRun returns the status of every test class and the results of finished test methods
+func (sf *Salesforce) GetApexTestRun(jobId string) (*ApexTestRun, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return getApexTestRun(context.Background(), sf, jobId)
+}
+
+// WaitForApexTests polls a test run every interval until it is done or ctx is done, and returns its
+// results with the code coverage of the classes and triggers the tests covered
+func (sf *Salesforce) WaitForApexTests(
+	ctx context.Context,
+	jobId string,
+	interval time.Duration,
+) (*ApexTestRun, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be greater than 0")
+	}
+
+	var run *ApexTestRun
+	err := pollUntilDone(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		run, err = getApexTestRun(ctx, sf, jobId)
+		if err != nil {
+			return true, err
+		}
+		return run.Done(), nil
+	})
+	if err != nil {
+		return run, err
+	}
+
+	run.Coverage, err = getApexTestRunCoverage(ctx, sf, run.QueueItems)
+	return run, err
+}
+
+func getApexTestRun(ctx context.Context, sf *Salesforce, jobId string) (*ApexTestRun, error) {
+	if !salesforceIdPattern.MatchString(jobId) {
+		return nil, errors.New("invalid apex test run job id: " + jobId)
+	}
+	run := &ApexTestRun{JobId: jobId}
+
+	queueItems, err := queryResourceRecords(ctx, sf, toolingQueryResource,
+		"SELECT Id, ApexClassId, ApexClass.Name, Status, ExtendedStatus FROM ApexTestQueueItem "+
+			"WHERE ParentJobId = '"+escapeSoqlString(jobId)+"'")
+	if err != nil {
+		return nil, err
+	}
+	if err := mapstructureDecode(queueItems, &run.QueueItems); err != nil {
+		return nil, err
+	}
+
+	results, err := queryResourceRecords(
+		ctx,
+		sf,
+		toolingQueryResource,
+		"SELECT Id, ApexClassId, ApexClass.Name, MethodName, Outcome, Message, StackTrace, RunTime "+
+			"FROM ApexTestResult WHERE AsyncApexJobId = '"+escapeSoqlString(
+			jobId,
+		)+"'",
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := mapstructureDecode(results, &run.Results); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func getApexTestRunCoverage(
+	ctx context.Context,
+	sf *Salesforce,
+	queueItems []ApexTestQueueItem,
+) ([]ApexCodeCoverage, error) {
+	var testClassIds []string
+	for _, item := range queueItems {
+		if item.ApexClassId != "" {
+			testClassIds = append(testClassIds, "'"+escapeSoqlString(item.ApexClassId)+"'")
+		}
+	}
+	if len(testClassIds) == 0 {
+		return nil, nil
+	}
+
+	covered, err := queryResourceRecords(ctx, sf, toolingQueryResource,
+		"SELECT ApexClassOrTriggerId FROM ApexCodeCoverage WHERE ApexTestClassId IN ("+
+			strings.Join(testClassIds, ",")+")")
+	if err != nil {
+		return nil, err
+	}
+	var coveredIds []string
+	seen := map[string]bool{}
+	for _, record := range covered {
+		id, _ := record["ApexClassOrTriggerId"].(string)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			coveredIds = append(coveredIds, "'"+escapeSoqlString(id)+"'")
+		}
+	}
+	if len(coveredIds) == 0 {
+		return nil, nil
+	}
+
+	aggregates, err := queryResourceRecords(ctx, sf, toolingQueryResource,
+		"SELECT ApexClassOrTriggerId, ApexClassOrTrigger.Name, NumLinesCovered, NumLinesUncovered "+
+			"FROM ApexCodeCoverageAggregate WHERE ApexClassOrTriggerId IN ("+
+			strings.Join(coveredIds, ",")+")")
+	if err != nil {
+		return nil, err
+	}
+	var coverage []ApexCodeCoverage
+	if err := mapstructureDecode(aggregates, &coverage); err != nil {
+		return nil, err
+	}
+	return coverage, nil
+}
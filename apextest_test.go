@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupApexTestServer(t *testing.T, queueStatus string) (*httptest.Server, authentication) {
+	t.Helper()
+	responses := map[string]any{
+		"ApexTestQueueItem": queryResponse{Done: true, Records: []map[string]any{{
+			"Id":          "709000000000001AAA",
+			"ApexClassId": "01p000000000001AAA",
+			"ApexClass":   map[string]any{"Name": "AccountServiceTest"},
+			"Status":      queueStatus,
+		}}},
+		"FROM ApexTestResult": queryResponse{Done: true, Records: []map[string]any{
+			{
+				"MethodName": "testInsert",
+				"Outcome":    ApexTestOutcomePass,
+				"RunTime":    12,
+				"ApexClass":  map[string]any{"Name": "AccountServiceTest"},
+			},
+			{
+				"MethodName": "testUpdate",
+				"Outcome":    ApexTestOutcomeFail,
+				"Message":    "System.AssertException: Assertion Failed",
+				"ApexClass":  map[string]any{"Name": "AccountServiceTest"},
+			},
+		}},
+		"FROM ApexCodeCoverage WHERE": queryResponse{Done: true, Records: []map[string]any{
+			{"ApexClassOrTriggerId": "01p000000000002AAA"},
+			{"ApexClassOrTriggerId": "01p000000000002AAA"},
+		}},
+		"ApexCodeCoverageAggregate": queryResponse{Done: true, Records: []map[string]any{{
+			"ApexClassOrTriggerId": "01p000000000002AAA",
+			"ApexClassOrTrigger":   map[string]any{"Name": "AccountService"},
+			"NumLinesCovered":      3,
+			"NumLinesUncovered":    1,
+		}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any = "707000000000001AAA"
+		if strings.HasSuffix(r.URL.Path, "/tooling/query/") {
+			query := r.URL.Query().Get("q")
+			body = nil
+			for key, resp := range responses {
+				if strings.Contains(query, key) {
+					body = resp
+				}
+			}
+			if body == nil {
+				t.Errorf("unexpected query: %s", query)
+			}
+		}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Error(err)
+		}
+	}))
+	return server, authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+}
+
+func TestSalesforce_RunApexTestsAsync(t *testing.T) {
+	server, sfAuth := setupApexTestServer(t, ApexTestStatusQueued)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	tests := []struct {
+		name    string
+		request ApexTestRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "specified_tests",
+			request: ApexTestRequest{ClassNames: []string{"AccountServiceTest"}},
+			want:    "707000000000001AAA",
+			wantErr: false,
+		},
+		{
+			name:    "local_tests",
+			request: ApexTestRequest{TestLevel: TestLevelRunLocalTests},
+			want:    "707000000000001AAA",
+			wantErr: false,
+		},
+		{
+			name:    "no_tests_specified",
+			request: ApexTestRequest{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sf.RunApexTestsAsync(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunApexTestsAsync() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RunApexTestsAsync() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetApexTestRun(t *testing.T) {
+	server, sfAuth := setupApexTestServer(t, ApexTestStatusProcessing)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	run, err := sf.GetApexTestRun("707000000000001AAA")
+	if err != nil {
+		t.Fatalf("GetApexTestRun() error = %v", err)
+	}
+	if run.Done() || run.Passed() {
+		t.Errorf("GetApexTestRun() done = %v, passed = %v, want running", run.Done(), run.Passed())
+	}
+	if len(run.QueueItems) != 1 || run.QueueItems[0].ApexClass.Name != "AccountServiceTest" {
+		t.Errorf("GetApexTestRun() queue items = %v", run.QueueItems)
+	}
+	if len(run.Results) != 2 || run.Results[0].RunTime != 12 {
+		t.Errorf("GetApexTestRun() results = %v", run.Results)
+	}
+
+	if _, err := sf.GetApexTestRun("bad' OR Id != '"); err == nil {
+		t.Errorf("GetApexTestRun() expected error for invalid job id")
+	}
+}
+
+func TestSalesforce_WaitForApexTests(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		server, sfAuth := setupApexTestServer(t, ApexTestStatusCompleted)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		run, err := sf.WaitForApexTests(
+			context.Background(),
+			"707000000000001AAA",
+			time.Millisecond,
+		)
+		if err != nil {
+			t.Fatalf("WaitForApexTests() error = %v", err)
+		}
+		if !run.Done() || run.Passed() {
+			t.Errorf("WaitForApexTests() done = %v, passed = %v", run.Done(), run.Passed())
+		}
+		failures := run.Failures()
+		if len(failures) != 1 || failures[0].MethodName != "testUpdate" {
+			t.Errorf("Failures() = %v", failures)
+		}
+		if len(run.Coverage) != 1 || run.Coverage[0].Percent() != 75 {
+			t.Errorf("WaitForApexTests() coverage = %v", run.Coverage)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		server, sfAuth := setupApexTestServer(t, ApexTestStatusQueued)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := sf.WaitForApexTests(ctx, "707000000000001AAA", time.Millisecond); err == nil {
+			t.Errorf("WaitForApexTests() expected error when the context is done")
+		}
+	})
+
+	t.Run("invalid_interval", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "token"})
+		if _, err := sf.WaitForApexTests(context.Background(), "707000000000001AAA", 0); err == nil {
+			t.Errorf("WaitForApexTests() expected error for invalid interval")
+		}
+	})
+}
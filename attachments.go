@@ -0,0 +1,383 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+const (
+	attachmentMigrationBatchSize = 50
+	binaryType                   = "application/octet-stream"
+)
+
+// AttachmentMigration selects the legacy attachments to migrate to files with MigrateAttachments
+type AttachmentMigration struct {
+	Where             string // SOQL condition on Attachment, such as ParentId IN (...); all if empty
+	AfterId           string // resume after this attachment id, as reported by Progress
+	BatchSize         int    // attachments per batch, 50 if 0
+	ShareType         string // ShareType of the ContentDocumentLinks, V (viewer) if empty
+	Visibility        string // Visibility of the ContentDocumentLinks, AllUsers if empty
+	KeepOwner         bool   // make the owner of the attachment the owner of the file
+	DeleteAttachments bool   // delete attachments once they are migrated and linked to their parent
+	// Progress is called after every batch, so the last attachment id can be stored to resume
+	// the migration later. Returning an error stops the migration.
+	Progress func(progress AttachmentMigrationProgress) error
+}
+
+// AttachmentMigrationProgress reports the progress of MigrateAttachments
+type AttachmentMigrationProgress struct {
+	LastAttachmentId string                      // pass as AfterId to resume the migration
+	Migrated         int                         // attachments migrated so far
+	Failed           int                         // attachments that failed so far
+	Results          []AttachmentMigrationResult // results of the batch, nil once the migration ends
+}
+
+// AttachmentMigrationResult is the outcome of migrating one attachment. Err is set if any step failed,
+// in which case the ids of the steps that succeeded are still set.
+type AttachmentMigrationResult struct {
+	AttachmentId          string
+	ParentId              string
+	ContentVersionId      string
+	ContentDocumentId     string
+	ContentDocumentLinkId string
+	Err                   error
+}
+
+type attachmentRecord struct {
+	Id          string
+	ParentId    string
+	Name        string
+	Description string
+	ContentType string
+	OwnerId     string
+}
+
+// MigrateAttachments migrates legacy attachments to files, in batches of attachments ordered by id.
+// The body of each attachment is downloaded and uploaded as a ContentVersion one at a time, then a
+// ContentDocumentLink is created for every new file of the batch to share it with the parent record
+// of its attachment. Attachments that fail are reported in the results of their batch and skipped.
+// It returns the progress of the whole migration, which can be resumed with its LastAttachmentId.
+func (sf *Salesforce) MigrateAttachments(
+	ctx context.Context,
+	migration AttachmentMigration,
+) (AttachmentMigrationProgress, error) {
+	progress := AttachmentMigrationProgress{LastAttachmentId: migration.AfterId}
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return progress, authErr
+	}
+	if migration.BatchSize < 0 {
+		return progress, errors.New("batch size cannot be negative")
+	}
+	if migration.BatchSize == 0 {
+		migration.BatchSize = attachmentMigrationBatchSize
+	}
+	if migration.BatchSize > sf.config.batchSizeMax {
+		return progress, fmt.Errorf("batch size cannot be greater than %d", sf.config.batchSizeMax)
+	}
+	if migration.ShareType == "" {
+		migration.ShareType = "V"
+	}
+	if migration.Visibility == "" {
+		migration.Visibility = "AllUsers"
+	}
+
+	for {
+		attachments, err := nextAttachmentBatch(ctx, sf, migration, progress.LastAttachmentId)
+		if err != nil {
+			return progress, err
+		}
+		if len(attachments) == 0 {
+			return progress, nil
+		}
+		results, err := migrateAttachmentBatch(ctx, sf, migration, attachments)
+		if err != nil {
+			return progress, err
+		}
+
+		progress.LastAttachmentId = attachments[len(attachments)-1].Id
+		for _, result := range results {
+			if result.Err != nil {
+				progress.Failed++
+			} else {
+				progress.Migrated++
+			}
+		}
+		if migration.Progress != nil {
+			progress.Results = results
+			err = migration.Progress(progress)
+			progress.Results = nil
+			if err != nil {
+				return progress, err
+			}
+		}
+		if len(attachments) < migration.BatchSize {
+			return progress, nil
+		}
+	}
+}
+
+func nextAttachmentBatch(
+	ctx context.Context,
+	sf *Salesforce,
+	migration AttachmentMigration,
+	afterId string,
+) ([]attachmentRecord, error) {
+	var conditions []string
+	if migration.Where != "" {
+		conditions = append(conditions, "("+migration.Where+")")
+	}
+	if afterId != "" {
+		conditions = append(conditions, "Id > '"+escapeSoqlString(afterId)+"'")
+	}
+	query := "SELECT Id, ParentId, Name, Description, ContentType, OwnerId FROM Attachment"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY Id LIMIT %d", migration.BatchSize)
+
+	records, err := queryAllRecords(ctx, sf, query)
+	if err != nil {
+		return nil, err
+	}
+	attachments := []attachmentRecord{}
+	if err := mapstructureDecode(records, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func migrateAttachmentBatch(
+	ctx context.Context,
+	sf *Salesforce,
+	migration AttachmentMigration,
+	attachments []attachmentRecord,
+) ([]AttachmentMigrationResult, error) {
+	results := make([]AttachmentMigrationResult, len(attachments))
+	var versionIds []string
+	for i, attachment := range attachments {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results[i] = AttachmentMigrationResult{
+			AttachmentId: attachment.Id,
+			ParentId:     attachment.ParentId,
+		}
+		versionId, err := copyAttachmentToContentVersion(ctx, sf, attachment, migration.KeepOwner)
+		if err != nil {
+			results[i].Err = fmt.Errorf("migrating attachment %s: %w", attachment.Id, err)
+			continue
+		}
+		results[i].ContentVersionId = versionId
+		versionIds = append(versionIds, versionId)
+	}
+	if len(versionIds) == 0 {
+		return results, nil
+	}
+
+	documentIds, err := contentDocumentIds(ctx, sf, versionIds)
+	if err != nil {
+		return nil, err
+	}
+	var links []map[string]any
+	var linked []int // index in results of each link
+	for i := range results {
+		if results[i].ContentVersionId == "" {
+			continue
+		}
+		results[i].ContentDocumentId = documentIds[results[i].ContentVersionId]
+		links = append(links, map[string]any{
+			"ContentDocumentId": results[i].ContentDocumentId,
+			"LinkedEntityId":    results[i].ParentId,
+			"ShareType":         migration.ShareType,
+			"Visibility":        migration.Visibility,
+		})
+		linked = append(linked, i)
+	}
+	linkResults, err := sf.InsertCollection("ContentDocumentLink", links, len(links))
+	if err != nil {
+		return nil, err
+	}
+	var migrated []map[string]any
+	for j, linkResult := range linkResults.Results {
+		i := linked[j]
+		if !linkResult.Success {
+			results[i].Err = fmt.Errorf(
+				"linking attachment %s: %w",
+				results[i].AttachmentId,
+				RecordError{Index: j, Errors: linkResult.Errors},
+			)
+			continue
+		}
+		results[i].ContentDocumentLinkId = linkResult.Id
+		migrated = append(migrated, map[string]any{"Id": results[i].AttachmentId})
+	}
+
+	if !migration.DeleteAttachments || len(migrated) == 0 {
+		return results, nil
+	}
+	deleteResults, err := sf.DeleteCollection("Attachment", migrated, len(migrated))
+	if err != nil {
+		return nil, err
+	}
+	for _, deleteResult := range deleteResults.Results {
+		if deleteResult.Success {
+			continue
+		}
+		for i := range results {
+			if results[i].AttachmentId == deleteResult.Id {
+				results[i].Err = fmt.Errorf(
+					"deleting attachment %s: %w",
+					deleteResult.Id,
+					RecordError{
+						Index:  deleteResult.Index,
+						Id:     deleteResult.Id,
+						Errors: deleteResult.Errors,
+					},
+				)
+			}
+		}
+	}
+	return results, nil
+}
+
+// copyAttachmentToContentVersion downloads the body of an attachment and uploads it as a new
+// ContentVersion with a multipart request, which allows larger files than base64 encoded json
+func copyAttachmentToContentVersion(
+	ctx context.Context,
+	sf *Salesforce,
+	attachment attachmentRecord,
+	keepOwner bool,
+) (string, error) {
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      "/sobjects/Attachment/" + url.PathEscape(attachment.Id) + "/Body",
+		content:  binaryType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	version := map[string]any{
+		"Title":        attachment.Name,
+		"PathOnClient": attachment.Name,
+	}
+	setNonEmptyField(version, "Description", attachment.Description)
+	if keepOwner {
+		setNonEmptyField(version, "OwnerId", attachment.OwnerId)
+	}
+	body, contentType, err := contentVersionMultipart(version, attachment, data)
+	if err != nil {
+		return "", err
+	}
+	resp, err = doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodPost,
+		uri:      "/sobjects/ContentVersion",
+		content:  contentType,
+		body:     bytes.NewReader(body),
+		compress: sf.config.compressionHeaders,
+		options:  []RequestOption{WithHeader("Accept", jsonType)},
+	})
+	if err != nil {
+		return "", err
+	}
+	result := SalesforceResult{}
+	if err := decodeJSONResponse(resp, &result); err != nil {
+		return "", err
+	}
+	return result.Id, nil
+}
+
+func contentVersionMultipart(
+	version map[string]any,
+	attachment attachmentRecord,
+	data []byte,
+) ([]byte, string, error) {
+	entity, err := json.Marshal(version)
+	if err != nil {
+		return nil, "", err
+	}
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="entity_content"`)
+	header.Set("Content-Type", jsonType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(entity); err != nil {
+		return nil, "", err
+	}
+
+	header = textproto.MIMEHeader{}
+	header.Set(
+		"Content-Disposition",
+		fmt.Sprintf(`form-data; name="VersionData"; filename=%q`, attachment.Name),
+	)
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = binaryType
+	}
+	header.Set("Content-Type", contentType)
+	part, err = writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// contentDocumentIds returns the ContentDocumentId of each ContentVersion id
+func contentDocumentIds(
+	ctx context.Context,
+	sf *Salesforce,
+	versionIds []string,
+) (map[string]string, error) {
+	quoted := make([]string, len(versionIds))
+	for i, id := range versionIds {
+		quoted[i] = "'" + escapeSoqlString(id) + "'"
+	}
+	records, err := queryAllRecords(
+		ctx,
+		sf,
+		"SELECT Id, ContentDocumentId FROM ContentVersion WHERE Id IN ("+strings.Join(
+			quoted,
+			", ",
+		)+")",
+	)
+	if err != nil {
+		return nil, err
+	}
+	documentIds := map[string]string{}
+	for _, record := range records {
+		id, _ := record["Id"].(string)
+		documentId, _ := record["ContentDocumentId"].(string)
+		documentIds[id] = documentId
+	}
+	return documentIds, nil
+}
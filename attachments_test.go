@@ -0,0 +1,219 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_MigrateAttachments(t *testing.T) {
+	attachments := []map[string]any{
+		{
+			"Id":          "00P000000000001AAA",
+			"ParentId":    "001000000000001AAA",
+			"Name":        "a.txt",
+			"ContentType": "text/plain",
+			"OwnerId":     "005000000000001AAA",
+		},
+		{"Id": "00P000000000002AAA", "ParentId": "001000000000002AAA", "Name": "b.txt"},
+		{
+			"Id":          "00P000000000003AAA",
+			"ParentId":    "001000000000003AAA",
+			"Name":        "c.pdf",
+			"Description": "contract",
+		},
+	}
+	var queries []string
+	var versions []map[string]any
+	var files []string
+	var links []map[string]any
+	var deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion)
+		switch {
+		case path == "/query/":
+			query := r.URL.Query().Get("q")
+			queries = append(queries, query)
+			records := []map[string]any{}
+			if strings.Contains(query, "FROM ContentVersion") {
+				for i := range versions {
+					records = append(records, map[string]any{
+						"Id":                fmt.Sprintf("068000000000%03dAAA", i+1),
+						"ContentDocumentId": fmt.Sprintf("069000000000%03dAAA", i+1),
+					})
+				}
+			} else if strings.Contains(query, "Id > '00P000000000002AAA'") {
+				records = attachments[2:]
+			} else {
+				records = attachments[:2]
+			}
+			body, _ := json.Marshal(
+				queryResponse{TotalSize: len(records), Done: true, Records: records},
+			)
+			_, _ = w.Write(body)
+		case strings.HasSuffix(path, "/Body"):
+			if strings.Contains(path, "00P000000000002AAA") {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`[{"message":"not found","errorCode":"NOT_FOUND"}]`))
+				return
+			}
+			_, _ = w.Write([]byte("body of " + strings.Split(path, "/")[3]))
+		case path == "/sobjects/ContentVersion":
+			_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			reader := multipart.NewReader(r.Body, params["boundary"])
+			entity, _ := reader.NextPart()
+			version := map[string]any{}
+			_ = json.NewDecoder(entity).Decode(&version)
+			versions = append(versions, version)
+			file, _ := reader.NextPart()
+			data, _ := io.ReadAll(file)
+			files = append(files, file.FileName()+": "+string(data))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(
+				w,
+				`{"id":"068000000000%03dAAA","success":true,"errors":[]}`,
+				len(versions),
+			)
+		case path == "/composite/sobjects/" && r.Method == http.MethodPost:
+			payload := sObjectCollection{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			results := []SalesforceResult{}
+			for _, link := range payload.Records {
+				delete(link, "attributes")
+				links = append(links, link)
+				results = append(results, SalesforceResult{
+					Id:      fmt.Sprintf("06A000000000%03dAAA", len(links)),
+					Success: true,
+				})
+			}
+			body, _ := json.Marshal(results)
+			_, _ = w.Write(body)
+		case path == "/composite/sobjects/" && r.Method == http.MethodDelete:
+			deleted = r.URL.Query().Get("ids")
+			results := []SalesforceResult{}
+			for _, id := range strings.Split(deleted, ",") {
+				results = append(results, SalesforceResult{Id: id, Success: true})
+			}
+			body, _ := json.Marshal(results)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	var reported []AttachmentMigrationProgress
+	progress, err := sf.MigrateAttachments(context.Background(), AttachmentMigration{
+		Where:             "Parent.Type = 'Account'",
+		BatchSize:         2,
+		KeepOwner:         true,
+		DeleteAttachments: true,
+		Progress: func(progress AttachmentMigrationProgress) error {
+			reported = append(reported, progress)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("MigrateAttachments() error = %v", err)
+	}
+	want := AttachmentMigrationProgress{
+		LastAttachmentId: "00P000000000003AAA",
+		Migrated:         2,
+		Failed:           1,
+	}
+	if !reflect.DeepEqual(progress, want) {
+		t.Errorf("MigrateAttachments() = %v, want %v", progress, want)
+	}
+
+	if len(reported) != 2 || reported[0].LastAttachmentId != "00P000000000002AAA" ||
+		reported[0].Migrated != 1 || reported[0].Failed != 1 || len(reported[1].Results) != 1 {
+		t.Fatalf("progress = %v", reported)
+	}
+	failed := reported[0].Results[1]
+	if failed.AttachmentId != "00P000000000002AAA" || failed.Err == nil ||
+		failed.ContentVersionId != "" {
+		t.Errorf("failed result = %v", failed)
+	}
+	migrated := reported[1].Results[0]
+	wantResult := AttachmentMigrationResult{
+		AttachmentId:          "00P000000000003AAA",
+		ParentId:              "001000000000003AAA",
+		ContentVersionId:      "068000000000002AAA",
+		ContentDocumentId:     "069000000000002AAA",
+		ContentDocumentLinkId: "06A000000000002AAA",
+	}
+	if !reflect.DeepEqual(migrated, wantResult) {
+		t.Errorf("result = %v, want %v", migrated, wantResult)
+	}
+
+	if !strings.Contains(queries[0], "WHERE (Parent.Type = 'Account') ORDER BY Id LIMIT 2") ||
+		!strings.Contains(queries[2], "AND Id > '00P000000000002AAA'") {
+		t.Errorf("queries = %v", queries)
+	}
+	wantVersions := []map[string]any{
+		{"Title": "a.txt", "PathOnClient": "a.txt", "OwnerId": "005000000000001AAA"},
+		{"Title": "c.pdf", "PathOnClient": "c.pdf", "Description": "contract"},
+	}
+	if !reflect.DeepEqual(versions, wantVersions) {
+		t.Errorf("versions = %v, want %v", versions, wantVersions)
+	}
+	wantFiles := []string{"a.txt: body of 00P000000000001AAA", "c.pdf: body of 00P000000000003AAA"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files = %v, want %v", files, wantFiles)
+	}
+	if len(links) != 2 || links[0]["LinkedEntityId"] != "001000000000001AAA" ||
+		links[0]["ContentDocumentId"] != "069000000000001AAA" || links[0]["ShareType"] != "V" ||
+		links[0]["Visibility"] != "AllUsers" {
+		t.Errorf("links = %v", links)
+	}
+	if deleted != "00P000000000003AAA" {
+		t.Errorf("deleted = %v", deleted)
+	}
+
+	stop := errors.New("stop")
+	queries = nil
+	progress, err = sf.MigrateAttachments(context.Background(), AttachmentMigration{
+		AfterId:   "00P000000000002AAA",
+		BatchSize: 1,
+		Progress: func(progress AttachmentMigrationProgress) error {
+			return stop
+		},
+	})
+	if !errors.Is(err, stop) || progress.LastAttachmentId != "00P000000000003AAA" {
+		t.Errorf("MigrateAttachments() = %v, %v", progress, err)
+	}
+	if !strings.Contains(queries[0], "WHERE Id > '00P000000000002AAA' ORDER BY Id LIMIT 1") {
+		t.Errorf("queries = %v", queries)
+	}
+
+	tests := []struct {
+		name      string
+		migration AttachmentMigration
+	}{
+		{
+			name:      "negative_batch_size",
+			migration: AttachmentMigration{BatchSize: -1},
+		},
+		{
+			name:      "batch_size_too_large",
+			migration: AttachmentMigration{BatchSize: 201},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sf.MigrateAttachments(context.Background(), tt.migration); err == nil {
+				t.Error("MigrateAttachments() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one request sent to Salesforce
+type AuditEntry struct {
+	Time       time.Time     `json:"time"`
+	User       string        `json:"user,omitempty"` // Id of the user the request was sent as, if known
+	Method     string        `json:"method"`
+	Endpoint   string        `json:"endpoint"`            // path and query of the request
+	RecordIds  []string      `json:"recordIds,omitempty"` // ids of records written or deleted
+	StatusCode int           `json:"statusCode,omitempty"`
+	Error      string        `json:"error,omitempty"` // request error or Salesforce error response
+	Duration   time.Duration `json:"duration"`
+}
+
+// AuditLog receives an entry for every request sent to Salesforce, set with WithAuditLog. Log is called
+// by concurrent requests, so implementations must be safe for concurrent use.
+type AuditLog interface {
+	Log(entry AuditEntry)
+}
+
+// MemoryAuditLog keeps audit entries in memory
+type MemoryAuditLog struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+}
+
+func NewMemoryAuditLog() *MemoryAuditLog {
+	return &MemoryAuditLog{}
+}
+
+func (l *MemoryAuditLog) Log(entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+// Entries returns a copy of the logged entries in the order the requests completed
+func (l *MemoryAuditLog) Entries() []AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	entries := make([]AuditEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// WriterAuditLog writes audit entries to a writer as JSON lines, such as to a file or a log shipper
+type WriterAuditLog struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+func NewWriterAuditLog(w io.Writer) *WriterAuditLog {
+	return &WriterAuditLog{encoder: json.NewEncoder(w)}
+}
+
+func (l *WriterAuditLog) Log(entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.encoder.Encode(entry); err != nil && l.err == nil {
+		l.err = err
+	}
+}
+
+// Err returns the first error writing an entry, since a failed write does not fail the request
+func (l *WriterAuditLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// auditRequest logs a request to the audit log, if one is set. The body of a response is read to find
+// the ids of records written and the errors of failed requests, and replaced so it can still be read.
+func auditRequest(
+	auth *authentication,
+	config *configuration,
+	req *http.Request,
+	body []byte,
+	start time.Time,
+	resp *http.Response,
+	err error,
+) {
+	if config.auditLog == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:      start,
+		User:      auditUser(auth),
+		Method:    req.Method,
+		Endpoint:  req.URL.RequestURI(),
+		RecordIds: urlRecordIds(req),
+		Duration:  time.Since(start),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		failed := resp.StatusCode >= http.StatusBadRequest
+		if req.Method != http.MethodGet || failed {
+			respBody, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			if readErr == nil && failed {
+				entry.Error = string(respBody)
+			}
+			if readErr == nil && !failed && req.Method != http.MethodGet {
+				entry.RecordIds = appendJSONRecordIds(entry.RecordIds, body)
+				entry.RecordIds = appendJSONRecordIds(entry.RecordIds, respBody)
+			}
+		}
+	}
+	config.auditLog.Log(entry)
+}
+
+// auditUser returns the user Id from the identity url of the session, or the username it logged in with
+func auditUser(auth *authentication) string {
+	if auth.Id != "" {
+		return auth.Id[strings.LastIndex(auth.Id, "/")+1:]
+	}
+	return auth.creds.Username
+}
+
+// urlRecordIds returns the record ids in the path of a request and in its ids query parameter
+func urlRecordIds(req *http.Request) []string {
+	var ids []string
+	for _, segment := range strings.Split(req.URL.Path, "/") {
+		if IsValidId(segment) {
+			ids = append(ids, segment)
+		}
+	}
+	for _, param := range req.URL.Query()["ids"] {
+		for _, id := range strings.Split(param, ",") {
+			if IsValidId(id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// appendJSONRecordIds appends the record ids found in the id fields of a JSON body, once each
+func appendJSONRecordIds(ids []string, body []byte) []string {
+	var value any
+	if len(body) == 0 || json.Unmarshal(body, &value) != nil {
+		return ids
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	var walk func(value any)
+	walk = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, field := range v {
+				id, ok := field.(string)
+				if ok && strings.EqualFold(key, "id") && IsValidId(id) && !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+				walk(field)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return ids
+}
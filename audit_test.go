@@ -0,0 +1,119 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithAuditLog_Requests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/sobjects/Account/001000000000002AAA"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`[{"errorCode":"NOT_FOUND","message":"not found"}]`))
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`[{"id":"001000000000001AAA","success":true,"errors":[]}]`))
+		case r.Method == http.MethodDelete:
+			_, _ = w.Write([]byte(`[{"id":"003000000000001AAA","success":true,"errors":[]}]`))
+		default:
+			_, _ = w.Write(
+				[]byte(`{"totalSize":1,"done":true,"records":[{"Id":"001000000000001AAA"}]}`),
+			)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "1234",
+		Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA",
+	})
+	log := NewMemoryAuditLog()
+	sf.config.auditLog = log
+
+	if _, err := sf.InsertCollection("Account", []map[string]any{{"Name": "Acme"}}, 200); err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if _, err := sf.DeleteCollection(
+		"Contact",
+		[]map[string]any{{"Id": "003000000000001AAA"}},
+		200,
+	); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	_ = sf.DeleteOne("Account", map[string]any{"Id": "001000000000002AAA"})
+
+	entries := log.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("Entries() = %v, want 4 entries", entries)
+	}
+	tests := []struct {
+		method        string
+		wantRecordIds []string
+		wantStatus    int
+		wantError     bool
+	}{
+		{http.MethodPost, []string{"001000000000001AAA"}, http.StatusOK, false},
+		{http.MethodDelete, []string{"003000000000001AAA"}, http.StatusOK, false},
+		{http.MethodGet, nil, http.StatusOK, false},
+		{http.MethodDelete, []string{"001000000000002AAA"}, http.StatusNotFound, true},
+	}
+	for i, tt := range tests {
+		entry := entries[i]
+		if entry.Method != tt.method || entry.User != "005000000000001AAA" ||
+			!strings.HasPrefix(entry.Endpoint, "/services/data/") || entry.Time.IsZero() {
+			t.Errorf("entry %d = %+v", i, entry)
+		}
+		if !reflect.DeepEqual(entry.RecordIds, tt.wantRecordIds) {
+			t.Errorf("entry %d RecordIds = %v, want %v", i, entry.RecordIds, tt.wantRecordIds)
+		}
+		if entry.StatusCode != tt.wantStatus || (entry.Error != "") != tt.wantError {
+			t.Errorf("entry %d outcome = %v, %v", i, entry.StatusCode, entry.Error)
+		}
+	}
+	if !strings.Contains(entries[3].Error, "NOT_FOUND") {
+		t.Errorf("Error = %v, want the Salesforce error", entries[3].Error)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriterAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWriterAuditLog(&buf)
+	log.Log(AuditEntry{Method: http.MethodPost, Endpoint: "/services/data/v63.0/sobjects/Account"})
+	log.Log(AuditEntry{Method: http.MethodDelete, RecordIds: []string{"001000000000001AAA"}})
+	if log.Err() != nil {
+		t.Fatalf("Err() = %v", log.Err())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %v, want 2", lines)
+	}
+	entry := AuditEntry{}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Method != http.MethodDelete || entry.RecordIds[0] != "001000000000001AAA" {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	failing := NewWriterAuditLog(failingWriter{})
+	failing.Log(AuditEntry{})
+	if failing.Err() == nil {
+		t.Error("Err() expected the write error")
+	}
+}
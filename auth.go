@@ -23,6 +23,7 @@ const (
 	AuthFlowClientCredentials
 	AuthFlowAccessToken
 	AuthFlowJWT
+	AuthFlowSoapLogin
 )
 
 func (a AuthFlowType) String() string {
@@ -35,6 +36,8 @@ func (a AuthFlowType) String() string {
 		return "Access Token"
 	case AuthFlowJWT:
 		return "JWT"
+	case AuthFlowSoapLogin:
+		return "SOAP Login"
 	default:
 		return "Unknown"
 	}
@@ -48,8 +51,20 @@ type authentication struct {
 	Scope       string `json:"scope"`
 	IssuedAt    string `json:"issued_at"`
 	Signature   string `json:"signature"`
-	grantType   string
-	creds       Creds
+	// returned for community users who authenticate through an Experience Cloud site
+	CommunityUrl string `json:"sfdc_community_url"`
+	CommunityId  string `json:"sfdc_community_id"`
+	grantType    string
+	creds        Creds
+	// consulted for current credentials on each refresh, nil if the credentials never change
+	credentialProvider CredentialProvider
+	// shares the session with other processes, nil if it is not shared
+	sessionStore SessionStore
+	sessionKey   string
+	// the instance url was set by the client rather than by the token response, and is kept on refreshes
+	pinnedInstanceUrl bool
+	// instance url of the latest token response, which is shared even when the instance url is pinned
+	tokenInstanceUrl string
 }
 
 type Creds struct {
@@ -65,11 +80,20 @@ type Creds struct {
 
 const JwtExpirationTime = 5 * time.Minute
 
+// Login urls of Salesforce, for Creds.Domain or WithLoginUrl. Orgs with My Domain can also log in
+// with their My Domain url.
+const (
+	LoginUrlProduction = "https://login.salesforce.com"
+	LoginUrlSandbox    = "https://test.salesforce.com"
+	LoginUrlMilitary   = "https://login.salesforce.mil" // Government Cloud Plus - Defense
+)
+
 const (
 	grantTypeUsernamePassword  = "password"
 	grantTypeClientCredentials = "client_credentials"
 	grantTypeAccessToken       = "access_token"
 	grantTypeJWT               = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	grantTypeSoapLogin         = "soap_login"
 )
 
 func validateAuth(sf Salesforce) error {
@@ -95,19 +119,38 @@ func (conf *configuration) validateAuthentication(auth authentication) error {
 	return nil
 }
 
-func refreshSession(auth *authentication) error {
+func refreshSession(auth *authentication, config *configuration) error {
+	if auth.sessionStore != nil {
+		return refreshSharedSession(auth, config)
+	}
+	return refreshOwnSession(auth, config)
+}
+
+// refreshOwnSession refreshes the session by authenticating again, without consulting a SessionStore
+func refreshOwnSession(auth *authentication, config *configuration) error {
+	if auth.credentialProvider != nil {
+		return refreshSessionWithProvider(auth, config)
+	}
+	return refreshSessionWithCreds(auth, config)
+}
+
+// refreshSessionWithCreds refreshes the session by authenticating again with the credentials it was
+// created with
+func refreshSessionWithCreds(auth *authentication, config *configuration) error {
 	var refreshedAuth *authentication
 	var err error
 
 	switch grantType := auth.grantType; grantType {
 	case grantTypeClientCredentials:
 		refreshedAuth, err = clientCredentialsFlow(
+			config,
 			auth.InstanceUrl,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerSecret,
 		)
 	case grantTypeUsernamePassword:
 		refreshedAuth, err = usernamePasswordFlow(
+			config,
 			auth.InstanceUrl,
 			auth.creds.Username,
 			auth.creds.Password,
@@ -117,12 +160,21 @@ func refreshSession(auth *authentication) error {
 		)
 	case grantTypeJWT:
 		refreshedAuth, err = jwtFlow(
+			config,
 			auth.InstanceUrl,
 			auth.creds.Username,
 			auth.creds.ConsumerKey,
 			auth.creds.ConsumerRSAPem,
 			JwtExpirationTime,
 		)
+	case grantTypeSoapLogin:
+		refreshedAuth, err = soapLoginFlow(
+			config,
+			auth.InstanceUrl,
+			auth.creds.Username,
+			auth.creds.Password,
+			auth.creds.SecurityToken,
+		)
 	default:
 		return errors.New("invalid session, unable to refresh session")
 	}
@@ -136,6 +188,7 @@ func refreshSession(auth *authentication) error {
 	}
 
 	auth.AccessToken = refreshedAuth.AccessToken
+	auth.tokenInstanceUrl = valueOrDefault(refreshedAuth.InstanceUrl, auth.tokenInstanceUrl)
 	auth.IssuedAt = refreshedAuth.IssuedAt
 	auth.Signature = refreshedAuth.Signature
 	auth.Id = refreshedAuth.Id
@@ -143,13 +196,15 @@ func refreshSession(auth *authentication) error {
 	return nil
 }
 
-func doAuth(url string, body *strings.Reader) (*authentication, error) {
-	resp, err := http.Post(url, "application/x-www-form-urlencoded", body)
+// doAuth sends a token request with the client of the configuration, so that the transport and the
+// redirect policy of WithAllowedDomains apply to authentication as well
+func doAuth(config *configuration, url string, body *strings.Reader) (*authentication, error) {
+	resp, err := config.httpClient.Post(url, "application/x-www-form-urlencoded", body)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(resp.Status) + ":" + " failed authentication")
+		return nil, &authenticationError{status: resp.Status, statusCode: resp.StatusCode}
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -170,6 +225,7 @@ func doAuth(url string, body *strings.Reader) (*authentication, error) {
 }
 
 func usernamePasswordFlow(
+	config *configuration,
 	domain string,
 	username string,
 	password string,
@@ -186,7 +242,7 @@ func usernamePasswordFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(config, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +251,7 @@ func usernamePasswordFlow(
 }
 
 func clientCredentialsFlow(
+	config *configuration,
 	domain string,
 	consumerKey string,
 	consumerSecret string,
@@ -206,7 +263,7 @@ func clientCredentialsFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(config, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
@@ -218,6 +275,9 @@ func (conf *configuration) getAccessTokenAuthentication(
 	domain string,
 	accessToken string,
 ) (*authentication, error) {
+	if err := conf.checkInstanceUrl(domain); err != nil {
+		return nil, err
+	}
 	auth := &authentication{InstanceUrl: domain, AccessToken: accessToken}
 	if conf.shouldValidateAuthentication {
 		if err := conf.validateAuthentication(*auth); err != nil {
@@ -228,22 +288,33 @@ func (conf *configuration) getAccessTokenAuthentication(
 	return auth, nil
 }
 
+// jwtAudience returns the login url that a JWT bearer token for a domain is issued to, which is the
+// login url of the Salesforce cloud of the domain rather than the domain itself
+func jwtAudience(domain string) string {
+	host := strings.ToLower(domain)
+	if parsed, err := url.Parse(domain); err == nil && parsed.Host != "" {
+		host = strings.ToLower(parsed.Hostname())
+	}
+	switch {
+	case host == "salesforce.mil" || strings.HasSuffix(host, ".salesforce.mil"):
+		return LoginUrlMilitary
+	case strings.Contains(host, "test.salesforce") || strings.Contains(host, "sandbox"):
+		return LoginUrlSandbox
+	}
+	return LoginUrlProduction
+}
+
 func jwtFlow(
+	config *configuration,
 	domain string,
 	username string,
 	consumerKey string,
 	consumerRSAPem string,
 	expirationTime time.Duration,
 ) (*authentication, error) {
-	audience := domain
-	if strings.Contains(audience, "test.salesforce") || strings.Contains(audience, "sandbox") {
-		audience = "https://test.salesforce.com"
-	} else {
-		audience = "https://login.salesforce.com"
-	}
 	claims := &jwt.MapClaims{
 		"exp": strconv.Itoa(int(time.Now().Unix() + int64(expirationTime.Seconds()))),
-		"aud": audience,
+		"aud": jwtAudience(domain),
 		"iss": consumerKey,
 		"sub": username,
 	}
@@ -263,7 +334,7 @@ func jwtFlow(
 	}
 	endpoint := "/services/oauth2/token"
 	body := strings.NewReader(payload.Encode())
-	auth, err := doAuth(domain+endpoint, body)
+	auth, err := doAuth(config, domain+endpoint, body)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +102,7 @@ func Test_usernamePasswordFlow(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := usernamePasswordFlow(
+				getDefaultConfig(t),
 				tt.args.domain,
 				tt.args.username,
 				tt.args.password,
@@ -170,6 +171,7 @@ func Test_clientCredentialsFlow(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := clientCredentialsFlow(
+				getDefaultConfig(t),
 				tt.args.domain,
 				tt.args.consumerKey,
 				tt.args.consumerSecret,
@@ -345,7 +347,7 @@ func Test_refreshSession(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := refreshSession(tt.args.auth); (err != nil) != tt.wantErr {
+			if err := refreshSession(tt.args.auth, getDefaultConfig(t)); (err != nil) != tt.wantErr {
 				t.Errorf("refreshSession() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -407,6 +409,7 @@ func Test_jwtFlow(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := jwtFlow(
+				getDefaultConfig(t),
 				tt.args.domain,
 				tt.args.username,
 				tt.args.consumerKey,
@@ -460,6 +463,11 @@ func TestAuthFlowType_String(t *testing.T) {
 			want:     "JWT",
 			receiver: 4,
 		},
+		{
+			name:     "soap_login",
+			want:     "SOAP Login",
+			receiver: 5,
+		},
 		{
 			name:     "unknown",
 			want:     "Unknown",
@@ -476,3 +484,25 @@ func TestAuthFlowType_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_jwtAudience(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{domain: "https://login.salesforce.com", want: LoginUrlProduction},
+		{domain: "https://example.my.salesforce.com", want: LoginUrlProduction},
+		{domain: "https://test.salesforce.com", want: LoginUrlSandbox},
+		{domain: "https://example--dev.sandbox.my.salesforce.com", want: LoginUrlSandbox},
+		{domain: "https://login.salesforce.mil", want: LoginUrlMilitary},
+		{domain: "https://example.my.salesforce.mil", want: LoginUrlMilitary},
+		{domain: "https://salesforce.mil.example.com", want: LoginUrlProduction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := jwtAudience(tt.domain); got != tt.want {
+				t.Errorf("jwtAudience() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package salesforce
+
+import "sort"
+
+// GroupingKey returns the group of a record written to an sObject, such as the Id of its parent. Records
+// of the same group are kept in the same batch, or in consecutive batches when a group is larger than a
+// batch, to reduce UNABLE_TO_LOCK_ROW errors when batches that lock the same parent run in parallel.
+// Records with an empty key are not grouped.
+type GroupingKey func(sObjectName string, record map[string]any) string
+
+// GroupByField groups records by the value of a field, such as AccountId of Contact or Opportunity
+// records, or ParentId of records that roll up to the same parent
+func GroupByField(fieldName string) GroupingKey {
+	return func(sObjectName string, record map[string]any) string {
+		key, _ := convertToString(record[fieldName])
+		return key
+	}
+}
+
+// recordGroups is the order that the records of a collection or bulk operation are sent in
+type recordGroups struct {
+	order []int // index of the record sent at each position
+	group []int // group of the record sent at each position
+}
+
+// groupRecords orders records by the grouping key of the client, with groups in the order of their
+// first record and the records of a group in their original order, and returns nil if none is set
+func groupRecords(
+	config *configuration,
+	sObjectName string,
+	records []map[string]any,
+) *recordGroups {
+	if config.batchGrouping == nil {
+		return nil
+	}
+	groupOf := make([]int, len(records))
+	groups := map[string]int{}
+	for i, record := range records {
+		// a group is numbered by the index of its first record
+		key := config.batchGrouping(sObjectName, record)
+		if key == "" {
+			groupOf[i] = i
+			continue
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = i
+			groups[key] = group
+		}
+		groupOf[i] = group
+	}
+
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return groupOf[order[i]] < groupOf[order[j]]
+	})
+	group := make([]int, len(records))
+	for i, index := range order {
+		group[i] = groupOf[index]
+	}
+	return &recordGroups{order: order, group: group}
+}
+
+// batchSize returns the number of records of the batch starting at offset, up to batchSize, that ends
+// with a whole group unless the group it ends in starts the batch
+func (g *recordGroups) batchSize(offset int, batchSize int) int {
+	end := offset + batchSize
+	if g == nil || end >= len(g.group) || g.group[end-1] != g.group[end] {
+		return batchSize
+	}
+	start := end - 1
+	for start > offset && g.group[start-1] == g.group[end] {
+		start--
+	}
+	if start == offset {
+		return batchSize
+	}
+	return start - offset
+}
+
+// orderEncoded returns the encoded records and their ids in the order they are sent in
+func (g *recordGroups) orderEncoded(records [][]byte, ids []string) ([][]byte, []string) {
+	if g == nil {
+		return records, ids
+	}
+	orderedRecords := make([][]byte, len(records))
+	orderedIds := make([]string, len(ids))
+	for i, index := range g.order {
+		orderedRecords[i] = records[index]
+		orderedIds[i] = ids[index]
+	}
+	return orderedRecords, orderedIds
+}
+
+// orderMaps returns the records in the order they are sent in
+func (g *recordGroups) orderMaps(records []map[string]any) []map[string]any {
+	if g == nil {
+		return records
+	}
+	ordered := make([]map[string]any, len(records))
+	for i, index := range g.order {
+		ordered[i] = records[index]
+	}
+	return ordered
+}
+
+// restoreOrder sets the index of each result to the index of its record in the original order and sorts
+// the results by it
+func (g *recordGroups) restoreOrder(results []SalesforceResult) []SalesforceResult {
+	if g == nil {
+		return results
+	}
+	for i := range results {
+		results[i].Index = g.order[results[i].Index]
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	return results
+}
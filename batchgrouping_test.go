@@ -0,0 +1,171 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_groupRecords(t *testing.T) {
+	tests := []struct {
+		name      string
+		records   []map[string]any
+		wantOrder []int
+		wantGroup []int
+	}{
+		{
+			name: "groups_in_order_of_first_record",
+			records: []map[string]any{
+				{"AccountId": "001A"},
+				{"AccountId": "001B"},
+				{"AccountId": "001A"},
+				{"AccountId": "001C"},
+				{"AccountId": "001B"},
+			},
+			wantOrder: []int{0, 2, 1, 4, 3},
+			wantGroup: []int{0, 0, 1, 1, 3},
+		},
+		{
+			name: "records_without_key",
+			records: []map[string]any{
+				{"AccountId": "001A"},
+				{"LastName": "Doe"},
+				{"AccountId": "001A"},
+				{"LastName": "Smith"},
+			},
+			wantOrder: []int{0, 2, 1, 3},
+			wantGroup: []int{0, 0, 1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			config.setDefaults()
+			config.batchGrouping = GroupByField("AccountId")
+
+			got := groupRecords(config, "Contact", tt.records)
+			if !reflect.DeepEqual(got.order, tt.wantOrder) ||
+				!reflect.DeepEqual(got.group, tt.wantGroup) {
+				t.Errorf(
+					"groupRecords() = %v %v, want %v %v",
+					got.order,
+					got.group,
+					tt.wantOrder,
+					tt.wantGroup,
+				)
+			}
+		})
+	}
+
+	config := &configuration{}
+	config.setDefaults()
+	if got := groupRecords(config, "Contact", []map[string]any{{}}); got != nil {
+		t.Errorf("groupRecords() without a grouping key = %v, want nil", got)
+	}
+}
+
+func Test_recordGroups_batchSize(t *testing.T) {
+	groups := &recordGroups{group: []int{0, 0, 2, 2, 2, 5, 6, 6, 6, 6, 6}}
+	tests := []struct {
+		name      string
+		groups    *recordGroups
+		offset    int
+		batchSize int
+		want      int
+	}{
+		{
+			name:      "ends_at_group_boundary",
+			groups:    groups,
+			offset:    0,
+			batchSize: 2,
+			want:      2,
+		},
+		{
+			name:      "ends_before_split_group",
+			groups:    groups,
+			offset:    0,
+			batchSize: 4,
+			want:      2,
+		},
+		{
+			name:      "group_larger_than_batch",
+			groups:    groups,
+			offset:    6,
+			batchSize: 3,
+			want:      3,
+		},
+		{
+			name:      "last_batch",
+			groups:    groups,
+			offset:    5,
+			batchSize: 10,
+			want:      10,
+		},
+		{
+			name:      "no_groups",
+			groups:    nil,
+			offset:    0,
+			batchSize: 4,
+			want:      4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.groups.batchSize(tt.offset, tt.batchSize); got != tt.want {
+				t.Errorf("batchSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithBatchGrouping_collection(t *testing.T) {
+	batches := [][]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Records []map[string]any `json:"records"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		batch := []string{}
+		results := []SalesforceResult{}
+		for _, record := range body.Records {
+			name, _ := record["LastName"].(string)
+			batch = append(batch, name)
+			results = append(results, SalesforceResult{Id: name, Success: true})
+		}
+		batches = append(batches, batch)
+		response, _ := json.Marshal(results)
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	})
+	if err := WithBatchGrouping(GroupByField("AccountId"))(sf.config); err != nil {
+		t.Fatalf("WithBatchGrouping() error = %v", err)
+	}
+	records := []map[string]any{
+		{"LastName": "a1", "AccountId": "001A"},
+		{"LastName": "b1", "AccountId": "001B"},
+		{"LastName": "a2", "AccountId": "001A"},
+		{"LastName": "c1", "AccountId": "001C"},
+		{"LastName": "b2", "AccountId": "001B"},
+	}
+	results, err := sf.InsertCollection("Contact", records, 3)
+	if err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+
+	wantBatches := [][]string{{"a1", "a2"}, {"b1", "b2", "c1"}}
+	if !reflect.DeepEqual(batches, wantBatches) {
+		t.Errorf("batches = %v, want %v", batches, wantBatches)
+	}
+	for i, result := range results.Results {
+		if result.Index != i || result.Id != records[i]["LastName"] {
+			t.Errorf("result %d = %+v, want the result of %v", i, result, records[i])
+		}
+	}
+}
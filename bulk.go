@@ -73,7 +73,7 @@ func updateJobState(job bulkJob, state string, sf *Salesforce) error {
 		method:   http.MethodPatch,
 		uri:      "/jobs/ingest/" + job.Id,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
 	})
 	if err != nil {
@@ -88,7 +88,7 @@ func createBulkJob(sf *Salesforce, jobType string, body []byte) (bulkJob, error)
 		method:   http.MethodPost,
 		uri:      "/jobs/" + jobType,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
 	})
 	if err != nil {
@@ -109,7 +109,7 @@ func createBulkJob(sf *Salesforce, jobType string, body []byte) (bulkJob, error)
 	return *newJob, nil
 }
 
-func uploadJobData(sf *Salesforce, data string, bulkJob bulkJob) error {
+func uploadJobData(sf *Salesforce, data io.Reader, bulkJob bulkJob) error {
 	_, uploadDataErr := doRequest(sf.auth, sf.config, requestPayload{
 		method:   http.MethodPut,
 		uri:      "/jobs/ingest/" + bulkJob.Id + "/batches",
@@ -152,6 +152,7 @@ func getJobResults(sf *Salesforce, jobType string, bulkJobId string) (BulkJobRes
 	if jsonError != nil {
 		return BulkJobResults{}, jsonError
 	}
+	sf.config.metrics.observeBulkJob(jobType, *bulkJobResults)
 
 	return *bulkJobResults, nil
 }
@@ -244,7 +245,15 @@ func pollUntilContextTimeout(
 ) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	return pollUntilDone(ctx, interval, checkFn)
+}
 
+// pollUntilDone calls checkFn every interval until it reports done, returns an error, or ctx is done
+func pollUntilDone(
+	ctx context.Context,
+	interval time.Duration,
+	checkFn func(context.Context) (bool, error),
+) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -485,18 +494,31 @@ func doBulkJob(
 	if err != nil {
 		return []string{}, err
 	}
+	if err := runBeforeHook(sf, operation, sObjectName, recordMap...); err != nil {
+		return []string{}, err
+	}
+	if operation != deleteOperation {
+		if err := prepareRecords(sf, sObjectName, operation, recordMap...); err != nil {
+			return []string{}, err
+		}
+	}
+	groups := groupRecords(sf.config, sObjectName, recordMap)
+	recordMap = flattenLookups(recordMap)
+	written := recordMap
+	recordMap = groups.orderMaps(recordMap)
 
 	var jobErrors error
 	var jobIds []string
-	for len(recordMap) > 0 {
+	for sent := 0; len(recordMap) > 0; {
 		var batch []map[string]any
 		var remaining []map[string]any
-		if len(recordMap) > batchSize {
-			batch, remaining = recordMap[:batchSize], recordMap[batchSize:]
+		if size := groups.batchSize(sent, batchSize); len(recordMap) > size {
+			batch, remaining = recordMap[:size], recordMap[size:]
 		} else {
 			batch = recordMap
 		}
 		recordMap = remaining
+		sent += len(batch)
 
 		job, constructJobErr := constructBulkJobRequest(
 			sf,
@@ -515,7 +537,7 @@ func doBulkJob(
 			return jobIds, convertErr
 		}
 
-		uploadErr := uploadJobData(sf, data, job)
+		uploadErr := uploadJobData(sf, strings.NewReader(data), job)
 		if uploadErr != nil {
 			return jobIds, uploadErr
 		}
@@ -528,8 +550,11 @@ func doBulkJob(
 		}
 		jobErrors = <-c
 	}
+	if jobErrors != nil {
+		return jobIds, jobErrors
+	}
 
-	return jobIds, jobErrors
+	return jobIds, runAfterHook(sf, operation, sObjectName, written...)
 }
 
 func doBulkJobWithFile(
@@ -589,7 +614,7 @@ func doBulkJobWithFile(
 			break
 		}
 
-		uploadErr := uploadJobData(sf, buf.String(), job)
+		uploadErr := uploadJobData(sf, bytes.NewReader(buf.Bytes()), job)
 		if uploadErr != nil {
 			jobErrors = errors.Join(jobErrors, uploadErr)
 		}
@@ -1025,7 +1025,7 @@ func Test_uploadJobData(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := uploadJobData(tt.args.sf, tt.args.data, tt.args.bulkJob); (err != nil) != tt.wantErr {
+			if err := uploadJobData(tt.args.sf, strings.NewReader(tt.args.data), tt.args.bulkJob); (err != nil) != tt.wantErr {
 				t.Errorf("uploadJobData() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
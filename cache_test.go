@@ -0,0 +1,40 @@
+package salesforce
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ttlCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newTTLCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("key", "value")
+	if got, ok := cache.get("key"); !ok || got != "value" {
+		t.Errorf("ttlCache.get() = %v, %v, want value, true", got, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("key"); ok {
+		t.Errorf("ttlCache.get() returned an expired entry")
+	}
+
+	cache.set("key", "value")
+	cache.clear()
+	if _, ok := cache.get("key"); ok {
+		t.Errorf("ttlCache.get() returned a cleared entry")
+	}
+
+	disabled := newTTLCache(0)
+	disabled.set("key", "value")
+	if _, ok := disabled.get("key"); ok {
+		t.Errorf("ttlCache.get() returned an entry from a disabled cache")
+	}
+
+	var nilCache *ttlCache
+	nilCache.set("key", "value")
+	if _, ok := nilCache.get("key"); ok {
+		t.Errorf("ttlCache.get() returned an entry from a nil cache")
+	}
+}
@@ -0,0 +1,174 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change types reported in the ChangeEventHeader of a Change Data Capture event
+const (
+	ChangeTypeCreate      = "CREATE"
+	ChangeTypeUpdate      = "UPDATE"
+	ChangeTypeDelete      = "DELETE"
+	ChangeTypeUndelete    = "UNDELETE"
+	ChangeTypeGapCreate   = "GAP_CREATE"
+	ChangeTypeGapUpdate   = "GAP_UPDATE"
+	ChangeTypeGapDelete   = "GAP_DELETE"
+	ChangeTypeGapUndelete = "GAP_UNDELETE"
+	ChangeTypeGapOverflow = "GAP_OVERFLOW"
+)
+
+const changeEventHeaderField = "ChangeEventHeader"
+
+// ChangeEventHeader contains the metadata of a Change Data Capture event
+type ChangeEventHeader struct {
+	EntityName      string   `json:"entityName"`
+	RecordIds       []string `json:"recordIds"`
+	ChangeType      string   `json:"changeType"`
+	ChangeOrigin    string   `json:"changeOrigin"`
+	TransactionKey  string   `json:"transactionKey"`
+	SequenceNumber  int      `json:"sequenceNumber"`
+	CommitTimestamp int64    `json:"commitTimestamp"`
+	CommitNumber    int64    `json:"commitNumber"`
+	CommitUser      string   `json:"commitUser"`
+	NulledFields    []string `json:"nulledFields"`
+	DiffFields      []string `json:"diffFields"`
+	ChangedFields   []string `json:"changedFields"`
+}
+
+// CommitTime returns the commit timestamp of the change as a time.Time
+func (h ChangeEventHeader) CommitTime() time.Time {
+	return time.UnixMilli(h.CommitTimestamp).UTC()
+}
+
+// IsGap returns true if the event is a gap event, which only contains the header and record ids
+func (h ChangeEventHeader) IsGap() bool {
+	return strings.HasPrefix(h.ChangeType, "GAP_")
+}
+
+// ChangeEvent is a decoded Change Data Capture event
+type ChangeEvent struct {
+	Header   ChangeEventHeader
+	Fields   map[string]any // changed field values, excluding the ChangeEventHeader
+	ReplayId int64
+	Channel  string
+}
+
+// IsDiffField returns true if the value of the field was sent as a diff rather than the full value
+func (e ChangeEvent) IsDiffField(fieldName string) bool {
+	return slices.Contains(e.Header.DiffFields, fieldName)
+}
+
+// IsNulledField returns true if the field was explicitly set to null by the change
+func (e ChangeEvent) IsNulledField(fieldName string) bool {
+	return slices.Contains(e.Header.NulledFields, fieldName)
+}
+
+// Decode decodes the changed field values into a struct or map, honoring salesforce struct tags
+func (e ChangeEvent) Decode(v any) error {
+	return mapstructureDecode(e.Fields, v)
+}
+
+type cometdChangeEventMessage struct {
+	Channel string `json:"channel"`
+	Data    struct {
+		Payload map[string]json.RawMessage `json:"payload"`
+		Event   struct {
+			ReplayId int64 `json:"replayId"`
+		} `json:"event"`
+	} `json:"data"`
+}
+
+// DecodeChangeEvent decodes a Change Data Capture event from JSON. Both the streaming (CometD) message
+// envelope and a bare event payload containing a ChangeEventHeader are supported.
+func DecodeChangeEvent(data []byte) (ChangeEvent, error) {
+	message := cometdChangeEventMessage{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return ChangeEvent{}, err
+	}
+	payload := message.Data.Payload
+	if payload == nil {
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return ChangeEvent{}, err
+		}
+	}
+
+	rawHeader, ok := payload[changeEventHeaderField]
+	if !ok {
+		return ChangeEvent{}, errors.New("change event payload is missing the ChangeEventHeader")
+	}
+	event := ChangeEvent{
+		ReplayId: message.Data.Event.ReplayId,
+		Channel:  message.Channel,
+		Fields:   map[string]any{},
+	}
+	if err := json.Unmarshal(rawHeader, &event.Header); err != nil {
+		return ChangeEvent{}, fmt.Errorf("decoding ChangeEventHeader: %w", err)
+	}
+	for field, rawValue := range payload {
+		if field == changeEventHeaderField {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			return ChangeEvent{}, fmt.Errorf("decoding field %s: %w", field, err)
+		}
+		event.Fields[field] = value
+	}
+
+	return event, nil
+}
+
+// ExpandFieldBitmaps converts the hex bitmaps used by the Pub/Sub API for changedFields, nulledFields,
+// and diffFields into field names. fieldNames holds the top level field names in schema order, and
+// nestedFieldNames holds the field names of compound fields (ex: Name) keyed by their top level index.
+// Bitmaps of the form "3-0x0002" refer to nested fields and are returned as "Parent.Child".
+func ExpandFieldBitmaps(
+	bitmaps []string,
+	fieldNames []string,
+	nestedFieldNames map[int][]string,
+) ([]string, error) {
+	var fields []string
+	for _, bitmap := range bitmaps {
+		parentIndex := -1
+		names := fieldNames
+		if before, after, found := strings.Cut(bitmap, "-"); found {
+			index, err := strconv.Atoi(before)
+			if err != nil || index < 0 || index >= len(fieldNames) {
+				return nil, fmt.Errorf("invalid field bitmap: %s", bitmap)
+			}
+			parentIndex = index
+			names = nestedFieldNames[index]
+			bitmap = after
+		}
+
+		bits, ok := new(big.Int).SetString(strings.TrimPrefix(bitmap, "0x"), 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid field bitmap: %s", bitmap)
+		}
+		for i := 0; i < bits.BitLen(); i++ {
+			if bits.Bit(i) == 0 {
+				continue
+			}
+			if i >= len(names) {
+				return nil, fmt.Errorf(
+					"field bitmap %s references unknown field index %d",
+					bitmap,
+					i,
+				)
+			}
+			if parentIndex >= 0 {
+				fields = append(fields, fieldNames[parentIndex]+"."+names[i])
+			} else {
+				fields = append(fields, names[i])
+			}
+		}
+	}
+	return fields, nil
+}
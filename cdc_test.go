@@ -0,0 +1,211 @@
+package salesforce
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeChangeEvent(t *testing.T) {
+	cometdMessage := `{
+		"channel": "/data/AccountChangeEvent",
+		"data": {
+			"schema": "IeRuaY6cbI_HsV8Rv1Mc5g",
+			"payload": {
+				"ChangeEventHeader": {
+					"entityName": "Account",
+					"recordIds": ["001xx000003DGb2AAG"],
+					"changeType": "UPDATE",
+					"changeOrigin": "com/salesforce/api/soap/58.0",
+					"transactionKey": "0002343d-9d90-e395-ed20-cf416ba652ad",
+					"sequenceNumber": 1,
+					"commitTimestamp": 1700000000000,
+					"commitNumber": 10943487123,
+					"commitUser": "005xx000001SwR6AAK",
+					"nulledFields": ["Phone"],
+					"diffFields": ["Description"],
+					"changedFields": ["Name", "Phone", "Description"]
+				},
+				"Name": "Acme",
+				"Phone": null,
+				"Description": "@@ -1 +1 @@"
+			},
+			"event": {"replayId": 42}
+		}
+	}`
+	barePayload := `{
+		"ChangeEventHeader": {"entityName": "Contact", "changeType": "CREATE", "recordIds": ["003"]},
+		"LastName": "Smith"
+	}`
+
+	tests := []struct {
+		name       string
+		data       string
+		wantHeader ChangeEventHeader
+		wantFields map[string]any
+		wantReplay int64
+		wantErr    bool
+	}{
+		{
+			name: "cometd_message",
+			data: cometdMessage,
+			wantHeader: ChangeEventHeader{
+				EntityName:      "Account",
+				RecordIds:       []string{"001xx000003DGb2AAG"},
+				ChangeType:      ChangeTypeUpdate,
+				ChangeOrigin:    "com/salesforce/api/soap/58.0",
+				TransactionKey:  "0002343d-9d90-e395-ed20-cf416ba652ad",
+				SequenceNumber:  1,
+				CommitTimestamp: 1700000000000,
+				CommitNumber:    10943487123,
+				CommitUser:      "005xx000001SwR6AAK",
+				NulledFields:    []string{"Phone"},
+				DiffFields:      []string{"Description"},
+				ChangedFields:   []string{"Name", "Phone", "Description"},
+			},
+			wantFields: map[string]any{
+				"Name":        "Acme",
+				"Phone":       nil,
+				"Description": "@@ -1 +1 @@",
+			},
+			wantReplay: 42,
+			wantErr:    false,
+		},
+		{
+			name: "bare_payload",
+			data: barePayload,
+			wantHeader: ChangeEventHeader{
+				EntityName: "Contact",
+				ChangeType: ChangeTypeCreate,
+				RecordIds:  []string{"003"},
+			},
+			wantFields: map[string]any{"LastName": "Smith"},
+			wantReplay: 0,
+			wantErr:    false,
+		},
+		{
+			name:    "missing_header",
+			data:    `{"Name": "Acme"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_json",
+			data:    `{`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeChangeEvent([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeChangeEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Header, tt.wantHeader) {
+				t.Errorf("DecodeChangeEvent() header = %v, want %v", got.Header, tt.wantHeader)
+			}
+			if !reflect.DeepEqual(got.Fields, tt.wantFields) {
+				t.Errorf("DecodeChangeEvent() fields = %v, want %v", got.Fields, tt.wantFields)
+			}
+			if got.ReplayId != tt.wantReplay {
+				t.Errorf("DecodeChangeEvent() replayId = %v, want %v", got.ReplayId, tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestChangeEvent_Decode(t *testing.T) {
+	type account struct {
+		Name        string
+		Description string `salesforce:"Description"`
+	}
+	event := ChangeEvent{
+		Header: ChangeEventHeader{
+			ChangeType:      ChangeTypeGapUpdate,
+			CommitTimestamp: 1700000000000,
+			NulledFields:    []string{"Phone"},
+			DiffFields:      []string{"Description"},
+		},
+		Fields: map[string]any{"Name": "Acme", "Description": "text"},
+	}
+
+	got := account{}
+	if err := event.Decode(&got); err != nil {
+		t.Fatalf("ChangeEvent.Decode() error = %v", err)
+	}
+	want := account{Name: "Acme", Description: "text"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangeEvent.Decode() = %v, want %v", got, want)
+	}
+	if !event.IsDiffField("Description") || event.IsDiffField("Name") {
+		t.Errorf("ChangeEvent.IsDiffField() returned unexpected result")
+	}
+	if !event.IsNulledField("Phone") || event.IsNulledField("Name") {
+		t.Errorf("ChangeEvent.IsNulledField() returned unexpected result")
+	}
+	if !event.Header.IsGap() {
+		t.Errorf("ChangeEventHeader.IsGap() = false, want true")
+	}
+	wantTime := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	if !event.Header.CommitTime().Equal(wantTime) {
+		t.Errorf(
+			"ChangeEventHeader.CommitTime() = %v, want %v",
+			event.Header.CommitTime(),
+			wantTime,
+		)
+	}
+}
+
+func TestExpandFieldBitmaps(t *testing.T) {
+	fieldNames := []string{"ChangeEventHeader", "Name", "Phone", "BillingAddress", "Description"}
+	nested := map[int][]string{3: {"Street", "City", "PostalCode"}}
+	tests := []struct {
+		name    string
+		bitmaps []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "top_level_fields",
+			bitmaps: []string{"0x16"},
+			want:    []string{"Name", "Phone", "Description"},
+			wantErr: false,
+		},
+		{
+			name:    "nested_fields",
+			bitmaps: []string{"0x02", "3-0x0005"},
+			want:    []string{"Name", "BillingAddress.Street", "BillingAddress.PostalCode"},
+			wantErr: false,
+		},
+		{
+			name:    "unknown_index",
+			bitmaps: []string{"0x40"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_hex",
+			bitmaps: []string{"0xZZ"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_parent",
+			bitmaps: []string{"9-0x01"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandFieldBitmaps(tt.bitmaps, fieldNames, nested)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpandFieldBitmaps() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandFieldBitmaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
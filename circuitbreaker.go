@@ -0,0 +1,188 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of sending a request while the circuit breaker is open
+var ErrCircuitOpen = errors.New("salesforce circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed sends requests and counts their failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen until the open timeout passes
+	CircuitOpen
+	// CircuitHalfOpen sends a limited number of trial requests to decide whether to close or reopen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerSettings configures the circuit breaker, see WithCircuitBreaker. Zero values take
+// the defaults noted on each field.
+type CircuitBreakerSettings struct {
+	FailureRate      float64       // failure rate, from 0 to 1, that opens the circuit (default 0.5)
+	MinRequests      int           // requests in a window before the failure rate is checked (default 20)
+	Window           time.Duration // period requests and failures are counted over (default 1 minute)
+	OpenTimeout      time.Duration // time the circuit stays open before trial requests (default 30 seconds)
+	HalfOpenRequests int           // trial requests that must succeed to close the circuit (default 1)
+}
+
+type circuitBreaker struct {
+	mu          sync.Mutex
+	settings    CircuitBreakerSettings
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	trials      int // trial requests sent while half-open
+	successes   int // trial requests that succeeded while half-open
+	now         func() time.Time
+}
+
+func newCircuitBreaker(settings CircuitBreakerSettings) (*circuitBreaker, error) {
+	if settings.FailureRate < 0 || settings.FailureRate > 1 {
+		return nil, errors.New("circuit breaker failure rate must be between 0 and 1")
+	}
+	if settings.MinRequests < 0 || settings.Window < 0 || settings.OpenTimeout < 0 ||
+		settings.HalfOpenRequests < 0 {
+		return nil, errors.New("circuit breaker settings cannot be negative")
+	}
+	if settings.FailureRate == 0 {
+		settings.FailureRate = 0.5
+	}
+	if settings.MinRequests == 0 {
+		settings.MinRequests = 20
+	}
+	if settings.Window == 0 {
+		settings.Window = time.Minute
+	}
+	if settings.OpenTimeout == 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.HalfOpenRequests == 0 {
+		settings.HalfOpenRequests = 1
+	}
+	return &circuitBreaker{settings: settings, now: time.Now}, nil
+}
+
+// allow returns ErrCircuitOpen if a request cannot be sent
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := cb.now()
+	if cb.state == CircuitOpen {
+		if now.Sub(cb.openedAt) < cb.settings.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.trials = 0
+		cb.successes = 0
+	}
+	if cb.state == CircuitHalfOpen {
+		if cb.trials >= cb.settings.HalfOpenRequests {
+			return ErrCircuitOpen
+		}
+		cb.trials++
+	}
+	return nil
+}
+
+// record counts the outcome of a sent request. Transport errors and server errors are failures,
+// requests canceled by the caller are not counted. SOAP faults are sent with a server error status but
+// are errors of the call, such as an invalid session, so they are not failures.
+func (cb *circuitBreaker) record(resp *http.Response, err error) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if errors.Is(err, context.Canceled) {
+		if cb.state == CircuitHalfOpen && cb.trials > 0 {
+			cb.trials--
+		}
+		return
+	}
+	failed := err != nil ||
+		(resp.StatusCode >= http.StatusInternalServerError && !isSoapFault(resp))
+	now := cb.now()
+	switch cb.state {
+	case CircuitHalfOpen:
+		if failed {
+			cb.open(now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.settings.HalfOpenRequests {
+			cb.state = CircuitClosed
+			cb.windowStart = now
+			cb.requests = 0
+			cb.failures = 0
+		}
+	case CircuitClosed:
+		if now.Sub(cb.windowStart) >= cb.settings.Window {
+			cb.windowStart = now
+			cb.requests = 0
+			cb.failures = 0
+		}
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= cb.settings.MinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= cb.settings.FailureRate {
+			cb.open(now)
+		}
+	}
+}
+
+// isSoapFault returns whether a response is a SOAP fault, which is the only xml error Salesforce returns
+func isSoapFault(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusInternalServerError &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "text/xml")
+}
+
+func (cb *circuitBreaker) open(now time.Time) {
+	cb.state = CircuitOpen
+	cb.openedAt = now
+}
+
+func (cb *circuitBreaker) currentState() CircuitState {
+	if cb == nil {
+		return CircuitClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.settings.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// GetCircuitState returns the state of the circuit breaker, or CircuitClosed if none is configured
+func (sf *Salesforce) GetCircuitState() CircuitState {
+	return sf.config.circuitBreaker.currentState()
+}
@@ -0,0 +1,170 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb, err := newCircuitBreaker(CircuitBreakerSettings{
+		FailureRate:      0.5,
+		MinRequests:      4,
+		Window:           time.Minute,
+		OpenTimeout:      10 * time.Second,
+		HalfOpenRequests: 2,
+	})
+	if err != nil {
+		t.Fatalf("newCircuitBreaker() error = %v", err)
+	}
+	cb.now = func() time.Time { return now }
+	ok := &http.Response{StatusCode: http.StatusOK}
+	serverError := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	clientError := &http.Response{StatusCode: http.StatusBadRequest}
+
+	send := func(resp *http.Response, err error) error {
+		if allowErr := cb.allow(); allowErr != nil {
+			return allowErr
+		}
+		cb.record(resp, err)
+		return nil
+	}
+
+	// client errors and canceled requests are not failures
+	_ = send(clientError, nil)
+	_ = send(nil, context.Canceled)
+	_ = send(serverError, nil)
+	_ = send(ok, nil)
+	if got := cb.currentState(); got != CircuitClosed {
+		t.Fatalf("state = %v, want closed", got)
+	}
+
+	// the window restarts after it passes
+	now = now.Add(time.Minute)
+	_ = send(serverError, nil)
+	_ = send(nil, errors.New("connection reset"))
+	_ = send(ok, nil)
+	if got := cb.currentState(); got != CircuitClosed {
+		t.Fatalf("state = %v, want closed below min requests", got)
+	}
+	_ = send(serverError, nil)
+	if got := cb.currentState(); got != CircuitOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	if err := send(ok, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() = %v, want ErrCircuitOpen", err)
+	}
+
+	// a failed trial request opens the circuit again
+	now = now.Add(10 * time.Second)
+	if got := cb.currentState(); got != CircuitHalfOpen {
+		t.Fatalf("state = %v, want half-open", got)
+	}
+	_ = send(serverError, nil)
+	if got := cb.currentState(); got != CircuitOpen {
+		t.Fatalf("state = %v, want open after failed trial", got)
+	}
+
+	// trial requests are limited, and close the circuit once they all succeed
+	now = now.Add(10 * time.Second)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("allow() = %v for first trial", err)
+	}
+	if err := cb.allow(); err != nil {
+		t.Fatalf("allow() = %v for second trial", err)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() = %v for third trial, want ErrCircuitOpen", err)
+	}
+	cb.record(ok, nil)
+	cb.record(ok, nil)
+	if got := cb.currentState(); got != CircuitClosed {
+		t.Fatalf("state = %v, want closed after successful trials", got)
+	}
+}
+
+func Test_newCircuitBreaker(t *testing.T) {
+	cb, err := newCircuitBreaker(CircuitBreakerSettings{})
+	if err != nil {
+		t.Fatalf("newCircuitBreaker() error = %v", err)
+	}
+	want := CircuitBreakerSettings{
+		FailureRate:      0.5,
+		MinRequests:      20,
+		Window:           time.Minute,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+	if cb.settings != want {
+		t.Errorf("newCircuitBreaker() settings = %+v, want %+v", cb.settings, want)
+	}
+	if _, err := newCircuitBreaker(CircuitBreakerSettings{FailureRate: 1.5}); err == nil {
+		t.Error("newCircuitBreaker() expected an error for a failure rate above 1")
+	}
+	if _, err := newCircuitBreaker(CircuitBreakerSettings{Window: -time.Second}); err == nil {
+		t.Error("newCircuitBreaker() expected an error for a negative window")
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`[{"errorCode":"SERVER_UNAVAILABLE","message":"down"}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	if err := WithCircuitBreaker(CircuitBreakerSettings{MinRequests: 2})(sf.config); err != nil {
+		t.Fatalf("WithCircuitBreaker() error = %v", err)
+	}
+
+	for range 2 {
+		if _, err := sf.DoRequest(http.MethodGet, "/limits", nil); err == nil {
+			t.Fatal("DoRequest() expected an error")
+		}
+	}
+	if got := sf.GetCircuitState(); got != CircuitOpen {
+		t.Errorf("GetCircuitState() = %v, want open", got)
+	}
+	_, err := sf.DoRequest(http.MethodGet, "/limits", nil)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("DoRequest() error = %v, want ErrCircuitOpen", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", hits.Load())
+	}
+}
+
+func TestWithCircuitBreaker_halfOpenRequestNotSent(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	if err := WithCircuitBreaker(CircuitBreakerSettings{})(sf.config); err != nil {
+		t.Fatalf("WithCircuitBreaker() error = %v", err)
+	}
+	cb := sf.config.circuitBreaker
+	cb.state = CircuitOpen
+	cb.openedAt = time.Now().Add(-cb.settings.OpenTimeout)
+
+	// a request that cannot be built does not take the only trial
+	if _, err := sf.DoRequest("BAD METHOD", "/limits", nil); err == nil ||
+		errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("DoRequest() error = %v, want a request error", err)
+	}
+	if _, err := sf.DoRequest(http.MethodGet, "/limits", nil); err != nil {
+		t.Fatalf("DoRequest() error = %v, want the trial request to be sent", err)
+	}
+	if got := sf.GetCircuitState(); got != CircuitClosed || hits.Load() != 1 {
+		t.Errorf("state = %v after %d requests, want closed after 1", got, hits.Load())
+	}
+}
@@ -0,0 +1,82 @@
+// Command sfgen generates Go structs for Salesforce sObjects, or typed constants for the values of their
+// picklist fields, from their describe metadata.
+//
+// Credentials are read from environment variables, see salesforce.InitFromEnv. Use it with go generate:
+//
+//	//go:generate go run github.com/k-capehart/go-salesforce/v3/cmd/sfgen -package models -out sobjects.go -objects Account,Contact
+//	//go:generate go run github.com/k-capehart/go-salesforce/v3/cmd/sfgen -package models -out picklists.go -picklists Opportunity.StageName,Case.Status
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k-capehart/go-salesforce/v3"
+)
+
+func main() {
+	packageName := flag.String(
+		"package",
+		os.Getenv("GOPACKAGE"),
+		"package name of the generated file",
+	)
+	out := flag.String("out", "", "output file, defaults to stdout")
+	objects := flag.String("objects", "", "comma separated sObject API names")
+	picklists := flag.String(
+		"picklists",
+		"",
+		"comma separated picklist fields, such as Case.Status, to generate constants for instead of structs",
+	)
+	flag.Parse()
+
+	if err := run(*packageName, *out, *objects, *picklists); err != nil {
+		fmt.Fprintln(os.Stderr, "sfgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(packageName string, out string, objects string, picklists string) error {
+	sObjectNames := splitNames(objects)
+	picklistFields := splitNames(picklists)
+	if len(sObjectNames) == 0 && len(picklistFields) == 0 {
+		return fmt.Errorf("-objects or -picklists is required")
+	}
+	if len(sObjectNames) > 0 && len(picklistFields) > 0 {
+		return fmt.Errorf(
+			"-objects and -picklists generate separate files, run sfgen once for each",
+		)
+	}
+
+	sf, err := salesforce.InitFromEnv()
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	if len(picklistFields) > 0 {
+		err = sf.GeneratePicklistConstants(&src, packageName, picklistFields...)
+	} else {
+		err = sf.GenerateStructs(&src, packageName, sObjectNames...)
+	}
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src.Bytes())
+		return err
+	}
+	return os.WriteFile(out, src.Bytes(), 0o644)
+}
+
+func splitNames(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,162 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	cmsChannelsUri = "/connect/cms/delivery/channels"
+	cmsPageSizeMax = 250
+)
+
+// CMSChannel is a content delivery channel of Salesforce CMS, which publishes managed content to a
+// site or to headless apps
+type CMSChannel struct {
+	ChannelId           string `json:"channelId"`
+	ChannelName         string `json:"channelName"`
+	ChannelType         string `json:"channelType"` // Community or PublicUnauthenticated
+	Domain              string `json:"domain"`      // custom domain url of the channel, if any
+	DomainName          string `json:"domainName"`
+	IsChannelSearchable bool   `json:"isChannelSearchable"`
+	IsDomainLocked      bool   `json:"isDomainLocked"`
+}
+
+// ManagedContentNode is a field of managed content, such as its title, body, or banner image
+type ManagedContentNode struct {
+	NodeType           string `json:"nodeType"` // such as NameField, Text, RichText, Media, or Url
+	Value              string `json:"value"`    // value of text nodes
+	Url                string `json:"url"`      // url of media nodes
+	UnauthenticatedUrl string `json:"unauthenticatedUrl"`
+	MediaType          string `json:"mediaType"`
+	MimeType           string `json:"mimeType"`
+	FileName           string `json:"fileName"`
+	AltText            string `json:"altText"`
+	Title              string `json:"title"`
+}
+
+// ManagedContent is a published version of Salesforce CMS content
+type ManagedContent struct {
+	ContentKey         string                        `json:"contentKey"` // stable across versions and orgs
+	ManagedContentId   string                        `json:"managedContentId"`
+	Title              string                        `json:"title"`
+	Type               string                        `json:"type"` // content type, such as news
+	TypeLabel          string                        `json:"typeLabel"`
+	Language           string                        `json:"language"`
+	ContentUrlName     string                        `json:"contentUrlName"`
+	PublishedDate      string                        `json:"publishedDate"`
+	UnauthenticatedUrl string                        `json:"unauthenticatedUrl"`
+	ContentNodes       map[string]ManagedContentNode `json:"contentNodes"` // fields by name
+}
+
+// Text returns the value of a text node of the content, or an empty string if it has none by that name
+func (c ManagedContent) Text(nodeName string) string {
+	return c.ContentNodes[nodeName].Value
+}
+
+// CMSContentQuery filters the managed content of a channel. Zero values match all published content.
+type CMSContentQuery struct {
+	ManagedContentType string   // content type, such as news
+	ContentKeys        []string // content keys, such as MCBBWXCX5L7BGKJIJBHKYC47VOXA
+	ManagedContentIds  []string
+	Language           string // such as en_US, the channel's default language if empty
+	ShowAbsoluteUrl    bool   // return absolute instead of relative urls of media
+}
+
+type cmsChannelsResponse struct {
+	Channels    []CMSChannel `json:"channels"`
+	NextPageUrl string       `json:"nextPageUrl"`
+}
+
+type cmsContentsResponse struct {
+	Items       []ManagedContent `json:"items"`
+	NextPageUrl string           `json:"nextPageUrl"`
+}
+
+// GetCMSChannels returns the Salesforce CMS delivery channels the user can access
+func (sf *Salesforce) GetCMSChannels(ctx context.Context) ([]CMSChannel, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	channels := []CMSChannel{}
+	uri := cmsChannelsUri
+	for uri != "" {
+		page := cmsChannelsResponse{}
+		if err := getConnectPage(ctx, sf, uri, &page); err != nil {
+			return nil, err
+		}
+		channels = append(channels, page.Channels...)
+		uri = connectNextPageUri(sf, page.NextPageUrl)
+	}
+	return channels, nil
+}
+
+// GetCMSContents returns the published managed content of a delivery channel that matches the query,
+// reading every page of results
+func (sf *Salesforce) GetCMSContents(
+	ctx context.Context,
+	channelId string,
+	query CMSContentQuery,
+) ([]ManagedContent, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if channelId == "" {
+		return nil, errors.New("channel id is required")
+	}
+	params := url.Values{}
+	params.Set("pageSize", strconv.Itoa(cmsPageSizeMax))
+	if query.ManagedContentType != "" {
+		params.Set("managedContentType", query.ManagedContentType)
+	}
+	if len(query.ContentKeys) > 0 {
+		params.Set("contentKeys", strings.Join(query.ContentKeys, ","))
+	}
+	if len(query.ManagedContentIds) > 0 {
+		params.Set("managedContentIds", strings.Join(query.ManagedContentIds, ","))
+	}
+	if query.Language != "" {
+		params.Set("language", query.Language)
+	}
+	if query.ShowAbsoluteUrl {
+		params.Set("showAbsoluteUrl", "true")
+	}
+
+	contents := []ManagedContent{}
+	uri := cmsChannelsUri + "/" + url.PathEscape(channelId) + "/contents/query?" + params.Encode()
+	for uri != "" {
+		page := cmsContentsResponse{}
+		if err := getConnectPage(ctx, sf, uri, &page); err != nil {
+			return nil, err
+		}
+		contents = append(contents, page.Items...)
+		uri = connectNextPageUri(sf, page.NextPageUrl)
+	}
+	return contents, nil
+}
+
+func getConnectPage(ctx context.Context, sf *Salesforce, uri string, page any) error {
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return err
+	}
+	return decodeJSONResponse(resp, page)
+}
+
+// connectNextPageUri returns the uri of the next page of a Connect API collection from its
+// nextPageUrl, which includes the versioned data api path, or an empty string on the last page
+func connectNextPageUri(sf *Salesforce, nextPageUrl string) string {
+	return strings.TrimPrefix(nextPageUrl, "/services/data/"+sf.config.apiVersion)
+}
@@ -0,0 +1,84 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSalesforce_GetCMSChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+cmsChannelsUri {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"channels": [{"channelId": "0ap2", "channelName": "App"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"channels": [{"channelId": "0ap1", "channelName": "Site", "channelType": "Community"}],
+			"nextPageUrl": "/services/data/` + apiVersion + cmsChannelsUri + `?page=1"
+		}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	channels, err := sf.GetCMSChannels(context.Background())
+	if err != nil {
+		t.Fatalf("GetCMSChannels() error = %v", err)
+	}
+	want := []CMSChannel{
+		{ChannelId: "0ap1", ChannelName: "Site", ChannelType: "Community"},
+		{ChannelId: "0ap2", ChannelName: "App"},
+	}
+	if !reflect.DeepEqual(channels, want) {
+		t.Errorf("GetCMSChannels() = %+v, want %+v", channels, want)
+	}
+}
+
+func TestSalesforce_GetCMSContents(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+cmsChannelsUri+"/0ap1/contents/query" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		_, _ = w.Write([]byte(`{"items": [{
+			"contentKey": "MCBBWXCX5L7BGKJIJBHKYC47VOXA",
+			"title": "Launch",
+			"type": "news",
+			"contentNodes": {
+				"body": {"nodeType": "RichText", "value": "<p>We launched</p>"},
+				"bannerImage": {"nodeType": "Media", "url": "/cms/media/MCA"}
+			}
+		}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	contents, err := sf.GetCMSContents(context.Background(), "0ap1", CMSContentQuery{
+		ManagedContentType: "news",
+		ContentKeys:        []string{"MCBB", "MCCC"},
+		Language:           "en_US",
+	})
+	if err != nil {
+		t.Fatalf("GetCMSContents() error = %v", err)
+	}
+	if len(contents) != 1 || contents[0].Text("body") != "<p>We launched</p>" ||
+		contents[0].ContentNodes["bannerImage"].Url != "/cms/media/MCA" ||
+		contents[0].Text("missing") != "" {
+		t.Errorf("GetCMSContents() = %+v", contents)
+	}
+	wantQuery := "contentKeys=MCBB%2CMCCC&language=en_US&managedContentType=news&pageSize=250"
+	if len(queries) != 1 || queries[0] != wantQuery {
+		t.Errorf("GetCMSContents() queries = %v, want %s", queries, wantQuery)
+	}
+
+	if _, err := sf.GetCMSContents(context.Background(), "", CMSContentQuery{}); err == nil {
+		t.Errorf("GetCMSContents() expected error without a channel id")
+	}
+}
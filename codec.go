@@ -0,0 +1,18 @@
+package salesforce
+
+import "encoding/json"
+
+// JSONCodec encodes and decodes JSON, see WithJSONCodec. Implementations must be compatible with
+// encoding/json, such as jsoniter.ConfigCompatibleWithStandardLibrary.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// marshalJSON encodes a value with the codec set with WithJSONCodec, or with encoding/json
+func marshalJSON(config *configuration, v any) ([]byte, error) {
+	if config.jsonCodec != nil {
+		return config.jsonCodec.Marshal(v)
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,75 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// countingCodec is a JSONCodec that counts its calls, decoding numbers as json.Number like a codec
+// configured for WithNumberDecoding
+type countingCodec struct {
+	marshals   int
+	unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func TestWithJSONCodec_query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{"NumberOfEmployees":42}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	codec := &countingCodec{}
+	sf.config.jsonCodec = codec
+	sf.config.numberDecoding = NumberInt64
+
+	var records []map[string]any
+	if err := sf.Query("SELECT NumberOfEmployees FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if codec.unmarshals != 1 {
+		t.Errorf("codec unmarshals = %v, want 1", codec.unmarshals)
+	}
+	if records[0]["NumberOfEmployees"] != int64(42) {
+		t.Errorf("NumberOfEmployees = %#v, want int64(42)", records[0]["NumberOfEmployees"])
+	}
+}
+
+func TestWithJSONCodec_dml(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`[{"id":"003000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	codec := &countingCodec{}
+	sf.config.jsonCodec = codec
+
+	records := []map[string]any{{"LastName": "test"}}
+	if _, err := sf.InsertCollection("Contact", records, 200); err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if codec.marshals != 1 {
+		t.Errorf("codec marshals = %v, want 1", codec.marshals)
+	}
+	want := `{"allOrNone":false,"records":[{"LastName":"test","attributes":{"type":"Contact"}}]}`
+	if string(body) != want {
+		t.Errorf("InsertCollection() sent %s, want %s", body, want)
+	}
+}
@@ -0,0 +1,329 @@
+package salesforce
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goFieldTypes maps describe field types to the Go types of generated struct fields
+var goFieldTypes = map[string]string{
+	"id":              "string",
+	"reference":       "string",
+	"string":          "string",
+	"textarea":        "string",
+	"email":           "string",
+	"phone":           "string",
+	"url":             "string",
+	"picklist":        "string",
+	"multipicklist":   "string",
+	"combobox":        "string",
+	"encryptedstring": "string",
+	"base64":          "string",
+	"date":            "string",
+	"datetime":        "string",
+	"time":            "string",
+	"boolean":         "bool",
+	"int":             "int",
+	"long":            "int64",
+	"double":          "float64",
+	"currency":        "float64",
+	"percent":         "float64",
+	"anyType":         "any",
+}
+
+// picklistFieldTypes are the describe field types of picklist fields, see GeneratePicklistConstants
+var picklistFieldTypes = map[string]bool{"picklist": true, "multipicklist": true}
+
+// GenerateStructs describes the given sObjects and writes Go source declaring a struct for each of
+// them to w, see GenerateStructsFromDescribe
+func (sf *Salesforce) GenerateStructs(
+	w io.Writer,
+	packageName string,
+	sObjectNames ...string,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if len(sObjectNames) == 0 {
+		return errors.New("at least one sObject name is required")
+	}
+
+	describes := make([]*SObjectDescribe, len(sObjectNames))
+	for i, sObjectName := range sObjectNames {
+		describe, err := describeSObject(sf, sObjectName)
+		if err != nil {
+			return fmt.Errorf("describing %s: %w", sObjectName, err)
+		}
+		describes[i] = describe
+	}
+	return GenerateStructsFromDescribe(w, packageName, describes...)
+}
+
+// GenerateStructsFromDescribe writes gofmt formatted Go source declaring a struct for each sObject to w.
+// Fields are tagged with their API names and omitempty so that unset fields are not sent with DML.
+// Lookup fields whose parent sObject is also generated get a pointer field for the relationship.
+// Compound address and location fields are left out, their component fields are generated instead.
+func GenerateStructsFromDescribe(
+	w io.Writer,
+	packageName string,
+	describes ...*SObjectDescribe,
+) error {
+	if !token.IsIdentifier(packageName) {
+		return fmt.Errorf("invalid package name: %q", packageName)
+	}
+
+	typeNames := map[string]string{}
+	for _, describe := range describes {
+		if describe == nil || describe.Name == "" {
+			return errors.New("describe results must have an sObject name")
+		}
+		typeNames[describe.Name] = goIdentifier(describe.Name)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(
+		&src,
+		"// Code generated by go-salesforce. DO NOT EDIT.\n\npackage %s\n",
+		packageName,
+	)
+	for _, describe := range describes {
+		typeName := typeNames[describe.Name]
+		fmt.Fprintf(
+			&src,
+			"\n// %s is the %s sObject\ntype %s struct {\n",
+			typeName,
+			describe.Name,
+			typeName,
+		)
+
+		used := map[string]bool{}
+		for _, field := range describe.Fields {
+			goType, ok := goFieldTypes[field.Type]
+			if !ok {
+				continue
+			}
+			writeStructField(&src, used, goIdentifier(field.Name), goType, field.Name)
+
+			if field.RelationshipName == "" || len(field.ReferenceTo) != 1 {
+				continue
+			}
+			if parentType, ok := typeNames[field.ReferenceTo[0]]; ok {
+				writeStructField(
+					&src,
+					used,
+					goIdentifier(field.RelationshipName),
+					"*"+parentType,
+					field.RelationshipName,
+				)
+			}
+		}
+		src.WriteString("}\n")
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated source: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// GeneratePicklistConstants describes the sObjects of the given picklist fields, such as
+// Opportunity.StageName and Case.Status, and writes Go source declaring a string type with a constant
+// for each of their values to w, see GeneratePicklistConstantsFromDescribe. An sObject name without a
+// field, such as Lead, generates constants for every picklist field of the sObject.
+func (sf *Salesforce) GeneratePicklistConstants(
+	w io.Writer,
+	packageName string,
+	fields ...string,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if len(fields) == 0 {
+		return errors.New("at least one picklist field is required")
+	}
+
+	describes := []*SObjectDescribe{}
+	selected := map[string]*SObjectDescribe{}
+	for _, name := range fields {
+		sObjectName, fieldName, hasField := strings.Cut(name, ".")
+		describe, err := describeSObject(sf, sObjectName)
+		if err != nil {
+			return fmt.Errorf("describing %s: %w", sObjectName, err)
+		}
+		picklists, ok := selected[describe.Name]
+		if !ok {
+			picklists = &SObjectDescribe{Name: describe.Name}
+			selected[describe.Name] = picklists
+			describes = append(describes, picklists)
+		}
+		if !hasField {
+			picklists.Fields = append(picklists.Fields, describe.Fields...)
+			continue
+		}
+		field, ok := describe.Field(fieldName)
+		if !ok || !picklistFieldTypes[field.Type] {
+			return fmt.Errorf("%s has no picklist field %s", describe.Name, fieldName)
+		}
+		picklists.Fields = append(picklists.Fields, field)
+	}
+	return GeneratePicklistConstantsFromDescribe(w, packageName, describes...)
+}
+
+// GeneratePicklistConstantsFromDescribe writes gofmt formatted Go source to w declaring, for each picklist
+// and multi-select picklist field of the sObjects, a string type named after the sObject and field, such
+// as OpportunityStageName, with a constant for each active value, such as OpportunityStageNameClosedWon,
+// and a slice of the values in their describe order, such as OpportunityStageNameValues.
+func GeneratePicklistConstantsFromDescribe(
+	w io.Writer,
+	packageName string,
+	describes ...*SObjectDescribe,
+) error {
+	if !token.IsIdentifier(packageName) {
+		return fmt.Errorf("invalid package name: %q", packageName)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(
+		&src,
+		"// Code generated by go-salesforce. DO NOT EDIT.\n\npackage %s\n",
+		packageName,
+	)
+	generated := map[string]bool{}
+	for _, describe := range describes {
+		if describe == nil || describe.Name == "" {
+			return errors.New("describe results must have an sObject name")
+		}
+		for _, field := range describe.Fields {
+			typeName := goIdentifier(describe.Name) + goIdentifier(field.Name)
+			if !picklistFieldTypes[field.Type] || generated[typeName] {
+				continue
+			}
+			generated[typeName] = true
+			writePicklistType(&src, typeName, describe.Name+"."+field.Name, field.PicklistValues)
+		}
+	}
+	if len(generated) == 0 {
+		return errors.New("describe results have no picklist fields")
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated source: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+func writePicklistType(
+	src *bytes.Buffer,
+	typeName string,
+	fieldName string,
+	values []DescribePicklistValue,
+) {
+	fmt.Fprintf(
+		src,
+		"\n// %s is a value of the %s picklist\ntype %s string\n",
+		typeName,
+		fieldName,
+		typeName,
+	)
+	used := map[string]bool{typeName + "Values": true}
+	var names []string
+	for _, value := range values {
+		if !value.Active {
+			continue
+		}
+		name := typeName + camelCase(value.Value)
+		if name == typeName {
+			name += "Value"
+		}
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", name, i)
+		}
+		used[unique] = true
+		if len(names) == 0 {
+			src.WriteString("\nconst (\n")
+		}
+		names = append(names, unique)
+		fmt.Fprintf(src, "\t%s %s = %s", unique, typeName, strconv.Quote(value.Value))
+		if value.Label != value.Value {
+			fmt.Fprintf(src, " // %s", strings.Join(strings.Fields(value.Label), " "))
+		}
+		src.WriteString("\n")
+	}
+	if len(names) > 0 {
+		src.WriteString(")\n")
+	}
+	fmt.Fprintf(
+		src,
+		"\n// %sValues are the active values of the %s picklist\nvar %sValues = []%s{\n",
+		typeName,
+		fieldName,
+		typeName,
+		typeName,
+	)
+	for _, name := range names {
+		fmt.Fprintf(src, "\t%s,\n", name)
+	}
+	src.WriteString("}\n")
+}
+
+func writeStructField(src *bytes.Buffer, used map[string]bool, name, goType, apiName string) {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	fmt.Fprintf(
+		src,
+		"\t%s %s `json:\"%s,omitempty\" salesforce:\"%s,omitempty\"`\n",
+		unique,
+		goType,
+		apiName,
+		apiName,
+	)
+}
+
+// goIdentifier converts an API name such as ns__Account_Status__c to an exported Go identifier
+// such as NsAccountStatus
+func goIdentifier(apiName string) string {
+	name := apiName
+	for _, suffix := range []string{"__c", "__r", "__e", "__mdt", "__x", "__b", "__kav", "__ka"} {
+		if trimmed, found := strings.CutSuffix(name, suffix); found {
+			name = trimmed
+			break
+		}
+	}
+
+	result := camelCase(name)
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
+
+// camelCase joins the words of a name, such as Closed Won or Proposal/Price Quote, capitalizing each
+// word and dropping the characters between them
+func camelCase(name string) string {
+	var identifier strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		identifier.WriteString(string(runes))
+	}
+	return identifier.String()
+}
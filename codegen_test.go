@@ -0,0 +1,235 @@
+package salesforce
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStructsFromDescribe(t *testing.T) {
+	account := &SObjectDescribe{
+		Name: "Account",
+		Fields: []DescribeField{
+			{Name: "Id", Type: "id"},
+			{Name: "Name", Type: "string"},
+			{Name: "NumberOfEmployees", Type: "int"},
+			{Name: "BillingAddress", Type: "address"},
+			{Name: "Is_Active__c", Type: "boolean"},
+		},
+	}
+	contact := &SObjectDescribe{
+		Name: "Contact",
+		Fields: []DescribeField{
+			{Name: "Id", Type: "id"},
+			{
+				Name:             "AccountId",
+				Type:             "reference",
+				ReferenceTo:      []string{"Account"},
+				RelationshipName: "Account",
+			},
+			{
+				Name:             "OwnerId",
+				Type:             "reference",
+				ReferenceTo:      []string{"User"},
+				RelationshipName: "Owner",
+			},
+			{Name: "Amount__c", Type: "currency"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateStructsFromDescribe(&buf, "models", account, contact); err != nil {
+		t.Fatalf("GenerateStructsFromDescribe() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"// Code generated by go-salesforce. DO NOT EDIT.",
+		"package models",
+		"type Account struct {",
+		"Name              string `json:\"Name,omitempty\" salesforce:\"Name,omitempty\"`",
+		"NumberOfEmployees int    `json:\"NumberOfEmployees,omitempty\" salesforce:\"NumberOfEmployees,omitempty\"`",
+		"IsActive          bool   `json:\"Is_Active__c,omitempty\" salesforce:\"Is_Active__c,omitempty\"`",
+		"type Contact struct {",
+		"Account   *Account `json:\"Account,omitempty\" salesforce:\"Account,omitempty\"`",
+		"Amount    float64  `json:\"Amount__c,omitempty\" salesforce:\"Amount__c,omitempty\"`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GenerateStructsFromDescribe() missing %q in\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"BillingAddress", "Owner "} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("GenerateStructsFromDescribe() unexpected %q in\n%s", unwanted, got)
+		}
+	}
+
+	if err := GenerateStructsFromDescribe(&buf, "bad-name", account); err == nil {
+		t.Errorf("GenerateStructsFromDescribe() expected error for invalid package name")
+	}
+}
+
+func TestSalesforce_GenerateStructs(t *testing.T) {
+	describe := SObjectDescribe{
+		Name:   "Account",
+		Fields: []DescribeField{{Name: "Id", Type: "id"}, {Name: "Name", Type: "string"}},
+	}
+	server, sfAuth := setupTestServer(describe, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	var buf bytes.Buffer
+	if err := sf.GenerateStructs(&buf, "models", "Account"); err != nil {
+		t.Fatalf("GenerateStructs() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "type Account struct {") {
+		t.Errorf("GenerateStructs() = %v", buf.String())
+	}
+
+	if err := sf.GenerateStructs(&buf, "models"); err == nil {
+		t.Errorf("GenerateStructs() expected error without sObject names")
+	}
+}
+
+func TestGeneratePicklistConstantsFromDescribe(t *testing.T) {
+	opportunity := &SObjectDescribe{
+		Name: "Opportunity",
+		Fields: []DescribeField{
+			{Name: "Name", Type: "string"},
+			{Name: "StageName", Type: "picklist", PicklistValues: []DescribePicklistValue{
+				{Label: "Prospecting", Value: "Prospecting", Active: true, DefaultValue: true},
+				{Label: "Closed Won", Value: "Closed Won", Active: true},
+				{Label: "Retired", Value: "Retired"},
+				{Label: "Closed/Won", Value: "Closed/Won", Active: true},
+			}},
+			{Name: "Region__c", Type: "multipicklist", PicklistValues: []DescribePicklistValue{
+				{Label: "Europe, Middle East, Africa", Value: "EMEA", Active: true},
+				{Label: "1", Value: "1", Active: true},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GeneratePicklistConstantsFromDescribe(&buf, "models", opportunity); err != nil {
+		t.Fatalf("GeneratePicklistConstantsFromDescribe() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"// Code generated by go-salesforce. DO NOT EDIT.",
+		"package models",
+		"// OpportunityStageName is a value of the Opportunity.StageName picklist",
+		"type OpportunityStageName string",
+		`OpportunityStageNameProspecting OpportunityStageName = "Prospecting"`,
+		`OpportunityStageNameClosedWon   OpportunityStageName = "Closed Won"`,
+		`OpportunityStageNameClosedWon2  OpportunityStageName = "Closed/Won"`,
+		"var OpportunityStageNameValues = []OpportunityStageName{",
+		"type OpportunityRegion string",
+		`OpportunityRegionEMEA OpportunityRegion = "EMEA" // Europe, Middle East, Africa`,
+		`OpportunityRegion1    OpportunityRegion = "1"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("GeneratePicklistConstantsFromDescribe() missing %q in\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Retired", "OpportunityName"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("GeneratePicklistConstantsFromDescribe() unexpected %q in\n%s", unwanted, got)
+		}
+	}
+
+	noPicklists := &SObjectDescribe{
+		Name:   "Account",
+		Fields: []DescribeField{{Name: "Name", Type: "string"}},
+	}
+	if err := GeneratePicklistConstantsFromDescribe(&buf, "models", noPicklists); err == nil {
+		t.Errorf("GeneratePicklistConstantsFromDescribe() expected error without picklist fields")
+	}
+	if err := GeneratePicklistConstantsFromDescribe(&buf, "bad-name", opportunity); err == nil {
+		t.Errorf("GeneratePicklistConstantsFromDescribe() expected error for invalid package name")
+	}
+}
+
+func TestSalesforce_GeneratePicklistConstants(t *testing.T) {
+	describe := SObjectDescribe{
+		Name: "Case",
+		Fields: []DescribeField{
+			{Name: "Subject", Type: "string"},
+			{Name: "Status", Type: "picklist", PicklistValues: []DescribePicklistValue{
+				{Label: "New", Value: "New", Active: true},
+			}},
+			{Name: "Priority", Type: "picklist", PicklistValues: []DescribePicklistValue{
+				{Label: "High", Value: "High", Active: true},
+			}},
+		},
+	}
+	server, sfAuth := setupTestServer(describe, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	tests := []struct {
+		name     string
+		fields   []string
+		want     []string
+		unwanted []string
+		wantErr  bool
+	}{
+		{
+			name:     "field",
+			fields:   []string{"Case.status"},
+			want:     []string{"type CaseStatus string", `CaseStatusNew CaseStatus = "New"`},
+			unwanted: []string{"CasePriority"},
+		},
+		{
+			name:   "sObject",
+			fields: []string{"Case", "Case.Status"},
+			want:   []string{"type CaseStatus string", "type CasePriority string"},
+		},
+		{name: "not_a_picklist", fields: []string{"Case.Subject"}, wantErr: true},
+		{name: "no_fields", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := sf.GeneratePicklistConstants(&buf, "models", tt.fields...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GeneratePicklistConstants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("GeneratePicklistConstants() missing %q in\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("GeneratePicklistConstants() unexpected %q in\n%s", unwanted, got)
+				}
+			}
+			if strings.Count(got, "type CaseStatus string") > 1 {
+				t.Errorf("GeneratePicklistConstants() generated CaseStatus twice in\n%s", got)
+			}
+		})
+	}
+}
+
+func Test_goIdentifier(t *testing.T) {
+	tests := []struct {
+		apiName string
+		want    string
+	}{
+		{apiName: "Account", want: "Account"},
+		{apiName: "Account_Status__c", want: "AccountStatus"},
+		{apiName: "ns__Score__c", want: "NsScore"},
+		{apiName: "Parent__r", want: "Parent"},
+		{apiName: "Setting__mdt", want: "Setting"},
+		{apiName: "X2nd_Phone__c", want: "X2ndPhone"},
+		{apiName: "__c", want: "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiName, func(t *testing.T) {
+			if got := goIdentifier(tt.apiName); got != tt.want {
+				t.Errorf("goIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 )
 
 type compositeRequest struct {
@@ -33,7 +35,11 @@ type compositeSubRequestResult struct {
 	ReferenceId    string             `json:"referenceId"`
 }
 
-func doCompositeRequest(sf *Salesforce, compReq compositeRequest) (SalesforceResults, error) {
+func doCompositeRequest(
+	sf *Salesforce,
+	compReq compositeRequest,
+	options ...RequestOption,
+) (SalesforceResults, error) {
 	body, jsonErr := json.Marshal(compReq)
 	if jsonErr != nil {
 		return SalesforceResults{}, jsonErr
@@ -42,13 +48,14 @@ func doCompositeRequest(sf *Salesforce, compReq compositeRequest) (SalesforceRes
 		method:   http.MethodPost,
 		uri:      "/composite",
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
+		options:  options,
 	})
 	if httpErr != nil {
 		return SalesforceResults{}, httpErr
 	}
-	results, salesforceErrors := processCompositeResponse(*resp, compReq.AllOrNone)
+	results, salesforceErrors := processCompositeResponse(*resp, compReq)
 	if salesforceErrors != nil {
 		return SalesforceResults{}, salesforceErrors
 	}
@@ -111,7 +118,18 @@ func createCompositeRequestForCollection(
 	}, nil
 }
 
-func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceResults, error) {
+// compositeSubRequestErrors reads subrequests that failed as a whole, such as those halted after
+// another subrequest of an allOrNone request failed, whose body is a list of errors
+type compositeSubRequestErrors struct {
+	CompositeResponse []struct {
+		Body []SalesforceErrorMessage `json:"body"`
+	} `json:"compositeResponse"`
+}
+
+func processCompositeResponse(
+	resp http.Response,
+	compReq compositeRequest,
+) (SalesforceResults, error) {
 	compositeResults := compositeRequestResult{}
 	results := SalesforceResults{}
 
@@ -123,19 +141,62 @@ func processCompositeResponse(resp http.Response, allOrNone bool) (SalesforceRes
 	if jsonError != nil {
 		return SalesforceResults{}, jsonError
 	}
+	subRequestErrors := compositeSubRequestErrors{}
+	if err := json.Unmarshal(responseData, &subRequestErrors); err != nil {
+		subRequestErrors = compositeSubRequestErrors{}
+	}
 
-	for _, subResult := range compositeResults.CompositeResponse {
-		for _, result := range subResult.Body {
+	for i, subResult := range compositeResults.CompositeResponse {
+		body := subResult.Body
+		if i < len(subRequestErrors.CompositeResponse) && i < len(compReq.CompositeRequest) {
+			errs := subRequestErrors.CompositeResponse[i].Body
+			if len(errs) > 0 && errs[0].ErrorCode != "" {
+				body = failedSubRequestResults(compReq.CompositeRequest[i], errs)
+			}
+		}
+		for _, result := range body {
 			if !result.Success {
 				results.HasSalesforceErrors = true
 			}
 		}
-		results.Results = append(results.Results, subResult.Body...)
+		var ids []string
+		if i < len(compReq.CompositeRequest) {
+			ids = subRequestIds(compReq.CompositeRequest[i])
+		}
+		results.Results = append(results.Results, indexResults(body, ids, len(results.Results))...)
 	}
 
 	return results, nil
 }
 
+// subRequestIds returns the Id of each record of a subrequest, from its body or its ids parameter
+func subRequestIds(subReq compositeSubRequest) []string {
+	if len(subReq.Body.Records) > 0 {
+		return recordIds(subReq.Body.Records)
+	}
+	if parsed, err := url.Parse(subReq.Url); err == nil && parsed.Query().Get("ids") != "" {
+		return strings.Split(parsed.Query().Get("ids"), ",")
+	}
+	return nil
+}
+
+// failedSubRequestResults returns a failed result for every record of a subrequest that failed as a whole
+func failedSubRequestResults(
+	subReq compositeSubRequest,
+	errs []SalesforceErrorMessage,
+) []SalesforceResult {
+	ids := subRequestIds(subReq)
+	if len(ids) == 0 {
+		ids = []string{""}
+	}
+
+	results := make([]SalesforceResult, len(ids))
+	for i, id := range ids {
+		results[i] = SalesforceResult{Id: id, Errors: errs, Success: false}
+	}
+	return results
+}
+
 func doInsertComposite(
 	sf *Salesforce,
 	sObjectName string,
@@ -147,6 +208,12 @@ func doInsertComposite(
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, insertOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, insertOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 
 	for i := range recordMap {
 		delete(recordMap[i], "Id")
@@ -164,12 +231,16 @@ func doInsertComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(
+		sf,
+		compReq,
+		sObjectDefaultOptions(sf.config, sObjectName)...,
+	)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
 
-	return results, nil
+	return results, runAfterHook(sf, insertOperation, sObjectName, recordMap...)
 }
 
 func doUpdateComposite(
@@ -183,6 +254,12 @@ func doUpdateComposite(
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, updateOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, updateOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 
 	for i := range recordMap {
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
@@ -203,12 +280,16 @@ func doUpdateComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(
+		sf,
+		compReq,
+		sObjectDefaultOptions(sf.config, sObjectName)...,
+	)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
 
-	return results, nil
+	return results, runAfterHook(sf, updateOperation, sObjectName, recordMap...)
 }
 
 func doUpsertComposite(
@@ -223,6 +304,12 @@ func doUpsertComposite(
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, upsertOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, upsertOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 	err = checkForExternalIdInList(sObjectName, fieldName, recordMap)
 	if err != nil {
 		return SalesforceResults{}, err
@@ -239,12 +326,16 @@ func doUpsertComposite(
 	if compositeErr != nil {
 		return SalesforceResults{}, compositeErr
 	}
-	results, compositeReqErr := doCompositeRequest(sf, compReq)
+	results, compositeReqErr := doCompositeRequest(
+		sf,
+		compReq,
+		sObjectDefaultOptions(sf.config, sObjectName)...,
+	)
 	if compositeReqErr != nil {
 		return SalesforceResults{}, compositeReqErr
 	}
 
-	return results, nil
+	return results, runAfterHook(sf, upsertOperation, sObjectName, recordMap...)
 }
 
 func doDeleteComposite(
@@ -258,6 +349,10 @@ func doDeleteComposite(
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, deleteOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	deleted := recordMap
 
 	var subReqs []compositeSubRequest
 	batchNumber := 0
@@ -305,5 +400,5 @@ func doDeleteComposite(
 		return SalesforceResults{}, compositeReqErr
 	}
 
-	return results, nil
+	return results, runAfterHook(sf, deleteOperation, sObjectName, deleted...)
 }
@@ -217,9 +217,43 @@ func Test_processCompositeResponse(t *testing.T) {
 		Body:       bodyNoError,
 	}
 
+	haltedResult := compositeSubRequestResult{
+		HttpHeaders:    map[string]string{},
+		HttpStatusCode: http.StatusBadRequest,
+		ReferenceId:    "refObj1",
+	}
+	haltedJson, _ := json.Marshal(haltedResult)
+	haltedJson = bytes.Replace(
+		haltedJson,
+		[]byte(`"body":null`),
+		[]byte(
+			`"body":[{"errorCode":"PROCESSING_HALTED","message":"The transaction was rolled back"}]`,
+		),
+		1,
+	)
+	failedJson, _ := json.Marshal(compSubResults[0])
+	httpRespHalted := http.Response{
+		Status:     fmt.Sprint(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Body: io.NopCloser(bytes.NewReader([]byte(
+			`{"compositeResponse":[` + string(failedJson) + `,` + string(haltedJson) + `]}`,
+		))),
+	}
+	haltedReq := compositeRequest{
+		AllOrNone: true,
+		CompositeRequest: []compositeSubRequest{
+			{Body: sObjectCollection{Records: []map[string]any{{"Id": "12345"}}}},
+			{Body: sObjectCollection{Records: []map[string]any{{"Id": "123"}, {"Id": "456"}}}},
+		},
+	}
+	haltedErrors := []SalesforceErrorMessage{{
+		Message:   "The transaction was rolled back",
+		ErrorCode: ErrorCodeProcessingHalted,
+	}}
+
 	type args struct {
-		resp      http.Response
-		allOrNone bool
+		resp    http.Response
+		compReq compositeRequest
 	}
 	tests := []struct {
 		name    string
@@ -227,6 +261,22 @@ func Test_processCompositeResponse(t *testing.T) {
 		want    SalesforceResults
 		wantErr bool
 	}{
+		{
+			name: "process_halted_subrequest",
+			args: args{
+				resp:    httpRespHalted,
+				compReq: haltedReq,
+			},
+			want: SalesforceResults{
+				Results: []SalesforceResult{
+					exampleError[0],
+					{Id: "123", Errors: haltedErrors, Index: 1},
+					{Id: "456", Errors: haltedErrors, Index: 2},
+				},
+				HasSalesforceErrors: true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "process_500_error",
 			args: args{
@@ -252,7 +302,7 @@ func Test_processCompositeResponse(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := processCompositeResponse(tt.args.resp, tt.args.allOrNone)
+			got, err := processCompositeResponse(tt.args.resp, tt.args.compReq)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("processCompositeResponse() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -352,7 +402,14 @@ func Test_doCompositeRequest(t *testing.T) {
 				sf:      buildSalesforceStruct(&sfErrorSfAuth),
 				compReq: compReq,
 			},
-			want:    sfResultsFail,
+			want: SalesforceResults{
+				Results: []SalesforceResult{{
+					Id:      "1234",
+					Success: false,
+					Errors:  sfResultsFail.Results[0].Errors,
+				}},
+				HasSalesforceErrors: true,
+			},
 			wantErr: false,
 		},
 	}
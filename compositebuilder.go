@@ -0,0 +1,229 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+const compositeSubRequestsMax = 25
+
+var compositeReferenceIdPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// CompositeBuilder builds a request of up to 25 subrequests sent together with the Composite API.
+// Subrequests can use the results of earlier ones with references such as @{newAccount.id}. Each
+// subrequest can register an output that its response body is decoded into, so results are read as
+// typed values instead of raw JSON.
+type CompositeBuilder struct {
+	sf          *Salesforce
+	allOrNone   bool
+	subRequests []compositeBuilderSubRequest
+	outputs     []any
+	executed    bool
+	responses   map[string]CompositeSubResponse // responses of the executed subrequests by reference id
+	reverts     map[string]map[string]any       // values that Rollback restores updated records to
+	rolledBack  bool
+}
+
+type compositeBuilderSubRequest struct {
+	Body        any    `json:"body,omitempty"`
+	Method      string `json:"method"`
+	Url         string `json:"url"`
+	ReferenceId string `json:"referenceId"`
+}
+
+type compositeBuilderRequest struct {
+	AllOrNone        bool                         `json:"allOrNone"`
+	CompositeRequest []compositeBuilderSubRequest `json:"compositeRequest"`
+}
+
+type compositeBuilderResponse struct {
+	CompositeResponse []struct {
+		Body           json.RawMessage   `json:"body"`
+		HttpHeaders    map[string]string `json:"httpHeaders"`
+		HttpStatusCode int               `json:"httpStatusCode"`
+		ReferenceId    string            `json:"referenceId"`
+	} `json:"compositeResponse"`
+}
+
+// CompositeSubResponse is the response of a subrequest of a CompositeBuilder
+type CompositeSubResponse struct {
+	ReferenceId    string
+	HttpStatusCode int
+	HttpHeaders    map[string]string
+	Body           json.RawMessage          // response body, also decoded into the output of the subrequest
+	Errors         []SalesforceErrorMessage // errors of a failed subrequest
+}
+
+// Success returns true if the subrequest succeeded
+func (r CompositeSubResponse) Success() bool {
+	return r.HttpStatusCode >= 200 && r.HttpStatusCode < 300
+}
+
+// NewCompositeBuilder returns an empty CompositeBuilder. If allOrNone is true, every subrequest is
+// rolled back when one of them fails.
+func (sf *Salesforce) NewCompositeBuilder(allOrNone bool) *CompositeBuilder {
+	return &CompositeBuilder{sf: sf, allOrNone: allOrNone}
+}
+
+// Add adds a subrequest. uri is relative to the versioned data API, such as "/sobjects/Account". Structs
+// in body are converted to records honoring salesforce struct tags, other bodies are sent as JSON. If
+// output is not nil, it must be a pointer that the response body is decoded into when the subrequest
+// succeeds, honoring salesforce struct tags. Query results are decoded into pointers to slices as their
+// records.
+func (b *CompositeBuilder) Add(
+	method string,
+	uri string,
+	referenceId string,
+	body any,
+	output any,
+) error {
+	if b.executed {
+		return errors.New("composite request has already been executed")
+	}
+	if len(b.subRequests) >= compositeSubRequestsMax {
+		return fmt.Errorf(
+			"a composite request can have at most %d subrequests",
+			compositeSubRequestsMax,
+		)
+	}
+	if method == "" || uri == "" {
+		return errors.New("method and uri are required")
+	}
+	if !compositeReferenceIdPattern.MatchString(referenceId) {
+		return fmt.Errorf(
+			"invalid reference id %q, it must start with a letter and contain only letters, numbers, and underscores",
+			referenceId,
+		)
+	}
+	for _, subReq := range b.subRequests {
+		if subReq.ReferenceId == referenceId {
+			return fmt.Errorf("duplicate reference id: %s", referenceId)
+		}
+	}
+	if output != nil && reflect.ValueOf(output).Kind() != reflect.Pointer {
+		return errors.New("output must be a pointer")
+	}
+	if reflect.Indirect(reflect.ValueOf(body)).Kind() == reflect.Struct {
+		record, err := convertToMap(body)
+		if err != nil {
+			return err
+		}
+		body = record
+	}
+
+	b.subRequests = append(b.subRequests, compositeBuilderSubRequest{
+		Body:        body,
+		Method:      method,
+		Url:         "/services/data/" + b.sf.config.apiVersion + uri,
+		ReferenceId: referenceId,
+	})
+	b.outputs = append(b.outputs, output)
+	return nil
+}
+
+// Get adds a GET subrequest, such as "/sobjects/Account/@{newAccount.id}"
+func (b *CompositeBuilder) Get(referenceId string, uri string, output any) error {
+	return b.Add(http.MethodGet, uri, referenceId, nil, output)
+}
+
+// Query adds a subrequest that runs a SOQL query, whose records are decoded into output
+func (b *CompositeBuilder) Query(referenceId string, query string, output any) error {
+	return b.Add(http.MethodGet, "/query/?q="+url.QueryEscape(query), referenceId, nil, output)
+}
+
+// Execute sends the subrequests and decodes the body of each successful subrequest into its output.
+// Responses are in the order the subrequests were added. Failed subrequests report their errors in the
+// Errors of their response rather than as an error. If an output cannot be decoded, the responses are
+// returned with an error.
+func (b *CompositeBuilder) Execute() ([]CompositeSubResponse, error) {
+	authErr := validateAuth(*b.sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if b.executed {
+		return nil, errors.New("composite request has already been executed")
+	}
+	if len(b.subRequests) == 0 {
+		return nil, errors.New("composite request has no subrequests")
+	}
+
+	body, err := json.Marshal(compositeBuilderRequest{
+		AllOrNone:        b.allOrNone,
+		CompositeRequest: b.subRequests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(b.sf.auth, b.sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/composite",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: b.sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.executed = true
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	compositeResp := compositeBuilderResponse{}
+	if err := json.Unmarshal(responseData, &compositeResp); err != nil {
+		return nil, err
+	}
+
+	b.responses = map[string]CompositeSubResponse{}
+	outputs := map[string]any{}
+	for i, subReq := range b.subRequests {
+		outputs[subReq.ReferenceId] = b.outputs[i]
+	}
+	var decodeErrs []error
+	responses := make([]CompositeSubResponse, len(compositeResp.CompositeResponse))
+	for i, subResp := range compositeResp.CompositeResponse {
+		response := CompositeSubResponse{
+			ReferenceId:    subResp.ReferenceId,
+			HttpStatusCode: subResp.HttpStatusCode,
+			HttpHeaders:    subResp.HttpHeaders,
+			Body:           subResp.Body,
+		}
+		if !response.Success() {
+			_ = json.Unmarshal(subResp.Body, &response.Errors)
+		} else if output := outputs[subResp.ReferenceId]; output != nil && len(subResp.Body) > 0 {
+			if err := decodeCompositeOutput(subResp.Body, output); err != nil {
+				decodeErrs = append(
+					decodeErrs,
+					fmt.Errorf("decoding subrequest %s: %w", subResp.ReferenceId, err),
+				)
+			}
+		}
+		responses[i] = response
+		b.responses[response.ReferenceId] = response
+	}
+	return responses, errors.Join(decodeErrs...)
+}
+
+// decodeCompositeOutput decodes a subrequest response body into output, using the records of query
+// results when output is a pointer to a slice
+func decodeCompositeOutput(body json.RawMessage, output any) error {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return err
+	}
+	if reflect.ValueOf(output).Elem().Kind() == reflect.Slice {
+		if queryResult, ok := value.(map[string]any); ok {
+			if records, ok := queryResult["records"]; ok {
+				value = records
+			}
+		}
+	}
+	return mapstructureDecode(value, output)
+}
@@ -0,0 +1,160 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCompositeBuilder_Add(t *testing.T) {
+	type Account struct {
+		Name string `salesforce:"Name"`
+	}
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example.com", AccessToken: "1234"},
+	)
+	builder := sf.NewCompositeBuilder(false)
+
+	tests := []struct {
+		name        string
+		method      string
+		uri         string
+		referenceId string
+		body        any
+		output      any
+		wantErr     bool
+	}{
+		{
+			name:        "struct_body",
+			method:      http.MethodPost,
+			uri:         "/sobjects/Account",
+			referenceId: "newAccount",
+			body:        Account{Name: "Acme"},
+			output:      &SalesforceResult{},
+		},
+		{
+			name:        "duplicate_reference_id",
+			method:      http.MethodGet,
+			uri:         "/sobjects/Account/@{newAccount.id}",
+			referenceId: "newAccount",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid_reference_id",
+			method:      http.MethodGet,
+			uri:         "/limits",
+			referenceId: "1st",
+			wantErr:     true,
+		},
+		{
+			name:        "output_not_pointer",
+			method:      http.MethodGet,
+			uri:         "/limits",
+			referenceId: "limits",
+			output:      Account{},
+			wantErr:     true,
+		},
+		{
+			name:        "missing_uri",
+			method:      http.MethodGet,
+			referenceId: "missing",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.Add(tt.method, tt.uri, tt.referenceId, tt.body, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	want := compositeBuilderSubRequest{
+		Body:        map[string]any{"Name": "Acme"},
+		Method:      http.MethodPost,
+		Url:         "/services/data/" + apiVersion + "/sobjects/Account",
+		ReferenceId: "newAccount",
+	}
+	if len(builder.subRequests) != 1 || !reflect.DeepEqual(builder.subRequests[0], want) {
+		t.Errorf("subrequests = %+v, want [%+v]", builder.subRequests, want)
+	}
+}
+
+func TestCompositeBuilder_Execute(t *testing.T) {
+	type Account struct {
+		Id   string
+		Name string
+	}
+	type Contact struct {
+		Id       string
+		LastName string
+		Account  Account
+	}
+	var sent compositeBuilderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(`{"compositeResponse":[
+			{"body":{"id":"001A","success":true,"errors":[]},"httpStatusCode":201,"referenceId":"newAccount"},
+			{"body":{"attributes":{"type":"Account"},"Id":"001A","Name":"Acme"},"httpStatusCode":200,"referenceId":"account"},
+			{"body":{"totalSize":1,"done":true,"records":[{"attributes":{"type":"Contact"},"Id":"003A","LastName":"Stark","Account":{"Id":"001A","Name":"Acme"}}]},"httpStatusCode":200,"referenceId":"contacts"},
+			{"body":[{"errorCode":"NOT_FOUND","message":"The requested resource does not exist"}],"httpStatusCode":404,"referenceId":"missing"}
+		]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	builder := sf.NewCompositeBuilder(false)
+	result := SalesforceResult{}
+	account := Account{}
+	contacts := []Contact{}
+	missing := Account{}
+	for _, err := range []error{
+		builder.Add(http.MethodPost, "/sobjects/Account", "newAccount", map[string]any{"Name": "Acme"}, &result),
+		builder.Get("account", "/sobjects/Account/@{newAccount.id}", &account),
+		builder.Query("contacts", "SELECT Id, LastName, Account.Id, Account.Name FROM Contact", &contacts),
+		builder.Get("missing", "/sobjects/Account/001B", &missing),
+	} {
+		if err != nil {
+			t.Fatalf("adding subrequest error = %v", err)
+		}
+	}
+
+	responses, err := builder.Execute()
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(sent.CompositeRequest) != 4 ||
+		sent.CompositeRequest[1].Url != "/services/data/"+apiVersion+"/sobjects/Account/@{newAccount.id}" {
+		t.Errorf("sent subrequests = %+v", sent.CompositeRequest)
+	}
+	if !result.Success || result.Id != "001A" {
+		t.Errorf("result = %+v", result)
+	}
+	if want := (Account{Id: "001A", Name: "Acme"}); account != want {
+		t.Errorf("account = %+v, want %+v", account, want)
+	}
+	wantContacts := []Contact{
+		{Id: "003A", LastName: "Stark", Account: Account{Id: "001A", Name: "Acme"}},
+	}
+	if !reflect.DeepEqual(contacts, wantContacts) {
+		t.Errorf("contacts = %+v, want %+v", contacts, wantContacts)
+	}
+	if missing != (Account{}) {
+		t.Errorf("output of a failed subrequest = %+v, want it unchanged", missing)
+	}
+	if len(responses) != 4 || responses[3].Success() || len(responses[3].Errors) != 1 ||
+		responses[3].Errors[0].ErrorCode != "NOT_FOUND" {
+		t.Errorf("responses = %+v", responses)
+	}
+
+	if _, err := builder.Execute(); err == nil {
+		t.Error("Execute() expected an error when executed twice")
+	}
+	if _, err := sf.NewCompositeBuilder(true).Execute(); err == nil {
+		t.Error("Execute() expected an error without subrequests")
+	}
+}
@@ -0,0 +1,276 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var compositeIdReferencePattern = regexp.MustCompile(`@\{([A-Za-z][A-Za-z0-9_]*)\.id\}`)
+
+// CompositeSavepoint marks a position in a CompositeBuilder, see Savepoint and RollbackTo
+type CompositeSavepoint struct {
+	index int
+}
+
+// RolledBackRecord is a record written by a subrequest of a CompositeBuilder that Rollback compensated
+// or could not compensate
+type RolledBackRecord struct {
+	ReferenceId string // reference id of the subrequest that wrote the record
+	SObjectName string
+	Id          string                   // empty if the record could not be identified
+	Errors      []SalesforceErrorMessage // errors of a failed rollback
+}
+
+// RollbackReport lists the records of successful subrequests that Rollback compensated
+type RollbackReport struct {
+	Deleted  []RolledBackRecord // records inserted by the subrequests, deleted
+	Reverted []RolledBackRecord // records updated by the subrequests, restored to the values set with SetRevert
+	Failed   []RolledBackRecord // records whose delete or restore failed, with the errors of Salesforce
+	// Skipped are records that cannot be compensated: deleted records, updated records without revert
+	// values, and records whose ids reference results that are not ids
+	Skipped []RolledBackRecord
+}
+
+// Complete returns true if every record written by the subrequests was rolled back
+func (r RollbackReport) Complete() bool {
+	return len(r.Failed) == 0 && len(r.Skipped) == 0
+}
+
+type compositeRollbackStep struct {
+	record  RolledBackRecord
+	request compositeBuilderSubRequest
+	delete  bool
+}
+
+// Savepoint returns the position after the subrequests added so far, so that RollbackTo compensates
+// only the subrequests added after it
+func (b *CompositeBuilder) Savepoint() CompositeSavepoint {
+	return CompositeSavepoint{index: len(b.subRequests)}
+}
+
+// SetRevert sets the field values that Rollback restores the record of an update or upsert subrequest
+// to, usually the values the record had before. Structs are converted to records honoring salesforce
+// struct tags.
+func (b *CompositeBuilder) SetRevert(referenceId string, previous any) error {
+	found := false
+	for _, subReq := range b.subRequests {
+		if subReq.ReferenceId == referenceId {
+			found = subReq.Method == http.MethodPatch
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no update or upsert subrequest with reference id: %s", referenceId)
+	}
+	record, err := convertToMap(previous)
+	if err != nil {
+		return err
+	}
+	delete(record, "Id")
+	if b.reverts == nil {
+		b.reverts = map[string]map[string]any{}
+	}
+	b.reverts[referenceId] = record
+	return nil
+}
+
+// Rollback compensates the successful subrequests of an executed composite request that was not all or
+// none and partially succeeded, see RollbackTo
+func (b *CompositeBuilder) Rollback() (RollbackReport, error) {
+	return b.RollbackTo(CompositeSavepoint{})
+}
+
+// RollbackTo compensates the successful subrequests added after the savepoint, in reverse order, with a
+// single composite request: inserted records are deleted, and updated records are restored to the
+// values set with SetRevert. Salesforce has no savepoints across requests, so records that other
+// processes changed in the meantime are overwritten. The report lists what was rolled back, and
+// failures of individual records are reported rather than returned as an error. A rollback whose
+// request fails can be retried.
+func (b *CompositeBuilder) RollbackTo(savepoint CompositeSavepoint) (RollbackReport, error) {
+	authErr := validateAuth(*b.sf)
+	if authErr != nil {
+		return RollbackReport{}, authErr
+	}
+	if !b.executed {
+		return RollbackReport{}, errors.New("composite request has not been executed")
+	}
+	if b.rolledBack {
+		return RollbackReport{}, errors.New("composite request has already been rolled back")
+	}
+	if savepoint.index < 0 || savepoint.index > len(b.subRequests) {
+		return RollbackReport{}, errors.New("savepoint is not of this composite request")
+	}
+
+	report := RollbackReport{}
+	steps := []compositeRollbackStep{}
+	for i := len(b.subRequests) - 1; i >= savepoint.index; i-- {
+		step, ok := b.rollbackStep(b.subRequests[i])
+		if !ok {
+			continue
+		}
+		if step.request.Method == "" {
+			report.Skipped = append(report.Skipped, step.record)
+			continue
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		b.rolledBack = true
+		return report, nil
+	}
+
+	subRequests := make([]compositeBuilderSubRequest, len(steps))
+	for i, step := range steps {
+		subRequests[i] = step.request
+	}
+	body, err := json.Marshal(compositeBuilderRequest{CompositeRequest: subRequests})
+	if err != nil {
+		return report, err
+	}
+	resp, err := doRequest(b.sf.auth, b.sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/composite",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: b.sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return report, err
+	}
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return report, err
+	}
+	compositeResp := compositeBuilderResponse{}
+	if err := json.Unmarshal(responseData, &compositeResp); err != nil {
+		return report, err
+	}
+	// only a rollback whose response was received is final, others can be retried
+	b.rolledBack = true
+
+	statusCodes := map[string]int{}
+	bodies := map[string]json.RawMessage{}
+	for _, subResp := range compositeResp.CompositeResponse {
+		statusCodes[subResp.ReferenceId] = subResp.HttpStatusCode
+		bodies[subResp.ReferenceId] = subResp.Body
+	}
+	for _, step := range steps {
+		statusCode := statusCodes[step.request.ReferenceId]
+		switch {
+		case statusCode >= 200 && statusCode < 300 && step.delete:
+			report.Deleted = append(report.Deleted, step.record)
+		case statusCode >= 200 && statusCode < 300:
+			report.Reverted = append(report.Reverted, step.record)
+		default:
+			_ = json.Unmarshal(bodies[step.request.ReferenceId], &step.record.Errors)
+			report.Failed = append(report.Failed, step.record)
+		}
+	}
+	return report, nil
+}
+
+// rollbackStep returns the subrequest that compensates a subrequest, or a step without a request if the
+// record it wrote cannot be compensated, and false if the subrequest failed or did not write a record
+func (b *CompositeBuilder) rollbackStep(
+	subReq compositeBuilderSubRequest,
+) (compositeRollbackStep, bool) {
+	response, ok := b.responses[subReq.ReferenceId]
+	if !ok || !response.Success() {
+		return compositeRollbackStep{}, false
+	}
+	path, _, _ := strings.Cut(subReq.Url, "?")
+	sObjectPath, ok := strings.CutPrefix(
+		path,
+		"/services/data/"+b.sf.config.apiVersion+"/sobjects/",
+	)
+	if !ok {
+		return compositeRollbackStep{}, false
+	}
+	segments := strings.Split(strings.Trim(sObjectPath, "/"), "/")
+	record := RolledBackRecord{ReferenceId: subReq.ReferenceId, SObjectName: segments[0]}
+	var result struct {
+		Id string `json:"id"`
+	}
+	_ = json.Unmarshal(response.Body, &result)
+
+	created := false
+	switch {
+	case subReq.Method == http.MethodPost && len(segments) == 1:
+		created = true
+		record.Id = result.Id
+	case subReq.Method == http.MethodPatch && len(segments) == 3:
+		// upserts respond with 201 when they create the record
+		created = response.HttpStatusCode == http.StatusCreated
+		record.Id = result.Id
+	case subReq.Method == http.MethodPatch && len(segments) == 2:
+		id, err := b.resolveIdReferences(segments[1])
+		if err != nil {
+			return compositeRollbackStep{record: record}, true
+		}
+		record.Id = id
+	case subReq.Method == http.MethodDelete && len(segments) == 2:
+		record.Id, _ = b.resolveIdReferences(segments[1])
+		return compositeRollbackStep{record: record}, true
+	default:
+		return compositeRollbackStep{}, false
+	}
+	if record.Id == "" {
+		return compositeRollbackStep{record: record}, true
+	}
+
+	uri := "/services/data/" + b.sf.config.apiVersion + "/sobjects/" + record.SObjectName + "/" + record.Id
+	referenceId := "rollback_" + subReq.ReferenceId
+	if created {
+		return compositeRollbackStep{
+			record: record,
+			request: compositeBuilderSubRequest{
+				Method:      http.MethodDelete,
+				Url:         uri,
+				ReferenceId: referenceId,
+			},
+			delete: true,
+		}, true
+	}
+	previous, ok := b.reverts[subReq.ReferenceId]
+	if !ok {
+		return compositeRollbackStep{record: record}, true
+	}
+	return compositeRollbackStep{
+		record: record,
+		request: compositeBuilderSubRequest{
+			Body:        previous,
+			Method:      http.MethodPatch,
+			Url:         uri,
+			ReferenceId: referenceId,
+		},
+	}, true
+}
+
+// resolveIdReferences replaces references such as @{newAccount.id} with the id in the response of the
+// referenced subrequest
+func (b *CompositeBuilder) resolveIdReferences(value string) (string, error) {
+	var resolveErr error
+	resolved := compositeIdReferencePattern.ReplaceAllStringFunc(
+		value,
+		func(reference string) string {
+			referenceId := compositeIdReferencePattern.FindStringSubmatch(reference)[1]
+			var result struct {
+				Id string `json:"id"`
+			}
+			_ = json.Unmarshal(b.responses[referenceId].Body, &result)
+			if result.Id == "" {
+				resolveErr = fmt.Errorf("cannot resolve reference: %s", reference)
+			}
+			return result.Id
+		},
+	)
+	if resolveErr == nil && strings.Contains(resolved, "@{") {
+		resolveErr = fmt.Errorf("cannot resolve reference: %s", value)
+	}
+	return resolved, resolveErr
+}
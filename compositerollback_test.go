@@ -0,0 +1,321 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// compositeRollbackServer responds to the subrequests of composite requests with the canned status code
+// and body of their reference id, and records the subrequests of each composite request
+func compositeRollbackServer(
+	responses map[string]compositeBuilderSubResponse,
+	requests *[][]compositeBuilderSubRequest,
+) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body compositeBuilderRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*requests = append(*requests, body.CompositeRequest)
+		compositeResp := map[string][]compositeBuilderSubResponse{"compositeResponse": {}}
+		for _, subReq := range body.CompositeRequest {
+			response := responses[subReq.ReferenceId]
+			response.ReferenceId = subReq.ReferenceId
+			compositeResp["compositeResponse"] = append(
+				compositeResp["compositeResponse"],
+				response,
+			)
+		}
+		data, _ := json.Marshal(compositeResp)
+		_, _ = w.Write(data)
+	}))
+}
+
+type compositeBuilderSubResponse struct {
+	Body           json.RawMessage `json:"body"`
+	HttpStatusCode int             `json:"httpStatusCode"`
+	ReferenceId    string          `json:"referenceId"`
+}
+
+func TestCompositeBuilder_Rollback(t *testing.T) {
+	requests := [][]compositeBuilderSubRequest{}
+	server := compositeRollbackServer(map[string]compositeBuilderSubResponse{
+		"newAccount": {
+			HttpStatusCode: 201,
+			Body:           json.RawMessage(`{"id": "001A", "success": true}`),
+		},
+		"newContact": {
+			HttpStatusCode: 201,
+			Body:           json.RawMessage(`{"id": "003C", "success": true}`),
+		},
+		"closeOpp": {HttpStatusCode: 204},
+		"newTask": {
+			HttpStatusCode: 400,
+			Body: json.RawMessage(
+				`[{"errorCode": "REQUIRED_FIELD_MISSING", "message": "Required fields are missing"}]`,
+			),
+		},
+		"oldLead": {HttpStatusCode: 204},
+		"upsertContact": {
+			HttpStatusCode: 201,
+			Body:           json.RawMessage(`{"id": "003U", "success": true, "created": true}`),
+		},
+		"accountQuery": {
+			HttpStatusCode: 200,
+			Body:           json.RawMessage(`{"totalSize": 0, "done": true, "records": []}`),
+		},
+		"rollback_newAccount": {
+			HttpStatusCode: 400,
+			Body: json.RawMessage(
+				`[{"errorCode": "DELETE_FAILED", "message": "Your attempt to delete Acme could not be completed"}]`,
+			),
+		},
+		"rollback_newContact":    {HttpStatusCode: 204},
+		"rollback_closeOpp":      {HttpStatusCode: 204},
+		"rollback_upsertContact": {HttpStatusCode: 204},
+	}, &requests)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	builder := sf.NewCompositeBuilder(false)
+	adds := []error{
+		builder.Add(
+			http.MethodPost,
+			"/sobjects/Account",
+			"newAccount",
+			map[string]any{"Name": "Acme"},
+			nil,
+		),
+		builder.Add(
+			http.MethodPost,
+			"/sobjects/Contact",
+			"newContact",
+			map[string]any{"LastName": "Doe", "AccountId": "@{newAccount.id}"},
+			nil,
+		),
+		builder.Add(
+			http.MethodPatch,
+			"/sobjects/Opportunity/006X",
+			"closeOpp",
+			map[string]any{"StageName": "Closed Won"},
+			nil,
+		),
+		builder.Add(http.MethodPost, "/sobjects/Task", "newTask", map[string]any{}, nil),
+		builder.Add(http.MethodDelete, "/sobjects/Lead/00QL", "oldLead", nil, nil),
+		builder.Add(
+			http.MethodPatch,
+			"/sobjects/Contact/External_Id__c/1",
+			"upsertContact",
+			map[string]any{"LastName": "Smith"},
+			nil,
+		),
+		builder.Query("accountQuery", "SELECT Id FROM Account", nil),
+		builder.SetRevert("closeOpp", map[string]any{"Id": "006X", "StageName": "Prospecting"}),
+	}
+	for _, err := range adds {
+		if err != nil {
+			t.Fatalf("building composite request error = %v", err)
+		}
+	}
+	if _, err := builder.Rollback(); err == nil {
+		t.Error("Rollback() before Execute() error = nil, want an error")
+	}
+	if _, err := builder.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	report, err := builder.Rollback()
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	uri := "/services/data/" + apiVersion + "/sobjects/"
+	wantRequests := []compositeBuilderSubRequest{
+		{
+			Method:      http.MethodDelete,
+			Url:         uri + "Contact/003U",
+			ReferenceId: "rollback_upsertContact",
+		},
+		{
+			Body:        map[string]any{"StageName": "Prospecting"},
+			Method:      http.MethodPatch,
+			Url:         uri + "Opportunity/006X",
+			ReferenceId: "rollback_closeOpp",
+		},
+		{Method: http.MethodDelete, Url: uri + "Contact/003C", ReferenceId: "rollback_newContact"},
+		{Method: http.MethodDelete, Url: uri + "Account/001A", ReferenceId: "rollback_newAccount"},
+	}
+	if len(requests) != 2 || !reflect.DeepEqual(requests[1], wantRequests) {
+		t.Errorf("rollback subrequests = %+v, want %+v", requests[len(requests)-1], wantRequests)
+	}
+	want := RollbackReport{
+		Deleted: []RolledBackRecord{
+			{ReferenceId: "upsertContact", SObjectName: "Contact", Id: "003U"},
+			{ReferenceId: "newContact", SObjectName: "Contact", Id: "003C"},
+		},
+		Reverted: []RolledBackRecord{
+			{ReferenceId: "closeOpp", SObjectName: "Opportunity", Id: "006X"},
+		},
+		Failed: []RolledBackRecord{
+			{
+				ReferenceId: "newAccount",
+				SObjectName: "Account",
+				Id:          "001A",
+				Errors: []SalesforceErrorMessage{{
+					ErrorCode: "DELETE_FAILED",
+					Message:   "Your attempt to delete Acme could not be completed",
+				}},
+			},
+		},
+		Skipped: []RolledBackRecord{
+			{ReferenceId: "oldLead", SObjectName: "Lead", Id: "00QL"},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Rollback() = %+v, want %+v", report, want)
+	}
+	if report.Complete() {
+		t.Error("Complete() = true, want false")
+	}
+	if _, err := builder.Rollback(); err == nil {
+		t.Error("Rollback() twice error = nil, want an error")
+	}
+}
+
+func TestCompositeBuilder_RollbackTo(t *testing.T) {
+	requests := [][]compositeBuilderSubRequest{}
+	server := compositeRollbackServer(map[string]compositeBuilderSubResponse{
+		"newAccount": {
+			HttpStatusCode: 201,
+			Body:           json.RawMessage(`{"id": "001A", "success": true}`),
+		},
+		"newContact": {
+			HttpStatusCode: 201,
+			Body:           json.RawMessage(`{"id": "003C", "success": true}`),
+		},
+		"rollback_newContact": {HttpStatusCode: 204},
+	}, &requests)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	builder := sf.NewCompositeBuilder(false)
+	if err := builder.Add(http.MethodPost, "/sobjects/Account", "newAccount", map[string]any{}, nil); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	savepoint := builder.Savepoint()
+	if err := builder.Add(http.MethodPost, "/sobjects/Contact", "newContact", map[string]any{}, nil); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := builder.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	report, err := builder.RollbackTo(savepoint)
+	if err != nil {
+		t.Fatalf("RollbackTo() error = %v", err)
+	}
+	want := RollbackReport{
+		Deleted: []RolledBackRecord{
+			{ReferenceId: "newContact", SObjectName: "Contact", Id: "003C"},
+		},
+	}
+	if !reflect.DeepEqual(report, want) || !report.Complete() {
+		t.Errorf("RollbackTo() = %+v, want %+v", report, want)
+	}
+}
+
+func TestCompositeBuilder_SetRevert(t *testing.T) {
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example.com", AccessToken: "1234"},
+	)
+	builder := sf.NewCompositeBuilder(false)
+	_ = builder.Add(http.MethodPost, "/sobjects/Account", "newAccount", map[string]any{}, nil)
+	_ = builder.Add(
+		http.MethodPatch,
+		"/sobjects/Account/001A",
+		"updateAccount",
+		map[string]any{},
+		nil,
+	)
+
+	tests := []struct {
+		name        string
+		referenceId string
+		previous    any
+		wantErr     bool
+	}{
+		{
+			name:        "update",
+			referenceId: "updateAccount",
+			previous:    map[string]any{"Name": "Acme"},
+		},
+		{
+			name:        "insert",
+			referenceId: "newAccount",
+			previous:    map[string]any{"Name": "Acme"},
+			wantErr:     true,
+		},
+		{
+			name:        "unknown_reference_id",
+			referenceId: "missing",
+			previous:    map[string]any{"Name": "Acme"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.SetRevert(tt.referenceId, tt.previous)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetRevert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompositeBuilder_Rollback_retry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			_, _ = w.Write(
+				[]byte(`{"compositeResponse": [{"body": {"id": "001A", "success": true}, ` +
+					`"httpStatusCode": 201, "referenceId": "newAccount"}]}`),
+			)
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write(
+				[]byte(`[{"errorCode": "UNKNOWN_EXCEPTION", "message": "unexpected error"}]`),
+			)
+		default:
+			_, _ = w.Write([]byte(`{"compositeResponse": [{"httpStatusCode": 204, ` +
+				`"referenceId": "rollback_newAccount"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	builder := sf.NewCompositeBuilder(false)
+	if err := builder.Add(http.MethodPost, "/sobjects/Account", "newAccount", map[string]any{}, nil); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := builder.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if _, err := builder.Rollback(); err == nil {
+		t.Fatal("Rollback() error = nil, want the error of the failed request")
+	}
+	report, err := builder.Rollback()
+	if err != nil {
+		t.Fatalf("Rollback() retry error = %v", err)
+	}
+	want := RollbackReport{
+		Deleted: []RolledBackRecord{
+			{ReferenceId: "newAccount", SObjectName: "Account", Id: "001A"},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Rollback() retry = %+v, want %+v", report, want)
+	}
+}
@@ -2,6 +2,7 @@ package salesforce
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,12 +15,57 @@ type configuration struct {
 	apiVersion                   string
 	batchSizeMax                 int
 	bulkBatchSizeMax             int
-	bulkPollTimeout              time.Duration     // timeout for waiting on bulk job completion
-	httpClient                   *http.Client      // HTTP client (created internally)
-	roundTripper                 http.RoundTripper // Custom round tripper
-	shouldValidateAuthentication bool              // Validate session on client creation
-	httpTimeout                  time.Duration     // HTTP client timeout
-	bulkQueryMaxRecords          int               // query parameter for bulk queries to use to split up large results
+	bulkPollTimeout              time.Duration                  // timeout for waiting on bulk job completion
+	httpClient                   *http.Client                   // HTTP client (created internally)
+	roundTripper                 http.RoundTripper              // Custom round tripper
+	transport                    TransportSettings              // connection tuning of the default transport
+	shouldValidateAuthentication bool                           // Validate session on client creation
+	httpTimeout                  time.Duration                  // HTTP client timeout
+	bulkQueryMaxRecords          int                            // query parameter for bulk queries to use to split up large results
+	responseCompression          bool                           // request gzip encoded responses and decompress them transparently
+	requestCompressionThreshold  int                            // gzip request bodies at least this many bytes long, 0 disables
+	maxRequestSize               int                            // split collections and reject request bodies above this many bytes, 0 disables
+	apiUsage                     *apiUsageTracker               // most recent api usage reported by salesforce
+	automationBypassField        string                         // checkbox field set to true on every record written
+	automationBypassObjects      []string                       // sObjects the bypass field applies to, all if empty
+	sObjectDefaults              map[string]SObjectDefaults     // DML defaults keyed by lowercased sObject name
+	batchGrouping                GroupingKey                    // keeps records of the same group in the same batch, nil if disabled
+	dmlHooks                     DMLHooks                       // called before and after DML operations
+	auditLog                     AuditLog                       // receives an entry for every request, nil if disabled
+	metrics                      *Metrics                       // collects metrics of every request, nil if disabled
+	redaction                    RedactionSettings              // fields masked by Redact
+	encryptedFieldCheck          bool                           // check queries for encrypted fields that cannot be filtered or sorted
+	nameValidation               bool                           // validate sObject and field names against the describe before requests
+	permissionPreflight          bool                           // check the user's permissions before bulk, metadata, and tooling requests
+	permissionCache              *ttlCache                      // cached permissions of the user
+	customMetadataCache          *ttlCache                      // cached custom metadata and custom setting records
+	describeCache                *ttlCache                      // cached sObject describe results
+	fieldTruncation              bool                           // truncate text values longer than their field length before DML
+	writableFieldsOnly           bool                           // remove fields the operation cannot set before DML
+	idempotencyStore             IdempotencyStore               // results of inserts sent with an idempotency key
+	idempotencyKeyField          string                         // external id field that idempotency keys are written to
+	sObjectNameInference         bool                           // infer omitted sObject names from struct type names
+	strictDecoding               bool                           // fail when query fields and struct fields do not match
+	unknownFieldErrors           bool                           // fail when query fields are missing from the struct
+	numberDecoding               NumberDecoding                 // how numbers of query results are decoded
+	jsonCodec                    JSONCodec                      // encodes DML bodies and decodes query results, nil for encoding/json
+	circuitBreaker               *circuitBreaker                // rejects requests while Salesforce is failing, nil if disabled
+	maintenanceBackoff           *MaintenanceBackoff            // waits out maintenance before retrying requests, nil if disabled
+	lockRetry                    *LockRetry                     // retries records of collections that failed on a row lock, nil if disabled
+	rateLimiter                  *RateLimiter                   // limits all requests, nil if disabled
+	endpointRateLimiters         map[EndpointClass]*RateLimiter // limits requests of an endpoint class
+	priorityQueue                *fairQueue                     // queues requests by priority, nil if disabled
+	responseCache                ResponseCache                  // cached bodies of GET responses, nil if disabled
+	responseCacheTTL             time.Duration                  // how long responses are cached
+	cachedEndpoints              []string                       // uri prefixes of GET requests cached in addition to the defaults
+	instanceUrl                  string                         // instance url used instead of the one of the token response
+	communityUrl                 string                         // experience cloud site url requests are sent under
+	loginUrl                     string                         // login url of the auth flows, overriding Creds.Domain
+	soapLogin                    bool                           // log in with the SOAP API when there is no consumer key
+	credentialProvider           CredentialProvider             // consulted for credentials on each authentication, nil if disabled
+	sessionStore                 SessionStore                   // shares the session with other processes, nil if disabled
+	sessionKey                   string                         // key of the shared session in sessionStore
+	allowedDomains               []string                       // domains the instance url and redirects must stay within
 }
 
 func (c *configuration) setDefaults() {
@@ -31,18 +77,35 @@ func (c *configuration) setDefaults() {
 	c.bulkPollTimeout = bulkPollTimeout
 	c.httpTimeout = httpDefaultTimeout
 	c.bulkQueryMaxRecords = bulkQueryMaxRecords
+	c.responseCompression = true
+	c.requestCompressionThreshold = 0
+	c.maxRequestSize = 0
+	c.apiUsage = &apiUsageTracker{}
+	c.customMetadataCache = newTTLCache(customMetadataCacheTTL)
+	c.describeCache = newTTLCache(describeCacheTTL)
+	c.fieldTruncation = false
+	c.writableFieldsOnly = false
+	c.idempotencyStore = NewMemoryIdempotencyStore(idempotencyKeyTTL)
+	c.sObjectNameInference = true
+	c.strictDecoding = false
+	c.unknownFieldErrors = false
+	c.numberDecoding = NumberFloat64
+	c.encryptedFieldCheck = false
+	c.nameValidation = false
+	c.permissionPreflight = false
+	c.permissionCache = newTTLCache(permissionCacheTTL)
+	c.redaction = RedactionSettings{
+		FieldTypes: defaultRedactedFieldTypes,
+		Mask:       defaultRedactionMask,
+	}
 }
 
 func (c *configuration) configureHttpClient() {
 	// Set default HTTP client if none provided
 	if c.roundTripper == nil {
 		c.httpClient = &http.Client{
-			Timeout: c.httpTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:       httpDefaultMaxIdleConnections,
-				IdleConnTimeout:    httpDefaultIdleConnTimeout,
-				DisableCompression: false,
-			},
+			Timeout:   c.httpTimeout,
+			Transport: newTransport(c.transport.withDefaults()),
 		}
 	} else {
 		// Use custom round tripper with configured timeout
@@ -51,6 +114,15 @@ func (c *configuration) configureHttpClient() {
 			Timeout:   c.httpTimeout,
 		}
 	}
+	if len(c.allowedDomains) > 0 {
+		c.httpClient.CheckRedirect = c.checkRedirect
+	}
+	if c.priorityQueue != nil {
+		c.httpClient.Transport = &priorityTransport{
+			next:  c.httpClient.Transport,
+			queue: c.priorityQueue,
+		}
+	}
 }
 
 // Option is a functional configuration option that can return an error
@@ -134,6 +206,19 @@ func WithRoundTripper(rt http.RoundTripper) Option {
 	}
 }
 
+// WithTransportSettings tunes the connections of the default HTTP transport, such as how many idle
+// connections are kept for reuse and whether HTTP/2 is used. It has no effect with WithRoundTripper.
+func WithTransportSettings(settings TransportSettings) Option {
+	return func(c *configuration) error {
+		if settings.MaxIdleConns < 0 || settings.MaxIdleConnsPerHost < 0 ||
+			settings.MaxConnsPerHost < 0 || settings.IdleConnTimeout < 0 {
+			return errors.New("transport settings cannot be negative")
+		}
+		c.transport = settings
+		return nil
+	}
+}
+
 // WithHTTPTimeout sets the HTTP client timeout duration
 func WithHTTPTimeout(timeout time.Duration) Option {
 	return func(c *configuration) error {
@@ -145,6 +230,111 @@ func WithHTTPTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithInstanceUrl pins the client to an instance url, such as a My Domain url, which is used instead
+// of the instance url of the token response for every request and session refresh
+func WithInstanceUrl(instanceUrl string) Option {
+	return func(c *configuration) error {
+		pinned, err := parseInstanceUrl(instanceUrl)
+		if err != nil {
+			return err
+		}
+		c.instanceUrl = pinned
+		return nil
+	}
+}
+
+// WithCommunityUrl sends every request under the url of an Experience Cloud site, such as
+// https://example.my.site.com/partners, as required for community users. Authenticate with the site url
+// as the domain of the credentials to log in as a community user. Without it, the sfdc_community_url
+// of a community user's token response is used.
+func WithCommunityUrl(communityUrl string) Option {
+	return func(c *configuration) error {
+		site, err := parseCommunityUrl(communityUrl)
+		if err != nil {
+			return err
+		}
+		c.communityUrl = site
+		return nil
+	}
+}
+
+// WithLoginUrl sets the login url that the username-password, client credentials, JWT, and SOAP login
+// flows authenticate against, such as LoginUrlSandbox, LoginUrlMilitary for Government Cloud Plus -
+// Defense, or a My Domain url, instead of Creds.Domain. The instance url of the session is still read
+// from the token response, or pinned with WithInstanceUrl.
+func WithLoginUrl(loginUrl string) Option {
+	return func(c *configuration) error {
+		parsed, err := parseLoginUrl(loginUrl)
+		if err != nil {
+			return err
+		}
+		c.loginUrl = parsed
+		return nil
+	}
+}
+
+// WithSoapLogin sets whether credentials with a username and password but no consumer key log in with
+// the SOAP API login call, for orgs without an External Client App. Disabled by default, so that
+// incomplete OAuth credentials fail instead of silently logging in through a different flow.
+func WithSoapLogin(enabled bool) Option {
+	return func(c *configuration) error {
+		c.soapLogin = enabled
+		return nil
+	}
+}
+
+// WithCredentialProvider authenticates with the credentials of a provider instead of the creds passed
+// to Init, which must be empty. The provider is consulted on each authentication, including session
+// refreshes, and again when Salesforce rejects its credentials, up to 3 times, so that rotated secrets
+// are picked up without rebuilding the client.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(c *configuration) error {
+		if provider == nil {
+			return errors.New("credential provider cannot be nil")
+		}
+		c.credentialProvider = provider
+		return nil
+	}
+}
+
+// WithSessionStore shares the session of the client with other processes through a SessionStore, such
+// as one backed by Redis, under the given key, which identifies the org and user, such as
+// salesforce:prod:integration-user. Init uses the stored session instead of authenticating if there is
+// one, and refreshes are locked so that only one process authenticates when the session expires.
+func WithSessionStore(store SessionStore, key string) Option {
+	return func(c *configuration) error {
+		if store == nil {
+			return errors.New("session store cannot be nil")
+		}
+		if key == "" {
+			return errors.New("session key is required")
+		}
+		c.sessionStore = store
+		c.sessionKey = key
+		return nil
+	}
+}
+
+// WithAllowedDomains requires the instance url and the redirects of every request to stay within the
+// given domains or their subdomains, such as example.my.salesforce.com or my.salesforce.com
+func WithAllowedDomains(domains ...string) Option {
+	return func(c *configuration) error {
+		if len(domains) == 0 {
+			return errors.New("at least one allowed domain is required")
+		}
+		allowed := make([]string, len(domains))
+		for i, domain := range domains {
+			domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+			if domain == "" || strings.Contains(domain, "/") {
+				return fmt.Errorf("invalid allowed domain %q", domains[i])
+			}
+			allowed[i] = domain
+		}
+		c.allowedDomains = allowed
+		return nil
+	}
+}
+
 // WithValidateAuthentication sets whether to validate the authentication session on client creation
 func WithValidateAuthentication(validate bool) Option {
 	return func(c *configuration) error {
@@ -163,3 +353,428 @@ func WithBulkQueryMaxRecords(maxRecords int) Option {
 		return nil
 	}
 }
+
+// WithResponseCompression sets whether to request gzip encoded responses from Salesforce.
+// Compressed responses are decompressed transparently. Enabled by default.
+func WithResponseCompression(compression bool) Option {
+	return func(c *configuration) error {
+		c.responseCompression = compression
+		return nil
+	}
+}
+
+// WithRequestCompressionThreshold gzips request bodies that are at least the given number of bytes.
+// A threshold of 0 disables size based compression.
+func WithRequestCompressionThreshold(size int) Option {
+	return func(c *configuration) error {
+		if size < 0 {
+			return errors.New("request compression threshold cannot be negative")
+		}
+		c.requestCompressionThreshold = size
+		return nil
+	}
+}
+
+// WithMaxRequestSize sets the largest request body in bytes that is sent to Salesforce, before
+// compression. SObject collection batches that are larger are split into smaller batches, so records
+// with large long text or rich text values are sent in as many requests as needed. Other requests that
+// are larger fail with a RequestTooLargeError before they are sent. A size of 0 disables the limit.
+func WithMaxRequestSize(size int) Option {
+	return func(c *configuration) error {
+		if size < 0 {
+			return errors.New("max request size cannot be negative")
+		}
+		c.maxRequestSize = size
+		return nil
+	}
+}
+
+// WithAutomationBypassField sets a checkbox field to true on every record written by DML operations,
+// for orgs whose triggers, flows, and validation rules check a designated field to skip automation.
+// If sObjectNames are given, the field is only set on records of those sObjects.
+func WithAutomationBypassField(fieldName string, sObjectNames ...string) Option {
+	return func(c *configuration) error {
+		if fieldName == "" {
+			return errors.New("automation bypass field name cannot be empty")
+		}
+		c.automationBypassField = fieldName
+		c.automationBypassObjects = sObjectNames
+		return nil
+	}
+}
+
+// WithSObjectDefaults registers defaults of DML operations on an sObject, such as to disable assignment
+// rules of Lead records or bypass duplicate rules of Account records, that apply to every call on it.
+// The batch size is used by calls passing a batch size of 0, and the assignment rule by bulk calls
+// passing an empty assignment rule id. Registering the same sObject again replaces its defaults.
+func WithSObjectDefaults(sObjectName string, defaults SObjectDefaults) Option {
+	return func(c *configuration) error {
+		if err := defaults.validate(sObjectName); err != nil {
+			return err
+		}
+		if c.sObjectDefaults == nil {
+			c.sObjectDefaults = map[string]SObjectDefaults{}
+		}
+		c.sObjectDefaults[strings.ToLower(sObjectName)] = defaults
+		return nil
+	}
+}
+
+// WithBatchGrouping orders the records of collection and bulk operations by a grouping key, such as
+// GroupByField("AccountId"), and keeps the records of a group in the same batch, to reduce
+// UNABLE_TO_LOCK_ROW errors of batches that lock the same parent records. Results are returned in the
+// order of the records passed.
+func WithBatchGrouping(key GroupingKey) Option {
+	return func(c *configuration) error {
+		if key == nil {
+			return errors.New("grouping key cannot be nil")
+		}
+		c.batchGrouping = key
+		return nil
+	}
+}
+
+// WithDMLHooks sets hooks called before and after DML operations with the sObject name and records,
+// such as to stamp a field on every record written or to log and count the records of each operation
+func WithDMLHooks(hooks DMLHooks) Option {
+	return func(c *configuration) error {
+		c.dmlHooks = hooks
+		return nil
+	}
+}
+
+// WithAuditLog logs every request sent to Salesforce, with its time, user, endpoint, ids of the records
+// written, and outcome, for audits of the changes an integration made
+func WithAuditLog(log AuditLog) Option {
+	return func(c *configuration) error {
+		if log == nil {
+			return errors.New("audit log cannot be nil")
+		}
+		c.auditLog = log
+		return nil
+	}
+}
+
+// WithMetrics collects metrics of the client's requests, bulk jobs, and change events in metrics,
+// which serves them to Prometheus, see Metrics. Pass the same metrics to several clients to collect
+// their metrics together.
+func WithMetrics(metrics *Metrics) Option {
+	return func(c *configuration) error {
+		if metrics == nil {
+			return errors.New("metrics cannot be nil")
+		}
+		c.metrics = metrics
+		return nil
+	}
+}
+
+// WithRedaction sets which fields Redact masks and what they are replaced with
+func WithRedaction(settings RedactionSettings) Option {
+	return func(c *configuration) error {
+		if settings.FieldTypes == nil {
+			settings.FieldTypes = defaultRedactedFieldTypes
+		}
+		if settings.Mask == "" {
+			settings.Mask = defaultRedactionMask
+		}
+		c.redaction = settings
+		return nil
+	}
+}
+
+// WithCustomMetadataCacheTTL sets how long custom metadata and custom setting records are cached.
+// A duration of 0 disables caching.
+func WithCustomMetadataCacheTTL(ttl time.Duration) Option {
+	return func(c *configuration) error {
+		if ttl < 0 {
+			return errors.New("custom metadata cache ttl cannot be negative")
+		}
+		c.customMetadataCache = newTTLCache(ttl)
+		return nil
+	}
+}
+
+// WithDescribeCacheTTL sets how long sObject describe results are cached.
+// A duration of 0 disables caching.
+func WithDescribeCacheTTL(ttl time.Duration) Option {
+	return func(c *configuration) error {
+		if ttl < 0 {
+			return errors.New("describe cache ttl cannot be negative")
+		}
+		c.describeCache = newTTLCache(ttl)
+		return nil
+	}
+}
+
+// WithFieldTruncation sets whether text values longer than their field length are truncated before
+// records are inserted, updated, or upserted, instead of failing with STRING_TOO_LONG.
+// The REST API has no equivalent of the SOAP AllowFieldTruncationHeader, so field lengths are read
+// from the cached sObject describe.
+func WithFieldTruncation(truncate bool) Option {
+	return func(c *configuration) error {
+		c.fieldTruncation = truncate
+		return nil
+	}
+}
+
+// WithWritableFieldsOnly sets whether fields that cannot be set by a DML operation are removed from
+// records before they are sent, such as formula, roll-up summary, and audit fields. Inserts keep
+// createable fields, updates keep updateable fields, and upserts keep fields that are both, as read
+// from the cached sObject describe. This lets queried records be written back without pruning fields.
+func WithWritableFieldsOnly(writableOnly bool) Option {
+	return func(c *configuration) error {
+		c.writableFieldsOnly = writableOnly
+		return nil
+	}
+}
+
+// WithSObjectNameInference sets whether the sObject name of DML operations called with an empty name
+// is inferred from the struct type name of the records, such as Account for a struct named Account.
+// A struct field tagged with the sObject name, such as _ struct{} `salesforce:"object=Account"`, is
+// used regardless. Enabled by default.
+func WithSObjectNameInference(enabled bool) Option {
+	return func(c *configuration) error {
+		c.sObjectNameInference = enabled
+		return nil
+	}
+}
+
+// WithStrictDecoding sets whether decoding query results into structs fails when a record has fields
+// that are missing from the struct, or the struct has fields that are missing from the record, naming
+// the fields in the error. Useful in tests to catch queries and structs that drift apart. Disabled by
+// default, which drops fields missing from the struct and leaves struct fields missing from the record
+// empty.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *configuration) error {
+		c.strictDecoding = strict
+		return nil
+	}
+}
+
+// WithUnknownFieldErrors sets whether decoding query results into structs fails when a record has
+// fields that are missing from the struct, naming the fields in the error, while struct fields missing
+// from the record are left empty. Useful in pipelines that must not silently drop data the query
+// returns. Disabled by default, which drops fields missing from the struct. WithStrictDecoding checks
+// both directions.
+func WithUnknownFieldErrors(enabled bool) Option {
+	return func(c *configuration) error {
+		c.unknownFieldErrors = enabled
+		return nil
+	}
+}
+
+// WithNumberDecoding sets how numbers of query results are decoded when they are not decoded into a
+// struct field of a specific type, such as into a map, Record, or AggregateResult. NumberJSONNumber
+// preserves every digit of large numbers, such as 18 digit number and currency fields, which float64
+// values round. Defaults to NumberFloat64.
+func WithNumberDecoding(decoding NumberDecoding) Option {
+	return func(c *configuration) error {
+		if decoding < NumberFloat64 || decoding > NumberInt64 {
+			return fmt.Errorf("invalid number decoding: %d", decoding)
+		}
+		c.numberDecoding = decoding
+		return nil
+	}
+}
+
+// WithJSONCodec sets the JSON codec that encodes the records of DML operations and decodes query
+// results instead of encoding/json, such as jsoniter.ConfigCompatibleWithStandardLibrary. Together with
+// WithNumberDecoding, the codec must decode numbers as json.Number, such as
+// jsoniter.Config{UseNumber: true}.Froze().
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(c *configuration) error {
+		if codec == nil {
+			return errors.New("JSON codec cannot be nil")
+		}
+		c.jsonCodec = codec
+		return nil
+	}
+}
+
+// WithEncryptedFieldCheck sets whether queries are checked with CheckEncryptedFields before they are
+// sent, returning an EncryptedFieldError instead of the error Salesforce returns when a query filters,
+// sorts, or groups by a field encrypted with Shield Platform Encryption. Checking requires the describes
+// of the queried sObjects, which are cached. Defaults to false.
+func WithEncryptedFieldCheck(enabled bool) Option {
+	return func(c *configuration) error {
+		c.encryptedFieldCheck = enabled
+		return nil
+	}
+}
+
+// WithNameValidation sets whether the sObject and field names of DML records and queries are validated
+// with ValidateNames before they are sent, returning an UnknownNameError that suggests the closest name
+// instead of the NOT_FOUND or INVALID_FIELD error Salesforce returns. Validation requires the global
+// describe and the describes of the sObjects, which are cached. Defaults to false.
+func WithNameValidation(enabled bool) Option {
+	return func(c *configuration) error {
+		c.nameValidation = enabled
+		return nil
+	}
+}
+
+// WithPermissionPreflight sets whether the user's permissions are checked before Bulk, Metadata, and
+// Tooling API requests, returning a PermissionError that names the missing permissions, such as API
+// Enabled or Modify Metadata, instead of the error Salesforce returns partway through an operation.
+// The permissions are read from the user's permission sets and cached. Defaults to false.
+func WithPermissionPreflight(enabled bool) Option {
+	return func(c *configuration) error {
+		c.permissionPreflight = enabled
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops requests from being sent while Salesforce is failing, so an outage fails
+// fast instead of piling up requests. Once the rate of transport and server errors reaches the failure
+// rate, requests fail with ErrCircuitOpen until the open timeout passes. Then trial requests are sent,
+// closing the circuit if they succeed or opening it again if any fails. Client errors, such as
+// validation errors, are not failures.
+func WithCircuitBreaker(settings CircuitBreakerSettings) Option {
+	return func(c *configuration) error {
+		cb, err := newCircuitBreaker(settings)
+		if err != nil {
+			return err
+		}
+		c.circuitBreaker = cb
+		return nil
+	}
+}
+
+// WithMaintenanceBackoff waits and sends requests again while Salesforce is unavailable for maintenance,
+// instead of failing every request with a MaintenanceError. Waits start at the initial wait and double
+// up to the max wait, or follow the Retry-After header of responses, until the max duration passes or
+// the context of the request is done. Requests with bodies that cannot be read again are not retried.
+func WithMaintenanceBackoff(backoff MaintenanceBackoff) Option {
+	return func(c *configuration) error {
+		settings, err := newMaintenanceBackoff(backoff)
+		if err != nil {
+			return err
+		}
+		c.maintenanceBackoff = settings
+		return nil
+	}
+}
+
+// WithLockRetry retries the records of collection inserts, updates, and upserts that fail with
+// UNABLE_TO_LOCK_ROW because another transaction holds a lock on them or on a record they reference.
+// Only the locked records are sent again, after a backoff and in batches half the size of the previous
+// attempt, and their results replace the lock errors.
+func WithLockRetry(retry LockRetry) Option {
+	return func(c *configuration) error {
+		settings, err := newLockRetry(retry)
+		if err != nil {
+			return err
+		}
+		c.lockRetry = settings
+		return nil
+	}
+}
+
+// WithRateLimiter limits how many requests per second are sent, waiting before requests that would
+// exceed the limit, so bursty workloads stay within the org's concurrent request limits. Pass the same
+// limiter to several clients to share the limit between them.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *configuration) error {
+		if limiter == nil {
+			return errors.New("rate limiter cannot be nil")
+		}
+		c.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithEndpointRateLimiter limits how many requests per second are sent to one class of endpoints,
+// such as queries or Bulk API jobs. Requests wait on both this limiter and the one set with
+// WithRateLimiter, if any. Pass the same limiter to several clients to share the limit between them.
+func WithEndpointRateLimiter(class EndpointClass, limiter *RateLimiter) Option {
+	return func(c *configuration) error {
+		if limiter == nil {
+			return errors.New("rate limiter cannot be nil")
+		}
+		switch class {
+		case EndpointQuery, EndpointSObject, EndpointComposite, EndpointBulk, EndpointTooling,
+			EndpointOther:
+		default:
+			return fmt.Errorf("unknown endpoint class: %s", class)
+		}
+		if c.endpointRateLimiters == nil {
+			c.endpointRateLimiters = map[EndpointClass]*RateLimiter{}
+		}
+		c.endpointRateLimiters[class] = limiter
+		return nil
+	}
+}
+
+// WithPriorityQueue limits how many requests are sent at once and queues the rest by priority, so
+// interactive requests are not starved behind batch work in processes that do both. Waiting requests
+// are let through by weighted fair queueing between the interactive and batch classes. Bulk API
+// requests and composite requests that write records are batch requests by default, and other
+// requests are interactive; use ContextWithPriority to set the class of a request made with a context.
+func WithPriorityQueue(settings PriorityQueueSettings) Option {
+	return func(c *configuration) error {
+		queue, err := newFairQueue(settings)
+		if err != nil {
+			return err
+		}
+		c.priorityQueue = queue
+		return nil
+	}
+}
+
+// WithResponseCache caches the responses of GET requests for sObject describes and UI API object info,
+// such as picklist values and record types, for the given duration. Add other endpoints with
+// WithCachedEndpoints, and remove cached responses with InvalidateResponseCache. Pass a cache
+// implemented with Redis, for example, to share cached responses between processes.
+func WithResponseCache(cache ResponseCache, ttl time.Duration) Option {
+	return func(c *configuration) error {
+		if cache == nil {
+			return errors.New("response cache cannot be nil")
+		}
+		if ttl <= 0 {
+			return errors.New("response cache ttl must be greater than 0")
+		}
+		c.responseCache = cache
+		c.responseCacheTTL = ttl
+		return nil
+	}
+}
+
+// WithCachedEndpoints adds GET requests whose uri, relative to the versioned data API, starts with one
+// of the given prefixes to the requests cached by WithResponseCache, such as "/tooling/sobjects/" or
+// "/query/?q=SELECT+Id%2C+DeveloperName+FROM+RecordType"
+func WithCachedEndpoints(uriPrefixes ...string) Option {
+	return func(c *configuration) error {
+		for _, prefix := range uriPrefixes {
+			if prefix == "" {
+				return errors.New("cached endpoint prefix cannot be empty")
+			}
+		}
+		c.cachedEndpoints = append(c.cachedEndpoints, uriPrefixes...)
+		return nil
+	}
+}
+
+// WithIdempotencyStore sets where the results of inserts sent with an idempotency key are kept.
+// Keys are kept in memory for 24 hours by default.
+func WithIdempotencyStore(store IdempotencyStore) Option {
+	return func(c *configuration) error {
+		if store == nil {
+			return errors.New("idempotency store cannot be nil")
+		}
+		c.idempotencyStore = store
+		return nil
+	}
+}
+
+// WithIdempotencyKeyField sets a unique external id field that idempotency keys are written to.
+// Inserts with an idempotency key are sent as upserts on this field, so Salesforce rejects duplicates
+// even when a retry happens after a request whose outcome is unknown.
+func WithIdempotencyKeyField(fieldName string) Option {
+	return func(c *configuration) error {
+		if fieldName == "" {
+			return errors.New("idempotency key field name cannot be empty")
+		}
+		c.idempotencyKeyField = fieldName
+		return nil
+	}
+}
@@ -101,6 +101,18 @@ func TestConfigurationHTTPClientDefaults(t *testing.T) {
 				httpDefaultIdleConnTimeout,
 			)
 		}
+
+		if transport.MaxIdleConnsPerHost != httpDefaultMaxIdleConnsPerHost {
+			t.Errorf(
+				"setDefaults() HTTP transport MaxIdleConnsPerHost = %v, want %v",
+				transport.MaxIdleConnsPerHost,
+				httpDefaultMaxIdleConnsPerHost,
+			)
+		}
+
+		if !transport.ForceAttemptHTTP2 {
+			t.Error("setDefaults() HTTP transport should attempt HTTP/2")
+		}
 	})
 
 	t.Run("with_custom_round_tripper", func(t *testing.T) {
@@ -300,3 +312,101 @@ func TestWithValidateAuthentication(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTransportSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings TransportSettings
+		want     TransportSettings
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			settings: TransportSettings{},
+			want: TransportSettings{
+				MaxIdleConns:        httpDefaultMaxIdleConnections,
+				MaxIdleConnsPerHost: httpDefaultMaxIdleConnsPerHost,
+				IdleConnTimeout:     httpDefaultIdleConnTimeout,
+			},
+		},
+		{
+			name: "custom",
+			settings: TransportSettings{
+				MaxIdleConns:        20,
+				MaxIdleConnsPerHost: 20,
+				MaxConnsPerHost:     50,
+				IdleConnTimeout:     time.Minute,
+				DisableHTTP2:        true,
+			},
+			want: TransportSettings{
+				MaxIdleConns:        20,
+				MaxIdleConnsPerHost: 20,
+				MaxConnsPerHost:     50,
+				IdleConnTimeout:     time.Minute,
+				DisableHTTP2:        true,
+			},
+		},
+		{
+			name:     "negative",
+			settings: TransportSettings{MaxConnsPerHost: -1},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			config.setDefaults()
+			err := WithTransportSettings(tt.settings)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithTransportSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			config.configureHttpClient()
+			transport := config.httpClient.Transport.(*http.Transport)
+			if transport.MaxIdleConns != tt.want.MaxIdleConns ||
+				transport.MaxIdleConnsPerHost != tt.want.MaxIdleConnsPerHost ||
+				transport.MaxConnsPerHost != tt.want.MaxConnsPerHost ||
+				transport.IdleConnTimeout != tt.want.IdleConnTimeout {
+				t.Errorf("transport = %+v, want %+v", transport, tt.want)
+			}
+			if transport.ForceAttemptHTTP2 == tt.want.DisableHTTP2 ||
+				(transport.TLSNextProto != nil) != tt.want.DisableHTTP2 {
+				t.Errorf("transport HTTP/2 enabled, want disabled %v", tt.want.DisableHTTP2)
+			}
+		})
+	}
+}
+
+// BenchmarkParallelRequests compares the default transport with one that keeps the 2 idle connections
+// per host of http.DefaultTransport, run with -cpu to vary the number of parallel requests
+func BenchmarkParallelRequests(b *testing.B) {
+	server, sfAuth := setupTestServer(map[string]any{}, http.StatusOK)
+	defer server.Close()
+
+	for _, bm := range []struct {
+		name     string
+		settings TransportSettings
+	}{
+		{name: "default", settings: TransportSettings{}},
+		{name: "two_idle_per_host", settings: TransportSettings{MaxIdleConnsPerHost: 2}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			sf := buildSalesforceStruct(&sfAuth)
+			sf.config.transport = bm.settings
+			sf.config.configureHttpClient()
+			b.SetParallelism(16)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					resp, err := sf.DoRequest(http.MethodGet, "/limits", nil)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					_ = resp.Body.Close()
+				}
+			})
+		})
+	}
+}
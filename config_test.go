@@ -1,6 +1,9 @@
 package salesforce
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -263,6 +266,20 @@ func TestConfigurationDefaults(t *testing.T) {
 		)
 	}
 
+	if config.responseCompression != true {
+		t.Errorf(
+			"Expected responseCompression default to be true, got %v",
+			config.responseCompression,
+		)
+	}
+
+	if config.requestCompressionThreshold != 0 {
+		t.Errorf(
+			"Expected requestCompressionThreshold default to be 0, got %v",
+			config.requestCompressionThreshold,
+		)
+	}
+
 	if config.bulkPollTimeout != bulkPollTimeout {
 		t.Errorf(
 			"Expected bulkPollTimeout default to be %v, got %v",
@@ -321,3 +338,892 @@ func TestWithBulkQueryMaxRecords(t *testing.T) {
 		})
 	}
 }
+
+func TestWithResponseCompression(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression bool
+	}{
+		{
+			name:        "enable_response_compression",
+			compression: true,
+		},
+		{
+			name:        "disable_response_compression",
+			compression: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			option := WithResponseCompression(tt.compression)
+			if err := option(&config); err != nil {
+				t.Errorf("WithResponseCompression() error = %v", err)
+				return
+			}
+
+			if config.responseCompression != tt.compression {
+				t.Errorf(
+					"WithResponseCompression() = %v, want %v",
+					config.responseCompression,
+					tt.compression,
+				)
+			}
+		})
+	}
+}
+
+func TestWithRequestCompressionThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		wantErr   bool
+		wantValue int
+	}{
+		{
+			name:      "valid_threshold",
+			size:      1024,
+			wantErr:   false,
+			wantValue: 1024,
+		},
+		{
+			name:      "disable_threshold",
+			size:      0,
+			wantErr:   false,
+			wantValue: 0,
+		},
+		{
+			name:      "negative_threshold",
+			size:      -1,
+			wantErr:   true,
+			wantValue: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			option := WithRequestCompressionThreshold(tt.size)
+			err := option(&config)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf(
+					"WithRequestCompressionThreshold() error = %v, wantErr %v",
+					err,
+					tt.wantErr,
+				)
+				return
+			}
+
+			if config.requestCompressionThreshold != tt.wantValue {
+				t.Errorf(
+					"WithRequestCompressionThreshold() = %v, want %v",
+					config.requestCompressionThreshold,
+					tt.wantValue,
+				)
+			}
+		})
+	}
+}
+
+func TestWithMaxRequestSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		wantErr   bool
+		wantValue int
+	}{
+		{
+			name:      "valid_size",
+			size:      6_000_000,
+			wantErr:   false,
+			wantValue: 6_000_000,
+		},
+		{
+			name:      "disable_size",
+			size:      0,
+			wantErr:   false,
+			wantValue: 0,
+		},
+		{
+			name:      "negative_size",
+			size:      -1,
+			wantErr:   true,
+			wantValue: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			option := WithMaxRequestSize(tt.size)
+			err := option(&config)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithMaxRequestSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if config.maxRequestSize != tt.wantValue {
+				t.Errorf("WithMaxRequestSize() = %v, want %v", config.maxRequestSize, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestWithAutomationBypassField(t *testing.T) {
+	tests := []struct {
+		name        string
+		fieldName   string
+		sObjects    []string
+		wantErr     bool
+		wantField   string
+		wantObjects []string
+	}{
+		{
+			name:        "all_objects",
+			fieldName:   "Bypass_Automation__c",
+			wantErr:     false,
+			wantField:   "Bypass_Automation__c",
+			wantObjects: nil,
+		},
+		{
+			name:        "selected_objects",
+			fieldName:   "Bypass_Automation__c",
+			sObjects:    []string{"Account", "Contact"},
+			wantErr:     false,
+			wantField:   "Bypass_Automation__c",
+			wantObjects: []string{"Account", "Contact"},
+		},
+		{
+			name:      "empty_field_name",
+			fieldName: "",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			option := WithAutomationBypassField(tt.fieldName, tt.sObjects...)
+			err := option(&config)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithAutomationBypassField() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if config.automationBypassField != tt.wantField ||
+				!reflect.DeepEqual(config.automationBypassObjects, tt.wantObjects) {
+				t.Errorf(
+					"WithAutomationBypassField() = %v %v, want %v %v",
+					config.automationBypassField,
+					config.automationBypassObjects,
+					tt.wantField,
+					tt.wantObjects,
+				)
+			}
+		})
+	}
+}
+
+func TestWithSObjectDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		sObjectName string
+		defaults    SObjectDefaults
+		wantErr     bool
+	}{
+		{
+			name:        "lead_without_assignment_rules",
+			sObjectName: "Lead",
+			defaults:    SObjectDefaults{DisableAssignmentRules: true, BatchSize: 100},
+		},
+		{
+			name:        "case_assignment_rule",
+			sObjectName: "Case",
+			defaults:    SObjectDefaults{AssignmentRuleId: "01Q000000000001"},
+		},
+		{
+			name:        "empty_sObject_name",
+			sObjectName: "",
+			defaults:    SObjectDefaults{AllowDuplicates: true},
+			wantErr:     true,
+		},
+		{
+			name:        "negative_batch_size",
+			sObjectName: "Task",
+			defaults:    SObjectDefaults{BatchSize: -1},
+			wantErr:     true,
+		},
+		{
+			name:        "assignment_rule_and_disabled_rules",
+			sObjectName: "Lead",
+			defaults: SObjectDefaults{
+				AssignmentRuleId:       "01Q000000000001",
+				DisableAssignmentRules: true,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "assignment_rule_of_unsupported_sObject",
+			sObjectName: "Account",
+			defaults:    SObjectDefaults{AssignmentRuleId: "01Q000000000001"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			err := WithSObjectDefaults(tt.sObjectName, tt.defaults)(&config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithSObjectDefaults() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			defaults, ok := sObjectDefaultsOf(&config, strings.ToUpper(tt.sObjectName))
+			if !ok || defaults != tt.defaults {
+				t.Errorf("WithSObjectDefaults() = %+v, want %+v", defaults, tt.defaults)
+			}
+		})
+	}
+}
+
+func TestWithBatchGrouping(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithBatchGrouping(nil)(&config); err == nil {
+		t.Error("WithBatchGrouping(nil) error = nil, want an error")
+	}
+	if err := WithBatchGrouping(GroupByField("AccountId"))(&config); err != nil {
+		t.Fatalf("WithBatchGrouping() error = %v", err)
+	}
+	if key := config.batchGrouping("Contact", map[string]any{"AccountId": "001A"}); key != "001A" {
+		t.Errorf("WithBatchGrouping() key = %v, want 001A", key)
+	}
+}
+
+func TestWithLockRetry(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithLockRetry(LockRetry{InitialWait: -time.Second})(&config); err == nil {
+		t.Error("WithLockRetry() with a negative wait error = nil, want an error")
+	}
+	if err := WithLockRetry(LockRetry{MaxAttempts: 5})(&config); err != nil {
+		t.Fatalf("WithLockRetry() error = %v", err)
+	}
+	if config.lockRetry == nil || config.lockRetry.MaxAttempts != 5 ||
+		config.lockRetry.InitialWait != time.Second {
+		t.Errorf("WithLockRetry() = %+v, want 5 attempts with default waits", config.lockRetry)
+	}
+}
+
+func TestWithFieldTruncation(t *testing.T) {
+	for _, truncate := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithFieldTruncation(truncate)(&config); err != nil {
+			t.Errorf("WithFieldTruncation() error = %v", err)
+		}
+		if config.fieldTruncation != truncate {
+			t.Errorf("WithFieldTruncation() = %v, want %v", config.fieldTruncation, truncate)
+		}
+	}
+}
+
+func TestWithWritableFieldsOnly(t *testing.T) {
+	for _, writableOnly := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithWritableFieldsOnly(writableOnly)(&config); err != nil {
+			t.Errorf("WithWritableFieldsOnly() error = %v", err)
+		}
+		if config.writableFieldsOnly != writableOnly {
+			t.Errorf(
+				"WithWritableFieldsOnly() = %v, want %v",
+				config.writableFieldsOnly,
+				writableOnly,
+			)
+		}
+	}
+}
+
+func TestWithSObjectNameInference(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+	if !config.sObjectNameInference {
+		t.Error("sObject name inference should be enabled by default")
+	}
+	if err := WithSObjectNameInference(false)(&config); err != nil {
+		t.Errorf("WithSObjectNameInference() error = %v", err)
+	}
+	if config.sObjectNameInference {
+		t.Error("WithSObjectNameInference(false) did not disable inference")
+	}
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	for _, strict := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithStrictDecoding(strict)(&config); err != nil {
+			t.Errorf("WithStrictDecoding() error = %v", err)
+		}
+		if config.strictDecoding != strict {
+			t.Errorf("WithStrictDecoding() = %v, want %v", config.strictDecoding, strict)
+		}
+	}
+}
+
+func TestWithSoapLogin(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithSoapLogin(enabled)(&config); err != nil {
+			t.Errorf("WithSoapLogin() error = %v", err)
+		}
+		if config.soapLogin != enabled {
+			t.Errorf("WithSoapLogin() = %v, want %v", config.soapLogin, enabled)
+		}
+	}
+}
+
+func TestWithUnknownFieldErrors(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithUnknownFieldErrors(enabled)(&config); err != nil {
+			t.Errorf("WithUnknownFieldErrors() error = %v", err)
+		}
+		if config.unknownFieldErrors != enabled {
+			t.Errorf("WithUnknownFieldErrors() = %v, want %v", config.unknownFieldErrors, enabled)
+		}
+	}
+}
+
+func TestWithDMLHooks(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	hooks := DMLHooks{BeforeInsert: func(string, []map[string]any) error { return nil }}
+	if err := WithDMLHooks(hooks)(&config); err != nil {
+		t.Errorf("WithDMLHooks() error = %v", err)
+	}
+	if config.dmlHooks.BeforeInsert == nil || config.dmlHooks.AfterInsert != nil {
+		t.Errorf("WithDMLHooks() = %v", config.dmlHooks)
+	}
+}
+
+func TestWithAuditLog(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	log := NewMemoryAuditLog()
+	if err := WithAuditLog(log)(&config); err != nil {
+		t.Errorf("WithAuditLog() error = %v", err)
+	}
+	if config.auditLog != log {
+		t.Errorf("WithAuditLog() = %v, want %v", config.auditLog, log)
+	}
+	if err := WithAuditLog(nil)(&config); err == nil {
+		t.Error("WithAuditLog(nil) expected an error")
+	}
+}
+
+func TestWithRedaction(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithRedaction(RedactionSettings{Fields: []string{"Birthdate"}})(&config); err != nil {
+		t.Errorf("WithRedaction() error = %v", err)
+	}
+	want := RedactionSettings{
+		FieldTypes: defaultRedactedFieldTypes,
+		Fields:     []string{"Birthdate"},
+		Mask:       defaultRedactionMask,
+	}
+	if !reflect.DeepEqual(config.redaction, want) {
+		t.Errorf("WithRedaction() = %v, want %v", config.redaction, want)
+	}
+}
+
+func TestWithEncryptedFieldCheck(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithEncryptedFieldCheck(enabled)(&config); err != nil {
+			t.Errorf("WithEncryptedFieldCheck() error = %v", err)
+		}
+		if config.encryptedFieldCheck != enabled {
+			t.Errorf("WithEncryptedFieldCheck() = %v, want %v", config.encryptedFieldCheck, enabled)
+		}
+	}
+}
+
+func TestWithInstanceUrl(t *testing.T) {
+	tests := []struct {
+		name        string
+		instanceUrl string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "my_domain",
+			instanceUrl: "https://example.my.salesforce.com",
+			want:        "https://example.my.salesforce.com",
+		},
+		{
+			name:        "trailing_slash",
+			instanceUrl: "https://example.my.salesforce.com/",
+			want:        "https://example.my.salesforce.com",
+		},
+		{name: "http", instanceUrl: "http://example.my.salesforce.com", wantErr: true},
+		{name: "path", instanceUrl: "https://example.my.salesforce.com/services", wantErr: true},
+		{name: "empty", instanceUrl: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			err := WithInstanceUrl(tt.instanceUrl)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithInstanceUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if config.instanceUrl != tt.want {
+				t.Errorf("instanceUrl = %v, want %v", config.instanceUrl, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCommunityUrl(t *testing.T) {
+	tests := []struct {
+		name         string
+		communityUrl string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "site_path",
+			communityUrl: "https://example.my.site.com/partners/",
+			want:         "https://example.my.site.com/partners",
+		},
+		{
+			name:         "site_domain",
+			communityUrl: "https://partners.example.com",
+			want:         "https://partners.example.com",
+		},
+		{name: "http", communityUrl: "http://example.my.site.com/partners", wantErr: true},
+		{name: "query", communityUrl: "https://example.my.site.com/partners?x=1", wantErr: true},
+		{name: "empty", communityUrl: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			err := WithCommunityUrl(tt.communityUrl)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithCommunityUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if config.communityUrl != tt.want {
+				t.Errorf("communityUrl = %v, want %v", config.communityUrl, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLoginUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		loginUrl string
+		want     string
+		wantErr  bool
+	}{
+		{name: "military", loginUrl: LoginUrlMilitary, want: "https://login.salesforce.mil"},
+		{
+			name:     "my_domain",
+			loginUrl: "https://example.my.salesforce.com/",
+			want:     "https://example.my.salesforce.com",
+		},
+		{name: "http", loginUrl: "http://login.salesforce.com", wantErr: true},
+		{name: "path", loginUrl: "https://login.salesforce.com/services", wantErr: true},
+		{name: "empty", loginUrl: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			err := WithLoginUrl(tt.loginUrl)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithLoginUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if config.loginUrl != tt.want {
+				t.Errorf("loginUrl = %v, want %v", config.loginUrl, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCredentialProvider(t *testing.T) {
+	config := &configuration{}
+	if err := WithCredentialProvider(nil)(config); err == nil {
+		t.Errorf("WithCredentialProvider(nil) expected an error")
+	}
+	provider := CredentialProviderFunc(func(ctx context.Context) (Creds, error) {
+		return Creds{AccessToken: "1234"}, nil
+	})
+	if err := WithCredentialProvider(provider)(config); err != nil {
+		t.Fatalf("WithCredentialProvider() error = %v", err)
+	}
+	if config.credentialProvider == nil {
+		t.Errorf("credentialProvider = nil, want the provider")
+	}
+}
+
+func TestWithSessionStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   SessionStore
+		key     string
+		wantErr bool
+	}{
+		{name: "store", store: NewMemorySessionStore(), key: "salesforce:prod"},
+		{name: "nil_store", key: "salesforce:prod", wantErr: true},
+		{name: "empty_key", store: NewMemorySessionStore(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			err := WithSessionStore(tt.store, tt.key)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithSessionStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (config.sessionStore != tt.store || config.sessionKey != tt.key) {
+				t.Errorf("WithSessionStore() = %v, %v", config.sessionStore, config.sessionKey)
+			}
+		})
+	}
+}
+
+func TestWithMaintenanceBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff MaintenanceBackoff
+		want    MaintenanceBackoff
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: MaintenanceBackoff{
+				InitialWait: 30 * time.Second,
+				MaxWait:     5 * time.Minute,
+				MaxDuration: time.Hour,
+			},
+		},
+		{
+			name:    "negative",
+			backoff: MaintenanceBackoff{InitialWait: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "max_wait_below_initial_wait",
+			backoff: MaintenanceBackoff{InitialWait: time.Minute, MaxWait: time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			err := WithMaintenanceBackoff(tt.backoff)(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithMaintenanceBackoff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(*config.maintenanceBackoff, tt.want) {
+				t.Errorf("maintenanceBackoff = %+v, want %+v", *config.maintenanceBackoff, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAllowedDomains(t *testing.T) {
+	config := &configuration{}
+	if err := WithAllowedDomains("Example.My.Salesforce.com", ".salesforce.com")(config); err != nil {
+		t.Fatalf("WithAllowedDomains() error = %v", err)
+	}
+	want := []string{"example.my.salesforce.com", "salesforce.com"}
+	if !reflect.DeepEqual(config.allowedDomains, want) {
+		t.Errorf("allowedDomains = %v, want %v", config.allowedDomains, want)
+	}
+	if err := WithAllowedDomains()(config); err == nil {
+		t.Error("WithAllowedDomains() expected an error without domains")
+	}
+	if err := WithAllowedDomains("https://example.com")(config); err == nil {
+		t.Error("WithAllowedDomains() expected an error for a url")
+	}
+	if !domainAllowed("sub.example.my.salesforce.com", want) ||
+		domainAllowed("example.my.salesforce.com.evil.com", want[:1]) {
+		t.Error("domainAllowed() matched the wrong hosts")
+	}
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	config := &configuration{}
+	codec := &countingCodec{}
+	if err := WithJSONCodec(codec)(config); err != nil {
+		t.Fatalf("WithJSONCodec() error = %v", err)
+	}
+	if config.jsonCodec != codec {
+		t.Errorf("jsonCodec = %v, want %v", config.jsonCodec, codec)
+	}
+	if err := WithJSONCodec(nil)(config); err == nil {
+		t.Error("WithJSONCodec() expected an error for a nil codec")
+	}
+}
+
+func TestWithNameValidation(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithNameValidation(enabled)(&config); err != nil {
+			t.Errorf("WithNameValidation() error = %v", err)
+		}
+		if config.nameValidation != enabled {
+			t.Errorf("WithNameValidation() = %v, want %v", config.nameValidation, enabled)
+		}
+	}
+}
+
+func TestWithPermissionPreflight(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		config := configuration{}
+		config.setDefaults()
+
+		if err := WithPermissionPreflight(enabled)(&config); err != nil {
+			t.Errorf("WithPermissionPreflight() error = %v", err)
+		}
+		if config.permissionPreflight != enabled {
+			t.Errorf("WithPermissionPreflight() = %v, want %v", config.permissionPreflight, enabled)
+		}
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithMetrics(metrics)(&config); err != nil {
+		t.Errorf("WithMetrics() error = %v", err)
+	}
+	if config.metrics != metrics {
+		t.Errorf("WithMetrics() = %v, want %v", config.metrics, metrics)
+	}
+	if err := WithMetrics(nil)(&config); err == nil {
+		t.Errorf("WithMetrics() expected error for nil metrics")
+	}
+}
+
+func TestWithNumberDecoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		decoding  NumberDecoding
+		wantErr   bool
+		wantValue NumberDecoding
+	}{
+		{
+			name:      "json_number",
+			decoding:  NumberJSONNumber,
+			wantErr:   false,
+			wantValue: NumberJSONNumber,
+		},
+		{
+			name:      "int64",
+			decoding:  NumberInt64,
+			wantErr:   false,
+			wantValue: NumberInt64,
+		},
+		{
+			name:      "invalid_decoding",
+			decoding:  NumberDecoding(-1),
+			wantErr:   true,
+			wantValue: NumberFloat64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			err := WithNumberDecoding(tt.decoding)(&config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithNumberDecoding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if config.numberDecoding != tt.wantValue {
+				t.Errorf("WithNumberDecoding() = %v, want %v", config.numberDecoding, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestWithDescribeCacheTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		wantErr   bool
+		wantValue time.Duration
+	}{
+		{
+			name:      "valid_ttl",
+			ttl:       time.Hour,
+			wantErr:   false,
+			wantValue: time.Hour,
+		},
+		{
+			name:      "disable_cache",
+			ttl:       0,
+			wantErr:   false,
+			wantValue: 0,
+		},
+		{
+			name:      "negative_ttl",
+			ttl:       -1,
+			wantErr:   true,
+			wantValue: describeCacheTTL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+
+			err := WithDescribeCacheTTL(tt.ttl)(&config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithDescribeCacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if config.describeCache.ttl != tt.wantValue {
+				t.Errorf(
+					"WithDescribeCacheTTL() = %v, want %v",
+					config.describeCache.ttl,
+					tt.wantValue,
+				)
+			}
+		})
+	}
+}
+
+func TestWithIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Minute)
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithIdempotencyStore(store)(&config); err != nil {
+		t.Errorf("WithIdempotencyStore() error = %v", err)
+	}
+	if config.idempotencyStore != store {
+		t.Errorf("WithIdempotencyStore() = %v, want %v", config.idempotencyStore, store)
+	}
+	if err := WithIdempotencyStore(nil)(&config); err == nil {
+		t.Errorf("WithIdempotencyStore() expected error for nil store")
+	}
+}
+
+func TestWithIdempotencyKeyField(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithIdempotencyKeyField("Idempotency_Key__c")(&config); err != nil {
+		t.Errorf("WithIdempotencyKeyField() error = %v", err)
+	}
+	if config.idempotencyKeyField != "Idempotency_Key__c" {
+		t.Errorf("WithIdempotencyKeyField() = %v", config.idempotencyKeyField)
+	}
+	if err := WithIdempotencyKeyField("")(&config); err == nil {
+		t.Errorf("WithIdempotencyKeyField() expected error for empty field")
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	limiter, _ := NewRateLimiter(10, 5)
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithRateLimiter(limiter)(&config); err != nil {
+		t.Errorf("WithRateLimiter() error = %v", err)
+	}
+	if config.rateLimiter != limiter {
+		t.Errorf("WithRateLimiter() = %v, want %v", config.rateLimiter, limiter)
+	}
+	if err := WithRateLimiter(nil)(&config); err == nil {
+		t.Errorf("WithRateLimiter() expected error for nil limiter")
+	}
+}
+
+func TestWithEndpointRateLimiter(t *testing.T) {
+	limiter, _ := NewRateLimiter(10, 5)
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithEndpointRateLimiter(EndpointBulk, limiter)(&config); err != nil {
+		t.Errorf("WithEndpointRateLimiter() error = %v", err)
+	}
+	if config.endpointRateLimiters[EndpointBulk] != limiter {
+		t.Errorf("WithEndpointRateLimiter() = %v, want %v", config.endpointRateLimiters, limiter)
+	}
+	if err := WithEndpointRateLimiter(EndpointQuery, nil)(&config); err == nil {
+		t.Errorf("WithEndpointRateLimiter() expected error for nil limiter")
+	}
+	if err := WithEndpointRateLimiter("apex", limiter)(&config); err == nil {
+		t.Errorf("WithEndpointRateLimiter() expected error for unknown endpoint class")
+	}
+}
+
+func TestWithResponseCache(t *testing.T) {
+	cache := NewMemoryResponseCache()
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithResponseCache(cache, time.Hour)(&config); err != nil {
+		t.Errorf("WithResponseCache() error = %v", err)
+	}
+	if config.responseCache != cache || config.responseCacheTTL != time.Hour {
+		t.Errorf("WithResponseCache() = %v, %v", config.responseCache, config.responseCacheTTL)
+	}
+	if err := WithResponseCache(nil, time.Hour)(&config); err == nil {
+		t.Errorf("WithResponseCache() expected error for nil cache")
+	}
+	if err := WithResponseCache(cache, 0)(&config); err == nil {
+		t.Errorf("WithResponseCache() expected error for zero ttl")
+	}
+}
+
+func TestWithCachedEndpoints(t *testing.T) {
+	config := configuration{}
+	config.setDefaults()
+
+	if err := WithCachedEndpoints("/tooling/", "/limits")(&config); err != nil {
+		t.Errorf("WithCachedEndpoints() error = %v", err)
+	}
+	if !reflect.DeepEqual(config.cachedEndpoints, []string{"/tooling/", "/limits"}) {
+		t.Errorf("WithCachedEndpoints() = %v", config.cachedEndpoints)
+	}
+	if err := WithCachedEndpoints("")(&config); err == nil {
+		t.Errorf("WithCachedEndpoints() expected error for empty prefix")
+	}
+}
@@ -0,0 +1,71 @@
+package salesforce
+
+import "errors"
+
+// ConvertLeadRequest describes the conversion of a lead into an account, contact, and optionally an
+// opportunity
+type ConvertLeadRequest struct {
+	LeadId                 string
+	ConvertedStatus        string // a converted LeadStatus of the org, such as "Closed - Converted"
+	AccountId              string // existing account to merge the lead into instead of creating one
+	ContactId              string // existing contact to merge the lead into, requires AccountId
+	OwnerId                string // owner of the new records, the lead owner if empty
+	OpportunityName        string // name of the new opportunity, the lead company if empty
+	DoNotCreateOpportunity bool
+	OverwriteLeadSource    bool // set the LeadSource of an existing contact to the lead's
+	SendNotificationEmail  bool // notify the owner when OwnerId is set
+}
+
+// ConvertLeadResult contains the ids of the records of a converted lead. OpportunityId is empty when no
+// opportunity was created.
+type ConvertLeadResult struct {
+	LeadId        string
+	AccountId     string
+	ContactId     string
+	OpportunityId string
+}
+
+// ConvertLead converts a lead with the SOAP API convertLead call. If Salesforce rejects the conversion,
+// its errors are returned as a RecordError.
+func (sf *Salesforce) ConvertLead(request ConvertLeadRequest) (ConvertLeadResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ConvertLeadResult{}, authErr
+	}
+	if request.LeadId == "" || request.ConvertedStatus == "" {
+		return ConvertLeadResult{}, errors.New("lead id and converted status are required")
+	}
+	if request.ContactId != "" && request.AccountId == "" {
+		return ConvertLeadResult{}, errors.New(
+			"account id is required when converting into an existing contact",
+		)
+	}
+
+	results, err := soapConvertLead(sf, []soapLeadConvert{{
+		AccountId:              request.AccountId,
+		ContactId:              request.ContactId,
+		ConvertedStatus:        request.ConvertedStatus,
+		DoNotCreateOpportunity: request.DoNotCreateOpportunity,
+		LeadId:                 request.LeadId,
+		OpportunityName:        request.OpportunityName,
+		OverwriteLeadSource:    request.OverwriteLeadSource,
+		OwnerId:                request.OwnerId,
+		SendNotificationEmail:  request.SendNotificationEmail,
+	}})
+	if err != nil {
+		return ConvertLeadResult{}, err
+	}
+	result := results[0]
+	if !result.Success {
+		return ConvertLeadResult{}, RecordError{
+			Id:     request.LeadId,
+			Errors: soapErrors(result.Errors),
+		}
+	}
+	return ConvertLeadResult{
+		LeadId:        result.LeadId,
+		AccountId:     result.AccountId,
+		ContactId:     result.ContactId,
+		OpportunityId: result.OpportunityId,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_ConvertLead(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<convertLeadResponse><result>`+
+		`<accountId>001000000000001AAA</accountId><contactId>003000000000001AAA</contactId>`+
+		`<leadId>00Q000000000001AAA</leadId><opportunityId>006000000000001AAA</opportunityId>`+
+		`<success>true</success></result></convertLeadResponse>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	failServer, _ := setupSoapTestServer(t, http.StatusOK, `<convertLeadResponse><result>`+
+		`<errors><message>Converted status is invalid</message>`+
+		`<statusCode>INVALID_STATUS</statusCode></errors>`+
+		`<leadId>00Q000000000001AAA</leadId><success>false</success></result>`+
+		`</convertLeadResponse>`)
+	defer failServer.Close()
+	sfFail := buildSalesforceStruct(
+		&authentication{InstanceUrl: failServer.URL, AccessToken: "1234"},
+	)
+
+	tests := []struct {
+		name    string
+		sf      *Salesforce
+		request ConvertLeadRequest
+		want    ConvertLeadResult
+		wantErr bool
+	}{
+		{
+			name: "convert",
+			sf:   sf,
+			request: ConvertLeadRequest{
+				LeadId:          "00Q000000000001AAA",
+				ConvertedStatus: "Closed - Converted",
+				OpportunityName: "Acme - Widgets",
+			},
+			want: ConvertLeadResult{
+				LeadId:        "00Q000000000001AAA",
+				AccountId:     "001000000000001AAA",
+				ContactId:     "003000000000001AAA",
+				OpportunityId: "006000000000001AAA",
+			},
+		},
+		{
+			name:    "missing_status",
+			sf:      sf,
+			request: ConvertLeadRequest{LeadId: "00Q000000000001AAA"},
+			wantErr: true,
+		},
+		{
+			name: "contact_without_account",
+			sf:   sf,
+			request: ConvertLeadRequest{
+				LeadId:          "00Q000000000001AAA",
+				ConvertedStatus: "Closed - Converted",
+				ContactId:       "003000000000001AAA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected",
+			sf:   sfFail,
+			request: ConvertLeadRequest{
+				LeadId:          "00Q000000000001AAA",
+				ConvertedStatus: "Open",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sf.ConvertLead(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertLead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertLead() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if !strings.Contains((*requests)[0], "<opportunityName>Acme - Widgets</opportunityName>") {
+		t.Errorf("request = %s", (*requests)[0])
+	}
+	_, err := sfFail.ConvertLead(
+		ConvertLeadRequest{LeadId: "00Q000000000001AAA", ConvertedStatus: "Open"},
+	)
+	var recordErr RecordError
+	if !errors.As(err, &recordErr) || recordErr.Errors[0].ErrorCode != "INVALID_STATUS" {
+		t.Errorf("ConvertLead() error = %v, want a RecordError", err)
+	}
+}
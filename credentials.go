@@ -0,0 +1,105 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// credentialProviderAttempts is how many times credentials are fetched from a CredentialProvider when
+// Salesforce rejects them, as a rotated secret may be read just before it is replaced
+const credentialProviderAttempts = 3
+
+// CredentialProvider returns the credentials to authenticate with, such as secrets read from Vault or
+// AWS Secrets Manager. With WithCredentialProvider, it is consulted each time the client authenticates,
+// including session refreshes, so secrets can rotate without rebuilding the client.
+type CredentialProvider interface {
+	// GetCreds returns the current credentials
+	GetCreds(ctx context.Context) (Creds, error)
+}
+
+// CredentialProviderFunc is a function that implements CredentialProvider
+type CredentialProviderFunc func(ctx context.Context) (Creds, error)
+
+// GetCreds returns f(ctx)
+func (f CredentialProviderFunc) GetCreds(ctx context.Context) (Creds, error) {
+	return f(ctx)
+}
+
+// authenticationError is returned when a token endpoint rejects a request
+type authenticationError struct {
+	status     string
+	statusCode int
+}
+
+func (e *authenticationError) Error() string {
+	return e.status + ": failed authentication"
+}
+
+// isInvalidCredentialError returns whether Salesforce rejected credentials, rather than failing to
+// respond, so that fetching them again may succeed
+func isInvalidCredentialError(err error) bool {
+	var authErr *authenticationError
+	if errors.As(err, &authErr) {
+		return authErr.statusCode == http.StatusBadRequest ||
+			authErr.statusCode == http.StatusUnauthorized
+	}
+	var fault *SoapFault
+	return errors.As(err, &fault) && fault.Code == "INVALID_LOGIN"
+}
+
+// authenticateWithProvider authenticates with the credentials of a provider, fetching them again if
+// Salesforce rejects them
+func authenticateWithProvider(
+	config *configuration,
+	provider CredentialProvider,
+) (*authentication, AuthFlowType, error) {
+	var err error
+	for attempt := 0; attempt < credentialProviderAttempts; attempt++ {
+		creds, credsErr := provider.GetCreds(context.Background())
+		if credsErr != nil {
+			return nil, AuthFlowUnknown, fmt.Errorf("getting credentials: %w", credsErr)
+		}
+		if creds == (Creds{}) {
+			return nil, AuthFlowUnknown, errors.New("credential provider returned empty creds")
+		}
+		var auth *authentication
+		var authFlow AuthFlowType
+		auth, authFlow, err = authenticate(config, creds)
+		if err == nil {
+			auth.credentialProvider = provider
+			return auth, authFlow, nil
+		}
+		if !isInvalidCredentialError(err) {
+			return nil, authFlow, err
+		}
+	}
+	return nil, AuthFlowUnknown, err
+}
+
+// refreshSessionWithProvider refreshes the session with the current credentials of its provider,
+// fetching them again if Salesforce rejects them. Sessions created with an access token take the
+// provider's current access token.
+func refreshSessionWithProvider(auth *authentication, config *configuration) error {
+	var err error
+	for attempt := 0; attempt < credentialProviderAttempts; attempt++ {
+		creds, credsErr := auth.credentialProvider.GetCreds(context.Background())
+		if credsErr != nil {
+			return fmt.Errorf("getting credentials: %w", credsErr)
+		}
+		auth.creds = creds
+		if auth.grantType == grantTypeAccessToken {
+			if creds.AccessToken == "" {
+				return errors.New("credential provider returned no access token")
+			}
+			auth.AccessToken = creds.AccessToken
+			return nil
+		}
+		err = refreshSessionWithCreds(auth, config)
+		if !isInvalidCredentialError(err) {
+			return err
+		}
+	}
+	return err
+}
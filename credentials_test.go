@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newRotatingSecretServer returns a server whose token endpoint accepts only the client secret in
+// secret, returning tokens named after it, and whose query endpoint accepts only the latest token
+func newRotatingSecretServer(secret *atomic.Value, tokenRequests *atomic.Int32) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := secret.Load().(string)
+		if r.URL.Path == "/services/oauth2/token" {
+			tokenRequests.Add(1)
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("client_secret") != current {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+				return
+			}
+			body, _ := json.Marshal(authentication{
+				AccessToken: "token-" + current,
+				InstanceUrl: server.URL,
+			})
+			_, _ = w.Write(body)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-"+current {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write(
+				[]byte(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired"}]`),
+			)
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	}))
+	return server
+}
+
+func TestInit_credentialProvider(t *testing.T) {
+	secret := &atomic.Value{}
+	secret.Store("new")
+	tokenRequests := &atomic.Int32{}
+	server := newRotatingSecretServer(secret, tokenRequests)
+	defer server.Close()
+
+	tests := []struct {
+		name              string
+		secrets           []string // returned by the provider in turn, repeating the last
+		providerErr       error
+		creds             Creds
+		wantErr           bool
+		wantTokenRequests int32
+	}{
+		{
+			name:              "current_secret",
+			secrets:           []string{"new"},
+			wantTokenRequests: 1,
+		},
+		{
+			name:              "rotated_secret",
+			secrets:           []string{"old", "new"},
+			wantTokenRequests: 2,
+		},
+		{
+			name:              "rejected_secret",
+			secrets:           []string{"old"},
+			wantErr:           true,
+			wantTokenRequests: credentialProviderAttempts,
+		},
+		{
+			name:        "provider_error",
+			providerErr: errors.New("vault is sealed"),
+			wantErr:     true,
+		},
+		{
+			name:    "creds_and_provider",
+			secrets: []string{"new"},
+			creds:   Creds{AccessToken: "1234"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRequests.Store(0)
+			calls := 0
+			provider := CredentialProviderFunc(func(ctx context.Context) (Creds, error) {
+				if tt.providerErr != nil {
+					return Creds{}, tt.providerErr
+				}
+				secret := tt.secrets[min(calls, len(tt.secrets)-1)]
+				calls++
+				return Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: secret}, nil
+			})
+			sf, err := Init(tt.creds, WithCredentialProvider(provider))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tokenRequests.Load(); got != tt.wantTokenRequests {
+				t.Errorf("token requests = %d, want %d", got, tt.wantTokenRequests)
+			}
+			if err == nil && sf.auth.AccessToken != "token-new" {
+				t.Errorf("AccessToken = %v, want token-new", sf.auth.AccessToken)
+			}
+		})
+	}
+}
+
+func TestCredentialProvider_refresh(t *testing.T) {
+	secret := &atomic.Value{}
+	secret.Store("old")
+	tokenRequests := &atomic.Int32{}
+	server := newRotatingSecretServer(secret, tokenRequests)
+	defer server.Close()
+
+	current := &atomic.Value{}
+	current.Store("old")
+	provider := CredentialProviderFunc(func(ctx context.Context) (Creds, error) {
+		return Creds{
+			Domain:         server.URL,
+			ConsumerKey:    "key",
+			ConsumerSecret: current.Load().(string),
+		}, nil
+	})
+	sf, err := Init(Creds{}, WithCredentialProvider(provider))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	// the secret rotates, expiring the session
+	secret.Store("new")
+	current.Store("new")
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if sf.auth.AccessToken != "token-new" || sf.auth.creds.ConsumerSecret != "new" {
+		t.Errorf("session = %v, %v, want the rotated secret", sf.auth.AccessToken, sf.auth.creds)
+	}
+}
+
+func TestCredentialProvider_refreshAccessToken(t *testing.T) {
+	token := &atomic.Value{}
+	token.Store("first")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write(
+				[]byte(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired"}]`),
+			)
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	}))
+	defer server.Close()
+
+	provider := CredentialProviderFunc(func(ctx context.Context) (Creds, error) {
+		return Creds{Domain: server.URL, AccessToken: token.Load().(string)}, nil
+	})
+	sf, err := Init(Creds{}, WithCredentialProvider(provider))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	// the token is replaced, expiring the session
+	token.Store("rotated")
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if sf.auth.AccessToken != "rotated" {
+		t.Errorf("AccessToken = %v, want rotated", sf.auth.AccessToken)
+	}
+}
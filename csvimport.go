@@ -0,0 +1,174 @@
+package salesforce
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportMapping configures how the rows of a CSV file are loaded into an sObject
+type ImportMapping struct {
+	SObjectName     string        // sObject the records are upserted into
+	ExternalIdField string        // field records are matched on, such as Id or an external id field
+	Fields          []ImportField // fields set on each record
+	UseBulk         bool          // upsert with a Bulk API 2.0 job instead of composite requests
+	BatchSize       int           // records per composite request or bulk job
+	AllOrNone       bool          // roll back a composite request when any record fails
+}
+
+// ImportField maps a CSV column, or a constant, to a field of the imported records
+type ImportField struct {
+	Field     string                          // API name of the field, or the relationship name for a lookup
+	Column    string                          // CSV column to read the value from
+	Constant  any                             // value set on every record instead of a column
+	Transform func(value string) (any, error) // optional conversion of the column value
+	// LookupField is the external id field of the related sObject. When set, the value is used to
+	// find the related record, and Field must be the relationship name, such as Account.
+	LookupField string
+}
+
+// ImportResult is the outcome of an import
+type ImportResult struct {
+	Rows    int               // number of CSV rows read
+	Results SalesforceResults // results of each composite request, when not using bulk
+	JobIds  []string          // ids of the bulk jobs, when using bulk
+}
+
+// ImportCSV reads CSV data with a header row from r, builds a record from each row with the given
+// mapping, and upserts the records, either with composite requests or with Bulk API 2.0 jobs, which are
+// waited on. Empty cells without a transform, and transforms returning nil, leave the field unset.
+func (sf *Salesforce) ImportCSV(r io.Reader, mapping ImportMapping) (ImportResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ImportResult{}, authErr
+	}
+	if err := validateImportMapping(mapping); err != nil {
+		return ImportResult{}, err
+	}
+
+	reader := csv.NewReader(r)
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return ImportResult{}, errors.New("csv data has no header row")
+	}
+	if err != nil {
+		return ImportResult{}, err
+	}
+	headers[0] = strings.TrimPrefix(headers[0], "\ufeff")
+	columns := make(map[string]int, len(headers))
+	for i, header := range headers {
+		columns[header] = i
+	}
+	for _, field := range mapping.Fields {
+		if _, ok := columns[field.Column]; field.Column != "" && !ok {
+			return ImportResult{}, fmt.Errorf("csv data has no column %s", field.Column)
+		}
+	}
+
+	var records []map[string]any
+	for row := 2; ; row++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ImportResult{}, err
+		}
+		record, err := importRecord(mapping, columns, values)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("row %d: %w", row, err)
+		}
+		records = append(records, record)
+	}
+
+	result := ImportResult{Rows: len(records)}
+	if len(records) == 0 {
+		return result, nil
+	}
+	if mapping.UseBulk {
+		result.JobIds, err = sf.UpsertBulk(
+			mapping.SObjectName,
+			mapping.ExternalIdField,
+			records,
+			mapping.BatchSize,
+			true,
+		)
+		return result, err
+	}
+	result.Results, err = sf.UpsertComposite(
+		mapping.SObjectName,
+		mapping.ExternalIdField,
+		records,
+		mapping.BatchSize,
+		mapping.AllOrNone,
+	)
+	return result, err
+}
+
+func validateImportMapping(mapping ImportMapping) error {
+	if mapping.SObjectName == "" {
+		return errors.New("import mapping requires an sObject name")
+	}
+	if mapping.ExternalIdField == "" {
+		return errors.New("import mapping requires an external id field")
+	}
+	if len(mapping.Fields) == 0 {
+		return errors.New("import mapping requires at least one field")
+	}
+	fields := map[string]bool{}
+	for _, field := range mapping.Fields {
+		if field.Field == "" {
+			return errors.New("import field requires a field name")
+		}
+		if fields[strings.ToLower(field.Field)] {
+			return fmt.Errorf("duplicate import field: %s", field.Field)
+		}
+		fields[strings.ToLower(field.Field)] = true
+		if (field.Column == "") == (field.Constant == nil) {
+			return fmt.Errorf("import field %s requires either a column or a constant", field.Field)
+		}
+		if field.Transform != nil && field.Column == "" {
+			return fmt.Errorf("import field %s has a transform but no column", field.Field)
+		}
+	}
+	return nil
+}
+
+// importRecord builds the record of a CSV row
+func importRecord(
+	mapping ImportMapping,
+	columns map[string]int,
+	values []string,
+) (map[string]any, error) {
+	record := map[string]any{}
+	for _, field := range mapping.Fields {
+		value := field.Constant
+		if field.Column != "" {
+			cell := ""
+			if i := columns[field.Column]; i < len(values) {
+				cell = values[i]
+			}
+			value = nil
+			if cell != "" {
+				value = cell
+			}
+			if field.Transform != nil {
+				var err error
+				value, err = field.Transform(cell)
+				if err != nil {
+					return nil, fmt.Errorf("column %s: %w", field.Column, err)
+				}
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if field.LookupField != "" {
+			value = map[string]any{field.LookupField: value}
+		}
+		record[field.Field] = value
+	}
+	return record, nil
+}
@@ -0,0 +1,189 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_ImportCSV(t *testing.T) {
+	var records []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var compReq compositeRequest
+		if err := json.NewDecoder(r.Body).Decode(&compReq); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var results []SalesforceResult
+		for _, subReq := range compReq.CompositeRequest {
+			for _, record := range subReq.Body.Records {
+				delete(record, "attributes")
+				records = append(records, record)
+				results = append(results, SalesforceResult{Success: true})
+			}
+		}
+		body, _ := json.Marshal(compositeRequestResult{
+			CompositeResponse: []compositeSubRequestResult{{Body: results}},
+		})
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	mapping := ImportMapping{
+		SObjectName:     "Contact",
+		ExternalIdField: "External_Id__c",
+		BatchSize:       200,
+		Fields: []ImportField{
+			{Field: "External_Id__c", Column: "key"},
+			{Field: "LastName", Column: "last name"},
+			{
+				Field:  "NumberOfVisits__c",
+				Column: "visits",
+				Transform: func(value string) (any, error) {
+					if value == "" {
+						return nil, nil
+					}
+					return strconv.Atoi(value)
+				},
+			},
+			{Field: "Account", Column: "account", LookupField: "External_Key__c"},
+			{Field: "LeadSource", Constant: "Import"},
+		},
+	}
+
+	t.Run("upserts_mapped_records", func(t *testing.T) {
+		records = nil
+		data := "\ufeffkey,last name,visits,account,ignored\n" +
+			"C-1,Grimm,3,A-1,x\n" +
+			"C-2,Hood,,,y\n"
+		got, err := sf.ImportCSV(strings.NewReader(data), mapping)
+		if err != nil {
+			t.Fatalf("ImportCSV() error = %v", err)
+		}
+		if got.Rows != 2 || len(got.Results.Results) != 2 {
+			t.Errorf("ImportCSV() = %+v, want 2 rows and results", got)
+		}
+		want := []map[string]any{
+			{
+				"External_Id__c":    "C-1",
+				"LastName":          "Grimm",
+				"NumberOfVisits__c": float64(3),
+				"Account":           map[string]any{"External_Key__c": "A-1"},
+				"LeadSource":        "Import",
+			},
+			{
+				"External_Id__c": "C-2",
+				"LastName":       "Hood",
+				"LeadSource":     "Import",
+			},
+		}
+		if !reflect.DeepEqual(records, want) {
+			t.Errorf("ImportCSV() sent %v, want %v", records, want)
+		}
+	})
+
+	t.Run("no_rows", func(t *testing.T) {
+		records = nil
+		got, err := sf.ImportCSV(strings.NewReader("key,last name,visits,account\n"), mapping)
+		if err != nil || got.Rows != 0 || records != nil {
+			t.Errorf("ImportCSV() = %+v, %v, want no rows and no request", got, err)
+		}
+	})
+
+	t.Run("missing_column", func(t *testing.T) {
+		_, err := sf.ImportCSV(strings.NewReader("key,last name\nC-1,Grimm\n"), mapping)
+		if err == nil || !strings.Contains(err.Error(), "visits") {
+			t.Errorf("ImportCSV() error = %v, want missing column visits", err)
+		}
+	})
+
+	t.Run("transform_error", func(t *testing.T) {
+		failing := mapping
+		failing.Fields = []ImportField{
+			{Field: "External_Id__c", Column: "key"},
+			{
+				Field:     "LastName",
+				Column:    "last name",
+				Transform: func(string) (any, error) { return nil, errors.New("bad name") },
+			},
+		}
+		_, err := sf.ImportCSV(strings.NewReader("key,last name\nC-1,Grimm\n"), failing)
+		if err == nil || err.Error() != "row 2: column last name: bad name" {
+			t.Errorf("ImportCSV() error = %v", err)
+		}
+	})
+}
+
+func Test_validateImportMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping ImportMapping
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			mapping: ImportMapping{
+				SObjectName:     "Account",
+				ExternalIdField: "Id",
+				Fields:          []ImportField{{Field: "Name", Column: "name"}},
+			},
+		},
+		{
+			name: "missing_external_id_field",
+			mapping: ImportMapping{
+				SObjectName: "Account",
+				Fields:      []ImportField{{Field: "Name", Column: "name"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "column_and_constant",
+			mapping: ImportMapping{
+				SObjectName:     "Account",
+				ExternalIdField: "Id",
+				Fields:          []ImportField{{Field: "Name", Column: "name", Constant: "Acme"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_field",
+			mapping: ImportMapping{
+				SObjectName:     "Account",
+				ExternalIdField: "Id",
+				Fields: []ImportField{
+					{Field: "Name", Column: "name"},
+					{Field: "name", Constant: "Acme"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "transform_without_column",
+			mapping: ImportMapping{
+				SObjectName:     "Account",
+				ExternalIdField: "Id",
+				Fields: []ImportField{
+					{
+						Field:     "Name",
+						Constant:  "Acme",
+						Transform: func(value string) (any, error) { return value, nil },
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateImportMapping(tt.mapping); (err != nil) != tt.wantErr {
+				t.Errorf("validateImportMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
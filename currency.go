@@ -0,0 +1,91 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CurrencyIsoCodeField is the field holding the currency of a record in multi-currency orgs
+const CurrencyIsoCodeField = "CurrencyIsoCode"
+
+const currencyTypeQuery = "SELECT IsoCode, ConversionRate, DecimalPlaces, IsCorporate, IsActive " +
+	"FROM CurrencyType WHERE IsActive = true"
+
+// CurrencyType is an active currency of a multi-currency org
+type CurrencyType struct {
+	IsoCode        string
+	ConversionRate float64 // rate relative to the corporate currency
+	DecimalPlaces  int
+	IsCorporate    bool
+	IsActive       bool
+}
+
+// CurrencyRates holds the active currencies of an org keyed by iso code
+type CurrencyRates map[string]CurrencyType
+
+// Corporate returns the corporate currency of the org
+func (r CurrencyRates) Corporate() (CurrencyType, bool) {
+	for _, currency := range r {
+		if currency.IsCorporate {
+			return currency, true
+		}
+	}
+	return CurrencyType{}, false
+}
+
+// Convert converts an amount between two currencies using the static conversion rates, rounding the
+// result to the decimal places of the target currency
+func (r CurrencyRates) Convert(
+	amount float64,
+	fromIsoCode string,
+	toIsoCode string,
+) (float64, error) {
+	from, ok := r[fromIsoCode]
+	if !ok {
+		return 0, fmt.Errorf("unknown or inactive currency: %s", fromIsoCode)
+	}
+	to, ok := r[toIsoCode]
+	if !ok {
+		return 0, fmt.Errorf("unknown or inactive currency: %s", toIsoCode)
+	}
+	if from.ConversionRate == 0 {
+		return 0, fmt.Errorf("currency %s has no conversion rate", fromIsoCode)
+	}
+
+	converted := amount / from.ConversionRate * to.ConversionRate
+	scale := math.Pow(10, float64(to.DecimalPlaces))
+	return math.Round(converted*scale) / scale, nil
+}
+
+// RecordCurrency returns the currency of a queried record, or the corporate currency if the record has
+// no CurrencyIsoCode (ex: the org does not have multiple currencies enabled)
+func (r CurrencyRates) RecordCurrency(record map[string]any) (string, error) {
+	if isoCode, ok := record[CurrencyIsoCodeField].(string); ok && isoCode != "" {
+		return isoCode, nil
+	}
+	corporate, ok := r.Corporate()
+	if !ok {
+		return "", errors.New("record has no currency and no corporate currency is defined")
+	}
+	return corporate.IsoCode, nil
+}
+
+// GetCurrencyRates queries the active currencies of a multi-currency org and their static
+// conversion rates
+func (sf *Salesforce) GetCurrencyRates() (CurrencyRates, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	currencies := []CurrencyType{}
+	if err := performQuery(sf, currencyTypeQuery, &currencies); err != nil {
+		return nil, err
+	}
+	rates := CurrencyRates{}
+	for _, currency := range currencies {
+		rates[currency.IsoCode] = currency
+	}
+	return rates, nil
+}
@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testCurrencyRates = CurrencyRates{
+	"USD": {IsoCode: "USD", ConversionRate: 1, DecimalPlaces: 2, IsCorporate: true, IsActive: true},
+	"EUR": {IsoCode: "EUR", ConversionRate: 0.9, DecimalPlaces: 2, IsActive: true},
+	"JPY": {IsoCode: "JPY", ConversionRate: 150, DecimalPlaces: 0, IsActive: true},
+}
+
+func TestCurrencyRates_Convert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		from    string
+		to      string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:    "corporate_to_foreign",
+			amount:  100,
+			from:    "USD",
+			to:      "EUR",
+			want:    90,
+			wantErr: false,
+		},
+		{
+			name:    "foreign_to_foreign_rounded",
+			amount:  10,
+			from:    "EUR",
+			to:      "JPY",
+			want:    1667,
+			wantErr: false,
+		},
+		{
+			name:    "same_currency",
+			amount:  12.345,
+			from:    "USD",
+			to:      "USD",
+			want:    12.35,
+			wantErr: false,
+		},
+		{
+			name:    "unknown_currency",
+			amount:  10,
+			from:    "GBP",
+			to:      "USD",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testCurrencyRates.Convert(tt.amount, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CurrencyRates.Convert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CurrencyRates.Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyRates_RecordCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   CurrencyRates
+		record  map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "record_currency",
+			rates:   testCurrencyRates,
+			record:  map[string]any{"Amount": 10.0, "CurrencyIsoCode": "EUR"},
+			want:    "EUR",
+			wantErr: false,
+		},
+		{
+			name:    "corporate_currency",
+			rates:   testCurrencyRates,
+			record:  map[string]any{"Amount": 10.0},
+			want:    "USD",
+			wantErr: false,
+		},
+		{
+			name:    "no_corporate_currency",
+			rates:   CurrencyRates{},
+			record:  map[string]any{"Amount": 10.0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rates.RecordCurrency(tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CurrencyRates.RecordCurrency() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CurrencyRates.RecordCurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetCurrencyRates(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 2,
+		Done:      true,
+		Records: []map[string]any{
+			{
+				"IsoCode":        "USD",
+				"ConversionRate": 1,
+				"DecimalPlaces":  2,
+				"IsCorporate":    true,
+				"IsActive":       true,
+			},
+			{
+				"IsoCode":        "EUR",
+				"ConversionRate": 0.9,
+				"DecimalPlaces":  2,
+				"IsCorporate":    false,
+				"IsActive":       true,
+			},
+		},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    CurrencyRates
+		wantErr bool
+	}{
+		{
+			name: "get_currency_rates",
+			auth: &sfAuth,
+			want: CurrencyRates{
+				"USD": testCurrencyRates["USD"],
+				"EUR": testCurrencyRates["EUR"],
+			},
+			wantErr: false,
+		},
+		{
+			name:    "bad_request",
+			auth:    &badSfAuth,
+			wantErr: true,
+		},
+		{
+			name:    "validation_fail_auth",
+			auth:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			got, err := sf.GetCurrencyRates()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.GetCurrencyRates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.GetCurrencyRates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const customMetadataSuffix = "__mdt"
+
+// queryCustomMetadataRecords runs a query through the custom metadata cache
+func queryCustomMetadataRecords(sf *Salesforce, query string) ([]map[string]any, error) {
+	if cached, ok := sf.config.customMetadataCache.get(query); ok {
+		return cached.([]map[string]any), nil
+	}
+	records, err := queryAllRecords(context.Background(), sf, query)
+	if err != nil {
+		return nil, err
+	}
+	sf.config.customMetadataCache.set(query, records)
+	return records, nil
+}
+
+func customMetadataQuery(typeName string, records any) (string, error) {
+	if !strings.HasSuffix(typeName, customMetadataSuffix) {
+		return "", fmt.Errorf(
+			"custom metadata type name must end with %s: %s",
+			customMetadataSuffix,
+			typeName,
+		)
+	}
+	fields := soqlFieldNames(reflect.TypeOf(records))
+	if len(fields) == 0 {
+		return "", errors.New(
+			"expected a pointer to a struct or slice of structs with at least one field",
+		)
+	}
+	return "SELECT " + strings.Join(fields, ", ") + " FROM " + typeName, nil
+}
+
+// QueryCustomMetadata queries all records of a Custom Metadata Type into a slice of structs. The fields
+// to query are taken from the struct, honoring salesforce struct tags. Results are cached for the
+// duration set by WithCustomMetadataCacheTTL.
+func (sf *Salesforce) QueryCustomMetadata(typeName string, records any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	query, err := customMetadataQuery(typeName, records)
+	if err != nil {
+		return err
+	}
+
+	results, err := queryCustomMetadataRecords(sf, query)
+	if err != nil {
+		return err
+	}
+	return mapstructureDecode(results, records)
+}
+
+// GetCustomMetadata retrieves a single Custom Metadata Type record by DeveloperName into a struct
+func (sf *Salesforce) GetCustomMetadata(typeName string, developerName string, record any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	query, err := customMetadataQuery(typeName, record)
+	if err != nil {
+		return err
+	}
+	query = query + " WHERE DeveloperName = '" + escapeSoqlString(developerName) + "' LIMIT 1"
+
+	results, err := queryCustomMetadataRecords(sf, query)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("%s record not found: %s", typeName, developerName)
+	}
+	return mapstructureDecode(results[0], record)
+}
+
+// GetHierarchySetting retrieves the effective values of a hierarchy custom setting for a user, the same
+// as getInstance() in Apex: fields not set at the user level are inherited from the user's profile,
+// then from the org default. If userId is empty, the authenticated user is used.
+func (sf *Salesforce) GetHierarchySetting(settingName string, userId string, setting any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	fields := soqlFieldNames(reflect.TypeOf(setting))
+	if len(fields) == 0 {
+		return errors.New("expected a pointer to a struct with at least one field")
+	}
+
+	orgId, currentUserId, err := identityIds(sf.auth)
+	if err != nil {
+		return err
+	}
+	if userId == "" {
+		userId = currentUserId
+	}
+	if !salesforceIdPattern.MatchString(userId) {
+		return fmt.Errorf("invalid user id: %s", userId)
+	}
+
+	users, err := queryCustomMetadataRecords(
+		sf,
+		"SELECT ProfileId FROM User WHERE Id = '"+escapeSoqlString(userId)+"'",
+	)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("user not found: %s", userId)
+	}
+	profileId, _ := users[0]["ProfileId"].(string)
+
+	query := "SELECT SetupOwnerId, " + strings.Join(fields, ", ") + " FROM " + settingName +
+		" WHERE SetupOwnerId IN ('" + escapeSoqlString(userId) + "', '" +
+		escapeSoqlString(profileId) + "', '" + escapeSoqlString(orgId) + "')"
+	levels, err := queryCustomMetadataRecords(sf, query)
+	if err != nil {
+		return err
+	}
+
+	merged := mergeHierarchySettings(levels, []string{userId, profileId, orgId})
+	return mapstructureDecode(merged, setting)
+}
+
+// mergeHierarchySettings merges setting records so that each field takes the value of the most
+// specific level that sets it
+func mergeHierarchySettings(levels []map[string]any, ownerIds []string) map[string]any {
+	merged := map[string]any{}
+	for i := len(ownerIds) - 1; i >= 0; i-- {
+		for _, level := range levels {
+			ownerId, _ := level["SetupOwnerId"].(string)
+			if !sameSalesforceId(ownerId, ownerIds[i]) {
+				continue
+			}
+			for field, value := range level {
+				if value != nil && field != "attributes" {
+					merged[field] = value
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// sameSalesforceId compares ids ignoring the 3 character checksum suffix of 18 character ids
+func sameSalesforceId(a string, b string) bool {
+	if len(a) < 15 || len(b) < 15 {
+		return a == b
+	}
+	return a[:15] == b[:15]
+}
+
+// identityIds returns the org and user ids from the identity url of the session
+func identityIds(auth *authentication) (string, string, error) {
+	parts := strings.Split(strings.TrimSuffix(auth.Id, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", errors.New("session has no identity url, unable to determine the user")
+	}
+	orgId, userId := parts[len(parts)-2], parts[len(parts)-1]
+	if !salesforceIdPattern.MatchString(orgId) || !salesforceIdPattern.MatchString(userId) {
+		return "", "", fmt.Errorf("invalid identity url: %s", auth.Id)
+	}
+	return orgId, userId, nil
+}
+
+// ClearCustomMetadataCache removes all cached custom metadata and custom setting records
+func (sf *Salesforce) ClearCustomMetadataCache() {
+	sf.config.customMetadataCache.clear()
+}
@@ -0,0 +1,181 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFeatureFlag struct {
+	DeveloperName string
+	Enabled       bool   `salesforce:"Enabled__c"`
+	Rollout       string `salesforce:"Rollout__c"`
+}
+
+func Test_customMetadataQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		records  any
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "query_from_struct_slice",
+			typeName: "Feature_Flag__mdt",
+			records:  &[]testFeatureFlag{},
+			want:     "SELECT DeveloperName, Enabled__c, Rollout__c FROM Feature_Flag__mdt",
+			wantErr:  false,
+		},
+		{
+			name:     "not_custom_metadata",
+			typeName: "Account",
+			records:  &[]testFeatureFlag{},
+			wantErr:  true,
+		},
+		{
+			name:     "not_a_struct",
+			typeName: "Feature_Flag__mdt",
+			records:  &[]map[string]any{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := customMetadataQuery(tt.typeName, tt.records)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("customMetadataQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("customMetadataQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryCustomMetadata(t *testing.T) {
+	requests := 0
+	body, _ := json.Marshal(queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{
+			{"DeveloperName": "NewCheckout", "Enabled__c": true, "Rollout__c": "Beta"},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "token"})
+
+	want := []testFeatureFlag{{DeveloperName: "NewCheckout", Enabled: true, Rollout: "Beta"}}
+	for i := 0; i < 2; i++ {
+		got := []testFeatureFlag{}
+		if err := sf.QueryCustomMetadata("Feature_Flag__mdt", &got); err != nil {
+			t.Fatalf("Salesforce.QueryCustomMetadata() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Salesforce.QueryCustomMetadata() = %v, want %v", got, want)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Salesforce.QueryCustomMetadata() made %d requests, want 1 (cached)", requests)
+	}
+
+	sf.ClearCustomMetadataCache()
+	single := testFeatureFlag{}
+	if err := sf.GetCustomMetadata("Feature_Flag__mdt", "NewCheckout", &single); err != nil {
+		t.Fatalf("Salesforce.GetCustomMetadata() error = %v", err)
+	}
+	if !reflect.DeepEqual(single, want[0]) {
+		t.Errorf("Salesforce.GetCustomMetadata() = %v, want %v", single, want[0])
+	}
+	if requests != 2 {
+		t.Errorf("Salesforce.GetCustomMetadata() made %d requests, want 2", requests)
+	}
+
+	unauthenticated := buildSalesforceStruct(nil)
+	if err := unauthenticated.QueryCustomMetadata("Feature_Flag__mdt", &[]testFeatureFlag{}); err == nil {
+		t.Errorf("Salesforce.QueryCustomMetadata() expected authentication error")
+	}
+}
+
+func TestSalesforce_GetHierarchySetting(t *testing.T) {
+	type integrationSettings struct {
+		Endpoint string `salesforce:"Endpoint__c"`
+		Timeout  int    `salesforce:"Timeout__c"`
+		Debug    bool   `salesforce:"Debug__c"`
+	}
+	const (
+		orgId     = "00Dxx0000001gEREAY"
+		userId    = "005xx000001SwR6AAK"
+		profileId = "00exx000000j7UUAAY"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		resp := queryResponse{Done: true}
+		if strings.Contains(query, "FROM User") {
+			resp.Records = []map[string]any{{"ProfileId": profileId}}
+		} else {
+			resp.Records = []map[string]any{
+				{"SetupOwnerId": orgId, "Endpoint__c": "https://org", "Timeout__c": 30, "Debug__c": false},
+				{"SetupOwnerId": profileId, "Endpoint__c": nil, "Timeout__c": 60, "Debug__c": nil},
+				{"SetupOwnerId": userId, "Endpoint__c": nil, "Timeout__c": nil, "Debug__c": true},
+			}
+		}
+		body, _ := json.Marshal(resp)
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    integrationSettings
+		wantErr bool
+	}{
+		{
+			name: "merge_hierarchy_levels",
+			auth: &authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "token",
+				Id:          "https://login.salesforce.com/id/" + orgId + "/" + userId,
+			},
+			want:    integrationSettings{Endpoint: "https://org", Timeout: 60, Debug: true},
+			wantErr: false,
+		},
+		{
+			name: "missing_identity",
+			auth: &authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "token",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			sf.config.customMetadataCache = newTTLCache(time.Minute)
+			got := integrationSettings{}
+			err := sf.GetHierarchySetting("Integration_Settings__c", "", &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.GetHierarchySetting() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.GetHierarchySetting() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
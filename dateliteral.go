@@ -0,0 +1,120 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLiteral is a SOQL date literal, such as TODAY or LAST_N_DAYS:30, which Salesforce evaluates in
+// the time zone of the user running the query. Bound as a named parameter of QueryNamed, it is written
+// as is rather than quoted.
+type DateLiteral string
+
+// Date literals for relative periods. Fiscal periods follow the fiscal year of the org.
+const (
+	DateYesterday         DateLiteral = "YESTERDAY"
+	DateToday             DateLiteral = "TODAY"
+	DateTomorrow          DateLiteral = "TOMORROW"
+	DateLastWeek          DateLiteral = "LAST_WEEK"
+	DateThisWeek          DateLiteral = "THIS_WEEK"
+	DateNextWeek          DateLiteral = "NEXT_WEEK"
+	DateLastMonth         DateLiteral = "LAST_MONTH"
+	DateThisMonth         DateLiteral = "THIS_MONTH"
+	DateNextMonth         DateLiteral = "NEXT_MONTH"
+	DateLast90Days        DateLiteral = "LAST_90_DAYS"
+	DateNext90Days        DateLiteral = "NEXT_90_DAYS"
+	DateLastQuarter       DateLiteral = "LAST_QUARTER"
+	DateThisQuarter       DateLiteral = "THIS_QUARTER"
+	DateNextQuarter       DateLiteral = "NEXT_QUARTER"
+	DateLastYear          DateLiteral = "LAST_YEAR"
+	DateThisYear          DateLiteral = "THIS_YEAR"
+	DateNextYear          DateLiteral = "NEXT_YEAR"
+	DateLastFiscalQuarter DateLiteral = "LAST_FISCAL_QUARTER"
+	DateThisFiscalQuarter DateLiteral = "THIS_FISCAL_QUARTER"
+	DateNextFiscalQuarter DateLiteral = "NEXT_FISCAL_QUARTER"
+	DateLastFiscalYear    DateLiteral = "LAST_FISCAL_YEAR"
+	DateThisFiscalYear    DateLiteral = "THIS_FISCAL_YEAR"
+	DateNextFiscalYear    DateLiteral = "NEXT_FISCAL_YEAR"
+)
+
+// DateUnit is the unit of a date literal that spans a number of periods, such as LAST_N_DAYS:n
+type DateUnit string
+
+const (
+	DateUnitDays           DateUnit = "DAYS"
+	DateUnitWeeks          DateUnit = "WEEKS"
+	DateUnitMonths         DateUnit = "MONTHS"
+	DateUnitQuarters       DateUnit = "QUARTERS"
+	DateUnitYears          DateUnit = "YEARS"
+	DateUnitFiscalQuarters DateUnit = "FISCAL_QUARTERS"
+	DateUnitFiscalYears    DateUnit = "FISCAL_YEARS"
+)
+
+var dateLiteralPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*(:[0-9]+)?$`)
+
+// LastN returns the date literal for the past n periods of a unit, such as LAST_N_DAYS:30. For days,
+// it includes today.
+func LastN(unit DateUnit, n int) DateLiteral {
+	return DateLiteral("LAST_N_" + string(unit) + ":" + strconv.Itoa(n))
+}
+
+// NextN returns the date literal for the next n periods of a unit, such as NEXT_N_WEEKS:2. For days,
+// it excludes today.
+func NextN(unit DateUnit, n int) DateLiteral {
+	return DateLiteral("NEXT_N_" + string(unit) + ":" + strconv.Itoa(n))
+}
+
+// NAgo returns the date literal for the single period of a unit n periods ago, such as N_DAYS_AGO:3
+func NAgo(unit DateUnit, n int) DateLiteral {
+	return DateLiteral("N_" + string(unit) + "_AGO:" + strconv.Itoa(n))
+}
+
+// String returns the date literal as it is written in SOQL
+func (d DateLiteral) String() string {
+	return string(d)
+}
+
+// soqlLiteral returns the date literal after checking that it is a keyword with an optional count,
+// since it is written into queries unquoted
+func (d DateLiteral) soqlLiteral() (string, error) {
+	if !dateLiteralPattern.MatchString(string(d)) {
+		return "", fmt.Errorf("invalid date literal %q", string(d))
+	}
+	return string(d), nil
+}
+
+// DateRange is an explicit range of time, from Start inclusive to End exclusive, for filtering
+// datetime fields. Unlike date literals, it does not depend on the time zone of the user running the
+// query; see Localization for the ranges that date literals cover.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Where returns a SOQL condition that matches values of a datetime field within the range, with both
+// times converted to UTC, such as
+// CreatedDate >= 2024-01-31T05:00:00Z AND CreatedDate < 2024-02-01T05:00:00Z. A zero Start or End
+// leaves that side of the range open.
+func (r DateRange) Where(fieldName string) (string, error) {
+	if fieldName == "" {
+		return "", errors.New("field name is required")
+	}
+	if r.Start.IsZero() && r.End.IsZero() {
+		return "", errors.New("date range needs a start or an end")
+	}
+	if !r.Start.IsZero() && !r.End.IsZero() && !r.Start.Before(r.End) {
+		return "", errors.New("date range must start before it ends")
+	}
+	conditions := []string{}
+	if !r.Start.IsZero() {
+		conditions = append(conditions, fieldName+" >= "+FormatSOQLDateTime(r.Start))
+	}
+	if !r.End.IsZero() {
+		conditions = append(conditions, fieldName+" < "+FormatSOQLDateTime(r.End))
+	}
+	return strings.Join(conditions, " AND "), nil
+}
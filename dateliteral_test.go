@@ -0,0 +1,97 @@
+package salesforce
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal DateLiteral
+		want    string
+		wantErr bool
+	}{
+		{name: "constant", literal: DateThisFiscalQuarter, want: "THIS_FISCAL_QUARTER"},
+		{name: "last_n", literal: LastN(DateUnitDays, 30), want: "LAST_N_DAYS:30"},
+		{name: "next_n", literal: NextN(DateUnitFiscalYears, 2), want: "NEXT_N_FISCAL_YEARS:2"},
+		{name: "n_ago", literal: NAgo(DateUnitWeeks, 3), want: "N_WEEKS_AGO:3"},
+		{name: "negative", literal: LastN(DateUnitDays, -1), wantErr: true},
+		{name: "injection", literal: DateLiteral("TODAY) OR (Id != null"), wantErr: true},
+		{name: "empty", literal: DateLiteral(""), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.literal.soqlLiteral()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("soqlLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("soqlLiteral() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateRange_Where(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, newYork)
+	end := time.Date(2024, time.February, 1, 0, 0, 0, 0, newYork)
+
+	tests := []struct {
+		name      string
+		dateRange DateRange
+		fieldName string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "closed",
+			dateRange: DateRange{Start: start, End: end},
+			fieldName: "CreatedDate",
+			want:      "CreatedDate >= 2024-01-31T05:00:00Z AND CreatedDate < 2024-02-01T05:00:00Z",
+		},
+		{
+			name:      "open_end",
+			dateRange: DateRange{Start: start},
+			fieldName: "SystemModstamp",
+			want:      "SystemModstamp >= 2024-01-31T05:00:00Z",
+		},
+		{
+			name:      "open_start",
+			dateRange: DateRange{End: end},
+			fieldName: "CreatedDate",
+			want:      "CreatedDate < 2024-02-01T05:00:00Z",
+		},
+		{
+			name:      "empty",
+			dateRange: DateRange{},
+			fieldName: "CreatedDate",
+			wantErr:   true,
+		},
+		{
+			name:      "reversed",
+			dateRange: DateRange{Start: end, End: start},
+			fieldName: "CreatedDate",
+			wantErr:   true,
+		},
+		{
+			name:      "no_field",
+			dateRange: DateRange{Start: start, End: end},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dateRange.Where(tt.fieldName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Where() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Where() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
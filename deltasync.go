@@ -0,0 +1,316 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	deltaSyncOverlap           = 5 * time.Minute
+	deltaSyncReconcileInterval = 15 * time.Minute
+	deltaSyncExtractBatchSize  = 2000
+)
+
+// SyncEventType is the kind of change a SyncEvent mirrors
+type SyncEventType string
+
+const (
+	SyncEventUpsert SyncEventType = "UPSERT" // the record was created, updated, or undeleted
+	SyncEventDelete SyncEventType = "DELETE"
+)
+
+// SyncSource is what a SyncEvent was read from
+type SyncSource string
+
+const (
+	SyncSourceExtract     SyncSource = "EXTRACT"      // the initial extract
+	SyncSourceChangeEvent SyncSource = "CHANGE_EVENT" // a Change Data Capture event
+	SyncSourceReconcile   SyncSource = "RECONCILE"    // a reconciliation query
+)
+
+// SyncEvent is a change to a record to apply to a copy of the sObject, such as a cache or a warehouse
+// table. Upserts hold every synced field of the record, even for change events that only list the
+// changed fields. Events are delivered at least once, and applying an event again has no effect.
+type SyncEvent struct {
+	Type        SyncEventType
+	SObjectName string
+	Id          string
+	Record      map[string]any // fields of upserted records without attributes, nil for deletes
+	Source      SyncSource
+}
+
+// DeltaSyncSettings configures a DeltaSync
+type DeltaSyncSettings struct {
+	SObjectName       string
+	Fields            []string       // fields to sync, Id and SystemModstamp are always synced
+	Store             SyncStateStore // stores the high-water marks of reconciliation
+	Key               string         // names the states in the store (default the sObject name)
+	Overlap           time.Duration  // how far back reconciliation reads again (default 5 minutes)
+	ReconcileInterval time.Duration  // how often Run reconciles (default 15 minutes)
+	BulkExtract       bool           // extract with a Bulk API 2.0 query, whose values are strings
+}
+
+// DeltaSync mirrors the records of an sObject as a stream of SyncEvents, combining an initial extract,
+// the Change Data Capture events of the sObject, and periodic reconciliation queries that catch changes
+// whose events were missed, such as while the subscriber was down. Subscribe to the change events with
+// any CometD or Pub/Sub API client and pass them to HandleChangeEvent, or to Run.
+type DeltaSync struct {
+	sf       *Salesforce
+	settings DeltaSyncSettings
+	query    string
+	upserts  *IncrementalSync
+	deletes  *IncrementalSync
+}
+
+// NewDeltaSync returns a DeltaSync for an sObject
+func (sf *Salesforce) NewDeltaSync(settings DeltaSyncSettings) (*DeltaSync, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if settings.SObjectName == "" || settings.Store == nil {
+		return nil, errors.New("sObject name and state store are required")
+	}
+	if settings.Overlap < 0 || settings.ReconcileInterval < 0 {
+		return nil, errors.New("overlap and reconcile interval cannot be negative")
+	}
+	if settings.Key == "" {
+		settings.Key = settings.SObjectName
+	}
+	if settings.Overlap == 0 {
+		settings.Overlap = deltaSyncOverlap
+	}
+	if settings.ReconcileInterval == 0 {
+		settings.ReconcileInterval = deltaSyncReconcileInterval
+	}
+
+	fields := []string{"Id", "SystemModstamp"}
+	for _, field := range settings.Fields {
+		if !slices.ContainsFunc(
+			fields,
+			func(f string) bool { return strings.EqualFold(f, field) },
+		) {
+			fields = append(fields, field)
+		}
+	}
+	query := "SELECT " + strings.Join(fields, ", ") + " FROM " + settings.SObjectName
+	upserts, err := sf.NewIncrementalSync(query, settings.Key, settings.Store, settings.Overlap)
+	if err != nil {
+		return nil, err
+	}
+	deletes, err := sf.NewIncrementalSync(
+		"SELECT Id, SystemModstamp FROM "+settings.SObjectName+" WHERE IsDeleted = true",
+		settings.Key+"/deleted",
+		settings.Store,
+		settings.Overlap,
+	)
+	if err != nil {
+		return nil, err
+	}
+	deletes.resource = "/queryAll/"
+	return &DeltaSync{
+		sf:       sf,
+		settings: settings,
+		query:    query,
+		upserts:  upserts,
+		deletes:  deletes,
+	}, nil
+}
+
+// Run extracts every record if the store has no state for the sync, then handles the change events
+// received on changes and reconciles every reconcile interval until ctx is done or changes is closed
+func (d *DeltaSync) Run(
+	ctx context.Context,
+	changes <-chan ChangeEvent,
+	handle func(events []SyncEvent) error,
+) error {
+	if err := d.Extract(ctx, handle); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(d.settings.ReconcileInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if err := d.HandleChangeEvent(ctx, event, handle); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := d.Reconcile(ctx, handle); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Extract emits an upsert for every record of the sObject, in batches, unless the store already has
+// a state for the sync. Once every batch is handled, reconciliation starts from the time the extract
+// started.
+func (d *DeltaSync) Extract(ctx context.Context, handle func(events []SyncEvent) error) error {
+	_, found, err := d.settings.Store.GetSyncState(d.settings.Key)
+	if err != nil || found {
+		return err
+	}
+
+	start := time.Now()
+	writer := &syncEventWriter{sync: d, handle: handle}
+	if d.settings.BulkExtract {
+		if _, err := d.sf.ExportBulkQuery(d.query, writer); err != nil {
+			return err
+		}
+	} else {
+		nextRecordsUrl := "/query/?q=" + url.QueryEscape(d.query)
+		for nextRecordsUrl != "" {
+			queryResp, err := getQueryPage(ctx, d.sf, nextRecordsUrl)
+			if err != nil {
+				return err
+			}
+			for _, record := range queryResp.Records {
+				if err := writer.WriteRecord(record); err != nil {
+					return err
+				}
+			}
+			nextRecordsUrl = ""
+			if !queryResp.Done {
+				nextRecordsUrl = queryResp.NextRecordsUrl
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	state := SyncState{HighWaterMark: start.UTC()}
+	if err := d.settings.Store.SetSyncState(d.upserts.key, state); err != nil {
+		return err
+	}
+	return d.settings.Store.SetSyncState(d.deletes.key, state)
+}
+
+// HandleChangeEvent emits the changes of a Change Data Capture event of the sObject. Created, updated,
+// and undeleted records are queried for their synced fields, since events only hold changed fields.
+// Gap overflow events, which do not list their records, reconcile instead. Events of other sObjects are
+// ignored.
+func (d *DeltaSync) HandleChangeEvent(
+	ctx context.Context,
+	event ChangeEvent,
+	handle func(events []SyncEvent) error,
+) error {
+	d.sf.config.metrics.ObserveChangeEvent(event)
+	header := event.Header
+	if !strings.EqualFold(header.EntityName, d.settings.SObjectName) {
+		return nil
+	}
+	switch header.ChangeType {
+	case ChangeTypeGapOverflow:
+		return d.Reconcile(ctx, handle)
+	case ChangeTypeDelete, ChangeTypeGapDelete:
+		events := make([]SyncEvent, len(header.RecordIds))
+		for i, id := range header.RecordIds {
+			events[i] = SyncEvent{
+				Type:        SyncEventDelete,
+				SObjectName: d.settings.SObjectName,
+				Id:          id,
+				Source:      SyncSourceChangeEvent,
+			}
+		}
+		return handleSyncEvents(events, handle)
+	}
+
+	events := []SyncEvent{}
+	for ids := range slices.Chunk(header.RecordIds, recordCacheBatchSize) {
+		quoted := make([]string, len(ids))
+		for i, id := range ids {
+			quoted[i] = "'" + escapeSoqlString(id) + "'"
+		}
+		records, err := queryAllRecords(
+			ctx,
+			d.sf,
+			d.query+" WHERE Id IN ("+strings.Join(quoted, ", ")+")",
+		)
+		if err != nil {
+			return err
+		}
+		// records deleted since the event are left to their delete event or reconciliation
+		for _, record := range records {
+			events = append(events, d.upsertEvent(record, SyncSourceChangeEvent))
+		}
+	}
+	return handleSyncEvents(events, handle)
+}
+
+// Reconcile emits the records changed and deleted since the last reconciliation, or since the
+// extract, which catches changes whose Change Data Capture events were missed
+func (d *DeltaSync) Reconcile(ctx context.Context, handle func(events []SyncEvent) error) error {
+	_, err := d.upserts.Pull(ctx, func(records []map[string]any) error {
+		events := make([]SyncEvent, len(records))
+		for i, record := range records {
+			events[i] = d.upsertEvent(record, SyncSourceReconcile)
+		}
+		return handle(events)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = d.deletes.Pull(ctx, func(records []map[string]any) error {
+		events := make([]SyncEvent, len(records))
+		for i, record := range records {
+			events[i] = SyncEvent{
+				Type:        SyncEventDelete,
+				SObjectName: d.settings.SObjectName,
+				Id:          Record(record).Id(),
+				Source:      SyncSourceReconcile,
+			}
+		}
+		return handle(events)
+	})
+	return err
+}
+
+func (d *DeltaSync) upsertEvent(record map[string]any, source SyncSource) SyncEvent {
+	record = removeAttributes(record)
+	return SyncEvent{
+		Type:        SyncEventUpsert,
+		SObjectName: d.settings.SObjectName,
+		Id:          Record(record).Id(),
+		Record:      record,
+		Source:      source,
+	}
+}
+
+func handleSyncEvents(events []SyncEvent, handle func(events []SyncEvent) error) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return handle(events)
+}
+
+// syncEventWriter is a RecordWriter that emits the records of an extract as batches of upserts
+type syncEventWriter struct {
+	sync   *DeltaSync
+	handle func(events []SyncEvent) error
+	events []SyncEvent
+}
+
+func (w *syncEventWriter) WriteRecord(record map[string]any) error {
+	w.events = append(w.events, w.sync.upsertEvent(record, SyncSourceExtract))
+	if len(w.events) < deltaSyncExtractBatchSize {
+		return nil
+	}
+	return w.Close()
+}
+
+func (w *syncEventWriter) Close() error {
+	events := w.events
+	w.events = nil
+	return handleSyncEvents(events, w.handle)
+}
@@ -0,0 +1,185 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func setupDeltaSyncServer(queries *[]string) *httptest.Server {
+	var mu sync.Mutex
+	modstamp := time.Now().UTC().Add(time.Minute).Format(salesforceDateTimeFormat)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		query := r.URL.Query().Get("q")
+		*queries = append(*queries, r.URL.Path+" "+query)
+		response := queryResponse{Done: true, Records: []map[string]any{}}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/queryAll/"):
+			response.Records = []map[string]any{{"Id": "001D", "SystemModstamp": modstamp}}
+		case strings.Contains(query, "WHERE Id IN ('001B', '001C')"):
+			response.Records = []map[string]any{{
+				"attributes":     map[string]any{"type": "Account"},
+				"Id":             "001B",
+				"Name":           "Globex",
+				"SystemModstamp": modstamp,
+			}}
+		case strings.Contains(query, "SystemModstamp >="):
+			response.Records = []map[string]any{
+				{"Id": "001E", "Name": "Initech", "SystemModstamp": modstamp},
+			}
+		case !strings.Contains(query, "WHERE"):
+			response.Records = []map[string]any{
+				{"Id": "001A", "Name": "Acme", "SystemModstamp": "2024-01-01T10:00:00.000+0000"},
+			}
+		}
+		body, _ := json.Marshal(response)
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestDeltaSync(t *testing.T) {
+	queries := []string{}
+	server := setupDeltaSyncServer(&queries)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	store := NewMemorySyncStateStore()
+	deltaSync, err := sf.NewDeltaSync(DeltaSyncSettings{
+		SObjectName: "Account",
+		Fields:      []string{"Name", "id"},
+		Store:       store,
+	})
+	if err != nil {
+		t.Fatalf("NewDeltaSync() error = %v", err)
+	}
+	if deltaSync.query != "SELECT Id, SystemModstamp, Name FROM Account" {
+		t.Errorf("query = %s", deltaSync.query)
+	}
+
+	var events []SyncEvent
+	handle := func(batch []SyncEvent) error {
+		events = append(events, batch...)
+		return nil
+	}
+	changes := make(chan ChangeEvent, 4)
+	changes <- ChangeEvent{Header: ChangeEventHeader{
+		EntityName: "Account",
+		ChangeType: ChangeTypeUpdate,
+		RecordIds:  []string{"001B", "001C"},
+	}}
+	changes <- ChangeEvent{Header: ChangeEventHeader{
+		EntityName: "Contact",
+		ChangeType: ChangeTypeDelete,
+		RecordIds:  []string{"003A"},
+	}}
+	changes <- ChangeEvent{Header: ChangeEventHeader{
+		EntityName: "Account",
+		ChangeType: ChangeTypeDelete,
+		RecordIds:  []string{"001A"},
+	}}
+	changes <- ChangeEvent{Header: ChangeEventHeader{
+		EntityName: "Account",
+		ChangeType: ChangeTypeGapOverflow,
+	}}
+	close(changes)
+	if err := deltaSync.Run(context.Background(), changes, handle); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []SyncEvent{
+		{
+			Type:        SyncEventUpsert,
+			SObjectName: "Account",
+			Id:          "001A",
+			Record: map[string]any{
+				"Id":             "001A",
+				"Name":           "Acme",
+				"SystemModstamp": "2024-01-01T10:00:00.000+0000",
+			},
+			Source: SyncSourceExtract,
+		},
+		{Type: SyncEventUpsert, SObjectName: "Account", Id: "001B", Source: SyncSourceChangeEvent},
+		{Type: SyncEventDelete, SObjectName: "Account", Id: "001A", Source: SyncSourceChangeEvent},
+		{Type: SyncEventUpsert, SObjectName: "Account", Id: "001E", Source: SyncSourceReconcile},
+		{Type: SyncEventDelete, SObjectName: "Account", Id: "001D", Source: SyncSourceReconcile},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %+v", events, want)
+	}
+	for i := range want {
+		got := events[i]
+		if i > 0 {
+			if _, ok := got.Record["attributes"]; ok {
+				t.Errorf("event %d record has attributes", i)
+			}
+			got.Record = nil
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("event %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if events[1].Record["Name"] != "Globex" {
+		t.Errorf("change event upsert = %v, want the queried record", events[1].Record)
+	}
+
+	// a sync with a stored state does not extract again
+	queries = queries[:0]
+	if err := deltaSync.Extract(context.Background(), handle); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(queries) != 0 {
+		t.Errorf("Extract() sent %v, want no queries", queries)
+	}
+	if _, found, _ := store.GetSyncState("Account/deleted"); !found {
+		t.Errorf("deleted records state not stored")
+	}
+}
+
+func TestSalesforce_NewDeltaSync(t *testing.T) {
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example", AccessToken: "1234"},
+	)
+	store := NewMemorySyncStateStore()
+	tests := []struct {
+		name     string
+		settings DeltaSyncSettings
+		wantErr  bool
+	}{
+		{name: "defaults", settings: DeltaSyncSettings{SObjectName: "Account", Store: store}},
+		{name: "no_sObject", settings: DeltaSyncSettings{Store: store}, wantErr: true},
+		{name: "no_store", settings: DeltaSyncSettings{SObjectName: "Account"}, wantErr: true},
+		{
+			name: "negative_interval",
+			settings: DeltaSyncSettings{
+				SObjectName:       "Account",
+				Store:             store,
+				ReconcileInterval: -1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltaSync, err := sf.NewDeltaSync(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDeltaSync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			settings := deltaSync.settings
+			if settings.Key != "Account" || settings.Overlap != deltaSyncOverlap ||
+				settings.ReconcileInterval != deltaSyncReconcileInterval {
+				t.Errorf("NewDeltaSync() settings = %+v", settings)
+			}
+		})
+	}
+}
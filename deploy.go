@@ -0,0 +1,242 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Statuses of a Metadata API deployment
+const (
+	DeployStatusPending          = "Pending"
+	DeployStatusInProgress       = "InProgress"
+	DeployStatusSucceeded        = "Succeeded"
+	DeployStatusSucceededPartial = "SucceededPartial"
+	DeployStatusFailed           = "Failed"
+	DeployStatusCanceling        = "Canceling"
+	DeployStatusCanceled         = "Canceled"
+)
+
+// DeployResult is the status of a Metadata API deployment
+type DeployResult struct {
+	Id                       string        `json:"id"`
+	Status                   string        `json:"status"`
+	Success                  bool          `json:"success"`
+	Done                     bool          `json:"done"`
+	CheckOnly                bool          `json:"checkOnly"`
+	StateDetail              string        `json:"stateDetail"`
+	ErrorMessage             string        `json:"errorMessage"`
+	ErrorStatusCode          string        `json:"errorStatusCode"`
+	NumberComponentsDeployed int           `json:"numberComponentsDeployed"`
+	NumberComponentErrors    int           `json:"numberComponentErrors"`
+	NumberComponentsTotal    int           `json:"numberComponentsTotal"`
+	NumberTestsCompleted     int           `json:"numberTestsCompleted"`
+	NumberTestErrors         int           `json:"numberTestErrors"`
+	NumberTestsTotal         int           `json:"numberTestsTotal"`
+	Details                  DeployDetails `json:"details"`
+}
+
+// DeployDetails are the component and test results of a deployment
+type DeployDetails struct {
+	ComponentFailures []DeployMessage `json:"componentFailures"`
+	RunTestResult     RunTestResult   `json:"runTestResult"`
+}
+
+// DeployMessage is the result of deploying a single component
+type DeployMessage struct {
+	ComponentType string `json:"componentType"`
+	FullName      string `json:"fullName"`
+	FileName      string `json:"fileName"`
+	LineNumber    int    `json:"lineNumber"`
+	ColumnNumber  int    `json:"columnNumber"`
+	Problem       string `json:"problem"`
+	ProblemType   string `json:"problemType"`
+	Success       bool   `json:"success"`
+}
+
+func (m DeployMessage) String() string {
+	location := m.FileName
+	if m.LineNumber > 0 {
+		location += fmt.Sprintf(":%d:%d", m.LineNumber, m.ColumnNumber)
+	}
+	return fmt.Sprintf("%s %s (%s): %s", m.ComponentType, m.FullName, location, m.Problem)
+}
+
+// RunTestResult is the outcome of the Apex tests run during a deployment
+type RunTestResult struct {
+	NumTestsRun          int                   `json:"numTestsRun"`
+	NumFailures          int                   `json:"numFailures"`
+	TotalTime            float64               `json:"totalTime"`
+	Failures             []DeployTestFailure   `json:"failures"`
+	CodeCoverageWarnings []CodeCoverageWarning `json:"codeCoverageWarnings"`
+}
+
+// DeployTestFailure is an Apex test that failed during a deployment
+type DeployTestFailure struct {
+	Name       string `json:"name"`
+	MethodName string `json:"methodName"`
+	Message    string `json:"message"`
+	StackTrace string `json:"stackTrace"`
+}
+
+func (f DeployTestFailure) String() string {
+	return fmt.Sprintf("%s.%s: %s", f.Name, f.MethodName, f.Message)
+}
+
+// CodeCoverageWarning is a class or trigger without enough code coverage
+type CodeCoverageWarning struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// DeployError is returned by WaitForDeploy when a deployment does not succeed
+type DeployError struct {
+	Result DeployResult
+}
+
+func (e *DeployError) Error() string {
+	messages := []string{"deploy " + e.Result.Id + " " + strings.ToLower(e.Result.Status)}
+	if e.Result.ErrorMessage != "" {
+		messages = append(messages, e.Result.ErrorMessage)
+	}
+	for _, failure := range e.Result.Details.ComponentFailures {
+		messages = append(messages, failure.String())
+	}
+	for _, failure := range e.Result.Details.RunTestResult.Failures {
+		messages = append(messages, failure.String())
+	}
+	for _, warning := range e.Result.Details.RunTestResult.CodeCoverageWarnings {
+		messages = append(messages, warning.Name+": "+warning.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+type deployRequestResponse struct {
+	Id           string       `json:"id"`
+	DeployResult DeployResult `json:"deployResult"`
+}
+
+// GetDeployStatus returns the status of a Metadata API deployment, including component errors and
+// test failures
+func (sf *Salesforce) GetDeployStatus(deployId string) (*DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return getDeployStatus(context.Background(), sf, deployId)
+}
+
+// WaitForDeploy polls a Metadata API deployment every interval until it is done. progress, if not nil,
+// is called with the result of every poll. If ctx is cancelled before the deployment is done, the
+// deployment is cancelled. A *DeployError is returned if the deployment does not succeed.
+func (sf *Salesforce) WaitForDeploy(
+	ctx context.Context,
+	deployId string,
+	interval time.Duration,
+	progress func(DeployResult),
+) (*DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be greater than 0")
+	}
+
+	var result *DeployResult
+	err := pollUntilDone(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		result, err = getDeployStatus(ctx, sf, deployId)
+		if err != nil {
+			return true, err
+		}
+		if progress != nil {
+			progress(*result)
+		}
+		return result.Done, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			if _, cancelErr := cancelDeploy(context.Background(), sf, deployId); cancelErr != nil {
+				return result, errors.Join(err, cancelErr)
+			}
+		}
+		return result, err
+	}
+
+	if result.Status != DeployStatusSucceeded {
+		return result, &DeployError{Result: *result}
+	}
+	return result, nil
+}
+
+// CancelDeploy requests the cancellation of a Metadata API deployment that is in progress
+func (sf *Salesforce) CancelDeploy(deployId string) (*DeployResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return cancelDeploy(context.Background(), sf, deployId)
+}
+
+func getDeployStatus(ctx context.Context, sf *Salesforce, deployId string) (*DeployResult, error) {
+	if deployId == "" {
+		return nil, errors.New("deploy id cannot be empty")
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      "/metadata/deployRequest/" + url.PathEscape(deployId) + "?includeDetails=true",
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployResp := deployRequestResponse{}
+	if err := decodeJSONResponse(resp, &deployResp); err != nil {
+		return nil, err
+	}
+	if deployResp.DeployResult.Id == "" {
+		deployResp.DeployResult.Id = deployResp.Id
+	}
+	return &deployResp.DeployResult, nil
+}
+
+func cancelDeploy(ctx context.Context, sf *Salesforce, deployId string) (*DeployResult, error) {
+	if deployId == "" {
+		return nil, errors.New("deploy id cannot be empty")
+	}
+	body, err := json.Marshal(map[string]any{
+		"deployResult": map[string]string{"status": DeployStatusCanceling},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodPatch,
+		uri:      "/metadata/deployRequest/" + url.PathEscape(deployId),
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployResp := deployRequestResponse{}
+	if err := decodeJSONResponse(resp, &deployResp); err != nil {
+		return nil, err
+	}
+	if deployResp.DeployResult.Id == "" {
+		deployResp.DeployResult.Id = deployResp.Id
+	}
+	return &deployResp.DeployResult, nil
+}
@@ -0,0 +1,194 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setupDeployServer responds to deploy status requests with results in order, repeating the last one
+func setupDeployServer(
+	t *testing.T,
+	results ...DeployResult,
+) (*httptest.Server, authentication, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var methods []string
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, r.Method)
+		result := results[min(polls, len(results)-1)]
+		if r.Method == http.MethodGet {
+			polls++
+		} else {
+			result = DeployResult{Id: result.Id, Status: DeployStatusCanceling}
+		}
+		if err := json.NewEncoder(w).Encode(deployRequestResponse{
+			Id:           result.Id,
+			DeployResult: result,
+		}); err != nil {
+			t.Error(err)
+		}
+	}))
+	return server, authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	}, &methods
+}
+
+func TestSalesforce_WaitForDeploy(t *testing.T) {
+	inProgress := DeployResult{
+		Id:                       "0Af000000000001AAA",
+		Status:                   DeployStatusInProgress,
+		NumberComponentsDeployed: 1,
+		NumberComponentsTotal:    2,
+	}
+	succeeded := DeployResult{
+		Id:      "0Af000000000001AAA",
+		Status:  DeployStatusSucceeded,
+		Success: true,
+		Done:    true,
+	}
+	failed := DeployResult{
+		Id:     "0Af000000000001AAA",
+		Status: DeployStatusFailed,
+		Done:   true,
+		Details: DeployDetails{
+			ComponentFailures: []DeployMessage{{
+				ComponentType: "ApexClass",
+				FullName:      "AccountService",
+				FileName:      "classes/AccountService.cls",
+				LineNumber:    3,
+				ColumnNumber:  5,
+				Problem:       "Variable does not exist: foo",
+				ProblemType:   "Error",
+			}},
+			RunTestResult: RunTestResult{
+				NumTestsRun: 1,
+				NumFailures: 1,
+				Failures: []DeployTestFailure{{
+					Name:       "AccountServiceTest",
+					MethodName: "testInsert",
+					Message:    "System.AssertException: Assertion Failed",
+				}},
+			},
+		},
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		server, sfAuth, _ := setupDeployServer(t, inProgress, succeeded)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		var updates []DeployResult
+		got, err := sf.WaitForDeploy(
+			context.Background(),
+			"0Af000000000001AAA",
+			time.Millisecond,
+			func(result DeployResult) { updates = append(updates, result) },
+		)
+		if err != nil {
+			t.Fatalf("WaitForDeploy() error = %v", err)
+		}
+		if got.Status != DeployStatusSucceeded {
+			t.Errorf("WaitForDeploy() status = %v", got.Status)
+		}
+		if len(updates) != 2 || updates[0].NumberComponentsDeployed != 1 {
+			t.Errorf("WaitForDeploy() progress = %v", updates)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		server, sfAuth, _ := setupDeployServer(t, failed)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.WaitForDeploy(
+			context.Background(),
+			"0Af000000000001AAA",
+			time.Millisecond,
+			nil,
+		)
+		var deployErr *DeployError
+		if !errors.As(err, &deployErr) {
+			t.Fatalf("WaitForDeploy() error = %v, want DeployError", err)
+		}
+		if got == nil || len(deployErr.Result.Details.ComponentFailures) != 1 {
+			t.Errorf("WaitForDeploy() = %v", got)
+		}
+		for _, want := range []string{
+			"ApexClass AccountService (classes/AccountService.cls:3:5): Variable does not exist: foo",
+			"AccountServiceTest.testInsert: System.AssertException: Assertion Failed",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("WaitForDeploy() error = %v, want %v", err, want)
+			}
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		server, sfAuth, methods := setupDeployServer(t, inProgress)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := sf.WaitForDeploy(ctx, "0Af000000000001AAA", time.Millisecond, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("WaitForDeploy() error = %v, want deadline exceeded", err)
+		}
+		if last := (*methods)[len(*methods)-1]; last != http.MethodPatch {
+			t.Errorf("WaitForDeploy() last request = %v, want cancel deploy", last)
+		}
+	})
+
+	t.Run("invalid_interval", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "token"})
+		if _, err := sf.WaitForDeploy(context.Background(), "0Af000000000001AAA", 0, nil); err == nil {
+			t.Errorf("WaitForDeploy() expected error for invalid interval")
+		}
+	})
+}
+
+func TestSalesforce_CancelDeploy(t *testing.T) {
+	server, sfAuth, methods := setupDeployServer(t, DeployResult{Id: "0Af000000000001AAA"})
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	got, err := sf.CancelDeploy("0Af000000000001AAA")
+	if err != nil {
+		t.Fatalf("CancelDeploy() error = %v", err)
+	}
+	if got.Status != DeployStatusCanceling || (*methods)[0] != http.MethodPatch {
+		t.Errorf("CancelDeploy() = %v, requests = %v", got, *methods)
+	}
+
+	if _, err := sf.CancelDeploy(""); err == nil {
+		t.Errorf("CancelDeploy() expected error for empty id")
+	}
+}
+
+func TestSalesforce_GetDeployStatus(t *testing.T) {
+	server, sfAuth, _ := setupDeployServer(t, DeployResult{
+		Id:     "0Af000000000001AAA",
+		Status: DeployStatusPending,
+	})
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	got, err := sf.GetDeployStatus("0Af000000000001AAA")
+	if err != nil {
+		t.Fatalf("GetDeployStatus() error = %v", err)
+	}
+	if got.Status != DeployStatusPending || got.Done {
+		t.Errorf("GetDeployStatus() = %v", got)
+	}
+}
@@ -0,0 +1,164 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// SObjectDescribe is the metadata of an sObject returned by the describe resource
+type SObjectDescribe struct {
+	Name        string          `json:"name"`
+	Label       string          `json:"label"`
+	LabelPlural string          `json:"labelPlural"`
+	KeyPrefix   string          `json:"keyPrefix"`
+	Custom      bool            `json:"custom"`
+	Createable  bool            `json:"createable"`
+	Updateable  bool            `json:"updateable"`
+	Deletable   bool            `json:"deletable"`
+	Queryable   bool            `json:"queryable"`
+	Fields      []DescribeField `json:"fields"`
+}
+
+// DescribeField is the metadata of a single field of an sObject
+type DescribeField struct {
+	Name             string                  `json:"name"`
+	Label            string                  `json:"label"`
+	Type             string                  `json:"type"`
+	Length           int                     `json:"length"`
+	Precision        int                     `json:"precision"`
+	Scale            int                     `json:"scale"`
+	Createable       bool                    `json:"createable"`
+	Updateable       bool                    `json:"updateable"`
+	Nillable         bool                    `json:"nillable"`
+	Calculated       bool                    `json:"calculated"`
+	ExternalId       bool                    `json:"externalId"`
+	IdLookup         bool                    `json:"idLookup"`
+	Unique           bool                    `json:"unique"`
+	Filterable       bool                    `json:"filterable"`
+	Sortable         bool                    `json:"sortable"`
+	Groupable        bool                    `json:"groupable"`
+	Encrypted        bool                    `json:"encrypted"` // encrypted with Shield Platform Encryption
+	ReferenceTo      []string                `json:"referenceTo"`
+	RelationshipName string                  `json:"relationshipName"`
+	PicklistValues   []DescribePicklistValue `json:"picklistValues"` // values of picklist fields
+}
+
+// DescribePicklistValue is a value of a picklist field as returned by the describe resource
+type DescribePicklistValue struct {
+	Label        string `json:"label"`
+	Value        string `json:"value"`
+	Active       bool   `json:"active"`
+	DefaultValue bool   `json:"defaultValue"`
+}
+
+// textFieldTypes are the field types whose values are limited by the field length
+var textFieldTypes = map[string]bool{
+	"string":          true,
+	"textarea":        true,
+	"email":           true,
+	"phone":           true,
+	"url":             true,
+	"picklist":        true,
+	"multipicklist":   true,
+	"combobox":        true,
+	"encryptedstring": true,
+}
+
+// Field returns the field with the given API name, matched case-insensitively
+func (d *SObjectDescribe) Field(name string) (DescribeField, bool) {
+	for _, field := range d.Fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return DescribeField{}, false
+}
+
+// DescribeSObject returns the metadata of an sObject, including its fields.
+// Results are cached, see WithDescribeCacheTTL.
+func (sf *Salesforce) DescribeSObject(sObjectName string) (*SObjectDescribe, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return describeSObject(sf, sObjectName)
+}
+
+func describeSObject(sf *Salesforce, sObjectName string) (*SObjectDescribe, error) {
+	cacheKey := strings.ToLower(sObjectName)
+	if cached, ok := sf.config.describeCache.get(cacheKey); ok {
+		return cached.(*SObjectDescribe), nil
+	}
+
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodGet,
+		uri:      "/sobjects/" + url.PathEscape(sObjectName) + "/describe",
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	describe := &SObjectDescribe{}
+	if err := decodeJSONResponse(resp, describe); err != nil {
+		return nil, err
+	}
+	sf.config.describeCache.set(cacheKey, describe)
+	return describe, nil
+}
+
+// ClearDescribeCache removes all cached sObject describe results, including describes cached by
+// WithResponseCache
+func (sf *Salesforce) ClearDescribeCache() {
+	sf.config.describeCache.clear()
+	_ = sf.InvalidateResponseCache("/sobjects")
+}
+
+// truncateFields shortens text values that are longer than their field's length
+func truncateFields(describe *SObjectDescribe, records ...map[string]any) {
+	for _, record := range records {
+		for key, value := range record {
+			text, ok := value.(string)
+			if !ok {
+				continue
+			}
+			field, ok := describe.Field(key)
+			if !ok || !textFieldTypes[field.Type] || field.Length <= 0 ||
+				utf8.RuneCountInString(text) <= field.Length {
+				continue
+			}
+			record[key] = string([]rune(text)[:field.Length])
+		}
+	}
+}
+
+// removeNonWritableFields deletes fields that cannot be set by the operation, such as formula and
+// audit fields, so that queried records can be written back. Id and external id fields are kept since
+// they identify records, and fields missing from the describe are left for Salesforce to validate.
+func removeNonWritableFields(
+	describe *SObjectDescribe,
+	operation string,
+	records ...map[string]any,
+) {
+	for _, record := range records {
+		for key := range record {
+			field, ok := describe.Field(key)
+			if !ok || field.Type == "id" || field.ExternalId {
+				continue
+			}
+			writable := field.Createable && field.Updateable
+			switch operation {
+			case insertOperation:
+				writable = field.Createable
+			case updateOperation:
+				writable = field.Updateable
+			}
+			if !writable {
+				delete(record, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSalesforce_DescribeSObject(t *testing.T) {
+	describe := SObjectDescribe{
+		Name:   "Account",
+		Label:  "Account",
+		Fields: []DescribeField{{Name: "Name", Type: "string", Length: 5}},
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		requests := 0
+		server, sfAuth := setupTestServer(describe, http.StatusOK)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Account/describe" {
+				t.Errorf("DescribeSObject() path = %v", r.URL.Path)
+			}
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+
+		for range 2 {
+			got, err := sf.DescribeSObject("Account")
+			if err != nil {
+				t.Fatalf("DescribeSObject() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, describe) {
+				t.Errorf("DescribeSObject() = %v, want %v", *got, describe)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("DescribeSObject() requests = %v, want 1", requests)
+		}
+
+		sf.ClearDescribeCache()
+		if _, err := sf.DescribeSObject("Account"); err != nil {
+			t.Fatalf("DescribeSObject() error = %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("DescribeSObject() requests after clear = %v, want 2", requests)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		server, sfAuth := setupTestServer("", http.StatusNotFound)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		if _, err := sf.DescribeSObject("Missing__c"); err == nil {
+			t.Errorf("DescribeSObject() expected error")
+		}
+	})
+
+	t.Run("not_authenticated", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{})
+		if _, err := sf.DescribeSObject("Account"); err == nil {
+			t.Errorf("DescribeSObject() expected error")
+		}
+	})
+}
+
+func Test_truncateFields(t *testing.T) {
+	describe := &SObjectDescribe{Fields: []DescribeField{
+		{Name: "Name", Type: "string", Length: 5},
+		{Name: "Description", Type: "textarea", Length: 3},
+		{Name: "External_Id__c", Type: "id", Length: 2},
+	}}
+	records := []map[string]any{
+		{"Name": "long name", "Description": "éèêë", "External_Id__c": "abc", "Other__c": "value"},
+		{"name": "short", "NumberOfEmployees": 10},
+	}
+	want := []map[string]any{
+		{"Name": "long ", "Description": "éèê", "External_Id__c": "abc", "Other__c": "value"},
+		{"name": "short", "NumberOfEmployees": 10},
+	}
+
+	truncateFields(describe, records...)
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("truncateFields() = %v, want %v", records, want)
+	}
+}
+
+func Test_removeNonWritableFields(t *testing.T) {
+	describe := &SObjectDescribe{Fields: []DescribeField{
+		{Name: "Id", Type: "id"},
+		{Name: "Name", Type: "string", Createable: true, Updateable: true},
+		{Name: "OwnerId", Type: "reference", Createable: true, Updateable: true},
+		{
+			Name:       "Legacy_Id__c",
+			Type:       "string",
+			Createable: true,
+			Updateable: true,
+			ExternalId: true,
+		},
+		{Name: "Region__c", Type: "picklist", Createable: true},
+		{Name: "Status__c", Type: "picklist", Updateable: true},
+		{Name: "Score__c", Type: "double", Calculated: true},
+		{Name: "CreatedDate", Type: "datetime"},
+	}}
+	record := func() map[string]any {
+		return map[string]any{
+			"Id":           "001000000000001AAA",
+			"Name":         "Acme",
+			"ownerid":      "005000000000001AAA",
+			"Legacy_Id__c": "A-1",
+			"Region__c":    "EMEA",
+			"Status__c":    "Active",
+			"Score__c":     4.5,
+			"CreatedDate":  "2024-01-01T00:00:00.000+0000",
+			"attributes":   map[string]string{"type": "Account"},
+		}
+	}
+	keep := func(fields ...string) map[string]any {
+		want := record()
+		for key := range want {
+			if !slices.Contains(fields, key) {
+				delete(want, key)
+			}
+		}
+		return want
+	}
+	common := []string{"Id", "Name", "ownerid", "Legacy_Id__c", "attributes"}
+	tests := []struct {
+		name      string
+		operation string
+		want      map[string]any
+	}{
+		{
+			name:      "insert",
+			operation: insertOperation,
+			want:      keep(append(common, "Region__c")...),
+		},
+		{
+			name:      "update",
+			operation: updateOperation,
+			want:      keep(append(common, "Status__c")...),
+		},
+		{
+			name:      "upsert",
+			operation: upsertOperation,
+			want:      keep(common...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := record()
+			removeNonWritableFields(describe, tt.operation, got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removeNonWritableFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	diagnosticsLimitThreshold     = 0.9 // share of an org limit used that is reported as a problem
+	diagnosticsErrorRateThreshold = 0.1 // share of failed requests that is reported as a problem
+)
+
+// diagnosticsPermissions are the user permissions the flows of the library depend on
+var diagnosticsPermissions = []string{
+	"ApiEnabled",
+	"BulkApiHardDelete",
+	"ModifyAllData",
+	"ViewAllData",
+	"ModifyMetadata",
+	"AuthorApex",
+	"CustomizeApplication",
+}
+
+// OrgLimit is the allocation of an org limit, such as DailyApiRequests, and how much of it remains
+type OrgLimit struct {
+	Max       int `json:"Max"`
+	Remaining int `json:"Remaining"`
+}
+
+// Used returns the share of the limit that is used, from 0 to 1
+func (l OrgLimit) Used() float64 {
+	if l.Max <= 0 {
+		return 0
+	}
+	return float64(l.Max-l.Remaining) / float64(l.Max)
+}
+
+// DiagnosticsRequestStats counts the requests sent to a class of endpoints and how many failed
+type DiagnosticsRequestStats struct {
+	Endpoint EndpointClass
+	Requests int
+	Errors   int // requests without a response or with a 4xx or 5xx status
+}
+
+// ErrorRate returns the share of requests that failed, from 0 to 1
+func (s DiagnosticsRequestStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// DiagnosticsReport describes the state of a client and its org for support triage. Sections that
+// could not be gathered are left empty and their errors are listed in Problems.
+type DiagnosticsReport struct {
+	GeneratedAt  time.Time
+	InstanceUrl  string
+	ApiVersion   string
+	AuthFlow     AuthFlowType
+	OrgId        string
+	UserId       string
+	Latency      time.Duration       // round trip of a ping, see Ping
+	Limits       map[string]OrgLimit // org limits by name
+	Permissions  map[string]bool     // user permissions the library's flows depend on, by name
+	APIUsage     APIUsage
+	CircuitState CircuitState
+	Requests     []DiagnosticsRequestStats // requests since the client's metrics were created, see WithMetrics
+	Problems     []string                  // failed checks and findings likely to cause errors
+}
+
+// Diagnostics gathers the org limits, API version, permissions of the user relevant to the library's
+// flows (API Enabled, Bulk API Hard Delete, Modify All Data, and others), and the error rates of recent
+// requests into a report, and lists the problems it finds, such as limits that are nearly used up
+func (sf *Salesforce) Diagnostics(ctx context.Context) (*DiagnosticsReport, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	report := &DiagnosticsReport{
+		GeneratedAt:  time.Now().UTC(),
+		InstanceUrl:  sf.auth.InstanceUrl,
+		ApiVersion:   sf.config.apiVersion,
+		AuthFlow:     sf.AuthFlow,
+		CircuitState: sf.config.circuitBreaker.currentState(),
+	}
+	problem := func(format string, args ...any) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+	}
+
+	latency, err := sf.Ping(ctx)
+	report.Latency = latency
+	if err != nil {
+		problem("ping failed: %v", err)
+	}
+	if report.CircuitState != CircuitClosed {
+		problem("circuit breaker is %s", report.CircuitState)
+	}
+
+	limits, err := getOrgLimits(ctx, sf)
+	if err != nil {
+		problem("reading org limits failed: %v", err)
+	}
+	report.Limits = limits
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if limit := limits[name]; limit.Used() >= diagnosticsLimitThreshold {
+			problem(
+				"%s is %.0f%% used (%d of %d remaining)",
+				name,
+				limit.Used()*100,
+				limit.Remaining,
+				limit.Max,
+			)
+		}
+	}
+	report.APIUsage = sf.config.apiUsage.get()
+
+	orgId, userId, err := identityIds(sf.auth)
+	if err != nil {
+		problem("reading user permissions failed: %v", err)
+	} else {
+		report.OrgId, report.UserId = orgId, userId
+		report.Permissions, err = userPermissions(ctx, sf, userId, diagnosticsPermissions)
+		if err != nil {
+			problem("reading user permissions failed: %v", err)
+		} else if !report.Permissions["ApiEnabled"] {
+			problem("user %s does not have the API Enabled permission", userId)
+		}
+	}
+
+	report.Requests = sf.config.metrics.requestStats()
+	for _, stats := range report.Requests {
+		if stats.Errors > 0 && stats.ErrorRate() >= diagnosticsErrorRateThreshold {
+			problem(
+				"%.0f%% of %s requests failed (%d of %d)",
+				stats.ErrorRate()*100,
+				stats.Endpoint,
+				stats.Errors,
+				stats.Requests,
+			)
+		}
+	}
+
+	return report, nil
+}
+
+// String formats the report as text to paste into a support ticket
+func (r DiagnosticsReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "generated at: %s\n", r.GeneratedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "instance url: %s\n", r.InstanceUrl)
+	fmt.Fprintf(&b, "api version: %s\n", r.ApiVersion)
+	fmt.Fprintf(&b, "auth flow: %s\n", r.AuthFlow)
+	fmt.Fprintf(&b, "org id: %s\nuser id: %s\n", r.OrgId, r.UserId)
+	fmt.Fprintf(&b, "latency: %s\n", r.Latency)
+	fmt.Fprintf(&b, "circuit breaker: %s\n", r.CircuitState)
+
+	permissions := make([]string, 0, len(r.Permissions))
+	for name, enabled := range r.Permissions {
+		permissions = append(permissions, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	slices.Sort(permissions)
+	fmt.Fprintf(&b, "permissions: %s\n", strings.Join(permissions, ", "))
+
+	limits := make([]string, 0, len(r.Limits))
+	for name, limit := range r.Limits {
+		limits = append(
+			limits,
+			fmt.Sprintf("  %s: %d of %d remaining\n", name, limit.Remaining, limit.Max),
+		)
+	}
+	slices.Sort(limits)
+	b.WriteString("limits:\n" + strings.Join(limits, ""))
+
+	b.WriteString("requests:\n")
+	for _, stats := range r.Requests {
+		fmt.Fprintf(
+			&b,
+			"  %s: %d requests, %d errors\n",
+			stats.Endpoint,
+			stats.Requests,
+			stats.Errors,
+		)
+	}
+	b.WriteString("problems:\n")
+	for _, problem := range r.Problems {
+		fmt.Fprintf(&b, "  %s\n", problem)
+	}
+	return b.String()
+}
+
+func getOrgLimits(ctx context.Context, sf *Salesforce) (map[string]OrgLimit, error) {
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:     ctx,
+		method:  http.MethodGet,
+		uri:     "/limits",
+		content: jsonType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	limits := map[string]OrgLimit{}
+	if err := decodeJSONResponse(resp, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// userPermissions returns whether a user has each of the permissions, such as ApiEnabled, from the
+// permission sets assigned to them, which include the permission set of their profile
+func userPermissions(
+	ctx context.Context,
+	sf *Salesforce,
+	userId string,
+	permissions []string,
+) (map[string]bool, error) {
+	if len(permissions) == 0 {
+		return nil, errors.New("no permissions to check")
+	}
+	fields := make([]string, len(permissions))
+	for i, permission := range permissions {
+		fields[i] = "Permissions" + permission
+	}
+	query := "SELECT " + strings.Join(fields, ", ") +
+		" FROM PermissionSet WHERE Id IN (SELECT PermissionSetId FROM PermissionSetAssignment" +
+		" WHERE AssigneeId = '" + escapeSoqlString(userId) + "')"
+	records, err := queryAllRecords(ctx, sf, query)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(permissions))
+	for i, permission := range permissions {
+		granted[permission] = false
+		for _, record := range records {
+			if enabled, _ := record[fields[i]].(bool); enabled {
+				granted[permission] = true
+				break
+			}
+		}
+	}
+	return granted, nil
+}
@@ -0,0 +1,108 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_Diagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case strings.HasSuffix(r.URL.Path, "/limits"):
+			_, _ = w.Write([]byte(`{
+				"DailyApiRequests": {"Max": 15000, "Remaining": 1000},
+				"DailyBulkV2QueryJobs": {"Max": 10000, "Remaining": 9990}
+			}`))
+		case strings.HasSuffix(r.URL.Path, "/query/"):
+			if !strings.Contains(r.URL.Query().Get("q"), "AssigneeId = '005000000000001AAA'") {
+				t.Errorf("query = %s", r.URL.Query().Get("q"))
+			}
+			_, _ = w.Write([]byte(`{"done": true, "records": [
+				{"PermissionsApiEnabled": false, "PermissionsModifyAllData": true},
+				{"PermissionsApiEnabled": false, "PermissionsAuthorApex": true}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "1234",
+		Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA",
+	})
+	metrics := NewMetrics()
+	sf.config.metrics = metrics
+	metrics.observeRequest(
+		EndpointComposite,
+		http.MethodPost,
+		&http.Response{StatusCode: 200},
+		time.Second,
+	)
+	metrics.observeRequest(EndpointComposite, http.MethodPost, nil, time.Second)
+
+	report, err := sf.Diagnostics(context.Background())
+	if err != nil {
+		t.Fatalf("Diagnostics() error = %v", err)
+	}
+	if report.OrgId != "00D000000000001AAA" || report.ApiVersion != apiVersion {
+		t.Errorf("Diagnostics() = %+v", report)
+	}
+	if limit := report.Limits["DailyApiRequests"]; limit.Max != 15000 || limit.Remaining != 1000 {
+		t.Errorf("Diagnostics() limits = %v", report.Limits)
+	}
+	if !report.Permissions["ModifyAllData"] || !report.Permissions["AuthorApex"] ||
+		report.Permissions["ApiEnabled"] || len(report.Permissions) != len(diagnosticsPermissions) {
+		t.Errorf("Diagnostics() permissions = %v", report.Permissions)
+	}
+	wantRequests := []DiagnosticsRequestStats{
+		{Endpoint: EndpointComposite, Requests: 2, Errors: 1},
+		{Endpoint: EndpointOther, Requests: 1},
+		{Endpoint: EndpointQuery, Requests: 1},
+	}
+	if !reflect.DeepEqual(report.Requests, wantRequests) {
+		t.Errorf("Diagnostics() requests = %v, want %v", report.Requests, wantRequests)
+	}
+	wantProblems := []string{
+		"DailyApiRequests is 93% used (1000 of 15000 remaining)",
+		"user 005000000000001AAA does not have the API Enabled permission",
+		"50% of composite requests failed (1 of 2)",
+	}
+	if !reflect.DeepEqual(report.Problems, wantProblems) {
+		t.Errorf("Diagnostics() problems = %q, want %q", report.Problems, wantProblems)
+	}
+	text := report.String()
+	for _, want := range []string{"  DailyApiRequests: 1000 of 15000 remaining\n", "ModifyAllData=true"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestSalesforce_Diagnostics_failures(t *testing.T) {
+	server, auth := setupTestServer(
+		[]SalesforceErrorMessage{{ErrorCode: "REQUEST_LIMIT_EXCEEDED", Message: "limit"}},
+		http.StatusForbidden,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	report, err := sf.Diagnostics(context.Background())
+	if err != nil {
+		t.Fatalf("Diagnostics() error = %v", err)
+	}
+	// failed sections are reported as problems rather than failing the report
+	if len(report.Problems) != 3 || report.Limits != nil || report.Permissions != nil {
+		t.Errorf("Diagnostics() = %+v", report)
+	}
+	if report.Requests != nil {
+		t.Errorf("Diagnostics() requests = %v without metrics, want none", report.Requests)
+	}
+}
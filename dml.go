@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/go-viper/mapstructure/v2"
 )
@@ -26,6 +28,7 @@ func convertToMap(obj any) (map[string]any, error) {
 		if err != nil {
 			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
 		}
+		removeFieldsPresent(recordMap)
 	}
 	return recordMap, nil
 }
@@ -39,10 +42,121 @@ func convertToSliceOfMaps(obj any) ([]map[string]any, error) {
 		if err != nil {
 			return nil, errors.New("issue decoding salesforce object, need a key value pair (custom struct or map)")
 		}
+		removeFieldsPresent(recordMap...)
 	}
 	return recordMap, nil
 }
 
+// removeFieldsPresent removes FieldsPresent struct fields from records converted from structs, so
+// they are not sent to Salesforce as fields
+func removeFieldsPresent(records ...map[string]any) {
+	for _, record := range records {
+		for name, value := range record {
+			if _, ok := value.(FieldsPresent); ok {
+				delete(record, name)
+			}
+		}
+	}
+}
+
+// prepareRecords applies client level record settings before records are sent to Salesforce for
+// the given insert, update, or upsert operation
+func prepareRecords(
+	sf *Salesforce,
+	sObjectName string,
+	operation string,
+	records ...map[string]any,
+) error {
+	for _, record := range records {
+		expandLookups(record)
+	}
+	config := sf.config
+	if config.nameValidation && len(records) > 0 {
+		if err := validateRecordNames(sf, sObjectName, records); err != nil {
+			return err
+		}
+	}
+	if config.automationBypassField != "" &&
+		(len(config.automationBypassObjects) == 0 ||
+			slices.Contains(config.automationBypassObjects, sObjectName)) {
+		for _, record := range records {
+			record[config.automationBypassField] = true
+		}
+	}
+	if (config.fieldTruncation || config.writableFieldsOnly) && len(records) > 0 {
+		describe, err := describeSObject(sf, sObjectName)
+		if err != nil {
+			return err
+		}
+		if config.writableFieldsOnly {
+			removeNonWritableFields(describe, operation, records...)
+		}
+		if config.fieldTruncation {
+			truncateFields(describe, records...)
+		}
+	}
+	if strings.EqualFold(sObjectName, "Account") {
+		for _, record := range records {
+			if err := validatePersonAccountFields(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandLookups replaces fields keyed by the path of an external id field of a related record, such as
+// Account.External_Key__c, with a related record, such as Account: {External_Key__c: value}, so that
+// the lookup is resolved by Salesforce
+func expandLookups(record map[string]any) {
+	for field, value := range record {
+		relationship, externalIdField, ok := strings.Cut(field, ".")
+		if !ok {
+			continue
+		}
+		delete(record, field)
+		related, ok := record[relationship].(map[string]any)
+		if !ok {
+			related = map[string]any{}
+			record[relationship] = related
+		}
+		related[externalIdField] = value
+	}
+}
+
+// flattenLookups returns the records with related records keyed by the path of their fields, such as
+// Account.External_Key__c, as expected in bulk job data. Every record has the same fields, as bulk job
+// data has a single header row.
+func flattenLookups(records []map[string]any) []map[string]any {
+	fields := map[string]bool{}
+	flattened := make([]map[string]any, len(records))
+	for i, record := range records {
+		flattened[i] = make(map[string]any, len(record))
+		for field, value := range record {
+			if related, ok := value.(map[string]any); ok {
+				for relatedField, relatedValue := range related {
+					if relatedField == "attributes" {
+						continue
+					}
+					flattened[i][field+"."+relatedField] = relatedValue
+					fields[field+"."+relatedField] = true
+				}
+				continue
+			}
+			flattened[i][field] = value
+			fields[field] = true
+		}
+	}
+	for _, record := range flattened {
+		for field := range fields {
+			if _, ok := record[field]; !ok {
+				record[field] = nil
+			}
+		}
+	}
+	return flattened
+}
+
 func processSalesforceResponse(resp http.Response) ([]SalesforceResult, error) {
 	results := []SalesforceResult{}
 	responseData, err := io.ReadAll(resp.Body)
@@ -62,54 +176,109 @@ func doBatchedRequestsForCollection(
 	method string,
 	url string,
 	batchSize int,
-	recordMap []map[string]any,
+	records [][]byte,
+	ids []string,
+	groups *recordGroups,
+	options ...RequestOption,
 ) (SalesforceResults, error) {
-	results := []SalesforceResult{}
+	records, ids = groups.orderEncoded(records, ids)
+	results, err := sendCollectionBatches(sf, method, url, batchSize, records, ids, groups, options)
+	if err == nil {
+		results, err = retryLockedRecords(
+			sf,
+			method,
+			url,
+			batchSize,
+			records,
+			ids,
+			results,
+			options,
+		)
+	}
+	results = groups.restoreOrder(results)
+	if err != nil {
+		return SalesforceResults{Results: results}, err
+	}
 
-	for len(recordMap) > 0 {
-		var batch, remaining []map[string]any
-		if len(recordMap) > batchSize {
-			batch, remaining = recordMap[:batchSize], recordMap[batchSize:]
-		} else {
-			batch = recordMap
+	for _, result := range results {
+		if !result.Success {
+			return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
 		}
-		recordMap = remaining
+	}
 
-		payload := sObjectCollection{
-			AllOrNone: false,
-			Records:   batch,
-		}
+	return SalesforceResults{Results: results}, nil
+}
 
-		body, err := json.Marshal(payload)
-		if err != nil {
-			return SalesforceResults{Results: results}, err
-		}
+// sendCollectionBatches sends the encoded records in batches of up to batchSize and returns their
+// results, indexed by the position of their record
+func sendCollectionBatches(
+	sf *Salesforce,
+	method string,
+	url string,
+	batchSize int,
+	records [][]byte,
+	ids []string,
+	groups *recordGroups,
+	options []RequestOption,
+) ([]SalesforceResult, error) {
+	results := []SalesforceResult{}
+
+	for len(records) > 0 {
+		batchLen, body := nextCollectionBatch(
+			sf.config,
+			records,
+			groups.batchSize(len(results), batchSize),
+		)
+		records = records[batchLen:]
+		batchIds := ids[:batchLen]
+		ids = ids[batchLen:]
 
 		resp, err := doRequest(sf.auth, sf.config, requestPayload{
 			method:   method,
 			uri:      url,
 			content:  jsonType,
-			body:     string(body),
+			body:     requestBody(body),
 			compress: sf.config.compressionHeaders,
+			options:  options,
 		})
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return results, err
 		}
 		currentResults, err := processSalesforceResponse(*resp)
 		if err != nil {
-			return SalesforceResults{Results: results}, err
+			return results, err
 		}
 
-		results = append(results, currentResults...)
+		results = append(results, indexResults(currentResults, batchIds, len(results))...)
 	}
 
-	for _, result := range results {
-		if !result.Success {
-			return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
+	return results, nil
+}
+
+// nextCollectionBatch returns the number of records of the next batch of up to batchSize encoded
+// records and its request body. When a max request size is set, a batch whose body is larger is halved
+// until it fits or has one record.
+func nextCollectionBatch(config *configuration, records [][]byte, batchSize int) (int, []byte) {
+	const prefix, suffix = `{"allOrNone":false,"records":[`, `]}`
+	batch := records[:min(batchSize, len(records))]
+	for {
+		size := len(prefix) + len(suffix) + len(batch) - 1
+		for _, record := range batch {
+			size += len(record)
 		}
+		if config.maxRequestSize == 0 || size <= config.maxRequestSize || len(batch) == 1 {
+			body := make([]byte, 0, size)
+			body = append(body, prefix...)
+			for i, record := range batch {
+				if i > 0 {
+					body = append(body, ',')
+				}
+				body = append(body, record...)
+			}
+			return len(batch), append(body, suffix...)
+		}
+		batch = batch[:len(batch)/2]
 	}
-
-	return SalesforceResults{Results: results}, nil
 }
 
 func decodeResponseBody(response *http.Response) (value SalesforceResult, err error) {
@@ -192,10 +361,16 @@ func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResu
 	if err != nil {
 		return SalesforceResult{}, err
 	}
+	if err := runBeforeHook(sf, insertOperation, sObjectName, recordMap); err != nil {
+		return SalesforceResult{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, insertOperation, recordMap); err != nil {
+		return SalesforceResult{}, err
+	}
 	recordMap["attributes"] = map[string]string{"type": sObjectName}
 	delete(recordMap, "Id")
 
-	body, err := json.Marshal(recordMap)
+	body, err := marshalJSON(sf.config, recordMap)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -204,8 +379,9 @@ func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResu
 		method:   http.MethodPost,
 		uri:      "/sobjects/" + sObjectName,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
+		options:  sObjectDefaultOptions(sf.config, sObjectName),
 	})
 	if err != nil {
 		return SalesforceResult{}, err
@@ -216,6 +392,9 @@ func doInsertOne(sf *Salesforce, sObjectName string, record any) (SalesforceResu
 		fmt.Println("Error decoding: ", err)
 		return SalesforceResult{}, err
 	}
+	if err := runAfterHook(sf, insertOperation, sObjectName, recordMap); err != nil {
+		return data, err
+	}
 
 	return data, nil
 }
@@ -225,6 +404,12 @@ func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
 	if err != nil {
 		return err
 	}
+	if err := runBeforeHook(sf, updateOperation, sObjectName, recordMap); err != nil {
+		return err
+	}
+	if err := prepareRecords(sf, sObjectName, updateOperation, recordMap); err != nil {
+		return err
+	}
 
 	recordId, ok := recordMap["Id"].(string)
 	if !ok || recordId == "" {
@@ -234,7 +419,7 @@ func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
 	recordMap["attributes"] = map[string]string{"type": sObjectName}
 	delete(recordMap, "Id")
 
-	body, err := json.Marshal(recordMap)
+	body, err := marshalJSON(sf.config, recordMap)
 	if err != nil {
 		return err
 	}
@@ -243,14 +428,15 @@ func doUpdateOne(sf *Salesforce, sObjectName string, record any) error {
 		method:   http.MethodPatch,
 		uri:      "/sobjects/" + sObjectName + "/" + recordId,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
+		options:  sObjectDefaultOptions(sf.config, sObjectName),
 	})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return runAfterHook(sf, updateOperation, sObjectName, recordMap)
 }
 
 func doUpsertOne(
@@ -263,6 +449,12 @@ func doUpsertOne(
 	if err != nil {
 		return SalesforceResult{}, err
 	}
+	if err := runBeforeHook(sf, upsertOperation, sObjectName, recordMap); err != nil {
+		return SalesforceResult{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, upsertOperation, recordMap); err != nil {
+		return SalesforceResult{}, err
+	}
 	externalIdValue, err := checkForExternalId(sObjectName, fieldName, recordMap)
 	if err != nil {
 		return SalesforceResult{}, err
@@ -272,7 +464,7 @@ func doUpsertOne(
 	delete(recordMap, "Id")
 	delete(recordMap, fieldName)
 
-	body, err := json.Marshal(recordMap)
+	body, err := marshalJSON(sf.config, recordMap)
 	if err != nil {
 		return SalesforceResult{}, err
 	}
@@ -293,8 +485,9 @@ func doUpsertOne(
 		method:   http.MethodPatch,
 		uri:      uri,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		compress: sf.config.compressionHeaders,
+		options:  sObjectDefaultOptions(sf.config, sObjectName),
 	})
 	if err != nil {
 		return SalesforceResult{}, err
@@ -305,6 +498,9 @@ func doUpsertOne(
 		fmt.Println("Error decoding: ", err)
 		return SalesforceResult{}, err
 	}
+	if err := runAfterHook(sf, upsertOperation, sObjectName, recordMap); err != nil {
+		return data, err
+	}
 
 	return data, nil
 }
@@ -314,6 +510,9 @@ func doDeleteOne(sf *Salesforce, sObjectName string, record any) error {
 	if err != nil {
 		return err
 	}
+	if err := runBeforeHook(sf, deleteOperation, sObjectName, recordMap); err != nil {
+		return err
+	}
 
 	recordId, ok := recordMap["Id"].(string)
 	if !ok || recordId == "" {
@@ -330,7 +529,7 @@ func doDeleteOne(sf *Salesforce, sObjectName string, record any) error {
 		return err
 	}
 
-	return nil
+	return runAfterHook(sf, deleteOperation, sObjectName, recordMap)
 }
 
 func doInsertCollection(
@@ -339,22 +538,55 @@ func doInsertCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	encoded, ids, ok, err := encodeStructCollection(sf, sObjectName, insertOperation, "", records)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if ok {
+		return doBatchedRequestsForCollection(
+			sf,
+			http.MethodPost,
+			"/composite/sobjects/",
+			batchSize,
+			encoded,
+			ids,
+			nil,
+			sObjectDefaultOptions(sf.config, sObjectName)...,
+		)
+	}
 	recordMap, err := convertToSliceOfMaps(records)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, insertOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, insertOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 	for i := range recordMap {
 		delete(recordMap[i], "Id")
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 	}
+	encoded, ids, err = encodeRecordMaps(sf.config, recordMap)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
 
-	return doBatchedRequestsForCollection(
+	results, err := doBatchedRequestsForCollection(
 		sf,
 		http.MethodPost,
 		"/composite/sobjects/",
 		batchSize,
-		recordMap,
+		encoded,
+		ids,
+		groupRecords(sf.config, sObjectName, recordMap),
+		sObjectDefaultOptions(sf.config, sObjectName)...,
 	)
+	if err != nil {
+		return results, err
+	}
+	return results, runAfterHook(sf, insertOperation, sObjectName, recordMap...)
 }
 
 func doUpdateCollection(
@@ -363,10 +595,32 @@ func doUpdateCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	encoded, ids, ok, err := encodeStructCollection(sf, sObjectName, updateOperation, "", records)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if ok {
+		return doBatchedRequestsForCollection(
+			sf,
+			http.MethodPatch,
+			"/composite/sobjects/",
+			batchSize,
+			encoded,
+			ids,
+			nil,
+			sObjectDefaultOptions(sf.config, sObjectName)...,
+		)
+	}
 	recordMap, err := convertToSliceOfMaps(records)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, updateOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, updateOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 	for i := range recordMap {
 		recordMap[i]["attributes"] = map[string]string{"type": sObjectName}
 		recordId, ok := recordMap[i]["Id"].(string)
@@ -374,14 +628,25 @@ func doUpdateCollection(
 			return SalesforceResults{}, errors.New("salesforce id not found in object data")
 		}
 	}
+	encoded, ids, err = encodeRecordMaps(sf.config, recordMap)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
 
-	return doBatchedRequestsForCollection(
+	results, err := doBatchedRequestsForCollection(
 		sf,
 		http.MethodPatch,
 		"/composite/sobjects/",
 		batchSize,
-		recordMap,
+		encoded,
+		ids,
+		groupRecords(sf.config, sObjectName, recordMap),
+		sObjectDefaultOptions(sf.config, sObjectName)...,
 	)
+	if err != nil {
+		return results, err
+	}
+	return results, runAfterHook(sf, updateOperation, sObjectName, recordMap...)
 }
 
 func doUpsertCollection(
@@ -391,16 +656,61 @@ func doUpsertCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	uri := "/composite/sobjects/" + sObjectName + "/" + fieldName
+	encoded, ids, ok, err := encodeStructCollection(
+		sf,
+		sObjectName,
+		upsertOperation,
+		fieldName,
+		records,
+	)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if ok {
+		return doBatchedRequestsForCollection(
+			sf,
+			http.MethodPatch,
+			uri,
+			batchSize,
+			encoded,
+			ids,
+			nil,
+			sObjectDefaultOptions(sf.config, sObjectName)...,
+		)
+	}
 	recordMap, err := convertToSliceOfMaps(records)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, upsertOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	if err := prepareRecords(sf, sObjectName, upsertOperation, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
 	err = checkForExternalIdInList(sObjectName, fieldName, recordMap)
 	if err != nil {
 		return SalesforceResults{}, err
 	}
-	uri := "/composite/sobjects/" + sObjectName + "/" + fieldName
-	return doBatchedRequestsForCollection(sf, http.MethodPatch, uri, batchSize, recordMap)
+	encoded, ids, err = encodeRecordMaps(sf.config, recordMap)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	results, err := doBatchedRequestsForCollection(
+		sf,
+		http.MethodPatch,
+		uri,
+		batchSize,
+		encoded,
+		ids,
+		groupRecords(sf.config, sObjectName, recordMap),
+		sObjectDefaultOptions(sf.config, sObjectName)...,
+	)
+	if err != nil {
+		return results, err
+	}
+	return results, runAfterHook(sf, upsertOperation, sObjectName, recordMap...)
 }
 
 func doDeleteCollection(
@@ -413,6 +723,10 @@ func doDeleteCollection(
 	if err != nil {
 		return SalesforceResults{}, err
 	}
+	if err := runBeforeHook(sf, deleteOperation, sObjectName, recordMap...); err != nil {
+		return SalesforceResults{}, err
+	}
+	deleted := recordMap
 
 	// we want to verify that ids are present before we start deleting
 	batchedIds := []string{}
@@ -458,7 +772,13 @@ func doDeleteCollection(
 			return SalesforceResults{Results: results}, err
 		}
 
-		results = append(results, currentResults...)
+		results = append(
+			results,
+			indexResults(currentResults, strings.Split(batchedIds[i], ","), len(results))...,
+		)
+	}
+	if err := runAfterHook(sf, deleteOperation, sObjectName, deleted...); err != nil {
+		return SalesforceResults{Results: results}, err
 	}
 
 	for _, result := range results {
@@ -471,12 +791,35 @@ func doDeleteCollection(
 }
 
 func mapstructureDecode(input any, output any) error {
+	return decodeWithConfig(input, output, false, false)
+}
+
+// strictMapstructureDecode decodes like mapstructureDecode, but fails on keys of the input that are
+// missing from the output struct and fields of the output struct that are missing from the input
+func strictMapstructureDecode(input any, output any) error {
+	return decodeWithConfig(input, output, true, true)
+}
+
+// errorUnusedMapstructureDecode decodes like mapstructureDecode, but fails on keys of the input that
+// are missing from the output struct
+func errorUnusedMapstructureDecode(input any, output any) error {
+	return decodeWithConfig(input, output, true, false)
+}
+
+func decodeWithConfig(input any, output any, errorUnused bool, errorUnset bool) error {
 	config := &mapstructure.DecoderConfig{
 		Metadata: nil,
 		Result:   output,
 		// mapstructure is included here to maintain strict backwards compatibility, even though there was no
 		// documentation that this tag was supported. It should be removed in the next major version.
 		TagName: "salesforce,mapstructure",
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			polymorphicDecodeHook,
+			fieldsPresentDecodeHook,
+			subqueryDecodeHook,
+		),
+		ErrorUnused: errorUnused,
+		ErrorUnset:  errorUnset,
 	}
 
 	decoder, err := mapstructure.NewDecoder(config)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
@@ -117,6 +118,222 @@ func Test_convertToSliceOfMaps(t *testing.T) {
 	}
 }
 
+func Test_prepareRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       string
+		objects     []string
+		sObjectName string
+		want        []map[string]any
+	}{
+		{
+			name:        "bypass_all_objects",
+			field:       "Bypass__c",
+			sObjectName: "Account",
+			want: []map[string]any{
+				{"Name": "a", "Bypass__c": true},
+				{"Name": "b", "Bypass__c": true},
+			},
+		},
+		{
+			name:        "bypass_selected_object",
+			field:       "Bypass__c",
+			objects:     []string{"Account"},
+			sObjectName: "Account",
+			want: []map[string]any{
+				{"Name": "a", "Bypass__c": true},
+				{"Name": "b", "Bypass__c": true},
+			},
+		},
+		{
+			name:        "skip_other_object",
+			field:       "Bypass__c",
+			objects:     []string{"Contact"},
+			sObjectName: "Account",
+			want:        []map[string]any{{"Name": "a"}, {"Name": "b"}},
+		},
+		{
+			name:        "no_bypass_field",
+			sObjectName: "Account",
+			want:        []map[string]any{{"Name": "a"}, {"Name": "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&authentication{})
+			sf.config.automationBypassField = tt.field
+			sf.config.automationBypassObjects = tt.objects
+			records := []map[string]any{{"Name": "a"}, {"Name": "b"}}
+			if err := prepareRecords(sf, tt.sObjectName, insertOperation, records...); err != nil {
+				t.Fatalf("prepareRecords() error = %v", err)
+			}
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("prepareRecords() = %v, want %v", records, tt.want)
+			}
+		})
+	}
+}
+
+func Test_prepareRecords_fieldTruncation(t *testing.T) {
+	describe := SObjectDescribe{
+		Name:   "Account",
+		Fields: []DescribeField{{Name: "Name", Type: "string", Length: 3}},
+	}
+	server, sfAuth := setupTestServer(describe, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+	sf.config.fieldTruncation = true
+
+	records := []map[string]any{{"Name": "abcdef"}}
+	if err := prepareRecords(sf, "Account", updateOperation, records...); err != nil {
+		t.Fatalf("prepareRecords() error = %v", err)
+	}
+	if records[0]["Name"] != "abc" {
+		t.Errorf("prepareRecords() = %v, want truncated name", records)
+	}
+
+	badServer, badAuth := setupTestServer("", http.StatusNotFound)
+	defer badServer.Close()
+	sf = buildSalesforceStruct(&badAuth)
+	sf.config.fieldTruncation = true
+	if err := prepareRecords(sf, "Account", updateOperation, records...); err == nil {
+		t.Errorf("prepareRecords() expected error when describe fails")
+	}
+}
+
+func Test_prepareRecords_writableFieldsOnly(t *testing.T) {
+	describe := SObjectDescribe{
+		Name: "Account",
+		Fields: []DescribeField{
+			{Name: "Name", Type: "string", Length: 3, Createable: true, Updateable: true},
+			{Name: "LastModifiedDate", Type: "datetime"},
+		},
+	}
+	server, sfAuth := setupTestServer(describe, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+	sf.config.writableFieldsOnly = true
+	sf.config.fieldTruncation = true
+
+	records := []map[string]any{
+		{"Name": "abcdef", "LastModifiedDate": "2024-01-01T00:00:00.000+0000"},
+	}
+	if err := prepareRecords(sf, "Account", updateOperation, records...); err != nil {
+		t.Fatalf("prepareRecords() error = %v", err)
+	}
+	want := []map[string]any{{"Name": "abc"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("prepareRecords() = %v, want %v", records, want)
+	}
+}
+
+func Test_expandLookups(t *testing.T) {
+	tests := []struct {
+		name   string
+		record map[string]any
+		want   map[string]any
+	}{
+		{
+			name:   "path_field",
+			record: map[string]any{"LastName": "Grimm", "Account.External_Key__c": "A-1"},
+			want: map[string]any{
+				"LastName": "Grimm",
+				"Account":  map[string]any{"External_Key__c": "A-1"},
+			},
+		},
+		{
+			name: "merges_related_record",
+			record: map[string]any{
+				"Account": map[string]any{
+					"attributes": map[string]any{"type": "Account"},
+				},
+				"Account.External_Key__c": "A-1",
+			},
+			want: map[string]any{
+				"Account": map[string]any{
+					"attributes":      map[string]any{"type": "Account"},
+					"External_Key__c": "A-1",
+				},
+			},
+		},
+		{
+			name:   "no_lookups",
+			record: map[string]any{"Name": "Acme"},
+			want:   map[string]any{"Name": "Acme"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expandLookups(tt.record)
+			if !reflect.DeepEqual(tt.record, tt.want) {
+				t.Errorf("expandLookups() = %v, want %v", tt.record, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flattenLookups(t *testing.T) {
+	records := []map[string]any{
+		{
+			"Name": "Acme",
+			"Parent": map[string]any{
+				"attributes":      map[string]any{"type": "Account"},
+				"External_Key__c": "A-1",
+			},
+		},
+		{"Name": "Globex", "Phone": "555-0100"},
+	}
+	want := []map[string]any{
+		{"Name": "Acme", "Parent.External_Key__c": "A-1", "Phone": nil},
+		{"Name": "Globex", "Parent.External_Key__c": nil, "Phone": "555-0100"},
+	}
+	if got := flattenLookups(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenLookups() = %v, want %v", got, want)
+	}
+}
+
+func Test_doInsertOne_lookupByExternalId(t *testing.T) {
+	type accountRef struct {
+		ExternalKey string `salesforce:"External_Key__c"`
+	}
+	type contact struct {
+		LastName   string
+		Account    *accountRef `salesforce:"Account,omitempty"`
+		ReportsTo  string      `salesforce:"ReportsTo.Employee_Number__c,omitempty"`
+		AssignedTo string      `salesforce:"Assigned_To__r.Employee_Number__c,omitempty"`
+	}
+	server, sfAuth := setupTestServer(
+		SalesforceResult{Id: "003A", Success: true},
+		http.StatusCreated,
+	)
+	defer server.Close()
+	var body map[string]any
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		handler.ServeHTTP(w, r)
+	})
+	sf := buildSalesforceStruct(&sfAuth)
+
+	_, err := doInsertOne(sf, "Contact", contact{
+		LastName:  "Grimm",
+		Account:   &accountRef{ExternalKey: "A-1"},
+		ReportsTo: "E-7",
+	})
+	if err != nil {
+		t.Fatalf("doInsertOne() error = %v", err)
+	}
+	want := map[string]any{
+		"attributes": map[string]any{"type": "Contact"},
+		"LastName":   "Grimm",
+		"Account":    map[string]any{"External_Key__c": "A-1"},
+		"ReportsTo":  map[string]any{"Employee_Number__c": "E-7"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("doInsertOne() sent %v, want %v", body, want)
+	}
+}
+
 func Test_processSalesforceResponse(t *testing.T) {
 	message := []SalesforceErrorMessage{{
 		Message:    "example error",
@@ -247,7 +464,7 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 				},
 			},
 			want: SalesforceResults{
-				Results:             []SalesforceResult{{Success: true}, {Success: true}},
+				Results:             []SalesforceResult{{Success: true}, {Success: true, Index: 1}},
 				HasSalesforceErrors: false,
 			},
 			wantErr: false,
@@ -290,12 +507,18 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			encoded, ids, err := encodeRecordMaps(tt.args.sf.config, tt.args.recordMap)
+			if err != nil {
+				t.Fatalf("encodeRecordMaps() error = %v", err)
+			}
 			got, err := doBatchedRequestsForCollection(
 				tt.args.sf,
 				tt.args.method,
 				tt.args.url,
 				tt.args.batchSize,
-				tt.args.recordMap,
+				encoded,
+				ids,
+				nil,
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("doBatchedRequestsForCollection() error = %v, wantErr %v", err, tt.wantErr)
@@ -307,6 +530,118 @@ func Test_doBatchedRequestsForCollection(t *testing.T) {
 	}
 }
 
+func Test_nextCollectionBatch(t *testing.T) {
+	records := []map[string]any{
+		{"Description": strings.Repeat("a", 100)},
+		{"Description": strings.Repeat("b", 100)},
+		{"Description": strings.Repeat("c", 100)},
+		{"Description": strings.Repeat("d", 100)},
+	}
+	encoded, _, err := encodeRecordMaps(&configuration{}, records)
+	if err != nil {
+		t.Fatalf("encodeRecordMaps() error = %v", err)
+	}
+	tests := []struct {
+		name           string
+		maxRequestSize int
+		batchSize      int
+		wantLen        int
+	}{
+		{
+			name:           "no_limit",
+			maxRequestSize: 0,
+			batchSize:      200,
+			wantLen:        4,
+		},
+		{
+			name:           "within_limit",
+			maxRequestSize: 1000,
+			batchSize:      3,
+			wantLen:        3,
+		},
+		{
+			name:           "split_batch",
+			maxRequestSize: 300,
+			batchSize:      200,
+			wantLen:        2,
+		},
+		{
+			name:           "single_record_above_limit",
+			maxRequestSize: 50,
+			batchSize:      200,
+			wantLen:        1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+			config.maxRequestSize = tt.maxRequestSize
+
+			batchLen, body := nextCollectionBatch(&config, encoded, tt.batchSize)
+			if batchLen != tt.wantLen {
+				t.Errorf("nextCollectionBatch() batch length = %v, want %v", batchLen, tt.wantLen)
+			}
+			batch := records[:batchLen]
+			payload := sObjectCollection{}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Errorf("nextCollectionBatch() body is not a collection: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(payload.Records, batch) {
+				t.Errorf("nextCollectionBatch() body records = %v, want %v", payload.Records, batch)
+			}
+		})
+	}
+}
+
+func Test_doBatchedRequestsForCollection_maxRequestSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		payload := sObjectCollection{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		results := make([]SalesforceResult, len(payload.Records))
+		for i := range results {
+			results[i] = SalesforceResult{Success: true}
+		}
+		body, _ := json.Marshal(results)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.maxRequestSize = 300
+	records := []map[string]any{
+		{"Description": strings.Repeat("a", 100)},
+		{"Description": strings.Repeat("b", 100)},
+		{"Description": strings.Repeat("c", 100)},
+		{"Description": strings.Repeat("d", 100)},
+	}
+	encoded, ids, err := encodeRecordMaps(sf.config, records)
+	if err != nil {
+		t.Fatalf("encodeRecordMaps() error = %v", err)
+	}
+	got, err := doBatchedRequestsForCollection(sf, http.MethodPost, "", 200, encoded, ids, nil)
+	if err != nil {
+		t.Errorf("doBatchedRequestsForCollection() error = %v", err)
+		return
+	}
+	if requests != 2 {
+		t.Errorf("doBatchedRequestsForCollection() requests = %v, want %v", requests, 2)
+	}
+	if len(got.Results) != len(records) {
+		t.Errorf(
+			"doBatchedRequestsForCollection() results = %v, want %v",
+			len(got.Results),
+			len(records),
+		)
+	}
+}
+
 func Test_doInsertOne(t *testing.T) {
 	type account struct {
 		Name string
@@ -864,6 +1199,7 @@ func Test_doDeleteCollection(t *testing.T) {
 				Id:      "1234",
 				Errors:  []SalesforceErrorMessage{},
 				Success: true,
+				Index:   1,
 			},
 		},
 		HasSalesforceErrors: false,
@@ -0,0 +1,173 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+const findDuplicatesMax = 50
+
+// DuplicateMatch is an existing record that a matching rule found to be a duplicate
+type DuplicateMatch struct {
+	Id              string
+	SObjectName     string
+	MatchConfidence float64 // from 0 to 100, 100 for exact matches
+	MatchingRule    string
+	MatchEngine     string // ExactMatchEngine or FuzzyMatchEngine
+	DuplicateRule   string
+	AllowSave       bool              // whether the duplicate rule allows saving a duplicate with an alert
+	Fields          map[string]string // fields of the matched record shown by the duplicate rule
+}
+
+// DuplicateResult contains the existing records that the active duplicate rules matched to a record
+type DuplicateResult struct {
+	Matches []DuplicateMatch // sorted by descending match confidence
+	Success bool
+	Errors  []SalesforceErrorMessage
+}
+
+// IsDuplicate returns whether any existing record matched
+func (r DuplicateResult) IsDuplicate() bool {
+	return len(r.Matches) > 0
+}
+
+// MatchedIds returns the ids of the matched records, once each, by descending match confidence
+func (r DuplicateResult) MatchedIds() []string {
+	ids := []string{}
+	for _, match := range r.Matches {
+		if !slices.Contains(ids, match.Id) {
+			ids = append(ids, match.Id)
+		}
+	}
+	return ids
+}
+
+// FindDuplicates runs the active duplicate rules of an sObject against up to 50 records that are not
+// saved yet, with the SOAP API findDuplicates call, so that duplicates can be found before inserting.
+// records is a struct or map, or a slice of them, and a result is returned for each in order.
+func (sf *Salesforce) FindDuplicates(sObjectName string, records any) ([]DuplicateResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if records == nil {
+		return nil, errors.New("records are required")
+	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return nil, nameErr
+	}
+
+	var recordMaps []map[string]any
+	if reflect.TypeOf(records).Kind() == reflect.Slice {
+		converted, err := convertToSliceOfMaps(records)
+		if err != nil {
+			return nil, err
+		}
+		recordMaps = converted
+	} else {
+		if err := validateOfTypeStructOrMap(records); err != nil {
+			return nil, err
+		}
+		recordMap, err := convertToMap(records)
+		if err != nil {
+			return nil, err
+		}
+		recordMaps = []map[string]any{recordMap}
+	}
+	if len(recordMaps) == 0 || len(recordMaps) > findDuplicatesMax {
+		return nil, fmt.Errorf("between 1 and %d records can be checked", findDuplicatesMax)
+	}
+
+	call := soapFindDuplicatesRequest{}
+	for _, recordMap := range recordMaps {
+		fields := make(map[string]any, len(recordMap))
+		for name, value := range recordMap {
+			if name != "Id" && name != "attributes" {
+				fields[name] = value
+			}
+		}
+		id, _ := recordMap["Id"].(string)
+		call.SObjects = append(call.SObjects, newSoapSObject(sObjectName, id, fields))
+	}
+	results, err := soapFindDuplicates(sf, call, len(call.SObjects))
+	if err != nil {
+		return nil, err
+	}
+	return duplicateResults(results), nil
+}
+
+// FindDuplicatesByIds runs the active duplicate rules against up to 50 saved records, with the SOAP API
+// findDuplicatesByIds call. A result is returned for each id in order; records never match themselves.
+func (sf *Salesforce) FindDuplicatesByIds(recordIds []string) ([]DuplicateResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if len(recordIds) == 0 || len(recordIds) > findDuplicatesMax {
+		return nil, fmt.Errorf("between 1 and %d record ids are required", findDuplicatesMax)
+	}
+	for _, id := range recordIds {
+		if !IsValidId(id) {
+			return nil, fmt.Errorf("invalid id: %q", id)
+		}
+	}
+
+	results, err := soapFindDuplicates(
+		sf,
+		soapFindDuplicatesByIdsRequest{Ids: recordIds},
+		len(recordIds),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return duplicateResults(results), nil
+}
+
+func duplicateResults(results []soapFindDuplicatesResult) []DuplicateResult {
+	duplicates := make([]DuplicateResult, len(results))
+	for i, result := range results {
+		duplicate := DuplicateResult{
+			Success: result.Success,
+			Errors:  soapErrors(result.Errors),
+			Matches: []DuplicateMatch{},
+		}
+		for _, duplicateResult := range result.DuplicateResults {
+			for _, matchResult := range duplicateResult.MatchResults {
+				duplicate.Errors = append(duplicate.Errors, soapErrors(matchResult.Errors)...)
+				for _, matchRecord := range matchResult.MatchRecords {
+					match := DuplicateMatch{
+						Id:              matchRecord.Record.Id,
+						SObjectName:     matchRecord.Record.Type,
+						MatchConfidence: matchRecord.MatchConfidence,
+						MatchingRule:    matchResult.Rule,
+						MatchEngine:     matchResult.MatchEngine,
+						DuplicateRule:   duplicateResult.DuplicateRule,
+						AllowSave:       duplicateResult.AllowSave,
+						Fields:          map[string]string{},
+					}
+					if match.SObjectName == "" {
+						match.SObjectName = matchResult.EntityType
+					}
+					for _, field := range matchRecord.Record.Fields {
+						match.Fields[field.XMLName.Local] = field.Value
+					}
+					duplicate.Matches = append(duplicate.Matches, match)
+				}
+			}
+		}
+		slices.SortStableFunc(duplicate.Matches, func(a, b DuplicateMatch) int {
+			switch {
+			case a.MatchConfidence > b.MatchConfidence:
+				return -1
+			case a.MatchConfidence < b.MatchConfidence:
+				return 1
+			}
+			return 0
+		})
+		duplicates[i] = duplicate
+	}
+	return duplicates
+}
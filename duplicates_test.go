@@ -0,0 +1,132 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const findDuplicatesResponse = `<result><duplicateResults>` +
+	`<allowSave>true</allowSave><duplicateRule>Standard_Account_Duplicate_Rule</duplicateRule>` +
+	`<matchResults><entityType>Account</entityType>` +
+	`<matchEngine>FuzzyMatchEngine</matchEngine>` +
+	`<matchRecords><matchConfidence>82.5</matchConfidence>` +
+	`<record xmlns:sf="urn:sobject.partner.soap.sforce.com">` +
+	`<sf:type>Account</sf:type><sf:Id>001000000000002AAA</sf:Id><sf:Name>Acme Inc</sf:Name>` +
+	`</record></matchRecords>` +
+	`<matchRecords><matchConfidence>100</matchConfidence>` +
+	`<record xmlns:sf="urn:sobject.partner.soap.sforce.com">` +
+	`<sf:type>Account</sf:type><sf:Id>001000000000001AAA</sf:Id><sf:Name>Acme</sf:Name>` +
+	`</record></matchRecords>` +
+	`<rule>Standard_Account_Match_Rule_v1_0</rule><size>2</size><success>true</success>` +
+	`</matchResults></duplicateResults><success>true</success></result>` +
+	`<result><success>true</success></result>`
+
+func TestSalesforce_FindDuplicates(t *testing.T) {
+	server, requests := setupSoapTestServer(
+		t,
+		http.StatusOK,
+		`<findDuplicatesResponse>`+findDuplicatesResponse+`</findDuplicatesResponse>`,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	type account struct {
+		_    struct{} `salesforce:"object=Account"`
+		Name string
+	}
+	results, err := sf.FindDuplicates("", []account{{Name: "Acme"}, {Name: "Globex"}})
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	want := []DuplicateResult{
+		{
+			Success: true,
+			Errors:  []SalesforceErrorMessage{},
+			Matches: []DuplicateMatch{
+				{
+					Id:              "001000000000001AAA",
+					SObjectName:     "Account",
+					MatchConfidence: 100,
+					MatchingRule:    "Standard_Account_Match_Rule_v1_0",
+					MatchEngine:     "FuzzyMatchEngine",
+					DuplicateRule:   "Standard_Account_Duplicate_Rule",
+					AllowSave:       true,
+					Fields:          map[string]string{"Name": "Acme"},
+				},
+				{
+					Id:              "001000000000002AAA",
+					SObjectName:     "Account",
+					MatchConfidence: 82.5,
+					MatchingRule:    "Standard_Account_Match_Rule_v1_0",
+					MatchEngine:     "FuzzyMatchEngine",
+					DuplicateRule:   "Standard_Account_Duplicate_Rule",
+					AllowSave:       true,
+					Fields:          map[string]string{"Name": "Acme Inc"},
+				},
+			},
+		},
+		{Success: true, Errors: []SalesforceErrorMessage{}, Matches: []DuplicateMatch{}},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("FindDuplicates() = %+v, want %+v", results, want)
+	}
+	if !results[0].IsDuplicate() || results[1].IsDuplicate() {
+		t.Errorf("IsDuplicate() = %v, %v", results[0].IsDuplicate(), results[1].IsDuplicate())
+	}
+	wantIds := []string{"001000000000001AAA", "001000000000002AAA"}
+	if ids := results[0].MatchedIds(); !reflect.DeepEqual(ids, wantIds) {
+		t.Errorf("MatchedIds() = %v, want %v", ids, wantIds)
+	}
+	if !strings.Contains((*requests)[0], "<findDuplicates") ||
+		strings.Count((*requests)[0], ">Account</type>") != 2 {
+		t.Errorf("FindDuplicates() request = %s", (*requests)[0])
+	}
+
+	invalid := []struct {
+		name        string
+		sObjectName string
+		records     any
+	}{
+		{name: "no_records", sObjectName: "Account", records: []map[string]any{}},
+		{name: "too_many_records", sObjectName: "Account", records: make([]map[string]any, 51)},
+		{name: "not_a_record", sObjectName: "Account", records: "Acme"},
+		{name: "no_sobject_name", records: map[string]any{"Name": "Acme"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sf.FindDuplicates(tt.sObjectName, tt.records); err == nil {
+				t.Errorf("FindDuplicates() expected error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_FindDuplicatesByIds(t *testing.T) {
+	server, requests := setupSoapTestServer(
+		t,
+		http.StatusOK,
+		`<findDuplicatesByIdsResponse>`+findDuplicatesResponse+`</findDuplicatesByIdsResponse>`,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	results, err := sf.FindDuplicatesByIds([]string{"001000000000003AAA", "001000000000004AAA"})
+	if err != nil {
+		t.Fatalf("FindDuplicatesByIds() error = %v", err)
+	}
+	if len(results) != 2 || len(results[0].Matches) != 2 {
+		t.Errorf("FindDuplicatesByIds() = %+v", results)
+	}
+	if !strings.Contains((*requests)[0], "<ids>001000000000003AAA</ids>") {
+		t.Errorf("FindDuplicatesByIds() request = %s", (*requests)[0])
+	}
+
+	if _, err := sf.FindDuplicatesByIds([]string{"001000000000003AAA"}); err == nil {
+		t.Errorf("FindDuplicatesByIds() expected error when the result count does not match")
+	}
+	if _, err := sf.FindDuplicatesByIds([]string{"invalid"}); err == nil {
+		t.Errorf("FindDuplicatesByIds() expected error for an invalid id")
+	}
+}
@@ -0,0 +1,112 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const emailAddressesMax = 5
+
+// Sender types of an EmailMessage
+const (
+	EmailSenderCurrentUser         = "CurrentUser"
+	EmailSenderDefaultWorkflowUser = "DefaultWorkflowUser"
+	EmailSenderOrgWideAddress      = "OrgWideEmailAddress"
+)
+
+// EmailMessage is an email sent through Salesforce with the Send Email invocable action
+type EmailMessage struct {
+	ToAddresses     []string `json:"emailAddressesArray,omitempty"` // up to 5 addresses
+	RecipientId     string   `json:"recipientId,omitempty"`         // contact, lead, or user to send to
+	Subject         string   `json:"emailSubject,omitempty"`
+	Body            string   `json:"emailBody,omitempty"`
+	EmailTemplateId string   `json:"emailTemplateId,omitempty"` // used instead of Subject and Body
+	RelatedRecordId string   `json:"relatedRecordId,omitempty"` // record the email is logged against
+	SenderType      string   `json:"senderType,omitempty"`      // CurrentUser if empty
+	SenderAddress   string   `json:"senderAddress,omitempty"`   // org-wide address for OrgWideEmailAddress
+	LogEmailOnSend  bool     `json:"logEmailOnSend,omitempty"`  // track the email in activity history
+	UseLineBreaks   bool     `json:"useLineBreaks,omitempty"`   // send a plain text Body as html
+}
+
+type invocableActionRequest struct {
+	Inputs []any `json:"inputs"`
+}
+
+type invocableActionResult struct {
+	ActionName string                   `json:"actionName"`
+	Errors     []SalesforceErrorMessage `json:"errors"`
+	IsSuccess  bool                     `json:"isSuccess"`
+}
+
+// SendEmail sends emails through Salesforce with the emailSimple invocable action, so that they use the
+// org's templates, branding, and deliverability settings and can be logged as activities. Messages
+// that fail are reported in the results rather than as an error.
+func (sf *Salesforce) SendEmail(messages []EmailMessage) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if len(messages) == 0 {
+		return SalesforceResults{}, errors.New("no email messages to send")
+	}
+	inputs := make([]any, len(messages))
+	for i, message := range messages {
+		if err := message.validate(); err != nil {
+			return SalesforceResults{}, fmt.Errorf("email message %d: %w", i, err)
+		}
+		inputs[i] = message
+	}
+
+	body, err := json.Marshal(invocableActionRequest{Inputs: inputs})
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/actions/standard/emailSimple",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	var actionResults []invocableActionResult
+	if err := json.Unmarshal(respBody, &actionResults); err != nil {
+		return SalesforceResults{}, err
+	}
+
+	results := SalesforceResults{}
+	for i, actionResult := range actionResults {
+		results.Results = append(results.Results, SalesforceResult{
+			Errors:  actionResult.Errors,
+			Success: actionResult.IsSuccess,
+			Index:   i,
+		})
+		results.HasSalesforceErrors = results.HasSalesforceErrors || !actionResult.IsSuccess
+	}
+	return results, nil
+}
+
+func (m EmailMessage) validate() error {
+	if len(m.ToAddresses) == 0 && m.RecipientId == "" {
+		return errors.New("to addresses or a recipient id are required")
+	}
+	if len(m.ToAddresses) > emailAddressesMax {
+		return fmt.Errorf("at most %d to addresses are allowed", emailAddressesMax)
+	}
+	if m.EmailTemplateId == "" && m.Subject == "" && m.Body == "" {
+		return errors.New("a subject and body or an email template id are required")
+	}
+	if m.SenderType == EmailSenderOrgWideAddress && m.SenderAddress == "" {
+		return errors.New("sender address is required for an org-wide email address")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSalesforce_SendEmail(t *testing.T) {
+	var sent invocableActionRequest
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(`[
+			{"actionName":"emailSimple","errors":null,"isSuccess":true,"outputValues":null},
+			{"actionName":"emailSimple","errors":[{"statusCode":"INVALID_EMAIL_ADDRESS","message":"Invalid email address","fields":[]}],"isSuccess":false,"outputValues":null}
+		]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := sf.SendEmail([]EmailMessage{
+		{
+			ToAddresses:    []string{"jane@example.com"},
+			Subject:        "Welcome",
+			Body:           "Hello Jane",
+			LogEmailOnSend: true,
+		},
+		{RecipientId: "003000000000001AAA", EmailTemplateId: "00X000000000001AAA"},
+	})
+	if err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	want := SalesforceResults{
+		Results: []SalesforceResult{
+			{Success: true},
+			{
+				Errors: []SalesforceErrorMessage{{
+					Message:    "Invalid email address",
+					StatusCode: "INVALID_EMAIL_ADDRESS",
+					Fields:     []string{},
+				}},
+				Index: 1,
+			},
+		},
+		HasSalesforceErrors: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SendEmail() = %+v, want %+v", got, want)
+	}
+	if path != "/services/data/"+apiVersion+"/actions/standard/emailSimple" {
+		t.Errorf("path = %s", path)
+	}
+	wantInputs := []any{
+		map[string]any{
+			"emailAddressesArray": []any{"jane@example.com"},
+			"emailSubject":        "Welcome",
+			"emailBody":           "Hello Jane",
+			"logEmailOnSend":      true,
+		},
+		map[string]any{
+			"recipientId":     "003000000000001AAA",
+			"emailTemplateId": "00X000000000001AAA",
+		},
+	}
+	if !reflect.DeepEqual(sent.Inputs, wantInputs) {
+		t.Errorf("inputs = %v, want %v", sent.Inputs, wantInputs)
+	}
+}
+
+func TestEmailMessage_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		message EmailMessage
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			message: EmailMessage{ToAddresses: []string{"jane@example.com"}, Body: "Hello"},
+		},
+		{
+			name:    "no_recipient",
+			message: EmailMessage{Subject: "Hello"},
+			wantErr: true,
+		},
+		{
+			name: "too_many_addresses",
+			message: EmailMessage{
+				ToAddresses: []string{
+					"a@x.com",
+					"b@x.com",
+					"c@x.com",
+					"d@x.com",
+					"e@x.com",
+					"f@x.com",
+				},
+				Body: "Hello",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no_content",
+			message: EmailMessage{RecipientId: "003000000000001AAA"},
+			wantErr: true,
+		},
+		{
+			name: "org_wide_without_address",
+			message: EmailMessage{
+				RecipientId:     "003000000000001AAA",
+				EmailTemplateId: "00X000000000001AAA",
+				SenderType:      EmailSenderOrgWideAddress,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.message.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structRecordField is a field of a struct record, named as convertToMap names it
+type structRecordField struct {
+	index     int
+	name      string
+	key       []byte // the name encoded as a JSON object key, with its colon
+	omitEmpty bool
+	omitZero  bool
+}
+
+// structRecordFieldsCache holds the fields of each struct record type, or nil for types that must be
+// converted to maps
+var structRecordFieldsCache sync.Map
+
+var attributesKey = []byte(`"attributes":`)
+
+// encodeStructCollection returns the JSON of each record of a slice of structs and their ids, encoded
+// directly from the structs rather than converted to maps and then marshaled, which reflects over each
+// record twice. The JSON is the same as that of the converted maps. It returns false if the records must
+// be converted to maps, such as when a DML hook or a record setting of the client changes them.
+func encodeStructCollection(
+	sf *Salesforce,
+	sObjectName string,
+	operation string,
+	externalIdField string,
+	records any,
+) ([][]byte, []string, bool, error) {
+	if recordsNeedMaps(sf.config, sObjectName, operation) {
+		return nil, nil, false, nil
+	}
+	value := reflect.ValueOf(records)
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() != reflect.Struct {
+		return nil, nil, false, nil
+	}
+	fields, ok := structRecordFields(value.Type().Elem())
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	skip := ""
+	if operation == insertOperation {
+		skip = "Id"
+	}
+	attributes, err := json.Marshal(map[string]string{"type": sObjectName})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	encoded := make([][]byte, value.Len())
+	ids := make([]string, value.Len())
+	var buf bytes.Buffer
+	for i := range encoded {
+		record := value.Index(i)
+		if operation != insertOperation {
+			ids[i], _ = structRecordValue(record, fields, "Id").(string)
+		}
+		if operation == updateOperation && ids[i] == "" {
+			return nil, nil, true, errors.New("salesforce id not found in object data")
+		}
+		if operation == upsertOperation {
+			externalId := structRecordValue(record, fields, externalIdField)
+			if _, err := checkForExternalId(
+				sObjectName,
+				externalIdField,
+				map[string]any{externalIdField: externalId},
+			); err != nil {
+				return nil, nil, true, err
+			}
+		}
+		buf.Reset()
+		err := encodeStructRecord(sf.config, &buf, record, fields, attributes, skip)
+		if err != nil {
+			return nil, nil, true, err
+		}
+		encoded[i] = bytes.Clone(buf.Bytes())
+	}
+	return encoded, ids, true, nil
+}
+
+// encodeRecordMaps returns the JSON of each record and their ids
+func encodeRecordMaps(config *configuration, records []map[string]any) ([][]byte, []string, error) {
+	encoded := make([][]byte, len(records))
+	for i, record := range records {
+		body, err := marshalJSON(config, record)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded[i] = body
+	}
+	return encoded, recordIds(records), nil
+}
+
+// recordsNeedMaps returns whether the records of a DML operation must be converted to maps, because a
+// hook or a record setting of the client reads or changes them
+func recordsNeedMaps(config *configuration, sObjectName string, operation string) bool {
+	return config.dmlHooks.before(operation) != nil ||
+		config.dmlHooks.after(operation) != nil ||
+		config.automationBypassField != "" ||
+		config.fieldTruncation ||
+		config.writableFieldsOnly ||
+		config.nameValidation ||
+		config.batchGrouping != nil ||
+		strings.EqualFold(sObjectName, "Account")
+}
+
+// structRecordFields returns the fields of a struct record type sorted by name, the order json.Marshal
+// writes the keys of a map in, and false if convertToMap treats a field specially, such as a nested
+// struct, a squashed or remaining field, or a lookup by external id like Account.External_Key__c
+func structRecordFields(t reflect.Type) ([]structRecordField, bool) {
+	if cached, ok := structRecordFieldsCache.Load(t); ok {
+		fields := cached.([]structRecordField)
+		return fields, fields != nil
+	}
+	fields := parseStructRecordFields(t)
+	structRecordFieldsCache.Store(t, fields)
+	return fields, fields != nil
+}
+
+func parseStructRecordFields(t reflect.Type) []structRecordField {
+	fieldsPresentType := reflect.TypeOf(FieldsPresent{})
+	fields := []structRecordField{}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Type == fieldsPresentType {
+			continue
+		}
+		tag := field.Tag.Get("salesforce")
+		if tag == "" {
+			tag = field.Tag.Get("mapstructure")
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct || names[name] || name == "attributes" ||
+			strings.Contains(name, ".") || strings.Contains(options, "squash") ||
+			strings.Contains(options, "remain") || strings.Contains(options, "deep") {
+			return nil
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil
+		}
+		names[name] = true
+		fields = append(fields, structRecordField{
+			index:     i,
+			name:      name,
+			key:       append(key, ':'),
+			omitEmpty: strings.Contains(options, "omitempty"),
+			omitZero:  strings.Contains(options, "omitzero"),
+		})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+	return fields
+}
+
+// encodeStructRecord writes the JSON of a struct record with the given attributes, skipping a field
+func encodeStructRecord(
+	config *configuration,
+	buf *bytes.Buffer,
+	record reflect.Value,
+	fields []structRecordField,
+	attributes []byte,
+	skip string,
+) error {
+	buf.WriteByte('{')
+	wroteAttributes := false
+	writeKey := func(key []byte) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+	}
+	for _, field := range fields {
+		if !wroteAttributes && field.name > "attributes" {
+			writeKey(attributesKey)
+			buf.Write(attributes)
+			wroteAttributes = true
+		}
+		value := record.Field(field.index)
+		if field.name == skip || omitStructRecordField(field, value) {
+			continue
+		}
+		data, err := marshalJSON(config, value.Interface())
+		if err != nil {
+			return err
+		}
+		writeKey(field.key)
+		buf.Write(data)
+	}
+	if !wroteAttributes {
+		writeKey(attributesKey)
+		buf.Write(attributes)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// omitStructRecordField returns whether convertToMap leaves a field out for its omitempty or omitzero
+// tag option, which checks pointers to slices by the slice they point to
+func omitStructRecordField(field structRecordField, value reflect.Value) bool {
+	if value.Kind() == reflect.Pointer && !value.IsNil() && value.Elem().Kind() == reflect.Slice {
+		value = value.Elem()
+	}
+	if field.omitZero && value.IsZero() {
+		return true
+	}
+	if !field.omitEmpty {
+		return false
+	}
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return value.IsNil()
+	}
+	return false
+}
+
+// structRecordValue returns the value of a field of a struct record, or nil if the record does not have
+// the field or leaves it out
+func structRecordValue(record reflect.Value, fields []structRecordField, name string) any {
+	for _, field := range fields {
+		if field.name != name {
+			continue
+		}
+		value := record.Field(field.index)
+		if omitStructRecordField(field, value) {
+			return nil
+		}
+		return value.Interface()
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package salesforce
+
+import (
+	"reflect"
+	"testing"
+)
+
+type encodeTestRecord struct {
+	Id          string
+	Name        string  `salesforce:"Name"`
+	Description *string `salesforce:"Description,omitempty"`
+	Amount      float64 `                                   mapstructure:"Amount__c"`
+	Tags        []string
+	Skipped     string `salesforce:"-"`
+	Lowercase   string `salesforce:"zeta__c"`
+	Value       any
+	Present     FieldsPresent
+	unexported  string
+}
+
+// encodeWithMaps encodes records the way collections of maps are encoded
+func encodeWithMaps(t *testing.T, sObjectName string, records any, operation string) [][]byte {
+	recordMaps, err := convertToSliceOfMaps(records)
+	if err != nil {
+		t.Fatalf("convertToSliceOfMaps() error = %v", err)
+	}
+	for _, record := range recordMaps {
+		if operation == insertOperation {
+			delete(record, "Id")
+		}
+		record["attributes"] = map[string]string{"type": sObjectName}
+	}
+	encoded, _, err := encodeRecordMaps(&configuration{}, recordMaps)
+	if err != nil {
+		t.Fatalf("encodeRecordMaps() error = %v", err)
+	}
+	return encoded
+}
+
+func Test_encodeStructCollection(t *testing.T) {
+	description := "a <b> description"
+	records := []encodeTestRecord{
+		{
+			Id:          "001000000000001AAA",
+			Name:        "test record 1",
+			Description: &description,
+			Amount:      1.5,
+			Tags:        []string{"a"},
+			Skipped:     "skipped",
+			Lowercase:   "z",
+			Value:       map[string]any{"nested": true},
+			Present:     FieldsPresent{"Name": true},
+			unexported:  "unexported",
+		},
+		{Id: "001000000000002AAA"},
+	}
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: "", AccessToken: "1234"})
+
+	for _, operation := range []string{insertOperation, updateOperation} {
+		t.Run(operation, func(t *testing.T) {
+			encoded, ids, ok, err := encodeStructCollection(
+				sf,
+				"Opportunity",
+				operation,
+				"",
+				records,
+			)
+			if err != nil || !ok {
+				t.Fatalf("encodeStructCollection() = %v, %v", ok, err)
+			}
+			want := encodeWithMaps(t, "Opportunity", records, operation)
+			for i := range encoded {
+				if string(encoded[i]) != string(want[i]) {
+					t.Errorf("encodeStructCollection() = %s, want %s", encoded[i], want[i])
+				}
+			}
+			wantIds := []string{"001000000000001AAA", "001000000000002AAA"}
+			if operation == insertOperation {
+				wantIds = []string{"", ""}
+			}
+			if !reflect.DeepEqual(ids, wantIds) {
+				t.Errorf("encodeStructCollection() ids = %v, want %v", ids, wantIds)
+			}
+		})
+	}
+}
+
+func Test_encodeStructCollection_fallback(t *testing.T) {
+	type nested struct {
+		Name    string
+		Account struct{ Name string }
+	}
+	type lookup struct {
+		Name       string
+		AccountKey string `salesforce:"Account.External_Key__c"`
+	}
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: "", AccessToken: "1234"})
+	hooked := buildSalesforceStruct(&authentication{InstanceUrl: "", AccessToken: "1234"})
+	hooked.config.dmlHooks = DMLHooks{
+		BeforeInsert: func(string, []map[string]any) error { return nil },
+	}
+
+	tests := []struct {
+		name        string
+		sf          *Salesforce
+		sObjectName string
+		records     any
+	}{
+		{name: "maps", sf: sf, sObjectName: "Contact", records: []map[string]any{{"Name": "test"}}},
+		{name: "nested_struct", sf: sf, sObjectName: "Contact", records: []nested{{Name: "test"}}},
+		{name: "lookup", sf: sf, sObjectName: "Contact", records: []lookup{{Name: "test"}}},
+		{name: "account", sf: sf, sObjectName: "Account", records: []encodeTestRecord{{}}},
+		{name: "hooks", sf: hooked, sObjectName: "Contact", records: []encodeTestRecord{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok, err := encodeStructCollection(
+				tt.sf,
+				tt.sObjectName,
+				insertOperation,
+				"",
+				tt.records,
+			)
+			if ok || err != nil {
+				t.Errorf("encodeStructCollection() = %v, %v, want a fallback to maps", ok, err)
+			}
+		})
+	}
+}
+
+func Test_encodeStructCollection_errors(t *testing.T) {
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: "", AccessToken: "1234"})
+	records := []encodeTestRecord{{Name: "test"}}
+	if _, _, _, err := encodeStructCollection(sf, "Contact", updateOperation, "", records); err == nil {
+		t.Error("encodeStructCollection() expected an error for an update without an Id")
+	}
+	_, _, _, err := encodeStructCollection(sf, "Contact", upsertOperation, "Amount__c", records)
+	if err == nil {
+		t.Error("encodeStructCollection() expected an error for an upsert without an external id")
+	}
+	records[0].Amount = 5
+	_, _, _, err = encodeStructCollection(sf, "Contact", upsertOperation, "Amount__c", records)
+	if err != nil {
+		t.Errorf("encodeStructCollection() error = %v", err)
+	}
+}
+
+func BenchmarkEncodeCollection(b *testing.B) {
+	description := "description"
+	records := make([]encodeTestRecord, 200)
+	for i := range records {
+		records[i] = encodeTestRecord{
+			Name:        "test record",
+			Description: &description,
+			Amount:      float64(i),
+			Tags:        []string{"a", "b"},
+		}
+	}
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: "", AccessToken: "1234"})
+
+	b.Run("structs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, _, _ = encodeStructCollection(sf, "Contact", insertOperation, "", records)
+		}
+	})
+	b.Run("maps", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			recordMaps, _ := convertToSliceOfMaps(records)
+			for _, record := range recordMaps {
+				delete(record, "Id")
+				record["attributes"] = map[string]string{"type": "Contact"}
+			}
+			_, _, _ = encodeRecordMaps(sf.config, recordMaps)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedFieldError is returned when a query filters, sorts, or groups by a field encrypted with
+// Shield Platform Encryption that does not support it, which Salesforce rejects with an error that does
+// not mention the encryption
+type EncryptedFieldError struct {
+	SObjectName string
+	Field       string // field as referenced by the query, such as SSN__c or Contact.SSN__c
+	Clause      string // WHERE, ORDER BY, or GROUP BY
+}
+
+func (e *EncryptedFieldError) Error() string {
+	return fmt.Sprintf(
+		"%s.%s is encrypted with Shield Platform Encryption and cannot be used in %s",
+		e.SObjectName,
+		e.Field,
+		e.Clause,
+	)
+}
+
+// soqlClauseEnds are the clauses that can follow each checked clause, in the order they appear
+var soqlClauseEnds = map[string][]string{
+	"WHERE":    {"WITH", "GROUP BY", "ORDER BY", "LIMIT", "OFFSET", "FOR"},
+	"GROUP BY": {"HAVING", "ORDER BY", "LIMIT", "OFFSET", "FOR"},
+	"ORDER BY": {"LIMIT", "OFFSET", "FOR"},
+}
+
+// EncryptedFields returns the fields of an sObject encrypted with Shield Platform Encryption, as read
+// from the cached sObject describe
+func (sf *Salesforce) EncryptedFields(sObjectName string) ([]DescribeField, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	describe, err := describeSObject(sf, sObjectName)
+	if err != nil {
+		return nil, err
+	}
+	var fields []DescribeField
+	for _, field := range describe.Fields {
+		if field.Encrypted {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// CheckEncryptedFields returns an EncryptedFieldError for each field of the WHERE, ORDER BY, and
+// GROUP BY clauses of a query that is encrypted with Shield Platform Encryption and cannot be filtered,
+// sorted, or grouped by, joined into one error. Fields of parent relationships, such as Account.Name,
+// are checked against the describe of the parent sObject. Subqueries are not checked.
+func (sf *Salesforce) CheckEncryptedFields(query string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	return checkEncryptedFields(sf, query)
+}
+
+func checkEncryptedFields(sf *Salesforce, query string) error {
+	from := soqlClauseIndex(query, "FROM")
+	if from < 0 {
+		return errors.New("query must have a FROM clause")
+	}
+	sObject := strings.Fields(query[from+len("FROM"):])
+	if len(sObject) == 0 {
+		return errors.New("query must have a FROM clause")
+	}
+	sObjectName := sObject[0]
+
+	var errs []error
+	for _, clause := range []string{"WHERE", "GROUP BY", "ORDER BY"} {
+		start := soqlClauseIndex(query, clause)
+		if start < 0 {
+			continue
+		}
+		start += len(clause)
+		end := len(query)
+		for _, next := range soqlClauseEnds[clause] {
+			if i := soqlClauseIndex(query[start:], next); i >= 0 && start+i < end {
+				end = start + i
+			}
+		}
+		for _, name := range soqlIdentifiers(query[start:end]) {
+			field, ok, err := resolveSoqlField(sf, sObjectName, name)
+			if err != nil {
+				return err
+			}
+			if ok && field.Encrypted && !encryptedFieldSupports(field, clause) {
+				errs = append(errs, &EncryptedFieldError{
+					SObjectName: sObjectName,
+					Field:       name,
+					Clause:      clause,
+				})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func encryptedFieldSupports(field DescribeField, clause string) bool {
+	switch clause {
+	case "WHERE":
+		return field.Filterable
+	case "ORDER BY":
+		return field.Sortable
+	}
+	return field.Groupable
+}
+
+// resolveSoqlField returns the describe of a field of an sObject, following parent relationships
+// such as Account.Owner.Name. It returns false if the name is not a field, such as a keyword, or
+// goes through a polymorphic relationship.
+func resolveSoqlField(
+	sf *Salesforce,
+	sObjectName string,
+	name string,
+) (DescribeField, bool, error) {
+	path := strings.Split(name, ".")
+	for i, part := range path {
+		describe, err := describeSObject(sf, sObjectName)
+		if err != nil {
+			return DescribeField{}, false, err
+		}
+		if i == len(path)-1 {
+			field, ok := describe.Field(part)
+			return field, ok, nil
+		}
+		sObjectName = ""
+		for _, field := range describe.Fields {
+			if strings.EqualFold(field.RelationshipName, part) && len(field.ReferenceTo) == 1 {
+				sObjectName = field.ReferenceTo[0]
+			}
+		}
+		if sObjectName == "" {
+			return DescribeField{}, false, nil
+		}
+	}
+	return DescribeField{}, false, nil
+}
+
+// soqlIdentifiers returns the words of a clause that may be field names, skipping string literals,
+// bind variables, and subqueries
+func soqlIdentifiers(clause string) []string {
+	var names []string
+	for i := 0; i < len(clause); i++ {
+		c := clause[i]
+		switch {
+		case c == '\'':
+			for i++; i < len(clause) && clause[i] != '\''; i++ {
+				if clause[i] == '\\' {
+					i++
+				}
+			}
+		case c == '(' && soqlClauseIndex(strings.TrimSpace(clause[i+1:]), "SELECT") == 0:
+			for depth := 1; depth > 0 && i+1 < len(clause); {
+				i++
+				switch clause[i] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+		case c == ':' || (c >= '0' && c <= '9'):
+			// bind variables and numbers, dates, and date literal arguments such as LAST_N_DAYS:5
+			for i+1 < len(clause) && (isSoqlWordChar(rune(clause[i+1])) ||
+				clause[i+1] == '-' || clause[i+1] == '.') {
+				i++
+			}
+		case isSoqlWordChar(rune(c)):
+			start := i
+			for i+1 < len(clause) && (isSoqlWordChar(rune(clause[i+1])) || clause[i+1] == '.') {
+				i++
+			}
+			names = append(names, clause[start:i+1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,134 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupEncryptedFieldServer() *httptest.Server {
+	describes := map[string]SObjectDescribe{
+		"Contact": {Name: "Contact", Fields: []DescribeField{
+			{Name: "Id", Type: "id", Filterable: true, Sortable: true, Groupable: true},
+			{Name: "LastName", Type: "string", Filterable: true, Sortable: true, Groupable: true},
+			{Name: "SSN__c", Type: "string", Encrypted: true},
+			// deterministic encryption supports filtering
+			{Name: "Email", Type: "email", Encrypted: true, Filterable: true},
+			{
+				Name:             "AccountId",
+				Type:             "reference",
+				ReferenceTo:      []string{"Account"},
+				RelationshipName: "Account",
+				Filterable:       true,
+			},
+		}},
+		"Account": {Name: "Account", Fields: []DescribeField{
+			{Name: "Name", Type: "string", Filterable: true, Sortable: true, Groupable: true},
+			{Name: "TaxId__c", Type: "string", Encrypted: true},
+		}},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, describe := range describes {
+			if strings.HasSuffix(r.URL.Path, "/sobjects/"+name+"/describe") {
+				_ = json.NewEncoder(w).Encode(describe)
+				return
+			}
+		}
+		_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+	}))
+}
+
+func TestSalesforce_CheckEncryptedFields(t *testing.T) {
+	server := setupEncryptedFieldServer()
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantFields []string
+		wantErr    bool
+	}{
+		{
+			name:  "no_encrypted_fields",
+			query: "SELECT Id, SSN__c FROM Contact WHERE LastName = 'SSN__c' ORDER BY LastName NULLS FIRST LIMIT 5",
+		},
+		{
+			name:       "filter_on_encrypted_field",
+			query:      "SELECT Id FROM Contact WHERE LastName LIKE 'S%' AND SSN__c = '123' LIMIT 5",
+			wantFields: []string{"WHERE SSN__c"},
+		},
+		{
+			name:  "deterministic_encryption_filter",
+			query: "SELECT Id FROM Contact WHERE Email = 'a@example.com'",
+		},
+		{
+			name:       "sort_and_group",
+			query:      "SELECT Email, COUNT(Id) FROM Contact GROUP BY Email ORDER BY Email",
+			wantFields: []string{"GROUP BY Email", "ORDER BY Email"},
+		},
+		{
+			name:       "parent_relationship",
+			query:      "SELECT Id FROM Contact WHERE CreatedDate = LAST_N_DAYS:5 ORDER BY Account.TaxId__c DESC",
+			wantFields: []string{"ORDER BY Account.TaxId__c"},
+		},
+		{
+			name:  "subquery_not_checked",
+			query: "SELECT Id FROM Account WHERE Id IN (SELECT AccountId FROM Contact WHERE SSN__c = '1')",
+		},
+		{
+			name:    "no_from",
+			query:   "SELECT Id",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sf.CheckEncryptedFields(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("CheckEncryptedFields() expected an error")
+				}
+				return
+			}
+			var got []string
+			if err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var fieldErr *EncryptedFieldError
+					if !errors.As(e, &fieldErr) {
+						t.Fatalf("CheckEncryptedFields() error = %v", e)
+					}
+					got = append(got, fieldErr.Clause+" "+fieldErr.Field)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("CheckEncryptedFields() = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestWithEncryptedFieldCheck_Query(t *testing.T) {
+	server := setupEncryptedFieldServer()
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.encryptedFieldCheck = true
+
+	records := []map[string]any{}
+	err := sf.Query("SELECT Id FROM Contact WHERE SSN__c = '123'", &records)
+	var fieldErr *EncryptedFieldError
+	if !errors.As(err, &fieldErr) || fieldErr.SObjectName != "Contact" {
+		t.Errorf("Query() error = %v, want an EncryptedFieldError", err)
+	}
+	if err := sf.Query("SELECT Id FROM Contact WHERE LastName = 'Smith'", &records); err != nil {
+		t.Errorf("Query() error = %v", err)
+	}
+
+	fields, err := sf.EncryptedFields("Contact")
+	if err != nil || len(fields) != 2 || fields[0].Name != "SSN__c" {
+		t.Errorf("EncryptedFields() = %v, %v", fields, err)
+	}
+}
@@ -0,0 +1,148 @@
+package salesforce
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Environment variables read by CredsFromEnv and InitFromEnv
+const (
+	EnvDomain        = "SF_DOMAIN"
+	EnvClientId      = "SF_CLIENT_ID"
+	EnvClientSecret  = "SF_CLIENT_SECRET"
+	EnvUsername      = "SF_USERNAME"
+	EnvPassword      = "SF_PASSWORD"
+	EnvSecurityToken = "SF_SECURITY_TOKEN"
+	EnvPrivateKey    = "SF_PRIVATE_KEY"     // path to the PEM encoded private key used by the JWT flow
+	EnvPrivateKeyPem = "SF_PRIVATE_KEY_PEM" // PEM encoded private key, alternative to SF_PRIVATE_KEY
+	EnvAccessToken   = "SF_ACCESS_TOKEN"
+	EnvAPIVersion    = "SF_API_VERSION"
+)
+
+// envFlowVariables are the environment variables of each authentication flow
+var envFlowVariables = []struct {
+	flow      AuthFlowType
+	variables []string
+}{
+	{
+		flow: AuthFlowUsernamePassword,
+		variables: []string{
+			EnvDomain, EnvUsername, EnvPassword, EnvSecurityToken, EnvClientId, EnvClientSecret,
+		},
+	},
+	{flow: AuthFlowClientCredentials, variables: []string{EnvDomain, EnvClientId, EnvClientSecret}},
+	{flow: AuthFlowAccessToken, variables: []string{EnvDomain, EnvAccessToken}},
+	{flow: AuthFlowJWT, variables: []string{EnvDomain, EnvUsername, EnvClientId, EnvPrivateKey}},
+}
+
+// CredsFromEnv builds credentials from environment variables, choosing the authentication flow the same
+// way as Init, so that the returned flow is the one Init will use:
+//   - Username/Password: SF_DOMAIN, SF_USERNAME, SF_PASSWORD, SF_SECURITY_TOKEN, SF_CLIENT_ID, SF_CLIENT_SECRET
+//   - Client Credentials: SF_DOMAIN, SF_CLIENT_ID, SF_CLIENT_SECRET
+//   - Access Token: SF_DOMAIN, SF_ACCESS_TOKEN
+//   - JWT: SF_DOMAIN, SF_USERNAME, SF_CLIENT_ID, SF_PRIVATE_KEY or SF_PRIVATE_KEY_PEM
+//
+// SF_CLIENT_ID and SF_CLIENT_SECRET are a Client Credentials flow unless every variable of the
+// Username/Password flow is set. An error naming the missing variables is returned when a flow is
+// only partially configured.
+func CredsFromEnv() (Creds, AuthFlowType, error) {
+	creds := Creds{
+		Domain:         strings.TrimSuffix(os.Getenv(EnvDomain), "/"),
+		Username:       os.Getenv(EnvUsername),
+		Password:       os.Getenv(EnvPassword),
+		SecurityToken:  os.Getenv(EnvSecurityToken),
+		ConsumerKey:    os.Getenv(EnvClientId),
+		ConsumerSecret: os.Getenv(EnvClientSecret),
+		ConsumerRSAPem: os.Getenv(EnvPrivateKeyPem),
+		AccessToken:    os.Getenv(EnvAccessToken),
+	}
+
+	if keyPath := os.Getenv(EnvPrivateKey); keyPath != "" {
+		if creds.ConsumerRSAPem != "" {
+			return Creds{}, AuthFlowUnknown, fmt.Errorf(
+				"only one of %s and %s can be set", EnvPrivateKey, EnvPrivateKeyPem,
+			)
+		}
+		key, err := afero.ReadFile(appFs, keyPath)
+		if err != nil {
+			return Creds{}, AuthFlowUnknown, fmt.Errorf("reading %s: %w", EnvPrivateKey, err)
+		}
+		creds.ConsumerRSAPem = string(key)
+	}
+
+	flow := detectAuthFlow(&configuration{}, creds)
+	var missing []string
+	switch {
+	case flow == AuthFlowUnknown:
+		flow, missing = closestEnvFlow(creds)
+		if flow == AuthFlowUnknown {
+			return Creds{}, flow, fmt.Errorf(
+				"no salesforce credentials found in the environment, set %s and the variables of an authentication flow",
+				EnvDomain,
+			)
+		}
+	case creds.Domain == "":
+		// access tokens are detected without a domain, which is the instance url of the session
+		missing = []string{EnvDomain}
+	}
+	if len(missing) > 0 {
+		return Creds{}, flow, fmt.Errorf(
+			"missing environment variables for %s flow: %s", flow, strings.Join(missing, ", "),
+		)
+	}
+	if !strings.HasPrefix(creds.Domain, "https://") {
+		return Creds{}, flow, fmt.Errorf("%s must be an https url: %s", EnvDomain, creds.Domain)
+	}
+
+	return creds, flow, nil
+}
+
+// closestEnvFlow returns the flow that partial credentials are closest to, which is the flow with the
+// largest share of its variables set besides SF_DOMAIN, and its missing variables
+func closestEnvFlow(creds Creds) (AuthFlowType, []string) {
+	values := map[string]string{
+		EnvDomain:        creds.Domain,
+		EnvUsername:      creds.Username,
+		EnvPassword:      creds.Password,
+		EnvSecurityToken: creds.SecurityToken,
+		EnvClientId:      creds.ConsumerKey,
+		EnvClientSecret:  creds.ConsumerSecret,
+		EnvPrivateKey:    creds.ConsumerRSAPem,
+		EnvAccessToken:   creds.AccessToken,
+	}
+	closest := AuthFlowUnknown
+	var closestMissing []string
+	closestShare := 0.0
+	for _, flow := range envFlowVariables {
+		var set int
+		var missing []string
+		for _, name := range flow.variables {
+			if values[name] == "" {
+				missing = append(missing, name)
+			} else if name != EnvDomain {
+				set++
+			}
+		}
+		share := float64(set) / float64(len(flow.variables)-1)
+		if share > closestShare {
+			closest, closestMissing, closestShare = flow.flow, missing, share
+		}
+	}
+	return closest, closestMissing
+}
+
+// InitFromEnv returns a new Salesforce instance using credentials from environment variables, see
+// CredsFromEnv. If SF_API_VERSION is set it is applied before the given options.
+func InitFromEnv(options ...Option) (*Salesforce, error) {
+	creds, _, err := CredsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if version := os.Getenv(EnvAPIVersion); version != "" {
+		options = append([]Option{WithAPIVersion(version)}, options...)
+	}
+	return Init(creds, options...)
+}
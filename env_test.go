@@ -0,0 +1,204 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range []string{
+		EnvDomain, EnvClientId, EnvClientSecret, EnvUsername, EnvPassword, EnvSecurityToken,
+		EnvPrivateKey, EnvPrivateKeyPem, EnvAccessToken, EnvAPIVersion,
+	} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func TestCredsFromEnv(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	if err := afero.WriteFile(appFs, "key.pem", []byte("pem contents"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantFlow AuthFlowType
+		wantPem  string
+		wantErr  bool
+	}{
+		{
+			name: "client_credentials",
+			env: map[string]string{
+				EnvDomain:       "https://example.my.salesforce.com/",
+				EnvClientId:     "id",
+				EnvClientSecret: "secret",
+			},
+			wantFlow: AuthFlowClientCredentials,
+			wantErr:  false,
+		},
+		{
+			name: "username_password",
+			env: map[string]string{
+				EnvDomain:        "https://example.my.salesforce.com",
+				EnvClientId:      "id",
+				EnvClientSecret:  "secret",
+				EnvUsername:      "user",
+				EnvPassword:      "pass",
+				EnvSecurityToken: "token",
+			},
+			wantFlow: AuthFlowUsernamePassword,
+			wantErr:  false,
+		},
+		{
+			name: "jwt_from_key_file",
+			env: map[string]string{
+				EnvDomain:     "https://example.my.salesforce.com",
+				EnvClientId:   "id",
+				EnvUsername:   "user",
+				EnvPrivateKey: "key.pem",
+			},
+			wantFlow: AuthFlowJWT,
+			wantPem:  "pem contents",
+			wantErr:  false,
+		},
+		{
+			name: "access_token",
+			env: map[string]string{
+				EnvDomain:      "https://example.my.salesforce.com",
+				EnvAccessToken: "token",
+			},
+			wantFlow: AuthFlowAccessToken,
+			wantErr:  false,
+		},
+		{
+			name: "username_password_missing_token",
+			env: map[string]string{
+				EnvDomain:       "https://example.my.salesforce.com",
+				EnvClientId:     "id",
+				EnvClientSecret: "secret",
+				EnvUsername:     "user",
+				EnvPassword:     "pass",
+			},
+			wantFlow: AuthFlowClientCredentials,
+			wantErr:  false,
+		},
+		{
+			name: "username_client_credentials",
+			env: map[string]string{
+				EnvDomain:       "https://example.my.salesforce.com",
+				EnvClientId:     "id",
+				EnvClientSecret: "secret",
+				EnvUsername:     "user",
+			},
+			wantFlow: AuthFlowClientCredentials,
+			wantErr:  false,
+		},
+		{
+			name: "partial_username_password",
+			env: map[string]string{
+				EnvDomain:   "https://example.my.salesforce.com",
+				EnvUsername: "user",
+				EnvPassword: "pass",
+			},
+			wantFlow: AuthFlowUsernamePassword,
+			wantErr:  true,
+		},
+		{
+			name: "access_token_missing_domain",
+			env: map[string]string{
+				EnvAccessToken: "token",
+			},
+			wantFlow: AuthFlowAccessToken,
+			wantErr:  true,
+		},
+		{
+			name: "missing_key_file",
+			env: map[string]string{
+				EnvDomain:     "https://example.my.salesforce.com",
+				EnvClientId:   "id",
+				EnvUsername:   "user",
+				EnvPrivateKey: "missing.pem",
+			},
+			wantFlow: AuthFlowUnknown,
+			wantErr:  true,
+		},
+		{
+			name: "insecure_domain",
+			env: map[string]string{
+				EnvDomain:       "http://example.my.salesforce.com",
+				EnvClientId:     "id",
+				EnvClientSecret: "secret",
+			},
+			wantFlow: AuthFlowClientCredentials,
+			wantErr:  true,
+		},
+		{
+			name: "partial_client_credentials",
+			env: map[string]string{
+				EnvDomain:   "https://example.my.salesforce.com",
+				EnvClientId: "id",
+			},
+			wantFlow: AuthFlowClientCredentials,
+			wantErr:  true,
+		},
+		{
+			name:     "no_credentials",
+			env:      map[string]string{},
+			wantFlow: AuthFlowUnknown,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			creds, flow, err := CredsFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CredsFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if flow != tt.wantFlow {
+				t.Errorf("CredsFromEnv() flow = %v, want %v", flow, tt.wantFlow)
+			}
+			if !tt.wantErr && creds.Domain != "https://example.my.salesforce.com" {
+				t.Errorf("CredsFromEnv() domain = %v", creds.Domain)
+			}
+			if creds.ConsumerRSAPem != tt.wantPem {
+				t.Errorf("CredsFromEnv() pem = %v, want %v", creds.ConsumerRSAPem, tt.wantPem)
+			}
+		})
+	}
+}
+
+func TestInitFromEnv(t *testing.T) {
+	body, _ := json.Marshal(authentication{AccessToken: "1234", InstanceUrl: "https://example"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := w.Write(body); err != nil {
+			panic(err)
+		}
+	}))
+	defer server.Close()
+
+	setEnv(t, map[string]string{
+		EnvDomain:       server.URL,
+		EnvClientId:     "id",
+		EnvClientSecret: "secret",
+	})
+	if _, err := InitFromEnv(); err == nil {
+		t.Errorf("InitFromEnv() expected error for non https domain")
+	}
+
+	setEnv(t, map[string]string{
+		EnvDomain:      "https://example.my.salesforce.com",
+		EnvAccessToken: "token",
+		EnvAPIVersion:  "invalid",
+	})
+	if _, err := InitFromEnv(); err == nil {
+		t.Errorf("InitFromEnv() expected error for invalid api version")
+	}
+}
@@ -0,0 +1,224 @@
+package salesforce
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Headers the EventBridge attaches to published messages
+const (
+	EventHeaderChannel       = "salesforce-channel"
+	EventHeaderReplayId      = "salesforce-replay-id"
+	EventHeaderEventType     = "salesforce-event-type"     // API name, ex: Order__e or AccountChangeEvent
+	EventHeaderSchemaVersion = "salesforce-schema-version" // fingerprint of the event's fields
+	EventHeaderChangeType    = "salesforce-change-type"    // change events only
+	EventHeaderEntityName    = "salesforce-entity-name"    // change events only
+	EventHeaderContentType   = "content-type"
+)
+
+// EventMessage is a Salesforce event translated for a message broker
+type EventMessage struct {
+	Topic   string
+	Key     []byte // record id of change events, EventUuid or replay id of platform events
+	Value   []byte // the event payload as json
+	Headers map[string]string
+}
+
+// EventPublisher publishes messages to a broker such as Kafka or NATS. Implement it with the client
+// of the broker, the bridge does not depend on any.
+type EventPublisher interface {
+	Publish(ctx context.Context, message EventMessage) error
+}
+
+// EventPublisherFunc adapts a function to an EventPublisher
+type EventPublisherFunc func(ctx context.Context, message EventMessage) error
+
+func (f EventPublisherFunc) Publish(ctx context.Context, message EventMessage) error {
+	return f(ctx, message)
+}
+
+// EventBridgeSettings configures an EventBridge
+type EventBridgeSettings struct {
+	Publisher       EventPublisher
+	Topic           func(channel string) string // topic of a channel (default ex: data.AccountChangeEvent)
+	ReplayStore     ReplayStore                 // stores the replay id of each published event, optional
+	DescribeSchemas bool                        // describe each event type to attach its schema version
+}
+
+// EventBridge republishes Platform Events and Change Data Capture events to a message broker, with the
+// channel, replay id, and event type attached as headers. Subscribe to the events with any CometD or
+// Pub/Sub API client and pass them to the bridge. When a replay store is set, the replay id of each
+// event is stored once it is published, so a subscriber that resumes from the store delivers every
+// event at least once.
+type EventBridge struct {
+	sf       *Salesforce
+	settings EventBridgeSettings
+	mu       sync.Mutex
+	schemas  map[string]string // schema versions by event type
+}
+
+// NewEventBridge returns an EventBridge that publishes events with the publisher of the settings
+func (sf *Salesforce) NewEventBridge(settings EventBridgeSettings) (*EventBridge, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if settings.Publisher == nil {
+		return nil, errors.New("event publisher is required")
+	}
+	if settings.Topic == nil {
+		settings.Topic = eventTopic
+	}
+	return &EventBridge{sf: sf, settings: settings, schemas: map[string]string{}}, nil
+}
+
+// HandleMessage publishes an event received as a streaming (CometD) message
+func (b *EventBridge) HandleMessage(ctx context.Context, data []byte) error {
+	message := cometdChangeEventMessage{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	if message.Channel == "" || message.Data.Payload == nil {
+		return errors.New("message is missing its channel or payload")
+	}
+	if _, ok := message.Data.Payload[changeEventHeaderField]; ok {
+		event, err := DecodeChangeEvent(data)
+		if err != nil {
+			return err
+		}
+		return b.HandleChangeEvent(ctx, event)
+	}
+	payload := map[string]any{}
+	for field, rawValue := range message.Data.Payload {
+		var value any
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			return err
+		}
+		payload[field] = value
+	}
+	return b.HandleEvent(ctx, message.Channel, message.Data.Event.ReplayId, payload)
+}
+
+// HandleEvent publishes a Platform Event, such as one received and decoded with the Pub/Sub API
+func (b *EventBridge) HandleEvent(
+	ctx context.Context,
+	channel string,
+	replayId int64,
+	payload map[string]any,
+) error {
+	key := strconv.FormatInt(replayId, 10)
+	if uuid, ok := payload["EventUuid"].(string); ok && uuid != "" {
+		key = uuid
+	}
+	eventType := channel[strings.LastIndex(channel, "/")+1:]
+	return b.publish(ctx, channel, replayId, eventType, key, payload, nil)
+}
+
+// HandleChangeEvent publishes a Change Data Capture event, keyed by its first record id so that brokers
+// which partition by key keep the changes of a record in order
+func (b *EventBridge) HandleChangeEvent(ctx context.Context, event ChangeEvent) error {
+	if event.Channel == "" {
+		return errors.New("change event is missing its channel")
+	}
+	b.sf.config.metrics.ObserveChangeEvent(event)
+	header := event.Header
+	payload := make(map[string]any, len(event.Fields)+1)
+	for field, value := range event.Fields {
+		payload[field] = value
+	}
+	payload[changeEventHeaderField] = header
+
+	key := header.EntityName
+	if len(header.RecordIds) > 0 {
+		key = header.RecordIds[0]
+	}
+	eventType := strings.TrimSuffix(header.EntityName, "__c")
+	if eventType != header.EntityName {
+		eventType += "__"
+	}
+	eventType += "ChangeEvent"
+	return b.publish(ctx, event.Channel, event.ReplayId, eventType, key, payload, map[string]string{
+		EventHeaderChangeType: header.ChangeType,
+		EventHeaderEntityName: header.EntityName,
+	})
+}
+
+func (b *EventBridge) publish(
+	ctx context.Context,
+	channel string,
+	replayId int64,
+	eventType string,
+	key string,
+	payload map[string]any,
+	extraHeaders map[string]string,
+) error {
+	value, err := marshalJSON(b.sf.config, payload)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		EventHeaderChannel:     channel,
+		EventHeaderReplayId:    strconv.FormatInt(replayId, 10),
+		EventHeaderEventType:   eventType,
+		EventHeaderContentType: jsonType,
+	}
+	for name, value := range extraHeaders {
+		headers[name] = value
+	}
+	if b.settings.DescribeSchemas {
+		version, err := b.schemaVersion(eventType)
+		if err != nil {
+			return err
+		}
+		headers[EventHeaderSchemaVersion] = version
+	}
+
+	err = b.settings.Publisher.Publish(ctx, EventMessage{
+		Topic:   b.settings.Topic(channel),
+		Key:     []byte(key),
+		Value:   value,
+		Headers: headers,
+	})
+	if err != nil {
+		return err
+	}
+	if b.settings.ReplayStore != nil {
+		return b.settings.ReplayStore.SetReplayId(channel, replayId)
+	}
+	return nil
+}
+
+// schemaVersion returns a fingerprint of the names and types of the event's fields, which changes
+// whenever fields are added, removed, or change type
+func (b *EventBridge) schemaVersion(eventType string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if version, ok := b.schemas[eventType]; ok {
+		return version, nil
+	}
+	describe, err := describeSObject(b.sf, eventType)
+	if err != nil {
+		return "", err
+	}
+	fields := make([]string, len(describe.Fields))
+	for i, field := range describe.Fields {
+		fields[i] = field.Name + ":" + field.Type
+	}
+	slices.Sort(fields)
+	sum := sha256.Sum256([]byte(strings.Join(fields, ",")))
+	version := hex.EncodeToString(sum[:8])
+	b.schemas[eventType] = version
+	return version, nil
+}
+
+// eventTopic converts a channel such as /event/Order__e to a topic such as event.Order__e
+func eventTopic(channel string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(channel, "/"), "/", ".")
+}
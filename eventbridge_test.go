@@ -0,0 +1,175 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEventBridge(t *testing.T) {
+	server, auth := setupTestServer(SObjectDescribe{
+		Name: "Order__e",
+		Fields: []DescribeField{
+			{Name: "Amount__c", Type: "double"},
+			{Name: "ReplayId", Type: "string"},
+		},
+	}, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	var published []EventMessage
+	replayStore := NewMemoryReplayStore()
+	bridge, err := sf.NewEventBridge(EventBridgeSettings{
+		Publisher: EventPublisherFunc(func(ctx context.Context, message EventMessage) error {
+			published = append(published, message)
+			return nil
+		}),
+		ReplayStore:     replayStore,
+		DescribeSchemas: true,
+	})
+	if err != nil {
+		t.Fatalf("NewEventBridge() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		message     string
+		wantTopic   string
+		wantKey     string
+		wantHeaders map[string]string
+		wantValue   map[string]any
+		wantReplay  int64
+		wantErr     bool
+	}{
+		{
+			name: "platform_event",
+			message: `{
+				"channel": "/event/Order__e",
+				"data": {
+					"payload": {"Amount__c": 10, "EventUuid": "c1f2"},
+					"event": {"replayId": 7}
+				}
+			}`,
+			wantTopic: "event.Order__e",
+			wantKey:   "c1f2",
+			wantHeaders: map[string]string{
+				EventHeaderChannel:       "/event/Order__e",
+				EventHeaderReplayId:      "7",
+				EventHeaderEventType:     "Order__e",
+				EventHeaderContentType:   jsonType,
+				EventHeaderSchemaVersion: "",
+			},
+			wantValue:  map[string]any{"Amount__c": float64(10), "EventUuid": "c1f2"},
+			wantReplay: 7,
+		},
+		{
+			name: "change_event",
+			message: `{
+				"channel": "/data/Invoice__ChangeEvent",
+				"data": {
+					"payload": {
+						"ChangeEventHeader": {
+							"entityName": "Invoice__c",
+							"recordIds": ["a01A"],
+							"changeType": "UPDATE"
+						},
+						"Total__c": 5
+					},
+					"event": {"replayId": 8}
+				}
+			}`,
+			wantTopic: "data.Invoice__ChangeEvent",
+			wantKey:   "a01A",
+			wantHeaders: map[string]string{
+				EventHeaderChannel:       "/data/Invoice__ChangeEvent",
+				EventHeaderReplayId:      "8",
+				EventHeaderEventType:     "Invoice__ChangeEvent",
+				EventHeaderContentType:   jsonType,
+				EventHeaderSchemaVersion: "",
+				EventHeaderChangeType:    ChangeTypeUpdate,
+				EventHeaderEntityName:    "Invoice__c",
+			},
+			wantReplay: 8,
+		},
+		{
+			name:    "missing_channel",
+			message: `{"data": {"payload": {"Amount__c": 10}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published = nil
+			err := bridge.HandleMessage(context.Background(), []byte(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(published) != 1 {
+				t.Fatalf("published %d messages, want 1", len(published))
+			}
+			message := published[0]
+			if message.Headers[EventHeaderSchemaVersion] == "" {
+				t.Errorf("HandleMessage() is missing the schema version")
+			}
+			tt.wantHeaders[EventHeaderSchemaVersion] = message.Headers[EventHeaderSchemaVersion]
+			if message.Topic != tt.wantTopic || string(message.Key) != tt.wantKey ||
+				!reflect.DeepEqual(message.Headers, tt.wantHeaders) {
+				t.Errorf(
+					"HandleMessage() = %s, %s, %v, want %s, %s, %v",
+					message.Topic,
+					message.Key,
+					message.Headers,
+					tt.wantTopic,
+					tt.wantKey,
+					tt.wantHeaders,
+				)
+			}
+			if tt.wantValue != nil {
+				value := map[string]any{}
+				if err := json.Unmarshal(message.Value, &value); err != nil ||
+					!reflect.DeepEqual(value, tt.wantValue) {
+					t.Errorf("HandleMessage() value = %s, want %v", message.Value, tt.wantValue)
+				}
+			}
+			replayId, _, _ := replayStore.GetReplayId(tt.wantHeaders[EventHeaderChannel])
+			if replayId != tt.wantReplay {
+				t.Errorf("stored replay id = %d, want %d", replayId, tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestEventBridge_publishError(t *testing.T) {
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example", AccessToken: "1234"},
+	)
+	replayStore := NewMemoryReplayStore()
+	bridge, err := sf.NewEventBridge(EventBridgeSettings{
+		Publisher: EventPublisherFunc(func(ctx context.Context, message EventMessage) error {
+			return errors.New("broker unavailable")
+		}),
+		Topic:       func(channel string) string { return "salesforce" },
+		ReplayStore: replayStore,
+	})
+	if err != nil {
+		t.Fatalf("NewEventBridge() error = %v", err)
+	}
+	err = bridge.HandleEvent(context.Background(), "/event/Order__e", 3, map[string]any{})
+	if err == nil {
+		t.Fatalf("HandleEvent() expected error")
+	}
+	// the replay id of an event that was not published is not stored
+	if _, found, _ := replayStore.GetReplayId("/event/Order__e"); found {
+		t.Errorf("replay id stored for an event that failed to publish")
+	}
+
+	if _, err := sf.NewEventBridge(EventBridgeSettings{}); err == nil {
+		t.Errorf("NewEventBridge() expected error without a publisher")
+	}
+}
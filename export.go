@@ -0,0 +1,412 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// RecordWriter writes exported records to a destination, such as a JSON Lines or Parquet file.
+// Close must be called once every record is written.
+type RecordWriter interface {
+	WriteRecord(record map[string]any) error
+	Close() error
+}
+
+// ExportQuery runs a query and writes every record to rw, reading one page of results at a time.
+// The attributes of records are not written. It returns the number of records written. The writer
+// is not closed.
+func (sf *Salesforce) ExportQuery(ctx context.Context, query string, rw RecordWriter) (int, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return 0, authErr
+	}
+
+	written := 0
+	nextRecordsUrl := "/query/?q=" + url.QueryEscape(query)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, sf, nextRecordsUrl)
+		if err != nil {
+			return written, err
+		}
+		for _, record := range queryResp.Records {
+			if err := rw.WriteRecord(removeAttributes(record)); err != nil {
+				return written, err
+			}
+			written++
+		}
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
+	return written, nil
+}
+
+// ExportBulkQuery runs a query as a Bulk API 2.0 query job and writes every result record to rw, reading
+// one batch of results at a time. Records are keyed by the CSV column names of the results, such as
+// Account.Name for related fields, and empty values are written as null. It returns the number of
+// records written. The writer is not closed.
+func (sf *Salesforce) ExportBulkQuery(query string, rw RecordWriter) (int, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return 0, authErr
+	}
+	body, jsonErr := json.Marshal(bulkQueryJobCreationRequest{
+		Operation: queryJobType,
+		Query:     query,
+	})
+	if jsonErr != nil {
+		return 0, jsonErr
+	}
+	job, jobCreationErr := createBulkJob(sf, queryJobType, body)
+	if jobCreationErr != nil {
+		return 0, jobCreationErr
+	}
+	if job.Id == "" {
+		return 0, errors.New("error creating bulk query job")
+	}
+	pollErr := waitForJobResults(sf, job.Id, queryJobType, (time.Second / 2))
+	if pollErr != nil {
+		return 0, pollErr
+	}
+
+	written := 0
+	locator := ""
+	for {
+		queryResults, resultsErr := getQueryJobResults(sf, job.Id, locator)
+		if resultsErr != nil {
+			return written, resultsErr
+		}
+		if len(queryResults.Data) > 0 {
+			headers := queryResults.Data[0]
+			for _, row := range queryResults.Data[1:] {
+				record := make(map[string]any, len(headers))
+				for i, header := range headers {
+					if i < len(row) && row[i] != "" {
+						record[header] = row[i]
+					} else {
+						record[header] = nil
+					}
+				}
+				if err := rw.WriteRecord(record); err != nil {
+					return written, err
+				}
+				written++
+			}
+		}
+		if queryResults.Locator == "" {
+			return written, nil
+		}
+		locator = queryResults.Locator
+	}
+}
+
+// removeAttributes returns a copy of a record without the attributes of it, its related records, and
+// the records of its subqueries
+func removeAttributes(record map[string]any) map[string]any {
+	stripped := make(map[string]any, len(record))
+	for field, value := range record {
+		if field == "attributes" {
+			continue
+		}
+		if related, ok := value.(map[string]any); ok {
+			value = removeAttributes(related)
+		}
+		if children, ok := value.([]any); ok {
+			strippedChildren := make([]any, len(children))
+			for i, child := range children {
+				if childRecord, ok := child.(map[string]any); ok {
+					child = removeAttributes(childRecord)
+				}
+				strippedChildren[i] = child
+			}
+			value = strippedChildren
+		}
+		stripped[field] = value
+	}
+	return stripped
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter returns a RecordWriter that writes each record to w as a line of JSON
+func NewJSONLWriter(w io.Writer) RecordWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &jsonlWriter{encoder: encoder}
+}
+
+func (w *jsonlWriter) WriteRecord(record map[string]any) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+// parquetColumn is a column of a Parquet export and the describe field type of its values
+type parquetColumn struct {
+	name      string
+	fieldType string
+}
+
+type parquetRecordWriter struct {
+	writer  *parquet.Writer
+	columns []parquetColumn
+}
+
+// NewParquetWriter returns a RecordWriter that writes records to w as an Apache Parquet file. The schema
+// is inferred from the describe metadata of the sObject: every column is optional and typed after its
+// field, with booleans, integers, and doubles written as such, dates and datetimes as logical DATE and
+// TIMESTAMP columns, and other fields as strings. Fields of related records are named by their path,
+// such as Account.Name. If no fields are given, all fields of the sObject are written.
+func (sf *Salesforce) NewParquetWriter(
+	w io.Writer,
+	sObjectName string,
+	fields ...string,
+) (RecordWriter, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	describe, err := describeSObject(sf, sObjectName)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		for _, field := range describe.Fields {
+			fields = append(fields, field.Name)
+		}
+	}
+
+	group := parquet.Group{}
+	fieldTypes := map[string]string{}
+	for _, path := range fields {
+		name, field, err := describeFieldPath(sf, describe, path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := group[name]; ok {
+			return nil, fmt.Errorf("duplicate field: %s", name)
+		}
+		group[name] = parquet.Optional(parquetNode(field.Type))
+		fieldTypes[name] = field.Type
+	}
+
+	schema := parquet.NewSchema(describe.Name, group)
+	columns := make([]parquetColumn, 0, len(group))
+	for _, field := range schema.Fields() {
+		columns = append(
+			columns,
+			parquetColumn{name: field.Name(), fieldType: fieldTypes[field.Name()]},
+		)
+	}
+	return &parquetRecordWriter{
+		writer:  parquet.NewWriter(w, schema),
+		columns: columns,
+	}, nil
+}
+
+// describeFieldPath returns the name and describe metadata of a field, following relationship names of
+// paths such as Account.Owner.Name to the describe of the related sObject
+func describeFieldPath(
+	sf *Salesforce,
+	describe *SObjectDescribe,
+	path string,
+) (string, DescribeField, error) {
+	parts := strings.Split(path, ".")
+	names := make([]string, len(parts))
+	for i, part := range parts[:len(parts)-1] {
+		relationship, ok := describeRelationship(describe, part)
+		if !ok || len(relationship.ReferenceTo) == 0 {
+			return "", DescribeField{}, fmt.Errorf(
+				"%s has no relationship %s: %s",
+				describe.Name,
+				part,
+				path,
+			)
+		}
+		names[i] = relationship.RelationshipName
+		related, err := describeSObject(sf, relationship.ReferenceTo[0])
+		if err != nil {
+			return "", DescribeField{}, err
+		}
+		describe = related
+	}
+	field, ok := describe.Field(parts[len(parts)-1])
+	if !ok {
+		return "", DescribeField{}, fmt.Errorf("%s has no field %s", describe.Name, path)
+	}
+	names[len(names)-1] = field.Name
+	return strings.Join(names, "."), field, nil
+}
+
+// describeRelationship returns the lookup field with the given relationship name
+func describeRelationship(describe *SObjectDescribe, name string) (DescribeField, bool) {
+	for _, field := range describe.Fields {
+		if field.RelationshipName != "" && strings.EqualFold(field.RelationshipName, name) {
+			return field, true
+		}
+	}
+	return DescribeField{}, false
+}
+
+// parquetNode returns the Parquet type of a describe field type
+func parquetNode(fieldType string) parquet.Node {
+	switch fieldType {
+	case "boolean":
+		return parquet.Leaf(parquet.BooleanType)
+	case "int":
+		return parquet.Int(32)
+	case "long":
+		return parquet.Int(64)
+	case "double", "currency", "percent":
+		return parquet.Leaf(parquet.DoubleType)
+	case "date":
+		return parquet.Date()
+	case "datetime":
+		return parquet.Timestamp(parquet.Millisecond)
+	default:
+		return parquet.String()
+	}
+}
+
+func (w *parquetRecordWriter) WriteRecord(record map[string]any) error {
+	row := make(parquet.Row, len(w.columns))
+	for i, column := range w.columns {
+		value, err := parquetValue(column.fieldType, recordPathValue(record, column.name))
+		if err != nil {
+			return fmt.Errorf("%s: %w", column.name, err)
+		}
+		definitionLevel := 1
+		if value.IsNull() {
+			definitionLevel = 0
+		}
+		row[i] = value.Level(0, definitionLevel, i)
+	}
+	_, err := w.writer.WriteRows([]parquet.Row{row})
+	return err
+}
+
+func (w *parquetRecordWriter) Close() error {
+	return w.writer.Close()
+}
+
+// recordPathValue returns the value of a field of a record, either keyed by its full path, as in bulk
+// query results, or nested in related records. Field names are matched case-insensitively.
+func recordPathValue(record map[string]any, path string) any {
+	if value, ok := recordField(record, path); ok {
+		return value
+	}
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		related, _ := recordField(record, part)
+		record, _ = related.(map[string]any)
+		if record == nil {
+			return nil
+		}
+	}
+	value, _ := recordField(record, parts[len(parts)-1])
+	return value
+}
+
+func recordField(record map[string]any, name string) (any, bool) {
+	if value, ok := record[name]; ok {
+		return value, true
+	}
+	for field, value := range record {
+		if strings.EqualFold(field, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// parquetValue converts a field value, as returned by the REST API or as a bulk query CSV string, to
+// the Parquet value of its describe field type
+func parquetValue(fieldType string, value any) (parquet.Value, error) {
+	if value == nil {
+		return parquet.Value{}, nil
+	}
+	switch fieldType {
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return parquet.BooleanValue(v), nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return parquet.Value{}, err
+			}
+			return parquet.BooleanValue(b), nil
+		}
+	case "int", "long", "double", "currency", "percent":
+		number, err := exportNumber(value)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		switch fieldType {
+		case "int":
+			return parquet.Int32Value(int32(number)), nil
+		case "long":
+			return parquet.Int64Value(int64(number)), nil
+		}
+		return parquet.DoubleValue(number), nil
+	case "date":
+		if v, ok := value.(string); ok {
+			date, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return parquet.Value{}, err
+			}
+			return parquet.Int32Value(int32(date.Unix() / (24 * 60 * 60))), nil
+		}
+	case "datetime":
+		if v, ok := value.(string); ok {
+			datetime, err := time.Parse(salesforceDateTimeFormat, v)
+			if err != nil {
+				datetime, err = time.Parse(time.RFC3339Nano, v)
+			}
+			if err != nil {
+				return parquet.Value{}, err
+			}
+			return parquet.Int64Value(datetime.UnixMilli()), nil
+		}
+	default:
+		if v, ok := value.(string); ok {
+			return parquet.ByteArrayValue([]byte(v)), nil
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.ByteArrayValue(encoded), nil
+	}
+	return parquet.Value{}, fmt.Errorf("unexpected %s value: %v", fieldType, value)
+}
+
+func exportNumber(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, fmt.Errorf("unexpected number: %v", value)
+}
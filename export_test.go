@@ -0,0 +1,276 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestSalesforce_ExportQuery(t *testing.T) {
+	pages := map[string]queryResponse{
+		"/services/data/" + apiVersion + "/query/": {
+			TotalSize:      3,
+			Done:           false,
+			NextRecordsUrl: "/services/data/" + apiVersion + "/query/01gD-2000",
+			Records: []map[string]any{
+				{
+					"attributes": map[string]any{"type": "Contact"},
+					"Id":         "003A",
+					"Account": map[string]any{
+						"attributes": map[string]any{"type": "Account"},
+						"Name":       "Acme",
+					},
+				},
+				{"attributes": map[string]any{"type": "Contact"}, "Id": "003B", "Account": nil},
+			},
+		},
+		"/services/data/" + apiVersion + "/query/01gD-2000": {
+			TotalSize: 3,
+			Done:      true,
+			Records: []map[string]any{
+				{"attributes": map[string]any{"type": "Contact"}, "Id": "003C"},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(page)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	var buf bytes.Buffer
+	got, err := sf.ExportQuery(
+		context.Background(),
+		"SELECT Id, Account.Name FROM Contact",
+		NewJSONLWriter(&buf),
+	)
+	if err != nil {
+		t.Fatalf("ExportQuery() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("ExportQuery() = %v, want %v", got, 3)
+	}
+	want := `{"Account":{"Name":"Acme"},"Id":"003A"}` + "\n" +
+		`{"Account":null,"Id":"003B"}` + "\n" +
+		`{"Id":"003C"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("ExportQuery() wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSalesforce_ExportBulkQuery(t *testing.T) {
+	jobCreationRespBody, _ := json.Marshal(bulkJob{Id: "1234", State: jobStateJobComplete})
+	jobResultsRespBody, _ := json.Marshal(
+		BulkJobResults{Id: "1234", State: jobStateJobComplete},
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/jobs/query"):
+			_, _ = w.Write(jobCreationRespBody)
+		case strings.HasSuffix(r.URL.Path, "/jobs/query/1234"):
+			_, _ = w.Write(jobResultsRespBody)
+		case r.URL.Query().Get("locator") == "":
+			w.Header().Add("Sforce-Locator", "2")
+			w.Header().Add("Sforce-Numberofrecords", "1")
+			_, _ = w.Write([]byte("\"Id\",\"Account.Name\"\n\"003A\",\"Acme\"\n"))
+		default:
+			w.Header().Add("Sforce-Locator", "null")
+			w.Header().Add("Sforce-Numberofrecords", "1")
+			_, _ = w.Write([]byte("\"Id\",\"Account.Name\"\n\"003B\",\"\"\n"))
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	var buf bytes.Buffer
+	got, err := sf.ExportBulkQuery("SELECT Id, Account.Name FROM Contact", NewJSONLWriter(&buf))
+	if err != nil {
+		t.Fatalf("ExportBulkQuery() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("ExportBulkQuery() = %v, want %v", got, 2)
+	}
+	want := `{"Account.Name":"Acme","Id":"003A"}` + "\n" +
+		`{"Account.Name":null,"Id":"003B"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("ExportBulkQuery() wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSalesforce_NewParquetWriter(t *testing.T) {
+	describes := map[string]SObjectDescribe{
+		"Contact": {
+			Name: "Contact",
+			Fields: []DescribeField{
+				{Name: "Id", Type: "id"},
+				{Name: "Birthdate", Type: "date"},
+				{Name: "CreatedDate", Type: "datetime"},
+				{Name: "HasOptedOutOfEmail", Type: "boolean"},
+				{Name: "NumberOfVisits__c", Type: "int"},
+				{Name: "Score__c", Type: "double"},
+				{
+					Name:             "AccountId",
+					Type:             "reference",
+					RelationshipName: "Account",
+					ReferenceTo:      []string{"Account"},
+				},
+			},
+		},
+		"Account": {
+			Name:   "Account",
+			Fields: []DescribeField{{Name: "Name", Type: "string"}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.URL.Path, "/")
+		describe, ok := describes[parts[len(parts)-2]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(describe)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	t.Run("writes_typed_columns", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, err := sf.NewParquetWriter(
+			&buf,
+			"Contact",
+			"Id",
+			"Birthdate",
+			"CreatedDate",
+			"HasOptedOutOfEmail",
+			"NumberOfVisits__c",
+			"Score__c",
+			"account.name",
+		)
+		if err != nil {
+			t.Fatalf("NewParquetWriter() error = %v", err)
+		}
+		records := []map[string]any{
+			{
+				"Id":                 "003A",
+				"Birthdate":          "1990-05-17",
+				"CreatedDate":        "2024-01-02T03:04:05.000+0000",
+				"HasOptedOutOfEmail": true,
+				"NumberOfVisits__c":  float64(4),
+				"Score__c":           12.5,
+				"Account":            map[string]any{"Name": "Acme"},
+			},
+			{
+				"Id":                 "003B",
+				"CreatedDate":        "2024-01-02T03:04:05.000Z",
+				"HasOptedOutOfEmail": "false",
+				"NumberOfVisits__c":  "7",
+				"Account.Name":       nil,
+			},
+		}
+		for _, record := range records {
+			if err := rw.WriteRecord(record); err != nil {
+				t.Fatalf("WriteRecord() error = %v", err)
+			}
+		}
+		if err := rw.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("parquet.OpenFile() error = %v", err)
+		}
+		if leaf, _ := file.Schema().Lookup("Birthdate"); leaf.Node.Type().
+			LogicalType().
+			Date == nil {
+			t.Errorf("Birthdate type = %v, want DATE", leaf.Node.Type())
+		}
+		leaf, _ := file.Schema().Lookup("CreatedDate")
+		if leaf.Node.Type().LogicalType().Timestamp == nil {
+			t.Errorf("CreatedDate type = %v, want TIMESTAMP", leaf.Node.Type())
+		}
+
+		rows := make([]parquet.Row, 3)
+		n, _ := parquet.NewReader(file).ReadRows(rows)
+		columns := file.Schema().Columns()
+		got := make([]map[string]any, n)
+		for i, row := range rows[:n] {
+			got[i] = map[string]any{}
+			for _, value := range row {
+				var v any
+				switch {
+				case value.IsNull():
+				case value.Kind() == parquet.Boolean:
+					v = value.Boolean()
+				case value.Kind() == parquet.Int32:
+					v = value.Int32()
+				case value.Kind() == parquet.Int64:
+					v = value.Int64()
+				case value.Kind() == parquet.Double:
+					v = value.Double()
+				default:
+					v = string(value.ByteArray())
+				}
+				got[i][strings.Join(columns[value.Column()], ".")] = v
+			}
+		}
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()
+		want := []map[string]any{
+			{
+				"Id": "003A",
+				"Birthdate": int32(
+					time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC).Unix() / 86400,
+				),
+				"CreatedDate":        created,
+				"HasOptedOutOfEmail": true,
+				"NumberOfVisits__c":  int32(4),
+				"Score__c":           12.5,
+				"Account.Name":       "Acme",
+			},
+			{
+				"Id":                 "003B",
+				"Birthdate":          nil,
+				"CreatedDate":        created,
+				"HasOptedOutOfEmail": false,
+				"NumberOfVisits__c":  int32(7),
+				"Score__c":           nil,
+				"Account.Name":       nil,
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("rows = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown_field", func(t *testing.T) {
+		_, err := sf.NewParquetWriter(&bytes.Buffer{}, "Contact", "Owner.Name")
+		if err == nil {
+			t.Error("NewParquetWriter() expected an error")
+		}
+	})
+
+	t.Run("invalid_value", func(t *testing.T) {
+		rw, err := sf.NewParquetWriter(&bytes.Buffer{}, "Contact", "Birthdate")
+		if err != nil {
+			t.Fatalf("NewParquetWriter() error = %v", err)
+		}
+		if err := rw.WriteRecord(map[string]any{"Birthdate": "May 17"}); err == nil {
+			t.Error("WriteRecord() expected an error")
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package salesforce
+
+// DMLHook is called with the sObject name and records of a DML operation. Before hooks can change the
+// records, such as to stamp a field on every record. An error returned by a hook stops the operation
+// and is returned by it.
+type DMLHook func(sObjectName string, records []map[string]any) error
+
+// DMLHooks are called before and after the insert, update, upsert, and delete operations of single
+// records, collections, composite requests, and bulk jobs created from records. Before hooks are called
+// before the records are sent, and after hooks once Salesforce has processed them, even if some
+// records failed, or for bulk jobs not waited on, once the jobs are created. Nil hooks are skipped.
+type DMLHooks struct {
+	BeforeInsert DMLHook
+	AfterInsert  DMLHook
+	BeforeUpdate DMLHook
+	AfterUpdate  DMLHook
+	BeforeUpsert DMLHook
+	AfterUpsert  DMLHook
+	BeforeDelete DMLHook
+	AfterDelete  DMLHook
+}
+
+func (h DMLHooks) before(operation string) DMLHook {
+	switch operation {
+	case insertOperation:
+		return h.BeforeInsert
+	case updateOperation:
+		return h.BeforeUpdate
+	case upsertOperation:
+		return h.BeforeUpsert
+	case deleteOperation:
+		return h.BeforeDelete
+	}
+	return nil
+}
+
+func (h DMLHooks) after(operation string) DMLHook {
+	switch operation {
+	case insertOperation:
+		return h.AfterInsert
+	case updateOperation:
+		return h.AfterUpdate
+	case upsertOperation:
+		return h.AfterUpsert
+	case deleteOperation:
+		return h.AfterDelete
+	}
+	return nil
+}
+
+// runBeforeHook calls the before hook of a DML operation, if one is set
+func runBeforeHook(
+	sf *Salesforce,
+	operation string,
+	sObjectName string,
+	records ...map[string]any,
+) error {
+	return runDMLHook(sf.config.dmlHooks.before(operation), sObjectName, records)
+}
+
+// runAfterHook calls the after hook of a DML operation, if one is set
+func runAfterHook(
+	sf *Salesforce,
+	operation string,
+	sObjectName string,
+	records ...map[string]any,
+) error {
+	return runDMLHook(sf.config.dmlHooks.after(operation), sObjectName, records)
+}
+
+func runDMLHook(hook DMLHook, sObjectName string, records []map[string]any) error {
+	if hook == nil || len(records) == 0 {
+		return nil
+	}
+	return hook(sObjectName, records)
+}
@@ -0,0 +1,107 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithDMLHooks_Collection(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(`[{"id":"001000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	var calls []string
+	record := func(name string) DMLHook {
+		return func(sObjectName string, records []map[string]any) error {
+			calls = append(calls, name+" "+sObjectName)
+			return nil
+		}
+	}
+	sf.config.dmlHooks = DMLHooks{
+		BeforeInsert: func(sObjectName string, records []map[string]any) error {
+			calls = append(calls, "BeforeInsert "+sObjectName)
+			for _, record := range records {
+				record["Source__c"] = "integration"
+			}
+			return nil
+		},
+		AfterInsert:  record("AfterInsert"),
+		BeforeDelete: record("BeforeDelete"),
+		AfterDelete:  record("AfterDelete"),
+	}
+
+	_, err := sf.InsertCollection("Account", []map[string]any{{"Name": "Acme"}}, 200)
+	if err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"Source__c":"integration"`) {
+		t.Errorf("request bodies = %v, want the stamped field", bodies)
+	}
+
+	// update hooks are not set, so only the request is sent
+	_, err = sf.UpdateCollection(
+		"Account",
+		[]map[string]any{{"Id": "001000000000001AAA", "Name": "Acme"}},
+		200,
+	)
+	if err != nil {
+		t.Fatalf("UpdateCollection() error = %v", err)
+	}
+	_, err = sf.DeleteCollection("Account", []map[string]any{{"Id": "001000000000001AAA"}}, 200)
+	if err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+
+	want := []string{
+		"BeforeInsert Account",
+		"AfterInsert Account",
+		"BeforeDelete Account",
+		"AfterDelete Account",
+	}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("hook calls = %v, want %v", calls, want)
+	}
+}
+
+func TestWithDMLHooks_Errors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(SalesforceResult{Id: "001000000000001AAA", Success: true})
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	hookErr := errors.New("hook failed")
+	failing := func(string, []map[string]any) error {
+		return hookErr
+	}
+
+	sf.config.dmlHooks = DMLHooks{BeforeInsert: failing}
+	if _, err := sf.InsertOne("Account", map[string]any{"Name": "Acme"}); !errors.Is(err, hookErr) {
+		t.Errorf("InsertOne() error = %v, want %v", err, hookErr)
+	}
+	if requests != 0 {
+		t.Errorf("a failing before hook sent %d requests", requests)
+	}
+
+	sf.config.dmlHooks = DMLHooks{AfterInsert: failing}
+	result, err := sf.InsertOne("Account", map[string]any{"Name": "Acme"})
+	if !errors.Is(err, hookErr) || result.Id != "001000000000001AAA" {
+		t.Errorf("InsertOne() = %v, %v, want the result and %v", result, err, hookErr)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
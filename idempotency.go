@@ -0,0 +1,294 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const idempotencyKeyTTL = time.Duration(24 * time.Hour)
+
+// IdempotencyStore keeps the results of inserts sent with an idempotency key, so that retrying an
+// insert returns the original result instead of creating the record again. Implement it to share
+// keys between processes, such as with a database or Redis.
+type IdempotencyStore interface {
+	// Get returns the stored result of a key, and false if the key has not been stored
+	Get(key string) (SalesforceResult, bool, error)
+	// Set stores the result of a successful insert, or a result that is not successful to mark the key
+	// pending while its insert is sent
+	Set(key string, result SalesforceResult) error
+	// Delete removes a key, such as the pending key of an insert that Salesforce rejected
+	Delete(key string) error
+}
+
+// IdempotencyKeyPendingError is returned for an idempotency key whose insert failed without a result,
+// such as after a timeout, when no idempotency key field is set. The record may have been created, so
+// it is not inserted again until the key is resolved with ResolveIdempotencyKey.
+type IdempotencyKeyPendingError struct {
+	Key string
+}
+
+func (e *IdempotencyKeyPendingError) Error() string {
+	return fmt.Sprintf(
+		"insert with idempotency key %s failed without a result and may have created the record, "+
+			"resolve the key with ResolveIdempotencyKey before inserting it again",
+		e.Key,
+	)
+}
+
+// pendingIdempotencyResult marks a key whose insert is being sent
+var pendingIdempotencyResult = SalesforceResult{
+	Errors: []SalesforceErrorMessage{{
+		ErrorCode: "IDEMPOTENCY_KEY_PENDING",
+		Message:   "insert with this idempotency key was sent without a result",
+	}},
+}
+
+type memoryIdempotencyStore struct {
+	cache *ttlCache
+}
+
+// NewMemoryIdempotencyStore returns an in-memory IdempotencyStore that keeps keys for the given duration
+func NewMemoryIdempotencyStore(ttl time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{cache: newTTLCache(ttl)}
+}
+
+func (s *memoryIdempotencyStore) Get(key string) (SalesforceResult, bool, error) {
+	value, ok := s.cache.get(key)
+	if !ok {
+		return SalesforceResult{}, false, nil
+	}
+	return value.(SalesforceResult), true, nil
+}
+
+func (s *memoryIdempotencyStore) Set(key string, result SalesforceResult) error {
+	s.cache.set(key, result)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Delete(key string) error {
+	s.cache.delete(key)
+	return nil
+}
+
+// ResolveIdempotencyKey resolves a pending idempotency key, see IdempotencyKeyPendingError, after
+// checking whether its insert created the record. Pass the id of the record if it was created, so that
+// retries return it, or an empty id if it was not, so that the record is inserted again.
+func (sf *Salesforce) ResolveIdempotencyKey(idempotencyKey string, recordId string) error {
+	if idempotencyKey == "" {
+		return errors.New("idempotency key cannot be empty")
+	}
+	store := sf.config.idempotencyStore
+	if recordId == "" {
+		if err := store.Delete(idempotencyKey); err != nil {
+			return fmt.Errorf("deleting idempotency key: %w", err)
+		}
+		return nil
+	}
+	if err := store.Set(idempotencyKey, SalesforceResult{Id: recordId, Success: true}); err != nil {
+		return fmt.Errorf("storing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// storedIdempotencyResult returns the stored result of a key, and false if the record of the key should
+// be sent. Pending keys are sent again only when they are upserted on an idempotency key field.
+func storedIdempotencyResult(sf *Salesforce, key string) (SalesforceResult, bool, error) {
+	stored, ok, err := sf.config.idempotencyStore.Get(key)
+	if err != nil {
+		return SalesforceResult{}, false, fmt.Errorf("reading idempotency key: %w", err)
+	}
+	if !ok || stored.Success {
+		return stored, ok, nil
+	}
+	if sf.config.idempotencyKeyField != "" {
+		return SalesforceResult{}, false, nil
+	}
+	return SalesforceResult{}, false, &IdempotencyKeyPendingError{Key: key}
+}
+
+// storeIdempotencyResult stores the result of an insert sent with a key that was marked pending. The
+// key stays pending if the insert failed without a result, since the record may have been created.
+func storeIdempotencyResult(
+	store IdempotencyStore,
+	key string,
+	result SalesforceResult,
+	hasResult bool,
+) error {
+	switch {
+	case result.Success:
+		if err := store.Set(key, result); err != nil {
+			return fmt.Errorf("storing idempotency key: %w", err)
+		}
+	case hasResult:
+		if err := store.Delete(key); err != nil {
+			return fmt.Errorf("deleting idempotency key: %w", err)
+		}
+	}
+	return nil
+}
+
+// InsertOneIdempotent inserts a record unless a record was already inserted with the same idempotency
+// key, in which case the stored result is returned without sending a request.
+// If an idempotency key field is configured, see WithIdempotencyKeyField, the key is written to that
+// field and the record is upserted on it, so a retry after an ambiguous failure such as a timeout
+// updates the record created by the first attempt instead of inserting a duplicate. Otherwise the key
+// is marked pending while the insert is sent, and stays pending if it fails with an error rather than
+// a result, so that retries return an IdempotencyKeyPendingError until the key is resolved.
+func (sf *Salesforce) InsertOneIdempotent(
+	idempotencyKey string,
+	sObjectName string,
+	record any,
+) (SalesforceResult, error) {
+	validationErr := validateSingles(*sf, record)
+	if validationErr != nil {
+		return SalesforceResult{}, validationErr
+	}
+	if idempotencyKey == "" {
+		return SalesforceResult{}, errors.New("idempotency key cannot be empty")
+	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, record)
+	if nameErr != nil {
+		return SalesforceResult{}, nameErr
+	}
+
+	store := sf.config.idempotencyStore
+	stored, ok, err := storedIdempotencyResult(sf, idempotencyKey)
+	if err != nil {
+		return SalesforceResult{}, err
+	}
+	if ok {
+		return stored, nil
+	}
+
+	var result SalesforceResult
+	if keyField := sf.config.idempotencyKeyField; keyField != "" {
+		recordMap, err := convertToMap(record)
+		if err != nil {
+			return SalesforceResult{}, err
+		}
+		recordMap[keyField] = idempotencyKey
+		result, err = doUpsertOne(sf, sObjectName, keyField, recordMap)
+		if err != nil {
+			return SalesforceResult{}, err
+		}
+		if result.Success {
+			if err := store.Set(idempotencyKey, result); err != nil {
+				return result, fmt.Errorf("storing idempotency key: %w", err)
+			}
+		}
+		return result, nil
+	}
+
+	if err := store.Set(idempotencyKey, pendingIdempotencyResult); err != nil {
+		return SalesforceResult{}, fmt.Errorf("storing idempotency key: %w", err)
+	}
+	// an error without a result leaves the key pending, since the request may have created the record
+	result, err = doInsertOne(sf, sObjectName, record)
+	storeErr := storeIdempotencyResult(store, idempotencyKey, result, err == nil)
+	return result, errors.Join(err, storeErr)
+}
+
+// InsertCollectionIdempotent inserts records with an idempotency key for each of them, see
+// InsertOneIdempotent. Records whose keys were already inserted are not sent, their stored results
+// are returned in their place. Nothing is sent if any key is pending.
+func (sf *Salesforce) InsertCollectionIdempotent(
+	sObjectName string,
+	records any,
+	idempotencyKeys []string,
+	batchSize int,
+) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
+	validationErr := validateCollections(*sf, records, batchSize)
+	if validationErr != nil {
+		return SalesforceResults{}, validationErr
+	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
+	recordMap, err := convertToSliceOfMaps(records)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if len(idempotencyKeys) != len(recordMap) {
+		return SalesforceResults{}, fmt.Errorf(
+			"got %d idempotency keys for %d records",
+			len(idempotencyKeys),
+			len(recordMap),
+		)
+	}
+
+	store := sf.config.idempotencyStore
+	results := make([]SalesforceResult, len(recordMap))
+	done := make([]bool, len(recordMap))
+	seen := map[string]bool{}
+	var pending []map[string]any
+	var pendingIndexes []int
+	for i, key := range idempotencyKeys {
+		if key == "" {
+			return SalesforceResults{}, errors.New("idempotency key cannot be empty")
+		}
+		if seen[key] {
+			return SalesforceResults{}, fmt.Errorf("duplicate idempotency key: %s", key)
+		}
+		seen[key] = true
+
+		stored, ok, err := storedIdempotencyResult(sf, key)
+		if err != nil {
+			return SalesforceResults{}, err
+		}
+		if ok {
+			stored.Index = i
+			results[i] = stored
+			done[i] = true
+			continue
+		}
+		if keyField := sf.config.idempotencyKeyField; keyField != "" {
+			recordMap[i][keyField] = key
+		}
+		pending = append(pending, recordMap[i])
+		pendingIndexes = append(pendingIndexes, i)
+	}
+
+	var sent SalesforceResults
+	if len(pending) > 0 {
+		if keyField := sf.config.idempotencyKeyField; keyField != "" {
+			sent, err = doUpsertCollection(sf, sObjectName, keyField, pending, batchSize)
+		} else {
+			for _, i := range pendingIndexes {
+				if err := store.Set(idempotencyKeys[i], pendingIdempotencyResult); err != nil {
+					return SalesforceResults{}, fmt.Errorf("storing idempotency key: %w", err)
+				}
+			}
+			sent, err = doInsertCollection(sf, sObjectName, pending, batchSize)
+		}
+	}
+	// records of batches that failed without results have no result, and their keys stay pending
+	for j, result := range sent.Results {
+		i := pendingIndexes[j]
+		result.Index = i
+		results[i] = result
+		done[i] = true
+		storeErr := storeIdempotencyResult(store, idempotencyKeys[i], result, true)
+		if storeErr != nil && err == nil {
+			err = storeErr
+		}
+	}
+	if err != nil {
+		completed := []SalesforceResult{}
+		for i, result := range results {
+			if done[i] {
+				completed = append(completed, result)
+			}
+		}
+		return SalesforceResults{Results: completed}, err
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
+		}
+	}
+	return SalesforceResults{Results: results}, nil
+}
@@ -0,0 +1,303 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Get(string) (SalesforceResult, bool, error) {
+	return SalesforceResult{}, false, errors.New("store unavailable")
+}
+
+func (failingIdempotencyStore) Set(string, SalesforceResult) error {
+	return errors.New("store unavailable")
+}
+
+func (failingIdempotencyStore) Delete(string) error {
+	return errors.New("store unavailable")
+}
+
+func TestSalesforce_InsertOneIdempotent(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	result := SalesforceResult{Id: "001000000000001AAA", Success: true}
+
+	t.Run("retry_returns_stored_result", func(t *testing.T) {
+		requests := 0
+		server, sfAuth := setupTestServer(result, http.StatusCreated)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Method != http.MethodPost ||
+				r.URL.Path != "/services/data/"+apiVersion+"/sobjects/Account" {
+				t.Errorf("InsertOneIdempotent() request = %v %v", r.Method, r.URL.Path)
+			}
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+
+		for range 2 {
+			got, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test account"})
+			if err != nil {
+				t.Fatalf("InsertOneIdempotent() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, result) {
+				t.Errorf("InsertOneIdempotent() = %v, want %v", got, result)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("InsertOneIdempotent() requests = %v, want 1", requests)
+		}
+	})
+
+	t.Run("upsert_on_key_field", func(t *testing.T) {
+		server, sfAuth, req := setupTestServerWithCapture(result, http.StatusCreated)
+		defer server.Close()
+		handler := server.Config.Handler
+		var body map[string]any
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+		sf.config.idempotencyKeyField = "Idempotency_Key__c"
+
+		if _, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test account"}); err != nil {
+			t.Fatalf("InsertOneIdempotent() error = %v", err)
+		}
+		wantPath := "/services/data/" + apiVersion + "/sobjects/Account/Idempotency_Key__c/key-1"
+		if (*req).Method != http.MethodPatch || (*req).URL.Path != wantPath {
+			t.Errorf("InsertOneIdempotent() request = %v %v", (*req).Method, (*req).URL.Path)
+		}
+		if body["Name"] != "test account" {
+			t.Errorf("InsertOneIdempotent() body = %v", body)
+		}
+	})
+
+	t.Run("failure_leaves_key_pending", func(t *testing.T) {
+		requests := 0
+		server, sfAuth := setupTestServer("", http.StatusGatewayTimeout)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+
+		if _, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test"}); err == nil {
+			t.Errorf("InsertOneIdempotent() expected error")
+		}
+		if stored, ok, _ := sf.config.idempotencyStore.Get("key-1"); !ok || stored.Success {
+			t.Errorf("InsertOneIdempotent() stored %v %v, want a pending key", stored, ok)
+		}
+		_, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test"})
+		var pendingErr *IdempotencyKeyPendingError
+		if !errors.As(err, &pendingErr) || pendingErr.Key != "key-1" {
+			t.Errorf("InsertOneIdempotent() error = %v, want an IdempotencyKeyPendingError", err)
+		}
+		if requests != 1 {
+			t.Errorf("InsertOneIdempotent() requests = %v, want 1", requests)
+		}
+
+		if err := sf.ResolveIdempotencyKey("key-1", ""); err != nil {
+			t.Fatalf("ResolveIdempotencyKey() error = %v", err)
+		}
+		if _, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test"}); err == nil {
+			t.Errorf("InsertOneIdempotent() expected error")
+		}
+		if requests != 2 {
+			t.Errorf(
+				"InsertOneIdempotent() requests = %v, want 2 after resolving the key",
+				requests,
+			)
+		}
+	})
+
+	t.Run("pending_key_upserted_on_key_field", func(t *testing.T) {
+		server, sfAuth := setupTestServer(result, http.StatusCreated)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+		sf.config.idempotencyKeyField = "Idempotency_Key__c"
+		_ = sf.config.idempotencyStore.Set("key-1", pendingIdempotencyResult)
+
+		got, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test"})
+		if err != nil || !reflect.DeepEqual(got, result) {
+			t.Errorf("InsertOneIdempotent() = %v, %v, want %v", got, err, result)
+		}
+	})
+
+	t.Run("store_error", func(t *testing.T) {
+		server, sfAuth := setupTestServer(result, http.StatusCreated)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+		sf.config.idempotencyStore = failingIdempotencyStore{}
+
+		if _, err := sf.InsertOneIdempotent("key-1", "Account", account{Name: "test"}); err == nil {
+			t.Errorf("InsertOneIdempotent() expected error")
+		}
+	})
+
+	t.Run("empty_key", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		if _, err := sf.InsertOneIdempotent("", "Account", account{Name: "test"}); err == nil {
+			t.Errorf("InsertOneIdempotent() expected error")
+		}
+	})
+}
+
+func TestSalesforce_ResolveIdempotencyKey(t *testing.T) {
+	sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+	_ = sf.config.idempotencyStore.Set("key-1", pendingIdempotencyResult)
+
+	if err := sf.ResolveIdempotencyKey("key-1", "001000000000001AAA"); err != nil {
+		t.Fatalf("ResolveIdempotencyKey() error = %v", err)
+	}
+	got, err := sf.InsertOneIdempotent("key-1", "Account", map[string]any{"Name": "test"})
+	want := SalesforceResult{Id: "001000000000001AAA", Success: true}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("InsertOneIdempotent() = %v, %v, want %v", got, err, want)
+	}
+	if err := sf.ResolveIdempotencyKey("", ""); err == nil {
+		t.Errorf("ResolveIdempotencyKey() expected error for an empty key")
+	}
+}
+
+func TestSalesforce_InsertCollectionIdempotent(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	records := []account{{Name: "first"}, {Name: "second"}, {Name: "third"}}
+
+	t.Run("skips_stored_keys", func(t *testing.T) {
+		var sentRecords int
+		server, sfAuth := setupTestServer([]SalesforceResult{
+			{Id: "001000000000002AAA", Success: true},
+			{
+				Success: false,
+				Errors:  []SalesforceErrorMessage{{ErrorCode: "REQUIRED_FIELD_MISSING"}},
+			},
+		}, http.StatusOK)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload := sObjectCollection{}
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &payload)
+			sentRecords += len(payload.Records)
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+		stored := SalesforceResult{Id: "001000000000001AAA", Success: true}
+		_ = sf.config.idempotencyStore.Set("key-1", stored)
+
+		got, err := sf.InsertCollectionIdempotent(
+			"Account",
+			records,
+			[]string{"key-1", "key-2", "key-3"},
+			200,
+		)
+		if err != nil {
+			t.Fatalf("InsertCollectionIdempotent() error = %v", err)
+		}
+		if sentRecords != 2 {
+			t.Errorf("InsertCollectionIdempotent() sent %v records, want 2", sentRecords)
+		}
+		want := SalesforceResults{
+			Results: []SalesforceResult{
+				{Id: "001000000000001AAA", Success: true, Index: 0},
+				{Id: "001000000000002AAA", Success: true, Index: 1},
+				{
+					Success: false,
+					Errors:  []SalesforceErrorMessage{{ErrorCode: "REQUIRED_FIELD_MISSING"}},
+					Index:   2,
+				},
+			},
+			HasSalesforceErrors: true,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("InsertCollectionIdempotent() = %v, want %v", got, want)
+		}
+		if _, ok, _ := sf.config.idempotencyStore.Get("key-2"); !ok {
+			t.Errorf("InsertCollectionIdempotent() did not store successful key")
+		}
+		if _, ok, _ := sf.config.idempotencyStore.Get("key-3"); ok {
+			t.Errorf("InsertCollectionIdempotent() stored failed key")
+		}
+	})
+
+	t.Run("failed_batch_leaves_keys_pending", func(t *testing.T) {
+		server, sfAuth := setupTestServer("", http.StatusGatewayTimeout)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+		keys := []string{"key-1", "key-2"}
+
+		if _, err := sf.InsertCollectionIdempotent("Account", records[:2], keys, 200); err == nil {
+			t.Errorf("InsertCollectionIdempotent() expected error")
+		}
+		_, err := sf.InsertCollectionIdempotent("Account", records[:2], keys, 200)
+		var pendingErr *IdempotencyKeyPendingError
+		if !errors.As(err, &pendingErr) || pendingErr.Key != "key-1" {
+			t.Errorf(
+				"InsertCollectionIdempotent() error = %v, want an IdempotencyKeyPendingError",
+				err,
+			)
+		}
+	})
+
+	t.Run("all_stored", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		for _, key := range []string{"key-1", "key-2"} {
+			_ = sf.config.idempotencyStore.Set(key, SalesforceResult{Success: true})
+		}
+
+		got, err := sf.InsertCollectionIdempotent(
+			"Account",
+			records[:2],
+			[]string{"key-1", "key-2"},
+			200,
+		)
+		if err != nil {
+			t.Fatalf("InsertCollectionIdempotent() error = %v", err)
+		}
+		if got.HasSalesforceErrors || len(got.Results) != 2 || got.Results[1].Index != 1 {
+			t.Errorf("InsertCollectionIdempotent() = %v", got)
+		}
+	})
+
+	t.Run("default_batch_size", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		err := WithSObjectDefaults("Account", SObjectDefaults{BatchSize: 100})(sf.config)
+		if err != nil {
+			t.Fatalf("WithSObjectDefaults() error = %v", err)
+		}
+		_ = sf.config.idempotencyStore.Set("key-1", SalesforceResult{Success: true})
+
+		_, err = sf.InsertCollectionIdempotent("Account", records[:1], []string{"key-1"}, 0)
+		if err != nil {
+			t.Errorf("InsertCollectionIdempotent() error = %v, want the default batch size", err)
+		}
+	})
+
+	t.Run("invalid_keys", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		for _, keys := range [][]string{
+			{"key-1", "key-2"},
+			{"key-1", "key-1", "key-2"},
+			{"key-1", "", "key-2"},
+		} {
+			if _, err := sf.InsertCollectionIdempotent("Account", records, keys, 200); err == nil {
+				t.Errorf("InsertCollectionIdempotent() expected error for keys %v", keys)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// idChecksumChars maps the case of each five character chunk of an id to its checksum character
+const idChecksumChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ012345"
+
+// keyPrefixCacheKey is the describe cache key of the key prefixes of the org's sObjects
+const keyPrefixCacheKey = "/sobjects"
+
+var salesforceIdPattern = regexp.MustCompile(`^[a-zA-Z0-9]{15}([a-zA-Z0-9]{3})?$`)
+
+type globalDescribe struct {
+	SObjects []struct {
+		Name      string `json:"name"`
+		KeyPrefix string `json:"keyPrefix"`
+	} `json:"sobjects"`
+}
+
+// idChecksum returns the three character suffix that makes a 15 character id case-insensitive
+func idChecksum(id string) string {
+	var checksum [3]byte
+	for chunk := range checksum {
+		index := 0
+		for i := 0; i < 5; i++ {
+			c := id[chunk*5+i]
+			if c >= 'A' && c <= 'Z' {
+				index |= 1 << i
+			}
+		}
+		checksum[chunk] = idChecksumChars[index]
+	}
+	return string(checksum[:])
+}
+
+// IsValidId reports whether id is a 15 or 18 character Salesforce id. The checksum of 18 character
+// ids is verified.
+func IsValidId(id string) bool {
+	if !salesforceIdPattern.MatchString(id) {
+		return false
+	}
+	if len(id) == 18 {
+		return strings.EqualFold(id[15:], idChecksum(id[:15]))
+	}
+	return true
+}
+
+// To18CharId converts a 15 character case-sensitive id to its 18 character case-insensitive form.
+// 18 character ids are returned unchanged.
+func To18CharId(id string) (string, error) {
+	if !IsValidId(id) {
+		return "", fmt.Errorf("invalid salesforce id: %s", id)
+	}
+	if len(id) == 18 {
+		return id, nil
+	}
+	return id + idChecksum(id), nil
+}
+
+// EqualIds reports whether two ids refer to the same record, comparing 15 and 18 character ids
+func EqualIds(a string, b string) bool {
+	if !IsValidId(a) || !IsValidId(b) {
+		return false
+	}
+	return a[:15] == b[:15]
+}
+
+// IdKeyPrefix returns the key prefix of an id, the first three characters that identify its sObject
+func IdKeyPrefix(id string) (string, error) {
+	if !IsValidId(id) {
+		return "", fmt.Errorf("invalid salesforce id: %s", id)
+	}
+	return id[:3], nil
+}
+
+// SObjectTypeFromId returns the name of the sObject an id belongs to from its key prefix.
+// Key prefixes are read from the org's global describe, which is cached, see WithDescribeCacheTTL.
+func (sf *Salesforce) SObjectTypeFromId(id string) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	keyPrefix, err := IdKeyPrefix(id)
+	if err != nil {
+		return "", err
+	}
+
+	keyPrefixes, err := sObjectKeyPrefixes(sf)
+	if err != nil {
+		return "", err
+	}
+	sObjectName, ok := keyPrefixes[keyPrefix]
+	if !ok {
+		return "", fmt.Errorf("no sObject found with key prefix %s", keyPrefix)
+	}
+	return sObjectName, nil
+}
+
+// sObjectKeyPrefixes returns the names of the org's sObjects by key prefix
+func sObjectKeyPrefixes(sf *Salesforce) (map[string]string, error) {
+	if cached, ok := sf.config.describeCache.get(keyPrefixCacheKey); ok {
+		return cached.(map[string]string), nil
+	}
+
+	describe, err := getGlobalDescribe(sf)
+	if err != nil {
+		return nil, err
+	}
+	keyPrefixes := map[string]string{}
+	for _, sObject := range describe.SObjects {
+		if sObject.KeyPrefix != "" {
+			keyPrefixes[sObject.KeyPrefix] = sObject.Name
+		}
+	}
+	sf.config.describeCache.set(keyPrefixCacheKey, keyPrefixes)
+	return keyPrefixes, nil
+}
+
+// getGlobalDescribe returns the org's global describe, which lists its sObjects
+func getGlobalDescribe(sf *Salesforce) (globalDescribe, error) {
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodGet,
+		uri:      "/sobjects/",
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return globalDescribe{}, err
+	}
+	describe := globalDescribe{}
+	if err := decodeJSONResponse(resp, &describe); err != nil {
+		return globalDescribe{}, err
+	}
+	if len(describe.SObjects) == 0 {
+		return globalDescribe{}, errors.New("global describe returned no sObjects")
+	}
+	return describe, nil
+}
@@ -0,0 +1,130 @@
+package salesforce
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTo18CharId(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "convert_15", id: "0015000000Gv7qJ", want: "0015000000Gv7qJAAR"},
+		{name: "all_lowercase", id: "001500000000000", want: "001500000000000AAA"},
+		{name: "already_18", id: "0015000000Gv7qJAAR", want: "0015000000Gv7qJAAR"},
+		{name: "bad_checksum", id: "0015000000Gv7qJAAA", wantErr: true},
+		{name: "bad_length", id: "0015000000Gv7q", wantErr: true},
+		{name: "bad_characters", id: "0015000000Gv7q!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To18CharId(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("To18CharId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("To18CharId() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidId(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "0015000000Gv7qJ", want: true},
+		{id: "0015000000Gv7qJAAR", want: true},
+		{id: "0015000000Gv7qJaar", want: true},
+		{id: "0015000000Gv7qJAAB", want: false},
+		{id: "", want: false},
+		{id: "0015000000Gv7qJA", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsValidId(tt.id); got != tt.want {
+			t.Errorf("IsValidId(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestEqualIds(t *testing.T) {
+	if !EqualIds("0015000000Gv7qJ", "0015000000Gv7qJAAR") {
+		t.Errorf("EqualIds() = false for 15 and 18 character forms of an id")
+	}
+	if EqualIds("0015000000Gv7qJ", "0015000000GV7QJ") {
+		t.Errorf("EqualIds() = true for ids that differ in case")
+	}
+	if EqualIds("invalid", "invalid") {
+		t.Errorf("EqualIds() = true for invalid ids")
+	}
+}
+
+func TestIdKeyPrefix(t *testing.T) {
+	got, err := IdKeyPrefix("0035000000Gv7qJ")
+	if err != nil || got != "003" {
+		t.Errorf("IdKeyPrefix() = %v, %v, want 003", got, err)
+	}
+	if _, err := IdKeyPrefix("003"); err == nil {
+		t.Errorf("IdKeyPrefix() expected error")
+	}
+}
+
+func TestSalesforce_SObjectTypeFromId(t *testing.T) {
+	describe := map[string]any{"sobjects": []map[string]any{
+		{"name": "Account", "keyPrefix": "001"},
+		{"name": "Contact", "keyPrefix": "003"},
+		{"name": "AccountChangeEvent", "keyPrefix": nil},
+	}}
+
+	t.Run("cached", func(t *testing.T) {
+		requests := 0
+		server, sfAuth := setupTestServer(describe, http.StatusOK)
+		defer server.Close()
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/services/data/"+apiVersion+"/sobjects/" {
+				t.Errorf("SObjectTypeFromId() path = %v", r.URL.Path)
+			}
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+
+		for id, want := range map[string]string{
+			"0015000000Gv7qJ":    "Account",
+			"0035000000Gv7qJAAR": "Contact",
+		} {
+			got, err := sf.SObjectTypeFromId(id)
+			if err != nil {
+				t.Fatalf("SObjectTypeFromId() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("SObjectTypeFromId() = %v, want %v", got, want)
+			}
+		}
+		if requests != 1 {
+			t.Errorf("SObjectTypeFromId() requests = %v, want 1", requests)
+		}
+
+		if _, err := sf.SObjectTypeFromId("a005000000Gv7qJ"); err == nil {
+			t.Errorf("SObjectTypeFromId() expected error for unknown key prefix")
+		}
+		if _, err := sf.SObjectTypeFromId("bad"); err == nil {
+			t.Errorf("SObjectTypeFromId() expected error for invalid id")
+		}
+	})
+
+	t.Run("describe_error", func(t *testing.T) {
+		server, sfAuth := setupTestServer("", http.StatusInternalServerError)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		if _, err := sf.SObjectTypeFromId("0015000000Gv7qJ"); err == nil {
+			t.Errorf("SObjectTypeFromId() expected error")
+		}
+	})
+}
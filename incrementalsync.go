@@ -0,0 +1,249 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// SyncState is the progress of an incremental sync: the latest SystemModstamp it delivered and the
+// records it delivered within the overlap window before it, which later pulls read again
+type SyncState struct {
+	HighWaterMark time.Time            `json:"highWaterMark"`
+	Seen          map[string]time.Time `json:"seen"` // SystemModstamp of each delivered record by id
+}
+
+// SyncStateStore persists the state of incremental syncs by key so that syncs can resume after a
+// restart. Implementations must be safe for concurrent use.
+type SyncStateStore interface {
+	// GetSyncState returns the stored state of the key, and false if none is stored
+	GetSyncState(key string) (SyncState, bool, error)
+	// SetSyncState stores the state of the key after records were delivered
+	SetSyncState(key string, state SyncState) error
+}
+
+// MemorySyncStateStore keeps sync states in memory, useful for tests and short lived syncs
+type MemorySyncStateStore struct {
+	mu     sync.RWMutex
+	states map[string]SyncState
+}
+
+func NewMemorySyncStateStore() *MemorySyncStateStore {
+	return &MemorySyncStateStore{states: map[string]SyncState{}}
+}
+
+func (s *MemorySyncStateStore) GetSyncState(key string) (SyncState, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, found := s.states[key]
+	return state, found, nil
+}
+
+func (s *MemorySyncStateStore) SetSyncState(key string, state SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[key] = state
+	return nil
+}
+
+// FileSyncStateStore persists the sync states of all keys to a single json file. Every update is
+// written to a temporary file which then replaces the original, so a crash never leaves a partial file.
+type FileSyncStateStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFileSyncStateStore(filePath string) *FileSyncStateStore {
+	return &FileSyncStateStore{filePath: filePath}
+}
+
+func (s *FileSyncStateStore) GetSyncState(key string) (SyncState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	if err != nil {
+		return SyncState{}, false, err
+	}
+	state, found := states[key]
+	return state, found, nil
+}
+
+func (s *FileSyncStateStore) SetSyncState(key string, state SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	states[key] = state
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	tempPath := s.filePath + ".tmp"
+	if err := afero.WriteFile(appFs, tempPath, data, 0o644); err != nil {
+		return err
+	}
+	return appFs.Rename(tempPath, s.filePath)
+}
+
+func (s *FileSyncStateStore) read() (map[string]SyncState, error) {
+	states := map[string]SyncState{}
+	data, err := afero.ReadFile(appFs, s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// IncrementalSync pulls the records of a query that changed since its last pull, tracking the latest
+// SystemModstamp it delivered as a high-water mark. Records can be committed with a SystemModstamp
+// earlier than records already read, so each pull reads again from the overlap window before the
+// high-water mark and drops records that were already delivered with the same SystemModstamp.
+type IncrementalSync struct {
+	sf       *Salesforce
+	resource string // query resource, /queryAll/ to read deleted records
+	query    string
+	key      string
+	store    SyncStateStore
+	overlap  time.Duration
+}
+
+// NewIncrementalSync returns an IncrementalSync for a query, whose state is stored in store under key.
+// The query must select Id and SystemModstamp and cannot have ORDER BY, LIMIT, OFFSET, GROUP BY, or FOR
+// clauses. The overlap is how far before the high-water mark pulls start reading.
+func (sf *Salesforce) NewIncrementalSync(
+	query string,
+	key string,
+	store SyncStateStore,
+	overlap time.Duration,
+) (*IncrementalSync, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if key == "" || store == nil {
+		return nil, errors.New("sync key and state store are required")
+	}
+	if overlap < 0 {
+		return nil, errors.New("overlap cannot be negative")
+	}
+	if soqlClauseIndex(query, "FROM") < 0 {
+		return nil, errors.New("query must have a FROM clause")
+	}
+	for _, clause := range []string{"ORDER BY", "LIMIT", "OFFSET", "GROUP BY", "FOR"} {
+		if soqlClauseIndex(query, clause) >= 0 {
+			return nil, fmt.Errorf("query cannot have a %s clause", clause)
+		}
+	}
+	return &IncrementalSync{
+		sf:       sf,
+		resource: "/query/",
+		query:    strings.TrimSpace(query),
+		key:      key,
+		store:    store,
+		overlap:  overlap,
+	}, nil
+}
+
+// Pull reads the records changed since the last pull, or every record on the first pull, and calls
+// handle with each page of records that were not delivered before, in SystemModstamp order. The state
+// is stored after handle returns, so records of a page whose handler fails are delivered again by the
+// next pull, and no record is delivered twice for the same change once its page was handled. Pull
+// returns the number of records delivered.
+func (s *IncrementalSync) Pull(
+	ctx context.Context,
+	handle func(records []map[string]any) error,
+) (int, error) {
+	state, _, err := s.store.GetSyncState(s.key)
+	if err != nil {
+		return 0, err
+	}
+	state.Seen = maps.Clone(state.Seen)
+	if state.Seen == nil {
+		state.Seen = map[string]time.Time{}
+	}
+
+	query := s.query
+	if !state.HighWaterMark.IsZero() {
+		from := state.windowStart(s.overlap).UTC().Format(soqlDateTimeFormat)
+		query = addSoqlCondition(query, "SystemModstamp >= "+from)
+	}
+	query += " ORDER BY SystemModstamp, Id"
+
+	delivered := 0
+	nextRecordsUrl := s.resource + "?q=" + url.QueryEscape(query)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, s.sf, nextRecordsUrl)
+		if err != nil {
+			return delivered, err
+		}
+		records := make([]map[string]any, 0, len(queryResp.Records))
+		modstamps := make([]time.Time, 0, len(queryResp.Records))
+		for _, record := range queryResp.Records {
+			id, _ := record["Id"].(string)
+			modstamp, err := parseSalesforceTime(record["SystemModstamp"])
+			if id == "" || err != nil {
+				return delivered, errors.New("query must select Id and SystemModstamp")
+			}
+			if seen, ok := state.Seen[id]; ok && !modstamp.After(seen) {
+				continue
+			}
+			records = append(records, record)
+			modstamps = append(modstamps, modstamp)
+		}
+		if len(records) > 0 {
+			if err := handle(records); err != nil {
+				return delivered, err
+			}
+			for i, record := range records {
+				state.Seen[record["Id"].(string)] = modstamps[i]
+				if modstamps[i].After(state.HighWaterMark) {
+					state.HighWaterMark = modstamps[i]
+				}
+			}
+			state.prune(s.overlap)
+			if err := s.store.SetSyncState(s.key, state); err != nil {
+				return delivered, err
+			}
+			delivered += len(records)
+		}
+
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
+	return delivered, nil
+}
+
+// windowStart returns the start of the overlap window, truncated to the whole seconds of SOQL datetimes
+func (s *SyncState) windowStart(overlap time.Duration) time.Time {
+	return s.HighWaterMark.Add(-overlap).Truncate(time.Second)
+}
+
+// prune forgets records changed before the overlap window, which later pulls do not read again
+func (s *SyncState) prune(overlap time.Duration) {
+	from := s.windowStart(overlap)
+	for id, modstamp := range s.Seen {
+		if modstamp.Before(from) {
+			delete(s.Seen, id)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestIncrementalSync_Pull(t *testing.T) {
+	pulls := []struct {
+		wantCondition string
+		records       []map[string]any
+		failHandler   bool
+		wantIds       []string
+		wantErr       bool
+	}{
+		{
+			records: []map[string]any{
+				{"Id": "001A", "SystemModstamp": "2024-01-01T10:00:00.000+0000"},
+				{"Id": "001B", "SystemModstamp": "2024-01-01T10:00:05.250+0000"},
+			},
+			wantIds: []string{"001A", "001B"},
+		},
+		{
+			// 001B was already delivered, 001C committed late, and 001A changed again
+			wantCondition: "SystemModstamp >= 2024-01-01T09:59:05Z",
+			records: []map[string]any{
+				{"Id": "001C", "SystemModstamp": "2024-01-01T10:00:03.000+0000"},
+				{"Id": "001B", "SystemModstamp": "2024-01-01T10:00:05.250+0000"},
+				{"Id": "001A", "SystemModstamp": "2024-01-01T10:01:00.000+0000"},
+			},
+			wantIds: []string{"001C", "001A"},
+		},
+		{
+			wantCondition: "SystemModstamp >= 2024-01-01T10:00:00Z",
+			records: []map[string]any{
+				{"Id": "001A", "SystemModstamp": "2024-01-01T10:01:00.000+0000"},
+				{"Id": "001D", "SystemModstamp": "2024-01-01T10:02:00.000+0000"},
+			},
+			failHandler: true,
+			wantIds:     []string{"001D"},
+			wantErr:     true,
+		},
+		{
+			// the failed page is delivered again
+			wantCondition: "SystemModstamp >= 2024-01-01T10:00:00Z",
+			records: []map[string]any{
+				{"Id": "001D", "SystemModstamp": "2024-01-01T10:02:00.000+0000"},
+			},
+			wantIds: []string{"001D"},
+		},
+		{
+			records: []map[string]any{{"Id": "001E"}},
+			wantErr: true,
+		},
+	}
+
+	pull := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		if !strings.HasSuffix(query, " ORDER BY SystemModstamp, Id") {
+			t.Errorf("query = %s, want order by SystemModstamp", query)
+		}
+		if condition := pulls[pull].wantCondition; condition != "" &&
+			!strings.Contains(query, "WHERE (IsDeleted = false) AND "+condition) {
+			t.Errorf("query = %s, want condition %s", query, condition)
+		}
+		body, _ := json.Marshal(queryResponse{Done: true, Records: pulls[pull].records})
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	store := NewMemorySyncStateStore()
+	incremental, err := sf.NewIncrementalSync(
+		"SELECT Id, SystemModstamp FROM Account WHERE IsDeleted = false",
+		"accounts",
+		store,
+		time.Minute,
+	)
+	if err != nil {
+		t.Fatalf("NewIncrementalSync() error = %v", err)
+	}
+	for i, tt := range pulls {
+		pull = i
+		var gotIds []string
+		delivered, err := incremental.Pull(
+			context.Background(),
+			func(records []map[string]any) error {
+				for _, record := range records {
+					gotIds = append(gotIds, record["Id"].(string))
+				}
+				if tt.failHandler {
+					return errors.New("handler failed")
+				}
+				return nil
+			},
+		)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("pull %d: Pull() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+		if len(tt.wantIds) > 0 && !reflect.DeepEqual(gotIds, tt.wantIds) {
+			t.Errorf("pull %d: delivered %v, want %v", i, gotIds, tt.wantIds)
+		}
+		if !tt.wantErr && delivered != len(tt.wantIds) {
+			t.Errorf("pull %d: Pull() = %d, want %d", i, delivered, len(tt.wantIds))
+		}
+	}
+
+	state, _, _ := store.GetSyncState("accounts")
+	wantMark := time.Date(2024, 1, 1, 10, 2, 0, 0, time.UTC)
+	if !state.HighWaterMark.Equal(wantMark) {
+		t.Errorf("HighWaterMark = %v, want %v", state.HighWaterMark, wantMark)
+	}
+	// records changed before the overlap window are forgotten
+	if _, ok := state.Seen["001C"]; ok || len(state.Seen) != 2 {
+		t.Errorf("Seen = %v, want 001A and 001D", state.Seen)
+	}
+}
+
+func TestSalesforce_NewIncrementalSync(t *testing.T) {
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example", AccessToken: "1234"},
+	)
+	store := NewMemorySyncStateStore()
+	tests := []struct {
+		name    string
+		query   string
+		key     string
+		store   SyncStateStore
+		overlap time.Duration
+		wantErr bool
+	}{
+		{name: "valid", query: "SELECT Id, SystemModstamp FROM Account", key: "a", store: store},
+		{name: "no_key", query: "SELECT Id FROM Account", store: store, wantErr: true},
+		{name: "no_store", query: "SELECT Id FROM Account", key: "a", wantErr: true},
+		{
+			name:    "negative_overlap",
+			query:   "SELECT Id FROM Account",
+			key:     "a",
+			store:   store,
+			overlap: -time.Second,
+			wantErr: true,
+		},
+		{name: "no_from", query: "SELECT Id", key: "a", store: store, wantErr: true},
+		{
+			name:    "order_by",
+			query:   "SELECT Id FROM Account ORDER BY Name",
+			key:     "a",
+			store:   store,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sf.NewIncrementalSync(tt.query, tt.key, tt.store, tt.overlap)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewIncrementalSync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileSyncStateStore(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	store := NewFileSyncStateStore("sync.json")
+
+	if _, found, err := store.GetSyncState("accounts"); err != nil || found {
+		t.Fatalf("GetSyncState() = %v, %v, want not found", found, err)
+	}
+	state := SyncState{
+		HighWaterMark: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Seen:          map[string]time.Time{"001A": time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	if err := store.SetSyncState("accounts", state); err != nil {
+		t.Fatalf("SetSyncState() error = %v", err)
+	}
+	if err := store.SetSyncState("contacts", SyncState{}); err != nil {
+		t.Fatalf("SetSyncState() error = %v", err)
+	}
+	got, found, err := NewFileSyncStateStore("sync.json").GetSyncState("accounts")
+	if err != nil || !found || !reflect.DeepEqual(got, state) {
+		t.Errorf("GetSyncState() = %v, %v, %v, want %v", got, found, err, state)
+	}
+
+	if err := afero.WriteFile(appFs, "corrupt.json", []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewFileSyncStateStore("corrupt.json").GetSyncState("accounts"); err == nil {
+		t.Errorf("GetSyncState() expected error for corrupt file")
+	}
+}
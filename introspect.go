@@ -0,0 +1,99 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TokenIntrospection is the response of the OAuth 2.0 token introspection endpoint
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope"`
+	ClientId  string `json:"client_id"`
+	Username  string `json:"username"`
+	Subject   string `json:"sub"`
+	TokenType string `json:"token_type"`
+	Audience  string `json:"aud"`
+	Issuer    string `json:"iss"`
+	Exp       int64  `json:"exp"`
+	Iat       int64  `json:"iat"`
+	Nbf       int64  `json:"nbf"`
+}
+
+// ExpiresAt returns the expiration time of the token, or the zero time if it is unknown
+func (t TokenIntrospection) ExpiresAt() time.Time {
+	if t.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t.Exp, 0)
+}
+
+// ExpiresIn returns the remaining validity of the token, zero if the token is inactive or expired
+func (t TokenIntrospection) ExpiresIn() time.Duration {
+	if !t.Active || t.Exp == 0 {
+		return 0
+	}
+	return max(time.Until(t.ExpiresAt()), 0)
+}
+
+// Scopes returns the scopes granted to the token
+func (t TokenIntrospection) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// HasScope reports whether the token was granted the given scope
+func (t TokenIntrospection) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes(), scope)
+}
+
+// IntrospectToken returns the validity and scopes of the current session's access token.
+// Salesforce requires the consumer key and secret of the connected app or external client app to
+// call the introspection endpoint.
+func (sf *Salesforce) IntrospectToken() (*TokenIntrospection, error) {
+	if err := validateAuth(*sf); err != nil {
+		return nil, err
+	}
+	creds := sf.auth.creds
+	if creds.ConsumerKey == "" || creds.ConsumerSecret == "" {
+		return nil, errors.New(
+			"token introspection requires the consumer key and consumer secret used to authenticate",
+		)
+	}
+
+	payload := url.Values{
+		"token":           {sf.auth.AccessToken},
+		"token_type_hint": {"access_token"},
+		"client_id":       {creds.ConsumerKey},
+		"client_secret":   {creds.ConsumerSecret},
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		sf.auth.InstanceUrl+"/services/oauth2/introspect",
+		strings.NewReader(payload.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", jsonType)
+
+	resp, err := sf.config.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s: failed token introspection", resp.Status)
+	}
+
+	introspection := &TokenIntrospection{}
+	if err := decodeJSONResponse(resp, introspection); err != nil {
+		return nil, err
+	}
+	return introspection, nil
+}
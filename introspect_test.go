@@ -0,0 +1,86 @@
+package salesforce
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_IntrospectToken(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	resp := TokenIntrospection{
+		Active:   true,
+		Scope:    "api refresh_token",
+		ClientId: "id",
+		Username: "user@example.com",
+		Exp:      exp,
+	}
+
+	t.Run("active_token", func(t *testing.T) {
+		server, sfAuth, captured := setupTestServerWithCapture(resp, http.StatusOK)
+		defer server.Close()
+		sfAuth.creds = Creds{ConsumerKey: "id", ConsumerSecret: "secret"}
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.IntrospectToken()
+		if err != nil {
+			t.Fatalf("IntrospectToken() error = %v", err)
+		}
+		req := *captured
+		if req.URL.Path != "/services/oauth2/introspect" || req.Method != http.MethodPost {
+			t.Errorf("IntrospectToken() request = %v %v", req.Method, req.URL.Path)
+		}
+		if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("IntrospectToken() content type = %v", req.Header.Get("Content-Type"))
+		}
+		if !got.Active || got.ExpiresAt().Unix() != exp || got.ExpiresIn() <= 0 {
+			t.Errorf("IntrospectToken() = %+v", got)
+		}
+		if !got.HasScope("api") || got.HasScope("web") {
+			t.Errorf("IntrospectToken() scopes = %v", got.Scopes())
+		}
+	})
+
+	t.Run("inactive_token", func(t *testing.T) {
+		server, sfAuth := setupTestServer(TokenIntrospection{Active: false}, http.StatusOK)
+		defer server.Close()
+		sfAuth.creds = Creds{ConsumerKey: "id", ConsumerSecret: "secret"}
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.IntrospectToken()
+		if err != nil {
+			t.Fatalf("IntrospectToken() error = %v", err)
+		}
+		if got.Active || got.ExpiresIn() != 0 || !got.ExpiresAt().IsZero() {
+			t.Errorf("IntrospectToken() = %+v", got)
+		}
+	})
+
+	t.Run("missing_client_credentials", func(t *testing.T) {
+		server, sfAuth := setupTestServer(resp, http.StatusOK)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		if _, err := sf.IntrospectToken(); err == nil {
+			t.Errorf("IntrospectToken() expected error without client credentials")
+		}
+	})
+
+	t.Run("bad_request", func(t *testing.T) {
+		server, sfAuth := setupTestServer(resp, http.StatusBadRequest)
+		defer server.Close()
+		sfAuth.creds = Creds{ConsumerKey: "id", ConsumerSecret: "secret"}
+		sf := buildSalesforceStruct(&sfAuth)
+
+		if _, err := sf.IntrospectToken(); err == nil {
+			t.Errorf("IntrospectToken() expected error")
+		}
+	})
+
+	t.Run("not_authenticated", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{})
+		if _, err := sf.IntrospectToken(); err == nil {
+			t.Errorf("IntrospectToken() expected error")
+		}
+	})
+}
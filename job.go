@@ -0,0 +1,468 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// JobOperation is the DML operation a job sink performs on the records of a batch
+type JobOperation string
+
+const (
+	JobInsert JobOperation = insertOperation
+	JobUpdate JobOperation = updateOperation
+	JobUpsert JobOperation = upsertOperation
+	JobDelete JobOperation = deleteOperation
+)
+
+// JobSource reads the records of a Job one batch at a time
+type JobSource interface {
+	// Next returns the batch of records after the checkpoint, where an empty checkpoint is the start,
+	// and the checkpoint after the batch. An empty batch ends the job.
+	Next(ctx context.Context, checkpoint string) ([]map[string]any, string, error)
+}
+
+// JobSink writes the records of a batch of a Job
+type JobSink interface {
+	// Write writes the records, reporting records that fail in the results. An error fails the batch.
+	Write(ctx context.Context, records []map[string]any) (SalesforceResults, error)
+}
+
+// CheckpointStore persists the checkpoint of the last successful batch of each job, so that jobs can
+// resume after a restart. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// GetCheckpoint returns the stored checkpoint of the job, or an empty string if none is stored
+	GetCheckpoint(jobName string) (string, error)
+	// SetCheckpoint stores the checkpoint of the job, an empty checkpoint restarts it from the start
+	SetCheckpoint(jobName string, checkpoint string) error
+}
+
+// Job reads records from a source, transforms them, and writes them to a sink in batches. The
+// checkpoint of every batch that is written is stored, so a job that is run again resumes after the
+// last successful batch.
+type Job struct {
+	Name   string // key of the checkpoint of the job in the store
+	Source JobSource
+	// Transform is called on every record before it is written, nil writes records unchanged.
+	// Returning a nil record skips it, and an error stops the job before the batch is written.
+	Transform func(record map[string]any) (map[string]any, error)
+	Sink      JobSink
+	Store     CheckpointStore // in memory if nil
+	// OnBatch is called after every batch is written and checkpointed, returning an error stops the job
+	OnBatch func(batch JobBatch) error
+}
+
+// JobBatch is a batch of a Job that was written
+type JobBatch struct {
+	Checkpoint string // checkpoint after the batch
+	Records    int    // records read from the source
+	Results    SalesforceResults
+}
+
+// JobResult summarizes a run of a Job
+type JobResult struct {
+	Checkpoint string // checkpoint of the last successful batch
+	Batches    int
+	Records    int // records read from the source
+	Failed     int // records that failed to be written
+}
+
+// Run runs the job from its stored checkpoint until the source is exhausted, the context is done, or
+// a batch fails. Records that fail to be written are counted and reported to OnBatch, but do not
+// stop the job.
+func (j Job) Run(ctx context.Context) (JobResult, error) {
+	if j.Name == "" {
+		return JobResult{}, errors.New("job name is required")
+	}
+	if j.Source == nil || j.Sink == nil {
+		return JobResult{}, errors.New("job source and sink are required")
+	}
+	store := j.Store
+	if store == nil {
+		store = NewMemoryCheckpointStore()
+	}
+	checkpoint, err := store.GetCheckpoint(j.Name)
+	if err != nil {
+		return JobResult{}, err
+	}
+
+	result := JobResult{Checkpoint: checkpoint}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		records, next, err := j.Source.Next(ctx, result.Checkpoint)
+		if err != nil {
+			return result, fmt.Errorf(
+				"reading batch after checkpoint %q: %w",
+				result.Checkpoint,
+				err,
+			)
+		}
+		if len(records) == 0 {
+			return result, nil
+		}
+
+		batch := JobBatch{Checkpoint: next, Records: len(records)}
+		transformed, err := j.transform(records)
+		if err != nil {
+			return result, err
+		}
+		if len(transformed) > 0 {
+			batch.Results, err = j.Sink.Write(ctx, transformed)
+			if err != nil {
+				return result, fmt.Errorf(
+					"writing batch after checkpoint %q: %w",
+					result.Checkpoint,
+					err,
+				)
+			}
+		}
+		if err := store.SetCheckpoint(j.Name, next); err != nil {
+			return result, err
+		}
+
+		result.Checkpoint = next
+		result.Batches++
+		result.Records += batch.Records
+		result.Failed += len(batch.Results.Failures())
+		if j.OnBatch != nil {
+			if err := j.OnBatch(batch); err != nil {
+				return result, err
+			}
+		}
+	}
+}
+
+func (j Job) transform(records []map[string]any) ([]map[string]any, error) {
+	if j.Transform == nil {
+		return records, nil
+	}
+	transformed := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		record, err := j.Transform(record)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			transformed = append(transformed, record)
+		}
+	}
+	return transformed, nil
+}
+
+type queryJobSource struct {
+	sf        *Salesforce
+	query     string
+	batchSize int
+}
+
+// NewQueryJobSource returns a JobSource that reads the records of a query in batches ordered by Id,
+// with the Id of the last record of a batch as its checkpoint. The query must select Id and cannot
+// have ORDER BY, LIMIT, OFFSET, GROUP BY, or FOR clauses.
+func (sf *Salesforce) NewQueryJobSource(query string, batchSize int) (JobSource, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if batchSize < 1 || batchSize > 2000 {
+		return nil, errors.New("batch size must be between 1 and 2000")
+	}
+	if soqlClauseIndex(query, "FROM") < 0 {
+		return nil, errors.New("query must have a FROM clause")
+	}
+	for _, clause := range []string{"ORDER BY", "LIMIT", "OFFSET", "GROUP BY", "FOR"} {
+		if soqlClauseIndex(query, clause) >= 0 {
+			return nil, fmt.Errorf("query cannot have a %s clause", clause)
+		}
+	}
+	return &queryJobSource{sf: sf, query: query, batchSize: batchSize}, nil
+}
+
+func (s *queryJobSource) Next(
+	ctx context.Context,
+	checkpoint string,
+) ([]map[string]any, string, error) {
+	query := s.query
+	if checkpoint != "" {
+		query = addSoqlCondition(query, "Id > '"+escapeSoqlString(checkpoint)+"'")
+	}
+	query += " ORDER BY Id LIMIT " + strconv.Itoa(s.batchSize)
+	queryResp, err := getQueryPage(ctx, s.sf, "/query/?q="+url.QueryEscape(query))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(queryResp.Records) == 0 {
+		return nil, checkpoint, nil
+	}
+	lastId, ok := queryResp.Records[len(queryResp.Records)-1]["Id"].(string)
+	if !ok {
+		return nil, "", errors.New("query must select Id")
+	}
+	return queryResp.Records, lastId, nil
+}
+
+type sliceJobSource struct {
+	records   []map[string]any
+	batchSize int
+}
+
+// NewSliceJobSource returns a JobSource that reads a slice of records in batches, such as records
+// read from a file, with the number of records read so far as its checkpoint. The slice must be the
+// same, in the same order, when a job is resumed.
+func NewSliceJobSource(records any, batchSize int) (JobSource, error) {
+	recordMap, err := convertToSliceOfMaps(records)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize < 1 {
+		return nil, errors.New("batch size must be greater than 0")
+	}
+	return &sliceJobSource{records: recordMap, batchSize: batchSize}, nil
+}
+
+func (s *sliceJobSource) Next(
+	_ context.Context,
+	checkpoint string,
+) ([]map[string]any, string, error) {
+	start := 0
+	if checkpoint != "" {
+		var err error
+		start, err = strconv.Atoi(checkpoint)
+		if err != nil || start < 0 {
+			return nil, "", fmt.Errorf("invalid checkpoint: %q", checkpoint)
+		}
+	}
+	if start >= len(s.records) {
+		return nil, checkpoint, nil
+	}
+	end := min(start+s.batchSize, len(s.records))
+	return s.records[start:end], strconv.Itoa(end), nil
+}
+
+type collectionJobSink struct {
+	sf                  *Salesforce
+	operation           JobOperation
+	sObjectName         string
+	externalIdFieldName string
+}
+
+// NewCollectionJobSink returns a JobSink that writes records with the sObject Collections API, 200
+// records at a time. externalIdFieldName is only used to upsert.
+func (sf *Salesforce) NewCollectionJobSink(
+	operation JobOperation,
+	sObjectName string,
+	externalIdFieldName string,
+) (JobSink, error) {
+	if err := validateJobSink(operation, sObjectName, externalIdFieldName); err != nil {
+		return nil, err
+	}
+	return &collectionJobSink{
+		sf:                  sf,
+		operation:           operation,
+		sObjectName:         sObjectName,
+		externalIdFieldName: externalIdFieldName,
+	}, nil
+}
+
+func (s *collectionJobSink) Write(
+	_ context.Context,
+	records []map[string]any,
+) (SalesforceResults, error) {
+	batchSize := s.sf.config.batchSizeMax
+	switch s.operation {
+	case JobInsert:
+		return s.sf.InsertCollection(s.sObjectName, records, batchSize)
+	case JobUpdate:
+		return s.sf.UpdateCollection(s.sObjectName, records, batchSize)
+	case JobUpsert:
+		return s.sf.UpsertCollection(s.sObjectName, s.externalIdFieldName, records, batchSize)
+	default:
+		return s.sf.DeleteCollection(s.sObjectName, records, batchSize)
+	}
+}
+
+type bulkJobSink struct {
+	sf                  *Salesforce
+	operation           JobOperation
+	sObjectName         string
+	externalIdFieldName string
+}
+
+// NewBulkJobSink returns a JobSink that writes every batch with a Bulk API job and waits for its
+// results, for jobs with large batches. externalIdFieldName is only used to upsert.
+func (sf *Salesforce) NewBulkJobSink(
+	operation JobOperation,
+	sObjectName string,
+	externalIdFieldName string,
+) (JobSink, error) {
+	if err := validateJobSink(operation, sObjectName, externalIdFieldName); err != nil {
+		return nil, err
+	}
+	return &bulkJobSink{
+		sf:                  sf,
+		operation:           operation,
+		sObjectName:         sObjectName,
+		externalIdFieldName: externalIdFieldName,
+	}, nil
+}
+
+func (s *bulkJobSink) Write(
+	_ context.Context,
+	records []map[string]any,
+) (SalesforceResults, error) {
+	batchSize := s.sf.config.bulkBatchSizeMax
+	var jobIds []string
+	var err error
+	switch s.operation {
+	case JobInsert:
+		jobIds, err = s.sf.InsertBulk(s.sObjectName, records, batchSize, true)
+	case JobUpdate:
+		jobIds, err = s.sf.UpdateBulk(s.sObjectName, records, batchSize, true)
+	case JobUpsert:
+		jobIds, err = s.sf.UpsertBulk(
+			s.sObjectName,
+			s.externalIdFieldName,
+			records,
+			batchSize,
+			true,
+		)
+	default:
+		jobIds, err = s.sf.DeleteBulk(s.sObjectName, records, batchSize, true)
+	}
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+
+	results := SalesforceResults{}
+	for _, jobId := range jobIds {
+		jobResults, err := s.sf.GetJobResults(jobId)
+		if err != nil {
+			return results, err
+		}
+		for _, record := range jobResults.SuccessfulRecords {
+			id, _ := record["sf__Id"].(string)
+			results.Results = append(results.Results, SalesforceResult{Id: id, Success: true})
+		}
+		for _, record := range jobResults.FailedRecords {
+			message, _ := record["sf__Error"].(string)
+			sfErr := SalesforceErrorMessage{Message: message}
+			if code, rest, found := strings.Cut(message, ":"); found {
+				sfErr = SalesforceErrorMessage{ErrorCode: code, Message: rest}
+			}
+			results.Results = append(results.Results, SalesforceResult{
+				Errors: []SalesforceErrorMessage{sfErr},
+			})
+			results.HasSalesforceErrors = true
+		}
+	}
+	for i := range results.Results {
+		results.Results[i].Index = i
+	}
+	return results, nil
+}
+
+func validateJobSink(operation JobOperation, sObjectName string, externalIdFieldName string) error {
+	switch operation {
+	case JobInsert, JobUpdate, JobDelete:
+	case JobUpsert:
+		if externalIdFieldName == "" {
+			return errors.New("external id field name is required to upsert")
+		}
+	default:
+		return fmt.Errorf("invalid job operation: %q", operation)
+	}
+	if sObjectName == "" {
+		return errors.New("sObject name is required")
+	}
+	return nil
+}
+
+// MemoryCheckpointStore keeps job checkpoints in memory, useful for tests and jobs that do not need
+// to survive a restart
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]string
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]string{}}
+}
+
+func (s *MemoryCheckpointStore) GetCheckpoint(jobName string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[jobName], nil
+}
+
+func (s *MemoryCheckpointStore) SetCheckpoint(jobName string, checkpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[jobName] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore persists the checkpoints of all jobs to a single json file. Every update is
+// written to a temporary file which then replaces the original, so a crash never leaves a partial file.
+type FileCheckpointStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFileCheckpointStore(filePath string) *FileCheckpointStore {
+	return &FileCheckpointStore{filePath: filePath}
+}
+
+func (s *FileCheckpointStore) GetCheckpoint(jobName string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return checkpoints[jobName], nil
+}
+
+func (s *FileCheckpointStore) SetCheckpoint(jobName string, checkpoint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[jobName] = checkpoint
+
+	data, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	tempPath := s.filePath + ".tmp"
+	if err := afero.WriteFile(appFs, tempPath, data, 0o644); err != nil {
+		return err
+	}
+	return appFs.Rename(tempPath, s.filePath)
+}
+
+func (s *FileCheckpointStore) read() (map[string]string, error) {
+	checkpoints := map[string]string{}
+	data, err := afero.ReadFile(appFs, s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,323 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestJob_Run(t *testing.T) {
+	var written [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := sObjectCollection{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		results := []SalesforceResult{}
+		for _, record := range payload.Records {
+			delete(record, "attributes")
+			if record["Name"] == "fail" {
+				results = append(results, SalesforceResult{
+					Errors: []SalesforceErrorMessage{
+						{Message: "error", StatusCode: "REQUIRED_FIELD_MISSING"},
+					},
+				})
+				continue
+			}
+			results = append(results, SalesforceResult{Id: "001000000000001AAA", Success: true})
+		}
+		written = append(written, payload.Records)
+		body, _ := json.Marshal(results)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	records := []map[string]any{
+		{"Name": "one"},
+		{"Name": "skip"},
+		{"Name": "fail"},
+		{"Name": "four"},
+		{"Name": "five"},
+	}
+	source, err := NewSliceJobSource(records, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink, err := sf.NewCollectionJobSink(JobInsert, "Account", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryCheckpointStore()
+	stop := errors.New("stop")
+	var batches []JobBatch
+	job := Job{
+		Name:   "accounts",
+		Source: source,
+		Transform: func(record map[string]any) (map[string]any, error) {
+			if record["Name"] == "skip" {
+				return nil, nil
+			}
+			return map[string]any{"Name": record["Name"], "Type": "Customer"}, nil
+		},
+		Sink:  sink,
+		Store: store,
+		OnBatch: func(batch JobBatch) error {
+			batches = append(batches, batch)
+			if len(batches) == 2 {
+				return stop
+			}
+			return nil
+		},
+	}
+
+	result, err := job.Run(context.Background())
+	if !errors.Is(err, stop) {
+		t.Fatalf("Job.Run() error = %v, want %v", err, stop)
+	}
+	want := JobResult{Checkpoint: "4", Batches: 2, Records: 4, Failed: 1}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Job.Run() = %v, want %v", result, want)
+	}
+	if checkpoint, _ := store.GetCheckpoint("accounts"); checkpoint != "4" {
+		t.Errorf("checkpoint = %v, want 4", checkpoint)
+	}
+	if len(written[0]) != 1 || written[0][0]["Type"] != "Customer" {
+		t.Errorf("written = %v", written)
+	}
+	if batches[1].Checkpoint != "4" || batches[1].Results.Err() == nil {
+		t.Errorf("batches = %v", batches)
+	}
+
+	// running the job again resumes after the last checkpoint
+	written = nil
+	job.OnBatch = nil
+	result, err = job.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Job.Run() error = %v", err)
+	}
+	want = JobResult{Checkpoint: "5", Batches: 1, Records: 1}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Job.Run() = %v, want %v", result, want)
+	}
+	if len(written) != 1 || written[0][0]["Name"] != "five" {
+		t.Errorf("written = %v", written)
+	}
+
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{
+			name: "missing_name",
+			job:  Job{Source: source, Sink: sink},
+		},
+		{
+			name: "missing_sink",
+			job:  Job{Name: "accounts", Source: source},
+		},
+		{
+			name: "transform_error",
+			job: Job{
+				Name:   "transform",
+				Source: source,
+				Sink:   sink,
+				Transform: func(record map[string]any) (map[string]any, error) {
+					return nil, errors.New("transform error")
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.job.Run(context.Background()); err == nil {
+				t.Error("Job.Run() expected an error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_NewQueryJobSource(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		queries = append(queries, query)
+		records := []map[string]any{}
+		if !strings.Contains(query, "Id >") {
+			records = append(records,
+				map[string]any{"Id": "001000000000001AAA"},
+				map[string]any{"Id": "001000000000002AAA"},
+			)
+		}
+		body, _ := json.Marshal(
+			queryResponse{TotalSize: len(records), Done: true, Records: records},
+		)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	source, err := sf.NewQueryJobSource("SELECT Id FROM Account WHERE Type = 'Customer'", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, checkpoint, err := source.Next(context.Background(), "")
+	if err != nil || len(records) != 2 || checkpoint != "001000000000002AAA" {
+		t.Fatalf("Next() = %v, %v, %v", records, checkpoint, err)
+	}
+	records, checkpoint, err = source.Next(context.Background(), checkpoint)
+	if err != nil || len(records) != 0 || checkpoint != "001000000000002AAA" {
+		t.Fatalf("Next() = %v, %v, %v", records, checkpoint, err)
+	}
+	wantQueries := []string{
+		"SELECT Id FROM Account WHERE Type = 'Customer' ORDER BY Id LIMIT 2",
+		"SELECT Id FROM Account WHERE (Type = 'Customer') AND Id > '001000000000002AAA' ORDER BY Id LIMIT 2",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("queries = %v, want %v", queries, wantQueries)
+	}
+
+	for _, query := range []string{
+		"SELECT Id",
+		"SELECT Id FROM Account LIMIT 10",
+		"SELECT Id FROM Account ORDER BY Name",
+	} {
+		if _, err := sf.NewQueryJobSource(query, 2); err == nil {
+			t.Errorf("NewQueryJobSource(%q) expected an error", query)
+		}
+	}
+	if _, err := sf.NewQueryJobSource("SELECT Id FROM Account", 0); err == nil {
+		t.Error("NewQueryJobSource() expected an error for batch size 0")
+	}
+}
+
+func TestNewSliceJobSource(t *testing.T) {
+	source, err := NewSliceJobSource(
+		[]map[string]any{{"Name": "a"}, {"Name": "b"}, {"Name": "c"}},
+		2,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		checkpoint     string
+		wantLen        int
+		wantCheckpoint string
+		wantErr        bool
+	}{
+		{checkpoint: "", wantLen: 2, wantCheckpoint: "2"},
+		{checkpoint: "2", wantLen: 1, wantCheckpoint: "3"},
+		{checkpoint: "3", wantLen: 0, wantCheckpoint: "3"},
+		{checkpoint: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("checkpoint_%q", tt.checkpoint), func(t *testing.T) {
+			records, checkpoint, err := source.Next(context.Background(), tt.checkpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(records) != tt.wantLen || checkpoint != tt.wantCheckpoint {
+				t.Errorf(
+					"Next() = %v, %v, want %v records, %v",
+					records,
+					checkpoint,
+					tt.wantLen,
+					tt.wantCheckpoint,
+				)
+			}
+		})
+	}
+	if _, err := NewSliceJobSource([]map[string]any{}, 0); err == nil {
+		t.Error("NewSliceJobSource() expected an error for batch size 0")
+	}
+}
+
+func Test_validateJobSink(t *testing.T) {
+	tests := []struct {
+		name                string
+		operation           JobOperation
+		sObjectName         string
+		externalIdFieldName string
+		wantErr             bool
+	}{
+		{
+			name:        "insert",
+			operation:   JobInsert,
+			sObjectName: "Account",
+		},
+		{
+			name:                "upsert",
+			operation:           JobUpsert,
+			sObjectName:         "Account",
+			externalIdFieldName: "External_Id__c",
+		},
+		{
+			name:        "upsert_without_external_id",
+			operation:   JobUpsert,
+			sObjectName: "Account",
+			wantErr:     true,
+		},
+		{
+			name:        "invalid_operation",
+			operation:   "merge",
+			sObjectName: "Account",
+			wantErr:     true,
+		},
+		{
+			name:      "missing_sobject_name",
+			operation: JobDelete,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateJobSink(tt.operation, tt.sObjectName, tt.externalIdFieldName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateJobSink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	store := NewFileCheckpointStore("checkpoints.json")
+
+	checkpoint, err := store.GetCheckpoint("accounts")
+	if err != nil || checkpoint != "" {
+		t.Fatalf("FileCheckpointStore.GetCheckpoint() = %v, %v, want empty", checkpoint, err)
+	}
+	if err := store.SetCheckpoint("accounts", "001000000000002AAA"); err != nil {
+		t.Fatalf("FileCheckpointStore.SetCheckpoint() error = %v", err)
+	}
+	if err := store.SetCheckpoint("contacts", "200"); err != nil {
+		t.Fatalf("FileCheckpointStore.SetCheckpoint() error = %v", err)
+	}
+
+	// a new store reading the same file resumes from the persisted values
+	reopened := NewFileCheckpointStore("checkpoints.json")
+	checkpoint, err = reopened.GetCheckpoint("accounts")
+	if err != nil || checkpoint != "001000000000002AAA" {
+		t.Errorf("FileCheckpointStore.GetCheckpoint() = %v, %v", checkpoint, err)
+	}
+	checkpoint, err = reopened.GetCheckpoint("contacts")
+	if err != nil || checkpoint != "200" {
+		t.Errorf("FileCheckpointStore.GetCheckpoint() = %v, %v", checkpoint, err)
+	}
+	if exists, _ := afero.Exists(appFs, "checkpoints.json.tmp"); exists {
+		t.Errorf("FileCheckpointStore left a temporary file behind")
+	}
+
+	if err := afero.WriteFile(appFs, "corrupt.json", []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileCheckpointStore("corrupt.json").GetCheckpoint("accounts"); err == nil {
+		t.Errorf("FileCheckpointStore.GetCheckpoint() expected error for corrupt file")
+	}
+}
@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const knowledgeVersionsUri = "/knowledgeManagement/articleVersions/masterVersions"
+
+// Publish statuses of a knowledge article version
+const (
+	KnowledgePublishStatusOnline   = "Online"
+	KnowledgePublishStatusArchived = "Archived"
+)
+
+// DataCategorySelection assigns a knowledge article version to a category of a data category group
+type DataCategorySelection struct {
+	GroupName    string // API name of the data category group
+	CategoryName string // API name of the category within the group
+}
+
+// DataCategoryGroup is a data category group and its tree of categories
+type DataCategoryGroup struct {
+	Name            string         `json:"name"`
+	Label           string         `json:"label"`
+	Active          bool           `json:"active"`
+	ObjectUsage     string         `json:"objectUsage"`
+	TopCategories   []DataCategory `json:"topCategories"`
+	TotalCategories int            `json:"totalCategories"`
+}
+
+// DataCategory is a category of a data category group
+type DataCategory struct {
+	Name            string         `json:"name"`
+	Label           string         `json:"label"`
+	ChildCategories []DataCategory `json:"childCategories"`
+}
+
+type knowledgeVersionResponse struct {
+	Id string `json:"id"`
+}
+
+// CreateKnowledgeDraft creates a draft version of a published or archived knowledge article, so that
+// it can be edited with UpdateOne and published again. It returns the id of the draft article version.
+func (sf *Salesforce) CreateKnowledgeDraft(knowledgeArticleId string) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if knowledgeArticleId == "" {
+		return "", errors.New("knowledge article id is required")
+	}
+	body, err := json.Marshal(map[string]string{"articleId": knowledgeArticleId})
+	if err != nil {
+		return "", err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      knowledgeVersionsUri,
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return "", err
+	}
+	version := knowledgeVersionResponse{}
+	if err := decodeJSONResponse(resp, &version); err != nil {
+		return "", err
+	}
+	return version.Id, nil
+}
+
+// PublishKnowledgeArticle publishes a draft knowledge article version, replacing its online version
+func (sf *Salesforce) PublishKnowledgeArticle(articleVersionId string) error {
+	return setKnowledgePublishStatus(sf, articleVersionId, KnowledgePublishStatusOnline)
+}
+
+// ArchiveKnowledgeArticle archives a published knowledge article version
+func (sf *Salesforce) ArchiveKnowledgeArticle(articleVersionId string) error {
+	return setKnowledgePublishStatus(sf, articleVersionId, KnowledgePublishStatusArchived)
+}
+
+// AssignDataCategories assigns a knowledge article version to data categories by creating data category
+// selections. articleObjectName is the article version sObject, such as Knowledge__kav, whose
+// selections are records of Knowledge__DataCategorySelection.
+func (sf *Salesforce) AssignDataCategories(
+	articleObjectName string,
+	articleVersionId string,
+	selections []DataCategorySelection,
+) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if !strings.HasSuffix(articleObjectName, "__kav") {
+		return SalesforceResults{}, errors.New(
+			"article object name must be a knowledge article version sObject, such as Knowledge__kav",
+		)
+	}
+	if articleVersionId == "" || len(selections) == 0 {
+		return SalesforceResults{}, errors.New(
+			"article version id and data category selections are required",
+		)
+	}
+	records := make([]map[string]any, len(selections))
+	for i, selection := range selections {
+		if selection.GroupName == "" || selection.CategoryName == "" {
+			return SalesforceResults{}, errors.New(
+				"data category selections require a group name and category name",
+			)
+		}
+		records[i] = map[string]any{
+			"ParentId":              articleVersionId,
+			"DataCategoryGroupName": selection.GroupName,
+			"DataCategoryName":      selection.CategoryName,
+		}
+	}
+	articleType := strings.TrimSuffix(articleObjectName, "__kav")
+	return sf.InsertCollection(
+		articleType+"__DataCategorySelection",
+		records,
+		sf.config.batchSizeMax,
+	)
+}
+
+// GetDataCategoryGroups returns the active data category groups of an sObject, such as
+// KnowledgeArticleVersion, with their category trees
+func (sf *Salesforce) GetDataCategoryGroups(sObjectName string) ([]DataCategoryGroup, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method: http.MethodGet,
+		uri: "/support/dataCategoryGroups?topCategoriesOnly=false&sObjectName=" +
+			url.QueryEscape(sObjectName),
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	groups := struct {
+		CategoryGroups []DataCategoryGroup `json:"categoryGroups"`
+	}{}
+	if err := decodeJSONResponse(resp, &groups); err != nil {
+		return nil, err
+	}
+	return groups.CategoryGroups, nil
+}
+
+func setKnowledgePublishStatus(sf *Salesforce, articleVersionId string, status string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if articleVersionId == "" {
+		return errors.New("article version id is required")
+	}
+	body, err := json.Marshal(map[string]string{"publishStatus": status})
+	if err != nil {
+		return err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodPatch,
+		uri:      knowledgeVersionsUri + "/" + url.PathEscape(articleVersionId),
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
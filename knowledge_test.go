@@ -0,0 +1,160 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSalesforce_KnowledgeLifecycle(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		body   string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.Path, string(body)})
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(
+			[]byte(
+				`{"id":"ka0000000000002AAA","url":"/services/data/v63.0/sobjects/Knowledge__kav/ka0000000000002AAA"}`,
+			),
+		)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	versionId, err := sf.CreateKnowledgeDraft("kA0000000000001AAA")
+	if err != nil || versionId != "ka0000000000002AAA" {
+		t.Fatalf("CreateKnowledgeDraft() = %v, %v", versionId, err)
+	}
+	if err := sf.PublishKnowledgeArticle(versionId); err != nil {
+		t.Fatalf("PublishKnowledgeArticle() error = %v", err)
+	}
+	if err := sf.ArchiveKnowledgeArticle(versionId); err != nil {
+		t.Fatalf("ArchiveKnowledgeArticle() error = %v", err)
+	}
+	if _, err := sf.CreateKnowledgeDraft(""); err == nil {
+		t.Error("CreateKnowledgeDraft() expected an error without an article id")
+	}
+	if err := sf.PublishKnowledgeArticle(""); err == nil {
+		t.Error("PublishKnowledgeArticle() expected an error without a version id")
+	}
+
+	prefix := "/services/data/" + apiVersion + knowledgeVersionsUri
+	want := []request{
+		{http.MethodPost, prefix, `{"articleId":"kA0000000000001AAA"}`},
+		{http.MethodPatch, prefix + "/ka0000000000002AAA", `{"publishStatus":"Online"}`},
+		{http.MethodPatch, prefix + "/ka0000000000002AAA", `{"publishStatus":"Archived"}`},
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestSalesforce_AssignDataCategories(t *testing.T) {
+	var path string
+	var sent map[string][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(`[{"id":"02o000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	results, err := sf.AssignDataCategories(
+		"Knowledge__kav",
+		"ka0000000000002AAA",
+		[]DataCategorySelection{{GroupName: "Products", CategoryName: "Widgets"}},
+	)
+	if err != nil || results.HasSalesforceErrors {
+		t.Fatalf("AssignDataCategories() = %v, %v", results, err)
+	}
+	if path != "/services/data/"+apiVersion+"/composite/sobjects/" || len(sent["records"]) != 1 {
+		t.Fatalf("path = %s, body = %v", path, sent)
+	}
+	record := sent["records"][0]
+	if record["ParentId"] != "ka0000000000002AAA" ||
+		record["DataCategoryGroupName"] != "Products" ||
+		record["DataCategoryName"] != "Widgets" {
+		t.Errorf("record = %v", record)
+	}
+	if attributes, _ := record["attributes"].(map[string]any); attributes["type"] != "Knowledge__DataCategorySelection" {
+		t.Errorf("attributes = %v", record["attributes"])
+	}
+
+	tests := []struct {
+		name              string
+		articleObjectName string
+		selections        []DataCategorySelection
+	}{
+		{
+			name:              "not_article_version",
+			articleObjectName: "Knowledge__ka",
+			selections: []DataCategorySelection{
+				{GroupName: "Products", CategoryName: "Widgets"},
+			},
+		},
+		{name: "no_selections", articleObjectName: "Knowledge__kav"},
+		{
+			name:              "missing_category",
+			articleObjectName: "Knowledge__kav",
+			selections:        []DataCategorySelection{{GroupName: "Products"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sf.AssignDataCategories(
+				tt.articleObjectName,
+				"ka0000000000002AAA",
+				tt.selections,
+			)
+			if err == nil {
+				t.Error("AssignDataCategories() expected an error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetDataCategoryGroups(t *testing.T) {
+	groups := []DataCategoryGroup{{
+		Name:        "Products",
+		Label:       "Products",
+		Active:      true,
+		ObjectUsage: "KnowledgeArticleVersion",
+		TopCategories: []DataCategory{{
+			Name:            "All",
+			Label:           "All",
+			ChildCategories: []DataCategory{{Name: "Widgets", Label: "Widgets"}},
+		}},
+		TotalCategories: 2,
+	}}
+	server, auth, captured := setupTestServerWithCapture(
+		map[string]any{"categoryGroups": groups},
+		http.StatusOK,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	got, err := sf.GetDataCategoryGroups("KnowledgeArticleVersion")
+	if err != nil {
+		t.Fatalf("GetDataCategoryGroups() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, groups) {
+		t.Errorf("GetDataCategoryGroups() = %+v, want %+v", got, groups)
+	}
+	if query := (*captured).URL.Query(); query.Get("sObjectName") != "KnowledgeArticleVersion" {
+		t.Errorf("query = %v", query)
+	}
+}
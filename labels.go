@@ -0,0 +1,103 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DescribeSObjectInLanguage returns the metadata of an sObject with its labels translated to a
+// language, such as fr or pt-BR, using the Accept-Language header. Labels without a translation in
+// the org are returned in the default language. Translated describes are not cached.
+func (sf *Salesforce) DescribeSObjectInLanguage(
+	sObjectName string,
+	language string,
+) (*SObjectDescribe, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if sObjectName == "" {
+		return nil, errors.New("sObject name is required")
+	}
+	describe := &SObjectDescribe{}
+	if err := getInLanguage(sf, describeUri(sObjectName), language, describe); err != nil {
+		return nil, err
+	}
+	return describe, nil
+}
+
+// GetFieldLabels returns the labels of the fields of an sObject, keyed by field name, translated to a
+// language, such as fr or pt-BR
+func (sf *Salesforce) GetFieldLabels(
+	sObjectName string,
+	language string,
+) (map[string]string, error) {
+	describe, err := sf.DescribeSObjectInLanguage(sObjectName, language)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(describe.Fields))
+	for _, field := range describe.Fields {
+		labels[field.Name] = field.Label
+	}
+	return labels, nil
+}
+
+// GetPicklistLabels returns the labels of the values of a picklist field for a record type, keyed by
+// value, translated to a language, such as fr or pt-BR. Use the master record type id,
+// 012000000000000AAA, for sObjects without record types.
+func (sf *Salesforce) GetPicklistLabels(
+	sObjectName string,
+	recordTypeId string,
+	fieldName string,
+	language string,
+) (map[string]string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if err := validatePicklistArgs(sObjectName, recordTypeId); err != nil {
+		return nil, err
+	}
+	if fieldName == "" {
+		return nil, errors.New("field name cannot be empty")
+	}
+
+	picklist := PicklistValues{}
+	uri := "/ui-api/object-info/" + url.PathEscape(sObjectName) +
+		"/picklist-values/" + url.PathEscape(recordTypeId) + "/" + url.PathEscape(fieldName)
+	if err := getInLanguage(sf, uri, language, &picklist); err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(picklist.Values))
+	for _, value := range picklist.Values {
+		labels[value.Value] = value.Label
+	}
+	return labels, nil
+}
+
+// getInLanguage sends a GET request with an Accept-Language header and decodes the response. The
+// response cache is bypassed, since it does not key responses by language.
+func getInLanguage(sf *Salesforce, uri string, language string, v any) error {
+	if language == "" {
+		return errors.New("language cannot be empty")
+	}
+	resp, err := sendRequest(sf.auth, sf.config, requestPayload{
+		ctx:      context.Background(),
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+		// Salesforce locales, such as pt_BR, are accepted as language tags
+		options: []RequestOption{
+			WithHeader("Accept-Language", strings.ReplaceAll(language, "_", "-")),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return decodeJSONResponse(resp, v)
+}
@@ -0,0 +1,85 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setupLabelServer(acceptLanguage *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*acceptLanguage = r.Header.Get("Accept-Language")
+		if strings.Contains(r.URL.Path, "/picklist-values/") {
+			_ = json.NewEncoder(w).Encode(PicklistValues{Values: []PicklistValue{
+				{Label: "Chaud", Value: "Hot"},
+				{Label: "Froid", Value: "Cold"},
+			}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(SObjectDescribe{
+			Name:  "Account",
+			Label: "Compte",
+			Fields: []DescribeField{
+				{Name: "Name", Label: "Nom du compte"},
+				{Name: "Rating", Label: "Évaluation"},
+			},
+		})
+	}))
+}
+
+func TestSalesforce_GetFieldLabels(t *testing.T) {
+	var acceptLanguage string
+	server := setupLabelServer(&acceptLanguage)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := sf.GetFieldLabels("Account", "fr_FR")
+	if err != nil {
+		t.Fatalf("GetFieldLabels() error = %v", err)
+	}
+	want := map[string]string{"Name": "Nom du compte", "Rating": "Évaluation"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFieldLabels() = %v, want %v", got, want)
+	}
+	if acceptLanguage != "fr-FR" {
+		t.Errorf("Accept-Language = %v, want fr-FR", acceptLanguage)
+	}
+
+	tests := []struct {
+		name        string
+		sObjectName string
+		language    string
+	}{
+		{name: "no_sobject", sObjectName: "", language: "fr"},
+		{name: "no_language", sObjectName: "Account", language: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sf.GetFieldLabels(tt.sObjectName, tt.language); err == nil {
+				t.Error("GetFieldLabels() expected an error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetPicklistLabels(t *testing.T) {
+	var acceptLanguage string
+	server := setupLabelServer(&acceptLanguage)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := sf.GetPicklistLabels("Account", "012000000000000AAA", "Rating", "fr")
+	if err != nil {
+		t.Fatalf("GetPicklistLabels() error = %v", err)
+	}
+	want := map[string]string{"Hot": "Chaud", "Cold": "Froid"}
+	if !reflect.DeepEqual(got, want) || acceptLanguage != "fr" {
+		t.Errorf("GetPicklistLabels() = %v, Accept-Language = %v", got, acceptLanguage)
+	}
+	if _, err := sf.GetPicklistLabels("Account", "012000000000000AAA", "", "fr"); err == nil {
+		t.Error("GetPicklistLabels() expected an error without a field name")
+	}
+}
@@ -0,0 +1,71 @@
+package salesforce
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const limitInfoHeader = "Sforce-Limit-Info"
+
+// APIUsage is the daily API request usage reported by Salesforce in the Sforce-Limit-Info header
+type APIUsage struct {
+	Used int
+	Max  int
+}
+
+// Remaining returns the number of API requests left before reaching the daily limit
+func (u APIUsage) Remaining() int {
+	return u.Max - u.Used
+}
+
+type apiUsageTracker struct {
+	mu    sync.RWMutex
+	usage APIUsage
+}
+
+func (t *apiUsageTracker) update(limitInfo string) {
+	if t == nil {
+		return
+	}
+	usage, ok := parseLimitInfo(limitInfo)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = usage
+}
+
+func (t *apiUsageTracker) get() APIUsage {
+	if t == nil {
+		return APIUsage{}
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.usage
+}
+
+// parseLimitInfo parses a header value of the format "api-usage=25/15000"
+func parseLimitInfo(limitInfo string) (APIUsage, bool) {
+	for _, part := range strings.Split(limitInfo, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || key != "api-usage" {
+			continue
+		}
+		usedStr, maxStr, found := strings.Cut(value, "/")
+		if !found {
+			return APIUsage{}, false
+		}
+		used, err := strconv.Atoi(usedStr)
+		if err != nil {
+			return APIUsage{}, false
+		}
+		max, err := strconv.Atoi(maxStr)
+		if err != nil {
+			return APIUsage{}, false
+		}
+		return APIUsage{Used: used, Max: max}, true
+	}
+	return APIUsage{}, false
+}
@@ -0,0 +1,77 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_parseLimitInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   APIUsage
+		wantOk bool
+	}{
+		{
+			name:   "api_usage",
+			header: "api-usage=25/15000",
+			want:   APIUsage{Used: 25, Max: 15000},
+			wantOk: true,
+		},
+		{
+			name:   "api_usage_with_other_limits",
+			header: "per-app-api-usage=2/250(appName=sample), api-usage=18/5000",
+			want:   APIUsage{Used: 18, Max: 5000},
+			wantOk: true,
+		},
+		{
+			name:   "empty_header",
+			header: "",
+			want:   APIUsage{},
+			wantOk: false,
+		},
+		{
+			name:   "invalid_usage",
+			header: "api-usage=abc/5000",
+			want:   APIUsage{},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLimitInfo(tt.header)
+			if ok != tt.wantOk {
+				t.Errorf("parseLimitInfo() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLimitInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_apiUsageTracker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(limitInfoHeader, "api-usage=42/15000")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	})
+
+	if _, err := sf.DoRequest(http.MethodGet, "/limits", nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	want := APIUsage{Used: 42, Max: 15000}
+	if got := sf.GetAPIUsage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAPIUsage() = %v, want %v", got, want)
+	}
+	if got := sf.GetAPIUsage().Remaining(); got != 14958 {
+		t.Errorf("APIUsage.Remaining() = %v, want %v", got, 14958)
+	}
+}
@@ -0,0 +1,147 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const soqlDateFormat = "2006-01-02"
+
+// Localization holds the time zones that Salesforce evaluates dates in. Date literals such as TODAY
+// and LAST_N_DAYS:n are evaluated in the time zone of the user running the query, while datetimes are
+// stored and compared in UTC, so converting with these helpers avoids records being synced a day late
+// or twice around midnight.
+type Localization struct {
+	UserId               string
+	UserTimeZone         *time.Location
+	UserLocale           string // such as en_US
+	OrgTimeZone          *time.Location
+	FiscalYearStartMonth time.Month // first month of the org's fiscal year
+}
+
+type identityResponse struct {
+	Timezone string `json:"timezone"`
+	Locale   string `json:"locale"`
+}
+
+// GetLocalization reads the time zone and locale of the authenticated user from their identity, and
+// the time zone and fiscal year of the org. Time zones are loaded from the system's time zone
+// database; import time/tzdata to embed one where the system has none.
+func (sf *Salesforce) GetLocalization(ctx context.Context) (*Localization, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	orgId, userId, err := identityIds(sf.auth)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:         ctx,
+		method:      http.MethodGet,
+		uri:         "/id/" + orgId + "/" + userId,
+		content:     jsonType,
+		instanceUri: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	identity := identityResponse{}
+	if err := decodeJSONResponse(resp, &identity); err != nil {
+		return nil, err
+	}
+	userTimeZone, err := loadTimeZone(identity.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := queryAllRecords(
+		ctx,
+		sf,
+		"SELECT TimeZoneSidKey, FiscalYearStartMonth FROM Organization",
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("organization %s not found", orgId)
+	}
+	orgTimeZoneKey, _ := orgs[0]["TimeZoneSidKey"].(string)
+	orgTimeZone, err := loadTimeZone(orgTimeZoneKey)
+	if err != nil {
+		return nil, err
+	}
+	fiscalYearStartMonth := time.January
+	if month, ok := numberValue(orgs[0]["FiscalYearStartMonth"]); ok && month >= 1 && month <= 12 {
+		fiscalYearStartMonth = time.Month(month)
+	}
+
+	return &Localization{
+		UserId:               userId,
+		UserTimeZone:         userTimeZone,
+		UserLocale:           identity.Locale,
+		OrgTimeZone:          orgTimeZone,
+		FiscalYearStartMonth: fiscalYearStartMonth,
+	}, nil
+}
+
+// UserTime returns t in the time zone of the user
+func (l *Localization) UserTime(t time.Time) time.Time {
+	return t.In(l.UserTimeZone)
+}
+
+// OrgTime returns t in the time zone of the org
+func (l *Localization) OrgTime(t time.Time) time.Time {
+	return t.In(l.OrgTimeZone)
+}
+
+// UserDate returns the date of t in the time zone of the user, formatted for comparing date fields in
+// SOQL, such as 2024-01-31
+func (l *Localization) UserDate(t time.Time) string {
+	return FormatSOQLDate(l.UserTime(t))
+}
+
+// StartOfUserDay returns the start of the user's day that contains t, in UTC
+func (l *Localization) StartOfUserDay(t time.Time) time.Time {
+	local := l.UserTime(t)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, l.UserTimeZone).UTC()
+}
+
+// TodayRange returns the UTC range, from start inclusive to end exclusive, that TODAY covers for
+// datetime fields when the user queries at now
+func (l *Localization) TodayRange(now time.Time) (time.Time, time.Time) {
+	return l.LastNDaysRange(0, now)
+}
+
+// LastNDaysRange returns the UTC range, from start inclusive to end exclusive, that LAST_N_DAYS:n
+// covers for datetime fields when the user queries at now: the past n days and today
+func (l *Localization) LastNDaysRange(n int, now time.Time) (time.Time, time.Time) {
+	local := l.UserTime(now)
+	start := time.Date(local.Year(), local.Month(), local.Day()-n, 0, 0, 0, 0, l.UserTimeZone)
+	end := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, l.UserTimeZone)
+	return start.UTC(), end.UTC()
+}
+
+// FormatSOQLDate formats the date of t, in its own time zone, for date fields in SOQL, such as 2024-01-31
+func FormatSOQLDate(t time.Time) string {
+	return t.Format(soqlDateFormat)
+}
+
+// FormatSOQLDateTime formats t in UTC for datetime fields in SOQL, such as 2024-01-31T23:00:00Z
+func FormatSOQLDateTime(t time.Time) string {
+	return t.UTC().Format(soqlDateTimeFormat)
+}
+
+// loadTimeZone loads a time zone by its Salesforce sid key, such as America/New_York
+func loadTimeZone(sidKey string) (*time.Location, error) {
+	if sidKey == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(sidKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading time zone %s: %w", sidKey, err)
+	}
+	return location, nil
+}
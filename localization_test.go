@@ -0,0 +1,115 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_GetLocalization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/id/00D000000000001AAA/005000000000001AAA":
+			_, _ = w.Write([]byte(`{"timezone": "America/New_York", "locale": "en_US"}`))
+		case strings.HasSuffix(r.URL.Path, "/query/"):
+			_, _ = w.Write([]byte(`{"done": true, "records": [` +
+				`{"TimeZoneSidKey": "Europe/Paris", "FiscalYearStartMonth": 4}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "1234",
+		Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA",
+	})
+
+	localization, err := sf.GetLocalization(context.Background())
+	if err != nil {
+		t.Fatalf("GetLocalization() error = %v", err)
+	}
+	if localization.UserTimeZone.String() != "America/New_York" ||
+		localization.OrgTimeZone.String() != "Europe/Paris" ||
+		localization.UserLocale != "en_US" || localization.FiscalYearStartMonth != time.April {
+		t.Errorf("GetLocalization() = %+v", localization)
+	}
+
+	sf.auth.Id = ""
+	if _, err := sf.GetLocalization(context.Background()); err == nil {
+		t.Errorf("GetLocalization() expected error without an identity url")
+	}
+}
+
+func TestLocalization(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	localization := &Localization{UserTimeZone: newYork, OrgTimeZone: time.UTC}
+	// 02:30 UTC on February 1st is still January 31st in New York
+	now := time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC)
+
+	if date := localization.UserDate(now); date != "2024-01-31" {
+		t.Errorf("UserDate() = %s, want 2024-01-31", date)
+	}
+	wantStart := time.Date(2024, time.January, 31, 5, 0, 0, 0, time.UTC)
+	if start := localization.StartOfUserDay(now); !start.Equal(wantStart) {
+		t.Errorf("StartOfUserDay() = %s, want %s", start, wantStart)
+	}
+
+	tests := []struct {
+		name      string
+		n         int
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "today",
+			wantStart: time.Date(2024, time.January, 31, 5, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.February, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "last_7_days",
+			n:         7,
+			wantStart: time.Date(2024, time.January, 24, 5, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.February, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			// the range starts before the change to daylight saving time and ends after it
+			name:      "across_dst",
+			n:         2,
+			wantStart: time.Date(2024, time.March, 9, 5, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.March, 12, 4, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now
+			if tt.name == "across_dst" {
+				at = time.Date(2024, time.March, 11, 12, 0, 0, 0, time.UTC)
+			}
+			start, end := localization.LastNDaysRange(tt.n, at)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf(
+					"LastNDaysRange() = %s, %s, want %s, %s",
+					start,
+					end,
+					tt.wantStart,
+					tt.wantEnd,
+				)
+			}
+		})
+	}
+	start, end := localization.TodayRange(now)
+	if FormatSOQLDateTime(start) != "2024-01-31T05:00:00Z" ||
+		FormatSOQLDateTime(end) != "2024-02-01T05:00:00Z" {
+		t.Errorf("TodayRange() = %s, %s", start, end)
+	}
+	if FormatSOQLDate(now) != "2024-02-01" {
+		t.Errorf("FormatSOQLDate() = %s", FormatSOQLDate(now))
+	}
+}
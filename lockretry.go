@@ -0,0 +1,116 @@
+package salesforce
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const unableToLockRowError = "UNABLE_TO_LOCK_ROW"
+
+// LockRetry configures retrying records of collection operations that failed because another
+// transaction locked them, see WithLockRetry. Zero values take the defaults noted on each field.
+type LockRetry struct {
+	MaxAttempts int           // times the locked records are retried (default 3)
+	InitialWait time.Duration // wait before the first retry, doubled for each retry (default 1 second)
+	MaxWait     time.Duration // longest wait between retries (default 30 seconds)
+	// OnRetry is called before each wait with the number of locked records, such as to log, if it is
+	// not nil
+	OnRetry func(records int, attempt int, wait time.Duration)
+}
+
+func newLockRetry(retry LockRetry) (*LockRetry, error) {
+	if retry.MaxAttempts < 0 || retry.InitialWait < 0 || retry.MaxWait < 0 {
+		return nil, errors.New("lock retry settings cannot be negative")
+	}
+	if retry.MaxAttempts == 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.InitialWait == 0 {
+		retry.InitialWait = time.Second
+	}
+	if retry.MaxWait == 0 {
+		retry.MaxWait = 30 * time.Second
+	}
+	if retry.MaxWait < retry.InitialWait {
+		return nil, errors.New("lock retry max wait cannot be less than the initial wait")
+	}
+	return &retry, nil
+}
+
+// isLockError returns whether a record failed because another transaction held a lock on it or on a
+// record it references
+func isLockError(result SalesforceResult) bool {
+	for _, sfError := range result.Errors {
+		if sfError.StatusCode == unableToLockRowError ||
+			sfError.ErrorCode == unableToLockRowError ||
+			strings.Contains(
+				strings.ToLower(sfError.Message),
+				"unable to obtain exclusive access",
+			) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryLockedRecords sends the records whose results are lock errors again, waiting longer and halving
+// the batch size before each retry, so that fewer records hold locks at once, and replaces their results
+func retryLockedRecords(
+	sf *Salesforce,
+	method string,
+	url string,
+	batchSize int,
+	records [][]byte,
+	ids []string,
+	results []SalesforceResult,
+	options []RequestOption,
+) ([]SalesforceResult, error) {
+	retry := sf.config.lockRetry
+	if retry == nil {
+		return results, nil
+	}
+	wait := retry.InitialWait
+	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
+		locked := []int{}
+		for i, result := range results {
+			if isLockError(result) {
+				locked = append(locked, i)
+			}
+		}
+		if len(locked) == 0 {
+			return results, nil
+		}
+		if retry.OnRetry != nil {
+			retry.OnRetry(len(locked), attempt, wait)
+		}
+		time.Sleep(wait)
+
+		lockedRecords := make([][]byte, len(locked))
+		lockedIds := make([]string, len(locked))
+		for i, index := range locked {
+			lockedRecords[i] = records[index]
+			lockedIds[i] = ids[index]
+		}
+		batchSize = max(batchSize/2, 1)
+		retried, err := sendCollectionBatches(
+			sf,
+			method,
+			url,
+			batchSize,
+			lockedRecords,
+			lockedIds,
+			nil,
+			options,
+		)
+		for i, result := range retried {
+			result.Index = locked[i]
+			results[locked[i]] = result
+		}
+		if err != nil {
+			return results, err
+		}
+		wait = min(wait*2, retry.MaxWait)
+	}
+	return results, nil
+}
@@ -0,0 +1,201 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_newLockRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   LockRetry
+		want    LockRetry
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			retry: LockRetry{},
+			want:  LockRetry{MaxAttempts: 3, InitialWait: time.Second, MaxWait: 30 * time.Second},
+		},
+		{
+			name:  "custom",
+			retry: LockRetry{MaxAttempts: 5, InitialWait: time.Millisecond, MaxWait: time.Second},
+			want:  LockRetry{MaxAttempts: 5, InitialWait: time.Millisecond, MaxWait: time.Second},
+		},
+		{
+			name:    "negative_attempts",
+			retry:   LockRetry{MaxAttempts: -1},
+			wantErr: true,
+		},
+		{
+			name:    "max_wait_less_than_initial_wait",
+			retry:   LockRetry{InitialWait: time.Minute, MaxWait: time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newLockRetry(tt.retry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newLockRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("newLockRetry() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isLockError(t *testing.T) {
+	tests := []struct {
+		name   string
+		result SalesforceResult
+		want   bool
+	}{
+		{
+			name: "unable_to_lock_row",
+			result: SalesforceResult{Errors: []SalesforceErrorMessage{{
+				StatusCode: "UNABLE_TO_LOCK_ROW",
+				Message:    "unable to obtain exclusive access to this record",
+			}}},
+			want: true,
+		},
+		{
+			name: "exclusive_access_message",
+			result: SalesforceResult{Errors: []SalesforceErrorMessage{{
+				StatusCode: "CANNOT_INSERT_UPDATE_ACTIVATE_ENTITY",
+				Message:    "AccountTrigger: execution of AfterUpdate caused by: System.DmlException: Update failed. UNABLE_TO_LOCK_ROW, unable to obtain exclusive access to this record or 1 records: 001000000000001",
+			}}},
+			want: true,
+		},
+		{
+			name: "other_error",
+			result: SalesforceResult{Errors: []SalesforceErrorMessage{{
+				StatusCode: "REQUIRED_FIELD_MISSING",
+				Message:    "Required fields are missing: [LastName]",
+			}}},
+			want: false,
+		},
+		{
+			name:   "success",
+			result: SalesforceResult{Success: true},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLockError(tt.result); got != tt.want {
+				t.Errorf("isLockError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// lockingServer responds with lock errors for the records named in locked until they have been sent
+// the given number of times, and records the names of the records of each request
+func lockingServer(locked map[string]int, batches *[][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Records []map[string]any `json:"records"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		batch := []string{}
+		results := []SalesforceResult{}
+		for _, record := range body.Records {
+			name, _ := record["LastName"].(string)
+			batch = append(batch, name)
+			if locked[name] > 0 {
+				locked[name]--
+				results = append(results, SalesforceResult{
+					Errors: []SalesforceErrorMessage{{
+						StatusCode: unableToLockRowError,
+						Message:    "unable to obtain exclusive access to this record",
+					}},
+				})
+				continue
+			}
+			results = append(results, SalesforceResult{Id: "003" + name, Success: true})
+		}
+		*batches = append(*batches, batch)
+		response, _ := json.Marshal(results)
+		_, _ = w.Write(response)
+	}))
+}
+
+func TestWithLockRetry_collection(t *testing.T) {
+	batches := [][]string{}
+	server := lockingServer(map[string]int{"b": 2, "d": 1}, &batches)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	})
+	retries := []int{}
+	err := WithLockRetry(LockRetry{
+		InitialWait: time.Millisecond,
+		MaxWait:     time.Millisecond,
+		OnRetry: func(records int, attempt int, wait time.Duration) {
+			retries = append(retries, records)
+		},
+	})(sf.config)
+	if err != nil {
+		t.Fatalf("WithLockRetry() error = %v", err)
+	}
+
+	records := []map[string]any{
+		{"LastName": "a"},
+		{"LastName": "b"},
+		{"LastName": "c"},
+		{"LastName": "d"},
+	}
+	results, err := sf.InsertCollection("Contact", records, 4)
+	if err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if results.HasSalesforceErrors {
+		t.Errorf("InsertCollection() HasSalesforceErrors = true, want false after retries")
+	}
+	for i, result := range results.Results {
+		if result.Index != i || result.Id != "003"+records[i]["LastName"].(string) {
+			t.Errorf("result %d = %+v, want the result of %v", i, result, records[i])
+		}
+	}
+	wantBatches := [][]string{{"a", "b", "c", "d"}, {"b", "d"}, {"b"}}
+	if !reflect.DeepEqual(batches, wantBatches) {
+		t.Errorf("batches = %v, want %v", batches, wantBatches)
+	}
+	if !reflect.DeepEqual(retries, []int{2, 1}) {
+		t.Errorf("OnRetry records = %v, want [2 1]", retries)
+	}
+}
+
+func TestWithLockRetry_maxAttempts(t *testing.T) {
+	batches := [][]string{}
+	server := lockingServer(map[string]int{"a": 10}, &batches)
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	})
+	err := WithLockRetry(LockRetry{MaxAttempts: 2, InitialWait: time.Millisecond})(sf.config)
+	if err != nil {
+		t.Fatalf("WithLockRetry() error = %v", err)
+	}
+
+	results, err := sf.InsertCollection("Contact", []map[string]any{{"LastName": "a"}}, 200)
+	if err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if !results.HasSalesforceErrors || !isLockError(results.Results[0]) {
+		t.Errorf("InsertCollection() = %+v, want the lock error", results)
+	}
+	if len(batches) != 3 {
+		t.Errorf("requests = %d, want 3", len(batches))
+	}
+}
@@ -0,0 +1,147 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const serverUnavailableError = "SERVER_UNAVAILABLE"
+
+// MaintenanceError is returned when Salesforce is unavailable, such as during scheduled maintenance or a
+// release, and responds with 503 Service Unavailable or a SERVER_UNAVAILABLE error
+type MaintenanceError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // from the Retry-After header of the response, zero if it has none
+}
+
+func (e *MaintenanceError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf(
+			"salesforce is unavailable: %d %s",
+			e.StatusCode,
+			http.StatusText(e.StatusCode),
+		)
+	}
+	return "salesforce is unavailable: " + e.Message
+}
+
+// MaintenanceBackoff configures waiting out Salesforce maintenance, see WithMaintenanceBackoff. Zero
+// values take the defaults noted on each field.
+type MaintenanceBackoff struct {
+	InitialWait time.Duration // wait before the first retry, doubled for each retry (default 30 seconds)
+	MaxWait     time.Duration // longest wait between retries (default 5 minutes)
+	MaxDuration time.Duration // time to keep retrying before returning the error (default 1 hour)
+	// OnMaintenance is called before each wait, such as to log or alert, if it is not nil
+	OnMaintenance func(err *MaintenanceError, wait time.Duration)
+}
+
+func newMaintenanceBackoff(backoff MaintenanceBackoff) (*MaintenanceBackoff, error) {
+	if backoff.InitialWait < 0 || backoff.MaxWait < 0 || backoff.MaxDuration < 0 {
+		return nil, errors.New("maintenance backoff settings cannot be negative")
+	}
+	if backoff.InitialWait == 0 {
+		backoff.InitialWait = 30 * time.Second
+	}
+	if backoff.MaxWait == 0 {
+		backoff.MaxWait = 5 * time.Minute
+	}
+	if backoff.MaxDuration == 0 {
+		backoff.MaxDuration = time.Hour
+	}
+	if backoff.MaxWait < backoff.InitialWait {
+		return nil, errors.New("maintenance backoff max wait cannot be less than the initial wait")
+	}
+	return &backoff, nil
+}
+
+// maintenanceError returns a MaintenanceError if a failed response is due to Salesforce being
+// unavailable. The body of such responses may be an HTML page rather than JSON errors.
+func maintenanceError(resp http.Response, body []byte) error {
+	var sfErrors []SalesforceErrorMessage
+	_ = json.Unmarshal(body, &sfErrors)
+	message := ""
+	unavailable := resp.StatusCode == http.StatusServiceUnavailable
+	for _, sfError := range sfErrors {
+		if sfError.ErrorCode == serverUnavailableError || unavailable {
+			message = sfError.Message
+			unavailable = true
+			break
+		}
+	}
+	if !unavailable {
+		return nil
+	}
+	return &MaintenanceError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter returns the wait of a Retry-After header, which is a number of seconds or an http date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(0, time.Duration(seconds)*time.Second)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(0, date.Sub(now))
+	}
+	return 0
+}
+
+// sendRequestWithMaintenanceBackoff sends a request, waiting and sending it again while Salesforce is
+// unavailable if WithMaintenanceBackoff is enabled. Waits honor the Retry-After header of responses.
+func sendRequestWithMaintenanceBackoff(
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
+) (*http.Response, error) {
+	resp, err := sendRequestOnce(auth, config, payload)
+	backoff := config.maintenanceBackoff
+	if backoff == nil {
+		return resp, err
+	}
+	ctx := payload.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	start := time.Now()
+	wait := backoff.InitialWait
+	for {
+		var maintenanceErr *MaintenanceError
+		if !errors.As(err, &maintenanceErr) {
+			return resp, err
+		}
+		delay := max(wait, maintenanceErr.RetryAfter)
+		if time.Since(start)+delay > backoff.MaxDuration || !rewindBody(payload.body) {
+			return resp, err
+		}
+		if backoff.OnMaintenance != nil {
+			backoff.OnMaintenance(maintenanceErr, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, errors.Join(err, ctx.Err())
+		}
+		// the maintenance response is discarded, so its connection can be reused
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		resp, err = sendRequestOnce(auth, config, payload)
+		wait = min(wait*2, backoff.MaxWait)
+	}
+}
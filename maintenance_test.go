@@ -0,0 +1,224 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_maintenanceError(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		header         http.Header
+		body           string
+		wantMessage    string
+		wantRetryAfter time.Duration
+		wantNil        bool
+	}{
+		{
+			name:   "html_page",
+			status: http.StatusServiceUnavailable,
+			body:   "<html><body>Down for maintenance</body></html>",
+		},
+		{
+			name:           "json_errors",
+			status:         http.StatusServiceUnavailable,
+			header:         http.Header{"Retry-After": {"120"}},
+			body:           `[{"errorCode": "SERVER_UNAVAILABLE", "message": "Scheduled maintenance"}]`,
+			wantMessage:    "Scheduled maintenance",
+			wantRetryAfter: 2 * time.Minute,
+		},
+		{
+			name:        "server_unavailable_code",
+			status:      http.StatusInternalServerError,
+			body:        `[{"errorCode": "SERVER_UNAVAILABLE", "message": "Server unavailable"}]`,
+			wantMessage: "Server unavailable",
+		},
+		{
+			name:    "other_error",
+			status:  http.StatusBadRequest,
+			body:    `[{"errorCode": "MALFORMED_QUERY", "message": "unexpected token"}]`,
+			wantNil: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := http.Response{StatusCode: tt.status, Header: tt.header}
+			if resp.Header == nil {
+				resp.Header = http.Header{}
+			}
+			err := maintenanceError(resp, []byte(tt.body))
+			if tt.wantNil {
+				if err != nil {
+					t.Errorf("maintenanceError() = %v, want nil", err)
+				}
+				return
+			}
+			var maintenanceErr *MaintenanceError
+			if !errors.As(err, &maintenanceErr) {
+				t.Fatalf("maintenanceError() = %v, want a MaintenanceError", err)
+			}
+			if maintenanceErr.StatusCode != tt.status || maintenanceErr.Message != tt.wantMessage ||
+				maintenanceErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("maintenanceError() = %+v", maintenanceErr)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "30", want: 30 * time.Second},
+		{value: "Fri, 01 Mar 2024 12:05:00 GMT", want: 5 * time.Minute},
+		{value: "Fri, 01 Mar 2024 11:00:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMaintenanceBackoff_requests(t *testing.T) {
+	tests := []struct {
+		name         string
+		unavailable  int32 // responses that are 503 before the org is back
+		backoff      *MaintenanceBackoff
+		wantErr      bool
+		wantRequests int32
+		wantWaits    int
+	}{
+		{
+			name:         "disabled",
+			unavailable:  1,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "waits_out_maintenance",
+			unavailable:  2,
+			backoff:      &MaintenanceBackoff{InitialWait: time.Millisecond},
+			wantRequests: 3,
+			wantWaits:    2,
+		},
+		{
+			name:        "max_duration",
+			unavailable: 100,
+			backoff: &MaintenanceBackoff{
+				InitialWait: 10 * time.Millisecond,
+				MaxDuration: 25 * time.Millisecond,
+			},
+			// the second wait of 20ms would end after the max duration
+			wantErr:      true,
+			wantRequests: 2,
+			wantWaits:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := &atomic.Int32{}
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if requests.Add(1) <= tt.unavailable {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						_, _ = w.Write([]byte("<html>Down for maintenance</html>"))
+						return
+					}
+					_, _ = w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+				}),
+			)
+			defer server.Close()
+			sf := buildSalesforceStruct(
+				&authentication{InstanceUrl: server.URL, AccessToken: "1234"},
+			)
+			waits := 0
+			if tt.backoff != nil {
+				tt.backoff.OnMaintenance = func(err *MaintenanceError, wait time.Duration) {
+					waits++
+				}
+				if err := WithMaintenanceBackoff(*tt.backoff)(sf.config); err != nil {
+					t.Fatalf("WithMaintenanceBackoff() error = %v", err)
+				}
+			}
+
+			records := []map[string]any{}
+			err := sf.Query("SELECT Id FROM Account", &records)
+			var maintenanceErr *MaintenanceError
+			if tt.wantErr != errors.As(err, &maintenanceErr) || (!tt.wantErr && err != nil) {
+				t.Errorf("Query() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests.Load() != tt.wantRequests || waits != tt.wantWaits {
+				t.Errorf(
+					"requests = %d, waits = %d, want %d, %d",
+					requests.Load(),
+					waits,
+					tt.wantRequests,
+					tt.wantWaits,
+				)
+			}
+		})
+	}
+}
+
+// bodyTrackingTransport responds with 503 to the first requests and records the bodies it returns
+type bodyTrackingTransport struct {
+	unavailable int
+	bodies      []*closeTrackingBody
+}
+
+func (tr *bodyTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	content := `{"totalSize": 0, "done": true, "records": []}`
+	if len(tr.bodies) < tr.unavailable {
+		status = http.StatusServiceUnavailable
+		content = "<html>Down for maintenance</html>"
+	}
+	body := &closeTrackingBody{Reader: strings.NewReader(content)}
+	tr.bodies = append(tr.bodies, body)
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{},
+		Body:       body,
+		Request:    req,
+	}, nil
+}
+
+func TestWithMaintenanceBackoff_closesDiscardedResponses(t *testing.T) {
+	transport := &bodyTrackingTransport{unavailable: 2}
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example.my.salesforce.com", AccessToken: "1234"},
+	)
+	sf.config.roundTripper = transport
+	sf.config.configureHttpClient()
+	err := WithMaintenanceBackoff(MaintenanceBackoff{InitialWait: time.Millisecond})(sf.config)
+	if err != nil {
+		t.Fatalf("WithMaintenanceBackoff() error = %v", err)
+	}
+
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(transport.bodies) != 3 {
+		t.Fatalf("requests = %d, want 3", len(transport.bodies))
+	}
+	for i, body := range transport.bodies[:2] {
+		if !body.closed {
+			t.Errorf("maintenance response %d was not closed", i)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+)
+
+const mergeRecordsMax = 2
+
+// MergeResult contains the outcome of a merge
+type MergeResult struct {
+	Id                string   // id of the master record
+	MergedRecordIds   []string // ids of the records that were merged into the master and deleted
+	UpdatedRelatedIds []string // ids of related records that were reparented to the master
+}
+
+// Merge merges up to two records into a master record of the same sObject with the SOAP API merge call.
+// The merged records are deleted and their related records are reparented to the master. Accounts,
+// contacts, leads, and cases can be merged. If Salesforce rejects the merge, its errors are returned as
+// a RecordError.
+func (sf *Salesforce) Merge(
+	sObjectName string,
+	masterId string,
+	mergedIds []string,
+) (MergeResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return MergeResult{}, authErr
+	}
+	if sObjectName == "" {
+		return MergeResult{}, errors.New("sObject name is required")
+	}
+	if len(mergedIds) == 0 || len(mergedIds) > mergeRecordsMax {
+		return MergeResult{}, fmt.Errorf(
+			"between 1 and %d records can be merged into a master record",
+			mergeRecordsMax,
+		)
+	}
+	for _, id := range append([]string{masterId}, mergedIds...) {
+		if !IsValidId(id) {
+			return MergeResult{}, fmt.Errorf("invalid id: %q", id)
+		}
+	}
+
+	result, err := soapMerge(sf, sObjectName, masterId, nil, mergedIds)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	if !result.Success {
+		return MergeResult{}, RecordError{Id: masterId, Errors: soapErrors(result.Errors)}
+	}
+	return MergeResult{
+		Id:                result.Id,
+		MergedRecordIds:   result.MergedRecordIds,
+		UpdatedRelatedIds: result.UpdatedRelatedIds,
+	}, nil
+}
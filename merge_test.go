@@ -0,0 +1,113 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_Merge(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<mergeResponse><result>`+
+		`<id>001000000000001AAA</id><mergedRecordIds>001000000000002AAA</mergedRecordIds>`+
+		`<mergedRecordIds>001000000000003AAA</mergedRecordIds><success>true</success>`+
+		`<updatedRelatedIds>003000000000001AAA</updatedRelatedIds></result></mergeResponse>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	failServer, _ := setupSoapTestServer(t, http.StatusOK, `<mergeResponse><result>`+
+		`<errors><message>Cannot merge records of different types</message>`+
+		`<statusCode>INVALID_ID_FIELD</statusCode></errors>`+
+		`<success>false</success></result></mergeResponse>`)
+	defer failServer.Close()
+	sfFail := buildSalesforceStruct(
+		&authentication{InstanceUrl: failServer.URL, AccessToken: "1234"},
+	)
+
+	type args struct {
+		sObjectName string
+		masterId    string
+		mergedIds   []string
+	}
+	tests := []struct {
+		name    string
+		sf      *Salesforce
+		args    args
+		want    MergeResult
+		wantErr bool
+	}{
+		{
+			name: "merge",
+			sf:   sf,
+			args: args{
+				sObjectName: "Account",
+				masterId:    "001000000000001AAA",
+				mergedIds:   []string{"001000000000002AAA", "001000000000003"},
+			},
+			want: MergeResult{
+				Id:                "001000000000001AAA",
+				MergedRecordIds:   []string{"001000000000002AAA", "001000000000003AAA"},
+				UpdatedRelatedIds: []string{"003000000000001AAA"},
+			},
+		},
+		{
+			name: "too_many_records",
+			sf:   sf,
+			args: args{
+				sObjectName: "Account",
+				masterId:    "001000000000001AAA",
+				mergedIds: []string{
+					"001000000000002AAA",
+					"001000000000003AAA",
+					"001000000000004AAA",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_id",
+			sf:   sf,
+			args: args{
+				sObjectName: "Account",
+				masterId:    "bad",
+				mergedIds:   []string{"001000000000002AAA"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejected",
+			sf:   sfFail,
+			args: args{
+				sObjectName: "Account",
+				masterId:    "001000000000001AAA",
+				mergedIds:   []string{"003000000000001AAA"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sf.Merge(tt.args.sObjectName, tt.args.masterId, tt.args.mergedIds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if len(*requests) != 1 || !strings.Contains(
+		(*requests)[0],
+		"<recordToMergeIds>001000000000002AAA</recordToMergeIds>"+
+			"<recordToMergeIds>001000000000003</recordToMergeIds>",
+	) {
+		t.Errorf("requests = %v", *requests)
+	}
+	_, err := sfFail.Merge("Account", "001000000000001AAA", []string{"003000000000001AAA"})
+	var recordErr RecordError
+	if !errors.As(err, &recordErr) || recordErr.Id != "001000000000001AAA" {
+		t.Errorf("Merge() error = %v, want a RecordError", err)
+	}
+}
@@ -0,0 +1,332 @@
+package salesforce
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// bulkJobsFinishedTTL is how long finished bulk jobs are remembered, so that reading a job again does
+// not count it twice
+const bulkJobsFinishedTTL = 24 * time.Hour
+
+// durationBuckets are the upper bounds, in seconds, of the request duration histogram
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type requestMetricLabels struct {
+	endpoint EndpointClass
+	method   string
+	status   string // status code of the response, or "error" if none was received
+}
+
+type bulkJobMetricLabels struct {
+	jobType string
+	state   string
+}
+
+type durationHistogram struct {
+	counts []int // requests per bucket, not cumulative, with a last bucket for longer requests
+	sum    float64
+	count  int
+}
+
+// Metrics collects metrics of the requests sent by the clients it is set on, see WithMetrics, and
+// serves them in the Prometheus text format, so it can be scraped without further instrumentation.
+// Metrics can be shared by several clients and is safe for concurrent use.
+type Metrics struct {
+	mu              sync.Mutex
+	requests        map[requestMetricLabels]int
+	durations       map[EndpointClass]*durationHistogram
+	apiUsage        APIUsage
+	apiUsageSet     bool
+	bulkJobsRunning map[string]string // job type by job id
+	bulkJobs        map[bulkJobMetricLabels]int
+	bulkJobsDone    *ttlCache          // ids of finished jobs
+	streamingLag    map[string]float64 // seconds by channel
+	now             func() time.Time
+}
+
+// NewMetrics returns empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:        map[requestMetricLabels]int{},
+		durations:       map[EndpointClass]*durationHistogram{},
+		bulkJobsRunning: map[string]string{},
+		bulkJobs:        map[bulkJobMetricLabels]int{},
+		bulkJobsDone:    newTTLCache(bulkJobsFinishedTTL),
+		streamingLag:    map[string]float64{},
+		now:             time.Now,
+	}
+}
+
+// ObserveEventLag records the time between an event being published and being processed, as the
+// streaming lag of its channel
+func (m *Metrics) ObserveEventLag(channel string, published time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streamingLag[channel] = m.now().Sub(published).Seconds()
+}
+
+// ObserveChangeEvent records the streaming lag of a Change Data Capture event from its commit time.
+// The EventBridge, DeltaSync, and RecordCache of a client with metrics observe their events.
+func (m *Metrics) ObserveChangeEvent(event ChangeEvent) {
+	if m == nil || event.Header.CommitTimestamp == 0 {
+		return
+	}
+	m.ObserveEventLag(event.Channel, event.Header.CommitTime())
+}
+
+func (m *Metrics) observeRequest(
+	endpoint EndpointClass,
+	method string,
+	resp *http.Response,
+	duration time.Duration,
+) {
+	if m == nil {
+		return
+	}
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestMetricLabels{endpoint: endpoint, method: method, status: status}]++
+
+	histogram, ok := m.durations[endpoint]
+	if !ok {
+		histogram = &durationHistogram{counts: make([]int, len(durationBuckets)+1)}
+		m.durations[endpoint] = histogram
+	}
+	seconds := duration.Seconds()
+	bucket, _ := slices.BinarySearch(durationBuckets, seconds)
+	histogram.counts[bucket]++
+	histogram.sum += seconds
+	histogram.count++
+}
+
+func (m *Metrics) observeLimitInfo(limitInfo string) {
+	if m == nil {
+		return
+	}
+	usage, ok := parseLimitInfo(limitInfo)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiUsage = usage
+	m.apiUsageSet = true
+}
+
+// observeBulkJob records the state of a bulk job each time it is read. Jobs are counted as running
+// until they are read in a final state, which is counted once.
+func (m *Metrics) observeBulkJob(jobType string, job BulkJobResults) {
+	if m == nil || job.Id == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch job.State {
+	case jobStateJobComplete, jobStateFailed, jobStateAborted:
+		delete(m.bulkJobsRunning, job.Id)
+		if _, done := m.bulkJobsDone.get(job.Id); !done {
+			m.bulkJobsDone.set(job.Id, true)
+			m.bulkJobs[bulkJobMetricLabels{jobType: jobType, state: job.State}]++
+		}
+	default:
+		m.bulkJobsRunning[job.Id] = jobType
+	}
+}
+
+// requestStats returns the requests and errors counted by endpoint class, in endpoint order
+func (m *Metrics) requestStats() []DiagnosticsRequestStats {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	byEndpoint := map[EndpointClass]*DiagnosticsRequestStats{}
+	for labels, count := range m.requests {
+		stats, ok := byEndpoint[labels.endpoint]
+		if !ok {
+			stats = &DiagnosticsRequestStats{Endpoint: labels.endpoint}
+			byEndpoint[labels.endpoint] = stats
+		}
+		stats.Requests += count
+		if code, err := strconv.Atoi(labels.status); err != nil || code >= http.StatusBadRequest {
+			stats.Errors += count
+		}
+	}
+	requestStats := make([]DiagnosticsRequestStats, 0, len(byEndpoint))
+	for _, stats := range byEndpoint {
+		requestStats = append(requestStats, *stats)
+	}
+	slices.SortFunc(requestStats, func(a, b DiagnosticsRequestStats) int {
+		return strings.Compare(string(a.Endpoint), string(b.Endpoint))
+	})
+	return requestStats
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format, mount it on the path scraped by
+// Prometheus, such as /metrics
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", prometheusContentType)
+	_ = m.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text format
+func (m *Metrics) Write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := bufio.NewWriter(w)
+
+	writeMetricHeader(out, "salesforce_requests_total", "counter", "Requests sent to Salesforce.")
+	requests := make([]string, 0, len(m.requests))
+	for labels, count := range m.requests {
+		requests = append(requests, fmt.Sprintf(
+			"salesforce_requests_total{endpoint=%s,method=%s,status=%s} %d\n",
+			quoteLabel(string(labels.endpoint)),
+			quoteLabel(labels.method),
+			quoteLabel(labels.status),
+			count,
+		))
+	}
+	writeSorted(out, requests)
+
+	writeMetricHeader(
+		out,
+		"salesforce_request_duration_seconds",
+		"histogram",
+		"Duration of requests sent to Salesforce.",
+	)
+	endpoints := make([]string, 0, len(m.durations))
+	for endpoint := range m.durations {
+		endpoints = append(endpoints, string(endpoint))
+	}
+	slices.Sort(endpoints)
+	for _, endpoint := range endpoints {
+		histogram := m.durations[EndpointClass(endpoint)]
+		label := "endpoint=" + quoteLabel(endpoint)
+		cumulative := 0
+		for i, count := range histogram.counts {
+			cumulative += count
+			bound := "+Inf"
+			if i < len(durationBuckets) {
+				bound = formatMetricValue(durationBuckets[i])
+			}
+			fmt.Fprintf(
+				out,
+				"salesforce_request_duration_seconds_bucket{%s,le=%s} %d\n",
+				label,
+				quoteLabel(bound),
+				cumulative,
+			)
+		}
+		fmt.Fprintf(
+			out,
+			"salesforce_request_duration_seconds_sum{%s} %s\n",
+			label,
+			formatMetricValue(histogram.sum),
+		)
+		fmt.Fprintf(
+			out,
+			"salesforce_request_duration_seconds_count{%s} %d\n",
+			label,
+			histogram.count,
+		)
+	}
+
+	if m.apiUsageSet {
+		writeMetricHeader(
+			out,
+			"salesforce_api_requests_used",
+			"gauge",
+			"API requests used in the last 24 hours, as last reported by Salesforce.",
+		)
+		fmt.Fprintf(out, "salesforce_api_requests_used %d\n", m.apiUsage.Used)
+		writeMetricHeader(
+			out,
+			"salesforce_api_requests_max",
+			"gauge",
+			"API requests allowed in 24 hours, as last reported by Salesforce.",
+		)
+		fmt.Fprintf(out, "salesforce_api_requests_max %d\n", m.apiUsage.Max)
+	}
+
+	writeMetricHeader(out, "salesforce_bulk_jobs_running", "gauge", "Bulk jobs not finished yet.")
+	running := map[string]int{}
+	for _, jobType := range m.bulkJobsRunning {
+		running[jobType]++
+	}
+	lines := []string{}
+	for jobType, count := range running {
+		lines = append(
+			lines,
+			fmt.Sprintf("salesforce_bulk_jobs_running{type=%s} %d\n", quoteLabel(jobType), count),
+		)
+	}
+	writeSorted(out, lines)
+
+	writeMetricHeader(out, "salesforce_bulk_jobs_total", "counter", "Bulk jobs finished by state.")
+	lines = lines[:0]
+	for labels, count := range m.bulkJobs {
+		lines = append(lines, fmt.Sprintf(
+			"salesforce_bulk_jobs_total{type=%s,state=%s} %d\n",
+			quoteLabel(labels.jobType),
+			quoteLabel(labels.state),
+			count,
+		))
+	}
+	writeSorted(out, lines)
+
+	writeMetricHeader(
+		out,
+		"salesforce_streaming_lag_seconds",
+		"gauge",
+		"Time between the last event of a channel being published and being processed.",
+	)
+	lines = lines[:0]
+	for channel, lag := range m.streamingLag {
+		lines = append(lines, fmt.Sprintf(
+			"salesforce_streaming_lag_seconds{channel=%s} %s\n",
+			quoteLabel(channel),
+			formatMetricValue(lag),
+		))
+	}
+	writeSorted(out, lines)
+
+	return out.Flush()
+}
+
+func writeMetricHeader(w io.Writer, name string, metricType string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSorted(w io.Writer, lines []string) {
+	slices.Sort(lines)
+	for _, line := range lines {
+		_, _ = io.WriteString(w, line)
+	}
+}
+
+// quoteLabel quotes a label value, escaping the characters the text format requires
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,109 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(limitInfoHeader, "api-usage=25/15000")
+		if strings.HasPrefix(r.URL.Path, "/services/data/"+apiVersion+"/jobs/") {
+			state := "InProgress"
+			if strings.HasSuffix(r.URL.Path, "/750B") {
+				state = jobStateJobComplete
+			}
+			_, _ = w.Write(
+				[]byte(`{"id":"` + r.URL.Path[len(r.URL.Path)-4:] + `","state":"` + state + `"}`),
+			)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`[{"errorCode":"NOT_FOUND","message":"not found"}]`))
+	}))
+	defer server.Close()
+	metrics := NewMetrics()
+	metrics.now = func() time.Time { return time.UnixMilli(1700000002500) }
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.metrics = metrics
+
+	for _, jobId := range []string{"750A", "750B", "750B"} {
+		if _, err := getJobResults(sf, ingestJobType, jobId); err != nil {
+			t.Fatalf("getJobResults() error = %v", err)
+		}
+	}
+	if _, err := getJobResults(sf, ingestJobType, "750C"); err != nil {
+		t.Fatalf("getJobResults() error = %v", err)
+	}
+	_, _ = sf.DescribeSObject("Account")
+	metrics.ObserveChangeEvent(ChangeEvent{
+		Channel: "/data/AccountChangeEvent",
+		Header:  ChangeEventHeader{CommitTimestamp: 1700000000000},
+	})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if contentType := recorder.Header().Get("Content-Type"); contentType != prometheusContentType {
+		t.Errorf("Content-Type = %s", contentType)
+	}
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE salesforce_requests_total counter\n",
+		`salesforce_requests_total{endpoint="bulk",method="GET",status="200"} 4` + "\n",
+		`salesforce_requests_total{endpoint="sobject",method="GET",status="404"} 1` + "\n",
+		"# TYPE salesforce_request_duration_seconds histogram\n",
+		`salesforce_request_duration_seconds_bucket{endpoint="bulk",le="+Inf"} 4` + "\n",
+		`salesforce_request_duration_seconds_count{endpoint="sobject"} 1` + "\n",
+		"salesforce_api_requests_used 25\n",
+		"salesforce_api_requests_max 15000\n",
+		`salesforce_bulk_jobs_running{type="ingest"} 2` + "\n",
+		`salesforce_bulk_jobs_total{type="ingest",state="JobComplete"} 1` + "\n",
+		`salesforce_streaming_lag_seconds{channel="/data/AccountChangeEvent"} 2.5` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics are missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetrics_observeRequest(t *testing.T) {
+	metrics := NewMetrics()
+	durations := []time.Duration{
+		10 * time.Millisecond,
+		100 * time.Millisecond,
+		2 * time.Second,
+		2 * time.Minute,
+	}
+	for _, duration := range durations {
+		metrics.observeRequest(EndpointQuery, http.MethodGet, nil, duration)
+	}
+
+	var body strings.Builder
+	if err := metrics.Write(&body); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, want := range []string{
+		`salesforce_requests_total{endpoint="query",method="GET",status="error"} 4`,
+		`salesforce_request_duration_seconds_bucket{endpoint="query",le="0.05"} 1`,
+		`salesforce_request_duration_seconds_bucket{endpoint="query",le="0.1"} 2`,
+		`salesforce_request_duration_seconds_bucket{endpoint="query",le="2.5"} 3`,
+		`salesforce_request_duration_seconds_bucket{endpoint="query",le="60"} 3`,
+		`salesforce_request_duration_seconds_bucket{endpoint="query",le="+Inf"} 4`,
+		`salesforce_request_duration_seconds_sum{endpoint="query"} 122.11`,
+	} {
+		if !strings.Contains(body.String(), want+"\n") {
+			t.Errorf("metrics are missing %q:\n%s", want, body.String())
+		}
+	}
+	if strings.Contains(body.String(), "salesforce_api_requests_used") {
+		t.Errorf("metrics report api usage before Salesforce reported it")
+	}
+
+	// a nil Metrics, as on clients without metrics, ignores observations
+	var disabled *Metrics
+	disabled.observeRequest(EndpointQuery, http.MethodGet, nil, time.Second)
+	disabled.ObserveChangeEvent(ChangeEvent{Header: ChangeEventHeader{CommitTimestamp: 1}})
+}
@@ -0,0 +1,117 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DomainNotAllowedError is returned when the instance url of a session, or a redirect of a request,
+// is outside the domains set with WithAllowedDomains
+type DomainNotAllowedError struct {
+	Url            string
+	AllowedDomains []string
+}
+
+func (e *DomainNotAllowedError) Error() string {
+	return fmt.Sprintf(
+		"%s is not within the allowed domains %s",
+		e.Url,
+		strings.Join(e.AllowedDomains, ", "),
+	)
+}
+
+// parseInstanceUrl returns an instance url without a trailing slash, requiring https so a pinned
+// instance never receives an access token in the clear
+func parseInstanceUrl(instanceUrl string) (string, error) {
+	parsed, err := url.Parse(instanceUrl)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", errors.New(
+			"instance url must be an https url, such as https://example.my.salesforce.com",
+		)
+	}
+	if parsed.Path != "" && parsed.Path != "/" {
+		return "", errors.New("instance url cannot have a path")
+	}
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
+
+// parseLoginUrl returns a login url without a trailing slash, requiring https so credentials are never
+// sent in the clear
+func parseLoginUrl(loginUrl string) (string, error) {
+	parsed, err := url.Parse(loginUrl)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", errors.New(
+			"login url must be an https url, such as https://login.salesforce.com",
+		)
+	}
+	if parsed.Path != "" && parsed.Path != "/" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", errors.New("login url cannot have a path, query, or fragment")
+	}
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
+
+// parseCommunityUrl returns the url of an Experience Cloud site without a trailing slash, requiring
+// https like parseInstanceUrl but allowing the path prefix of the site, such as /partners
+func parseCommunityUrl(communityUrl string) (string, error) {
+	parsed, err := url.Parse(communityUrl)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", errors.New(
+			"community url must be an https url, such as https://example.my.site.com/partners",
+		)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", errors.New("community url cannot have a query or fragment")
+	}
+	return parsed.Scheme + "://" + parsed.Host + strings.TrimSuffix(parsed.Path, "/"), nil
+}
+
+// domainAllowed returns whether a host is one of the allowed domains or a subdomain of one
+func domainAllowed(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInstanceUrl returns a DomainNotAllowedError if the instance url of a session is outside the
+// allowed domains
+func (c *configuration) checkInstanceUrl(instanceUrl string) error {
+	if len(c.allowedDomains) == 0 {
+		return nil
+	}
+	parsed, err := url.Parse(instanceUrl)
+	if err != nil {
+		return err
+	}
+	if !domainAllowed(parsed.Hostname(), c.allowedDomains) {
+		return &DomainNotAllowedError{Url: instanceUrl, AllowedDomains: c.allowedDomains}
+	}
+	return nil
+}
+
+// checkRedirect stops requests from following redirects outside the allowed domains, keeping the
+// default limit of 10 redirects
+func (c *configuration) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !domainAllowed(req.URL.Hostname(), c.allowedDomains) {
+		return &DomainNotAllowedError{Url: req.URL.String(), AllowedDomains: c.allowedDomains}
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInit_instanceUrl(t *testing.T) {
+	server, _ := setupTestServer(authentication{
+		AccessToken: "1234",
+		InstanceUrl: "https://other.my.salesforce.com",
+	}, http.StatusOK)
+	defer server.Close()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+
+	tests := []struct {
+		name      string
+		options   []Option
+		want      string
+		wantError bool
+	}{
+		{
+			name: "token_response_url",
+			want: "https://other.my.salesforce.com",
+		},
+		{
+			name:    "pinned_url",
+			options: []Option{WithInstanceUrl("https://example.my.salesforce.com/")},
+			want:    "https://example.my.salesforce.com",
+		},
+		{
+			name: "pinned_url_within_allowed_domains",
+			options: []Option{
+				WithInstanceUrl("https://example.my.salesforce.com"),
+				WithAllowedDomains("example.my.salesforce.com"),
+			},
+			want: "https://example.my.salesforce.com",
+		},
+		{
+			name:      "token_response_url_outside_allowed_domains",
+			options:   []Option{WithAllowedDomains("example.my.salesforce.com")},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf, err := Init(creds, tt.options...)
+			if tt.wantError {
+				var domainErr *DomainNotAllowedError
+				if !errors.As(err, &domainErr) {
+					t.Fatalf("Init() error = %v, want a DomainNotAllowedError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			if sf.auth.InstanceUrl != tt.want {
+				t.Errorf("InstanceUrl = %v, want %v", sf.auth.InstanceUrl, tt.want)
+			}
+		})
+	}
+}
+
+func TestInit_loginUrl(t *testing.T) {
+	server, _ := setupTestServer(authentication{
+		AccessToken: "1234",
+		InstanceUrl: "https://example.my.salesforce.mil",
+	}, http.StatusOK)
+	defer server.Close()
+	// the login url is set directly, since WithLoginUrl requires https
+	withLoginUrl := func(c *configuration) error {
+		c.loginUrl = server.URL
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		creds Creds
+	}{
+		{
+			name:  "without_domain",
+			creds: Creds{ConsumerKey: "key", ConsumerSecret: "secret"},
+		},
+		{
+			name: "overrides_domain",
+			creds: Creds{
+				Domain:         "https://unreachable.invalid",
+				ConsumerKey:    "key",
+				ConsumerSecret: "secret",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf, err := Init(tt.creds, withLoginUrl)
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			if sf.AuthFlow != AuthFlowClientCredentials ||
+				sf.auth.InstanceUrl != "https://example.my.salesforce.mil" {
+				t.Errorf("Init() = %v, %v", sf.AuthFlow, sf.auth.InstanceUrl)
+			}
+		})
+	}
+}
+
+func TestInit_communityUrl(t *testing.T) {
+	server, _ := setupTestServer(authentication{
+		AccessToken:  "1234",
+		InstanceUrl:  "https://example.my.salesforce.com",
+		CommunityUrl: "https://example.my.site.com/partners/",
+		CommunityId:  "0DB000000000001AAA",
+	}, http.StatusOK)
+	defer server.Close()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "token_response_community_url",
+			want: "https://example.my.site.com/partners",
+		},
+		{
+			name:    "configured_community_url",
+			options: []Option{WithCommunityUrl("https://example.my.site.com/customers")},
+			want:    "https://example.my.site.com/customers",
+		},
+		{
+			name:    "pinned_instance_url",
+			options: []Option{WithInstanceUrl("https://example.my.salesforce.com")},
+			want:    "https://example.my.salesforce.com",
+		},
+		{
+			name: "instance_and_community_url",
+			options: []Option{
+				WithInstanceUrl("https://example.my.salesforce.com"),
+				WithCommunityUrl("https://example.my.site.com/customers"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf, err := Init(creds, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if sf.GetInstanceUrl() != tt.want {
+				t.Errorf("GetInstanceUrl() = %v, want %v", sf.GetInstanceUrl(), tt.want)
+			}
+			if sf.GetCommunityId() != "0DB000000000001AAA" {
+				t.Errorf("GetCommunityId() = %v", sf.GetCommunityId())
+			}
+		})
+	}
+}
+
+func TestCommunityUrl_requests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/partners/services/data/"+apiVersion+"/sobjects/Account/001000000000001AAA" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Id": "001000000000001AAA"}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: server.URL + "/partners", AccessToken: "1234"},
+	)
+
+	resp, err := sf.DoRequest(http.MethodGet, "/sobjects/Account/001000000000001AAA", nil)
+	if err != nil {
+		t.Fatalf("request under the community url failed: %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func Test_checkRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/" + apiVersion + "/outside":
+			http.Redirect(w, r, "https://example.com/limits", http.StatusFound)
+		case "/services/data/" + apiVersion + "/inside":
+			http.Redirect(w, r, "/services/data/"+apiVersion+"/limits", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.allowedDomains = []string{"127.0.0.1"}
+	sf.config.configureHttpClient()
+
+	if _, err := sf.DoRequest(http.MethodGet, "/inside", nil); err != nil {
+		t.Errorf("DoRequest() error = %v for a redirect within the allowed domains", err)
+	}
+	_, err := sf.DoRequest(http.MethodGet, "/outside", nil)
+	var domainErr *DomainNotAllowedError
+	if !errors.As(err, &domainErr) {
+		t.Errorf("DoRequest() error = %v, want a DomainNotAllowedError", err)
+	}
+}
+
+func Test_checkRedirect_auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(
+			w,
+			r,
+			"https://example.com/services/oauth2/token",
+			http.StatusTemporaryRedirect,
+		)
+	}))
+	defer server.Close()
+	config := getDefaultConfig(t)
+	config.allowedDomains = []string{"127.0.0.1"}
+	config.configureHttpClient()
+
+	var domainErr *DomainNotAllowedError
+	_, err := clientCredentialsFlow(config, server.URL, "key", "secret")
+	if !errors.As(err, &domainErr) {
+		t.Errorf("clientCredentialsFlow() error = %v, want a DomainNotAllowedError", err)
+	}
+	_, err = soapLoginFlow(config, server.URL, "user@example.com", "pass", "")
+	if !errors.As(err, &domainErr) {
+		t.Errorf("soapLoginFlow() error = %v, want a DomainNotAllowedError", err)
+	}
+}
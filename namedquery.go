@@ -0,0 +1,154 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const soqlDateTimeFormat = "2006-01-02T15:04:05Z"
+
+// QueryNamed performs a SOQL query with named parameters, such as :email, replaced by the values of
+// params. Values are formatted as SOQL literals by type: strings are quoted and escaped, time.Time is a
+// UTC datetime, DateLiteral, numbers, and booleans are written as is, nil is null, and slices are lists
+// for IN clauses.
+func (sf *Salesforce) QueryNamed(query string, params map[string]any, sObject any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	boundQuery, err := bindNamedParameters(query, params)
+	if err != nil {
+		return err
+	}
+	return performQuery(sf, boundQuery, sObject)
+}
+
+// bindNamedParameters replaces the named parameters of a query with SOQL literals. Colons inside
+// string literals and those of date literals such as LAST_N_DAYS:30 are left unchanged.
+func bindNamedParameters(query string, params map[string]any) (string, error) {
+	var bound strings.Builder
+	runes := []rune(query)
+	inString := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inString && r == '\\' && i+1 < len(runes):
+			bound.WriteRune(r)
+			i++
+			bound.WriteRune(runes[i])
+			continue
+		case r == '\'':
+			inString = !inString
+		case !inString && r == ':' && i+1 < len(runes) && isParameterStart(runes[i+1]):
+			end := i + 1
+			for end < len(runes) && isParameterPart(runes[end]) {
+				end++
+			}
+			name := string(runes[i+1 : end])
+			value, ok := params[name]
+			if !ok {
+				return "", fmt.Errorf("no value for query parameter :%s", name)
+			}
+			literal, err := soqlLiteral(value)
+			if err != nil {
+				return "", fmt.Errorf("query parameter :%s: %w", name, err)
+			}
+			bound.WriteString(literal)
+			i = end - 1
+			continue
+		}
+		bound.WriteRune(r)
+	}
+	if inString {
+		return "", errors.New("query has an unterminated string literal")
+	}
+	return bound.String(), nil
+}
+
+func isParameterStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isParameterPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// soqlLiteral formats a value as a SOQL literal
+func soqlLiteral(value any) (string, error) {
+	if d, ok := value.(DateLiteral); ok {
+		return d.soqlLiteral()
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.UTC().Format(soqlDateTimeFormat), nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "null", nil
+		}
+		v = v.Elem()
+		if d, ok := v.Interface().(DateLiteral); ok {
+			return d.soqlLiteral()
+		}
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.UTC().Format(soqlDateTimeFormat), nil
+		}
+	}
+	if !v.IsValid() {
+		return "null", nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return "'" + escapeSoqlString(v.String()) + "'", nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		// SOQL has no literal for NaN or infinity
+		if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+			return "", fmt.Errorf("unsupported number value %v", v.Float())
+		}
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice, reflect.Array:
+		// bytes would otherwise be bound as a list of numbers
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return "", fmt.Errorf("unsupported value type %T, convert it to a string", value)
+		}
+		if v.Len() == 0 {
+			return "", errors.New("list values cannot be empty")
+		}
+		literals := make([]string, v.Len())
+		for i := range literals {
+			element := v.Index(i)
+			for element.Kind() == reflect.Interface || element.Kind() == reflect.Pointer {
+				if element.IsNil() {
+					break
+				}
+				element = element.Elem()
+			}
+			if element.Kind() == reflect.Slice || element.Kind() == reflect.Array {
+				return "", errors.New("list values cannot contain lists")
+			}
+			literal, err := soqlLiteral(element.Interface())
+			if err != nil {
+				return "", err
+			}
+			literals[i] = literal
+		}
+		return "(" + strings.Join(literals, ", ") + ")", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+}
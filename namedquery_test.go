@@ -0,0 +1,179 @@
+package salesforce
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_bindNamedParameters(t *testing.T) {
+	type status string
+	since := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	email := "o'brien@example.com"
+	var missing *string
+
+	tests := []struct {
+		name    string
+		query   string
+		params  map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "types",
+			query: "SELECT Id FROM Contact WHERE Email = :email AND CreatedDate > :since AND NumberOfEmployees >= :size AND Score__c < :score AND IsActive__c = :active",
+			params: map[string]any{
+				"email":  &email,
+				"since":  since,
+				"size":   10,
+				"score":  4.5,
+				"active": true,
+			},
+			want: `SELECT Id FROM Contact WHERE Email = 'o\'brien@example.com' AND CreatedDate > 2024-03-01T14:30:00Z AND NumberOfEmployees >= 10 AND Score__c < 4.5 AND IsActive__c = true`,
+		},
+		{
+			name:   "float32",
+			query:  "SELECT Id FROM Account WHERE Rating__c = :rating AND Score__c IN :scores",
+			params: map[string]any{"rating": float32(0.1), "scores": []float32{1.5, 0.3}},
+			want:   "SELECT Id FROM Account WHERE Rating__c = 0.1 AND Score__c IN (1.5, 0.3)",
+		},
+		{
+			name:  "lists",
+			query: "SELECT Id FROM Account WHERE Id IN :ids AND Status__c IN :statuses",
+			params: map[string]any{
+				"ids":      []string{"001A", "001B"},
+				"statuses": []any{status("Open"), nil},
+			},
+			want: "SELECT Id FROM Account WHERE Id IN ('001A', '001B') AND Status__c IN ('Open', null)",
+		},
+		{
+			name:   "null_and_reused",
+			query:  "SELECT Id FROM Account WHERE ParentId = :parent OR (Name = :name AND Site = :name)",
+			params: map[string]any{"parent": missing, "name": "Acme"},
+			want:   "SELECT Id FROM Account WHERE ParentId = null OR (Name = 'Acme' AND Site = 'Acme')",
+		},
+		{
+			name:   "colons_left_unchanged",
+			query:  `SELECT Id FROM Case WHERE Subject = 'time: \':now\'' AND CreatedDate = LAST_N_DAYS:30 AND OwnerId = :owner`,
+			params: map[string]any{"owner": "005A"},
+			want:   `SELECT Id FROM Case WHERE Subject = 'time: \':now\'' AND CreatedDate = LAST_N_DAYS:30 AND OwnerId = '005A'`,
+		},
+		{
+			name:  "date_literals",
+			query: "SELECT Id FROM Opportunity WHERE CloseDate = :quarter AND CreatedDate = :recent",
+			params: map[string]any{
+				"quarter": DateThisFiscalQuarter,
+				"recent":  LastN(DateUnitDays, 30),
+			},
+			want: "SELECT Id FROM Opportunity WHERE CloseDate = THIS_FISCAL_QUARTER AND CreatedDate = LAST_N_DAYS:30",
+		},
+		{
+			name:    "invalid_date_literal",
+			query:   "SELECT Id FROM Opportunity WHERE CloseDate = :quarter",
+			params:  map[string]any{"quarter": DateLiteral("TODAY OR Id != null")},
+			wantErr: true,
+		},
+		{
+			name:    "missing_parameter",
+			query:   "SELECT Id FROM Account WHERE Name = :name",
+			params:  map[string]any{},
+			wantErr: true,
+		},
+		{
+			name:    "empty_list",
+			query:   "SELECT Id FROM Account WHERE Id IN :ids",
+			params:  map[string]any{"ids": []string{}},
+			wantErr: true,
+		},
+		{
+			name:    "nested_list",
+			query:   "SELECT Id FROM Account WHERE Id IN :ids",
+			params:  map[string]any{"ids": [][]string{{"001A"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported_type",
+			query:   "SELECT Id FROM Account WHERE Name = :name",
+			params:  map[string]any{"name": map[string]string{}},
+			wantErr: true,
+		},
+		{
+			name:    "nan",
+			query:   "SELECT Id FROM Account WHERE AnnualRevenue = :revenue",
+			params:  map[string]any{"revenue": math.NaN()},
+			wantErr: true,
+		},
+		{
+			name:    "infinity",
+			query:   "SELECT Id FROM Account WHERE AnnualRevenue < :revenue",
+			params:  map[string]any{"revenue": float32(math.Inf(1))},
+			wantErr: true,
+		},
+		{
+			name:    "infinity_in_list",
+			query:   "SELECT Id FROM Account WHERE AnnualRevenue IN :revenues",
+			params:  map[string]any{"revenues": []float64{1, math.Inf(-1)}},
+			wantErr: true,
+		},
+		{
+			name:    "bytes",
+			query:   "SELECT Id FROM Account WHERE Name = :name",
+			params:  map[string]any{"name": []byte("Acme")},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated_string",
+			query:   "SELECT Id FROM Account WHERE Name = 'Acme",
+			params:  map[string]any{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bindNamedParameters(tt.query, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bindNamedParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("bindNamedParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryNamed(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "001000000000001AAA", "Name": "Acme"}},
+	}
+	server, sfAuth, req := setupTestServerWithCapture(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	accounts := []account{}
+	err := sf.QueryNamed(
+		"SELECT Id, Name FROM Account WHERE Name = :name",
+		map[string]any{"name": "Acme"},
+		&accounts,
+	)
+	if err != nil {
+		t.Fatalf("QueryNamed() error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Name != "Acme" {
+		t.Errorf("QueryNamed() = %v", accounts)
+	}
+	query := (*req).URL.Query().Get("q")
+	if query != "SELECT Id, Name FROM Account WHERE Name = 'Acme'" {
+		t.Errorf("QueryNamed() query = %v", query)
+	}
+
+	if err := sf.QueryNamed("SELECT Id FROM Account WHERE Name = :name", nil, &accounts); err == nil {
+		t.Errorf("QueryNamed() expected error for missing parameter")
+	}
+}
@@ -0,0 +1,194 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// sObjectNamesCacheKey is the describe cache key of the names of the org's sObjects
+const sObjectNamesCacheKey = "/sobjects/names"
+
+var soqlFieldNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// UnknownNameError is returned when an sObject or field name is not in the org's describe, with the
+// closest name as a suggestion, instead of the NOT_FOUND or INVALID_FIELD error Salesforce returns
+type UnknownNameError struct {
+	SObjectName string // sObject of an unknown field, or the unknown sObject
+	Field       string // unknown field, empty if the sObject is unknown
+	Suggestion  string // closest known name, empty if none is close
+}
+
+func (e *UnknownNameError) Error() string {
+	message := "unknown sObject " + e.SObjectName
+	if e.Field != "" {
+		message = fmt.Sprintf("unknown field %s of %s", e.Field, e.SObjectName)
+	}
+	if e.Suggestion != "" {
+		message += ", did you mean " + e.Suggestion + "?"
+	}
+	return message
+}
+
+// ValidateNames returns an UnknownNameError if an sObject or any of its fields is not in the org's
+// describe, suggesting the closest name, such as Region__c for Region. Fields may be relationship names,
+// such as Account. The describes are cached, see WithDescribeCacheTTL.
+func (sf *Salesforce) ValidateNames(sObjectName string, fields ...string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	return validateNames(sf, sObjectName, fields)
+}
+
+func validateNames(sf *Salesforce, sObjectName string, fields []string) error {
+	names, err := sObjectNames(sf)
+	if err != nil {
+		return err
+	}
+	name, ok := names[strings.ToLower(sObjectName)]
+	if !ok {
+		known := make([]string, 0, len(names))
+		for _, sObject := range names {
+			known = append(known, sObject)
+		}
+		return &UnknownNameError{
+			SObjectName: sObjectName,
+			Suggestion:  closestName(sObjectName, known),
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	describe, err := describeSObject(sf, name)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, field := range fields {
+		if field == "attributes" || describeHasName(describe, field) {
+			continue
+		}
+		known := make([]string, 0, len(describe.Fields))
+		for _, describeField := range describe.Fields {
+			known = append(known, describeField.Name)
+			if describeField.RelationshipName != "" {
+				known = append(known, describeField.RelationshipName)
+			}
+		}
+		errs = append(errs, &UnknownNameError{
+			SObjectName: sObjectName,
+			Field:       field,
+			Suggestion:  closestName(field, known),
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// validateRecordNames validates the sObject name and the fields of records before DML
+func validateRecordNames(sf *Salesforce, sObjectName string, records []map[string]any) error {
+	seen := map[string]bool{}
+	var fields []string
+	for _, record := range records {
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	return validateNames(sf, sObjectName, fields)
+}
+
+// validateQueryNames validates the sObject of the FROM clause of a query and the fields it selects.
+// Only plain field names are validated, not relationship paths, functions, or subqueries.
+func validateQueryNames(sf *Salesforce, query string) error {
+	selectIndex := soqlClauseIndex(query, "SELECT")
+	from := soqlClauseIndex(query, "FROM")
+	if selectIndex < 0 || from < selectIndex {
+		return errors.New("query must have SELECT and FROM clauses")
+	}
+	sObject := strings.Fields(query[from+len("FROM"):])
+	if len(sObject) == 0 {
+		return errors.New("query must have a FROM clause")
+	}
+
+	var fields []string
+	for _, item := range splitSoqlList(query[selectIndex+len("SELECT") : from]) {
+		if soqlFieldNamePattern.MatchString(item) {
+			fields = append(fields, item)
+		}
+	}
+	return validateNames(sf, sObject[0], fields)
+}
+
+// sObjectNames returns the names of the org's sObjects keyed by their lowercase names
+func sObjectNames(sf *Salesforce) (map[string]string, error) {
+	if cached, ok := sf.config.describeCache.get(sObjectNamesCacheKey); ok {
+		return cached.(map[string]string), nil
+	}
+	describe, err := getGlobalDescribe(sf)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(describe.SObjects))
+	for _, sObject := range describe.SObjects {
+		names[strings.ToLower(sObject.Name)] = sObject.Name
+	}
+	sf.config.describeCache.set(sObjectNamesCacheKey, names)
+	return names, nil
+}
+
+func describeHasName(describe *SObjectDescribe, name string) bool {
+	for _, field := range describe.Fields {
+		if strings.EqualFold(field.Name, name) || strings.EqualFold(field.RelationshipName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestName returns the known name closest to an unknown name by edit distance, ignoring case and
+// the suffix of custom names such as __c, or an empty string if none is close enough to be a typo
+func closestName(name string, known []string) string {
+	name = strings.ToLower(name)
+	maxDistance := max(2, len(name)/3)
+	closest := ""
+	closestDistance := maxDistance + 1
+	for _, candidate := range known {
+		lower := strings.ToLower(candidate)
+		distance := editDistance(name, lower)
+		if suffix := strings.LastIndex(lower, "__"); suffix > 0 {
+			distance = min(distance, editDistance(name, lower[:suffix]))
+		}
+		if distance < closestDistance ||
+			(distance == closestDistance && closest != "" && candidate < closest) {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
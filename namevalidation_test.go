@@ -0,0 +1,236 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupNameValidationServer(requests *[]string) *httptest.Server {
+	describe := SObjectDescribe{Name: "Contact", Fields: []DescribeField{
+		{Name: "Id", Type: "id"},
+		{Name: "LastName", Type: "string"},
+		{Name: "Region__c", Type: "picklist"},
+		{
+			Name:             "AccountId",
+			Type:             "reference",
+			ReferenceTo:      []string{"Account"},
+			RelationshipName: "Account",
+		},
+	}}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sobjects/"):
+			_, _ = w.Write([]byte(`{"sobjects":[` +
+				`{"name":"Account","keyPrefix":"001"},` +
+				`{"name":"Contact","keyPrefix":"003"},` +
+				`{"name":"Invoice__c","keyPrefix":"a01"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/sobjects/Contact/describe"):
+			_ = json.NewEncoder(w).Encode(describe)
+		case strings.Contains(r.URL.Path, "/query"):
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"003000000000001AAA","success":true,"errors":[]}`))
+		}
+	}))
+}
+
+func TestSalesforce_ValidateNames(t *testing.T) {
+	requests := []string{}
+	server := setupNameValidationServer(&requests)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name        string
+		sObjectName string
+		fields      []string
+		wantErrs    []string
+	}{
+		{
+			name:        "valid_names",
+			sObjectName: "contact",
+			fields:      []string{"LastName", "region__c", "Account", "attributes"},
+		},
+		{
+			name:        "sObject_typo",
+			sObjectName: "Contcat",
+			wantErrs:    []string{"unknown sObject Contcat, did you mean Contact?"},
+		},
+		{
+			name:        "custom_sObject_without_suffix",
+			sObjectName: "Invoice",
+			wantErrs:    []string{"unknown sObject Invoice, did you mean Invoice__c?"},
+		},
+		{
+			name:        "no_suggestion",
+			sObjectName: "Opportunity",
+			wantErrs:    []string{"unknown sObject Opportunity"},
+		},
+		{
+			name:        "field_typos",
+			sObjectName: "Contact",
+			fields:      []string{"LastNam", "Region", "Birthdate"},
+			wantErrs: []string{
+				"unknown field LastNam of Contact, did you mean LastName?",
+				"unknown field Region of Contact, did you mean Region__c?",
+				"unknown field Birthdate of Contact",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sf.ValidateNames(tt.sObjectName, tt.fields...)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateNames() error = %v", err)
+				}
+				return
+			}
+			var nameErr *UnknownNameError
+			if !errors.As(err, &nameErr) {
+				t.Fatalf("ValidateNames() error = %v, want UnknownNameError", err)
+			}
+			if err.Error() != strings.Join(tt.wantErrs, "\n") {
+				t.Errorf(
+					"ValidateNames() error = %q, want %q",
+					err,
+					strings.Join(tt.wantErrs, "\n"),
+				)
+			}
+		})
+	}
+
+	globalDescribes := 0
+	for _, request := range requests {
+		if strings.HasSuffix(request, "/sobjects/") {
+			globalDescribes++
+		}
+	}
+	if globalDescribes != 1 {
+		t.Errorf("global describe requested %d times, want 1", globalDescribes)
+	}
+}
+
+func Test_nameValidation(t *testing.T) {
+	requests := []string{}
+	server := setupNameValidationServer(&requests)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.nameValidation = true
+
+	type contact struct {
+		LastName  string
+		Regoin__c string
+	}
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{
+			name: "valid_insert",
+			run: func() error {
+				_, err := sf.InsertOne("Contact", map[string]any{
+					"LastName":               "Smith",
+					"Account.External_Id__c": "A-1",
+				})
+				return err
+			},
+		},
+		{
+			name: "insert_field_typo",
+			run: func() error {
+				_, err := sf.InsertOne(
+					"Contact",
+					map[string]any{"LastName": "Smith", "Regon__c": "EMEA"},
+				)
+				return err
+			},
+			wantErr: "unknown field Regon__c of Contact, did you mean Region__c?",
+		},
+		{
+			name: "struct_collection_field_typo",
+			run: func() error {
+				_, err := sf.InsertCollection("Contact", []contact{{LastName: "Smith"}}, 200)
+				return err
+			},
+			wantErr: "unknown field Regoin__c of Contact, did you mean Region__c?",
+		},
+		{
+			name: "valid_query",
+			run: func() error {
+				records := []map[string]any{}
+				return sf.Query(
+					"SELECT Id, LastName, Account.Name, COUNT(Id), (SELECT Id FROM Cases) FROM Contact",
+					&records,
+				)
+			},
+		},
+		{
+			name: "query_sObject_typo",
+			run: func() error {
+				records := []map[string]any{}
+				return sf.Query("SELECT Id FROM Contacts WHERE LastName = 'Smith'", &records)
+			},
+			wantErr: "unknown sObject Contacts, did you mean Contact?",
+		},
+		{
+			name: "query_field_typo",
+			run: func() error {
+				records := []map[string]any{}
+				return sf.Query("SELECT Id, Lastname, Region FROM Contact", &records)
+			},
+			wantErr: "unknown field Region of Contact, did you mean Region__c?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = requests[:0]
+			err := tt.run()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			for _, request := range requests {
+				if !strings.HasSuffix(request, "/sobjects/") &&
+					!strings.HasSuffix(request, "/describe") {
+					t.Errorf("request sent after failed validation: %s", request)
+				}
+			}
+		})
+	}
+}
+
+func Test_closestName(t *testing.T) {
+	known := []string{"Name", "Region__c", "ns__Amount__c", "AccountId", "Account"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "region", want: "Region__c"},
+		{name: "Regino__c", want: "Region__c"},
+		{name: "ns__Amount", want: "ns__Amount__c"},
+		{name: "Acount", want: "Account"},
+		{name: "AccountID", want: "AccountId"},
+		{name: "Description", want: ""},
+		{name: "Na", want: "Name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestName(tt.name, known); got != tt.want {
+				t.Errorf("closestName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
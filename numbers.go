@@ -0,0 +1,99 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// NumberDecoding is how numbers of query results are decoded when they are not decoded into a struct
+// field of a specific type, such as into a map, Record, or AggregateResult
+type NumberDecoding int
+
+const (
+	// NumberFloat64 decodes numbers as float64, which loses precision past 15 to 17 significant digits
+	NumberFloat64 NumberDecoding = iota
+	// NumberJSONNumber decodes numbers as json.Number, preserving every digit
+	NumberJSONNumber
+	// NumberInt64 decodes whole numbers that fit as int64 and other numbers as float64
+	NumberInt64
+)
+
+// unmarshalJSON decodes a response body like json.Unmarshal, with the codec set with WithJSONCodec,
+// decoding numbers as configured by WithNumberDecoding
+func unmarshalJSON(config *configuration, data []byte, v any) error {
+	switch {
+	case config.jsonCodec != nil:
+		if err := config.jsonCodec.Unmarshal(data, v); err != nil {
+			return err
+		}
+	case config.numberDecoding == NumberFloat64:
+		return json.Unmarshal(data, v)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+	}
+	if config.numberDecoding == NumberInt64 {
+		if queryResp, ok := v.(*queryResponse); ok {
+			for _, record := range queryResp.Records {
+				convertJSONNumbers(record)
+			}
+		}
+	}
+	return nil
+}
+
+// convertJSONNumbers replaces the json.Number values of a record and its related records with
+// int64 values, or float64 values if they are not whole numbers that fit in an int64
+func convertJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if !strings.ContainsAny(v.String(), ".eE") {
+			if i, err := v.Int64(); err == nil {
+				return i
+			}
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, field := range v {
+			v[key] = convertJSONNumbers(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+	}
+	return value
+}
+
+// numberValue returns a number decoded with any NumberDecoding as a float64
+func numberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatNumber formats a number decoded with any NumberDecoding without losing digits
+func formatNumber(value any) (string, bool) {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,104 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_unmarshalJSON(t *testing.T) {
+	body := []byte(`{"totalSize":1,"done":true,"records":[{
+		"Amount__c": 123456789012345678,
+		"Rate__c": 1.5,
+		"Account": {"NumberOfEmployees": 42}
+	}]}`)
+	tests := []struct {
+		name     string
+		decoding NumberDecoding
+		want     map[string]any
+	}{
+		{
+			name:     "float64",
+			decoding: NumberFloat64,
+			want: map[string]any{
+				"Amount__c": float64(123456789012345678),
+				"Rate__c":   1.5,
+				"Account":   map[string]any{"NumberOfEmployees": float64(42)},
+			},
+		},
+		{
+			name:     "json_number",
+			decoding: NumberJSONNumber,
+			want: map[string]any{
+				"Amount__c": json.Number("123456789012345678"),
+				"Rate__c":   json.Number("1.5"),
+				"Account":   map[string]any{"NumberOfEmployees": json.Number("42")},
+			},
+		},
+		{
+			name:     "int64",
+			decoding: NumberInt64,
+			want: map[string]any{
+				"Amount__c": int64(123456789012345678),
+				"Rate__c":   1.5,
+				"Account":   map[string]any{"NumberOfEmployees": int64(42)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configuration{}
+			config.setDefaults()
+			config.numberDecoding = tt.decoding
+
+			queryResp := &queryResponse{}
+			if err := unmarshalJSON(config, body, queryResp); err != nil {
+				t.Fatalf("unmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(queryResp.Records[0], tt.want) {
+				t.Errorf("unmarshalJSON() = %v, want %v", queryResp.Records[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestWithNumberDecoding_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{
+			"attributes": {"type": "Account"},
+			"Amount__c": 123456789012345678,
+			"Rate__c": 1.5
+		}]}`))
+	}))
+	defer server.Close()
+
+	for _, decoding := range []NumberDecoding{NumberJSONNumber, NumberInt64} {
+		sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+		sf.config.numberDecoding = decoding
+
+		records := []Record{}
+		if err := sf.Query("SELECT Amount__c, Rate__c FROM Account", &records); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if got := records[0].GetString("Amount__c"); got != "123456789012345678" {
+			t.Errorf("GetString() = %v, want 123456789012345678", got)
+		}
+		if got := records[0].GetFloat("Rate__c"); got != 1.5 {
+			t.Errorf("GetFloat() = %v, want 1.5", got)
+		}
+
+		type account struct {
+			Amount__c int64
+			Rate__c   float64
+		}
+		accounts := []account{}
+		if err := sf.Query("SELECT Amount__c, Rate__c FROM Account", &accounts); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if accounts[0].Amount__c != 123456789012345678 || accounts[0].Rate__c != 1.5 {
+			t.Errorf("Query() = %v", accounts)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Routing types of Omni-Channel work
+const (
+	RoutingTypeQueueBased  = "QueueBased"
+	RoutingTypeSkillsBased = "SkillsBased"
+	RoutingTypeExternal    = "ExternalRouting"
+)
+
+// PendingServiceRouting is a work item to route with Omni-Channel, created with CreatePendingServiceRouting
+type PendingServiceRouting struct {
+	WorkItemId         string // record to route, such as a case or chat transcript
+	ServiceChannelId   string
+	RoutingType        string // such as RoutingTypeSkillsBased or RoutingTypeExternal
+	RoutingModel       string // MostAvailable or LeastActive
+	RoutingPriority    int
+	CapacityWeight     float64 // capacity used by the work, or CapacityPercentage
+	CapacityPercentage float64
+	QueueId            string // queue the work is routed through for queue based routing
+	PushTimeout        int    // seconds an agent has to accept the work
+	PreferredUserId    string
+	Skills             []SkillRequirement // skills for skills based routing
+	Fields             map[string]any     // other fields of the PendingServiceRouting record
+}
+
+// SkillRequirement is a skill required to be routed a work item
+type SkillRequirement struct {
+	SkillId           string
+	SkillLevel        int
+	IsAdditionalSkill bool // dropped after the DropAdditionalSkillsTimeout of the routing
+	SkillPriority     int  // priority of an additional skill
+}
+
+// AgentWork assigns a work item directly to an agent, such as from an external routing engine
+type AgentWork struct {
+	UserId                  string
+	WorkItemId              string
+	ServiceChannelId        string
+	PendingServiceRoutingId string // routing of the work item when it was created with one
+	CapacityWeight          float64
+	CapacityPercentage      float64
+	Fields                  map[string]any // other fields of the AgentWork record
+}
+
+// ServiceChannel is an Omni-Channel service channel
+type ServiceChannel struct {
+	Id            string
+	DeveloperName string
+	MasterLabel   string
+	RelatedEntity string // sObject routed by the channel, such as Case
+}
+
+// AgentPresence is the current presence of an agent that is online for a service channel
+type AgentPresence struct {
+	UserId             string
+	StatusId           string
+	StatusName         string // developer name of the presence status
+	IsAway             bool
+	ConfiguredCapacity float64
+	StatusStartDate    string
+}
+
+type userServicePresence struct {
+	UserId                  string
+	ServicePresenceStatusId string
+	ServicePresenceStatus   struct {
+		DeveloperName string
+	}
+	IsAway             bool
+	ConfiguredCapacity float64
+	StatusStartDate    string
+}
+
+// CreatePendingServiceRouting creates a PendingServiceRouting record to route a work item with
+// Omni-Channel. Skills based routing needs the skill requirements to exist before the routing is ready,
+// so the routing, its skill requirements, and marking it ready are sent in one all or none composite
+// request. If Salesforce rejects the routing, its errors are returned as a RecordError.
+func (sf *Salesforce) CreatePendingServiceRouting(routing PendingServiceRouting) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if routing.WorkItemId == "" || routing.ServiceChannelId == "" || routing.RoutingType == "" {
+		return "", errors.New("work item id, service channel id, and routing type are required")
+	}
+	if len(routing.Skills) > 0 && routing.RoutingType != RoutingTypeSkillsBased {
+		return "", errors.New("skills require skills based routing")
+	}
+	if len(routing.Skills) > compositeSubRequestsMax-2 {
+		return "", fmt.Errorf("a routing can have at most %d skills", compositeSubRequestsMax-2)
+	}
+
+	record := map[string]any{}
+	for name, value := range routing.Fields {
+		record[name] = value
+	}
+	record["WorkItemId"] = routing.WorkItemId
+	record["ServiceChannelId"] = routing.ServiceChannelId
+	record["RoutingType"] = routing.RoutingType
+	record["IsReadyForRouting"] = len(routing.Skills) == 0
+	setNonEmptyField(record, "RoutingModel", routing.RoutingModel)
+	setNonEmptyField(record, "QueueId", routing.QueueId)
+	setNonEmptyField(record, "PreferredUserId", routing.PreferredUserId)
+	setNonZeroField(record, "RoutingPriority", float64(routing.RoutingPriority))
+	setNonZeroField(record, "CapacityWeight", routing.CapacityWeight)
+	setNonZeroField(record, "CapacityPercentage", routing.CapacityPercentage)
+	setNonZeroField(record, "PushTimeout", float64(routing.PushTimeout))
+
+	if len(routing.Skills) == 0 {
+		result, err := sf.InsertOne("PendingServiceRouting", record)
+		if err != nil {
+			return "", err
+		}
+		return result.Id, nil
+	}
+
+	builder := sf.NewCompositeBuilder(true)
+	result := SalesforceResult{}
+	err := builder.Add(
+		http.MethodPost,
+		"/sobjects/PendingServiceRouting",
+		"routing",
+		record,
+		&result,
+	)
+	if err != nil {
+		return "", err
+	}
+	for i, skill := range routing.Skills {
+		skillRecord := map[string]any{
+			"RelatedRecordId":   "@{routing.id}",
+			"SkillId":           skill.SkillId,
+			"SkillLevel":        skill.SkillLevel,
+			"IsAdditionalSkill": skill.IsAdditionalSkill,
+		}
+		setNonZeroField(skillRecord, "SkillPriority", float64(skill.SkillPriority))
+		err = builder.Add(
+			http.MethodPost,
+			"/sobjects/SkillRequirement",
+			fmt.Sprintf("skill%d", i),
+			skillRecord,
+			nil,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = builder.Add(
+		http.MethodPatch,
+		"/sobjects/PendingServiceRouting/@{routing.id}",
+		"ready",
+		map[string]any{"IsReadyForRouting": true},
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	responses, err := builder.Execute()
+	if err != nil {
+		return "", err
+	}
+	for i, response := range responses {
+		recordErr := RecordError{Index: i, Errors: response.Errors}
+		if !response.Success() && !recordErr.IsRollback() {
+			return "", recordErr
+		}
+	}
+	return result.Id, nil
+}
+
+// CreateAgentWork assigns a work item to an agent by creating an AgentWork record
+func (sf *Salesforce) CreateAgentWork(work AgentWork) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if work.UserId == "" || work.WorkItemId == "" || work.ServiceChannelId == "" {
+		return "", errors.New("user id, work item id, and service channel id are required")
+	}
+	record := map[string]any{}
+	for name, value := range work.Fields {
+		record[name] = value
+	}
+	record["UserId"] = work.UserId
+	record["WorkItemId"] = work.WorkItemId
+	record["ServiceChannelId"] = work.ServiceChannelId
+	setNonEmptyField(record, "PendingServiceRoutingId", work.PendingServiceRoutingId)
+	setNonZeroField(record, "CapacityWeight", work.CapacityWeight)
+	setNonZeroField(record, "CapacityPercentage", work.CapacityPercentage)
+	result, err := sf.InsertOne("AgentWork", record)
+	if err != nil {
+		return "", err
+	}
+	return result.Id, nil
+}
+
+// GetServiceChannels returns the Omni-Channel service channels of the org
+func (sf *Salesforce) GetServiceChannels() ([]ServiceChannel, error) {
+	channels := []ServiceChannel{}
+	err := sf.Query(
+		"SELECT Id, DeveloperName, MasterLabel, RelatedEntity FROM ServiceChannel ORDER BY DeveloperName",
+		&channels,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// GetAgentPresence returns the agents that are currently online with a presence status of a service
+// channel, so that an external routing engine can choose an agent to assign work to
+func (sf *Salesforce) GetAgentPresence(serviceChannelId string) ([]AgentPresence, error) {
+	if serviceChannelId == "" {
+		return nil, errors.New("service channel id is required")
+	}
+	presences := []userServicePresence{}
+	err := sf.QueryNamed(
+		"SELECT UserId, ServicePresenceStatusId, ServicePresenceStatus.DeveloperName, IsAway, "+
+			"ConfiguredCapacity, StatusStartDate FROM UserServicePresence "+
+			"WHERE IsCurrentState = true AND ServicePresenceStatusId IN "+
+			"(SELECT ServicePresenceStatusId FROM ServiceChannelStatus WHERE ServiceChannelId = :channelId)",
+		map[string]any{"channelId": serviceChannelId},
+		&presences,
+	)
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]AgentPresence, len(presences))
+	for i, presence := range presences {
+		agents[i] = AgentPresence{
+			UserId:             presence.UserId,
+			StatusId:           presence.ServicePresenceStatusId,
+			StatusName:         presence.ServicePresenceStatus.DeveloperName,
+			IsAway:             presence.IsAway,
+			ConfiguredCapacity: presence.ConfiguredCapacity,
+			StatusStartDate:    presence.StatusStartDate,
+		}
+	}
+	return agents, nil
+}
+
+func setNonZeroField(record map[string]any, name string, value float64) {
+	if value != 0 {
+		record[name] = value
+	}
+}
@@ -0,0 +1,189 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_CreatePendingServiceRouting(t *testing.T) {
+	var sent compositeBuilderRequest
+	compositeResponse := `{"compositeResponse":[
+		{"body":{"id":"0JR000000000001AAA","success":true,"errors":[]},"httpStatusCode":201,"referenceId":"routing"},
+		{"body":{"id":"0Sr000000000001AAA","success":true,"errors":[]},"httpStatusCode":201,"referenceId":"skill0"},
+		{"body":null,"httpStatusCode":204,"referenceId":"ready"}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(compositeResponse))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	routing := PendingServiceRouting{
+		WorkItemId:       "500000000000001AAA",
+		ServiceChannelId: "0N9000000000001AAA",
+		RoutingType:      RoutingTypeSkillsBased,
+		RoutingModel:     "MostAvailable",
+		RoutingPriority:  1,
+		CapacityWeight:   1,
+		Skills:           []SkillRequirement{{SkillId: "0C5000000000001AAA", SkillLevel: 5}},
+	}
+	id, err := sf.CreatePendingServiceRouting(routing)
+	if err != nil || id != "0JR000000000001AAA" {
+		t.Fatalf("CreatePendingServiceRouting() = %v, %v", id, err)
+	}
+	subRequests := sent.CompositeRequest
+	if len(subRequests) != 3 {
+		t.Fatalf("subrequests = %+v", subRequests)
+	}
+	wantRouting := map[string]any{
+		"WorkItemId":        "500000000000001AAA",
+		"ServiceChannelId":  "0N9000000000001AAA",
+		"RoutingType":       "SkillsBased",
+		"RoutingModel":      "MostAvailable",
+		"RoutingPriority":   float64(1),
+		"CapacityWeight":    float64(1),
+		"IsReadyForRouting": false,
+	}
+	if !reflect.DeepEqual(subRequests[0].Body, wantRouting) {
+		t.Errorf("routing = %v, want %v", subRequests[0].Body, wantRouting)
+	}
+	wantSkill := map[string]any{
+		"RelatedRecordId":   "@{routing.id}",
+		"SkillId":           "0C5000000000001AAA",
+		"SkillLevel":        float64(5),
+		"IsAdditionalSkill": false,
+	}
+	if !reflect.DeepEqual(subRequests[1].Body, wantSkill) {
+		t.Errorf("skill = %v, want %v", subRequests[1].Body, wantSkill)
+	}
+	if subRequests[2].Method != http.MethodPatch ||
+		!strings.HasSuffix(subRequests[2].Url, "/sobjects/PendingServiceRouting/@{routing.id}") {
+		t.Errorf("ready subrequest = %+v", subRequests[2])
+	}
+
+	compositeResponse = `{"compositeResponse":[
+		{"body":[{"errorCode":"PROCESSING_HALTED","message":"rolled back"}],"httpStatusCode":400,"referenceId":"routing"},
+		{"body":[{"errorCode":"INVALID_CROSS_REFERENCE_KEY","message":"invalid skill"}],"httpStatusCode":400,"referenceId":"skill0"},
+		{"body":[{"errorCode":"PROCESSING_HALTED","message":"rolled back"}],"httpStatusCode":400,"referenceId":"ready"}
+	]}`
+	_, err = sf.CreatePendingServiceRouting(routing)
+	var recordErr RecordError
+	if !errors.As(err, &recordErr) || recordErr.Index != 1 {
+		t.Errorf("CreatePendingServiceRouting() error = %v, want the skill RecordError", err)
+	}
+
+	routing.RoutingType = RoutingTypeExternal
+	if _, err := sf.CreatePendingServiceRouting(routing); err == nil {
+		t.Error(
+			"CreatePendingServiceRouting() expected an error for skills without skills based routing",
+		)
+	}
+}
+
+func TestSalesforce_CreateAgentWork(t *testing.T) {
+	var path string
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"0Bz000000000001AAA","success":true,"errors":[]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	id, err := sf.CreateAgentWork(AgentWork{
+		UserId:           "005000000000001AAA",
+		WorkItemId:       "500000000000001AAA",
+		ServiceChannelId: "0N9000000000001AAA",
+		CapacityWeight:   2,
+	})
+	if err != nil || id != "0Bz000000000001AAA" {
+		t.Fatalf("CreateAgentWork() = %v, %v", id, err)
+	}
+	if path != "/services/data/"+apiVersion+"/sobjects/AgentWork" ||
+		sent["UserId"] != "005000000000001AAA" || sent["CapacityWeight"] != float64(2) {
+		t.Errorf("path = %s, body = %v", path, sent)
+	}
+	if _, err := sf.CreateAgentWork(AgentWork{UserId: "005000000000001AAA"}); err == nil {
+		t.Error("CreateAgentWork() expected an error without a work item")
+	}
+}
+
+func TestSalesforce_GetAgentPresence(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"UserId":                  "005000000000001AAA",
+			"ServicePresenceStatusId": "0N5000000000001AAA",
+			"ServicePresenceStatus":   map[string]any{"DeveloperName": "Available"},
+			"IsAway":                  false,
+			"ConfiguredCapacity":      5,
+			"StatusStartDate":         "2024-03-08T09:00:00.000+0000",
+		}},
+	}
+	server, auth, captured := setupTestServerWithCapture(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	got, err := sf.GetAgentPresence("0N9000000000001AAA")
+	if err != nil {
+		t.Fatalf("GetAgentPresence() error = %v", err)
+	}
+	want := []AgentPresence{{
+		UserId:             "005000000000001AAA",
+		StatusId:           "0N5000000000001AAA",
+		StatusName:         "Available",
+		ConfiguredCapacity: 5,
+		StatusStartDate:    "2024-03-08T09:00:00.000+0000",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAgentPresence() = %+v, want %+v", got, want)
+	}
+	if query := (*captured).URL.Query().Get("q"); !strings.Contains(
+		query,
+		"WHERE ServiceChannelId = '0N9000000000001AAA'",
+	) {
+		t.Errorf("query = %s", query)
+	}
+	if _, err := sf.GetAgentPresence(""); err == nil {
+		t.Error("GetAgentPresence() expected an error without a service channel id")
+	}
+}
+
+func TestSalesforce_GetServiceChannels(t *testing.T) {
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"Id":            "0N9000000000001AAA",
+			"DeveloperName": "Cases",
+			"MasterLabel":   "Cases",
+			"RelatedEntity": "Case",
+		}},
+	}
+	server, auth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	got, err := sf.GetServiceChannels()
+	want := []ServiceChannel{{
+		Id:            "0N9000000000001AAA",
+		DeveloperName: "Cases",
+		MasterLabel:   "Cases",
+		RelatedEntity: "Case",
+	}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetServiceChannels() = %+v, %v, want %+v", got, err, want)
+	}
+}
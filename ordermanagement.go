@@ -0,0 +1,213 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+const (
+	orderSummariesUri    = "/commerce/order-management/order-summaries"
+	fulfillmentOrdersUri = "/commerce/fulfillment/fulfillment-orders"
+)
+
+// Life cycle types of an order summary
+const (
+	OrderLifeCycleManaged   = "MANAGED"   // changed only through Order Management actions
+	OrderLifeCycleUnmanaged = "UNMANAGED" // changed directly by an external system
+)
+
+// FulfillmentOrderItem is a quantity of an order item summary to fulfill
+type FulfillmentOrderItem struct {
+	OrderItemSummaryId string  `json:"orderItemSummaryId"`
+	Quantity           float64 `json:"quantity"`
+}
+
+// FulfillmentOrderInput is a fulfillment order to create from an order delivery group summary
+type FulfillmentOrderInput struct {
+	FulfilledFromLocationId string                 `json:"fulfilledFromLocationId"`
+	FulfillmentType         string                 `json:"fulfillmentType"` // such as Warehouse or Store
+	OrderItemSummaries      []FulfillmentOrderItem `json:"orderItemSummaries"`
+}
+
+// FulfillmentOrderLineItem is a quantity of a fulfillment order line item
+type FulfillmentOrderLineItem struct {
+	FulfillmentOrderLineItemId string  `json:"fulfillmentOrderLineItemId"`
+	Quantity                   float64 `json:"quantity"`
+}
+
+type orderSummaryInput struct {
+	OrderId            string `json:"orderId"`
+	OrderLifeCycleType string `json:"orderLifeCycleType,omitempty"`
+}
+
+type fulfillmentOrdersInput struct {
+	FulfillmentOrders           []FulfillmentOrderInput `json:"fulfillmentOrders"`
+	OrderDeliveryGroupSummaryId string                  `json:"orderDeliveryGroupSummaryId"`
+	OrderSummaryId              string                  `json:"orderSummaryId"`
+}
+
+type cancelFulfillmentOrderItemsInput struct {
+	FulfillmentOrderLineItemsToCancel []FulfillmentOrderLineItem `json:"fulfillmentOrderLineItemsToCancel"`
+}
+
+// orderManagementResult is the output of an Order Management action, with the ids of the records it
+// created, if any
+type orderManagementResult struct {
+	Success             bool                     `json:"success"`
+	Errors              []SalesforceErrorMessage `json:"errors"`
+	OrderSummaryId      string                   `json:"orderSummaryId"`
+	FulfillmentOrderIds []string                 `json:"fulfillmentOrderIds"`
+	InvoiceId           string                   `json:"invoiceId"`
+}
+
+// CreateOrderSummary creates an order summary from an activated order, which Order Management processes
+// are run against, and returns its id. lifeCycleType is OrderLifeCycleManaged or
+// OrderLifeCycleUnmanaged, managed if empty. If Salesforce rejects it, its errors are returned as a
+// RecordError.
+func (sf *Salesforce) CreateOrderSummary(
+	ctx context.Context,
+	orderId string,
+	lifeCycleType string,
+) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if orderId == "" {
+		return "", errors.New("order id is required")
+	}
+	result, err := postOrderManagementAction(ctx, sf, orderSummariesUri, orderId, orderSummaryInput{
+		OrderId:            orderId,
+		OrderLifeCycleType: lifeCycleType,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.OrderSummaryId, nil
+}
+
+// CreateFulfillmentOrders creates fulfillment orders for the items of an order delivery group summary,
+// such as one for each warehouse that ships part of an order, and returns their ids. If Salesforce
+// rejects them, its errors are returned as a RecordError.
+func (sf *Salesforce) CreateFulfillmentOrders(
+	ctx context.Context,
+	orderSummaryId string,
+	orderDeliveryGroupSummaryId string,
+	fulfillmentOrders []FulfillmentOrderInput,
+) ([]string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if orderSummaryId == "" || orderDeliveryGroupSummaryId == "" {
+		return nil, errors.New("order summary id and order delivery group summary id are required")
+	}
+	if len(fulfillmentOrders) == 0 {
+		return nil, errors.New("no fulfillment orders to create")
+	}
+	result, err := postOrderManagementAction(
+		ctx,
+		sf,
+		fulfillmentOrdersUri,
+		orderSummaryId,
+		fulfillmentOrdersInput{
+			FulfillmentOrders:           fulfillmentOrders,
+			OrderDeliveryGroupSummaryId: orderDeliveryGroupSummaryId,
+			OrderSummaryId:              orderSummaryId,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result.FulfillmentOrderIds, nil
+}
+
+// CancelFulfillmentOrderItems cancels quantities of the line items of a fulfillment order, such as
+// items a warehouse cannot ship, and updates the order summary accordingly. If Salesforce rejects the
+// cancellation, its errors are returned as a RecordError.
+func (sf *Salesforce) CancelFulfillmentOrderItems(
+	ctx context.Context,
+	fulfillmentOrderId string,
+	lineItems []FulfillmentOrderLineItem,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if fulfillmentOrderId == "" {
+		return errors.New("fulfillment order id is required")
+	}
+	if len(lineItems) == 0 {
+		return errors.New("no line items to cancel")
+	}
+	_, err := postOrderManagementAction(
+		ctx,
+		sf,
+		fulfillmentOrdersUri+"/"+url.PathEscape(fulfillmentOrderId)+"/actions/cancel-item",
+		fulfillmentOrderId,
+		cancelFulfillmentOrderItemsInput{FulfillmentOrderLineItemsToCancel: lineItems},
+	)
+	return err
+}
+
+// CreateFulfillmentOrderInvoice creates an invoice for a fulfillment order, typically once it has
+// shipped, and returns its id. If Salesforce rejects it, its errors are returned as a RecordError.
+func (sf *Salesforce) CreateFulfillmentOrderInvoice(
+	ctx context.Context,
+	fulfillmentOrderId string,
+) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if fulfillmentOrderId == "" {
+		return "", errors.New("fulfillment order id is required")
+	}
+	result, err := postOrderManagementAction(
+		ctx,
+		sf,
+		fulfillmentOrdersUri+"/"+url.PathEscape(fulfillmentOrderId)+"/actions/create-invoice",
+		fulfillmentOrderId,
+		struct{}{},
+	)
+	if err != nil {
+		return "", err
+	}
+	return result.InvoiceId, nil
+}
+
+// postOrderManagementAction posts an Order Management action, returning the errors of an unsuccessful
+// action as a RecordError of the record it acts on
+func postOrderManagementAction(
+	ctx context.Context,
+	sf *Salesforce,
+	uri string,
+	recordId string,
+	input any,
+) (orderManagementResult, error) {
+	body, err := marshalJSON(sf.config, input)
+	if err != nil {
+		return orderManagementResult{}, err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodPost,
+		uri:      uri,
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return orderManagementResult{}, err
+	}
+	result := orderManagementResult{}
+	if err := decodeJSONResponse(resp, &result); err != nil {
+		return orderManagementResult{}, err
+	}
+	if !result.Success {
+		return orderManagementResult{}, RecordError{Id: recordId, Errors: result.Errors}
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderManagement(t *testing.T) {
+	requests := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri := strings.TrimPrefix(r.URL.Path, "/services/data/"+apiVersion)
+		body, _ := io.ReadAll(r.Body)
+		request := map[string]any{}
+		_ = json.Unmarshal(body, &request)
+		requests[uri] = request
+		switch uri {
+		case orderSummariesUri:
+			_, _ = w.Write([]byte(`{"orderSummaryId": "1Os000000000001AAA", "success": true}`))
+		case fulfillmentOrdersUri:
+			_, _ = w.Write(
+				[]byte(`{"fulfillmentOrderIds": ["0a3000000000001AAA"], "success": true}`),
+			)
+		case fulfillmentOrdersUri + "/0a3000000000001AAA/actions/cancel-item":
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case fulfillmentOrdersUri + "/0a3000000000001AAA/actions/create-invoice":
+			_, _ = w.Write([]byte(`{"invoiceId": "3tt000000000001AAA", "success": true}`))
+		case fulfillmentOrdersUri + "/0a3000000000002AAA/actions/create-invoice":
+			_, _ = w.Write([]byte(`{"success": false, "errors": [` +
+				`{"errorCode": "INVALID_STATUS", "message": "Fulfillment order has an invoice"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	ctx := context.Background()
+
+	orderSummaryId, err := sf.CreateOrderSummary(ctx, "801000000000001AAA", OrderLifeCycleManaged)
+	if err != nil || orderSummaryId != "1Os000000000001AAA" {
+		t.Errorf("CreateOrderSummary() = %s, %v", orderSummaryId, err)
+	}
+	wantRequest := map[string]any{
+		"orderId":            "801000000000001AAA",
+		"orderLifeCycleType": OrderLifeCycleManaged,
+	}
+	if !reflect.DeepEqual(requests[orderSummariesUri], wantRequest) {
+		t.Errorf(
+			"CreateOrderSummary() request = %v, want %v",
+			requests[orderSummariesUri],
+			wantRequest,
+		)
+	}
+
+	fulfillmentOrderIds, err := sf.CreateFulfillmentOrders(
+		ctx,
+		"1Os000000000001AAA",
+		"0ag000000000001AAA",
+		[]FulfillmentOrderInput{{
+			FulfilledFromLocationId: "131000000000001AAA",
+			FulfillmentType:         "Warehouse",
+			OrderItemSummaries: []FulfillmentOrderItem{
+				{OrderItemSummaryId: "10u000000000001AAA", Quantity: 2},
+			},
+		}},
+	)
+	if err != nil || !reflect.DeepEqual(fulfillmentOrderIds, []string{"0a3000000000001AAA"}) {
+		t.Errorf("CreateFulfillmentOrders() = %v, %v", fulfillmentOrderIds, err)
+	}
+	if requests[fulfillmentOrdersUri]["orderDeliveryGroupSummaryId"] != "0ag000000000001AAA" {
+		t.Errorf("CreateFulfillmentOrders() request = %v", requests[fulfillmentOrdersUri])
+	}
+
+	err = sf.CancelFulfillmentOrderItems(ctx, "0a3000000000001AAA", []FulfillmentOrderLineItem{
+		{FulfillmentOrderLineItemId: "0a4000000000001AAA", Quantity: 1},
+	})
+	if err != nil {
+		t.Errorf("CancelFulfillmentOrderItems() error = %v", err)
+	}
+
+	invoiceId, err := sf.CreateFulfillmentOrderInvoice(ctx, "0a3000000000001AAA")
+	if err != nil || invoiceId != "3tt000000000001AAA" {
+		t.Errorf("CreateFulfillmentOrderInvoice() = %s, %v", invoiceId, err)
+	}
+	_, err = sf.CreateFulfillmentOrderInvoice(ctx, "0a3000000000002AAA")
+	recordErr := RecordError{}
+	if !errors.As(err, &recordErr) || recordErr.Id != "0a3000000000002AAA" ||
+		recordErr.Errors[0].ErrorCode != "INVALID_STATUS" {
+		t.Errorf("CreateFulfillmentOrderInvoice() error = %v, want a RecordError", err)
+	}
+
+	if _, err := sf.CreateOrderSummary(ctx, "", ""); err == nil {
+		t.Errorf("CreateOrderSummary() expected error without an order id")
+	}
+	if _, err := sf.CreateFulfillmentOrders(ctx, "1Os", "0ag", nil); err == nil {
+		t.Errorf("CreateFulfillmentOrders() expected error without fulfillment orders")
+	}
+	if err := sf.CancelFulfillmentOrderItems(ctx, "0a3", nil); err == nil {
+		t.Errorf("CancelFulfillmentOrderItems() expected error without line items")
+	}
+}
@@ -0,0 +1,165 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+const (
+	xmlSchemaInstanceNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	outboundMessageMaxBytes    = 10 << 20 // 10 MB, well above the largest outbound message
+	xmlType                    = "text/xml; charset=utf-8"
+)
+
+const outboundMessageAckTemplate = `<?xml version="1.0" encoding="UTF-8"?>` +
+	`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+	`<soapenv:Body>` +
+	`<notificationsResponse xmlns="http://soap.sforce.com/2005/09/outbound">` +
+	`<Ack>%s</Ack>` +
+	`</notificationsResponse>` +
+	`</soapenv:Body>` +
+	`</soapenv:Envelope>`
+
+// OutboundMessage is a workflow or flow Outbound Message sent by Salesforce
+type OutboundMessage struct {
+	OrganizationId string
+	ActionId       string
+	SessionId      string
+	EnterpriseUrl  string
+	PartnerUrl     string
+	Notifications  []OutboundNotification
+}
+
+// OutboundNotification is a single record included in an Outbound Message
+type OutboundNotification struct {
+	Id          string         // notification id, used by Salesforce to detect duplicate deliveries
+	SObjectType string         // ex: Account
+	Fields      map[string]any // field values, nil for fields sent as null
+}
+
+// Decode decodes the notification fields into a struct or map, honoring salesforce struct tags.
+// Values are sent as strings, so numbers and booleans are converted to the type of the destination.
+func (n OutboundNotification) Decode(v any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           v,
+		TagName:          "salesforce,mapstructure",
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(n.Fields)
+}
+
+// OutboundMessageFunc processes an Outbound Message. Returning an error negatively acknowledges the
+// message so that Salesforce retries the delivery.
+type OutboundMessageFunc func(ctx context.Context, message OutboundMessage) error
+
+type outboundMessageHandler struct {
+	handle          OutboundMessageFunc
+	organizationIds []string
+}
+
+// NewOutboundMessageHandler returns an http.Handler that parses Outbound Message SOAP requests, passes
+// them to handle, and responds with the acknowledgement Salesforce expects. If organizationIds are
+// given, messages from any other org are rejected.
+func NewOutboundMessageHandler(handle OutboundMessageFunc, organizationIds ...string) http.Handler {
+	return &outboundMessageHandler{
+		handle:          handle,
+		organizationIds: organizationIds,
+	}
+}
+
+func (h *outboundMessageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	message, err := ParseOutboundMessage(io.LimitReader(r.Body, outboundMessageMaxBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(h.organizationIds) > 0 && !slices.Contains(h.organizationIds, message.OrganizationId) {
+		http.Error(w, "unexpected organization id", http.StatusForbidden)
+		return
+	}
+
+	ack := "true"
+	if err := h.handle(r.Context(), message); err != nil {
+		ack = "false"
+	}
+	w.Header().Set("Content-Type", xmlType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, outboundMessageAckTemplate, ack)
+}
+
+type outboundEnvelope struct {
+	Body struct {
+		Notifications *struct {
+			OrganizationId string `xml:"OrganizationId"`
+			ActionId       string `xml:"ActionId"`
+			SessionId      string `xml:"SessionId"`
+			EnterpriseUrl  string `xml:"EnterpriseUrl"`
+			PartnerUrl     string `xml:"PartnerUrl"`
+			Notification   []struct {
+				Id      string `xml:"Id"`
+				SObject struct {
+					Type   string             `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+					Fields []outboundXmlField `xml:",any"`
+				} `xml:"sObject"`
+			} `xml:"Notification"`
+		} `xml:"notifications"`
+	} `xml:"Body"`
+}
+
+type outboundXmlField struct {
+	XMLName xml.Name
+	Nil     string `xml:"http://www.w3.org/2001/XMLSchema-instance nil,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// ParseOutboundMessage parses the SOAP envelope of an Outbound Message
+func ParseOutboundMessage(body io.Reader) (OutboundMessage, error) {
+	envelope := outboundEnvelope{}
+	if err := xml.NewDecoder(body).Decode(&envelope); err != nil {
+		return OutboundMessage{}, err
+	}
+	notifications := envelope.Body.Notifications
+	if notifications == nil {
+		return OutboundMessage{}, errors.New("outbound message is missing notifications")
+	}
+
+	message := OutboundMessage{
+		OrganizationId: notifications.OrganizationId,
+		ActionId:       notifications.ActionId,
+		SessionId:      notifications.SessionId,
+		EnterpriseUrl:  notifications.EnterpriseUrl,
+		PartnerUrl:     notifications.PartnerUrl,
+	}
+	for _, notification := range notifications.Notification {
+		_, sObjectType, _ := strings.Cut(notification.SObject.Type, ":")
+		fields := map[string]any{}
+		for _, field := range notification.SObject.Fields {
+			if field.Nil == "true" {
+				fields[field.XMLName.Local] = nil
+			} else {
+				fields[field.XMLName.Local] = field.Value
+			}
+		}
+		message.Notifications = append(message.Notifications, OutboundNotification{
+			Id:          notification.Id,
+			SObjectType: sObjectType,
+			Fields:      fields,
+		})
+	}
+	return message, nil
+}
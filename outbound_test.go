@@ -0,0 +1,194 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testOutboundMessage = `<?xml version="1.0" encoding="UTF-8"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+	xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+	<soapenv:Body>
+		<notifications xmlns="http://soap.sforce.com/2005/09/outbound">
+			<OrganizationId>00Dxx0000001gEREAY</OrganizationId>
+			<ActionId>04kxx0000000007AAA</ActionId>
+			<SessionId xsi:nil="true"/>
+			<EnterpriseUrl>https://example.my.salesforce.com/services/Soap/c/63.0/00Dxx</EnterpriseUrl>
+			<PartnerUrl>https://example.my.salesforce.com/services/Soap/u/63.0/00Dxx</PartnerUrl>
+			<Notification>
+				<Id>04lxx000000000TAAQ</Id>
+				<sObject xsi:type="sf:Account" xmlns:sf="urn:sobject.enterprise.soap.sforce.com">
+					<sf:Id>001xx000003DGb2AAG</sf:Id>
+					<sf:Name>Acme</sf:Name>
+					<sf:NumberOfEmployees>25</sf:NumberOfEmployees>
+					<sf:Phone xsi:nil="true"/>
+				</sObject>
+			</Notification>
+		</notifications>
+	</soapenv:Body>
+</soapenv:Envelope>`
+
+func TestParseOutboundMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    OutboundMessage
+		wantErr bool
+	}{
+		{
+			name: "parse_account_notification",
+			body: testOutboundMessage,
+			want: OutboundMessage{
+				OrganizationId: "00Dxx0000001gEREAY",
+				ActionId:       "04kxx0000000007AAA",
+				EnterpriseUrl:  "https://example.my.salesforce.com/services/Soap/c/63.0/00Dxx",
+				PartnerUrl:     "https://example.my.salesforce.com/services/Soap/u/63.0/00Dxx",
+				Notifications: []OutboundNotification{{
+					Id:          "04lxx000000000TAAQ",
+					SObjectType: "Account",
+					Fields: map[string]any{
+						"Id":                "001xx000003DGb2AAG",
+						"Name":              "Acme",
+						"NumberOfEmployees": "25",
+						"Phone":             nil,
+					},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing_notifications",
+			body:    `<Envelope><Body></Body></Envelope>`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_xml",
+			body:    `<Envelope>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOutboundMessage(strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseOutboundMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOutboundMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboundNotification_Decode(t *testing.T) {
+	type account struct {
+		Id        string
+		Name      string
+		Employees int `salesforce:"NumberOfEmployees"`
+		Phone     *string
+	}
+	notification := OutboundNotification{Fields: map[string]any{
+		"Id":                "001xx000003DGb2AAG",
+		"Name":              "Acme",
+		"NumberOfEmployees": "25",
+		"Phone":             nil,
+	}}
+	got := account{}
+	if err := notification.Decode(&got); err != nil {
+		t.Fatalf("OutboundNotification.Decode() error = %v", err)
+	}
+	want := account{Id: "001xx000003DGb2AAG", Name: "Acme", Employees: 25}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OutboundNotification.Decode() = %v, want %v", got, want)
+	}
+}
+
+func TestOutboundMessageHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		body            string
+		handleErr       error
+		organizationIds []string
+		wantStatus      int
+		wantAck         string
+	}{
+		{
+			name:       "ack_true",
+			method:     http.MethodPost,
+			body:       testOutboundMessage,
+			wantStatus: http.StatusOK,
+			wantAck:    "<Ack>true</Ack>",
+		},
+		{
+			name:       "ack_false_on_handler_error",
+			method:     http.MethodPost,
+			body:       testOutboundMessage,
+			handleErr:  errors.New("downstream unavailable"),
+			wantStatus: http.StatusOK,
+			wantAck:    "<Ack>false</Ack>",
+		},
+		{
+			name:            "allowed_organization",
+			method:          http.MethodPost,
+			body:            testOutboundMessage,
+			organizationIds: []string{"00Dxx0000001gEREAY"},
+			wantStatus:      http.StatusOK,
+			wantAck:         "<Ack>true</Ack>",
+		},
+		{
+			name:            "unexpected_organization",
+			method:          http.MethodPost,
+			body:            testOutboundMessage,
+			organizationIds: []string{"00Dxx0000009999AAA"},
+			wantStatus:      http.StatusForbidden,
+		},
+		{
+			name:       "invalid_body",
+			method:     http.MethodPost,
+			body:       "not xml",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid_method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received OutboundMessage
+			handler := NewOutboundMessageHandler(
+				func(ctx context.Context, message OutboundMessage) error {
+					received = message
+					return tt.handleErr
+				},
+				tt.organizationIds...,
+			)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, "/outbound", strings.NewReader(tt.body))
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %v, want %v", recorder.Code, tt.wantStatus)
+			}
+			body, _ := io.ReadAll(recorder.Body)
+			if tt.wantAck != "" {
+				if !strings.Contains(string(body), tt.wantAck) {
+					t.Errorf("ServeHTTP() body = %v, want %v", string(body), tt.wantAck)
+				}
+				if received.OrganizationId != "00Dxx0000001gEREAY" {
+					t.Errorf("ServeHTTP() did not pass the parsed message to the handler")
+				}
+			}
+		})
+	}
+}
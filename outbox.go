@@ -0,0 +1,505 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	outboxMaxAttempts   = 10
+	outboxMinBackoff    = time.Second
+	outboxMaxBackoff    = 5 * time.Minute
+	outboxFlushInterval = 5 * time.Second
+)
+
+// OutboxOperation is the DML operation of an outbox entry
+type OutboxOperation string
+
+const (
+	OutboxInsert OutboxOperation = "INSERT"
+	OutboxUpdate OutboxOperation = "UPDATE"
+	OutboxUpsert OutboxOperation = "UPSERT"
+	OutboxDelete OutboxOperation = "DELETE"
+)
+
+// OutboxEntry is a write waiting in a WriteOutbox to be sent to Salesforce
+type OutboxEntry struct {
+	Key             string          `json:"key"` // idempotency key of the write
+	Operation       OutboxOperation `json:"operation"`
+	SObjectName     string          `json:"sObjectName"`
+	ExternalIdField string          `json:"externalIdField,omitempty"` // upserts only
+	Record          map[string]any  `json:"record"`
+	OrderingKey     string          `json:"orderingKey"` // entries with the same key are sent in order
+	Attempts        int             `json:"attempts"`
+	LastError       string          `json:"lastError,omitempty"`
+	NextAttempt     time.Time       `json:"nextAttempt"`
+	Failed          bool            `json:"failed"` // every attempt failed, see WriteOutbox.Retry
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+// OutboxStore persists the entries of a WriteOutbox, so that writes survive a restart or an outage of
+// Salesforce. Store it alongside application data, such as in the same database, to add entries in the
+// same transaction as the change they mirror. Implementations must be safe for concurrent use.
+type OutboxStore interface {
+	// Add stores an entry unless an entry with the same key is stored, and reports whether it was added
+	Add(entry OutboxEntry) (bool, error)
+	// Entries returns the stored entries in the order they were added
+	Entries() ([]OutboxEntry, error)
+	// Update replaces the stored entry with the same key
+	Update(entry OutboxEntry) error
+	// Remove deletes the entry with the key, after it was written to Salesforce or discarded
+	Remove(key string) error
+}
+
+// MemoryOutboxStore keeps outbox entries in memory, useful for tests and for buffering writes during
+// short outages of a long running process
+type MemoryOutboxStore struct {
+	mu      sync.Mutex
+	entries []OutboxEntry
+}
+
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{}
+}
+
+func (s *MemoryOutboxStore) Add(entry OutboxEntry) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if outboxEntryIndex(s.entries, entry.Key) >= 0 {
+		return false, nil
+	}
+	s.entries = append(s.entries, entry)
+	return true, nil
+}
+
+func (s *MemoryOutboxStore) Entries() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]OutboxEntry(nil), s.entries...), nil
+}
+
+func (s *MemoryOutboxStore) Update(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := outboxEntryIndex(s.entries, entry.Key)
+	if i < 0 {
+		return fmt.Errorf("outbox entry not found: %s", entry.Key)
+	}
+	s.entries[i] = entry
+	return nil
+}
+
+func (s *MemoryOutboxStore) Remove(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := outboxEntryIndex(s.entries, key); i >= 0 {
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	}
+	return nil
+}
+
+// FileOutboxStore persists outbox entries to a json file. Every update is written to a temporary file
+// which then replaces the original, so a crash never leaves a partial file.
+type FileOutboxStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFileOutboxStore(filePath string) *FileOutboxStore {
+	return &FileOutboxStore{filePath: filePath}
+}
+
+func (s *FileOutboxStore) Add(entry OutboxEntry) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	if outboxEntryIndex(entries, entry.Key) >= 0 {
+		return false, nil
+	}
+	return true, s.write(append(entries, entry))
+}
+
+func (s *FileOutboxStore) Entries() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *FileOutboxStore) Update(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	i := outboxEntryIndex(entries, entry.Key)
+	if i < 0 {
+		return fmt.Errorf("outbox entry not found: %s", entry.Key)
+	}
+	entries[i] = entry
+	return s.write(entries)
+}
+
+func (s *FileOutboxStore) Remove(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	i := outboxEntryIndex(entries, key)
+	if i < 0 {
+		return nil
+	}
+	return s.write(append(entries[:i], entries[i+1:]...))
+}
+
+func (s *FileOutboxStore) read() ([]OutboxEntry, error) {
+	entries := []OutboxEntry{}
+	data, err := afero.ReadFile(appFs, s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *FileOutboxStore) write(entries []OutboxEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tempPath := s.filePath + ".tmp"
+	if err := afero.WriteFile(appFs, tempPath, data, 0o644); err != nil {
+		return err
+	}
+	return appFs.Rename(tempPath, s.filePath)
+}
+
+func outboxEntryIndex(entries []OutboxEntry, key string) int {
+	for i, entry := range entries {
+		if entry.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// OutboxSettings configures a WriteOutbox. Zero values take the defaults noted on each field.
+type OutboxSettings struct {
+	Store         OutboxStore
+	MaxAttempts   int           // attempts before an entry is marked as failed (default 10)
+	MinBackoff    time.Duration // wait after the first failure, doubled after each (default 1 second)
+	MaxBackoff    time.Duration // longest wait between attempts (default 5 minutes)
+	FlushInterval time.Duration // how often Run flushes (default 5 seconds)
+}
+
+// WriteOutbox accepts writes without contacting Salesforce and sends them later, so that application
+// transactions do not depend on Salesforce being available. Writes are persisted in a store, sent in
+// the order they were added for each record, and retried with exponential backoff. Every write has an
+// idempotency key: adding a key that is already in the outbox has no effect, and inserts are sent with
+// InsertOneIdempotent so that a retry never creates the record twice, see WithIdempotencyKeyField.
+type WriteOutbox struct {
+	sf       *Salesforce
+	settings OutboxSettings
+	flushMu  sync.Mutex
+	now      func() time.Time
+}
+
+// NewWriteOutbox returns a WriteOutbox that persists its entries in the store of the settings
+func (sf *Salesforce) NewWriteOutbox(settings OutboxSettings) (*WriteOutbox, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if settings.Store == nil {
+		return nil, errors.New("outbox store is required")
+	}
+	if settings.MaxAttempts < 0 || settings.MinBackoff < 0 || settings.MaxBackoff < 0 ||
+		settings.FlushInterval < 0 {
+		return nil, errors.New("outbox settings cannot be negative")
+	}
+	if settings.MaxAttempts == 0 {
+		settings.MaxAttempts = outboxMaxAttempts
+	}
+	if settings.MinBackoff == 0 {
+		settings.MinBackoff = outboxMinBackoff
+	}
+	if settings.MaxBackoff == 0 {
+		settings.MaxBackoff = outboxMaxBackoff
+	}
+	if settings.FlushInterval == 0 {
+		settings.FlushInterval = outboxFlushInterval
+	}
+	return &WriteOutbox{sf: sf, settings: settings, now: time.Now}, nil
+}
+
+// Insert adds a record to insert, and reports whether it was added or its key was already in the outbox
+func (o *WriteOutbox) Insert(key string, sObjectName string, record any) (bool, error) {
+	return o.add(key, OutboxInsert, sObjectName, "", record)
+}
+
+// Update adds a record to update, the record must have an Id
+func (o *WriteOutbox) Update(key string, sObjectName string, record any) (bool, error) {
+	return o.add(key, OutboxUpdate, sObjectName, "", record)
+}
+
+// Upsert adds a record to upsert on an external id field
+func (o *WriteOutbox) Upsert(
+	key string,
+	sObjectName string,
+	externalIdFieldName string,
+	record any,
+) (bool, error) {
+	if externalIdFieldName == "" {
+		return false, errors.New("external id field name cannot be empty")
+	}
+	return o.add(key, OutboxUpsert, sObjectName, externalIdFieldName, record)
+}
+
+// Delete adds a record to delete, the record must have an Id
+func (o *WriteOutbox) Delete(key string, sObjectName string, record any) (bool, error) {
+	return o.add(key, OutboxDelete, sObjectName, "", record)
+}
+
+func (o *WriteOutbox) add(
+	key string,
+	operation OutboxOperation,
+	sObjectName string,
+	externalIdField string,
+	record any,
+) (bool, error) {
+	if key == "" {
+		return false, errors.New("idempotency key cannot be empty")
+	}
+	if err := validateOfTypeStructOrMap(record); err != nil {
+		return false, err
+	}
+	sObjectName, nameErr := inferSObjectName(o.sf, sObjectName, record)
+	if nameErr != nil {
+		return false, nameErr
+	}
+	recordMap, err := convertToMap(record)
+	if err != nil {
+		return false, err
+	}
+	recordMap = cloneRecord(recordMap)
+
+	// writes to the same record are ordered, inserts have no record to order on until they are sent
+	orderingKey := key
+	switch operation {
+	case OutboxUpdate, OutboxDelete:
+		id, ok := recordMap["Id"].(string)
+		if !ok || id == "" {
+			return false, errors.New("salesforce id not found in object data")
+		}
+		orderingKey = sObjectName + "/" + id
+	case OutboxUpsert:
+		externalId, ok := convertToString(recordMap[externalIdField])
+		if !ok || externalId == "" {
+			return false, fmt.Errorf("%s not found in %s data", externalIdField, sObjectName)
+		}
+		orderingKey = sObjectName + "/" + externalIdField + "/" + externalId
+	}
+
+	now := o.now()
+	return o.settings.Store.Add(OutboxEntry{
+		Key:             key,
+		Operation:       operation,
+		SObjectName:     sObjectName,
+		ExternalIdField: externalIdField,
+		Record:          recordMap,
+		OrderingKey:     orderingKey,
+		NextAttempt:     now,
+		CreatedAt:       now,
+	})
+}
+
+// Flush sends the entries that are due, in the order they were added, and returns the number of entries
+// written. A failed entry is retried after a backoff and holds back later entries with the same ordering
+// key until it is written or discarded. Flush only returns an error if the store or ctx fails, errors of
+// writes are recorded on their entries.
+func (o *WriteOutbox) Flush(ctx context.Context) (int, error) {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+
+	entries, err := o.settings.Store.Entries()
+	if err != nil {
+		return 0, err
+	}
+	written := 0
+	blocked := map[string]bool{}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		if blocked[entry.OrderingKey] {
+			continue
+		}
+		if entry.Failed || o.now().Before(entry.NextAttempt) {
+			blocked[entry.OrderingKey] = true
+			continue
+		}
+
+		if writeErr := o.write(entry); writeErr != nil {
+			blocked[entry.OrderingKey] = true
+			entry.Attempts++
+			entry.LastError = writeErr.Error()
+			if entry.Attempts >= o.settings.MaxAttempts {
+				entry.Failed = true
+			} else {
+				entry.NextAttempt = o.now().Add(o.backoff(entry.Attempts))
+			}
+			if err := o.settings.Store.Update(entry); err != nil {
+				return written, err
+			}
+			continue
+		}
+		if err := o.settings.Store.Remove(entry.Key); err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// Run flushes every flush interval until ctx is done
+func (o *WriteOutbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.settings.FlushInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := o.Flush(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Failed returns the entries whose every attempt failed, which hold back later writes to their records
+// until they are retried or discarded
+func (o *WriteOutbox) Failed() ([]OutboxEntry, error) {
+	entries, err := o.settings.Store.Entries()
+	if err != nil {
+		return nil, err
+	}
+	failed := []OutboxEntry{}
+	for _, entry := range entries {
+		if entry.Failed {
+			failed = append(failed, entry)
+		}
+	}
+	return failed, nil
+}
+
+// Retry resets the attempts of a failed entry so that the next flush sends it again
+func (o *WriteOutbox) Retry(key string) error {
+	entries, err := o.settings.Store.Entries()
+	if err != nil {
+		return err
+	}
+	i := outboxEntryIndex(entries, key)
+	if i < 0 {
+		return fmt.Errorf("outbox entry not found: %s", key)
+	}
+	entry := entries[i]
+	entry.Attempts = 0
+	entry.Failed = false
+	entry.NextAttempt = o.now()
+	return o.settings.Store.Update(entry)
+}
+
+// Discard removes an entry without sending it
+func (o *WriteOutbox) Discard(key string) error {
+	return o.settings.Store.Remove(key)
+}
+
+func (o *WriteOutbox) write(entry OutboxEntry) error {
+	// requests modify the record they send, including nested lookups, which must stay intact for retries
+	record := cloneRecord(entry.Record)
+	var result SalesforceResult
+	var err error
+	switch entry.Operation {
+	case OutboxInsert:
+		result, err = o.sf.InsertOneIdempotent(entry.Key, entry.SObjectName, record)
+	case OutboxUpdate:
+		return o.sf.UpdateOne(entry.SObjectName, record)
+	case OutboxUpsert:
+		result, err = o.sf.UpsertOne(entry.SObjectName, entry.ExternalIdField, record)
+	case OutboxDelete:
+		return o.sf.DeleteOne(entry.SObjectName, record)
+	default:
+		return fmt.Errorf("unknown outbox operation: %s", entry.Operation)
+	}
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return RecordError{Id: result.Id, Errors: result.Errors}
+	}
+	return nil
+}
+
+// cloneRecord returns a deep copy of a record, copying nested records such as lookups by external id and
+// lists of values, so that changes to the copy do not change the record
+func cloneRecord(record map[string]any) map[string]any {
+	if record == nil {
+		return nil
+	}
+	clone := make(map[string]any, len(record))
+	for key, value := range record {
+		clone[key] = cloneRecordValue(value)
+	}
+	return clone
+}
+
+func cloneRecordValue(value any) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return cloneRecord(typedValue)
+	case map[string]string:
+		return maps.Clone(typedValue)
+	case []any:
+		values := make([]any, len(typedValue))
+		for i, element := range typedValue {
+			values[i] = cloneRecordValue(element)
+		}
+		return values
+	case []map[string]any:
+		records := make([]map[string]any, len(typedValue))
+		for i, element := range typedValue {
+			records[i] = cloneRecord(element)
+		}
+		return records
+	default:
+		return value
+	}
+}
+
+// backoff returns the wait after a number of failed attempts
+func (o *WriteOutbox) backoff(attempts int) time.Duration {
+	backoff := o.settings.MinBackoff
+	for i := 1; i < attempts && backoff < o.settings.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, o.settings.MaxBackoff)
+}
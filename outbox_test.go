@@ -0,0 +1,336 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestWriteOutbox_Flush(t *testing.T) {
+	var mu sync.Mutex
+	requests := []string{}
+	failures := map[string]int{"/services/data/" + apiVersion + "/sobjects/Account/001A": 1}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if failures[r.URL.Path] > 0 {
+			failures[r.URL.Path]--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`[{"errorCode":"SERVER_UNAVAILABLE","message":"unavailable"}]`))
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"001N","success":true}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	outbox, err := sf.NewWriteOutbox(OutboxSettings{Store: NewMemoryOutboxStore()})
+	if err != nil {
+		t.Fatalf("NewWriteOutbox() error = %v", err)
+	}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time { return now }
+
+	adds := []struct {
+		add       func() (bool, error)
+		wantAdded bool
+	}{
+		{
+			add: func() (bool, error) {
+				return outbox.Insert("k1", "Account", map[string]any{"Name": "Acme"})
+			},
+			wantAdded: true,
+		},
+		{
+			add: func() (bool, error) {
+				return outbox.Update("k2", "Account", map[string]any{"Id": "001A", "Name": "B"})
+			},
+			wantAdded: true,
+		},
+		{
+			add: func() (bool, error) {
+				return outbox.Update("k3", "Account", map[string]any{"Id": "001A", "Name": "C"})
+			},
+			wantAdded: true,
+		},
+		{
+			add: func() (bool, error) {
+				return outbox.Delete("k4", "Contact", map[string]any{"Id": "003A"})
+			},
+			wantAdded: true,
+		},
+		{
+			add: func() (bool, error) {
+				return outbox.Insert("k1", "Account", map[string]any{"Name": "Acme"})
+			},
+			wantAdded: false,
+		},
+	}
+	for i, tt := range adds {
+		added, err := tt.add()
+		if err != nil || added != tt.wantAdded {
+			t.Fatalf("add %d = %v, %v, want %v", i, added, err, tt.wantAdded)
+		}
+	}
+
+	flushes := []struct {
+		advance      time.Duration
+		wantWritten  int
+		wantRequests []string
+	}{
+		{
+			// the failed update holds back the later update of the same record
+			wantWritten: 2,
+			wantRequests: []string{
+				"POST /services/data/" + apiVersion + "/sobjects/Account",
+				"PATCH /services/data/" + apiVersion + "/sobjects/Account/001A",
+				"DELETE /services/data/" + apiVersion + "/sobjects/Contact/003A",
+			},
+		},
+		{
+			wantWritten:  0,
+			wantRequests: []string{},
+		},
+		{
+			advance:     time.Second,
+			wantWritten: 2,
+			wantRequests: []string{
+				"PATCH /services/data/" + apiVersion + "/sobjects/Account/001A",
+				"PATCH /services/data/" + apiVersion + "/sobjects/Account/001A",
+			},
+		},
+	}
+	for i, tt := range flushes {
+		now = now.Add(tt.advance)
+		requests = []string{}
+		written, err := outbox.Flush(context.Background())
+		if err != nil {
+			t.Fatalf("flush %d: Flush() error = %v", i, err)
+		}
+		if written != tt.wantWritten || !reflect.DeepEqual(requests, tt.wantRequests) {
+			t.Errorf(
+				"flush %d: Flush() = %d, requests %v, want %d, %v",
+				i,
+				written,
+				requests,
+				tt.wantWritten,
+				tt.wantRequests,
+			)
+		}
+	}
+
+	entries, _ := outbox.settings.Store.Entries()
+	if len(entries) != 0 {
+		t.Errorf("Entries() = %v, want none", entries)
+	}
+}
+
+func TestWriteOutbox_Failed(t *testing.T) {
+	server, auth := setupTestServer(
+		[]SalesforceErrorMessage{{ErrorCode: "FIELD_CUSTOM_VALIDATION_EXCEPTION", Message: "bad"}},
+		http.StatusBadRequest,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+
+	outbox, err := sf.NewWriteOutbox(OutboxSettings{
+		Store:       NewMemoryOutboxStore(),
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWriteOutbox() error = %v", err)
+	}
+	if _, err := outbox.Upsert("k1", "Account", "Ext__c", map[string]any{"Ext__c": "A"}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	now := time.Now()
+	outbox.now = func() time.Time { return now }
+	for range 2 {
+		if _, err := outbox.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	failed, err := outbox.Failed()
+	if err != nil || len(failed) != 1 {
+		t.Fatalf("Failed() = %v, %v, want one entry", failed, err)
+	}
+	if failed[0].Attempts != 2 || failed[0].LastError == "" ||
+		failed[0].OrderingKey != "Account/Ext__c/A" {
+		t.Errorf("Failed() = %+v", failed[0])
+	}
+
+	if err := outbox.Retry("k1"); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if failed, _ := outbox.Failed(); len(failed) != 0 {
+		t.Errorf("Failed() = %v after Retry(), want none", failed)
+	}
+	if err := outbox.Discard("k1"); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if entries, _ := outbox.settings.Store.Entries(); len(entries) != 0 {
+		t.Errorf("Entries() = %v after Discard(), want none", entries)
+	}
+	if err := outbox.Retry("k1"); err == nil {
+		t.Errorf("Retry() expected error for a discarded entry")
+	}
+}
+
+func TestWriteOutbox_add(t *testing.T) {
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: "https://example", AccessToken: "1234"},
+	)
+	outbox, err := sf.NewWriteOutbox(OutboxSettings{Store: NewMemoryOutboxStore()})
+	if err != nil {
+		t.Fatalf("NewWriteOutbox() error = %v", err)
+	}
+	type account struct {
+		_    struct{} `salesforce:"object=Account"`
+		Id   string
+		Name string
+	}
+	tests := []struct {
+		name    string
+		add     func() (bool, error)
+		wantErr bool
+	}{
+		{
+			name: "struct",
+			add: func() (bool, error) {
+				return outbox.Update("k1", "", account{Id: "001A", Name: "Acme"})
+			},
+		},
+		{
+			name: "no_key",
+			add: func() (bool, error) {
+				return outbox.Insert("", "Account", map[string]any{"Name": "Acme"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "update_without_id",
+			add: func() (bool, error) {
+				return outbox.Update("k2", "Account", map[string]any{"Name": "Acme"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "upsert_without_external_id",
+			add: func() (bool, error) {
+				return outbox.Upsert("k3", "Account", "Ext__c", map[string]any{"Name": "Acme"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "map_without_sObject_name",
+			add: func() (bool, error) {
+				return outbox.Insert("k4", "", map[string]any{"Name": "Acme"})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.add(); (err != nil) != tt.wantErr {
+				t.Errorf("add error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	entries, _ := outbox.settings.Store.Entries()
+	if len(entries) != 1 || entries[0].SObjectName != "Account" ||
+		entries[0].OrderingKey != "Account/001A" {
+		t.Errorf("Entries() = %+v", entries)
+	}
+}
+
+func TestFileOutboxStore(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	store := NewFileOutboxStore("outbox.json")
+
+	for _, key := range []string{"k1", "k2", "k1"} {
+		if _, err := store.Add(OutboxEntry{Key: key, Operation: OutboxInsert}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := store.Update(OutboxEntry{Key: "k2", Attempts: 1}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := store.Update(OutboxEntry{Key: "k3"}); err == nil {
+		t.Errorf("Update() expected error for a missing entry")
+	}
+	if err := store.Remove("k1"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	entries, err := NewFileOutboxStore("outbox.json").Entries()
+	if err != nil || len(entries) != 1 || entries[0].Key != "k2" || entries[0].Attempts != 1 {
+		t.Errorf("Entries() = %+v, %v, want k2", entries, err)
+	}
+}
+
+func TestWriteOutbox_backoff(t *testing.T) {
+	outbox := &WriteOutbox{
+		settings: OutboxSettings{MinBackoff: time.Second, MaxBackoff: 5 * time.Second},
+	}
+	for attempts, want := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := outbox.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestWriteOutbox_nestedRecordsUnchanged(t *testing.T) {
+	server, auth := setupTestServer(
+		[]SalesforceErrorMessage{{ErrorCode: "UNABLE_TO_LOCK_ROW", Message: "locked"}},
+		http.StatusBadRequest,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&auth)
+	// the write path changes nested lookups in place
+	sf.config.dmlHooks.BeforeUpdate = func(sObjectName string, records []map[string]any) error {
+		for _, record := range records {
+			record["Account"].(map[string]any)["External_Key__c"] = "changed"
+		}
+		return nil
+	}
+
+	outbox, err := sf.NewWriteOutbox(OutboxSettings{Store: NewMemoryOutboxStore()})
+	if err != nil {
+		t.Fatalf("NewWriteOutbox() error = %v", err)
+	}
+	record := map[string]any{
+		"Id":      "003A",
+		"Account": map[string]any{"External_Key__c": "A1"},
+	}
+	if _, err := outbox.Update("k1", "Contact", record); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := outbox.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	entries, _ := outbox.settings.Store.Entries()
+	want := map[string]any{"External_Key__c": "A1"}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0].Record["Account"], want) {
+		t.Errorf("Entries() = %+v, want the stored lookup %v", entries, want)
+	}
+	if !reflect.DeepEqual(record["Account"], want) {
+		t.Errorf("record lookup = %v, want %v", record["Account"], want)
+	}
+}
@@ -0,0 +1,219 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Statuses of a PackageInstallRequest
+const (
+	PackageInstallStatusInProgress = "IN_PROGRESS"
+	PackageInstallStatusSuccess    = "SUCCESS"
+	PackageInstallStatusError      = "ERROR"
+	PackageInstallStatusUnknown    = "UNKNOWN"
+)
+
+// PackageInstallRequest installs or upgrades a package version in the org
+type PackageInstallRequest struct {
+	SubscriberPackageVersionKey string `json:"SubscriberPackageVersionKey"` // package version id starting with 04t
+	Password                    string `json:"Password,omitempty"`          // installation key
+	NameConflictResolution      string `json:"NameConflictResolution,omitempty"`
+	SecurityType                string `json:"SecurityType,omitempty"`    // Full for all users, None for admins only, or Custom
+	ApexCompileType             string `json:"ApexCompileType,omitempty"` // all or package
+	UpgradeType                 string `json:"UpgradeType,omitempty"`     // mixed-mode, deprecate-only, or delete-only
+	EnableRss                   bool   `json:"EnableRss,omitempty"`       // enable remote site settings and CSP trusted sites
+}
+
+// PackageInstallStatus is the progress of a PackageInstallRequest
+type PackageInstallStatus struct {
+	Id                          string `json:"Id"`
+	Status                      string `json:"Status"`
+	SubscriberPackageVersionKey string `json:"SubscriberPackageVersionKey"`
+	Errors                      struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"Errors"`
+}
+
+// ErrorMessages returns the errors reported by a failed installation
+func (s PackageInstallStatus) ErrorMessages() []string {
+	var messages []string
+	for _, err := range s.Errors.Errors {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+// InstalledPackage is a package installed in the org
+type InstalledPackage struct {
+	Id                  string
+	SubscriberPackageId string
+	SubscriberPackage   struct {
+		Name            string
+		NamespacePrefix string
+	}
+	SubscriberPackageVersion struct {
+		Id           string
+		Name         string
+		MajorVersion int
+		MinorVersion int
+		PatchVersion int
+		BuildNumber  int
+	}
+}
+
+// Version returns the installed version formatted as major.minor.patch.build
+func (p InstalledPackage) Version() string {
+	v := p.SubscriberPackageVersion
+	return fmt.Sprintf("%d.%d.%d.%d", v.MajorVersion, v.MinorVersion, v.PatchVersion, v.BuildNumber)
+}
+
+// InstallPackage creates a PackageInstallRequest with the Tooling API and returns its id.
+// Installation is asynchronous, use WaitForPackageInstall to wait for it to finish.
+func (sf *Salesforce) InstallPackage(request PackageInstallRequest) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if !strings.HasPrefix(request.SubscriberPackageVersionKey, "04t") {
+		return "", errors.New(
+			"subscriber package version key must be a package version id starting with 04t",
+		)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/tooling/sobjects/PackageInstallRequest",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result, err := decodeResponseBody(resp)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		var messages []string
+		for _, sfErr := range result.Errors {
+			messages = append(messages, sfErr.Code()+": "+sfErr.Message)
+		}
+		return "", errors.New("package install request failed: " + strings.Join(messages, "; "))
+	}
+	return result.Id, nil
+}
+
+// GetPackageInstallStatus returns the progress of a PackageInstallRequest
+func (sf *Salesforce) GetPackageInstallStatus(requestId string) (*PackageInstallStatus, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	return getPackageInstallStatus(context.Background(), sf, requestId)
+}
+
+// WaitForPackageInstall polls a PackageInstallRequest every interval until it finishes or ctx is done.
+// An error with the installation errors is returned if the installation fails.
+func (sf *Salesforce) WaitForPackageInstall(
+	ctx context.Context,
+	requestId string,
+	interval time.Duration,
+) (*PackageInstallStatus, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if interval <= 0 {
+		return nil, errors.New("poll interval must be greater than 0")
+	}
+
+	var status *PackageInstallStatus
+	err := pollUntilDone(ctx, interval, func(ctx context.Context) (bool, error) {
+		var err error
+		status, err = getPackageInstallStatus(ctx, sf, requestId)
+		if err != nil {
+			return true, err
+		}
+		return status.Status != PackageInstallStatusInProgress, nil
+	})
+	if err != nil {
+		return status, err
+	}
+
+	if status.Status != PackageInstallStatusSuccess {
+		return status, fmt.Errorf(
+			"package install %s %s: %s",
+			requestId,
+			strings.ToLower(status.Status),
+			strings.Join(status.ErrorMessages(), "; "),
+		)
+	}
+	return status, nil
+}
+
+// GetInstalledPackages returns the packages installed in the org and their versions
+func (sf *Salesforce) GetInstalledPackages() ([]InstalledPackage, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	records, err := queryResourceRecords(
+		context.Background(),
+		sf,
+		toolingQueryResource,
+		"SELECT Id, SubscriberPackageId, SubscriberPackage.Name, SubscriberPackage.NamespacePrefix, "+
+			"SubscriberPackageVersion.Id, SubscriberPackageVersion.Name, "+
+			"SubscriberPackageVersion.MajorVersion, SubscriberPackageVersion.MinorVersion, "+
+			"SubscriberPackageVersion.PatchVersion, SubscriberPackageVersion.BuildNumber "+
+			"FROM InstalledSubscriberPackage ORDER BY SubscriberPackage.Name",
+	)
+	if err != nil {
+		return nil, err
+	}
+	packages := []InstalledPackage{}
+	if err := mapstructureDecode(records, &packages); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+func getPackageInstallStatus(
+	ctx context.Context,
+	sf *Salesforce,
+	requestId string,
+) (*PackageInstallStatus, error) {
+	if requestId == "" {
+		return nil, errors.New("package install request id cannot be empty")
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      "/tooling/sobjects/PackageInstallRequest/" + url.PathEscape(requestId),
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &PackageInstallStatus{}
+	if err := decodeJSONResponse(resp, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
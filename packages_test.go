@@ -0,0 +1,154 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_InstallPackage(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		server, sfAuth, captured := setupTestServerWithCapture(
+			SalesforceResult{Id: "0Hf000000000001AAA", Success: true},
+			http.StatusCreated,
+		)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.InstallPackage(PackageInstallRequest{
+			SubscriberPackageVersionKey: "04t000000000001AAA",
+			SecurityType:                "None",
+		})
+		if err != nil {
+			t.Fatalf("InstallPackage() error = %v", err)
+		}
+		if got != "0Hf000000000001AAA" {
+			t.Errorf("InstallPackage() = %v", got)
+		}
+		if !strings.HasSuffix((*captured).URL.Path, "/tooling/sobjects/PackageInstallRequest") {
+			t.Errorf("InstallPackage() path = %v", (*captured).URL.Path)
+		}
+	})
+
+	t.Run("invalid_version_key", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "token"})
+		if _, err := sf.InstallPackage(PackageInstallRequest{SubscriberPackageVersionKey: "033"}); err == nil {
+			t.Errorf("InstallPackage() expected error for invalid version key")
+		}
+	})
+
+	t.Run("not_successful", func(t *testing.T) {
+		server, sfAuth := setupTestServer(SalesforceResult{
+			Success: false,
+			Errors:  []SalesforceErrorMessage{{Message: "invalid key", ErrorCode: "INVALID_INPUT"}},
+		}, http.StatusOK)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		_, err := sf.InstallPackage(
+			PackageInstallRequest{SubscriberPackageVersionKey: "04t000000000001AAA"},
+		)
+		if err == nil || !strings.Contains(err.Error(), "INVALID_INPUT: invalid key") {
+			t.Errorf("InstallPackage() error = %v", err)
+		}
+	})
+}
+
+func TestSalesforce_WaitForPackageInstall(t *testing.T) {
+	setupServer := func(statuses ...string) (*httptest.Server, authentication) {
+		polls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{
+				"Id":     "0Hf000000000001AAA",
+				"Status": statuses[min(polls, len(statuses)-1)],
+				"Errors": map[string]any{
+					"errors": []map[string]string{{"message": "missing dependency"}},
+				},
+			}
+			polls++
+			if err := json.NewEncoder(w).Encode(body); err != nil {
+				t.Error(err)
+			}
+		}))
+		return server, authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		server, sfAuth := setupServer(PackageInstallStatusInProgress, PackageInstallStatusSuccess)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.WaitForPackageInstall(
+			context.Background(),
+			"0Hf000000000001AAA",
+			time.Millisecond,
+		)
+		if err != nil {
+			t.Fatalf("WaitForPackageInstall() error = %v", err)
+		}
+		if got.Status != PackageInstallStatusSuccess {
+			t.Errorf("WaitForPackageInstall() = %v", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		server, sfAuth := setupServer(PackageInstallStatusError)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		_, err := sf.WaitForPackageInstall(
+			context.Background(),
+			"0Hf000000000001AAA",
+			time.Millisecond,
+		)
+		if err == nil || !strings.Contains(err.Error(), "missing dependency") {
+			t.Errorf("WaitForPackageInstall() error = %v", err)
+		}
+	})
+
+	t.Run("get_status", func(t *testing.T) {
+		server, sfAuth := setupServer(PackageInstallStatusInProgress)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		got, err := sf.GetPackageInstallStatus("0Hf000000000001AAA")
+		if err != nil {
+			t.Fatalf("GetPackageInstallStatus() error = %v", err)
+		}
+		if got.Status != PackageInstallStatusInProgress ||
+			got.ErrorMessages()[0] != "missing dependency" {
+			t.Errorf("GetPackageInstallStatus() = %v", got)
+		}
+	})
+}
+
+func TestSalesforce_GetInstalledPackages(t *testing.T) {
+	resp := queryResponse{Done: true, Records: []map[string]any{{
+		"Id":                  "0A3000000000001AAA",
+		"SubscriberPackageId": "033000000000001AAA",
+		"SubscriberPackage":   map[string]any{"Name": "Example", "NamespacePrefix": "ex"},
+		"SubscriberPackageVersion": map[string]any{
+			"Id":           "04t000000000001AAA",
+			"MajorVersion": 1,
+			"MinorVersion": 2,
+			"PatchVersion": 0,
+			"BuildNumber":  3,
+		},
+	}}}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	got, err := sf.GetInstalledPackages()
+	if err != nil {
+		t.Fatalf("GetInstalledPackages() error = %v", err)
+	}
+	if len(got) != 1 || got[0].SubscriberPackage.NamespacePrefix != "ex" ||
+		got[0].Version() != "1.2.0.3" {
+		t.Errorf("GetInstalledPackages() = %v", got)
+	}
+}
@@ -0,0 +1,294 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PagerOrder is the keyset a QueryPager orders records by
+type PagerOrder int
+
+const (
+	// PagerOrderId orders records by Id
+	PagerOrderId PagerOrder = iota
+	// PagerOrderCreatedDate orders records by CreatedDate, then by Id
+	PagerOrderCreatedDate
+)
+
+const salesforceDateTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// pagerCursor is the keyset of the last record of a page
+type pagerCursor struct {
+	id          string
+	createdDate string
+}
+
+// QueryPager pages through the results of a query without OFFSET, which Salesforce caps at 2000 rows.
+// Pages are read by keyset pagination: each page continues after the Id, or CreatedDate and Id, of the
+// last record of the previous page.
+type QueryPager struct {
+	sf       *Salesforce
+	query    string
+	pageSize int
+	order    PagerOrder
+	cursors  []pagerCursor // cursors[i] is the cursor after page i+1
+	next     int
+	done     bool
+}
+
+// NewQueryPager returns a QueryPager for a query. The query must select Id, and CreatedDate when
+// ordering by it, and cannot have ORDER BY, LIMIT, OFFSET, GROUP BY, or FOR clauses.
+func (sf *Salesforce) NewQueryPager(
+	query string,
+	pageSize int,
+	order PagerOrder,
+) (*QueryPager, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if pageSize < 1 || pageSize > 2000 {
+		return nil, errors.New("page size must be between 1 and 2000")
+	}
+	if order != PagerOrderId && order != PagerOrderCreatedDate {
+		return nil, fmt.Errorf("invalid pager order: %d", order)
+	}
+	if soqlClauseIndex(query, "FROM") < 0 {
+		return nil, errors.New("query must have a FROM clause")
+	}
+	for _, clause := range []string{"ORDER BY", "LIMIT", "OFFSET", "GROUP BY", "FOR"} {
+		if soqlClauseIndex(query, clause) >= 0 {
+			return nil, fmt.Errorf("query cannot have a %s clause", clause)
+		}
+	}
+	return &QueryPager{
+		sf:       sf,
+		query:    strings.TrimSpace(query),
+		pageSize: pageSize,
+		order:    order,
+		next:     1,
+	}, nil
+}
+
+// Next decodes the next page into sObject and returns false once there are no more records
+func (p *QueryPager) Next(sObject any) (bool, error) {
+	if p.done {
+		return false, nil
+	}
+	records, err := p.pageRecords(p.next)
+	if err != nil {
+		return false, err
+	}
+	if len(records) == 0 {
+		p.done = true
+		return false, nil
+	}
+	p.next++
+	if len(records) < p.pageSize {
+		p.done = true
+	}
+	return true, decodeQueryRecords(p.sf.config, records, sObject)
+}
+
+// Page decodes the page with the given number, starting at 1, into sObject. Pages past the last
+// visited page are reached by querying only the keys of the records before them. A page past the end
+// of the results decodes no records.
+func (p *QueryPager) Page(number int, sObject any) error {
+	if number < 1 {
+		return errors.New("page number must be at least 1")
+	}
+	records, err := p.pageRecords(number)
+	if err != nil {
+		return err
+	}
+	return decodeQueryRecords(p.sf.config, records, sObject)
+}
+
+func (p *QueryPager) pageRecords(number int) ([]map[string]any, error) {
+	if number-1 > len(p.cursors) {
+		if err := p.seek(number - 1); err != nil {
+			return nil, err
+		}
+		if number-1 > len(p.cursors) {
+			return []map[string]any{}, nil
+		}
+	}
+
+	var cursor *pagerCursor
+	if number > 1 {
+		cursor = &p.cursors[number-2]
+	}
+	records, err := queryAllRecords(
+		context.Background(),
+		p.sf,
+		p.keysetQuery(p.query, cursor, p.pageSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	last, err := p.cursor(records[len(records)-1])
+	if err != nil {
+		return nil, err
+	}
+	if number-1 == len(p.cursors) && len(records) == p.pageSize {
+		p.cursors = append(p.cursors, last)
+	}
+	return records, nil
+}
+
+// seek finds the cursors of pages up to the given page by querying only the keys of their records
+func (p *QueryPager) seek(page int) error {
+	var cursor *pagerCursor
+	if len(p.cursors) > 0 {
+		cursor = &p.cursors[len(p.cursors)-1]
+	}
+	keys := "SELECT Id"
+	if p.order == PagerOrderCreatedDate {
+		keys += ", CreatedDate"
+	}
+	keyQuery := keys + " " + p.query[soqlClauseIndex(p.query, "FROM"):]
+	missing := page - len(p.cursors)
+	records, err := queryAllRecords(
+		context.Background(),
+		p.sf,
+		p.keysetQuery(keyQuery, cursor, missing*p.pageSize),
+	)
+	if err != nil {
+		return err
+	}
+	for end := p.pageSize; end <= len(records); end += p.pageSize {
+		next, err := p.cursor(records[end-1])
+		if err != nil {
+			return err
+		}
+		p.cursors = append(p.cursors, next)
+	}
+	return nil
+}
+
+func (p *QueryPager) cursor(record map[string]any) (pagerCursor, error) {
+	id, _ := record["Id"].(string)
+	if id == "" {
+		return pagerCursor{}, errors.New("query must select Id")
+	}
+	if p.order == PagerOrderId {
+		return pagerCursor{id: id}, nil
+	}
+	createdDate, _ := record["CreatedDate"].(string)
+	created, err := time.Parse(salesforceDateTimeFormat, createdDate)
+	if err != nil {
+		return pagerCursor{}, errors.New("query must select CreatedDate")
+	}
+	return pagerCursor{id: id, createdDate: created.UTC().Format(soqlDateTimeFormat)}, nil
+}
+
+// keysetQuery adds the keyset condition, order, and limit of a page to a query
+func (p *QueryPager) keysetQuery(query string, cursor *pagerCursor, limit int) string {
+	orderBy := " ORDER BY Id"
+	if p.order == PagerOrderCreatedDate {
+		orderBy = " ORDER BY CreatedDate, Id"
+	}
+	if cursor == nil {
+		return query + orderBy + fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	condition := "Id > '" + escapeSoqlString(cursor.id) + "'"
+	if p.order == PagerOrderCreatedDate {
+		condition = fmt.Sprintf(
+			"(CreatedDate > %s OR (CreatedDate = %s AND %s))",
+			cursor.createdDate,
+			cursor.createdDate,
+			condition,
+		)
+	}
+	query = addSoqlCondition(query, condition)
+	return query + orderBy + fmt.Sprintf(" LIMIT %d", limit)
+}
+
+// addSoqlCondition adds a condition to the WHERE clause of a query with AND, or adds a WHERE clause
+// if the query has none
+func addSoqlCondition(query string, condition string) string {
+	// WITH clauses, such as WITH SECURITY_ENFORCED, follow the WHERE clause
+	end := soqlClauseIndex(query, "WITH")
+	if end < 0 {
+		end = len(query)
+	}
+	rest := strings.TrimSpace(query[end:])
+	if rest != "" {
+		rest = " " + rest
+	}
+	if where := soqlClauseIndex(query, "WHERE"); where >= 0 {
+		conditions := strings.TrimSpace(query[where+len("WHERE") : end])
+		return query[:where] + "WHERE (" + conditions + ") AND " + condition + rest
+	}
+	return strings.TrimSpace(query[:end]) + " WHERE " + condition + rest
+}
+
+// soqlClauseIndex returns the index of a clause keyword, such as WHERE or ORDER BY, in the outer query,
+// ignoring subqueries and string literals. It returns -1 if the query has no such clause.
+func soqlClauseIndex(query string, clause string) int {
+	words := strings.Fields(strings.ToUpper(clause))
+	depth := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case inString && c == '\\':
+			i++
+			continue
+		case c == '\'':
+			inString = !inString
+			continue
+		case inString:
+			continue
+		case c == '(':
+			depth++
+			continue
+		case c == ')':
+			depth--
+			continue
+		}
+		if depth != 0 || (i > 0 && isSoqlWordChar(rune(query[i-1]))) {
+			continue
+		}
+		if end, ok := matchSoqlWords(query, i, words); ok {
+			if end == len(query) || !isSoqlWordChar(rune(query[end])) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// matchSoqlWords matches keywords separated by whitespace at position i of a query and returns the
+// position after them
+func matchSoqlWords(query string, i int, words []string) (int, bool) {
+	for w, word := range words {
+		if w > 0 {
+			start := i
+			for i < len(query) && unicode.IsSpace(rune(query[i])) {
+				i++
+			}
+			if i == start {
+				return 0, false
+			}
+		}
+		if len(query)-i < len(word) || !strings.EqualFold(query[i:i+len(word)], word) {
+			return 0, false
+		}
+		i += len(word)
+	}
+	return i, true
+}
+
+func isSoqlWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
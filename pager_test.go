@@ -0,0 +1,183 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// setupPagerServer serves the responses in order and records the query of each request
+func setupPagerServer(responses ...queryResponse) (*httptest.Server, authentication, *[]string) {
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		if len(queries) > len(responses) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := json.Marshal(responses[len(queries)-1])
+		_, _ = w.Write(body)
+	}))
+	auth := authentication{InstanceUrl: server.URL, AccessToken: "accesstokenvalue"}
+	return server, auth, &queries
+}
+
+func pagerRecords(ids ...string) []map[string]any {
+	records := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		records[i] = map[string]any{"Id": id, "CreatedDate": "2024-03-01T09:30:00.000-0500"}
+	}
+	return records
+}
+
+func TestQueryPager_Next(t *testing.T) {
+	type account struct {
+		Id string
+	}
+	server, sfAuth, queries := setupPagerServer(
+		queryResponse{Done: true, Records: pagerRecords("001A", "001B")},
+		queryResponse{Done: true, Records: pagerRecords("001C")},
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	pager, err := sf.NewQueryPager(
+		"SELECT Id, Name FROM Account WHERE Industry = 'Energy'",
+		2,
+		PagerOrderId,
+	)
+	if err != nil {
+		t.Fatalf("NewQueryPager() error = %v", err)
+	}
+	var pages [][]account
+	for {
+		page := []account{}
+		ok, err := pager.Next(&page)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if !ok {
+			break
+		}
+		pages = append(pages, page)
+	}
+
+	wantPages := [][]account{{{Id: "001A"}, {Id: "001B"}}, {{Id: "001C"}}}
+	if !reflect.DeepEqual(pages, wantPages) {
+		t.Errorf("Next() pages = %v, want %v", pages, wantPages)
+	}
+	wantQueries := []string{
+		"SELECT Id, Name FROM Account WHERE Industry = 'Energy' ORDER BY Id LIMIT 2",
+		"SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id > '001B' ORDER BY Id LIMIT 2",
+	}
+	if !reflect.DeepEqual(*queries, wantQueries) {
+		t.Errorf("Next() queries = %v, want %v", *queries, wantQueries)
+	}
+}
+
+func TestQueryPager_Page(t *testing.T) {
+	type contact struct {
+		Id string
+	}
+	server, sfAuth, queries := setupPagerServer(
+		queryResponse{Done: true, Records: pagerRecords("003A", "003B", "003C", "003D")},
+		queryResponse{Done: true, Records: pagerRecords("003E", "003F")},
+		queryResponse{Done: true, Records: pagerRecords("003C", "003D")},
+		queryResponse{Done: true, Records: pagerRecords()},
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	pager, err := sf.NewQueryPager(
+		"SELECT Id, CreatedDate FROM Contact WITH SECURITY_ENFORCED",
+		2,
+		PagerOrderCreatedDate,
+	)
+	if err != nil {
+		t.Fatalf("NewQueryPager() error = %v", err)
+	}
+
+	page := []contact{}
+	if err := pager.Page(3, &page); err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if !reflect.DeepEqual(page, []contact{{Id: "003E"}, {Id: "003F"}}) {
+		t.Errorf("Page(3) = %v", page)
+	}
+	page = []contact{}
+	if err := pager.Page(2, &page); err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if !reflect.DeepEqual(page, []contact{{Id: "003C"}, {Id: "003D"}}) {
+		t.Errorf("Page(2) = %v", page)
+	}
+	page = []contact{}
+	if err := pager.Page(10, &page); err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+	if len(page) != 0 {
+		t.Errorf("Page(10) = %v, want no records", page)
+	}
+
+	after := func(id string) string {
+		return "(CreatedDate > 2024-03-01T14:30:00Z OR (CreatedDate = 2024-03-01T14:30:00Z AND Id > '" + id + "'))"
+	}
+	wantQueries := []string{
+		"SELECT Id, CreatedDate FROM Contact WITH SECURITY_ENFORCED ORDER BY CreatedDate, Id LIMIT 4",
+		"SELECT Id, CreatedDate FROM Contact WHERE " + after(
+			"003D",
+		) + " WITH SECURITY_ENFORCED ORDER BY CreatedDate, Id LIMIT 2",
+		"SELECT Id, CreatedDate FROM Contact WHERE " + after(
+			"003B",
+		) + " WITH SECURITY_ENFORCED ORDER BY CreatedDate, Id LIMIT 2",
+		"SELECT Id, CreatedDate FROM Contact WHERE " + after(
+			"003F",
+		) + " WITH SECURITY_ENFORCED ORDER BY CreatedDate, Id LIMIT 12",
+	}
+	if !reflect.DeepEqual(*queries, wantQueries) {
+		t.Errorf("Page() queries = %v, want %v", *queries, wantQueries)
+	}
+}
+
+func TestNewQueryPager(t *testing.T) {
+	sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+	tests := []struct {
+		name     string
+		query    string
+		pageSize int
+		order    PagerOrder
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			query:    "SELECT Id, (SELECT Id FROM Contacts ORDER BY Name LIMIT 5) FROM Account WHERE Name = 'LIMIT'",
+			pageSize: 50,
+		},
+		{
+			name:     "order_by",
+			query:    "SELECT Id FROM Account ORDER BY Name",
+			pageSize: 50,
+			wantErr:  true,
+		},
+		{name: "limit", query: "SELECT Id FROM Account limit 10", pageSize: 50, wantErr: true},
+		{name: "no_from", query: "SELECT Id", pageSize: 50, wantErr: true},
+		{name: "page_size", query: "SELECT Id FROM Account", pageSize: 2001, wantErr: true},
+		{
+			name:     "order",
+			query:    "SELECT Id FROM Account",
+			pageSize: 50,
+			order:    PagerOrder(5),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sf.NewQueryPager(tt.query, tt.pageSize, tt.order)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewQueryPager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
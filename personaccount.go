@@ -0,0 +1,128 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// personNameFields are the name fields of a person account, which replace the Name field of business
+// accounts
+var personNameFields = []string{"FirstName", "LastName", "MiddleName", "Salutation", "Suffix"}
+
+// personAccountContactFields maps standard Contact fields to the Account fields of a person account
+var personAccountContactFields = map[string]string{
+	"AssistantName":      "PersonAssistantName",
+	"AssistantPhone":     "PersonAssistantPhone",
+	"Birthdate":          "PersonBirthdate",
+	"Department":         "PersonDepartment",
+	"DoNotCall":          "PersonDoNotCall",
+	"Email":              "PersonEmail",
+	"HasOptedOutOfEmail": "PersonHasOptedOutOfEmail",
+	"HasOptedOutOfFax":   "PersonHasOptedOutOfFax",
+	"HomePhone":          "PersonHomePhone",
+	"LeadSource":         "PersonLeadSource",
+	"MailingCity":        "PersonMailingCity",
+	"MailingCountry":     "PersonMailingCountry",
+	"MailingPostalCode":  "PersonMailingPostalCode",
+	"MailingState":       "PersonMailingState",
+	"MailingStreet":      "PersonMailingStreet",
+	"MobilePhone":        "PersonMobilePhone",
+	"OtherCity":          "PersonOtherCity",
+	"OtherCountry":       "PersonOtherCountry",
+	"OtherPhone":         "PersonOtherPhone",
+	"OtherPostalCode":    "PersonOtherPostalCode",
+	"OtherState":         "PersonOtherState",
+	"OtherStreet":        "PersonOtherStreet",
+	"Title":              "PersonTitle",
+}
+
+// personAccountOnlyContactFields are Contact fields that are not copied to a person account, because the
+// account sets them itself
+var personAccountOnlyContactFields = []string{"Id", "AccountId", "Name", "OwnerId", "RecordTypeId"}
+
+// PersonAccountsEnabled returns whether person accounts are enabled in the org, by checking the cached
+// Account describe for the IsPersonAccount field
+func (sf *Salesforce) PersonAccountsEnabled() (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+	describe, err := describeSObject(sf, "Account")
+	if err != nil {
+		return false, err
+	}
+	_, ok := describe.Field("IsPersonAccount")
+	return ok, nil
+}
+
+// IsPersonAccount returns whether an Account record, such as a query result that selects
+// IsPersonAccount, is a person account
+func IsPersonAccount(record map[string]any) bool {
+	isPersonAccount, _ := recordField(record, "IsPersonAccount")
+	return isPersonAccount == true
+}
+
+// ContactToPersonAccount returns the Account fields of a person account for the fields of a Contact,
+// such as Email to PersonEmail and custom fields like Language__c to Language__pc. Name fields are kept,
+// and fields the account sets itself, like Id and AccountId, are dropped.
+func ContactToPersonAccount(contact map[string]any) map[string]any {
+	account := make(map[string]any, len(contact))
+	for name, value := range contact {
+		switch {
+		case name == "attributes" || slices.Contains(personAccountOnlyContactFields, name):
+			continue
+		case personAccountContactFields[name] != "":
+			account[personAccountContactFields[name]] = value
+		case strings.HasSuffix(name, "__c"):
+			account[strings.TrimSuffix(name, "__c")+"__pc"] = value
+		default:
+			account[name] = value
+		}
+	}
+	return account
+}
+
+// validatePersonAccountFields returns an error for an Account record that sets both Name and the fields
+// of person accounts, or sets IsPersonAccount, which Salesforce rejects with errors about fields that
+// appear to exist. Empty values are not considered set, since structs send every field.
+func validatePersonAccountFields(record map[string]any) error {
+	if _, ok := recordField(record, "IsPersonAccount"); ok {
+		return errors.New(
+			"cannot set IsPersonAccount, set RecordTypeId to a person account record type instead",
+		)
+	}
+	if name, _ := recordField(record, "Name"); name == nil || name == "" {
+		return nil
+	}
+	var personFields []string
+	for name, value := range record {
+		if value != nil && value != "" && isPersonAccountField(name) {
+			personFields = append(personFields, name)
+		}
+	}
+	if len(personFields) == 0 {
+		return nil
+	}
+	sort.Strings(personFields)
+	return fmt.Errorf(
+		"cannot set Name with person account fields %s, person accounts are named by FirstName and LastName",
+		strings.Join(personFields, ", "),
+	)
+}
+
+func isPersonAccountField(name string) bool {
+	for _, nameField := range personNameFields {
+		if strings.EqualFold(name, nameField) {
+			return true
+		}
+	}
+	for _, personField := range personAccountContactFields {
+		if strings.EqualFold(name, personField) {
+			return true
+		}
+	}
+	return strings.HasSuffix(strings.ToLower(name), "__pc")
+}
@@ -0,0 +1,134 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_PersonAccountsEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		describe SObjectDescribe
+		want     bool
+	}{
+		{
+			name: "enabled",
+			describe: SObjectDescribe{
+				Name:   "Account",
+				Fields: []DescribeField{{Name: "Name"}, {Name: "IsPersonAccount"}},
+			},
+			want: true,
+		},
+		{
+			name:     "disabled",
+			describe: SObjectDescribe{Name: "Account", Fields: []DescribeField{{Name: "Name"}}},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sfAuth := setupTestServer(tt.describe, http.StatusOK)
+			defer server.Close()
+			sf := buildSalesforceStruct(&sfAuth)
+
+			got, err := sf.PersonAccountsEnabled()
+			if err != nil {
+				t.Fatalf("PersonAccountsEnabled() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PersonAccountsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPersonAccount(t *testing.T) {
+	if !IsPersonAccount(map[string]any{"IsPersonAccount": true}) ||
+		IsPersonAccount(map[string]any{"IsPersonAccount": false}) ||
+		IsPersonAccount(map[string]any{"Name": "Acme"}) {
+		t.Error("IsPersonAccount() returned the wrong value")
+	}
+}
+
+func TestContactToPersonAccount(t *testing.T) {
+	got := ContactToPersonAccount(map[string]any{
+		"attributes":  map[string]any{"type": "Contact"},
+		"Id":          "003000000000001AAA",
+		"AccountId":   "001000000000001AAA",
+		"FirstName":   "Jane",
+		"LastName":    "Smith",
+		"Email":       "jane@example.com",
+		"Phone":       "555-0100",
+		"Language__c": "French",
+	})
+	want := map[string]any{
+		"FirstName":    "Jane",
+		"LastName":     "Smith",
+		"PersonEmail":  "jane@example.com",
+		"Phone":        "555-0100",
+		"Language__pc": "French",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ContactToPersonAccount() = %v, want %v", got, want)
+	}
+}
+
+func Test_validatePersonAccountFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  map[string]any
+		wantErr string
+	}{
+		{
+			name:   "business_account",
+			record: map[string]any{"Name": "Acme", "PersonnelCount__c": 5},
+		},
+		{
+			name: "person_account",
+			record: map[string]any{
+				"FirstName":   "Jane",
+				"LastName":    "Smith",
+				"PersonEmail": "a@b.c",
+			},
+		},
+		{
+			name:   "empty_name_from_struct",
+			record: map[string]any{"Name": "", "LastName": "Smith", "PersonEmail": ""},
+		},
+		{
+			name:    "name_with_person_fields",
+			record:  map[string]any{"Name": "Acme", "LastName": "Smith", "Language__pc": "French"},
+			wantErr: "Language__pc, LastName",
+		},
+		{
+			name:    "is_person_account",
+			record:  map[string]any{"LastName": "Smith", "IsPersonAccount": true},
+			wantErr: "RecordTypeId",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePersonAccountFields(tt.record)
+			if (err != nil) != (tt.wantErr != "") ||
+				(err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validatePersonAccountFields() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSalesforce_InsertOne_personAccountFields(t *testing.T) {
+	server, sfAuth, req := setupTestServerWithCapture(
+		SalesforceResult{Success: true},
+		http.StatusOK,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	_, err := sf.InsertOne("Account", map[string]any{"Name": "Acme", "LastName": "Smith"})
+	if err == nil || *req != nil {
+		t.Errorf("InsertOne() error = %v, want an error before any request", err)
+	}
+}
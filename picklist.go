@@ -0,0 +1,123 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// MasterRecordTypeId is the id of the master record type, used by objects without record types
+const MasterRecordTypeId = "012000000000000AAA"
+
+// PicklistValue is a single picklist entry
+type PicklistValue struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	ValidFor []int  `json:"validFor"` // indexes of the controlling values this value is valid for
+}
+
+// PicklistValues are the values of a picklist field that are available for a record type
+type PicklistValues struct {
+	ControllerValues map[string]int  `json:"controllerValues"`
+	DefaultValue     *PicklistValue  `json:"defaultValue"`
+	Values           []PicklistValue `json:"values"`
+}
+
+// ValuesFor returns the values of a dependent picklist that are valid for the given controlling value
+func (p PicklistValues) ValuesFor(controllerValue string) []PicklistValue {
+	index, ok := p.ControllerValues[controllerValue]
+	if !ok {
+		return nil
+	}
+	values := []PicklistValue{}
+	for _, value := range p.Values {
+		if slices.Contains(value.ValidFor, index) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+type picklistValuesResponse struct {
+	PicklistFieldValues map[string]PicklistValues `json:"picklistFieldValues"`
+}
+
+func validatePicklistArgs(sObjectName string, recordTypeId string) error {
+	if sObjectName == "" {
+		return errors.New("sObject name cannot be empty")
+	}
+	if recordTypeId == "" {
+		return errors.New(
+			"record type id cannot be empty, use MasterRecordTypeId for the master record type",
+		)
+	}
+	return nil
+}
+
+// GetPicklistValues returns the values of every picklist field of an sObject for a record type, keyed by
+// field name, using the UI API
+func (sf *Salesforce) GetPicklistValues(
+	sObjectName string,
+	recordTypeId string,
+) (map[string]PicklistValues, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if err := validatePicklistArgs(sObjectName, recordTypeId); err != nil {
+		return nil, err
+	}
+
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method: http.MethodGet,
+		uri: "/ui-api/object-info/" + url.PathEscape(sObjectName) +
+			"/picklist-values/" + url.PathEscape(recordTypeId),
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	picklists := picklistValuesResponse{}
+	if err := decodeJSONResponse(resp, &picklists); err != nil {
+		return nil, err
+	}
+	return picklists.PicklistFieldValues, nil
+}
+
+// GetPicklistValuesForField returns the values of a single picklist field for a record type
+func (sf *Salesforce) GetPicklistValuesForField(
+	sObjectName string,
+	recordTypeId string,
+	fieldName string,
+) (PicklistValues, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return PicklistValues{}, authErr
+	}
+	if err := validatePicklistArgs(sObjectName, recordTypeId); err != nil {
+		return PicklistValues{}, err
+	}
+	if fieldName == "" {
+		return PicklistValues{}, errors.New("field name cannot be empty")
+	}
+
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method: http.MethodGet,
+		uri: "/ui-api/object-info/" + url.PathEscape(sObjectName) +
+			"/picklist-values/" + url.PathEscape(recordTypeId) + "/" + url.PathEscape(fieldName),
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return PicklistValues{}, err
+	}
+
+	picklist := PicklistValues{}
+	if err := decodeJSONResponse(resp, &picklist); err != nil {
+		return PicklistValues{}, err
+	}
+	return picklist, nil
+}
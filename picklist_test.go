@@ -0,0 +1,186 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testPicklistValues = PicklistValues{
+	ControllerValues: map[string]int{"Agriculture": 0, "Banking": 1},
+	DefaultValue:     nil,
+	Values: []PicklistValue{
+		{Label: "Farming", Value: "Farming", ValidFor: []int{0}},
+		{Label: "Lending", Value: "Lending", ValidFor: []int{1}},
+		{Label: "Consulting", Value: "Consulting", ValidFor: []int{0, 1}},
+	},
+}
+
+func TestPicklistValues_ValuesFor(t *testing.T) {
+	tests := []struct {
+		name            string
+		controllerValue string
+		want            []PicklistValue
+	}{
+		{
+			name:            "values_for_agriculture",
+			controllerValue: "Agriculture",
+			want: []PicklistValue{
+				testPicklistValues.Values[0],
+				testPicklistValues.Values[2],
+			},
+		},
+		{
+			name:            "values_for_banking",
+			controllerValue: "Banking",
+			want: []PicklistValue{
+				testPicklistValues.Values[1],
+				testPicklistValues.Values[2],
+			},
+		},
+		{
+			name:            "unknown_controller_value",
+			controllerValue: "Unknown",
+			want:            nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testPicklistValues.ValuesFor(tt.controllerValue); !reflect.DeepEqual(
+				got,
+				tt.want,
+			) {
+				t.Errorf("PicklistValues.ValuesFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetPicklistValues(t *testing.T) {
+	resp := picklistValuesResponse{
+		PicklistFieldValues: map[string]PicklistValues{"SubIndustry__c": testPicklistValues},
+	}
+	server, sfAuth, capturedRequest := setupTestServerWithCapture(resp, http.StatusOK)
+	defer server.Close()
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name         string
+		auth         *authentication
+		sObjectName  string
+		recordTypeId string
+		want         map[string]PicklistValues
+		wantErr      bool
+	}{
+		{
+			name:         "get_picklist_values",
+			auth:         &sfAuth,
+			sObjectName:  "Account",
+			recordTypeId: MasterRecordTypeId,
+			want:         resp.PicklistFieldValues,
+			wantErr:      false,
+		},
+		{
+			name:         "missing_record_type",
+			auth:         &sfAuth,
+			sObjectName:  "Account",
+			recordTypeId: "",
+			wantErr:      true,
+		},
+		{
+			name:         "bad_request",
+			auth:         &badSfAuth,
+			sObjectName:  "Account",
+			recordTypeId: MasterRecordTypeId,
+			wantErr:      true,
+		},
+		{
+			name:         "validation_fail_auth",
+			auth:         nil,
+			sObjectName:  "Account",
+			recordTypeId: MasterRecordTypeId,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			got, err := sf.GetPicklistValues(tt.sObjectName, tt.recordTypeId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.GetPicklistValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.GetPicklistValues() = %v, want %v", got, tt.want)
+			}
+			wantUri := "/services/data/" + apiVersion + "/ui-api/object-info/Account/picklist-values/" + MasterRecordTypeId
+			if (*capturedRequest).RequestURI != wantUri {
+				t.Errorf(
+					"Salesforce.GetPicklistValues() uri = %v, want %v",
+					(*capturedRequest).RequestURI,
+					wantUri,
+				)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetPicklistValuesForField(t *testing.T) {
+	server, sfAuth, capturedRequest := setupTestServerWithCapture(testPicklistValues, http.StatusOK)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		auth      *authentication
+		fieldName string
+		want      PicklistValues
+		wantErr   bool
+	}{
+		{
+			name:      "get_field_picklist_values",
+			auth:      &sfAuth,
+			fieldName: "SubIndustry__c",
+			want:      testPicklistValues,
+			wantErr:   false,
+		},
+		{
+			name:      "missing_field_name",
+			auth:      &sfAuth,
+			fieldName: "",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			got, err := sf.GetPicklistValuesForField("Account", "012xx0000000001AAA", tt.fieldName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf(
+					"Salesforce.GetPicklistValuesForField() error = %v, wantErr %v",
+					err,
+					tt.wantErr,
+				)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.GetPicklistValuesForField() = %v, want %v", got, tt.want)
+			}
+			wantUri := "/services/data/" + apiVersion + "/ui-api/object-info/Account/picklist-values/012xx0000000001AAA/SubIndustry__c"
+			if (*capturedRequest).RequestURI != wantUri {
+				t.Errorf(
+					"Salesforce.GetPicklistValuesForField() uri = %v, want %v",
+					(*capturedRequest).RequestURI,
+					wantUri,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Ping checks that Salesforce is reachable and the session is valid with a HEAD request to the limits
+// resource, which returns no body. It returns the round trip latency of the request. An expired
+// session is refreshed once, as with other requests, when the auth flow supports it. Use it in health
+// checks, such as Kubernetes readiness probes.
+func (sf *Salesforce) Ping(ctx context.Context) (time.Duration, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return 0, authErr
+	}
+
+	latency, status, err := ping(ctx, sf)
+	if err != nil {
+		return latency, err
+	}
+	if status == http.StatusUnauthorized {
+		if refreshErr := refreshSession(sf.auth, sf.config); refreshErr != nil {
+			return latency, fmt.Errorf("ping: invalid session: %w", refreshErr)
+		}
+		latency, status, err = ping(ctx, sf)
+		if err != nil {
+			return latency, err
+		}
+	}
+	if status < 200 || status > 299 {
+		return latency, fmt.Errorf("ping: %d %s", status, http.StatusText(status))
+	}
+	return latency, nil
+}
+
+// ping sends a single HEAD request to the limits resource and returns its latency and status code
+func ping(ctx context.Context, sf *Salesforce) (time.Duration, int, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodHead,
+		buildEndpoint(sf.auth, sf.config, requestPayload{uri: "/limits"}),
+		nil,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Authorization", "Bearer "+sf.auth.AccessToken)
+
+	start := time.Now()
+	resp, err := sf.config.httpClient.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, 0, err
+	}
+	_ = resp.Body.Close()
+	sf.config.apiUsage.update(resp.Header.Get(limitInfoHeader))
+	return latency, resp.StatusCode, nil
+}
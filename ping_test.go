@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSalesforce_Ping(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "healthy", status: http.StatusOK},
+		{name: "invalid_session", status: http.StatusUnauthorized, wantErr: true},
+		{name: "unavailable", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != http.MethodHead ||
+						r.URL.Path != "/services/data/"+apiVersion+"/limits" {
+						t.Errorf("Ping() sent %s %s", r.Method, r.URL.Path)
+					}
+					if r.Header.Get("Authorization") != "Bearer 1234" {
+						t.Errorf("Ping() sent no access token")
+					}
+					w.Header().Set(limitInfoHeader, "api-usage=25/15000")
+					w.WriteHeader(tt.status)
+				}),
+			)
+			defer server.Close()
+			sf := buildSalesforceStruct(
+				&authentication{InstanceUrl: server.URL, AccessToken: "1234"},
+			)
+
+			latency, err := sf.Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if latency <= 0 {
+				t.Errorf("Ping() latency = %v, want a positive duration", latency)
+			}
+			if usage := sf.GetAPIUsage(); usage.Used != 25 {
+				t.Errorf("GetAPIUsage() = %v after Ping()", usage)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+		if _, err := sf.Ping(context.Background()); err == nil {
+			t.Error("Ping() expected an error")
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// QueryPKChunked extracts the records of a large query in parallel by splitting it into Id ranges,
+// known as PK chunking. The Ids of the matching records are read first to find range boundaries of
+// chunkSize records, then workers query the ranges concurrently and send their records to the
+// returned channel, in no particular order. The record channel must be drained. Both channels are
+// closed once every range is read, and at most one error is sent, after which remaining ranges stop.
+func (sf *Salesforce) QueryPKChunked(
+	ctx context.Context,
+	query string,
+	chunkSize int,
+	workers int,
+) (<-chan map[string]any, <-chan error) {
+	records := make(chan map[string]any)
+	errs := make(chan error, 1)
+
+	fail := func(err error) (<-chan map[string]any, <-chan error) {
+		close(records)
+		errs <- err
+		close(errs)
+		return records, errs
+	}
+	if authErr := validateAuth(*sf); authErr != nil {
+		return fail(authErr)
+	}
+	if chunkSize < 1 {
+		return fail(errors.New("chunk size must be greater than 0"))
+	}
+	if workers < 1 {
+		return fail(errors.New("workers must be greater than 0"))
+	}
+	if soqlClauseIndex(query, "FROM") < 0 {
+		return fail(errors.New("query must have a FROM clause"))
+	}
+	for _, clause := range []string{"ORDER BY", "LIMIT", "OFFSET", "GROUP BY", "FOR"} {
+		if soqlClauseIndex(query, clause) >= 0 {
+			return fail(fmt.Errorf("query cannot have a %s clause", clause))
+		}
+	}
+
+	go extractPKChunks(ctx, sf, query, chunkSize, workers, records, errs)
+	return records, errs
+}
+
+func extractPKChunks(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	chunkSize int,
+	workers int,
+	records chan<- map[string]any,
+	errs chan<- error,
+) {
+	defer close(records)
+	defer close(errs)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	boundaries, err := pkChunkBoundaries(ctx, sf, query, chunkSize)
+	if err != nil {
+		errs <- err
+		return
+	}
+
+	chunks := make(chan string)
+	var once sync.Once
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunkQuery := range chunks {
+				if err := sendQueryRecords(ctx, sf, chunkQuery, records); err != nil {
+					once.Do(func() {
+						errs <- err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, chunkQuery := range pkChunkQueries(query, boundaries) {
+		select {
+		case chunks <- chunkQuery:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		once.Do(func() {
+			errs <- ctx.Err()
+		})
+	}
+}
+
+// pkChunkBoundaries returns the Id of every chunkSize-th record matching the query, in Id order
+func pkChunkBoundaries(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	chunkSize int,
+) ([]string, error) {
+	idQuery := "SELECT Id " + query[soqlClauseIndex(query, "FROM"):] + " ORDER BY Id"
+	var boundaries []string
+	count := 0
+	nextRecordsUrl := "/query/?q=" + url.QueryEscape(idQuery)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, sf, nextRecordsUrl)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range queryResp.Records {
+			if count > 0 && count%chunkSize == 0 {
+				id, _ := record["Id"].(string)
+				boundaries = append(boundaries, id)
+			}
+			count++
+		}
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
+	return boundaries, nil
+}
+
+// pkChunkQueries returns a query for each Id range between the boundaries
+func pkChunkQueries(query string, boundaries []string) []string {
+	if len(boundaries) == 0 {
+		return []string{query}
+	}
+	queries := make([]string, 0, len(boundaries)+1)
+	for i := 0; i <= len(boundaries); i++ {
+		var condition string
+		switch i {
+		case 0:
+			condition = "Id < '" + escapeSoqlString(boundaries[i]) + "'"
+		case len(boundaries):
+			condition = "Id >= '" + escapeSoqlString(boundaries[i-1]) + "'"
+		default:
+			condition = "Id >= '" + escapeSoqlString(boundaries[i-1]) +
+				"' AND Id < '" + escapeSoqlString(boundaries[i]) + "'"
+		}
+		queries = append(queries, addSoqlCondition(query, condition))
+	}
+	return queries
+}
+
+// sendQueryRecords sends every record of a query to records
+func sendQueryRecords(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	records chan<- map[string]any,
+) error {
+	nextRecordsUrl := "/query/?q=" + url.QueryEscape(query)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, sf, nextRecordsUrl)
+		if err != nil {
+			return err
+		}
+		for _, record := range queryResp.Records {
+			select {
+			case records <- record:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
+	return nil
+}
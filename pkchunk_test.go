@@ -0,0 +1,146 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_pkChunkQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		boundaries []string
+		want       []string
+	}{
+		{
+			name:  "single_chunk",
+			query: "SELECT Id FROM Account",
+			want:  []string{"SELECT Id FROM Account"},
+		},
+		{
+			name:       "ranges",
+			query:      "SELECT Id, Name FROM Account WHERE Industry = 'Energy'",
+			boundaries: []string{"001B", "001D"},
+			want: []string{
+				"SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id < '001B'",
+				"SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id >= '001B' AND Id < '001D'",
+				"SELECT Id, Name FROM Account WHERE (Industry = 'Energy') AND Id >= '001D'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pkChunkQueries(tt.query, tt.boundaries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pkChunkQueries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryPKChunked(t *testing.T) {
+	ids := []string{"001A", "001B", "001C", "001D", "001E"}
+
+	t.Run("extracts_all_ranges", func(t *testing.T) {
+		var mu sync.Mutex
+		queries := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query().Get("q")
+			mu.Lock()
+			queries = append(queries, query)
+			mu.Unlock()
+
+			var records []map[string]any
+			for _, id := range ids {
+				lower := strings.Contains(query, "Id >= '001C'") && id < "001C" ||
+					strings.Contains(query, "Id >= '001E'") && id < "001E"
+				upper := strings.Contains(query, "Id < '001C'") && id >= "001C" ||
+					strings.Contains(query, "Id < '001E'") && id >= "001E"
+				if !lower && !upper {
+					records = append(records, map[string]any{"Id": id})
+				}
+			}
+			body, _ := json.Marshal(
+				queryResponse{TotalSize: len(records), Done: true, Records: records},
+			)
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+		sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+		records, errs := sf.QueryPKChunked(
+			context.Background(),
+			"SELECT Id, Name FROM Account",
+			2,
+			2,
+		)
+		var got []string
+		for record := range records {
+			got = append(got, record["Id"].(string))
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("QueryPKChunked() error = %v", err)
+		}
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("QueryPKChunked() ids = %v, want %v", got, ids)
+		}
+		if len(queries) != 4 || queries[0] != "SELECT Id FROM Account ORDER BY Id" {
+			t.Errorf("QueryPKChunked() queries = %v", queries)
+		}
+	})
+
+	t.Run("chunk_error", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests > 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			records := []map[string]any{{"Id": "001A"}, {"Id": "001B"}}
+			body, _ := json.Marshal(queryResponse{TotalSize: 2, Done: true, Records: records})
+			_, _ = w.Write(body)
+		}))
+		defer server.Close()
+		sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+		records, errs := sf.QueryPKChunked(context.Background(), "SELECT Id FROM Account", 1, 1)
+		for range records {
+		}
+		if err := <-errs; err == nil {
+			t.Errorf("QueryPKChunked() expected error")
+		}
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		for _, args := range []struct {
+			query     string
+			chunkSize int
+			workers   int
+		}{
+			{query: "SELECT Id FROM Account", chunkSize: 0, workers: 1},
+			{query: "SELECT Id FROM Account", chunkSize: 10, workers: 0},
+			{query: "SELECT Id FROM Account LIMIT 10", chunkSize: 10, workers: 1},
+		} {
+			records, errs := sf.QueryPKChunked(
+				context.Background(),
+				args.query,
+				args.chunkSize,
+				args.workers,
+			)
+			for range records {
+			}
+			if err := <-errs; err == nil {
+				t.Errorf("QueryPKChunked(%v) expected error", args)
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package salesforce
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// PolymorphicRecord is a related record of a polymorphic relationship, such as Owner, What, or Who,
+// whose sObject type is only known once the record is returned. Use it as the type of a relationship
+// field and read the record with Decode or Value.
+type PolymorphicRecord struct {
+	Type   string         // sObject type of the record, from its attributes
+	Fields map[string]any // fields of the record
+}
+
+var polymorphicTypes = struct {
+	mu    sync.RWMutex
+	types map[string][]reflect.Type
+}{types: map[string][]reflect.Type{}}
+
+// RegisterPolymorphicType registers the Go type that records of an sObject type decode into when they
+// are the value of a polymorphic relationship. Relationship fields declared with an interface type,
+// such as Who with a Contact and a Lead implementation, are decoded into the registered type of the
+// record's sObject type that implements the interface. Register types once, such as in an init function.
+func RegisterPolymorphicType(sObjectName string, value any) {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(
+			fmt.Sprintf(
+				"salesforce: polymorphic type of %s must be a struct, got %T",
+				sObjectName,
+				value,
+			),
+		)
+	}
+
+	polymorphicTypes.mu.Lock()
+	defer polymorphicTypes.mu.Unlock()
+	for _, registered := range polymorphicTypes.types[sObjectName] {
+		if registered == t {
+			return
+		}
+	}
+	polymorphicTypes.types[sObjectName] = append(polymorphicTypes.types[sObjectName], t)
+}
+
+// Decode decodes the record's fields into out, which must be a pointer
+func (r PolymorphicRecord) Decode(out any) error {
+	return mapstructureDecode(r.Fields, out)
+}
+
+// Value decodes the record into the first type registered for its sObject type, see
+// RegisterPolymorphicType. The value is returned as a pointer to the registered type.
+func (r PolymorphicRecord) Value() (any, error) {
+	polymorphicTypes.mu.RLock()
+	types := polymorphicTypes.types[r.Type]
+	polymorphicTypes.mu.RUnlock()
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no polymorphic type registered for %s", r.Type)
+	}
+
+	value := reflect.New(types[0])
+	if err := r.Decode(value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+// recordType returns the sObject type of a record from its attributes
+func recordType(record map[string]any) string {
+	attributes, ok := record["attributes"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	sObjectType, _ := attributes["type"].(string)
+	return sObjectType
+}
+
+// polymorphicDecodeHook decodes related records into PolymorphicRecord fields, and into the type
+// registered for their sObject type when the field is an interface
+func polymorphicDecodeHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	record, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+	if to == reflect.TypeOf(PolymorphicRecord{}) {
+		return PolymorphicRecord{Type: recordType(record), Fields: record}, nil
+	}
+	if to.Kind() != reflect.Interface || to.NumMethod() == 0 {
+		return data, nil
+	}
+
+	sObjectType := recordType(record)
+	if sObjectType == "" {
+		return data, nil
+	}
+	polymorphicTypes.mu.RLock()
+	types := polymorphicTypes.types[sObjectType]
+	polymorphicTypes.mu.RUnlock()
+	for _, t := range types {
+		if !t.Implements(to) && !reflect.PointerTo(t).Implements(to) {
+			continue
+		}
+		value := reflect.New(t)
+		if err := mapstructureDecode(record, value.Interface()); err != nil {
+			return nil, err
+		}
+		if t.Implements(to) {
+			return value.Elem().Interface(), nil
+		}
+		return value.Interface(), nil
+	}
+	return nil, fmt.Errorf(
+		"no polymorphic type registered for %s that implements %s",
+		sObjectType,
+		to,
+	)
+}
@@ -0,0 +1,171 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type testWho interface {
+	whoName() string
+}
+
+type testWhoContact struct {
+	Id       string
+	LastName string
+}
+
+func (c testWhoContact) whoName() string { return c.LastName }
+
+type testWhoLead struct {
+	Id      string
+	Company string
+}
+
+func (l *testWhoLead) whoName() string { return l.Company }
+
+func init() {
+	RegisterPolymorphicType("Contact", testWhoContact{})
+	RegisterPolymorphicType("Lead", &testWhoLead{})
+}
+
+func TestQuery_polymorphic(t *testing.T) {
+	type task struct {
+		Id    string
+		Who   testWho
+		Owner PolymorphicRecord
+		What  *PolymorphicRecord
+	}
+
+	resp := queryResponse{
+		TotalSize: 2,
+		Done:      true,
+		Records: []map[string]any{
+			{
+				"Id": "00T000000000001AAA",
+				"Who": map[string]any{
+					"attributes": map[string]any{"type": "Contact"},
+					"Id":         "003000000000001AAA",
+					"LastName":   "Stark",
+				},
+				"Owner": map[string]any{
+					"attributes": map[string]any{"type": "User"},
+					"Id":         "005000000000001AAA",
+					"Name":       "Tony",
+				},
+				"What": nil,
+			},
+			{
+				"Id": "00T000000000002AAA",
+				"Who": map[string]any{
+					"attributes": map[string]any{"type": "Lead"},
+					"Id":         "00Q000000000001AAA",
+					"Company":    "Acme",
+				},
+				"Owner": map[string]any{
+					"attributes": map[string]any{"type": "Group"},
+					"Id":         "00G000000000001AAA",
+					"Name":       "Queue",
+				},
+				"What": map[string]any{
+					"attributes": map[string]any{"type": "Account"},
+					"Id":         "001000000000001AAA",
+				},
+			},
+		},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	tasks := []task{}
+	err := sf.Query(
+		"SELECT Id, TYPEOF Who WHEN Contact THEN Id, LastName WHEN Lead THEN Id, Company END, Owner.Name, What.Id FROM Task",
+		&tasks,
+	)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Query() returned %d records, want 2", len(tasks))
+	}
+
+	if got, ok := tasks[0].Who.(testWhoContact); !ok || got.LastName != "Stark" {
+		t.Errorf("Query() Who = %#v, want Contact", tasks[0].Who)
+	}
+	if got, ok := tasks[1].Who.(*testWhoLead); !ok || got.whoName() != "Acme" {
+		t.Errorf("Query() Who = %#v, want Lead", tasks[1].Who)
+	}
+	if tasks[0].Owner.Type != "User" || tasks[1].Owner.Type != "Group" {
+		t.Errorf("Query() Owner types = %v, %v", tasks[0].Owner.Type, tasks[1].Owner.Type)
+	}
+	if tasks[0].What != nil || tasks[1].What == nil || tasks[1].What.Type != "Account" {
+		t.Errorf("Query() What = %v, %v", tasks[0].What, tasks[1].What)
+	}
+
+	type owner struct {
+		Id   string
+		Name string
+	}
+	var user owner
+	if err := tasks[0].Owner.Decode(&user); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if user != (owner{Id: "005000000000001AAA", Name: "Tony"}) {
+		t.Errorf("Decode() = %v", user)
+	}
+}
+
+func TestQuery_polymorphicUnregistered(t *testing.T) {
+	type task struct {
+		Who testWho
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{
+			{
+				"Who": map[string]any{
+					"attributes": map[string]any{"type": "User"},
+					"Id":         "005000000000001AAA",
+				},
+			},
+		},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	tasks := []task{}
+	if err := sf.Query("SELECT Who.Id FROM Task", &tasks); err == nil {
+		t.Errorf("Query() expected error for unregistered type")
+	}
+}
+
+func TestPolymorphicRecord_Value(t *testing.T) {
+	record := PolymorphicRecord{
+		Type:   "Contact",
+		Fields: map[string]any{"Id": "003000000000001AAA", "LastName": "Stark"},
+	}
+	got, err := record.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	want := &testWhoContact{Id: "003000000000001AAA", LastName: "Stark"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Value() = %#v, want %#v", got, want)
+	}
+
+	if _, err := (PolymorphicRecord{Type: "Unregistered__c"}).Value(); err == nil {
+		t.Errorf("Value() expected error for unregistered type")
+	}
+}
+
+func TestRegisterPolymorphicType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterPolymorphicType() expected panic for non-struct type")
+		}
+	}()
+	RegisterPolymorphicType("Contact", "not a struct")
+}
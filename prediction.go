@@ -0,0 +1,243 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	predictUri                = "/smartdatadiscovery/predict"
+	predictionDefinitionsUri  = "/smartdatadiscovery/predictionDefinitions"
+	predictionRecordsMax      = 200
+	predictionTypeRecords     = "Records"
+	predictionTypeRawData     = "RawData"
+	predictionStatusSucceeded = "Success"
+)
+
+// PredictionSettings tunes the explanations returned with predictions. Zero values use the defaults of
+// the prediction definition.
+type PredictionSettings struct {
+	MaxPrescriptions             int `json:"maxPrescriptions,omitempty"` // improvements to suggest
+	MaxMiddleValues              int `json:"maxMiddleValues,omitempty"`  // top factors to explain
+	PrescriptionImpactPercentage int `json:"prescriptionImpactPercentage,omitempty"`
+}
+
+// PredictionColumn is a field of a predicted record and its value
+type PredictionColumn struct {
+	ColumnName  string `json:"columnName"`
+	ColumnValue string `json:"columnValue"`
+}
+
+// PredictionFactor is a combination of field values and its contribution to a prediction, or, as a
+// prescription, the improvement of changing the fields to these values
+type PredictionFactor struct {
+	Columns []PredictionColumn `json:"columns"`
+	Value   float64            `json:"value"`
+}
+
+// Prediction is the score of a record or row by an Einstein Discovery model
+type Prediction struct {
+	ModelId       string
+	Status        string  // Success, or the reason the record could not be scored
+	Total         float64 // predicted outcome
+	BaseLine      float64 // average outcome the factors are relative to
+	Other         float64 // contribution of factors not in MiddleValues
+	MiddleValues  []PredictionFactor
+	Prescriptions []PredictionFactor
+}
+
+// Succeeded returns whether the record or row was scored
+func (p Prediction) Succeeded() bool {
+	return p.Status == predictionStatusSucceeded
+}
+
+// PredictionDefinition is an Einstein Discovery prediction definition, which predicts an outcome field
+// of an sObject with its active models
+type PredictionDefinition struct {
+	Id                  string `json:"id"`
+	Name                string `json:"name"`
+	Label               string `json:"label"`
+	Status              string `json:"status"` // Enabled or Disabled
+	CountOfActiveModels int    `json:"countOfActiveModels"`
+	Outcome             struct {
+		Name  string `json:"name"`  // field that is predicted
+		Label string `json:"label"` // label of the field that is predicted
+		Goal  string `json:"goal"`  // Maximize or Minimize
+	} `json:"outcome"`
+}
+
+type predictionRequest struct {
+	PredictionDefinition string              `json:"predictionDefinition"`
+	Type                 string              `json:"type"`
+	Records              []string            `json:"records,omitempty"`
+	ColumnNames          []string            `json:"columnNames,omitempty"`
+	Rows                 [][]string          `json:"rows,omitempty"`
+	Settings             *PredictionSettings `json:"settings,omitempty"`
+}
+
+type predictionResponse struct {
+	Predictions []struct {
+		Model struct {
+			Id string `json:"id"`
+		} `json:"model"`
+		Prediction struct {
+			Total        float64            `json:"total"`
+			BaseLine     float64            `json:"baseLine"`
+			Other        float64            `json:"other"`
+			MiddleValues []PredictionFactor `json:"middleValues"`
+		} `json:"prediction"`
+		Prescriptions []PredictionFactor `json:"prescriptions"`
+		Status        string             `json:"status"`
+	} `json:"predictions"`
+}
+
+type predictionDefinitionsResponse struct {
+	PredictionDefinitions []PredictionDefinition `json:"predictionDefinitions"`
+	NextPageUrl           string                 `json:"nextPageUrl"`
+}
+
+// Predict scores up to 200 records with an Einstein Discovery prediction definition, returning a
+// prediction for each record in order. Records that cannot be scored are reported in their Status
+// rather than as an error.
+func (sf *Salesforce) Predict(
+	ctx context.Context,
+	predictionDefinitionId string,
+	recordIds []string,
+	settings PredictionSettings,
+) ([]Prediction, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if len(recordIds) == 0 || len(recordIds) > predictionRecordsMax {
+		return nil, fmt.Errorf("between 1 and %d record ids are required", predictionRecordsMax)
+	}
+	for _, id := range recordIds {
+		if !IsValidId(id) {
+			return nil, fmt.Errorf("invalid id: %q", id)
+		}
+	}
+	return predict(ctx, sf, predictionRequest{
+		PredictionDefinition: predictionDefinitionId,
+		Type:                 predictionTypeRecords,
+		Records:              recordIds,
+	}, settings)
+}
+
+// PredictRaw scores up to 200 rows of field values that are not saved as records, such as records
+// being edited, with an Einstein Discovery prediction definition. Each row has a value for each of
+// the column names, which are field API names, and a prediction is returned for each row in order.
+func (sf *Salesforce) PredictRaw(
+	ctx context.Context,
+	predictionDefinitionId string,
+	columnNames []string,
+	rows [][]string,
+	settings PredictionSettings,
+) ([]Prediction, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if len(columnNames) == 0 {
+		return nil, errors.New("column names are required")
+	}
+	if len(rows) == 0 || len(rows) > predictionRecordsMax {
+		return nil, fmt.Errorf("between 1 and %d rows are required", predictionRecordsMax)
+	}
+	for i, row := range rows {
+		if len(row) != len(columnNames) {
+			return nil, fmt.Errorf(
+				"row %d has %d values, want one for each of the %d columns",
+				i,
+				len(row),
+				len(columnNames),
+			)
+		}
+	}
+	return predict(ctx, sf, predictionRequest{
+		PredictionDefinition: predictionDefinitionId,
+		Type:                 predictionTypeRawData,
+		ColumnNames:          columnNames,
+		Rows:                 rows,
+	}, settings)
+}
+
+// GetPredictionDefinitions returns the Einstein Discovery prediction definitions of the org
+func (sf *Salesforce) GetPredictionDefinitions(
+	ctx context.Context,
+) ([]PredictionDefinition, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	definitions := []PredictionDefinition{}
+	uri := predictionDefinitionsUri
+	for uri != "" {
+		resp, err := doRequest(sf.auth, sf.config, requestPayload{
+			ctx:      ctx,
+			method:   http.MethodGet,
+			uri:      uri,
+			content:  jsonType,
+			compress: sf.config.compressionHeaders,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := predictionDefinitionsResponse{}
+		if err := decodeJSONResponse(resp, &page); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, page.PredictionDefinitions...)
+		uri = connectNextPageUri(sf, page.NextPageUrl)
+	}
+	return definitions, nil
+}
+
+func predict(
+	ctx context.Context,
+	sf *Salesforce,
+	request predictionRequest,
+	settings PredictionSettings,
+) ([]Prediction, error) {
+	if request.PredictionDefinition == "" {
+		return nil, errors.New("prediction definition id is required")
+	}
+	if settings != (PredictionSettings{}) {
+		request.Settings = &settings
+	}
+	body, err := marshalJSON(sf.config, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodPost,
+		uri:      predictUri,
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	predictionResp := predictionResponse{}
+	if err := decodeJSONResponse(resp, &predictionResp); err != nil {
+		return nil, err
+	}
+
+	predictions := make([]Prediction, len(predictionResp.Predictions))
+	for i, result := range predictionResp.Predictions {
+		predictions[i] = Prediction{
+			ModelId:       result.Model.Id,
+			Status:        result.Status,
+			Total:         result.Prediction.Total,
+			BaseLine:      result.Prediction.BaseLine,
+			Other:         result.Prediction.Other,
+			MiddleValues:  result.Prediction.MiddleValues,
+			Prescriptions: result.Prescriptions,
+		}
+	}
+	return predictions, nil
+}
@@ -0,0 +1,175 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_Predict(t *testing.T) {
+	var requests []predictionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := predictionRequest{}
+		_ = json.Unmarshal(body, &request)
+		requests = append(requests, request)
+		if r.URL.Path != "/services/data/"+apiVersion+predictUri {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"predictions": [{
+			"model": {"id": "1Ot000000000001AAA"},
+			"prediction": {
+				"total": 42.5,
+				"baseLine": 30,
+				"other": 2.5,
+				"middleValues": [{"columns": [{"columnName": "Industry", "columnValue": "Retail"}], "value": 10}]
+			},
+			"prescriptions": [{"columns": [{"columnName": "Rating", "columnValue": "Hot"}], "value": 5}],
+			"status": "Success"
+		}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	want := []Prediction{{
+		ModelId:  "1Ot000000000001AAA",
+		Status:   "Success",
+		Total:    42.5,
+		BaseLine: 30,
+		Other:    2.5,
+		MiddleValues: []PredictionFactor{{
+			Columns: []PredictionColumn{{ColumnName: "Industry", ColumnValue: "Retail"}},
+			Value:   10,
+		}},
+		Prescriptions: []PredictionFactor{{
+			Columns: []PredictionColumn{{ColumnName: "Rating", ColumnValue: "Hot"}},
+			Value:   5,
+		}},
+	}}
+	predictions, err := sf.Predict(
+		context.Background(),
+		"1OR000000000001AAA",
+		[]string{"001000000000001AAA"},
+		PredictionSettings{MaxPrescriptions: 3},
+	)
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if !reflect.DeepEqual(predictions, want) || !predictions[0].Succeeded() {
+		t.Errorf("Predict() = %+v, want %+v", predictions, want)
+	}
+	wantRequest := predictionRequest{
+		PredictionDefinition: "1OR000000000001AAA",
+		Type:                 predictionTypeRecords,
+		Records:              []string{"001000000000001AAA"},
+		Settings:             &PredictionSettings{MaxPrescriptions: 3},
+	}
+	if !reflect.DeepEqual(requests[0], wantRequest) {
+		t.Errorf("Predict() request = %+v, want %+v", requests[0], wantRequest)
+	}
+
+	_, err = sf.PredictRaw(
+		context.Background(),
+		"1OR000000000001AAA",
+		[]string{"Industry", "Rating"},
+		[][]string{{"Retail", "Cold"}},
+		PredictionSettings{},
+	)
+	if err != nil {
+		t.Fatalf("PredictRaw() error = %v", err)
+	}
+	if requests[1].Type != predictionTypeRawData || requests[1].Settings != nil ||
+		!reflect.DeepEqual(requests[1].Rows, [][]string{{"Retail", "Cold"}}) {
+		t.Errorf("PredictRaw() request = %+v", requests[1])
+	}
+
+	invalid := []struct {
+		name string
+		call func() error
+	}{
+		{name: "no_records", call: func() error {
+			_, err := sf.Predict(
+				context.Background(),
+				"1OR000000000001AAA",
+				nil,
+				PredictionSettings{},
+			)
+			return err
+		}},
+		{name: "invalid_id", call: func() error {
+			_, err := sf.Predict(
+				context.Background(),
+				"1OR000000000001AAA",
+				[]string{"invalid"},
+				PredictionSettings{},
+			)
+			return err
+		}},
+		{name: "no_definition", call: func() error {
+			_, err := sf.Predict(
+				context.Background(),
+				"",
+				[]string{"001000000000001AAA"},
+				PredictionSettings{},
+			)
+			return err
+		}},
+		{name: "row_length", call: func() error {
+			_, err := sf.PredictRaw(
+				context.Background(),
+				"1OR000000000001AAA",
+				[]string{"Industry", "Rating"},
+				[][]string{{"Retail"}},
+				PredictionSettings{},
+			)
+			return err
+		}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetPredictionDefinitions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"predictionDefinitions": [{"id": "1OR2", "name": "Churn"}]}`))
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, predictionDefinitionsUri) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"predictionDefinitions": [{
+				"id": "1OR1",
+				"name": "Win_Rate",
+				"status": "Enabled",
+				"countOfActiveModels": 1,
+				"outcome": {"name": "IsWon", "goal": "Maximize"}
+			}],
+			"nextPageUrl": "/services/data/` + apiVersion + predictionDefinitionsUri + `?page=2"
+		}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	definitions, err := sf.GetPredictionDefinitions(context.Background())
+	if err != nil {
+		t.Fatalf("GetPredictionDefinitions() error = %v", err)
+	}
+	if len(definitions) != 2 || definitions[0].Outcome.Name != "IsWon" ||
+		definitions[0].CountOfActiveModels != 1 || definitions[1].Name != "Churn" {
+		t.Errorf("GetPredictionDefinitions() = %+v", definitions)
+	}
+}
@@ -0,0 +1,144 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// permissionCacheTTL is how long the permissions of a user are cached by the permission pre-flight check
+const permissionCacheTTL = 10 * time.Minute
+
+// permissionLabels are the names of permissions as shown in Setup, for error messages
+var permissionLabels = map[string]string{
+	"ApiEnabled":           "API Enabled",
+	"BulkApiHardDelete":    "Bulk API Hard Delete",
+	"ModifyAllData":        "Modify All Data",
+	"ViewAllData":          "View All Data",
+	"ModifyMetadata":       "Modify Metadata Through Metadata API Functions",
+	"AuthorApex":           "Author Apex",
+	"CustomizeApplication": "Customize Application",
+	"ViewSetup":            "View Setup and Configuration",
+}
+
+// preflightOperations are the operations checked by the permission pre-flight check, by uri prefix,
+// with the permissions they require
+var preflightOperations = []struct {
+	uriPrefix   string
+	operation   string
+	permissions []string
+}{
+	{uriPrefix: "/jobs/", operation: "Bulk API", permissions: []string{"ApiEnabled"}},
+	{
+		uriPrefix:   "/metadata/",
+		operation:   "Metadata API",
+		permissions: []string{"ApiEnabled", "ModifyMetadata"},
+	},
+	{
+		uriPrefix:   "/tooling/",
+		operation:   "Tooling API",
+		permissions: []string{"ApiEnabled", "ViewSetup"},
+	},
+}
+
+// preflightPermissions are the permissions read by the permission pre-flight check
+var preflightPermissions = []string{"ApiEnabled", "ModifyMetadata", "ViewSetup"}
+
+// PermissionError is returned when the user lacks permissions an operation requires, see
+// WithPermissionPreflight and CheckPermissions, instead of the error Salesforce returns partway through
+type PermissionError struct {
+	UserId      string
+	Operation   string   // operation that requires the permissions, such as "Bulk API"
+	Permissions []string // missing permissions, such as ModifyMetadata
+}
+
+func (e *PermissionError) Error() string {
+	labels := make([]string, len(e.Permissions))
+	for i, permission := range e.Permissions {
+		labels[i] = permission
+		if label, ok := permissionLabels[permission]; ok {
+			labels[i] = fmt.Sprintf("%s (%s)", label, permission)
+		}
+	}
+	return fmt.Sprintf(
+		"user %s lacks permissions required for %s: %s; grant them with a permission set",
+		e.UserId,
+		e.Operation,
+		strings.Join(labels, ", "),
+	)
+}
+
+// CheckPermissions returns a PermissionError if the user lacks any of the permissions, given as the
+// API names of user permissions without the Permissions prefix, such as ApiEnabled or ModifyAllData
+func (sf *Salesforce) CheckPermissions(ctx context.Context, permissions ...string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	_, userId, err := identityIds(sf.auth)
+	if err != nil {
+		return err
+	}
+	granted, err := userPermissions(ctx, sf, userId, permissions)
+	if err != nil {
+		return err
+	}
+	return missingPermissions(userId, "this operation", permissions, granted)
+}
+
+// checkPreflightPermissions returns a PermissionError if the request is a Bulk, Metadata, or Tooling API
+// operation and the user lacks a permission it requires. The permissions of the user are cached.
+func checkPreflightPermissions(
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
+) error {
+	if !config.permissionPreflight || payload.instanceUri {
+		return nil
+	}
+	for _, preflight := range preflightOperations {
+		if !strings.HasPrefix(payload.uri, preflight.uriPrefix) {
+			continue
+		}
+		_, userId, err := identityIds(auth)
+		if err != nil {
+			return fmt.Errorf("permission pre-flight check failed: %w", err)
+		}
+		var granted map[string]bool
+		if cached, ok := config.permissionCache.get(userId); ok {
+			granted = cached.(map[string]bool)
+		} else {
+			ctx := payload.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			sf := &Salesforce{auth: auth, config: config}
+			granted, err = userPermissions(ctx, sf, userId, preflightPermissions)
+			if err != nil {
+				return fmt.Errorf("permission pre-flight check failed: %w", err)
+			}
+			config.permissionCache.set(userId, granted)
+		}
+		return missingPermissions(userId, preflight.operation, preflight.permissions, granted)
+	}
+	return nil
+}
+
+func missingPermissions(
+	userId string,
+	operation string,
+	permissions []string,
+	granted map[string]bool,
+) error {
+	var missing []string
+	for _, permission := range permissions {
+		if !granted[permission] {
+			missing = append(missing, permission)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &PermissionError{UserId: userId, Operation: operation, Permissions: missing}
+}
@@ -0,0 +1,114 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckPreflightPermissions(t *testing.T) {
+	queries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query/") {
+			queries++
+			_, _ = w.Write([]byte(`{"done": true, "records": [
+				{"PermissionsApiEnabled": true, "PermissionsModifyMetadata": false, "PermissionsViewSetup": true}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "750A", "state": "JobComplete"}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "1234",
+		Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA",
+	})
+	sf.config.permissionPreflight = true
+
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr *PermissionError
+	}{
+		{name: "bulk", uri: "/jobs/ingest/750A"},
+		{name: "tooling", uri: "/tooling/sobjects/ApexClass"},
+		{
+			name: "metadata",
+			uri:  "/metadata/deployRequest/0Af",
+			wantErr: &PermissionError{
+				UserId:      "005000000000001AAA",
+				Operation:   "Metadata API",
+				Permissions: []string{"ModifyMetadata"},
+			},
+		},
+		{name: "not_checked", uri: "/sobjects/Account"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := doRequest(sf.auth, sf.config, requestPayload{
+				method:  http.MethodGet,
+				uri:     tt.uri,
+				content: jsonType,
+			})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("doRequest() error = %v", err)
+				}
+				return
+			}
+			permissionErr := &PermissionError{}
+			if !errors.As(err, &permissionErr) || !reflect.DeepEqual(permissionErr, tt.wantErr) {
+				t.Errorf("doRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	// the permissions are read once and cached
+	if queries != 1 {
+		t.Errorf("permissions queried %d times, want 1", queries)
+	}
+}
+
+func TestPermissionError_Error(t *testing.T) {
+	err := &PermissionError{
+		UserId:      "005000000000001AAA",
+		Operation:   "Bulk API",
+		Permissions: []string{"ApiEnabled", "Custom"},
+	}
+	want := "user 005000000000001AAA lacks permissions required for Bulk API: " +
+		"API Enabled (ApiEnabled), Custom; grant them with a permission set"
+	if err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+}
+
+func TestSalesforce_CheckPermissions(t *testing.T) {
+	server, auth := setupTestServer(queryResponse{
+		Done: true,
+		Records: []map[string]any{
+			{"PermissionsApiEnabled": true, "PermissionsModifyAllData": false},
+		},
+	}, http.StatusOK)
+	defer server.Close()
+	auth.Id = "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA"
+	sf := buildSalesforceStruct(&auth)
+
+	if err := sf.CheckPermissions(context.Background(), "ApiEnabled"); err != nil {
+		t.Errorf("CheckPermissions() error = %v", err)
+	}
+	err := sf.CheckPermissions(context.Background(), "ApiEnabled", "ModifyAllData")
+	permissionErr := &PermissionError{}
+	if !errors.As(err, &permissionErr) ||
+		!reflect.DeepEqual(permissionErr.Permissions, []string{"ModifyAllData"}) {
+		t.Errorf("CheckPermissions() error = %v, want missing ModifyAllData", err)
+	}
+
+	sf.auth.Id = ""
+	if err := sf.CheckPermissions(context.Background(), "ApiEnabled"); err == nil {
+		t.Errorf("CheckPermissions() expected error without an identity url")
+	}
+}
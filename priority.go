@@ -0,0 +1,208 @@
+package salesforce
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Priority is the class a request is queued in when the number of concurrent requests is limited,
+// see WithPriorityQueue
+type Priority int
+
+const (
+	// PriorityInteractive is for requests a user is waiting on, such as queries and single record
+	// operations
+	PriorityInteractive Priority = iota
+	// PriorityBatch is for large background work, such as Bulk API jobs and collection DML
+	PriorityBatch
+)
+
+func (p Priority) String() string {
+	if p == PriorityBatch {
+		return "batch"
+	}
+	return "interactive"
+}
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a context that queues requests made with it in the given priority
+// class, overriding the default class of the request
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// priorityFromContext returns the priority set with ContextWithPriority, if any
+func priorityFromContext(ctx context.Context) (Priority, bool) {
+	priority, ok := ctx.Value(priorityContextKey{}).(Priority)
+	return priority, ok
+}
+
+// requestPriority returns the default priority of a request: Bulk API requests and composite
+// requests that write records are batch work, everything else is interactive
+func requestPriority(payload requestPayload) Priority {
+	switch classifyEndpoint(payload) {
+	case EndpointBulk:
+		return PriorityBatch
+	case EndpointComposite:
+		if payload.method != http.MethodGet {
+			return PriorityBatch
+		}
+	}
+	return PriorityInteractive
+}
+
+// PriorityQueueSettings configures the priority queue, see WithPriorityQueue. Zero values take the
+// defaults noted on each field.
+type PriorityQueueSettings struct {
+	MaxConcurrent     int // requests sent at once, others wait in the queue (default 10)
+	InteractiveWeight int // share of queued requests sent from the interactive class (default 4)
+	BatchWeight       int // share of queued requests sent from the batch class (default 1)
+}
+
+// fairQueue limits the number of concurrent requests and lets waiting requests through by weighted
+// fair queueing: each waiter is given a virtual finish time that grows by 1/weight of its class, and
+// the waiter with the earliest finish time is let through first. With weights of 4 and 1, four
+// interactive requests are let through for every batch request while both classes are waiting, and
+// a class without waiters gives its share to the other.
+type fairQueue struct {
+	mu          sync.Mutex
+	available   int
+	weights     [2]float64
+	lastFinish  [2]float64 // finish time of the last waiter queued in each class
+	virtualTime float64    // finish time of the last waiter let through
+	seq         int
+	waiters     waiterHeap
+}
+
+type queueWaiter struct {
+	priority Priority
+	finish   float64
+	seq      int // breaks ties in queued order
+	index    int // position in the heap, -1 once let through
+	ready    chan struct{}
+}
+
+func newFairQueue(settings PriorityQueueSettings) (*fairQueue, error) {
+	if settings.MaxConcurrent < 0 || settings.InteractiveWeight < 0 || settings.BatchWeight < 0 {
+		return nil, errors.New("priority queue settings cannot be negative")
+	}
+	if settings.MaxConcurrent == 0 {
+		settings.MaxConcurrent = 10
+	}
+	if settings.InteractiveWeight == 0 {
+		settings.InteractiveWeight = 4
+	}
+	if settings.BatchWeight == 0 {
+		settings.BatchWeight = 1
+	}
+	return &fairQueue{
+		available: settings.MaxConcurrent,
+		weights:   [2]float64{float64(settings.InteractiveWeight), float64(settings.BatchWeight)},
+	}, nil
+}
+
+// acquire waits until a request of the given priority may be sent or the context is done
+func (q *fairQueue) acquire(ctx context.Context, priority Priority) error {
+	if priority != PriorityBatch {
+		priority = PriorityInteractive
+	}
+	q.mu.Lock()
+	if q.available > 0 && len(q.waiters) == 0 {
+		q.available--
+		q.mu.Unlock()
+		return nil
+	}
+	finish := max(q.virtualTime, q.lastFinish[priority]) + 1/q.weights[priority]
+	q.lastFinish[priority] = finish
+	waiter := &queueWaiter{
+		priority: priority,
+		finish:   finish,
+		seq:      q.seq,
+		ready:    make(chan struct{}),
+	}
+	q.seq++
+	heap.Push(&q.waiters, waiter)
+	q.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		if waiter.index >= 0 {
+			heap.Remove(&q.waiters, waiter.index)
+			q.mu.Unlock()
+			return ctx.Err()
+		}
+		q.mu.Unlock()
+		// let through while the context was canceled, pass the slot on
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a finished request, letting the next waiter through
+func (q *fairQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		q.available++
+		return
+	}
+	waiter := heap.Pop(&q.waiters).(*queueWaiter)
+	q.virtualTime = waiter.finish
+	close(waiter.ready)
+}
+
+type waiterHeap []*queueWaiter
+
+func (h waiterHeap) Len() int { return len(h) }
+
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].finish != h[j].finish {
+		return h[i].finish < h[j].finish
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap) Push(x any) {
+	waiter := x.(*queueWaiter)
+	waiter.index = len(*h)
+	*h = append(*h, waiter)
+}
+
+func (h *waiterHeap) Pop() any {
+	old := *h
+	waiter := old[len(old)-1]
+	old[len(old)-1] = nil
+	waiter.index = -1
+	*h = old[:len(old)-1]
+	return waiter
+}
+
+// priorityTransport sends requests through a fair queue, a request holds its slot until its response
+// headers are received
+type priorityTransport struct {
+	next  http.RoundTripper
+	queue *fairQueue
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *priorityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	priority, _ := priorityFromContext(req.Context())
+	if err := t.queue.acquire(req.Context(), priority); err != nil {
+		return nil, err
+	}
+	defer t.queue.release()
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,187 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_requestPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload requestPayload
+		want    Priority
+	}{
+		{
+			name: "query",
+			payload: requestPayload{
+				method: http.MethodGet,
+				uri:    "/query/?q=SELECT+Id+FROM+Account",
+			},
+			want: PriorityInteractive,
+		},
+		{
+			name:    "single_record",
+			payload: requestPayload{method: http.MethodPatch, uri: "/sobjects/Account/001"},
+			want:    PriorityInteractive,
+		},
+		{
+			name:    "collection_insert",
+			payload: requestPayload{method: http.MethodPost, uri: "/composite/sobjects/"},
+			want:    PriorityBatch,
+		},
+		{
+			name: "collection_retrieve",
+			payload: requestPayload{
+				method: http.MethodGet,
+				uri:    "/composite/sobjects/Account?ids=001",
+			},
+			want: PriorityInteractive,
+		},
+		{
+			name:    "bulk",
+			payload: requestPayload{method: http.MethodGet, uri: "/jobs/ingest/750"},
+			want:    PriorityBatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestPriority(tt.payload); got != tt.want {
+				t.Errorf("requestPriority() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// queueWaiting acquires a slot of the queue in a goroutine and returns once it is queued
+func queueWaiting(
+	t *testing.T,
+	ctx context.Context,
+	q *fairQueue,
+	priority Priority,
+	done func(error),
+) {
+	t.Helper()
+	q.mu.Lock()
+	queued := len(q.waiters)
+	q.mu.Unlock()
+	go func() { done(q.acquire(ctx, priority)) }()
+	for {
+		q.mu.Lock()
+		n := len(q.waiters)
+		q.mu.Unlock()
+		if n > queued {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_fairQueue(t *testing.T) {
+	q, err := newFairQueue(PriorityQueueSettings{MaxConcurrent: 1})
+	if err != nil {
+		t.Fatalf("newFairQueue() error = %v", err)
+	}
+	if err := q.acquire(context.Background(), PriorityBatch); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	granted := make(chan struct{})
+	enqueue := func(label string, priority Priority) {
+		queueWaiting(t, context.Background(), q, priority, func(err error) {
+			if err != nil {
+				t.Errorf("acquire() %s error = %v", label, err)
+			}
+			mu.Lock()
+			order = append(order, label)
+			mu.Unlock()
+			granted <- struct{}{}
+		})
+	}
+	enqueue("B1", PriorityBatch)
+	enqueue("B2", PriorityBatch)
+	for _, label := range []string{"I1", "I2", "I3", "I4", "I5", "I6", "I7", "I8"} {
+		enqueue(label, PriorityInteractive)
+	}
+
+	// a request canceled while queued gives up its place
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	queueWaiting(t, ctx, q, PriorityInteractive, func(err error) { canceled <- err })
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("acquire() error = %v, want context.Canceled", err)
+	}
+
+	for range 10 {
+		q.release()
+		<-granted
+	}
+	want := []string{"I1", "I2", "I3", "B1", "I4", "I5", "I6", "I7", "B2", "I8"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	q.release()
+	if q.available != 1 {
+		t.Errorf("available = %d, want 1", q.available)
+	}
+}
+
+type priorityCapture struct {
+	mu         sync.Mutex
+	priorities map[string]Priority
+}
+
+func (c *priorityCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	priority, _ := priorityFromContext(req.Context())
+	c.mu.Lock()
+	c.priorities[req.Method+" "+req.URL.Path] = priority
+	c.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithPriorityQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"done":true,"records":[]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	capture := &priorityCapture{priorities: map[string]Priority{}}
+	if err := WithRoundTripper(capture)(sf.config); err != nil {
+		t.Fatalf("WithRoundTripper() error = %v", err)
+	}
+	if err := WithPriorityQueue(PriorityQueueSettings{MaxConcurrent: 2})(sf.config); err != nil {
+		t.Fatalf("WithPriorityQueue() error = %v", err)
+	}
+	sf.config.configureHttpClient()
+
+	if _, err := sf.DoRequest(http.MethodGet, "/query/?q=SELECT+Id+FROM+Account", nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if _, err := sf.DoRequest(http.MethodPost, "/jobs/ingest", []byte(`{}`)); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	ctx := ContextWithPriority(context.Background(), PriorityBatch)
+	if _, err := getQueryPage(ctx, sf, "/query/01gD-2000"); err != nil {
+		t.Fatalf("getQueryPage() error = %v", err)
+	}
+
+	want := map[string]Priority{
+		"GET /services/data/" + apiVersion + "/query/":          PriorityInteractive,
+		"POST /services/data/" + apiVersion + "/jobs/ingest":    PriorityBatch,
+		"GET /services/data/" + apiVersion + "/query/01gD-2000": PriorityBatch,
+	}
+	if !reflect.DeepEqual(capture.priorities, want) {
+		t.Errorf("priorities = %v, want %v", capture.priorities, want)
+	}
+	if _, err := newFairQueue(PriorityQueueSettings{MaxConcurrent: -1}); err == nil {
+		t.Error("newFairQueue() expected an error for negative settings")
+	}
+}
@@ -1,11 +1,14 @@
 package salesforce
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"time"
 )
 
 type queryResponse struct {
@@ -15,50 +18,240 @@ type queryResponse struct {
 	Records        []map[string]any `json:"records"`
 }
 
-func performQuery(sf *Salesforce, query string, sObject any) error {
-	query = url.QueryEscape(query)
-	queryResp := &queryResponse{
-		Done:           false,
-		NextRecordsUrl: "/query/?q=" + query,
-	}
-
-	for !queryResp.Done {
-		resp, err := doRequest(sf.auth, sf.config, requestPayload{
-			method:   http.MethodGet,
-			uri:      queryResp.NextRecordsUrl,
-			content:  jsonType,
-			compress: sf.config.compressionHeaders,
-		})
+func getQueryPage(ctx context.Context, sf *Salesforce, uri string) (*queryResponse, error) {
+	queryResp := &queryResponse{}
+	if err := readQueryPage(ctx, sf, uri, queryResp); err != nil {
+		return nil, err
+	}
+	queryResp.NextRecordsUrl = strings.TrimPrefix(
+		queryResp.NextRecordsUrl,
+		"/services/data/"+sf.config.apiVersion,
+	)
+	for _, record := range queryResp.Records {
+		if err := completeSubqueries(ctx, sf, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return queryResp, nil
+}
+
+// readQueryPage reads a page of query results into page
+func readQueryPage(ctx context.Context, sf *Salesforce, uri string, page any) error {
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return err
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return readErr
+	}
+
+	return unmarshalJSON(sf.config, respBody, page)
+}
+
+func queryAllRecords(ctx context.Context, sf *Salesforce, query string) ([]map[string]any, error) {
+	return queryResourceRecords(ctx, sf, "/query/", query)
+}
+
+// queryResourceRecords returns every record of a query against a query resource, such as the
+// Tooling API's /tooling/query/
+func queryResourceRecords(
+	ctx context.Context,
+	sf *Salesforce,
+	resource string,
+	query string,
+) ([]map[string]any, error) {
+	records := []map[string]any{}
+	nextRecordsUrl := resource + "?q=" + url.QueryEscape(query)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, sf, nextRecordsUrl)
 		if err != nil {
+			return nil, err
+		}
+		records = append(records, queryResp.Records...)
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
+	return records, nil
+}
+
+func performQuery(sf *Salesforce, query string, sObject any) error {
+	if err := checkQuery(sf, query); err != nil {
+		return err
+	}
+	records, err := queryAllRecords(context.Background(), sf, query)
+	if err != nil {
+		return err
+	}
+
+	sObjectError := decodeQueryRecords(sf.config, records, sObject)
+	if sObjectError != nil {
+		return sObjectError
+	}
+
+	return nil
+}
+
+// checkQuery runs the checks of a query enabled by WithEncryptedFieldCheck and WithNameValidation
+func checkQuery(sf *Salesforce, query string) error {
+	if sf.config.encryptedFieldCheck {
+		if err := checkEncryptedFields(sf, query); err != nil {
+			return err
+		}
+	}
+	if sf.config.nameValidation {
+		if err := validateQueryNames(sf, query); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+// escapeSoqlString escapes a value for use inside a single quoted SOQL string literal
+func escapeSoqlString(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return replacer.Replace(value)
+}
 
-		respBody, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return readErr
+// decodeQueryRecords decodes query records into sObject, strictly if WithStrictDecoding is enabled,
+// or failing on fields missing from the struct if WithUnknownFieldErrors is enabled
+func decodeQueryRecords(config *configuration, records []map[string]any, sObject any) error {
+	if !config.strictDecoding && !config.unknownFieldErrors {
+		return mapstructureDecode(records, sObject)
+	}
+	stripped := make([]map[string]any, len(records))
+	for i, record := range records {
+		stripped[i] = removeAttributes(record)
+	}
+	if !config.strictDecoding {
+		if err := errorUnusedMapstructureDecode(stripped, sObject); err != nil {
+			return fmt.Errorf("decoding query results: %w", err)
 		}
+		return nil
+	}
+	if err := strictMapstructureDecode(stripped, sObject); err != nil {
+		return fmt.Errorf("strict decoding of query results: %w", err)
+	}
+	return nil
+}
+
+// soqlFieldNames returns the SOQL field names of a struct type, honoring salesforce struct tags.
+// Nested structs are treated as parent relationships (ex: Account.Name), slices are skipped.
+// Relationships back to a struct type that is already being expanded, such as Account.Parent, are skipped.
+func soqlFieldNames(t reflect.Type) []string {
+	return relationshipFieldNames(t, map[reflect.Type]bool{})
+}
 
-		tempQueryResp := &queryResponse{}
-		queryResponseError := json.Unmarshal(respBody, &tempQueryResp)
-		if queryResponseError != nil {
-			return queryResponseError
+func relationshipFieldNames(t reflect.Type, expanding map[reflect.Type]bool) []string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || expanding[t] {
+		return nil
+	}
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := structFieldName(field)
+		if name == "-" {
+			continue
 		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Map:
+			continue
+		case fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}):
+			for _, nested := range relationshipFieldNames(fieldType, expanding) {
+				if isSquashed(field) {
+					fields = append(fields, nested)
+				} else {
+					fields = append(fields, name+"."+nested)
+				}
+			}
+		default:
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
 
-		queryResp.TotalSize = queryResp.TotalSize + tempQueryResp.TotalSize
-		queryResp.Records = append(queryResp.Records, tempQueryResp.Records...)
-		queryResp.Done = tempQueryResp.Done
-		if !tempQueryResp.Done && tempQueryResp.NextRecordsUrl != "" {
-			queryResp.NextRecordsUrl = strings.TrimPrefix(
-				tempQueryResp.NextRecordsUrl,
-				"/services/data/"+apiVersion,
-			)
+// isSquashed returns true if an embedded struct field is flattened into its parent by mapstructure
+func isSquashed(field reflect.StructField) bool {
+	for _, tagName := range []string{"salesforce", "mapstructure"} {
+		if strings.Contains(field.Tag.Get(tagName), ",squash") {
+			return true
 		}
 	}
+	return false
+}
 
-	sObjectError := mapstructureDecode(queryResp.Records, sObject)
-	if sObjectError != nil {
-		return sObjectError
+// structFieldName returns the salesforce field name of a struct field from its salesforce or
+// mapstructure tag, or the field name if untagged
+func structFieldName(field reflect.StructField) string {
+	for _, tagName := range []string{"salesforce", "mapstructure"} {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name
+			}
+		}
 	}
+	return field.Name
+}
 
-	return nil
+func streamQuery(
+	ctx context.Context,
+	sf *Salesforce,
+	query string,
+	records chan<- map[string]any,
+	errs chan<- error,
+) {
+	defer close(records)
+	defer close(errs)
+
+	nextRecordsUrl := "/query/?q=" + url.QueryEscape(query)
+	for nextRecordsUrl != "" {
+		queryResp, err := getQueryPage(ctx, sf, nextRecordsUrl)
+		if err != nil {
+			errs <- err
+			return
+		}
+		for _, record := range queryResp.Records {
+			select {
+			case records <- record:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		nextRecordsUrl = ""
+		if !queryResp.Done {
+			nextRecordsUrl = queryResp.NextRecordsUrl
+		}
+	}
 }
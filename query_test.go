@@ -1,12 +1,14 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_performQuery(t *testing.T) {
@@ -122,3 +124,313 @@ func Test_performQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestSalesforce_QueryChan(t *testing.T) {
+	firstPage, _ := json.Marshal(queryResponse{
+		TotalSize:      2,
+		Done:           false,
+		NextRecordsUrl: "/services/data/" + apiVersion + "/query/01gxx-2000",
+		Records:        []map[string]any{{"Id": "001a"}},
+	})
+	lastPage, _ := json.Marshal(queryResponse{
+		TotalSize: 2,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "001b"}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := firstPage
+		if strings.HasSuffix(r.URL.Path, "/query/01gxx-2000") {
+			page = lastPage
+		}
+		if _, err := w.Write(page); err != nil {
+			panic(err.Error())
+		}
+	}))
+	defer server.Close()
+	sfAuth := authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstoken",
+	}
+
+	badServer, badSfAuth := setupTestServer("", http.StatusBadRequest)
+	defer badServer.Close()
+
+	tests := []struct {
+		name    string
+		auth    *authentication
+		want    []map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "stream_all_pages",
+			auth:    &sfAuth,
+			want:    []map[string]any{{"Id": "001a"}, {"Id": "001b"}},
+			wantErr: false,
+		},
+		{
+			name:    "http_error",
+			auth:    &badSfAuth,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "validation_fail_auth",
+			auth:    nil,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			records, errs := sf.QueryChan("SELECT Id FROM Account")
+			var got []map[string]any
+			for record := range records {
+				got = append(got, record)
+			}
+			err := <-errs
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.QueryChan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salesforce.QueryChan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryChanContext_Canceled(t *testing.T) {
+	page, _ := json.Marshal(queryResponse{
+		TotalSize: 2,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "001a"}, {"Id": "001b"}},
+	})
+	server, sfAuth := setupTestServer(json.RawMessage(page), http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records, errs := sf.QueryChanContext(ctx, "SELECT Id FROM Account")
+	<-records // receive the first record, then stop consuming
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("Salesforce.QueryChanContext() error = %v, want %v", err, context.Canceled)
+	}
+	if _, open := <-records; open {
+		t.Errorf("Salesforce.QueryChanContext() sent records after being canceled")
+	}
+}
+
+func Test_soqlFieldNames(t *testing.T) {
+	type owner struct {
+		Name string
+	}
+	type Base struct {
+		Id string
+	}
+	type contact struct {
+		Id string
+	}
+	type account struct {
+		Base       `salesforce:",squash"`
+		Name       string
+		ExternalId string    `salesforce:"External_Id__c"`
+		Legacy     string    `                            mapstructure:"Legacy__c,omitempty"`
+		Ignored    string    `salesforce:"-"`
+		CreatedAt  time.Time `salesforce:"CreatedDate"`
+		Owner      *owner
+		Contacts   []contact
+		unexported string
+	}
+	type hierarchy struct {
+		Name   string
+		Parent *hierarchy
+		Owner  *owner
+	}
+	tests := []struct {
+		name  string
+		input any
+		want  []string
+	}{
+		{
+			name:  "struct_fields",
+			input: &[]account{},
+			want: []string{
+				"Id",
+				"Name",
+				"External_Id__c",
+				"Legacy__c",
+				"CreatedDate",
+				"Owner.Name",
+			},
+		},
+		{
+			name:  "self_relationship",
+			input: &[]hierarchy{},
+			want:  []string{"Name", "Owner.Name"},
+		},
+		{
+			name:  "not_a_struct",
+			input: map[string]any{},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := soqlFieldNames(reflect.TypeOf(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("soqlFieldNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryTooling(t *testing.T) {
+	type apexClass struct {
+		Id   string
+		Name string
+	}
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "01p000000000001AAA", "Name": "AccountService"}},
+	}
+	server, sfAuth, captured := setupTestServerWithCapture(resp, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	var classes []apexClass
+	if err := sf.QueryTooling("SELECT Id, Name FROM ApexClass", &classes); err != nil {
+		t.Fatalf("QueryTooling() error = %v", err)
+	}
+	if (*captured).URL.Path != "/services/data/"+apiVersion+"/tooling/query/" {
+		t.Errorf("QueryTooling() path = %v", (*captured).URL.Path)
+	}
+	if len(classes) != 1 || classes[0].Name != "AccountService" {
+		t.Errorf("QueryTooling() = %v", classes)
+	}
+}
+
+func Test_decodeQueryRecords(t *testing.T) {
+	type account struct {
+		Name string
+	}
+	type contact struct {
+		Id       string
+		LastName string `salesforce:"LastName"`
+		Account  account
+	}
+	attributes := map[string]any{
+		"type": "Contact",
+		"url":  "/services/data/v63.0/sobjects/Contact/003",
+	}
+	tests := []struct {
+		name       string
+		strict     bool
+		unknown    bool
+		records    []map[string]any
+		wantErr    bool
+		wantFields []string // field names expected in the error
+	}{
+		{
+			name:   "strict_match",
+			strict: true,
+			records: []map[string]any{{
+				"attributes": attributes,
+				"Id":         "003000000000001AAA",
+				"LastName":   "Smith",
+				"Account":    map[string]any{"attributes": attributes, "Name": "Acme"},
+			}},
+		},
+		{
+			name:   "strict_null_relationship",
+			strict: true,
+			records: []map[string]any{{
+				"attributes": attributes,
+				"Id":         "003000000000001AAA",
+				"LastName":   "Smith",
+				"Account":    nil,
+			}},
+		},
+		{
+			name:   "strict_field_missing_from_struct",
+			strict: true,
+			records: []map[string]any{{
+				"Id":       "003000000000001AAA",
+				"LastName": "Smith",
+				"Email":    "smith@example.com",
+				"Account":  map[string]any{"Name": "Acme"},
+			}},
+			wantErr:    true,
+			wantFields: []string{"Email"},
+		},
+		{
+			name:   "strict_field_missing_from_query",
+			strict: true,
+			records: []map[string]any{{
+				"Id":      "003000000000001AAA",
+				"Account": map[string]any{},
+			}},
+			wantErr:    true,
+			wantFields: []string{"LastName", "Name"},
+		},
+		{
+			name:    "unknown_field",
+			unknown: true,
+			records: []map[string]any{{
+				"attributes": attributes,
+				"Id":         "003000000000001AAA",
+				"Email":      "smith@example.com",
+				"Account":    map[string]any{"attributes": attributes, "Name": "Acme"},
+			}},
+			wantErr:    true,
+			wantFields: []string{"Email"},
+		},
+		{
+			name:    "unknown_field_missing_from_query",
+			unknown: true,
+			records: []map[string]any{{
+				"attributes": attributes,
+				"Id":         "003000000000001AAA",
+			}},
+		},
+		{
+			name:   "not_strict",
+			strict: false,
+			records: []map[string]any{{
+				"attributes": attributes,
+				"Id":         "003000000000001AAA",
+				"Email":      "smith@example.com",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := configuration{}
+			config.setDefaults()
+			config.strictDecoding = tt.strict
+			config.unknownFieldErrors = tt.unknown
+
+			var contacts []contact
+			err := decodeQueryRecords(&config, tt.records, &contacts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeQueryRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, field := range tt.wantFields {
+				if !strings.Contains(err.Error(), field) {
+					t.Errorf("decodeQueryRecords() error = %v, want field %v", err, field)
+				}
+			}
+			if !tt.wantErr && (len(contacts) != 1 || contacts[0].Id != "003000000000001AAA") {
+				t.Errorf("decodeQueryRecords() = %v", contacts)
+			}
+		})
+	}
+}
+
+func Test_escapeSoqlString(t *testing.T) {
+	got := escapeSoqlString("O'Brien \\ new\nline")
+	want := `O\'Brien \\ new\nline`
+	if got != want {
+		t.Errorf("escapeSoqlString() = %v, want %v", got, want)
+	}
+}
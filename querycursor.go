@@ -0,0 +1,129 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// QueryCursor is the position of a query in its results. It can be saved, such as to a database or a
+// queue message, and resumed by another process with ResumeQuery, so that paging over a large result set
+// can be spread across invocations of short-lived workers. The access token is not saved, the resuming
+// client must be authenticated as the same user of the same org. Salesforce deletes query cursors that
+// are not used for about 15 minutes, after which resuming fails with INVALID_QUERY_LOCATOR.
+type QueryCursor struct {
+	Query          string    `json:"query"`
+	NextRecordsUrl string    `json:"nextRecordsUrl"` // empty once every page has been read
+	InstanceUrl    string    `json:"instanceUrl"`
+	OrgId          string    `json:"orgId"`
+	UserId         string    `json:"userId"`
+	ApiVersion     string    `json:"apiVersion"`
+	TotalSize      int       `json:"totalSize"`
+	RecordsRead    int       `json:"recordsRead"`
+	UpdatedAt      time.Time `json:"updatedAt"` // when the last page was read
+}
+
+// Done returns whether every page of the query has been read
+func (c QueryCursor) Done() bool {
+	return c.NextRecordsUrl == ""
+}
+
+// Encode returns the cursor as a URL-safe string, such as for an environment variable or a task payload
+func (c QueryCursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeQueryCursor returns the cursor of a string returned by QueryCursor.Encode
+func DecodeQueryCursor(encoded string) (QueryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return QueryCursor{}, fmt.Errorf("invalid query cursor: %w", err)
+	}
+	cursor := QueryCursor{}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return QueryCursor{}, fmt.Errorf("invalid query cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// QueryWithCursor performs a SOQL query, decodes the first page of results into sObject, and returns
+// a cursor to read the following pages with ResumeQuery
+func (sf *Salesforce) QueryWithCursor(query string, sObject any) (QueryCursor, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return QueryCursor{}, authErr
+	}
+	if err := checkQuery(sf, query); err != nil {
+		return QueryCursor{}, err
+	}
+	orgId, userId, _ := identityIds(sf.auth)
+	cursor := QueryCursor{
+		Query:       query,
+		InstanceUrl: sf.auth.InstanceUrl,
+		OrgId:       orgId,
+		UserId:      userId,
+		ApiVersion:  sf.config.apiVersion,
+	}
+	return readCursorPage(sf, cursor, "/query/?q="+url.QueryEscape(query), sObject)
+}
+
+// ResumeQuery decodes the next page of results of a query cursor into sObject and returns the cursor of
+// the page after it. The cursor must have been created by a client of the same user, org, and API
+// version.
+func (sf *Salesforce) ResumeQuery(cursor QueryCursor, sObject any) (QueryCursor, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return QueryCursor{}, authErr
+	}
+	if cursor.Done() {
+		return cursor, errors.New("query cursor has no more pages")
+	}
+	if cursor.ApiVersion != sf.config.apiVersion {
+		return cursor, fmt.Errorf(
+			"query cursor was created with API version %s, client uses %s",
+			cursor.ApiVersion,
+			sf.config.apiVersion,
+		)
+	}
+	orgId, userId, _ := identityIds(sf.auth)
+	switch {
+	case cursor.OrgId != "" && orgId != "":
+		if !sameSalesforceId(cursor.OrgId, orgId) || !sameSalesforceId(cursor.UserId, userId) {
+			return cursor, errors.New("query cursor was created by a different user or org")
+		}
+	case cursor.InstanceUrl != sf.auth.InstanceUrl:
+		return cursor, fmt.Errorf("query cursor was created for instance %s", cursor.InstanceUrl)
+	}
+	return readCursorPage(sf, cursor, cursor.NextRecordsUrl, sObject)
+}
+
+func readCursorPage(
+	sf *Salesforce,
+	cursor QueryCursor,
+	uri string,
+	sObject any,
+) (QueryCursor, error) {
+	queryResp, err := getQueryPage(context.Background(), sf, uri)
+	if err != nil {
+		return cursor, err
+	}
+	if err := decodeQueryRecords(sf.config, queryResp.Records, sObject); err != nil {
+		return cursor, err
+	}
+	cursor.TotalSize = queryResp.TotalSize
+	cursor.RecordsRead += len(queryResp.Records)
+	cursor.UpdatedAt = time.Now()
+	cursor.NextRecordsUrl = ""
+	if !queryResp.Done {
+		cursor.NextRecordsUrl = queryResp.NextRecordsUrl
+	}
+	return cursor, nil
+}
@@ -0,0 +1,156 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func setupQueryCursorServer() *httptest.Server {
+	pages := map[string]queryResponse{
+		"/services/data/" + apiVersion + "/query/": {
+			TotalSize:      3,
+			NextRecordsUrl: "/services/data/" + apiVersion + "/query/01gD-2",
+			Records:        []map[string]any{{"Id": "003A"}, {"Id": "003B"}},
+		},
+		"/services/data/" + apiVersion + "/query/01gD-2": {
+			TotalSize: 3,
+			Done:      true,
+			Records:   []map[string]any{{"Id": "003C"}},
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(page)
+		_, _ = w.Write(body)
+	}))
+}
+
+func TestSalesforce_QueryWithCursor(t *testing.T) {
+	server := setupQueryCursorServer()
+	defer server.Close()
+	identity := "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA"
+	sf := buildSalesforceStruct(
+		&authentication{InstanceUrl: server.URL, AccessToken: "1234", Id: identity},
+	)
+
+	type contact struct {
+		Id string
+	}
+	first := []contact{}
+	cursor, err := sf.QueryWithCursor("SELECT Id FROM Contact", &first)
+	if err != nil {
+		t.Fatalf("QueryWithCursor() error = %v", err)
+	}
+	if len(first) != 2 || cursor.Done() || cursor.RecordsRead != 2 || cursor.TotalSize != 3 {
+		t.Fatalf("QueryWithCursor() = %+v, records %v", cursor, first)
+	}
+	if cursor.OrgId != "00D000000000001AAA" || cursor.UserId != "005000000000001AAA" {
+		t.Errorf("QueryWithCursor() ids = %s, %s", cursor.OrgId, cursor.UserId)
+	}
+
+	// resume from the encoded cursor in a new client, as another process would
+	encoded, err := cursor.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := DecodeQueryCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeQueryCursor() error = %v", err)
+	}
+	if decoded.NextRecordsUrl != cursor.NextRecordsUrl ||
+		!decoded.UpdatedAt.Equal(cursor.UpdatedAt) {
+		t.Errorf("DecodeQueryCursor() = %+v, want %+v", decoded, cursor)
+	}
+	resumer := buildSalesforceStruct(
+		&authentication{InstanceUrl: server.URL, AccessToken: "5678", Id: identity},
+	)
+	second := []contact{}
+	cursor, err = resumer.ResumeQuery(decoded, &second)
+	if err != nil {
+		t.Fatalf("ResumeQuery() error = %v", err)
+	}
+	if !reflect.DeepEqual(second, []contact{{Id: "003C"}}) || !cursor.Done() ||
+		cursor.RecordsRead != 3 {
+		t.Errorf("ResumeQuery() = %+v, records %v", cursor, second)
+	}
+
+	if _, err := resumer.ResumeQuery(cursor, &second); err == nil {
+		t.Errorf("ResumeQuery() expected error for a cursor without more pages")
+	}
+	if _, err := DecodeQueryCursor("not a cursor"); err == nil {
+		t.Errorf("DecodeQueryCursor() expected error")
+	}
+}
+
+func TestSalesforce_ResumeQuery_mismatch(t *testing.T) {
+	server := setupQueryCursorServer()
+	defer server.Close()
+	cursor := QueryCursor{
+		NextRecordsUrl: "/query/01gD-2",
+		InstanceUrl:    server.URL,
+		OrgId:          "00D000000000001AAA",
+		UserId:         "005000000000001AAA",
+		ApiVersion:     apiVersion,
+	}
+
+	tests := []struct {
+		name    string
+		auth    authentication
+		cursor  func(QueryCursor) QueryCursor
+		wantErr bool
+	}{
+		{
+			name: "same_user_18_and_15_character_ids",
+			auth: authentication{
+				InstanceUrl: server.URL,
+				Id:          "https://login.salesforce.com/id/00D000000000001/005000000000001",
+			},
+		},
+		{
+			name: "different_user",
+			auth: authentication{
+				InstanceUrl: server.URL,
+				Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000002AAA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no_identity_same_instance",
+			auth: authentication{InstanceUrl: server.URL},
+		},
+		{
+			name:    "no_identity_different_instance",
+			auth:    authentication{InstanceUrl: server.URL},
+			cursor:  func(c QueryCursor) QueryCursor { c.InstanceUrl = "https://other"; return c },
+			wantErr: true,
+		},
+		{
+			name:    "different_api_version",
+			auth:    authentication{InstanceUrl: server.URL},
+			cursor:  func(c QueryCursor) QueryCursor { c.ApiVersion = "v1.0"; return c },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.auth.AccessToken = "1234"
+			sf := buildSalesforceStruct(&tt.auth)
+			c := cursor
+			if tt.cursor != nil {
+				c = tt.cursor(c)
+			}
+			records := []map[string]any{}
+			_, err := sf.ResumeQuery(c, &records)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResumeQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
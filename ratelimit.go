@@ -0,0 +1,119 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups requests by the Salesforce API they are sent to, so each group can be rate
+// limited separately, see WithEndpointRateLimiter
+type EndpointClass string
+
+const (
+	EndpointQuery     EndpointClass = "query"     // SOQL queries and their next pages
+	EndpointSObject   EndpointClass = "sobject"   // single record operations and describes
+	EndpointComposite EndpointClass = "composite" // composite, collection, and graph requests
+	EndpointBulk      EndpointClass = "bulk"      // Bulk API 2.0 jobs and their results
+	EndpointTooling   EndpointClass = "tooling"   // Tooling API requests
+	EndpointOther     EndpointClass = "other"     // any other request, such as limits, metadata, or Apex REST
+)
+
+// RateLimiter is a token bucket limiting how many requests are sent per second. A limiter can be
+// shared by several clients, such as clients of the same org in one process, by passing it to each
+// of them with WithRateLimiter or WithEndpointRateLimiter. It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // tokens the bucket holds when full
+	tokens float64 // may be negative when requests are waiting on tokens already reserved
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing requestsPerSecond requests per second on average, and
+// bursts of up to burst requests at once. A burst below 1 is treated as 1.
+func NewRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, error) {
+	if requestsPerSecond <= 0 {
+		return nil, errors.New("requests per second must be greater than 0")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}, nil
+}
+
+// Wait blocks until a request may be sent or the context is done, in which case it returns the
+// context error. Requests are let through in the order they called Wait.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give back the reserved token so requests behind this one are not delayed by it
+		l.mu.Lock()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// classifyEndpoint returns the endpoint class of a request
+func classifyEndpoint(payload requestPayload) EndpointClass {
+	if payload.instanceUri {
+		return EndpointOther
+	}
+	uri := payload.uri
+	switch {
+	case strings.HasPrefix(uri, "/query"):
+		return EndpointQuery
+	case strings.HasPrefix(uri, "/sobjects"):
+		return EndpointSObject
+	case strings.HasPrefix(uri, "/composite"):
+		return EndpointComposite
+	case strings.HasPrefix(uri, "/jobs/"):
+		return EndpointBulk
+	case strings.HasPrefix(uri, "/tooling/"):
+		return EndpointTooling
+	}
+	return EndpointOther
+}
+
+// waitForRateLimit waits on the global limiter and then on the limiter of the endpoint class, if any
+func waitForRateLimit(ctx context.Context, config *configuration, payload requestPayload) error {
+	if err := config.rateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	if len(config.endpointRateLimiters) == 0 {
+		return nil
+	}
+	return config.endpointRateLimiters[classifyEndpoint(payload)].Wait(ctx)
+}
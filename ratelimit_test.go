@@ -0,0 +1,135 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_classifyEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload requestPayload
+		want    EndpointClass
+	}{
+		{
+			name:    "query",
+			payload: requestPayload{uri: "/query/?q=SELECT+Id+FROM+Account"},
+			want:    EndpointQuery,
+		},
+		{
+			name:    "query_next_page",
+			payload: requestPayload{uri: "/query/01gD-2000"},
+			want:    EndpointQuery,
+		},
+		{
+			name:    "query_all",
+			payload: requestPayload{uri: "/queryAll/?q=SELECT+Id+FROM+Account"},
+			want:    EndpointQuery,
+		},
+		{
+			name:    "sobject",
+			payload: requestPayload{uri: "/sobjects/Account/001"},
+			want:    EndpointSObject,
+		},
+		{
+			name:    "collection",
+			payload: requestPayload{uri: "/composite/sobjects/"},
+			want:    EndpointComposite,
+		},
+		{name: "composite", payload: requestPayload{uri: "/composite"}, want: EndpointComposite},
+		{name: "bulk", payload: requestPayload{uri: "/jobs/ingest/750"}, want: EndpointBulk},
+		{
+			name:    "tooling",
+			payload: requestPayload{uri: "/tooling/runTestsAsynchronous"},
+			want:    EndpointTooling,
+		},
+		{name: "limits", payload: requestPayload{uri: "/limits"}, want: EndpointOther},
+		{
+			name:    "instance_uri",
+			payload: requestPayload{uri: "/services/apexrest/query", instanceUri: true},
+			want:    EndpointOther,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyEndpoint(tt.payload); got != tt.want {
+				t.Errorf("classifyEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	if _, err := NewRateLimiter(0, 1); err == nil {
+		t.Fatal("NewRateLimiter() expected an error for a rate of 0")
+	}
+	limiter, err := NewRateLimiter(1, 2)
+	if err != nil {
+		t.Fatalf("NewRateLimiter() error = %v", err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	// the burst is let through at once
+	for i := range 2 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() %d error = %v", i, err)
+		}
+	}
+	// the next request waits for a token, until its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+	// tokens are added at the rate, and the canceled request gave its token back
+	now = now.Add(time.Second)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() after refill error = %v", err)
+	}
+	if limiter.tokens != 0 {
+		t.Errorf("tokens = %v, want 0", limiter.tokens)
+	}
+	// the bucket holds no more than the burst
+	now = now.Add(time.Hour)
+	_ = limiter.Wait(context.Background())
+	if limiter.tokens != 1 {
+		t.Errorf("tokens = %v, want 1", limiter.tokens)
+	}
+}
+
+func TestWithEndpointRateLimiter_requests(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"done":true,"records":[]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	limiter, _ := NewRateLimiter(0.001, 1)
+	if err := WithEndpointRateLimiter(EndpointQuery, limiter)(sf.config); err != nil {
+		t.Fatalf("WithEndpointRateLimiter() error = %v", err)
+	}
+
+	if _, err := getQueryPage(context.Background(), sf, "/query/?q=SELECT+Id+FROM+Account"); err != nil {
+		t.Fatalf("first query error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := getQueryPage(ctx, sf, "/query/?q=SELECT+Id+FROM+Account")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second query error = %v, want context.DeadlineExceeded", err)
+	}
+	// other endpoint classes are not limited
+	if _, err := sf.DoRequest(http.MethodGet, "/limits", nil); err != nil {
+		t.Errorf("DoRequest() error = %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server received %d requests, want 2", hits.Load())
+	}
+}
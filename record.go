@@ -0,0 +1,221 @@
+package salesforce
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is a record with dynamic access to its fields, for callers who prefer it over decoding into
+// structs. Query results decode into a []Record. Getters take a field name or a path through related
+// records, such as Account.Owner.Name, and return the zero value when a field or related record is
+// null, missing, or cannot be converted. Field names are matched case-insensitively.
+type Record map[string]any
+
+// Type returns the sObject type of the record from its attributes
+func (r Record) Type() string {
+	return recordType(r)
+}
+
+// Id returns the Id of the record
+func (r Record) Id() string {
+	return r.GetString("Id")
+}
+
+// IsNull returns whether a field is null or missing
+func (r Record) IsNull(path string) bool {
+	return r.GetRelated(path) == nil
+}
+
+// Has returns whether a field is present, even if it is null, to tell a null field apart from one that
+// was not queried. A field of a null related record is not present.
+func (r Record) Has(path string) bool {
+	names := strings.Split(path, ".")
+	fields := map[string]any(r)
+	for i, name := range names {
+		value, ok := recordField(fields, name)
+		if !ok {
+			return false
+		}
+		if i == len(names)-1 {
+			return true
+		}
+		if fields, ok = recordFields(value); !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// GetRelated returns the value of a field or of a path through related records, such as
+// Account.Name, or nil if it or a related record along the path is null or missing
+func (r Record) GetRelated(path string) any {
+	var value any = map[string]any(r)
+	for _, name := range strings.Split(path, ".") {
+		fields, ok := recordFields(value)
+		if !ok {
+			return nil
+		}
+		value, _ = recordField(fields, name)
+	}
+	return value
+}
+
+// GetString returns a field as a string, formatting numbers and booleans
+func (r Record) GetString(path string) string {
+	value := r.GetRelated(path)
+	if number, ok := formatNumber(value); ok {
+		return number
+	}
+	switch value := value.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
+
+// GetFloat returns a number field, such as a currency, percent, or double field, as a float64
+func (r Record) GetFloat(path string) float64 {
+	value := r.GetRelated(path)
+	if number, ok := numberValue(value); ok {
+		return number
+	}
+	if text, ok := value.(string); ok {
+		f, _ := strconv.ParseFloat(text, 64)
+		return f
+	}
+	return 0
+}
+
+// GetInt returns a number field as an int, truncating any decimals
+func (r Record) GetInt(path string) int {
+	if value, ok := r.GetRelated(path).(int64); ok {
+		return int(value)
+	}
+	return int(r.GetFloat(path))
+}
+
+// GetBool returns a checkbox field as a bool
+func (r Record) GetBool(path string) bool {
+	switch value := r.GetRelated(path).(type) {
+	case bool:
+		return value
+	case string:
+		b, _ := strconv.ParseBool(value)
+		return b
+	}
+	return false
+}
+
+// GetTime returns a date, datetime, or time field as a time.Time
+func (r Record) GetTime(path string) time.Time {
+	value := r.GetRelated(path)
+	if value == nil {
+		return time.Time{}
+	}
+	t, _ := parseSalesforceTime(value)
+	return t
+}
+
+// GetRecord returns a related record, such as Account or Account.Owner, or nil if it is null
+func (r Record) GetRecord(path string) Record {
+	fields, ok := recordFields(r.GetRelated(path))
+	if !ok {
+		return nil
+	}
+	return Record(fields)
+}
+
+// GetRecords returns the records of a child relationship subquery, such as
+// (SELECT Id FROM Contacts), or nil if it has no records
+func (r Record) GetRecords(path string) []Record {
+	result, ok := recordFields(r.GetRelated(path))
+	if !ok {
+		return nil
+	}
+	children, _ := result["records"].([]any)
+	records := make([]Record, 0, len(children))
+	for _, child := range children {
+		if fields, ok := recordFields(child); ok {
+			records = append(records, Record(fields))
+		}
+	}
+	return records
+}
+
+func recordFields(value any) (map[string]any, bool) {
+	switch fields := value.(type) {
+	case map[string]any:
+		return fields, true
+	case Record:
+		return fields, true
+	}
+	return nil, false
+}
+
+// FieldsPresent is the set of fields present in a decoded record, including fields that are null. A
+// struct field of this type is filled when records are decoded into the struct, so that a null field,
+// decoded into a nil pointer or a zero value, can be told apart from a field that was not queried.
+type FieldsPresent map[string]bool
+
+// Has returns whether a field was present, matching its name case-insensitively
+func (f FieldsPresent) Has(field string) bool {
+	if f[field] {
+		return true
+	}
+	for name := range f {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsPresentDecodeHook adds the fields of a record to the FieldsPresent field of the struct it is
+// decoded into, if the struct has one
+func fieldsPresentDecodeHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	record, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+	for to.Kind() == reflect.Pointer {
+		to = to.Elem()
+	}
+	if to.Kind() != reflect.Struct {
+		return data, nil
+	}
+	fieldsPresentType := reflect.TypeOf(FieldsPresent{})
+	for i := 0; i < to.NumField(); i++ {
+		field := to.Field(i)
+		if field.Type != fieldsPresentType || !field.IsExported() {
+			continue
+		}
+		present := make(map[string]bool, len(record))
+		withPresent := make(map[string]any, len(record)+1)
+		for name, value := range record {
+			if name != "attributes" {
+				present[name] = true
+			}
+			withPresent[name] = value
+		}
+		withPresent[fieldsPresentKey(field)] = present
+		return withPresent, nil
+	}
+	return data, nil
+}
+
+// fieldsPresentKey returns the key a struct field is decoded from, honoring salesforce and
+// mapstructure struct tags
+func fieldsPresentKey(field reflect.StructField) string {
+	for _, tagName := range []string{"salesforce", "mapstructure"} {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,206 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	record := Record{}
+	err := json.Unmarshal([]byte(`{
+		"attributes": {"type": "Contact"},
+		"Id": "003000000000001AAA",
+		"LastName": "Smith",
+		"Email": null,
+		"NumberOfEmployees__c": 42,
+		"Score__c": "7.5",
+		"HasOptedOutOfEmail": true,
+		"Birthdate": "1990-05-17",
+		"LastModifiedDate": "2024-01-02T03:04:05.000+0000",
+		"Account": {
+			"attributes": {"type": "Account"},
+			"Name": "Acme",
+			"Owner": {"attributes": {"type": "User"}, "Name": "Jane"},
+			"Parent": null
+		},
+		"Cases": {
+			"totalSize": 2,
+			"done": true,
+			"records": [{"Id": "500000000000001AAA"}, {"Id": "500000000000002AAA"}]
+		}
+	}`), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Type() != "Contact" || record.Id() != "003000000000001AAA" {
+		t.Errorf("Type() = %v, Id() = %v", record.Type(), record.Id())
+	}
+	stringTests := map[string]string{
+		"LastName":             "Smith",
+		"lastname":             "Smith",
+		"Email":                "",
+		"Missing":              "",
+		"NumberOfEmployees__c": "42",
+		"HasOptedOutOfEmail":   "true",
+		"Account.Name":         "Acme",
+		"Account.Owner.Name":   "Jane",
+		"Account.Parent.Name":  "",
+		"LastName.Invalid":     "",
+	}
+	for path, want := range stringTests {
+		if got := record.GetString(path); got != want {
+			t.Errorf("GetString(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if got := record.GetFloat("Score__c"); got != 7.5 {
+		t.Errorf("GetFloat() = %v, want 7.5", got)
+	}
+	if got := record.GetInt("NumberOfEmployees__c"); got != 42 {
+		t.Errorf("GetInt() = %v, want 42", got)
+	}
+	if got := record.GetFloat("Email"); got != 0 {
+		t.Errorf("GetFloat() = %v, want 0", got)
+	}
+	if !record.GetBool("HasOptedOutOfEmail") || record.GetBool("Email") {
+		t.Errorf("GetBool() = %v", record.GetBool("HasOptedOutOfEmail"))
+	}
+	wantTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := record.GetTime("LastModifiedDate"); !got.Equal(wantTime) {
+		t.Errorf("GetTime() = %v, want %v", got, wantTime)
+	}
+	if got := record.GetTime("Birthdate"); !got.Equal(
+		time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+	) {
+		t.Errorf("GetTime() = %v", got)
+	}
+	if got := record.GetTime("Email"); !got.IsZero() {
+		t.Errorf("GetTime() = %v, want zero", got)
+	}
+	if !record.IsNull("Email") || !record.IsNull("Account.Parent") ||
+		record.IsNull("Account.Name") {
+		t.Error("IsNull() returned the wrong value")
+	}
+	if account := record.GetRecord("Account"); account.Type() != "Account" ||
+		account.GetString("Owner.Name") != "Jane" {
+		t.Errorf("GetRecord() = %v", account)
+	}
+	if record.GetRecord("Account.Parent") != nil {
+		t.Error("GetRecord() of a null relationship should be nil")
+	}
+	cases := record.GetRecords("Cases")
+	if len(cases) != 2 || cases[1].Id() != "500000000000002AAA" {
+		t.Errorf("GetRecords() = %v", cases)
+	}
+	if record.GetRecords("Opportunities") != nil {
+		t.Error("GetRecords() of a missing subquery should be nil")
+	}
+}
+
+func TestRecord_Query(t *testing.T) {
+	server, sfAuth := setupTestServer(queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records: []map[string]any{{
+			"attributes": map[string]any{"type": "Contact"},
+			"Id":         "003000000000001AAA",
+			"Account":    map[string]any{"Name": "Acme"},
+		}},
+	}, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	records := []Record{}
+	if err := sf.Query("SELECT Id, Account.Name FROM Contact", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Type() != "Contact" ||
+		records[0].GetString("Account.Name") != "Acme" {
+		t.Errorf("Query() = %v", records)
+	}
+}
+
+func TestRecord_Has(t *testing.T) {
+	record := Record{
+		"Id":      "003000000000001AAA",
+		"Email":   nil,
+		"Account": map[string]any{"Name": "Acme", "Parent": nil},
+		"Owner":   nil,
+	}
+	tests := map[string]bool{
+		"Id":                  true,
+		"email":               true,
+		"Phone":               false,
+		"Account.Name":        true,
+		"Account.Parent":      true,
+		"Account.Parent.Name": false,
+		"Account.Industry":    false,
+		"Owner":               true,
+		"Owner.Name":          false,
+	}
+	for path, want := range tests {
+		if got := record.Has(path); got != want {
+			t.Errorf("Has(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestFieldsPresent(t *testing.T) {
+	type account struct {
+		Name    string
+		Present FieldsPresent
+	}
+	type contact struct {
+		Id       string
+		Email    *string
+		Phone    *string
+		Account  *account
+		Presence FieldsPresent `salesforce:"Fields"`
+	}
+	records := []map[string]any{
+		{
+			"attributes": map[string]any{"type": "Contact"},
+			"Id":         "003000000000001AAA",
+			"Email":      nil,
+			"Account":    map[string]any{"Name": "Acme"},
+		},
+	}
+
+	// strict decoding runs last, with every field of the struct present
+	for _, strict := range []bool{false, true} {
+		config := &configuration{}
+		config.setDefaults()
+		config.strictDecoding = strict
+		if strict {
+			records[0]["Phone"] = "555"
+		}
+
+		contacts := []contact{}
+		if err := decodeQueryRecords(config, records, &contacts); err != nil {
+			t.Fatalf("decodeQueryRecords() error = %v", err)
+		}
+		got := contacts[0]
+		if !got.Presence.Has("email") || got.Email != nil || got.Presence.Has("attributes") {
+			t.Errorf("Presence = %v, Email = %v", got.Presence, got.Email)
+		}
+		if got.Presence.Has("Phone") != strict {
+			t.Errorf("Presence.Has(Phone) = %v, want %v", got.Presence.Has("Phone"), strict)
+		}
+		if got.Account == nil || !got.Account.Present.Has("Name") || got.Account.Present.Has("Id") {
+			t.Errorf("Account = %v", got.Account)
+		}
+		if _, ok := records[0]["Fields"]; ok {
+			t.Error("decodeQueryRecords() modified the records")
+		}
+
+		recordMap, err := convertToMap(got)
+		if err != nil {
+			t.Fatalf("convertToMap() error = %v", err)
+		}
+		if _, ok := recordMap["Fields"]; ok {
+			t.Errorf("convertToMap() = %v, want no FieldsPresent field", recordMap)
+		}
+	}
+}
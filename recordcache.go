@@ -0,0 +1,261 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recordCacheBatchSize is the number of ids queried at once when fetching records missing from a
+// RecordCache, keeping queries well below the SOQL length limit
+const recordCacheBatchSize = 200
+
+// RecordCache caches records by Id, so read-heavy services can read records without a query each time.
+// Entries expire after the cache's ttl, and are removed as soon as a Change Data Capture event for
+// their record is passed to HandleChangeEvent, keeping the cache consistent with the org in near real
+// time. It is safe for concurrent use.
+type RecordCache struct {
+	sf      *Salesforce
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedRecord // keyed by 18 character id
+	now     func() time.Time
+}
+
+type cachedRecord struct {
+	sObjectName string
+	record      map[string]any
+	expires     time.Time
+}
+
+// NewRecordCache returns an empty RecordCache whose entries expire after ttl, which bounds how stale a
+// record can be if change events are missed
+func (sf *Salesforce) NewRecordCache(ttl time.Duration) (*RecordCache, error) {
+	if ttl <= 0 {
+		return nil, errors.New("record cache ttl must be greater than 0")
+	}
+	return &RecordCache{
+		sf:      sf,
+		ttl:     ttl,
+		entries: map[string]cachedRecord{},
+		now:     time.Now,
+	}, nil
+}
+
+// Query runs a query, caches every returned record that includes its Id, and decodes the records into
+// records, like Salesforce.Query. The query is always sent; use Get to read cached records.
+func (c *RecordCache) Query(query string, records any) error {
+	authErr := validateAuth(*c.sf)
+	if authErr != nil {
+		return authErr
+	}
+	results, err := queryAllRecords(context.Background(), c.sf, query)
+	if err != nil {
+		return err
+	}
+	c.store("", results)
+	return mapstructureDecode(results, records)
+}
+
+// Get decodes the records with the given ids into records, a pointer to a slice of structs, in the
+// order of ids. Records that are cached with every field of the struct are read from the cache, and
+// the others are queried and cached. Ids that do not match a record are skipped. An empty sObjectName
+// is inferred from the struct type, as with DML operations.
+func (c *RecordCache) Get(sObjectName string, ids []string, records any) error {
+	authErr := validateAuth(*c.sf)
+	if authErr != nil {
+		return authErr
+	}
+	recordsValue := reflect.ValueOf(records)
+	if recordsValue.Kind() != reflect.Pointer || recordsValue.Elem().Kind() != reflect.Slice {
+		return errors.New("records must be a pointer to a slice of structs")
+	}
+	structType := structElem(recordsValue.Type())
+	if structType == nil {
+		return errors.New("records must be a pointer to a slice of structs")
+	}
+	sObjectName, err := inferSObjectName(c.sf, sObjectName, records)
+	if err != nil {
+		return err
+	}
+	fields := soqlFieldNames(structType)
+	hasId := false
+	for _, field := range fields {
+		hasId = hasId || strings.EqualFold(field, "Id")
+	}
+	if !hasId {
+		fields = append([]string{"Id"}, fields...)
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		key, err := recordCacheKey(id)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+	found := map[string]map[string]any{}
+	queued := map[string]bool{}
+	var missing []string
+	for _, key := range keys {
+		if record, ok := c.lookup(key, sObjectName, fields); ok {
+			found[key] = record
+		} else if !queued[key] {
+			queued[key] = true
+			missing = append(missing, key)
+		}
+	}
+
+	for start := 0; start < len(missing); start += recordCacheBatchSize {
+		batch := missing[start:min(start+recordCacheBatchSize, len(missing))]
+		quoted := make([]string, len(batch))
+		for i, key := range batch {
+			quoted[i] = "'" + escapeSoqlString(key) + "'"
+		}
+		query := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE Id IN (%s)",
+			strings.Join(fields, ", "),
+			sObjectName,
+			strings.Join(quoted, ","),
+		)
+		results, err := queryAllRecords(context.Background(), c.sf, query)
+		if err != nil {
+			return err
+		}
+		for key, record := range c.store(sObjectName, results) {
+			found[key] = record
+		}
+	}
+
+	ordered := []map[string]any{}
+	for _, key := range keys {
+		if record, ok := found[key]; ok {
+			ordered = append(ordered, record)
+		}
+	}
+	return mapstructureDecode(ordered, records)
+}
+
+// HandleChangeEvent removes the records of a Change Data Capture event from the cache. Gap overflow
+// events, which do not list their records, remove every cached record of the event's sObject.
+func (c *RecordCache) HandleChangeEvent(event ChangeEvent) {
+	c.sf.config.metrics.ObserveChangeEvent(event)
+	if len(event.Header.RecordIds) == 0 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for key, entry := range c.entries {
+			if strings.EqualFold(entry.sObjectName, event.Header.EntityName) {
+				delete(c.entries, key)
+			}
+		}
+		return
+	}
+	c.Invalidate(event.Header.RecordIds...)
+}
+
+// Invalidate removes the records with the given ids from the cache
+func (c *RecordCache) Invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		if key, err := recordCacheKey(id); err == nil {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Clear removes every record from the cache
+func (c *RecordCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]cachedRecord{}
+}
+
+// lookup returns the cached record of a key if it has not expired and has every field
+func (c *RecordCache) lookup(
+	key string,
+	sObjectName string,
+	fields []string,
+) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	if !strings.EqualFold(entry.sObjectName, sObjectName) {
+		return nil, false
+	}
+	for _, field := range fields {
+		if !hasRecordPath(entry.record, field) {
+			return nil, false
+		}
+	}
+	return entry.record, true
+}
+
+// store caches query results that include their Id and returns them keyed by 18 character id. The
+// sObject name is read from the record attributes when it is empty.
+func (c *RecordCache) store(
+	sObjectName string,
+	records []map[string]any,
+) map[string]map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored := map[string]map[string]any{}
+	expires := c.now().Add(c.ttl)
+	for _, record := range records {
+		id, _ := recordField(record, "Id")
+		key, err := recordCacheKey(fmt.Sprint(id))
+		if err != nil {
+			continue
+		}
+		name := sObjectName
+		if attributes, ok := record["attributes"].(map[string]any); ok && name == "" {
+			name, _ = attributes["type"].(string)
+		}
+		c.entries[key] = cachedRecord{sObjectName: name, record: record, expires: expires}
+		stored[key] = record
+	}
+	return stored
+}
+
+// recordCacheKey returns the 18 character form of an id with an upper case checksum, so that every
+// form of an id has the same key
+func recordCacheKey(id string) (string, error) {
+	id, err := To18CharId(id)
+	if err != nil {
+		return "", err
+	}
+	return id[:15] + strings.ToUpper(id[15:]), nil
+}
+
+// hasRecordPath returns whether a record has a field, following relationship names such as
+// Account.Name. Fields of a null relationship are treated as present.
+func hasRecordPath(record map[string]any, path string) bool {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		value, ok := recordField(record, part)
+		if !ok {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		related, isMap := value.(map[string]any)
+		if !isMap {
+			return value == nil
+		}
+		record = related
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_hasRecordPath(t *testing.T) {
+	record := map[string]any{
+		"Id":      "001",
+		"Name":    "Acme",
+		"Owner":   map[string]any{"Name": "Jane"},
+		"Parent":  nil,
+		"Website": nil,
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "Name", want: true},
+		{path: "name", want: true},
+		{path: "Website", want: true},
+		{path: "Owner.Name", want: true},
+		{path: "Parent.Name", want: true},
+		{path: "Owner.Email", want: false},
+		{path: "Industry", want: false},
+		{path: "Name.First", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := hasRecordPath(record, tt.path); got != tt.want {
+				t.Errorf("hasRecordPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordCache(t *testing.T) {
+	acmeId, _ := To18CharId("001000000000001")
+	globexId, _ := To18CharId("001000000000002")
+	names := map[string]string{acmeId: "Acme", globexId: "Globex"}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		queries = append(queries, query)
+		records := []map[string]any{}
+		for id, name := range names {
+			if strings.Contains(query, id) || !strings.Contains(query, "WHERE") {
+				records = append(records, map[string]any{
+					"attributes": map[string]any{"type": "Account"},
+					"Id":         id,
+					"Name":       name,
+				})
+			}
+		}
+		body, _ := json.Marshal(
+			queryResponse{Done: true, TotalSize: len(records), Records: records},
+		)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	cache, err := sf.NewRecordCache(time.Hour)
+	if err != nil {
+		t.Fatalf("NewRecordCache() error = %v", err)
+	}
+
+	type Account struct {
+		Id   string
+		Name string
+	}
+	get := func(ids ...string) []Account {
+		t.Helper()
+		accounts := []Account{}
+		if err := cache.Get("", ids, &accounts); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return accounts
+	}
+	want := []Account{{Id: globexId, Name: "Globex"}, {Id: acmeId, Name: "Acme"}}
+
+	if got := get(globexId, acmeId[:15]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %v, want %v", got, want)
+	}
+	if got := get(globexId, strings.ToLower(acmeId)); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached Get() = %v, want %v", got, want)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("sent %d queries, want 1: %v", len(queries), queries)
+	}
+
+	// a change event removes its records, which are queried again
+	names[acmeId] = "Acme Corp"
+	cache.HandleChangeEvent(ChangeEvent{
+		Header: ChangeEventHeader{
+			EntityName: "Account",
+			ChangeType: ChangeTypeUpdate,
+			RecordIds:  []string{acmeId},
+		},
+	})
+	got := get(acmeId, globexId)
+	if got[0].Name != "Acme Corp" || len(queries) != 2 ||
+		strings.Contains(queries[1], globexId) {
+		t.Errorf("Get() after change event = %v with queries %v", got, queries)
+	}
+
+	// records cached by a query are read by Get, and gap overflow events clear the sObject
+	cache.Clear()
+	accounts := []Account{}
+	if err := cache.Query("SELECT Id, Name FROM Account", &accounts); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	get(acmeId, globexId)
+	if len(queries) != 3 {
+		t.Errorf("sent %d queries, want 3: %v", len(queries), queries)
+	}
+	cache.HandleChangeEvent(ChangeEvent{
+		Header: ChangeEventHeader{EntityName: "Account", ChangeType: ChangeTypeGapOverflow},
+	})
+	get(acmeId)
+	if len(queries) != 4 {
+		t.Errorf("sent %d queries, want 4: %v", len(queries), queries)
+	}
+
+	// fields that are not cached are queried
+	type AccountWithIndustry struct {
+		Id       string
+		Name     string
+		Industry string
+		_        struct{} `salesforce:"object=Account"`
+	}
+	withIndustry := []AccountWithIndustry{}
+	if err := cache.Get("", []string{acmeId}, &withIndustry); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(queries) != 5 ||
+		!strings.HasPrefix(queries[4], "SELECT Id, Name, Industry FROM Account") {
+		t.Errorf("queries = %v", queries)
+	}
+
+	if err := cache.Get("Account", []string{"bad"}, &accounts); err == nil {
+		t.Error("Get() expected an error for an invalid id")
+	}
+	if err := cache.Get("Account", []string{acmeId}, accounts); err == nil {
+		t.Error("Get() expected an error for records that are not a pointer to a slice")
+	}
+	if _, err := sf.NewRecordCache(0); err == nil {
+		t.Error("NewRecordCache() expected an error for a zero ttl")
+	}
+}
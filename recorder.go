@@ -0,0 +1,368 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// RecorderMode controls whether a Recorder captures real responses or replays a cassette
+type RecorderMode int
+
+const (
+	// RecorderModeAuto replays the cassette if it exists, otherwise records a new one
+	RecorderModeAuto RecorderMode = iota
+	// RecorderModeRecord sends requests to Salesforce and records the responses
+	RecorderModeRecord
+	// RecorderModeReplay serves responses from the cassette without network access
+	RecorderModeReplay
+)
+
+const redactedValue = "REDACTED"
+
+// recordedHeaders are the response headers kept in a cassette, all others are dropped
+var recordedHeaders = []string{"Content-Type", "Location", limitInfoHeader}
+
+// sensitiveFields are redacted from recorded json, form, and query values
+var sensitiveFields = []string{
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"signature",
+	"client_id",
+	"client_secret",
+	"username",
+	"password",
+	"assertion",
+	"token",
+	"sessionId",
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request stored in a cassette. Only the path and query of the url are kept
+// so that cassettes replay against any instance.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URI    string `json:"uri"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response stored in a cassette
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is the fixture file written and read by a Recorder
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is a http.RoundTripper that records Salesforce responses to a cassette file and replays
+// them in tests. Use it with WithRoundTripper. Recorded interactions are sanitized: request headers
+// and most response headers are dropped, the host is removed from urls, and credentials and tokens
+// are redacted from bodies. Set Sanitize to redact additional data before an interaction is stored.
+type Recorder struct {
+	// Sanitize is called on every interaction before it is recorded and on every request before it is
+	// matched during replay
+	Sanitize func(*Interaction)
+
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a Recorder for the cassette at path. Requests are sent with transport when
+// recording, http.DefaultTransport is used if it is nil.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if path == "" {
+		return nil, errors.New("cassette path cannot be empty")
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	recorder := &Recorder{path: path, mode: mode, transport: transport}
+
+	data, err := afero.ReadFile(appFs, path)
+	if errors.Is(err, os.ErrNotExist) && mode != RecorderModeReplay {
+		recorder.mode = RecorderModeRecord
+		return recorder, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+	if mode == RecorderModeRecord {
+		return recorder, nil
+	}
+
+	recorder.mode = RecorderModeReplay
+	if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+		return nil, fmt.Errorf("decoding cassette: %w", err)
+	}
+	recorder.used = make([]bool, len(recorder.cassette.Interactions))
+	return recorder, nil
+}
+
+// Mode returns whether the recorder is recording or replaying
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URI:    req.URL.RequestURI(),
+			Body:   body,
+		},
+	}
+
+	if r.mode == RecorderModeReplay {
+		return r.replay(req, interaction)
+	}
+	return r.record(req, interaction)
+}
+
+func (r *Recorder) record(req *http.Request, interaction Interaction) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		body, err = gunzip(body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.ContentLength = int64(len(body))
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range recordedHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	interaction.Response = RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       string(body),
+	}
+	r.sanitize(&interaction)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, interaction Interaction) (*http.Response, error) {
+	r.sanitize(&interaction)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, recorded := range r.cassette.Interactions {
+		if r.used[i] || recorded.Request != interaction.Request {
+			continue
+		}
+		r.used[i] = true
+		header := recorded.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status: fmt.Sprintf(
+				"%d %s",
+				recorded.Response.StatusCode,
+				http.StatusText(recorded.Response.StatusCode),
+			),
+			StatusCode:    recorded.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Response.Body))),
+			ContentLength: int64(len(recorded.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf(
+		"no recorded interaction for %s %s",
+		interaction.Request.Method,
+		interaction.Request.URI,
+	)
+}
+
+// Unused returns the recorded interactions that have not been replayed
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// Stop writes the cassette when recording. It is a no-op when replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(appFs, r.path, data, 0o644)
+}
+
+func (r *Recorder) sanitize(interaction *Interaction) {
+	interaction.Request.URI = redactURI(interaction.Request.URI)
+	interaction.Request.Body = redactBody(interaction.Request.Body)
+	interaction.Response.Body = redactBody(interaction.Response.Body)
+	if r.Sanitize != nil {
+		r.Sanitize(interaction)
+	}
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if closeErr := req.Body.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		plain, err := gunzip(body)
+		if err != nil {
+			return "", err
+		}
+		body = plain
+	}
+	return string(body), nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	body, err := decompress(io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	return io.ReadAll(body)
+}
+
+func redactURI(uri string) string {
+	parsed, err := url.ParseRequestURI(uri)
+	if err != nil || parsed.RawQuery == "" {
+		return uri
+	}
+	query := parsed.Query()
+	if !redactValues(query) {
+		return uri
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.RequestURI()
+}
+
+// redactBody redacts sensitive fields of json and form encoded bodies, other bodies are unchanged
+func redactBody(body string) string {
+	if body == "" {
+		return body
+	}
+	var data any
+	if err := json.Unmarshal([]byte(body), &data); err == nil {
+		if !redactJSON(data) {
+			return body
+		}
+		redacted, err := json.Marshal(data)
+		if err != nil {
+			return body
+		}
+		return string(redacted)
+	}
+	if form, err := url.ParseQuery(body); err == nil && redactValues(form) {
+		return form.Encode()
+	}
+	return body
+}
+
+func redactJSON(data any) bool {
+	redacted := false
+	switch value := data.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if isSensitiveField(key) {
+				value[key] = redactedValue
+				redacted = true
+			} else if redactJSON(field) {
+				redacted = true
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if redactJSON(item) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+func redactValues(values url.Values) bool {
+	redacted := false
+	for key := range values {
+		if isSensitiveField(key) {
+			values.Set(key, redactedValue)
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+func isSensitiveField(name string) bool {
+	return slices.Contains(sensitiveFields, name)
+}
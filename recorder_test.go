@@ -0,0 +1,141 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestRecorder(t *testing.T) {
+	type account struct {
+		Id   string
+		Name string
+	}
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+
+	resp := queryResponse{
+		TotalSize: 1,
+		Done:      true,
+		Records:   []map[string]any{{"Id": "123abc", "Name": "test account"}},
+	}
+	server, sfAuth := setupTestServer(resp, http.StatusOK)
+
+	// record against the test server
+	recorder, err := NewRecorder("cassette.json", RecorderModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if recorder.Mode() != RecorderModeRecord {
+		t.Fatalf("NewRecorder() mode = %v, want record", recorder.Mode())
+	}
+	sf := buildSalesforceStruct(&sfAuth)
+	sf.config.roundTripper = recorder
+	sf.config.configureHttpClient()
+
+	var recorded []account
+	if err := sf.Query("SELECT Id, Name FROM Account", &recorded); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	server.Close()
+
+	data, err := afero.ReadFile(appFs, "cassette.json")
+	if err != nil {
+		t.Fatalf("reading cassette error = %v", err)
+	}
+	cassette := Cassette{}
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		t.Fatalf("decoding cassette error = %v", err)
+	}
+	if len(cassette.Interactions) != 1 {
+		t.Fatalf("cassette interactions = %d, want 1", len(cassette.Interactions))
+	}
+	if strings.Contains(string(data), server.URL) ||
+		strings.Contains(string(data), "Authorization") {
+		t.Errorf("cassette is not sanitized: %s", data)
+	}
+
+	// replay without the test server
+	replayer, err := NewRecorder("cassette.json", RecorderModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if replayer.Mode() != RecorderModeReplay {
+		t.Fatalf("NewRecorder() mode = %v, want replay", replayer.Mode())
+	}
+	sf.config.roundTripper = replayer
+	sf.config.configureHttpClient()
+
+	var replayed []account
+	if err := sf.Query("SELECT Id, Name FROM Account", &replayed); err != nil {
+		t.Fatalf("Query() replay error = %v", err)
+	}
+	if len(replayed) != 1 || replayed[0] != recorded[0] {
+		t.Errorf("Query() replay = %v, want %v", replayed, recorded)
+	}
+	if len(replayer.Unused()) != 0 {
+		t.Errorf("Unused() = %v, want none", replayer.Unused())
+	}
+
+	// interactions are only replayed once
+	if err := sf.Query("SELECT Id, Name FROM Account", &replayed); err == nil {
+		t.Errorf("Query() expected error when the cassette has no matching interaction")
+	}
+}
+
+func TestNewRecorder_missingCassette(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+
+	if _, err := NewRecorder("missing.json", RecorderModeReplay, nil); err == nil {
+		t.Errorf("NewRecorder() expected error for missing cassette in replay mode")
+	}
+	if _, err := NewRecorder("", RecorderModeAuto, nil); err == nil {
+		t.Errorf("NewRecorder() expected error for empty path")
+	}
+}
+
+func Test_redactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "json",
+			body: `{"access_token":"secret","instance_url":"https://example"}`,
+			want: `{"access_token":"REDACTED","instance_url":"https://example"}`,
+		},
+		{
+			name: "nested_json",
+			body: `{"records":[{"sessionId":"secret"}]}`,
+			want: `{"records":[{"sessionId":"REDACTED"}]}`,
+		},
+		{
+			name: "form",
+			body: "client_id=id&client_secret=secret&grant_type=client_credentials",
+			want: "client_id=REDACTED&client_secret=REDACTED&grant_type=client_credentials",
+		},
+		{
+			name: "unchanged",
+			body: `{"Name":"test account"}`,
+			want: `{"Name":"test account"}`,
+		},
+		{
+			name: "csv",
+			body: "Id,Name\n123,test",
+			want: "Id,Name\n123,test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody(tt.body); got != tt.want {
+				t.Errorf("redactBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package salesforce
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// defaultRedactedFieldTypes are the describe field types that hold personal data in most orgs
+var defaultRedactedFieldTypes = []string{"email", "phone", "encryptedstring"}
+
+// RedactionSettings configures which fields Redact masks, see WithRedaction. Zero values take the
+// defaults noted on each field. Fields encrypted with Shield Platform Encryption are always masked.
+type RedactionSettings struct {
+	FieldTypes []string // describe field types to mask (default email, phone, and encryptedstring)
+	Fields     []string // field names to mask on every sObject, or on one sObject as Contact.Birthdate
+	Mask       string   // value masked fields are replaced with (default [REDACTED])
+}
+
+// Redact returns a copy of a record with its personal data masked, so it can be logged or exported
+// without leaking customer PII. Fields are masked based on their describe metadata and the settings
+// of WithRedaction. Related records and the records of subqueries are redacted too. Null fields are
+// left null. If sObjectName is empty, it is read from the record's attributes, or inferred from the
+// struct type name like DML operations.
+func (sf *Salesforce) Redact(sObjectName string, record any) (map[string]any, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if record == nil {
+		return nil, errors.New("record cannot be nil")
+	}
+	if recordMap, ok := record.(map[string]any); ok && sObjectName == "" {
+		sObjectName = recordType(recordMap)
+	}
+	sObjectName, err := inferSObjectName(sf, sObjectName, record)
+	if err != nil {
+		return nil, err
+	}
+	recordMap, err := convertToMap(record)
+	if err != nil {
+		return nil, err
+	}
+	return redactRecord(sf, sObjectName, recordMap)
+}
+
+func redactRecord(
+	sf *Salesforce,
+	sObjectName string,
+	record map[string]any,
+) (map[string]any, error) {
+	describe, err := describeSObject(sf, sObjectName)
+	if err != nil {
+		return nil, err
+	}
+	settings := sf.config.redaction
+	redacted := make(map[string]any, len(record))
+	for name, value := range record {
+		if name == "attributes" || value == nil {
+			redacted[name] = value
+			continue
+		}
+		if related, ok := value.(map[string]any); ok {
+			redacted[name], err = redactRelated(sf, describe, name, related)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if field, ok := describe.Field(name); ok && shouldRedact(settings, sObjectName, field) {
+			redacted[name] = settings.Mask
+			continue
+		}
+		redacted[name] = value
+	}
+	return redacted, nil
+}
+
+// redactRelated redacts a related record, or the records of a subquery, whose sObject type is read
+// from their attributes or from the relationship field of the parent describe
+func redactRelated(
+	sf *Salesforce,
+	describe *SObjectDescribe,
+	relationshipName string,
+	related map[string]any,
+) (any, error) {
+	if children, ok := subqueryRecords(related); ok {
+		redactedChildren := make([]any, len(children))
+		for i, child := range children {
+			childRecord, ok := recordFields(child)
+			if !ok || recordType(childRecord) == "" {
+				return nil, errors.New(
+					"cannot determine the sObject type of the records of " + relationshipName,
+				)
+			}
+			redactedChild, err := redactRecord(sf, recordType(childRecord), childRecord)
+			if err != nil {
+				return nil, err
+			}
+			redactedChildren[i] = redactedChild
+		}
+		result := make(map[string]any, len(related))
+		for key, value := range related {
+			result[key] = value
+		}
+		result["records"] = redactedChildren
+		return result, nil
+	}
+
+	sObjectName := recordType(related)
+	if sObjectName == "" {
+		for _, field := range describe.Fields {
+			if strings.EqualFold(field.RelationshipName, relationshipName) &&
+				len(field.ReferenceTo) == 1 {
+				sObjectName = field.ReferenceTo[0]
+			}
+		}
+	}
+	if sObjectName == "" {
+		return nil, errors.New(
+			"cannot determine the sObject type of related record " + relationshipName,
+		)
+	}
+	return redactRecord(sf, sObjectName, related)
+}
+
+func shouldRedact(settings RedactionSettings, sObjectName string, field DescribeField) bool {
+	if field.Encrypted || slices.Contains(settings.FieldTypes, field.Type) {
+		return true
+	}
+	for _, name := range settings.Fields {
+		object, fieldName, found := strings.Cut(name, ".")
+		if !found {
+			object, fieldName = sObjectName, name
+		}
+		if strings.EqualFold(object, sObjectName) && strings.EqualFold(fieldName, field.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// subqueryRecords returns the records of a subquery result
+func subqueryRecords(result map[string]any) ([]any, bool) {
+	switch records := result["records"].(type) {
+	case []any:
+		return records, true
+	case []map[string]any:
+		children := make([]any, len(records))
+		for i, record := range records {
+			children[i] = record
+		}
+		return children, true
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_Redact(t *testing.T) {
+	describes := map[string]SObjectDescribe{
+		"Contact": {Name: "Contact", Fields: []DescribeField{
+			{Name: "Id", Type: "id"},
+			{Name: "LastName", Type: "string"},
+			{Name: "Email", Type: "email"},
+			{Name: "Birthdate", Type: "date"},
+			{Name: "SSN__c", Type: "string", Encrypted: true},
+			{
+				Name:             "AccountId",
+				Type:             "reference",
+				ReferenceTo:      []string{"Account"},
+				RelationshipName: "Account",
+			},
+		}},
+		"Account": {Name: "Account", Fields: []DescribeField{
+			{Name: "Name", Type: "string"},
+			{Name: "Phone", Type: "phone"},
+		}},
+		"Case": {Name: "Case", Fields: []DescribeField{
+			{Name: "Subject", Type: "string"},
+			{Name: "SuppliedEmail", Type: "email"},
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, describe := range describes {
+			if strings.HasSuffix(r.URL.Path, "/sobjects/"+name+"/describe") {
+				_ = json.NewEncoder(w).Encode(describe)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.redaction.Fields = []string{"Contact.Birthdate", "Subject"}
+
+	record := map[string]any{
+		"attributes": map[string]any{"type": "Contact"},
+		"Id":         "003000000000001AAA",
+		"LastName":   "Smith",
+		"Email":      "smith@example.com",
+		"Birthdate":  "1990-05-17",
+		"SSN__c":     "123-45-6789",
+		"Account":    map[string]any{"Name": "Acme", "Phone": "555-0100"},
+		"Cases": map[string]any{
+			"totalSize": 1,
+			"done":      true,
+			"records": []any{map[string]any{
+				"attributes":    map[string]any{"type": "Case"},
+				"Subject":       "Billing",
+				"SuppliedEmail": nil,
+			}},
+		},
+	}
+	got, err := sf.Redact("", record)
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	want := map[string]any{
+		"attributes": map[string]any{"type": "Contact"},
+		"Id":         "003000000000001AAA",
+		"LastName":   "Smith",
+		"Email":      "[REDACTED]",
+		"Birthdate":  "[REDACTED]",
+		"SSN__c":     "[REDACTED]",
+		"Account":    map[string]any{"Name": "Acme", "Phone": "[REDACTED]"},
+		"Cases": map[string]any{
+			"totalSize": 1,
+			"done":      true,
+			"records": []any{map[string]any{
+				"attributes":    map[string]any{"type": "Case"},
+				"Subject":       "[REDACTED]",
+				"SuppliedEmail": nil,
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %v, want %v", got, want)
+	}
+	if record["Email"] != "smith@example.com" {
+		t.Error("Redact() modified the record")
+	}
+
+	type Contact struct {
+		LastName string
+		Email    string
+	}
+	got, err = sf.Redact("", Contact{LastName: "Smith", Email: "smith@example.com"})
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	if got["Email"] != "[REDACTED]" || got["LastName"] != "Smith" {
+		t.Errorf("Redact() = %v", got)
+	}
+
+	if _, err := sf.Redact("", map[string]any{"Email": "smith@example.com"}); err == nil {
+		t.Error("Redact() expected an error without an sObject name")
+	}
+	if _, err := sf.Redact("Contact", map[string]any{
+		"Cases": map[string]any{"records": []any{map[string]any{"Subject": "Billing"}}},
+	}); err == nil {
+		t.Error("Redact() expected an error for subquery records without attributes")
+	}
+}
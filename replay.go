@@ -0,0 +1,122 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Special replay ids understood by Salesforce event subscriptions
+const (
+	ReplayIdLatest   int64 = -1 // only receive new events
+	ReplayIdEarliest int64 = -2 // receive all events in the retention window
+)
+
+// ReplayStore persists the last processed replay id per channel so that subscribers can resume
+// after a restart. Implementations must be safe for concurrent use.
+type ReplayStore interface {
+	// GetReplayId returns the last stored replay id of the channel, and false if none is stored
+	GetReplayId(channel string) (int64, bool, error)
+	// SetReplayId stores the replay id of the most recently processed event of the channel
+	SetReplayId(channel string, replayId int64) error
+}
+
+// ResumeReplayId returns the replay id a subscriber should resume from, or fallback if the store
+// has no replay id for the channel
+func ResumeReplayId(store ReplayStore, channel string, fallback int64) (int64, error) {
+	if store == nil {
+		return fallback, errors.New("replay store cannot be nil")
+	}
+	replayId, found, err := store.GetReplayId(channel)
+	if err != nil {
+		return fallback, err
+	}
+	if !found {
+		return fallback, nil
+	}
+	return replayId, nil
+}
+
+// MemoryReplayStore keeps replay ids in memory, useful for tests and short lived subscribers
+type MemoryReplayStore struct {
+	mu        sync.RWMutex
+	replayIds map[string]int64
+}
+
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{replayIds: map[string]int64{}}
+}
+
+func (s *MemoryReplayStore) GetReplayId(channel string) (int64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	replayId, found := s.replayIds[channel]
+	return replayId, found, nil
+}
+
+func (s *MemoryReplayStore) SetReplayId(channel string, replayId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replayIds[channel] = replayId
+	return nil
+}
+
+// FileReplayStore persists replay ids of all channels to a single json file. Every update is written
+// to a temporary file which then replaces the original, so a crash never leaves a partial file.
+type FileReplayStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFileReplayStore(filePath string) *FileReplayStore {
+	return &FileReplayStore{filePath: filePath}
+}
+
+func (s *FileReplayStore) GetReplayId(channel string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replayIds, err := s.read()
+	if err != nil {
+		return 0, false, err
+	}
+	replayId, found := replayIds[channel]
+	return replayId, found, nil
+}
+
+func (s *FileReplayStore) SetReplayId(channel string, replayId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	replayIds, err := s.read()
+	if err != nil {
+		return err
+	}
+	replayIds[channel] = replayId
+
+	data, err := json.Marshal(replayIds)
+	if err != nil {
+		return err
+	}
+	tempPath := s.filePath + ".tmp"
+	if err := afero.WriteFile(appFs, tempPath, data, 0o644); err != nil {
+		return err
+	}
+	return appFs.Rename(tempPath, s.filePath)
+}
+
+func (s *FileReplayStore) read() (map[string]int64, error) {
+	replayIds := map[string]int64{}
+	data, err := afero.ReadFile(appFs, s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return replayIds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &replayIds); err != nil {
+		return nil, err
+	}
+	return replayIds, nil
+}
@@ -0,0 +1,112 @@
+package salesforce
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+type failingReplayStore struct{}
+
+func (failingReplayStore) GetReplayId(string) (int64, bool, error) {
+	return 0, false, errors.New("store unavailable")
+}
+
+func (failingReplayStore) SetReplayId(string, int64) error {
+	return errors.New("store unavailable")
+}
+
+func TestResumeReplayId(t *testing.T) {
+	memoryStore := NewMemoryReplayStore()
+	if err := memoryStore.SetReplayId("/data/AccountChangeEvent", 25); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		store   ReplayStore
+		channel string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "stored_replay_id",
+			store:   memoryStore,
+			channel: "/data/AccountChangeEvent",
+			want:    25,
+			wantErr: false,
+		},
+		{
+			name:    "fallback_when_missing",
+			store:   memoryStore,
+			channel: "/data/ContactChangeEvent",
+			want:    ReplayIdEarliest,
+			wantErr: false,
+		},
+		{
+			name:    "store_error",
+			store:   failingReplayStore{},
+			channel: "/data/AccountChangeEvent",
+			want:    ReplayIdEarliest,
+			wantErr: true,
+		},
+		{
+			name:    "nil_store",
+			store:   nil,
+			channel: "/data/AccountChangeEvent",
+			want:    ReplayIdEarliest,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResumeReplayId(tt.store, tt.channel, ReplayIdEarliest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResumeReplayId() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResumeReplayId() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileReplayStore(t *testing.T) {
+	appFs = afero.NewMemMapFs() // replace appFs with mocked file system
+	store := NewFileReplayStore("replay.json")
+
+	_, found, err := store.GetReplayId("/event/Order__e")
+	if err != nil || found {
+		t.Fatalf("FileReplayStore.GetReplayId() found = %v, err = %v, want not found", found, err)
+	}
+
+	if err := store.SetReplayId("/event/Order__e", 10); err != nil {
+		t.Fatalf("FileReplayStore.SetReplayId() error = %v", err)
+	}
+	if err := store.SetReplayId("/data/AccountChangeEvent", 20); err != nil {
+		t.Fatalf("FileReplayStore.SetReplayId() error = %v", err)
+	}
+
+	// a new store reading the same file resumes from the persisted values
+	reopened := NewFileReplayStore("replay.json")
+	got, found, err := reopened.GetReplayId("/event/Order__e")
+	if err != nil || !found || got != 10 {
+		t.Errorf("FileReplayStore.GetReplayId() = %v, %v, %v, want 10, true, nil", got, found, err)
+	}
+	got, found, err = reopened.GetReplayId("/data/AccountChangeEvent")
+	if err != nil || !found || got != 20 {
+		t.Errorf("FileReplayStore.GetReplayId() = %v, %v, %v, want 20, true, nil", got, found, err)
+	}
+	if exists, _ := afero.Exists(appFs, "replay.json.tmp"); exists {
+		t.Errorf("FileReplayStore left a temporary file behind")
+	}
+
+	if err := afero.WriteFile(appFs, "corrupt.json", []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewFileReplayStore("corrupt.json").GetReplayId("/event/Order__e"); err == nil {
+		t.Errorf("FileReplayStore.GetReplayId() expected error for corrupt file")
+	}
+}
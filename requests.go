@@ -3,11 +3,13 @@ package salesforce
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"time"
 )
 
 // RequestOption represents a functional option for configuring HTTP requests
@@ -20,38 +22,129 @@ func WithHeader(key, value string) RequestOption {
 	}
 }
 
+// withHeaders sets every value of the given headers on the HTTP request
+func withHeaders(headers http.Header) RequestOption {
+	return func(req *http.Request) {
+		for key, values := range headers {
+			req.Header.Del(key)
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+}
+
 type requestPayload struct {
-	method   string
-	uri      string
-	content  string
-	body     string
-	retry    bool
-	compress bool
-	options  []RequestOption
+	ctx         context.Context // defaults to context.Background() when nil
+	method      string
+	uri         string
+	content     string
+	body        io.Reader // nil if the request has no body; resent after a session refresh only if it is an io.Seeker
+	retry       bool
+	compress    bool
+	instanceUri bool // uri is relative to the instance url instead of the versioned data api
+	soap        bool // body is a SOAP envelope, whose faults are left to the caller to decode
+	options     []RequestOption
+}
+
+// RequestTooLargeError is returned when a request body is larger than the size set with
+// WithMaxRequestSize, instead of sending a request that Salesforce would reject
+type RequestTooLargeError struct {
+	Method string
+	Uri    string
+	Size   int // size of the body in bytes
+	Limit  int
+}
+
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"%s %s: request body of %d bytes is larger than the max request size of %d bytes",
+		e.Method,
+		e.Uri,
+		e.Size,
+		e.Limit,
+	)
+}
+
+func buildEndpoint(auth *authentication, config *configuration, payload requestPayload) string {
+	if payload.instanceUri {
+		return auth.InstanceUrl + payload.uri
+	}
+	return auth.InstanceUrl + "/services/data/" + config.apiVersion + payload.uri
 }
 
 func doRequest(
 	auth *authentication,
 	config *configuration,
 	payload requestPayload,
+) (*http.Response, error) {
+	if size, ok := bodySize(payload.body); ok && config.maxRequestSize > 0 &&
+		size > config.maxRequestSize {
+		return nil, &RequestTooLargeError{
+			Method: payload.method,
+			Uri:    payload.uri,
+			Size:   size,
+			Limit:  config.maxRequestSize,
+		}
+	}
+	if err := checkPreflightPermissions(auth, config, payload); err != nil {
+		return nil, err
+	}
+	if isCacheableRequest(config, payload) {
+		return doCachedRequest(auth, config, payload)
+	}
+	return sendRequest(auth, config, payload)
+}
+
+func sendRequest(
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
+) (*http.Response, error) {
+	return sendRequestWithMaintenanceBackoff(auth, config, payload)
+}
+
+func sendRequestOnce(
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
 ) (*http.Response, error) {
 	var reader io.Reader
 	var req *http.Request
 	var err error
-	endpoint := auth.InstanceUrl + "/services/data/" + config.apiVersion + payload.uri
+	ctx := payload.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := priorityFromContext(ctx); !ok && config.priorityQueue != nil {
+		ctx = ContextWithPriority(ctx, requestPriority(payload))
+	}
+	if err = waitForRateLimit(ctx, config, payload); err != nil {
+		return nil, err
+	}
+	endpoint := buildEndpoint(auth, config, payload)
 
-	if payload.body != "" {
-		if payload.compress {
-			reader, err = compress(payload.body)
+	compressBody := shouldCompressBody(config, payload)
+	var auditBody []byte
+	if payload.body != nil {
+		reader = payload.body
+		if config.auditLog != nil && payload.content == jsonType {
+			// json bodies are built in memory, keep a copy for the record ids of the audit entry
+			auditBody, err = io.ReadAll(reader)
 			if err != nil {
 				return nil, err
 			}
-		} else {
-			reader = strings.NewReader(payload.body)
+			reader = bytes.NewReader(auditBody)
 		}
-		req, err = http.NewRequest(payload.method, endpoint, reader)
+		if compressBody {
+			reader, err = compress(reader)
+			if err != nil {
+				return nil, err
+			}
+		}
+		req, err = http.NewRequestWithContext(ctx, payload.method, endpoint, reader)
 	} else {
-		req, err = http.NewRequest(payload.method, endpoint, nil)
+		req, err = http.NewRequestWithContext(ctx, payload.method, endpoint, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -61,9 +154,11 @@ func doRequest(
 	req.Header.Set("Content-Type", payload.content)
 	req.Header.Set("Accept", payload.content)
 	req.Header.Set("Authorization", "Bearer "+auth.AccessToken)
-	if payload.compress {
+	if compressBody {
 		req.Header.Set("Content-Encoding", "gzip") // compress request
-		req.Header.Set("Accept-Encoding", "gzip")  // compress response
+	}
+	if payload.compress || config.responseCompression {
+		req.Header.Set("Accept-Encoding", "gzip") // compress response
 	}
 
 	// Apply custom request options
@@ -71,29 +166,105 @@ func doRequest(
 		option(req)
 	}
 
+	// a half-open trial is only taken for a request that is sent, so it is always recorded
+	if err = config.circuitBreaker.allow(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
 	resp, err := config.httpClient.Do(req)
+	config.circuitBreaker.record(resp, err)
+	config.metrics.observeRequest(classifyEndpoint(payload), req.Method, resp, time.Since(start))
 	if err != nil {
+		auditRequest(auth, config, req, auditBody, start, nil, err)
 		return resp, err
 	}
-	if resp.StatusCode < 200 || resp.StatusCode > 300 {
+	config.apiUsage.update(resp.Header.Get(limitInfoHeader))
+	config.metrics.observeLimitInfo(resp.Header.Get(limitInfoHeader))
+
+	// salesforce does not guarantee that the response will be compressed
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		resp.Body, err = decompress(resp.Body)
+		if err != nil {
+			return resp, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.ContentLength = -1
+	}
+	auditRequest(auth, config, req, auditBody, start, resp, nil)
+
+	// a conditional request, such as one with If-Modified-Since, is not modified rather than failed
+	if (resp.StatusCode < 200 || resp.StatusCode > 300) &&
+		resp.StatusCode != http.StatusNotModified {
 		resp, err = processSalesforceError(*resp, auth, config, payload)
 		if err != nil {
 			return resp, err
 		}
 	}
 
-	// salesforce does not guarantee that the response will be compressed
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		resp.Body, err = decompress(resp.Body)
+	return resp, nil
+}
+
+func shouldCompressBody(config *configuration, payload requestPayload) bool {
+	if payload.compress {
+		return true
 	}
+	if config.requestCompressionThreshold <= 0 || payload.body == nil {
+		return false
+	}
+	// bodies of unknown size are streamed, such as files, and are assumed to be large
+	size, ok := bodySize(payload.body)
+	return !ok || size >= config.requestCompressionThreshold
+}
+
+// requestBody returns a body for the bytes of a request, or nil if there are none
+func requestBody(body []byte) io.Reader {
+	if len(body) == 0 {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
 
-	return resp, err
+// bodySize returns the number of unread bytes of a body, and false if its size is unknown
+func bodySize(body io.Reader) (int, bool) {
+	if sized, ok := body.(interface{ Len() int }); ok {
+		return sized.Len(), true
+	}
+	return 0, false
 }
 
-func compress(body string) (io.Reader, error) {
+// rewindBody seeks a body back to its start so it can be sent again, and returns false if it cannot
+func rewindBody(body io.Reader) bool {
+	if body == nil {
+		return true
+	}
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err == nil
+}
+
+// decodeJSONResponse reads the response body into v and closes it
+func decodeJSONResponse(resp *http.Response, v any) (err error) {
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(respBody, v)
+}
+
+// compress gzips a body into memory, which is a fraction of the size of the body for the text formats
+// Salesforce accepts
+func compress(body io.Reader) (io.Reader, error) {
 	var buf bytes.Buffer
 	gz := gzip.NewWriter(&buf)
-	if _, err := gz.Write([]byte(body)); err != nil {
+	if _, err := io.Copy(gz, body); err != nil {
 		return nil, err
 	}
 	if err := gz.Close(); err != nil {
@@ -102,21 +273,28 @@ func compress(body string) (io.Reader, error) {
 	return &buf, nil
 }
 
+// gzipReadCloser streams a gzip body, closing the gzip reader and the body it reads from
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (r gzipReadCloser) Close() error {
+	return errors.Join(r.Reader.Close(), r.body.Close())
+}
+
+// decompress returns a reader that decompresses a gzip body as it is read, without buffering it, so
+// large responses such as bulk query results are streamed
 func decompress(body io.ReadCloser) (io.ReadCloser, error) {
 	gzReader, err := gzip.NewReader(body)
-	if err != nil {
-		return nil, err
+	if err == io.EOF {
+		return body, nil // empty body, nothing to decompress
 	}
-	defer func() {
-		_ = gzReader.Close() // Ignore error since we've read what we need
-	}()
-
-	decompressed, err := io.ReadAll(gzReader)
 	if err != nil {
+		_ = body.Close()
 		return nil, err
 	}
-
-	return io.NopCloser(bytes.NewReader(decompressed)), nil
+	return gzipReadCloser{Reader: gzReader, body: body}, nil
 }
 
 func processSalesforceError(
@@ -129,6 +307,15 @@ func processSalesforceError(
 	if err != nil {
 		return &resp, err
 	}
+	if err := maintenanceError(resp, responseData); err != nil {
+		return &resp, err
+	}
+	if payload.soap {
+		// the session of a SOAP call is in its envelope, which only the caller can build again
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(responseData))
+		return &resp, nil
+	}
 	var sfErrors []SalesforceErrorMessage
 	err = json.Unmarshal(responseData, &sfErrors)
 	if err != nil {
@@ -137,24 +324,20 @@ func processSalesforceError(
 	for _, sfError := range sfErrors {
 		if sfError.ErrorCode == invalidSessionIdError &&
 			!payload.retry { // only attempt to refresh the session once
-			err = refreshSession(auth)
+			err = refreshSession(auth, config)
 			if err != nil {
 				return &resp, err
 			}
+			if !rewindBody(payload.body) {
+				return &resp, errors.New(
+					"session refreshed, but the request body cannot be sent again: " +
+						string(responseData),
+				)
+			}
 
-			newResp, err := doRequest(
-				auth,
-				config,
-				requestPayload{
-					payload.method,
-					payload.uri,
-					payload.content,
-					payload.body,
-					true,
-					payload.compress,
-					payload.options,
-				},
-			)
+			retryPayload := payload
+			retryPayload.retry = true
+			newResp, err := doRequest(auth, config, retryPayload)
 			if err != nil {
 				return &resp, err
 			}
@@ -162,5 +345,8 @@ func processSalesforceError(
 		}
 	}
 
+	if err := highAssuranceError(sfErrors, string(responseData)); err != nil {
+		return &resp, err
+	}
 	return &resp, errors.New(string(responseData))
 }
@@ -1,7 +1,9 @@
 package salesforce
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -46,7 +48,6 @@ func Test_doRequest(t *testing.T) {
 					method:  http.MethodGet,
 					uri:     "",
 					content: jsonType,
-					body:    "",
 				},
 			},
 			want:    http.StatusOK,
@@ -60,7 +61,6 @@ func Test_doRequest(t *testing.T) {
 					method:  http.MethodGet,
 					uri:     "",
 					content: jsonType,
-					body:    "",
 				},
 			},
 			want:    http.StatusBadRequest,
@@ -74,7 +74,6 @@ func Test_doRequest(t *testing.T) {
 					method:  http.MethodGet,
 					uri:     "/sobjects/Contact/ContactExternalId__c/Avng1",
 					content: jsonType,
-					body:    "",
 				},
 			},
 			want:    http.StatusMultipleChoices,
@@ -88,7 +87,7 @@ func Test_doRequest(t *testing.T) {
 					method:   http.MethodGet,
 					uri:      "",
 					content:  jsonType,
-					body:     "test",
+					body:     strings.NewReader("test"),
 					compress: true,
 				},
 			},
@@ -111,8 +110,210 @@ func Test_doRequest(t *testing.T) {
 	}
 }
 
+func Test_doRequest_compressionThreshold(t *testing.T) {
+	tests := []struct {
+		name                string
+		threshold           int
+		responseCompression bool
+		body                string
+		wantContentEncoding string
+		wantAcceptEncoding  string
+	}{
+		{
+			name:                "body_above_threshold",
+			threshold:           5,
+			responseCompression: true,
+			body:                "large body",
+			wantContentEncoding: "gzip",
+			wantAcceptEncoding:  "gzip",
+		},
+		{
+			name:                "body_below_threshold",
+			threshold:           100,
+			responseCompression: true,
+			body:                "small body",
+			wantContentEncoding: "",
+			wantAcceptEncoding:  "gzip",
+		},
+		{
+			name:                "threshold_disabled",
+			threshold:           0,
+			responseCompression: false,
+			body:                "large body",
+			wantContentEncoding: "",
+			wantAcceptEncoding:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sfAuth, capturedRequest := setupTestServerWithCapture(
+				"compressed",
+				http.StatusOK,
+			)
+			defer server.Close()
+
+			config := getDefaultConfig(t)
+			config.requestCompressionThreshold = tt.threshold
+			config.responseCompression = tt.responseCompression
+			config.httpClient.Transport = &http.Transport{DisableCompression: true}
+			resp, err := doRequest(&sfAuth, config, requestPayload{
+				method:  http.MethodPost,
+				uri:     "",
+				content: jsonType,
+				body:    strings.NewReader(tt.body),
+			})
+			if err != nil {
+				t.Errorf("doRequest() error = %v", err)
+				return
+			}
+			got := (*capturedRequest).Header
+			if got.Get("Content-Encoding") != tt.wantContentEncoding {
+				t.Errorf(
+					"doRequest() Content-Encoding = %v, want %v",
+					got.Get("Content-Encoding"),
+					tt.wantContentEncoding,
+				)
+			}
+			if got.Get("Accept-Encoding") != tt.wantAcceptEncoding {
+				t.Errorf(
+					"doRequest() Accept-Encoding = %v, want %v",
+					got.Get("Accept-Encoding"),
+					tt.wantAcceptEncoding,
+				)
+			}
+			respBody, _ := io.ReadAll(resp.Body)
+			if string(respBody) != "\"compressed\"" {
+				t.Errorf("doRequest() body = %v, want %v", string(respBody), "\"compressed\"")
+			}
+		})
+	}
+}
+
+func Test_doRequest_maxRequestSize(t *testing.T) {
+	server, sfAuth := setupTestServer("", http.StatusOK)
+	defer server.Close()
+
+	config := getDefaultConfig(t)
+	config.maxRequestSize = 5
+	_, err := doRequest(&sfAuth, config, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/sobjects/Account",
+		content: jsonType,
+		body:    strings.NewReader("large body"),
+	})
+	tooLargeErr := &RequestTooLargeError{}
+	if !errors.As(err, &tooLargeErr) {
+		t.Errorf("doRequest() error = %v, want RequestTooLargeError", err)
+		return
+	}
+	want := &RequestTooLargeError{
+		Method: http.MethodPost,
+		Uri:    "/sobjects/Account",
+		Size:   10,
+		Limit:  5,
+	}
+	if !reflect.DeepEqual(tooLargeErr, want) {
+		t.Errorf("doRequest() error = %v, want %v", tooLargeErr, want)
+	}
+
+	config.maxRequestSize = 100
+	resp, err := doRequest(&sfAuth, config, requestPayload{
+		method:  http.MethodPost,
+		uri:     "/sobjects/Account",
+		content: jsonType,
+		body:    strings.NewReader("small body"),
+	})
+	if err != nil {
+		t.Errorf("doRequest() error = %v", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+func Test_doRequest_readerBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func() io.Reader
+		wantErr bool
+	}{
+		{
+			name:    "seekable_body_resent",
+			body:    func() io.Reader { return strings.NewReader(`{"Name":"test"}`) },
+			wantErr: false,
+		},
+		{
+			name:    "stream_not_resent",
+			body:    func() io.Reader { return io.MultiReader(strings.NewReader(`{"Name":"test"}`)) },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/services/oauth2/token" {
+						_, _ = w.Write([]byte(`{"access_token":"refreshed"}`))
+						return
+					}
+					data, _ := io.ReadAll(r.Body)
+					bodies = append(bodies, string(data))
+					if r.Header.Get("Authorization") != "Bearer refreshed" {
+						w.WriteHeader(http.StatusUnauthorized)
+						_, _ = w.Write([]byte(`[{"errorCode":"INVALID_SESSION_ID"}]`))
+						return
+					}
+					_, _ = w.Write([]byte(`{}`))
+				}),
+			)
+			defer server.Close()
+			sfAuth := authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "expired",
+				grantType:   grantTypeClientCredentials,
+			}
+
+			_, err := doRequest(&sfAuth, getDefaultConfig(t), requestPayload{
+				method:  http.MethodPost,
+				uri:     "/sobjects/Account",
+				content: jsonType,
+				body:    tt.body(),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(bodies) != 2 || bodies[1] != bodies[0]) {
+				t.Errorf("doRequest() sent bodies %q, want the body resent", bodies)
+			}
+		})
+	}
+}
+
+func Test_shouldCompressBody(t *testing.T) {
+	config := getDefaultConfig(t)
+	config.requestCompressionThreshold = 100
+	tests := []struct {
+		name string
+		body io.Reader
+		want bool
+	}{
+		{name: "no_body", body: nil, want: false},
+		{name: "small_body", body: strings.NewReader("small body"), want: false},
+		{name: "large_body", body: strings.NewReader(strings.Repeat("a", 100)), want: true},
+		{name: "unknown_size", body: io.MultiReader(strings.NewReader("small")), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldCompressBody(config, requestPayload{body: tt.body})
+			if got != tt.want {
+				t.Errorf("shouldCompressBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_compression(t *testing.T) {
-	compressedResp, _ := compress("testRecord1")
+	compressedResp, _ := compress(strings.NewReader("testRecord1"))
 
 	type args struct {
 		body io.ReadCloser
@@ -131,6 +332,14 @@ func Test_compression(t *testing.T) {
 			want:    []byte("testRecord1"),
 			wantErr: false,
 		},
+		{
+			name: "decompress_empty",
+			args: args{
+				body: io.NopCloser(strings.NewReader("")),
+			},
+			want:    []byte{},
+			wantErr: false,
+		},
 		{
 			name: "decompress_invalid",
 			args: args{
@@ -164,6 +373,49 @@ func Test_compression(t *testing.T) {
 	}
 }
 
+// closeTrackingBody records whether it was closed and how many bytes were read from it
+type closeTrackingBody struct {
+	io.Reader
+	read   int
+	closed bool
+}
+
+func (b *closeTrackingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func Test_decompress_streams(t *testing.T) {
+	data := bytes.Repeat([]byte("Id,Name\n001000000000001AAA,Acme\n"), 100000)
+	compressed, err := compress(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("compress() error = %v", err)
+	}
+	compressedData, _ := io.ReadAll(compressed)
+	body := &closeTrackingBody{Reader: bytes.NewReader(compressedData)}
+
+	got, err := decompress(body)
+	if err != nil {
+		t.Fatalf("decompress() error = %v", err)
+	}
+	if body.read == len(compressedData) {
+		t.Errorf("decompress() read the whole body before it was read")
+	}
+	decompressed, err := io.ReadAll(got)
+	if err != nil || !bytes.Equal(decompressed, data) {
+		t.Fatalf("decompress() = %d bytes, %v, want %d bytes", len(decompressed), err, len(data))
+	}
+	if err := got.Close(); err != nil || !body.closed {
+		t.Errorf("Close() = %v, closed body = %v, want the body closed", err, body.closed)
+	}
+}
+
 func Test_processSalesforceError(t *testing.T) {
 	body, _ := json.Marshal([]SalesforceErrorMessage{{
 		Message:    "error message",
@@ -190,7 +442,6 @@ func Test_processSalesforceError(t *testing.T) {
 		method:  http.MethodGet,
 		uri:     "",
 		content: jsonType,
-		body:    "",
 	}
 
 	serverRefreshed, sfAuthRefreshed := setupTestServer("", http.StatusOK)
@@ -0,0 +1,137 @@
+package salesforce
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache stores the bodies of GET responses, see WithResponseCache. Implement it to share
+// cached responses between processes, such as with Redis. Keys are full request URLs, which include
+// the instance URL and API version.
+type ResponseCache interface {
+	// Get returns the cached body of a key, and false if the key is not cached or has expired
+	Get(key string) ([]byte, bool, error)
+	// Set caches the body of a key for the given duration
+	Set(key string, body []byte, ttl time.Duration) error
+	// Delete removes every key starting with prefix, or every key if prefix is empty
+	Delete(prefix string) error
+}
+
+type memoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// NewMemoryResponseCache returns an in-memory ResponseCache
+func NewMemoryResponseCache() ResponseCache {
+	return &memoryResponseCache{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+func (c *memoryResponseCache) Get(key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	return entry.value.([]byte), true, nil
+}
+
+func (c *memoryResponseCache) Set(key string, body []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: body, expires: c.now().Add(ttl)}
+	return nil
+}
+
+func (c *memoryResponseCache) Delete(prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// isCacheableRequest returns whether the response of a request may be cached: GET requests for
+// sObject describes, UI API object info such as picklist values and record types, and the endpoints
+// added with WithCachedEndpoints
+func isCacheableRequest(config *configuration, payload requestPayload) bool {
+	if config.responseCache == nil || payload.method != http.MethodGet || payload.instanceUri {
+		return false
+	}
+	path, _, _ := strings.Cut(payload.uri, "?")
+	if path == "/sobjects" || path == "/sobjects/" ||
+		(strings.HasPrefix(path, "/sobjects/") && strings.HasSuffix(path, "/describe")) ||
+		strings.HasPrefix(path, "/ui-api/object-info/") {
+		return true
+	}
+	for _, prefix := range config.cachedEndpoints {
+		if strings.HasPrefix(payload.uri, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// doCachedRequest returns the cached response of a request, or sends it and caches a successful
+// response. Errors of the cache are ignored so that an unavailable cache does not fail requests.
+func doCachedRequest(
+	auth *authentication,
+	config *configuration,
+	payload requestPayload,
+) (*http.Response, error) {
+	key := buildEndpoint(auth, config, payload)
+	if body, ok, err := config.responseCache.Get(key); err == nil && ok {
+		return cachedResponse(body), nil
+	}
+
+	resp, err := sendRequest(auth, config, payload)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	_ = config.responseCache.Set(key, body, config.responseCacheTTL)
+	return resp, nil
+}
+
+func cachedResponse(body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {jsonType}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+// InvalidateResponseCache removes cached responses of requests whose uri starts with uriPrefix, such
+// as "/sobjects/Account/describe" or "/ui-api/object-info/Account", relative to the versioned data
+// API. An empty prefix removes every cached response of the org.
+func (sf *Salesforce) InvalidateResponseCache(uriPrefix string) error {
+	if sf.config.responseCache == nil {
+		return nil
+	}
+	return sf.config.responseCache.Delete(
+		buildEndpoint(sf.auth, sf.config, requestPayload{uri: uriPrefix}),
+	)
+}
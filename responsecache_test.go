@@ -0,0 +1,166 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_isCacheableRequest(t *testing.T) {
+	config := &configuration{
+		responseCache:   NewMemoryResponseCache(),
+		cachedEndpoints: []string{"/tooling/sobjects/"},
+	}
+	tests := []struct {
+		name    string
+		payload requestPayload
+		want    bool
+	}{
+		{
+			name:    "describe",
+			payload: requestPayload{method: http.MethodGet, uri: "/sobjects/Account/describe"},
+			want:    true,
+		},
+		{
+			name:    "global_describe",
+			payload: requestPayload{method: http.MethodGet, uri: "/sobjects/"},
+			want:    true,
+		},
+		{
+			name: "picklist_values",
+			payload: requestPayload{
+				method: http.MethodGet,
+				uri:    "/ui-api/object-info/Account/picklist-values/012000000000000AAA",
+			},
+			want: true,
+		},
+		{
+			name:    "configured_endpoint",
+			payload: requestPayload{method: http.MethodGet, uri: "/tooling/sobjects/ApexClass"},
+			want:    true,
+		},
+		{
+			name:    "record",
+			payload: requestPayload{method: http.MethodGet, uri: "/sobjects/Account/001"},
+			want:    false,
+		},
+		{
+			name:    "not_get",
+			payload: requestPayload{method: http.MethodPost, uri: "/sobjects/Account/describe"},
+			want:    false,
+		},
+		{
+			name: "query",
+			payload: requestPayload{
+				method: http.MethodGet,
+				uri:    "/query/?q=SELECT+Id+FROM+Account",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCacheableRequest(config, tt.payload); got != tt.want {
+				t.Errorf("isCacheableRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if isCacheableRequest(&configuration{}, tests[0].payload) {
+		t.Error("isCacheableRequest() = true without a response cache")
+	}
+}
+
+func TestMemoryResponseCache(t *testing.T) {
+	cache := NewMemoryResponseCache().(*memoryResponseCache)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_ = cache.Set("https://a/describe/Account", []byte("account"), time.Minute)
+	_ = cache.Set("https://a/describe/Contact", []byte("contact"), time.Hour)
+	_ = cache.Set("https://b/describe/Account", []byte("other org"), time.Hour)
+	if body, ok, _ := cache.Get("https://a/describe/Account"); !ok || string(body) != "account" {
+		t.Errorf("Get() = %s, %v, want account, true", body, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := cache.Get("https://a/describe/Account"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	_ = cache.Delete("https://a/")
+	if _, ok, _ := cache.Get("https://a/describe/Contact"); ok {
+		t.Error("Get() returned a deleted entry")
+	}
+	if _, ok, _ := cache.Get("https://b/describe/Account"); !ok {
+		t.Error("Delete() removed an entry without the prefix")
+	}
+}
+
+func TestWithResponseCache_requests(t *testing.T) {
+	hits := map[string]int{}
+	picklists := picklistValuesResponse{
+		PicklistFieldValues: map[string]PicklistValues{"SubIndustry__c": testPicklistValues},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/describe"):
+			body, _ := json.Marshal(SObjectDescribe{Name: "Account"})
+			_, _ = w.Write(body)
+		case strings.Contains(r.URL.Path, "/picklist-values/"):
+			body, _ := json.Marshal(picklists)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`[{"errorCode":"NOT_FOUND","message":"not found"}]`))
+		}
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	for _, option := range []Option{
+		WithDescribeCacheTTL(0),
+		WithResponseCache(NewMemoryResponseCache(), time.Minute),
+		WithCachedEndpoints("/tooling/"),
+	} {
+		if err := option(sf.config); err != nil {
+			t.Fatalf("option error = %v", err)
+		}
+	}
+
+	for range 2 {
+		describe, err := sf.DescribeSObject("Account")
+		if err != nil || describe.Name != "Account" {
+			t.Fatalf("DescribeSObject() = %v, %v", describe, err)
+		}
+		got, err := sf.GetPicklistValues("Account", MasterRecordTypeId)
+		if err != nil || !reflect.DeepEqual(got, picklists.PicklistFieldValues) {
+			t.Fatalf("GetPicklistValues() = %v, %v", got, err)
+		}
+		if _, err := sf.DoRequest(http.MethodGet, "/tooling/sobjects/Missing", nil); err == nil {
+			t.Fatal("DoRequest() expected an error")
+		}
+	}
+	if err := sf.InvalidateResponseCache("/ui-api/"); err != nil {
+		t.Fatalf("InvalidateResponseCache() error = %v", err)
+	}
+	if _, err := sf.GetPicklistValues("Account", MasterRecordTypeId); err != nil {
+		t.Fatalf("GetPicklistValues() error = %v", err)
+	}
+	sf.ClearDescribeCache()
+	if _, err := sf.DescribeSObject("Account"); err != nil {
+		t.Fatalf("DescribeSObject() error = %v", err)
+	}
+
+	prefix := "/services/data/" + apiVersion
+	want := map[string]int{
+		prefix + "/sobjects/Account/describe":                                        2,
+		prefix + "/ui-api/object-info/Account/picklist-values/" + MasterRecordTypeId: 2,
+		prefix + "/tooling/sobjects/Missing":                                         2, // errors are not cached
+	}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("server hits = %v, want %v", hits, want)
+	}
+}
@@ -0,0 +1,130 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error codes Salesforce returns for records that did not fail themselves but were rolled back
+// because another record of an allOrNone request failed
+const (
+	ErrorCodeAllOrNoneRolledBack = "ALL_OR_NONE_OPERATION_ROLLED_BACK"
+	ErrorCodeProcessingHalted    = "PROCESSING_HALTED"
+)
+
+// Code returns the error code of the message, collection results report it as the status code
+func (e SalesforceErrorMessage) Code() string {
+	if e.ErrorCode != "" {
+		return e.ErrorCode
+	}
+	return e.StatusCode
+}
+
+// IsRollback reports whether the error only signals that the record was rolled back because of a
+// failure elsewhere in an allOrNone request
+func (e SalesforceErrorMessage) IsRollback() bool {
+	code := e.Code()
+	return code == ErrorCodeAllOrNoneRolledBack || code == ErrorCodeProcessingHalted
+}
+
+// RecordError is a failed record of a collection or composite request
+type RecordError struct {
+	Index  int // position of the record in the request
+	Id     string
+	Errors []SalesforceErrorMessage
+}
+
+func (e RecordError) Error() string {
+	var messages []string
+	for _, sfErr := range e.Errors {
+		message := sfErr.Code() + ": " + sfErr.Message
+		if len(sfErr.Fields) > 0 {
+			message += " [" + strings.Join(sfErr.Fields, ", ") + "]"
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		messages = append(messages, "unknown error")
+	}
+	record := fmt.Sprintf("record %d", e.Index)
+	if e.Id != "" {
+		record += " (" + e.Id + ")"
+	}
+	return record + ": " + strings.Join(messages, "; ")
+}
+
+// IsRollback reports whether the record only failed because it was rolled back
+func (e RecordError) IsRollback() bool {
+	if len(e.Errors) == 0 {
+		return false
+	}
+	for _, sfErr := range e.Errors {
+		if !sfErr.IsRollback() {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns every failed record, including records that were only rolled back
+func (r SalesforceResults) Failures() []RecordError {
+	var failures []RecordError
+	for _, result := range r.Results {
+		if !result.Success {
+			failures = append(
+				failures,
+				RecordError{Index: result.Index, Id: result.Id, Errors: result.Errors},
+			)
+		}
+	}
+	return failures
+}
+
+// RootCauses returns the failed records that caused an allOrNone request to be rolled back,
+// leaving out records that only report ALL_OR_NONE_OPERATION_ROLLED_BACK or PROCESSING_HALTED.
+// If every failure is a rollback, all failures are returned.
+func (r SalesforceResults) RootCauses() []RecordError {
+	failures := r.Failures()
+	var rootCauses []RecordError
+	for _, failure := range failures {
+		if !failure.IsRollback() {
+			rootCauses = append(rootCauses, failure)
+		}
+	}
+	if len(rootCauses) == 0 {
+		return failures
+	}
+	return rootCauses
+}
+
+// Err returns the root cause failures joined into one error, or nil if every record succeeded
+func (r SalesforceResults) Err() error {
+	var errs []error
+	for _, rootCause := range r.RootCauses() {
+		errs = append(errs, rootCause)
+	}
+	return errors.Join(errs...)
+}
+
+// indexResults sets the request index of the results of a batch that starts at offset. Salesforce
+// returns results in the order of the batch's records, so failed results without an Id are given the
+// Id of their input record.
+func indexResults(results []SalesforceResult, ids []string, offset int) []SalesforceResult {
+	for i := range results {
+		results[i].Index = offset + i
+		if !results[i].Success && results[i].Id == "" && i < len(ids) {
+			results[i].Id = ids[i]
+		}
+	}
+	return results
+}
+
+// recordIds returns the Id of each record, or an empty string for records without one
+func recordIds(records []map[string]any) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i], _ = record["Id"].(string)
+	}
+	return ids
+}
@@ -0,0 +1,106 @@
+package salesforce
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforceResults_RootCauses(t *testing.T) {
+	rolledBack := []SalesforceErrorMessage{{
+		Message:    "Record rolled back because not all records were valid",
+		StatusCode: ErrorCodeAllOrNoneRolledBack,
+	}}
+	validation := []SalesforceErrorMessage{{
+		Message:    "Name is required",
+		StatusCode: "REQUIRED_FIELD_MISSING",
+		Fields:     []string{"Name"},
+	}}
+	halted := []SalesforceErrorMessage{{
+		Message:   "The transaction was rolled back",
+		ErrorCode: ErrorCodeProcessingHalted,
+	}}
+
+	tests := []struct {
+		name           string
+		results        SalesforceResults
+		wantFailures   int
+		wantRootCauses []RecordError
+		wantErr        string
+	}{
+		{
+			name: "root_cause_among_rollbacks",
+			results: SalesforceResults{
+				Results: []SalesforceResult{
+					{Errors: rolledBack, Index: 0},
+					{Errors: validation, Index: 1},
+					{Errors: rolledBack, Index: 2},
+					{Errors: halted, Index: 3},
+				},
+				HasSalesforceErrors: true,
+			},
+			wantFailures:   4,
+			wantRootCauses: []RecordError{{Index: 1, Errors: validation}},
+			wantErr:        "record 1: REQUIRED_FIELD_MISSING: Name is required [Name]",
+		},
+		{
+			name: "only_rollbacks",
+			results: SalesforceResults{
+				Results: []SalesforceResult{
+					{Id: "001", Success: true, Index: 0},
+					{Id: "002", Errors: rolledBack, Index: 1},
+				},
+				HasSalesforceErrors: true,
+			},
+			wantFailures:   1,
+			wantRootCauses: []RecordError{{Index: 1, Id: "002", Errors: rolledBack}},
+			wantErr:        "record 1 (002): " + ErrorCodeAllOrNoneRolledBack,
+		},
+		{
+			name: "success",
+			results: SalesforceResults{
+				Results: []SalesforceResult{{Id: "001", Success: true}},
+			},
+			wantFailures:   0,
+			wantRootCauses: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(tt.results.Failures()); got != tt.wantFailures {
+				t.Errorf("Failures() = %v, want %v", got, tt.wantFailures)
+			}
+			if got := tt.results.RootCauses(); !reflect.DeepEqual(got, tt.wantRootCauses) {
+				t.Errorf("RootCauses() = %v, want %v", got, tt.wantRootCauses)
+			}
+			err := tt.results.Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_indexResults(t *testing.T) {
+	results := []SalesforceResult{
+		{Id: "003", Success: true},
+		{Errors: []SalesforceErrorMessage{{StatusCode: "ENTITY_IS_DELETED"}}},
+	}
+	want := []SalesforceResult{
+		{Id: "003", Success: true, Index: 200},
+		{
+			Id:     "004",
+			Errors: []SalesforceErrorMessage{{StatusCode: "ENTITY_IS_DELETED"}},
+			Index:  201,
+		},
+	}
+	if got := indexResults(results, []string{"", "004"}, 200); !reflect.DeepEqual(got, want) {
+		t.Errorf("indexResults() = %v, want %v", got, want)
+	}
+}
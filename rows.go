@@ -0,0 +1,380 @@
+package salesforce
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rows is the result of a query read one record at a time, with the same Columns, Next, Scan, Err,
+// and Close methods as sql.Rows, so code written for database/sql can read Salesforce records.
+// Records are read one page at a time as Next advances.
+type Rows struct {
+	sf             *Salesforce
+	ctx            context.Context
+	columns        []string
+	records        []map[string]any
+	index          int
+	nextRecordsUrl string
+	current        map[string]any
+	err            error
+	closed         bool
+}
+
+// QueryRows runs a query and returns its results as Rows. Columns are the fields of the SELECT clause,
+// such as Id and Account.Name, the aliases of aggregate expressions, or expr0, expr1, and so on for
+// aggregate expressions without one. Subqueries are columns named by their relationship, holding the
+// related records.
+func (sf *Salesforce) QueryRows(ctx context.Context, query string) (*Rows, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	columns, err := soqlSelectColumns(query)
+	if err != nil {
+		return nil, err
+	}
+	rows := &Rows{
+		sf:             sf,
+		ctx:            ctx,
+		columns:        columns,
+		nextRecordsUrl: "/query/?q=" + url.QueryEscape(query),
+	}
+	if err := rows.fetch(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Columns returns the column names
+func (r *Rows) Columns() ([]string, error) {
+	if r.closed {
+		return nil, errors.New("rows are closed")
+	}
+	return r.columns, nil
+}
+
+// Next advances to the next record, reading the next page of results when needed. It returns false
+// once there are no more records or an error occurred, see Err.
+func (r *Rows) Next() bool {
+	if r.closed {
+		return false
+	}
+	for r.index >= len(r.records) {
+		if r.nextRecordsUrl == "" {
+			_ = r.Close()
+			return false
+		}
+		if err := r.fetch(); err != nil {
+			r.err = err
+			_ = r.Close()
+			return false
+		}
+	}
+	r.current = r.records[r.index]
+	r.index++
+	return true
+}
+
+func (r *Rows) fetch() error {
+	queryResp, err := getQueryPage(r.ctx, r.sf, r.nextRecordsUrl)
+	if err != nil {
+		return err
+	}
+	r.records = queryResp.Records
+	r.index = 0
+	r.nextRecordsUrl = ""
+	if !queryResp.Done {
+		r.nextRecordsUrl = queryResp.NextRecordsUrl
+	}
+	return nil
+}
+
+// Scan copies the columns of the current record into dest, which must have one value per column.
+// Values can be scanned into pointers to strings, booleans, numbers, time.Time, any, types
+// implementing sql.Scanner such as sql.NullString, and pointers to these for null values. Related
+// records and subqueries are scanned into any as maps, or into strings and byte slices as JSON.
+func (r *Rows) Scan(dest ...any) error {
+	if r.closed {
+		return errors.New("rows are closed")
+	}
+	if r.current == nil {
+		return errors.New("scan called without calling Next")
+	}
+	if len(dest) != len(r.columns) {
+		return fmt.Errorf(
+			"expected %d destination arguments in Scan, not %d",
+			len(r.columns),
+			len(dest),
+		)
+	}
+	for i, column := range r.columns {
+		if err := scanValue(dest[i], recordPathValue(r.current, column)); err != nil {
+			return fmt.Errorf("converting column %d, name %q: %w", i, column, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error, if any, that ended the iteration
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close closes the rows, after which Next returns false. Rows are closed automatically once Next
+// returns false.
+func (r *Rows) Close() error {
+	r.closed = true
+	r.records = nil
+	r.current = nil
+	return nil
+}
+
+// scanValue stores a record value in dest
+func scanValue(dest any, src any) error {
+	if related, ok := src.(map[string]any); ok {
+		src = removeAttributes(related)
+	}
+	switch d := dest.(type) {
+	case *any:
+		*d = src
+		return nil
+	case *sql.NullTime:
+		if src == nil {
+			*d = sql.NullTime{}
+			return nil
+		}
+		t, err := parseSalesforceTime(src)
+		if err != nil {
+			return err
+		}
+		*d = sql.NullTime{Time: t, Valid: true}
+		return nil
+	case sql.Scanner:
+		if _, ok := src.(map[string]any); ok {
+			encoded, err := json.Marshal(src)
+			if err != nil {
+				return err
+			}
+			src = string(encoded)
+		}
+		return d.Scan(src)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return errors.New("destination not a pointer")
+	}
+	dv = dv.Elem()
+	if dv.Kind() == reflect.Pointer {
+		if src == nil {
+			dv.SetZero()
+			return nil
+		}
+		value := reflect.New(dv.Type().Elem())
+		if err := scanValue(value.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(value)
+		return nil
+	}
+	if src == nil {
+		return fmt.Errorf("converting NULL to %s is unsupported", dv.Type())
+	}
+
+	if dv.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := parseSalesforceTime(src)
+		if err != nil {
+			return err
+		}
+		dv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch dv.Kind() {
+	case reflect.String:
+		s, err := scanString(src)
+		if err != nil {
+			return err
+		}
+		dv.SetString(s)
+		return nil
+	case reflect.Slice:
+		if dv.Type().Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		s, err := scanString(src)
+		if err != nil {
+			return err
+		}
+		dv.SetBytes([]byte(s))
+		return nil
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			dv.SetBool(v)
+			return nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			dv.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, err := scanString(src)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, err := scanString(src)
+		if err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		s, err := scanString(src)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(s, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetFloat(f)
+		return nil
+	}
+	return fmt.Errorf("unsupported Scan, storing %T into type %T", src, dest)
+}
+
+// scanString formats a record value as a string, with related records as JSON
+func scanString(src any) (string, error) {
+	if number, ok := formatNumber(src); ok {
+		return number, nil
+	}
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	encoded, err := json.Marshal(src)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// parseSalesforceTime parses a datetime, date, or time value
+func parseSalesforceTime(src any) (time.Time, error) {
+	s, ok := src.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unsupported Scan, storing %T into type time.Time", src)
+	}
+	for _, layout := range []string{
+		salesforceDateTimeFormat,
+		time.RFC3339Nano,
+		time.DateOnly,
+		"15:04:05.000Z",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date or time", s)
+}
+
+// soqlSelectColumns returns the names that the fields of the SELECT clause of a query are returned by
+func soqlSelectColumns(query string) ([]string, error) {
+	query = strings.TrimSpace(query)
+	if len(query) < len("SELECT ") || !strings.EqualFold(query[:len("SELECT")], "SELECT") {
+		return nil, errors.New("query must start with SELECT")
+	}
+	from := soqlClauseIndex(query, "FROM")
+	if from < 0 {
+		return nil, errors.New("query must have a FROM clause")
+	}
+
+	var columns []string
+	expressions := 0
+	for _, item := range splitSoqlList(query[len("SELECT"):from]) {
+		words := strings.Fields(item)
+		switch {
+		case len(words) == 0:
+			return nil, errors.New("query has an empty SELECT field")
+		case strings.HasPrefix(item, "("):
+			inner := strings.TrimSuffix(strings.TrimPrefix(item, "("), ")")
+			innerFrom := soqlClauseIndex(inner, "FROM")
+			if innerFrom < 0 {
+				return nil, fmt.Errorf("invalid subquery: %s", item)
+			}
+			relationship := strings.Fields(inner[innerFrom+len("FROM"):])
+			if len(relationship) == 0 {
+				return nil, fmt.Errorf("invalid subquery: %s", item)
+			}
+			columns = append(columns, relationship[0])
+		case strings.EqualFold(words[0], "TYPEOF") && len(words) > 1:
+			columns = append(columns, words[1])
+		case strings.Contains(item, "("):
+			open := strings.Index(item, "(")
+			function := strings.ToLower(strings.TrimSpace(item[:open]))
+			closing := strings.LastIndex(item, ")")
+			if alias := strings.Fields(item[closing+1:]); len(alias) == 1 {
+				columns = append(columns, alias[0])
+				continue
+			}
+			if function == "tolabel" || function == "format" || function == "convertcurrency" {
+				columns = append(columns, strings.TrimSpace(item[open+1:closing]))
+				continue
+			}
+			columns = append(columns, "expr"+strconv.Itoa(expressions))
+			expressions++
+		default:
+			columns = append(columns, words[0])
+		}
+	}
+	return columns, nil
+}
+
+// splitSoqlList splits a comma separated list of a query, ignoring commas in parentheses and strings
+func splitSoqlList(list string) []string {
+	var items []string
+	depth := 0
+	inString := false
+	start := 0
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(list[start:]))
+}
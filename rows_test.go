@@ -0,0 +1,249 @@
+package salesforce
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_soqlSelectColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "fields",
+			query: "SELECT Id, Name, Account.Owner.Name FROM Contact WHERE Name = 'a, b'",
+			want:  []string{"Id", "Name", "Account.Owner.Name"},
+		},
+		{
+			name:  "aggregates",
+			query: "select LeadSource, COUNT(Id), SUM(Amount) total, MAX(CreatedDate) FROM Lead GROUP BY LeadSource",
+			want:  []string{"LeadSource", "expr0", "total", "expr1"},
+		},
+		{
+			name:  "field_functions",
+			query: "SELECT toLabel(Status), FORMAT(Amount) amt, convertCurrency(Amount) FROM Opportunity",
+			want:  []string{"Status", "amt", "Amount"},
+		},
+		{
+			name:  "subquery",
+			query: "SELECT Id, (SELECT Id, LastName FROM Contacts WHERE LastName != null) FROM Account",
+			want:  []string{"Id", "Contacts"},
+		},
+		{
+			name:  "typeof",
+			query: "SELECT TYPEOF What WHEN Account THEN Name ELSE Id END, Subject FROM Task",
+			want:  []string{"What", "Subject"},
+		},
+		{
+			name:    "not_select",
+			query:   "FIND {Acme}",
+			wantErr: true,
+		},
+		{
+			name:    "no_from",
+			query:   "SELECT Id",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := soqlSelectColumns(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("soqlSelectColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("soqlSelectColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scanValue(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	name := "Acme"
+	tests := []struct {
+		name    string
+		src     any
+		dest    any
+		want    any
+		wantErr bool
+	}{
+		{name: "string", src: "Acme", dest: new(string), want: "Acme"},
+		{name: "number_to_string", src: float64(12.5), dest: new(string), want: "12.5"},
+		{name: "number_to_int", src: float64(3), dest: new(int), want: 3},
+		{name: "fraction_to_int", src: float64(3.5), dest: new(int), wantErr: true},
+		{name: "number_to_float", src: float64(3.5), dest: new(float64), want: 3.5},
+		{name: "bool", src: true, dest: new(bool), want: true},
+		{
+			name: "datetime",
+			src:  "2024-01-02T03:04:05.000+0000",
+			dest: new(time.Time),
+			want: created,
+		},
+		{name: "null_to_string", src: nil, dest: new(string), wantErr: true},
+		{name: "null_to_pointer", src: nil, dest: new(*string), want: (*string)(nil)},
+		{name: "pointer", src: "Acme", dest: new(*string), want: &name},
+		{
+			name: "null_string",
+			src:  "Acme",
+			dest: new(sql.NullString),
+			want: sql.NullString{String: "Acme", Valid: true},
+		},
+		{name: "null_int", src: nil, dest: new(sql.NullInt64), want: sql.NullInt64{}},
+		{
+			name: "null_time",
+			src:  "2024-01-02T03:04:05.000Z",
+			dest: new(sql.NullTime),
+			want: sql.NullTime{Time: created, Valid: true},
+		},
+		{
+			name: "related_record_to_any",
+			src:  map[string]any{"attributes": map[string]any{"type": "Account"}, "Name": "Acme"},
+			dest: new(any),
+			want: map[string]any{"Name": "Acme"},
+		},
+		{
+			name: "related_record_to_string",
+			src:  map[string]any{"attributes": map[string]any{"type": "Account"}, "Name": "Acme"},
+			dest: new(string),
+			want: `{"Name":"Acme"}`,
+		},
+		{name: "unsupported", src: "a", dest: new(struct{}), wantErr: true},
+		{name: "not_pointer", src: "a", dest: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := scanValue(tt.dest, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scanValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := reflect.ValueOf(tt.dest).Elem().Interface()
+			if tm, ok := got.(time.Time); ok {
+				got = tm.UTC()
+			}
+			if nt, ok := got.(sql.NullTime); ok {
+				nt.Time = nt.Time.UTC()
+				got = nt
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_QueryRows(t *testing.T) {
+	pages := map[string]queryResponse{
+		"/services/data/" + apiVersion + "/query/": {
+			Done:           false,
+			NextRecordsUrl: "/services/data/" + apiVersion + "/query/01gD-2000",
+			Records: []map[string]any{
+				{
+					"Id":                "003A",
+					"NumberOfVisits__c": float64(2),
+					"Account":           map[string]any{"Name": "Acme"},
+				},
+			},
+		},
+		"/services/data/" + apiVersion + "/query/01gD-2000": {
+			Done:    true,
+			Records: []map[string]any{{"Id": "003B", "NumberOfVisits__c": nil, "Account": nil}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(page)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	rows, err := sf.QueryRows(
+		context.Background(),
+		"SELECT Id, NumberOfVisits__c, Account.Name FROM Contact",
+	)
+	if err != nil {
+		t.Fatalf("QueryRows() error = %v", err)
+	}
+	defer rows.Close()
+	columns, _ := rows.Columns()
+	wantColumns := []string{"Id", "NumberOfVisits__c", "Account.Name"}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("Columns() = %v, want %v", columns, wantColumns)
+	}
+
+	type row struct {
+		id      string
+		visits  sql.NullInt64
+		account sql.NullString
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.visits, &r.account); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		got = append(got, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	want := []row{
+		{
+			id:      "003A",
+			visits:  sql.NullInt64{Int64: 2, Valid: true},
+			account: sql.NullString{String: "Acme", Valid: true},
+		},
+		{id: "003B"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+	if err := rows.Scan(new(string), new(string), new(string)); err == nil {
+		t.Error("Scan() after the last row expected an error")
+	}
+}
+
+func TestSalesforce_QueryRows_pageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/data/"+apiVersion+"/query/" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := json.Marshal(queryResponse{
+			NextRecordsUrl: "/services/data/" + apiVersion + "/query/01gD-2000",
+			Records:        []map[string]any{{"Id": "003A"}},
+		})
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	rows, err := sf.QueryRows(context.Background(), "SELECT Id FROM Contact")
+	if err != nil {
+		t.Fatalf("QueryRows() error = %v", err)
+	}
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if count != 1 || rows.Err() == nil {
+		t.Errorf("read %d rows with error %v, want 1 row and an error", count, rows.Err())
+	}
+}
@@ -1,6 +1,7 @@
 package salesforce
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"reflect"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/forcedotcom/go-soql"
@@ -30,6 +32,7 @@ type SalesforceResult struct {
 	Id      string                   `json:"id"`
 	Errors  []SalesforceErrorMessage `json:"errors"`
 	Success bool                     `json:"success"`
+	Index   int                      `json:"-"` // position of the record in the request
 }
 
 type SalesforceResults struct {
@@ -38,17 +41,20 @@ type SalesforceResults struct {
 }
 
 const (
-	apiVersion                    = "v63.0"
-	jsonType                      = "application/json"
-	csvType                       = "text/csv"
-	batchSizeMax                  = 200
-	bulkBatchSizeMax              = 10000
-	bulkPollTimeout               = time.Duration(1 * time.Minute)
-	invalidSessionIdError         = "INVALID_SESSION_ID"
-	httpDefaultMaxIdleConnections = 10
-	httpDefaultIdleConnTimeout    = time.Duration(30 * time.Second)
-	httpDefaultTimeout            = time.Duration(120 * time.Second)
-	bulkQueryMaxRecords           = -1 // use server default
+	apiVersion                     = "v63.0"
+	jsonType                       = "application/json"
+	csvType                        = "text/csv"
+	batchSizeMax                   = 200
+	bulkBatchSizeMax               = 10000
+	bulkPollTimeout                = time.Duration(1 * time.Minute)
+	invalidSessionIdError          = "INVALID_SESSION_ID"
+	httpDefaultMaxIdleConnections  = 100
+	httpDefaultMaxIdleConnsPerHost = 100
+	httpDefaultIdleConnTimeout     = time.Duration(90 * time.Second)
+	httpDefaultTimeout             = time.Duration(120 * time.Second)
+	bulkQueryMaxRecords            = -1 // use server default
+	customMetadataCacheTTL         = time.Duration(5 * time.Minute)
+	describeCacheTTL               = time.Duration(30 * time.Minute)
 )
 
 func validateOfTypeSlice(data any) error {
@@ -167,10 +173,6 @@ func validateObjectWithAssignmentRuleId(sObjectName string) error {
 }
 
 func Init(creds Creds, options ...Option) (*Salesforce, error) {
-	var auth *authentication
-	var err error
-	var authFlow AuthFlowType
-
 	// Initialize configuration with defaults
 	config := &configuration{}
 	config.setDefaults()
@@ -182,61 +184,146 @@ func Init(creds Creds, options ...Option) (*Salesforce, error) {
 		}
 	}
 
+	if config.communityUrl != "" && config.instanceUrl != "" {
+		return nil, errors.New(
+			"configuration error: cannot pin both an instance url and a community url",
+		)
+	}
 	config.configureHttpClient()
 
-	if creds == (Creds{}) {
+	if config.credentialProvider != nil && creds != (Creds{}) {
+		return nil, errors.New(
+			"configuration error: cannot use both creds and a credential provider",
+		)
+	} else if config.credentialProvider == nil && creds == (Creds{}) {
 		return nil, errors.New("creds is empty")
 	}
 
-	// Determine authentication flow and authenticate
-	if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
-		creds.Username != "" && creds.Password != "" && creds.SecurityToken != "" {
+	var auth *authentication
+	var authFlow AuthFlowType
+	var err error
+	if config.sessionStore != nil {
+		auth, authFlow, err = authenticateShared(config, creds)
+	} else {
+		auth, authFlow, err = authenticateOwn(config, creds)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	tokenInstanceUrl := auth.InstanceUrl
+	switch {
+	case config.communityUrl != "":
+		auth.InstanceUrl = config.communityUrl
+		auth.pinnedInstanceUrl = true
+	case config.instanceUrl != "":
+		auth.InstanceUrl = config.instanceUrl
+		auth.pinnedInstanceUrl = true
+	case auth.CommunityUrl != "":
+		// community users call the API under the url of their site
+		auth.InstanceUrl = strings.TrimSuffix(auth.CommunityUrl, "/")
+		auth.pinnedInstanceUrl = true
+	}
+	if auth.pinnedInstanceUrl {
+		// a shared session keeps the instance url of the token response for processes that don't pin it
+		auth.tokenInstanceUrl = tokenInstanceUrl
+	}
+	if err := config.checkInstanceUrl(auth.InstanceUrl); err != nil {
+		return nil, err
+	}
+
+	return &Salesforce{
+		auth:     auth,
+		config:   config,
+		AuthFlow: authFlow,
+	}, nil
+}
+
+// authenticateOwn authenticates with the credentials of the provider if there is one, or else with
+// creds, without consulting a SessionStore
+func authenticateOwn(config *configuration, creds Creds) (*authentication, AuthFlowType, error) {
+	if config.credentialProvider != nil {
+		return authenticateWithProvider(config, config.credentialProvider)
+	}
+	return authenticate(config, creds)
+}
+
+// authenticate authenticates with the flow that the given credentials are for
+func authenticate(config *configuration, creds Creds) (*authentication, AuthFlowType, error) {
+	var auth *authentication
+	var err error
+
+	loginUrl := valueOrDefault(config.loginUrl, creds.Domain)
+	authFlow := detectAuthFlow(config, creds)
+	switch authFlow {
+	case AuthFlowUsernamePassword:
 		auth, err = usernamePasswordFlow(
-			creds.Domain,
+			config,
+			loginUrl,
 			creds.Username,
 			creds.Password,
 			creds.SecurityToken,
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
 		)
-		authFlow = AuthFlowUsernamePassword
-	} else if creds.Domain != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" {
+	case AuthFlowClientCredentials:
 		auth, err = clientCredentialsFlow(
-			creds.Domain,
+			config,
+			loginUrl,
 			creds.ConsumerKey,
 			creds.ConsumerSecret,
 		)
-		authFlow = AuthFlowClientCredentials
-	} else if creds.AccessToken != "" {
+	case AuthFlowAccessToken:
 		auth, err = config.getAccessTokenAuthentication(
-			creds.Domain,
+			valueOrDefault(config.communityUrl, valueOrDefault(config.instanceUrl, creds.Domain)),
 			creds.AccessToken,
 		)
-		authFlow = AuthFlowAccessToken
-	} else if creds.Domain != "" && creds.Username != "" &&
-		creds.ConsumerKey != "" && creds.ConsumerRSAPem != "" {
+	case AuthFlowJWT:
 		auth, err = jwtFlow(
-			creds.Domain,
+			config,
+			loginUrl,
 			creds.Username,
 			creds.ConsumerKey,
 			creds.ConsumerRSAPem,
 			JwtExpirationTime,
 		)
-		authFlow = AuthFlowJWT
+	case AuthFlowSoapLogin:
+		auth, err = soapLoginFlow(
+			config,
+			loginUrl,
+			creds.Username,
+			creds.Password,
+			creds.SecurityToken,
+		)
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, authFlow, err
 	} else if auth == nil || auth.AccessToken == "" {
-		return nil, errors.New("unknown authentication error")
+		return nil, authFlow, errors.New("unknown authentication error")
 	}
 	auth.creds = creds
+	return auth, authFlow, nil
+}
 
-	return &Salesforce{
-		auth:     auth,
-		config:   config,
-		AuthFlow: authFlow,
-	}, nil
+// detectAuthFlow returns the flow that the given credentials are for
+func detectAuthFlow(config *configuration, creds Creds) AuthFlowType {
+	loginUrl := valueOrDefault(config.loginUrl, creds.Domain)
+	switch {
+	case loginUrl != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "" &&
+		creds.Username != "" && creds.Password != "" && creds.SecurityToken != "":
+		return AuthFlowUsernamePassword
+	case loginUrl != "" && creds.ConsumerKey != "" && creds.ConsumerSecret != "":
+		return AuthFlowClientCredentials
+	case creds.AccessToken != "":
+		return AuthFlowAccessToken
+	case loginUrl != "" && creds.Username != "" &&
+		creds.ConsumerKey != "" && creds.ConsumerRSAPem != "":
+		return AuthFlowJWT
+	case config.soapLogin && loginUrl != "" && creds.Username != "" && creds.Password != "":
+		return AuthFlowSoapLogin
+	}
+	return AuthFlowUnknown
 }
 
 func (sf *Salesforce) DoRequest(
@@ -254,7 +341,7 @@ func (sf *Salesforce) DoRequest(
 		method:   method,
 		uri:      uri,
 		content:  jsonType,
-		body:     string(body),
+		body:     requestBody(body),
 		options:  opts,
 		compress: sf.config.compressionHeaders,
 	})
@@ -265,6 +352,35 @@ func (sf *Salesforce) DoRequest(
 	return resp, nil
 }
 
+// DoRaw makes a http call to any Salesforce endpoint, relative to the instance url.
+// Authentication, session refresh, compression, and api usage tracking are handled the same as DoRequest.
+func (sf *Salesforce) DoRaw(
+	method string,
+	relativeUri string,
+	headers http.Header,
+	body []byte,
+) (*http.Response, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:      method,
+		uri:         relativeUri,
+		content:     jsonType,
+		body:        requestBody(body),
+		options:     []RequestOption{withHeaders(headers)},
+		compress:    sf.config.compressionHeaders,
+		instanceUri: true,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
 func (sf *Salesforce) Query(query string, sObject any) error {
 	authErr := validateAuth(*sf)
 	if authErr != nil {
@@ -279,6 +395,33 @@ func (sf *Salesforce) Query(query string, sObject any) error {
 	return nil
 }
 
+// QueryChan performs a SOQL query and sends each record on the returned channel as pages of results
+// arrive. The record channel must be drained. Both channels are closed once the query completes,
+// and at most one error is sent.
+func (sf *Salesforce) QueryChan(query string) (<-chan map[string]any, <-chan error) {
+	return sf.QueryChanContext(context.Background(), query)
+}
+
+// QueryChanContext is QueryChan with a context, which stops the query when canceled
+func (sf *Salesforce) QueryChanContext(
+	ctx context.Context,
+	query string,
+) (<-chan map[string]any, <-chan error) {
+	records := make(chan map[string]any)
+	errs := make(chan error, 1)
+
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		close(records)
+		errs <- authErr
+		close(errs)
+		return records, errs
+	}
+
+	go streamQuery(ctx, sf, query, records, errs)
+	return records, errs
+}
+
 func (sf *Salesforce) QueryStruct(soqlStruct any, sObject any) error {
 	validationErr := validateGoSoql(*sf, soqlStruct)
 	if validationErr != nil {
@@ -302,6 +445,10 @@ func (sf *Salesforce) InsertOne(sObjectName string, record any) (SalesforceResul
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, record)
+	if nameErr != nil {
+		return SalesforceResult{}, nameErr
+	}
 
 	return doInsertOne(sf, sObjectName, record)
 }
@@ -311,6 +458,10 @@ func (sf *Salesforce) UpdateOne(sObjectName string, record any) error {
 	if validationErr != nil {
 		return validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, record)
+	if nameErr != nil {
+		return nameErr
+	}
 
 	return doUpdateOne(sf, sObjectName, record)
 }
@@ -324,6 +475,10 @@ func (sf *Salesforce) UpsertOne(
 	if validationErr != nil {
 		return SalesforceResult{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, record)
+	if nameErr != nil {
+		return SalesforceResult{}, nameErr
+	}
 
 	return doUpsertOne(sf, sObjectName, externalIdFieldName, record)
 }
@@ -333,6 +488,10 @@ func (sf *Salesforce) DeleteOne(sObjectName string, record any) error {
 	if validationErr != nil {
 		return validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, record)
+	if nameErr != nil {
+		return nameErr
+	}
 
 	return doDeleteOne(sf, sObjectName, record)
 }
@@ -342,10 +501,15 @@ func (sf *Salesforce) InsertCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doInsertCollection(sf, sObjectName, records, batchSize)
 }
@@ -355,10 +519,15 @@ func (sf *Salesforce) UpdateCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doUpdateCollection(sf, sObjectName, records, batchSize)
 }
@@ -369,10 +538,15 @@ func (sf *Salesforce) UpsertCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doUpsertCollection(sf, sObjectName, externalIdFieldName, records, batchSize)
 }
@@ -382,10 +556,15 @@ func (sf *Salesforce) DeleteCollection(
 	records any,
 	batchSize int,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doDeleteCollection(sf, sObjectName, records, batchSize)
 }
@@ -396,10 +575,15 @@ func (sf *Salesforce) InsertComposite(
 	batchSize int,
 	allOrNone bool,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doInsertComposite(sf, sObjectName, records, allOrNone, batchSize)
 }
@@ -410,10 +594,15 @@ func (sf *Salesforce) UpdateComposite(
 	batchSize int,
 	allOrNone bool,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doUpdateComposite(sf, sObjectName, records, allOrNone, batchSize)
 }
@@ -425,10 +614,15 @@ func (sf *Salesforce) UpsertComposite(
 	batchSize int,
 	allOrNone bool,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doUpsertComposite(sf, sObjectName, externalIdFieldName, records, allOrNone, batchSize)
 }
@@ -439,10 +633,15 @@ func (sf *Salesforce) DeleteComposite(
 	batchSize int,
 	allOrNone bool,
 ) (SalesforceResults, error) {
+	batchSize = defaultBatchSize(sf, sObjectName, records, batchSize)
 	validationErr := validateCollections(*sf, records, batchSize)
 	if validationErr != nil {
 		return SalesforceResults{}, validationErr
 	}
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return SalesforceResults{}, nameErr
+	}
 
 	return doDeleteComposite(sf, sObjectName, records, allOrNone, batchSize)
 }
@@ -519,6 +718,16 @@ func (sf *Salesforce) InsertBulkAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return []string{}, nameErr
+	}
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -557,6 +766,12 @@ func (sf *Salesforce) InsertBulkFileAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -595,6 +810,16 @@ func (sf *Salesforce) UpdateBulkAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return []string{}, nameErr
+	}
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -633,6 +858,12 @@ func (sf *Salesforce) UpdateBulkFileAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -680,6 +911,16 @@ func (sf *Salesforce) UpsertBulkAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return []string{}, nameErr
+	}
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -727,6 +968,12 @@ func (sf *Salesforce) UpsertBulkFileAssign(
 	waitForResults bool,
 	assignmentRuleId string,
 ) ([]string, error) {
+	batchSize, assignmentRuleId = defaultBulkOptions(
+		sf.config,
+		sObjectName,
+		batchSize,
+		assignmentRuleId,
+	)
 	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, assignmentRuleId)
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -755,6 +1002,11 @@ func (sf *Salesforce) DeleteBulk(
 	batchSize int,
 	waitForResults bool,
 ) ([]string, error) {
+	sObjectName, nameErr := inferSObjectName(sf, sObjectName, records)
+	if nameErr != nil {
+		return []string{}, nameErr
+	}
+	batchSize, _ = defaultBulkOptions(sf.config, sObjectName, batchSize, "")
 	validationErr := validateBulk(*sf, records, batchSize, false, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -783,6 +1035,7 @@ func (sf *Salesforce) DeleteBulkFile(
 	batchSize int,
 	waitForResults bool,
 ) ([]string, error) {
+	batchSize, _ = defaultBulkOptions(sf.config, sObjectName, batchSize, "")
 	validationErr := validateBulk(*sf, nil, batchSize, true, sObjectName, "")
 	if validationErr != nil {
 		return []string{}, validationErr
@@ -851,6 +1104,21 @@ func (sf *Salesforce) GetCompressionHeaders() bool {
 	return sf.config.compressionHeaders
 }
 
+// GetResponseCompression returns whether gzip encoded responses are requested
+func (sf *Salesforce) GetResponseCompression() bool {
+	return sf.config.responseCompression
+}
+
+// GetRequestCompressionThreshold returns the request body size at which bodies are gzipped
+func (sf *Salesforce) GetRequestCompressionThreshold() int {
+	return sf.config.requestCompressionThreshold
+}
+
+// GetAPIUsage returns the most recent daily api usage reported by Salesforce
+func (sf *Salesforce) GetAPIUsage() APIUsage {
+	return sf.config.apiUsage.get()
+}
+
 // GetHTTPClient returns the configured HTTP client
 func (sf *Salesforce) GetHTTPClient() *http.Client {
 	return sf.config.httpClient
@@ -874,3 +1142,12 @@ func (sf *Salesforce) GetInstanceUrl() string {
 	}
 	return sf.auth.InstanceUrl
 }
+
+// GetCommunityId returns the id of the Experience Cloud site a community user authenticated through, or
+// an empty string for other users
+func (sf *Salesforce) GetCommunityId() string {
+	if sf.auth == nil {
+		return ""
+	}
+	return sf.auth.CommunityId
+}
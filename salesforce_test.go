@@ -720,6 +720,91 @@ func TestSalesforce_DoRequest(t *testing.T) {
 	}
 }
 
+func TestSalesforce_DoRaw(t *testing.T) {
+	server, sfAuth, capturedRequest := setupTestServerWithCapture("response_body", http.StatusOK)
+	defer server.Close()
+
+	type args struct {
+		method      string
+		relativeUri string
+		headers     http.Header
+		body        []byte
+	}
+	tests := []struct {
+		name        string
+		auth        *authentication
+		args        args
+		wantUri     string
+		wantHeaders http.Header
+		wantErr     bool
+	}{
+		{
+			name: "apex_rest_request",
+			auth: &sfAuth,
+			args: args{
+				method:      http.MethodPost,
+				relativeUri: "/services/apexrest/custom",
+				headers:     http.Header{"X-Custom": []string{"value"}},
+				body:        []byte("{}"),
+			},
+			wantUri: "/services/apexrest/custom",
+			wantHeaders: http.Header{
+				"X-Custom":      []string{"value"},
+				"Authorization": []string{"Bearer accesstokenvalue"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "override_content_type",
+			auth: &sfAuth,
+			args: args{
+				method:      http.MethodPut,
+				relativeUri: "/services/data/v63.0/jobs/ingest/123/batches",
+				headers:     http.Header{"Content-Type": []string{"text/csv"}},
+				body:        []byte("Name\nexample"),
+			},
+			wantUri:     "/services/data/v63.0/jobs/ingest/123/batches",
+			wantHeaders: http.Header{"Content-Type": []string{"text/csv"}},
+			wantErr:     false,
+		},
+		{
+			name: "validation_fail_auth",
+			auth: nil,
+			args: args{
+				method:      http.MethodGet,
+				relativeUri: "/services/apexrest/custom",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(tt.auth)
+			_, err := sf.DoRaw(tt.args.method, tt.args.relativeUri, tt.args.headers, tt.args.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Salesforce.DoRaw() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if (*capturedRequest).RequestURI != tt.wantUri {
+				t.Errorf(
+					"Salesforce.DoRaw() uri = %v, want %v",
+					(*capturedRequest).RequestURI,
+					tt.wantUri,
+				)
+			}
+			for key, expectedValues := range tt.wantHeaders {
+				actualValues := (*capturedRequest).Header[key]
+				if !reflect.DeepEqual(actualValues, expectedValues) {
+					t.Errorf("DoRaw() Header[%s] = %v, want %v", key, actualValues, expectedValues)
+				}
+			}
+		})
+	}
+}
+
 func TestSalesforce_Query(t *testing.T) {
 	type account struct {
 		Id   string
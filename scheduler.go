@@ -0,0 +1,298 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// AppointmentSlotsRequest is a search for available appointment time slots with Salesforce Scheduler
+type AppointmentSlotsRequest struct {
+	StartTime           time.Time
+	EndTime             time.Time
+	WorkTypeGroupId     string // work type group of the appointment, or WorkTypeId
+	WorkTypeId          string
+	TerritoryIds        []string // service territories to search
+	RequiredResourceIds []string // service resources that must be available
+	AccountId           string
+	SchedulingPolicyId  string // the default scheduling policy if empty
+}
+
+// AppointmentSlot is an available appointment time slot in a service territory
+type AppointmentSlot struct {
+	StartTime             time.Time
+	EndTime               time.Time
+	TerritoryId           string
+	RemainingAppointments int
+}
+
+// ServiceAppointment is an appointment to book with Salesforce Scheduler
+type ServiceAppointment struct {
+	ParentRecordId     string // account, lead, or other record the appointment is for
+	WorkTypeId         string
+	ServiceTerritoryId string
+	SchedStartTime     time.Time
+	SchedEndTime       time.Time
+	ContactId          string
+	Subject            string
+	Description        string
+	AppointmentType    string
+	Street             string
+	City               string
+	State              string
+	PostalCode         string
+	Country            string
+	Fields             map[string]any // other fields of the ServiceAppointment record
+}
+
+// AssignedResource is a service resource assigned to a ServiceAppointment
+type AssignedResource struct {
+	ServiceResourceId  string
+	IsRequiredResource bool
+	IsPrimaryResource  bool
+}
+
+// ServiceAppointmentResult contains the ids of a booked appointment and its assigned resources
+type ServiceAppointmentResult struct {
+	ServiceAppointmentId string   `json:"serviceAppointmentId"`
+	AssignedResourceIds  []string `json:"assignedResourceIds"`
+}
+
+type appointmentSlotsPayload struct {
+	StartTime           string            `json:"startTime"`
+	EndTime             string            `json:"endTime"`
+	WorkTypeGroupId     string            `json:"workTypeGroupId,omitempty"`
+	WorkType            map[string]string `json:"workType,omitempty"`
+	TerritoryIds        []string          `json:"territoryIds"`
+	RequiredResourceIds []string          `json:"requiredResourceIds,omitempty"`
+	AccountId           string            `json:"accountId,omitempty"`
+	SchedulingPolicyId  string            `json:"schedulingPolicyId,omitempty"`
+}
+
+type appointmentSlotsResponse struct {
+	TimeSlots []struct {
+		StartTime             string `json:"startTime"`
+		EndTime               string `json:"endTime"`
+		TerritoryId           string `json:"territoryId"`
+		RemainingAppointments int    `json:"remainingAppointments"`
+	} `json:"timeSlots"`
+}
+
+type serviceAppointmentPayload struct {
+	ServiceAppointment map[string]any            `json:"serviceAppointment"`
+	AssignedResources  []assignedResourcePayload `json:"assignedResources,omitempty"`
+}
+
+type assignedResourcePayload struct {
+	ServiceResourceId  string `json:"serviceResourceId"`
+	IsRequiredResource bool   `json:"isRequiredResource"`
+	IsPrimaryResource  bool   `json:"isPrimaryResource"`
+}
+
+// GetAppointmentSlots returns the time slots that are available for an appointment, in the order
+// returned by Salesforce Scheduler
+func (sf *Salesforce) GetAppointmentSlots(
+	request AppointmentSlotsRequest,
+) ([]AppointmentSlot, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if request.StartTime.IsZero() || request.EndTime.IsZero() ||
+		!request.EndTime.After(request.StartTime) {
+		return nil, errors.New("a start time before the end time is required")
+	}
+	if (request.WorkTypeGroupId == "") == (request.WorkTypeId == "") {
+		return nil, errors.New("either a work type group id or a work type id is required")
+	}
+	if len(request.TerritoryIds) == 0 {
+		return nil, errors.New("at least one territory id is required")
+	}
+
+	payload := appointmentSlotsPayload{
+		StartTime:           request.StartTime.UTC().Format(soqlDateTimeFormat),
+		EndTime:             request.EndTime.UTC().Format(soqlDateTimeFormat),
+		WorkTypeGroupId:     request.WorkTypeGroupId,
+		TerritoryIds:        request.TerritoryIds,
+		RequiredResourceIds: request.RequiredResourceIds,
+		AccountId:           request.AccountId,
+		SchedulingPolicyId:  request.SchedulingPolicyId,
+	}
+	if request.WorkTypeId != "" {
+		payload.WorkType = map[string]string{"id": request.WorkTypeId}
+	}
+	slotsResp := appointmentSlotsResponse{}
+	err := doSchedulingRequest(
+		sf,
+		http.MethodPost,
+		"/connect/scheduling/available-appointment-slots",
+		payload,
+		&slotsResp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]AppointmentSlot, len(slotsResp.TimeSlots))
+	for i, timeSlot := range slotsResp.TimeSlots {
+		start, err := time.Parse(salesforceDateTimeFormat, timeSlot.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing slot start time: %w", err)
+		}
+		end, err := time.Parse(salesforceDateTimeFormat, timeSlot.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing slot end time: %w", err)
+		}
+		slots[i] = AppointmentSlot{
+			StartTime:             start,
+			EndTime:               end,
+			TerritoryId:           timeSlot.TerritoryId,
+			RemainingAppointments: timeSlot.RemainingAppointments,
+		}
+	}
+	return slots, nil
+}
+
+// CreateServiceAppointment books an appointment and assigns its resources with Salesforce Scheduler,
+// which checks the availability of the resources unlike inserting the records directly
+func (sf *Salesforce) CreateServiceAppointment(
+	appointment ServiceAppointment,
+	resources []AssignedResource,
+) (ServiceAppointmentResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ServiceAppointmentResult{}, authErr
+	}
+	if appointment.ParentRecordId == "" || appointment.SchedStartTime.IsZero() ||
+		appointment.SchedEndTime.IsZero() {
+		return ServiceAppointmentResult{}, errors.New(
+			"parent record id, scheduled start time, and scheduled end time are required",
+		)
+	}
+	if len(resources) == 0 {
+		return ServiceAppointmentResult{}, errors.New("at least one assigned resource is required")
+	}
+	return saveServiceAppointment(
+		sf,
+		http.MethodPost,
+		"/connect/scheduling/service-appointments",
+		appointment,
+		resources,
+	)
+}
+
+// UpdateServiceAppointment reschedules or updates a booked appointment. Only the fields that are set
+// are changed, and the assigned resources are replaced when resources is not empty.
+func (sf *Salesforce) UpdateServiceAppointment(
+	serviceAppointmentId string,
+	appointment ServiceAppointment,
+	resources []AssignedResource,
+) (ServiceAppointmentResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return ServiceAppointmentResult{}, authErr
+	}
+	if serviceAppointmentId == "" {
+		return ServiceAppointmentResult{}, errors.New("service appointment id is required")
+	}
+	return saveServiceAppointment(
+		sf,
+		http.MethodPatch,
+		"/connect/scheduling/service-appointments/"+url.PathEscape(serviceAppointmentId),
+		appointment,
+		resources,
+	)
+}
+
+func saveServiceAppointment(
+	sf *Salesforce,
+	method string,
+	uri string,
+	appointment ServiceAppointment,
+	resources []AssignedResource,
+) (ServiceAppointmentResult, error) {
+	payload := serviceAppointmentPayload{ServiceAppointment: appointment.payload()}
+	for _, resource := range resources {
+		payload.AssignedResources = append(payload.AssignedResources, assignedResourcePayload{
+			ServiceResourceId:  resource.ServiceResourceId,
+			IsRequiredResource: resource.IsRequiredResource,
+			IsPrimaryResource:  resource.IsPrimaryResource,
+		})
+	}
+	resp := struct {
+		Result ServiceAppointmentResult `json:"result"`
+	}{}
+	if err := doSchedulingRequest(sf, method, uri, payload, &resp); err != nil {
+		return ServiceAppointmentResult{}, err
+	}
+	return resp.Result, nil
+}
+
+// payload returns the serviceAppointment input of the scheduling API, which takes fields that are
+// not part of it as extended fields
+func (a ServiceAppointment) payload() map[string]any {
+	input := map[string]any{}
+	setNonEmptyField(input, "parentRecordId", a.ParentRecordId)
+	setNonEmptyField(input, "workTypeId", a.WorkTypeId)
+	setNonEmptyField(input, "serviceTerritoryId", a.ServiceTerritoryId)
+	setNonEmptyField(input, "contactId", a.ContactId)
+	setNonEmptyField(input, "subject", a.Subject)
+	setNonEmptyField(input, "description", a.Description)
+	setNonEmptyField(input, "appointmentType", a.AppointmentType)
+	setNonEmptyField(input, "street", a.Street)
+	setNonEmptyField(input, "city", a.City)
+	setNonEmptyField(input, "state", a.State)
+	setNonEmptyField(input, "postalCode", a.PostalCode)
+	setNonEmptyField(input, "country", a.Country)
+	if !a.SchedStartTime.IsZero() {
+		input["schedStartTime"] = a.SchedStartTime.UTC().Format(soqlDateTimeFormat)
+	}
+	if !a.SchedEndTime.IsZero() {
+		input["schedEndTime"] = a.SchedEndTime.UTC().Format(soqlDateTimeFormat)
+	}
+
+	names := make([]string, 0, len(a.Fields))
+	for name := range a.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var extendedFields []map[string]any
+	for _, name := range names {
+		extendedFields = append(extendedFields, map[string]any{
+			"name":  name,
+			"value": a.Fields[name],
+		})
+	}
+	if len(extendedFields) > 0 {
+		input["extendedFields"] = extendedFields
+	}
+	return input
+}
+
+func doSchedulingRequest(
+	sf *Salesforce,
+	method string,
+	uri string,
+	payload any,
+	response any,
+) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   method,
+		uri:      uri,
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return err
+	}
+	return decodeJSONResponse(resp, response)
+}
@@ -0,0 +1,145 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_GetAppointmentSlots(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(`{"timeSlots":[{"startTime":"2024-03-08T09:00:00.000+0000",` +
+			`"endTime":"2024-03-08T10:00:00.000+0000","territoryId":"0Hh000000000001AAA",` +
+			`"remainingAppointments":2}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	start := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	request := AppointmentSlotsRequest{
+		StartTime:    start,
+		EndTime:      start.Add(24 * time.Hour),
+		WorkTypeId:   "08q000000000001AAA",
+		TerritoryIds: []string{"0Hh000000000001AAA"},
+	}
+	got, err := sf.GetAppointmentSlots(request)
+	if err != nil {
+		t.Fatalf("GetAppointmentSlots() error = %v", err)
+	}
+	want := []AppointmentSlot{{
+		StartTime:             start.Add(9 * time.Hour),
+		EndTime:               start.Add(10 * time.Hour),
+		TerritoryId:           "0Hh000000000001AAA",
+		RemainingAppointments: 2,
+	}}
+	if len(got) != 1 || !got[0].StartTime.Equal(want[0].StartTime) ||
+		!got[0].EndTime.Equal(want[0].EndTime) || got[0].TerritoryId != want[0].TerritoryId ||
+		got[0].RemainingAppointments != 2 {
+		t.Errorf("GetAppointmentSlots() = %+v, want %+v", got, want)
+	}
+	wantSent := map[string]any{
+		"startTime":    "2024-03-08T00:00:00Z",
+		"endTime":      "2024-03-09T00:00:00Z",
+		"workType":     map[string]any{"id": "08q000000000001AAA"},
+		"territoryIds": []any{"0Hh000000000001AAA"},
+	}
+	if !reflect.DeepEqual(sent, wantSent) {
+		t.Errorf("sent = %v, want %v", sent, wantSent)
+	}
+
+	request.WorkTypeGroupId = "0VS000000000001AAA"
+	if _, err := sf.GetAppointmentSlots(request); err == nil {
+		t.Error("GetAppointmentSlots() expected an error with a work type and a work type group")
+	}
+}
+
+func TestSalesforce_CreateServiceAppointment(t *testing.T) {
+	var method, path string
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		sent = nil
+		_ = json.Unmarshal(body, &sent)
+		_, _ = w.Write([]byte(`{"result":{"serviceAppointmentId":"08p000000000001AAA",` +
+			`"assignedResourceIds":["03r000000000001AAA"]}}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	start := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	appointment := ServiceAppointment{
+		ParentRecordId:     "001000000000001AAA",
+		WorkTypeId:         "08q000000000001AAA",
+		ServiceTerritoryId: "0Hh000000000001AAA",
+		SchedStartTime:     start,
+		SchedEndTime:       start.Add(time.Hour),
+		Subject:            "Consultation",
+		Fields:             map[string]any{"Channel__c": "Web"},
+	}
+	resources := []AssignedResource{
+		{
+			ServiceResourceId:  "0Hn000000000001AAA",
+			IsRequiredResource: true,
+			IsPrimaryResource:  true,
+		},
+	}
+	got, err := sf.CreateServiceAppointment(appointment, resources)
+	if err != nil {
+		t.Fatalf("CreateServiceAppointment() error = %v", err)
+	}
+	want := ServiceAppointmentResult{
+		ServiceAppointmentId: "08p000000000001AAA",
+		AssignedResourceIds:  []string{"03r000000000001AAA"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateServiceAppointment() = %+v, want %+v", got, want)
+	}
+	wantSent := map[string]any{
+		"serviceAppointment": map[string]any{
+			"parentRecordId":     "001000000000001AAA",
+			"workTypeId":         "08q000000000001AAA",
+			"serviceTerritoryId": "0Hh000000000001AAA",
+			"schedStartTime":     "2024-03-08T09:00:00Z",
+			"schedEndTime":       "2024-03-08T10:00:00Z",
+			"subject":            "Consultation",
+			"extendedFields":     []any{map[string]any{"name": "Channel__c", "value": "Web"}},
+		},
+		"assignedResources": []any{map[string]any{
+			"serviceResourceId":  "0Hn000000000001AAA",
+			"isRequiredResource": true,
+			"isPrimaryResource":  true,
+		}},
+	}
+	if method != http.MethodPost || !reflect.DeepEqual(sent, wantSent) {
+		t.Errorf("%s sent = %v, want %v", method, sent, wantSent)
+	}
+
+	_, err = sf.UpdateServiceAppointment(
+		"08p000000000001AAA",
+		ServiceAppointment{SchedStartTime: start.Add(time.Hour)},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("UpdateServiceAppointment() error = %v", err)
+	}
+	wantSent = map[string]any{
+		"serviceAppointment": map[string]any{"schedStartTime": "2024-03-08T10:00:00Z"},
+	}
+	if method != http.MethodPatch ||
+		path != "/services/data/"+apiVersion+"/connect/scheduling/service-appointments/08p000000000001AAA" ||
+		!reflect.DeepEqual(sent, wantSent) {
+		t.Errorf("%s %s sent = %v, want %v", method, path, sent, wantSent)
+	}
+
+	if _, err := sf.CreateServiceAppointment(appointment, nil); err == nil {
+		t.Error("CreateServiceAppointment() expected an error without resources")
+	}
+}
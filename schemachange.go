@@ -0,0 +1,177 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SchemaChange reports a change of the org schema detected by NotifyOnSchemaChange
+type SchemaChange struct {
+	SObjectName string           // sObject whose describe changed, empty if any sObject changed
+	Describe    *SObjectDescribe // the new describe of SObjectName, nil if it is empty
+}
+
+// describeVersion identifies a describe response, to ask Salesforce whether it changed
+type describeVersion struct {
+	etag         string
+	lastModified string
+}
+
+// DescribeSObjectIfModified returns the metadata of an sObject if it changed since the given time,
+// using the If-Modified-Since header, or false if it did not change. The describe cache is bypassed.
+func (sf *Salesforce) DescribeSObjectIfModified(
+	sObjectName string,
+	since time.Time,
+) (*SObjectDescribe, bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, false, authErr
+	}
+	if sObjectName == "" {
+		return nil, false, errors.New("sObject name is required")
+	}
+	describe := &SObjectDescribe{}
+	_, modified, err := describeIfModified(
+		context.Background(),
+		sf,
+		describeUri(sObjectName),
+		describeVersion{lastModified: since.UTC().Format(http.TimeFormat)},
+		describe,
+	)
+	if err != nil || !modified {
+		return nil, false, err
+	}
+	sf.config.describeCache.set(strings.ToLower(sObjectName), describe)
+	return describe, true, nil
+}
+
+// NotifyOnSchemaChange polls the describes of the given sObjects every interval, or the global
+// describe if none are given, and calls onChange when one changes, such as when an admin adds a field.
+// Describes are requested with their ETag and If-Modified-Since headers, so Salesforce only returns a
+// describe that changed. Cached describes of a changed sObject, or of every sObject if the global
+// describe changed, are removed before onChange is called. It runs until the context is done or a
+// request fails.
+func (sf *Salesforce) NotifyOnSchemaChange(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(change SchemaChange),
+	sObjectNames ...string,
+) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if interval <= 0 {
+		return errors.New("poll interval must be greater than 0")
+	}
+	if onChange == nil {
+		return errors.New("onChange cannot be nil")
+	}
+	if len(sObjectNames) == 0 {
+		sObjectNames = []string{""}
+	}
+
+	// the first describe of each sObject is the version later ones are compared to
+	versions := make([]describeVersion, len(sObjectNames))
+	for i, sObjectName := range sObjectNames {
+		version, _, err := describeIfModified(
+			ctx,
+			sf,
+			describeUri(sObjectName),
+			describeVersion{},
+			&SObjectDescribe{},
+		)
+		if err != nil {
+			return err
+		}
+		versions[i] = version
+	}
+
+	return pollUntilDone(ctx, interval, func(ctx context.Context) (bool, error) {
+		for i, sObjectName := range sObjectNames {
+			describe := &SObjectDescribe{}
+			version, modified, err := describeIfModified(
+				ctx,
+				sf,
+				describeUri(sObjectName),
+				versions[i],
+				describe,
+			)
+			if err != nil {
+				return true, err
+			}
+			if !modified {
+				continue
+			}
+			versions[i] = version
+			change := SchemaChange{SObjectName: sObjectName}
+			if sObjectName == "" {
+				sf.ClearDescribeCache()
+			} else {
+				change.Describe = describe
+				sf.config.describeCache.delete(strings.ToLower(sObjectName))
+				_ = sf.InvalidateResponseCache(describeUri(sObjectName))
+			}
+			onChange(change)
+		}
+		return false, nil
+	})
+}
+
+// describeUri returns the uri of the describe of an sObject, or of the global describe if empty
+func describeUri(sObjectName string) string {
+	if sObjectName == "" {
+		return "/sobjects"
+	}
+	return "/sobjects/" + url.PathEscape(sObjectName) + "/describe"
+}
+
+// describeIfModified requests a describe conditionally on the given version, decoding it into
+// describe and returning its version when it was modified. The request is sent without the response
+// cache, which would return the cached describe instead of asking Salesforce.
+func describeIfModified(
+	ctx context.Context,
+	sf *Salesforce,
+	uri string,
+	version describeVersion,
+	describe any,
+) (describeVersion, bool, error) {
+	var options []RequestOption
+	if version.etag != "" {
+		options = append(options, WithHeader("If-None-Match", version.etag))
+	}
+	if version.lastModified != "" {
+		options = append(options, WithHeader("If-Modified-Since", version.lastModified))
+	}
+	resp, err := sendRequest(sf.auth, sf.config, requestPayload{
+		ctx:      ctx,
+		method:   http.MethodGet,
+		uri:      uri,
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+		options:  options,
+	})
+	if err != nil {
+		return version, false, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return version, false, resp.Body.Close()
+	}
+
+	next := describeVersion{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if next.lastModified == "" {
+		// the global describe has no Last-Modified header, so it is compared to the time of the response
+		next.lastModified = resp.Header.Get("Date")
+	}
+	if err := decodeJSONResponse(resp, describe); err != nil {
+		return version, false, err
+	}
+	return next, true, nil
+}
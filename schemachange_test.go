@@ -0,0 +1,138 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSalesforce_DescribeSObjectIfModified(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ifModifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifModifiedSince = r.Header.Get("If-Modified-Since")
+		since, err := http.ParseTime(ifModifiedSince)
+		if err == nil && !lastModified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"name":"Account","fields":[{"name":"Name","type":"string"}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name         string
+		since        time.Time
+		wantModified bool
+	}{
+		{
+			name:         "modified",
+			since:        lastModified.Add(-time.Hour),
+			wantModified: true,
+		},
+		{
+			name:         "not_modified",
+			since:        lastModified,
+			wantModified: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			describe, modified, err := sf.DescribeSObjectIfModified("Account", tt.since)
+			if err != nil {
+				t.Fatalf("DescribeSObjectIfModified() error = %v", err)
+			}
+			if modified != tt.wantModified || (describe != nil) != tt.wantModified {
+				t.Errorf(
+					"DescribeSObjectIfModified() = %v, %v, want modified %v",
+					describe,
+					modified,
+					tt.wantModified,
+				)
+			}
+			if ifModifiedSince != tt.since.Format(http.TimeFormat) {
+				t.Errorf("If-Modified-Since = %v", ifModifiedSince)
+			}
+		})
+	}
+
+	if _, _, err := sf.DescribeSObjectIfModified("", time.Now()); err == nil {
+		t.Error("DescribeSObjectIfModified() expected an error without an sObject name")
+	}
+}
+
+func TestSalesforce_NotifyOnSchemaChange(t *testing.T) {
+	var mu sync.Mutex
+	etag := `"v1"`
+	var ifNoneMatch []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		current := etag
+		etag = `"v2"` // the schema changes after the first describe
+		w.Header().Set("ETag", current)
+		_, _ = w.Write([]byte(`{"name":"Account","fields":[{"name":"Name","type":"string"}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	sf.config.describeCache.set("account", &SObjectDescribe{Name: "Account"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var changes []SchemaChange
+	err := sf.NotifyOnSchemaChange(ctx, 10*time.Millisecond, func(change SchemaChange) {
+		changes = append(changes, change)
+		cancel()
+	}, "Account")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("NotifyOnSchemaChange() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].SObjectName != "Account" || changes[0].Describe == nil ||
+		len(changes[0].Describe.Fields) != 1 {
+		t.Fatalf("changes = %v", changes)
+	}
+	if _, ok := sf.config.describeCache.get("account"); ok {
+		t.Error("NotifyOnSchemaChange() did not remove the cached describe")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ifNoneMatch) != 2 || ifNoneMatch[0] != "" || ifNoneMatch[1] != `"v1"` {
+		t.Errorf("If-None-Match = %v", ifNoneMatch)
+	}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		onChange func(SchemaChange)
+	}{
+		{
+			name:     "invalid_interval",
+			interval: 0,
+			onChange: func(SchemaChange) {},
+		},
+		{
+			name:     "nil_on_change",
+			interval: time.Second,
+			onChange: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sf.NotifyOnSchemaChange(context.Background(), tt.interval, tt.onChange)
+			if err == nil {
+				t.Error("NotifyOnSchemaChange() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// SessionSecurityLevel is the security level of a session, which is raised to high assurance by
+// verifying the user's identity with multi-factor authentication
+type SessionSecurityLevel string
+
+const (
+	SessionSecurityStandard      SessionSecurityLevel = "STANDARD"
+	SessionSecurityHighAssurance SessionSecurityLevel = "HIGH_ASSURANCE"
+)
+
+// SessionSecurity is the security information of the current session, read from its AuthSession record
+type SessionSecurity struct {
+	SessionId       string // Id of the AuthSession record
+	SecurityLevel   SessionSecurityLevel
+	SessionType     string // such as Oauth2 or UI
+	LoginType       string
+	SourceIp        string // IP address the session was created from
+	NumSecondsValid int
+}
+
+// HighAssuranceSessionError is returned when Salesforce rejects a request because the operation
+// requires a high assurance session, such as managing users or reading some setup data in orgs that
+// require it. Retrying the request fails the same way until the user verifies their identity and
+// authenticates again with a high assurance session.
+type HighAssuranceSessionError struct {
+	Errors []SalesforceErrorMessage
+	body   string
+}
+
+func (e *HighAssuranceSessionError) Error() string {
+	return e.body
+}
+
+// highAssuranceError returns a HighAssuranceSessionError if one of the errors of a response is due to
+// the session security level
+func highAssuranceError(sfErrors []SalesforceErrorMessage, body string) error {
+	for _, sfError := range sfErrors {
+		message := strings.ToLower(sfError.Message)
+		if strings.Contains(sfError.ErrorCode, "HIGH_ASSURANCE") ||
+			strings.Contains(message, "high assurance") ||
+			strings.Contains(message, "session security level") {
+			return &HighAssuranceSessionError{Errors: sfErrors, body: body}
+		}
+	}
+	return nil
+}
+
+// GetSessionSecurity returns the security information of the current session
+func (sf *Salesforce) GetSessionSecurity() (SessionSecurity, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SessionSecurity{}, authErr
+	}
+	records, err := queryAllRecords(
+		context.Background(),
+		sf,
+		"SELECT Id, SessionSecurityLevel, SessionType, LoginType, SourceIp, NumSecondsValid "+
+			"FROM AuthSession WHERE IsCurrent = true",
+	)
+	if err != nil {
+		return SessionSecurity{}, err
+	}
+	if len(records) == 0 {
+		return SessionSecurity{}, errors.New("no auth session found for the current session")
+	}
+	r := Record(records[0])
+	return SessionSecurity{
+		SessionId:       r.Id(),
+		SecurityLevel:   SessionSecurityLevel(r.GetString("SessionSecurityLevel")),
+		SessionType:     r.GetString("SessionType"),
+		LoginType:       r.GetString("LoginType"),
+		SourceIp:        r.GetString("SourceIp"),
+		NumSecondsValid: r.GetInt("NumSecondsValid"),
+	}, nil
+}
+
+// RequireHighAssurance returns a HighAssuranceSessionError if the current session is not a high
+// assurance session, so sensitive operations can ask the user to step up their authentication before
+// starting rather than failing partway through
+func (sf *Salesforce) RequireHighAssurance() error {
+	session, err := sf.GetSessionSecurity()
+	if err != nil {
+		return err
+	}
+	if session.SecurityLevel == SessionSecurityHighAssurance {
+		return nil
+	}
+	message := SalesforceErrorMessage{
+		Message: fmt.Sprintf(
+			"session security level is %s, a high assurance session is required",
+			session.SecurityLevel,
+		),
+	}
+	return &HighAssuranceSessionError{
+		Errors: []SalesforceErrorMessage{message},
+		body:   message.Message,
+	}
+}
+
+// LoginIpAllowed returns whether a profile allows logins from an IP address, by checking the login IP
+// ranges of the profile. Profiles without login IP ranges allow logins from any IP address.
+func (sf *Salesforce) LoginIpAllowed(profileId string, ip string) (bool, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return false, authErr
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false, err
+	}
+	addr = addr.Unmap()
+	ranges, err := queryAllRecords(
+		context.Background(),
+		sf,
+		"SELECT StartAddress, EndAddress FROM LoginIpRange WHERE ProfileId = '"+
+			escapeSoqlString(profileId)+"'",
+	)
+	if err != nil {
+		return false, err
+	}
+	if len(ranges) == 0 {
+		return true, nil
+	}
+	for _, ipRange := range ranges {
+		r := Record(ipRange)
+		start, err := netip.ParseAddr(r.GetString("StartAddress"))
+		if err != nil {
+			return false, err
+		}
+		end, err := netip.ParseAddr(r.GetString("EndAddress"))
+		if err != nil {
+			return false, err
+		}
+		if addr.Compare(start) >= 0 && addr.Compare(end) <= 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
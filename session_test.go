@@ -0,0 +1,126 @@
+package salesforce
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_highAssuranceError(t *testing.T) {
+	server, sfAuth := setupTestServer([]SalesforceErrorMessage{{
+		Message:   "This operation requires a high assurance session",
+		ErrorCode: "INSUFFICIENT_ACCESS",
+	}}, http.StatusForbidden)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	_, err := sf.DoRequest(http.MethodGet, "/sobjects/User", nil)
+	var haErr *HighAssuranceSessionError
+	if !errors.As(err, &haErr) {
+		t.Fatalf("DoRequest() error = %v, want a HighAssuranceSessionError", err)
+	}
+	if len(haErr.Errors) != 1 || haErr.Errors[0].ErrorCode != "INSUFFICIENT_ACCESS" {
+		t.Errorf("HighAssuranceSessionError.Errors = %v", haErr.Errors)
+	}
+	if !strings.Contains(err.Error(), "high assurance session") {
+		t.Errorf("Error() = %v", err.Error())
+	}
+
+	if highAssuranceError([]SalesforceErrorMessage{{Message: "insufficient access"}}, "") != nil {
+		t.Error("highAssuranceError() returned an error for an unrelated error")
+	}
+}
+
+func TestSalesforce_GetSessionSecurity(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       string
+		wantElevate bool
+	}{
+		{name: "standard", level: "STANDARD", wantElevate: true},
+		{name: "high_assurance", level: "HIGH_ASSURANCE", wantElevate: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{
+						"Id": "0Ak000000000001AAA",
+						"SessionSecurityLevel": "` + tt.level + `",
+						"SessionType": "Oauth2",
+						"SourceIp": "10.0.0.1",
+						"NumSecondsValid": 7200
+					}]}`))
+				}),
+			)
+			defer server.Close()
+			sf := buildSalesforceStruct(
+				&authentication{InstanceUrl: server.URL, AccessToken: "1234"},
+			)
+
+			session, err := sf.GetSessionSecurity()
+			if err != nil {
+				t.Fatalf("GetSessionSecurity() error = %v", err)
+			}
+			if session.SecurityLevel != SessionSecurityLevel(tt.level) ||
+				session.SourceIp != "10.0.0.1" || session.NumSecondsValid != 7200 {
+				t.Errorf("GetSessionSecurity() = %v", session)
+			}
+			err = sf.RequireHighAssurance()
+			var haErr *HighAssuranceSessionError
+			if errors.As(err, &haErr) != tt.wantElevate {
+				t.Errorf(
+					"RequireHighAssurance() error = %v, want elevation %v",
+					err,
+					tt.wantElevate,
+				)
+			}
+		})
+	}
+}
+
+func TestSalesforce_LoginIpAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "00e000000000002AAA") {
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[
+			{"StartAddress": "10.0.0.0", "EndAddress": "10.0.0.255"}
+		]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name      string
+		profileId string
+		ip        string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "in_range", profileId: "00e000000000001AAA", ip: "10.0.0.42", want: true},
+		{
+			name:      "mapped_in_range",
+			profileId: "00e000000000001AAA",
+			ip:        "::ffff:10.0.0.1",
+			want:      true,
+		},
+		{name: "out_of_range", profileId: "00e000000000001AAA", ip: "10.0.1.1", want: false},
+		{name: "no_ranges", profileId: "00e000000000002AAA", ip: "192.168.0.1", want: true},
+		{name: "invalid_ip", profileId: "00e000000000001AAA", ip: "not an ip", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sf.LoginIpAllowed(tt.profileId, tt.ip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoginIpAllowed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LoginIpAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
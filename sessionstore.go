@@ -0,0 +1,207 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sessionLockTimeout is how long a process waits for the lock of a shared session, which is held while
+// another process authenticates
+const sessionLockTimeout = time.Minute
+
+// Session is a Salesforce session shared between processes through a SessionStore
+type Session struct {
+	AccessToken string `json:"accessToken"`
+	InstanceUrl string `json:"instanceUrl"`
+	Id          string `json:"id"` // identity url of the user
+}
+
+// SessionStore shares a session between processes, such as the pods of an API behind a load balancer,
+// so they authenticate once instead of each logging in and hitting the org's login limits. Implement it
+// with an external cache, such as Redis or memcached. Sessions are keyed by the key passed to
+// WithSessionStore.
+type SessionStore interface {
+	// Get returns the stored session of a key, and false if none is stored
+	Get(ctx context.Context, key string) (Session, bool, error)
+	// Set stores the session of a key
+	Set(ctx context.Context, key string, session Session) error
+	// Lock blocks until the caller holds the lock of a key, which guards authenticating for its session
+	// across processes, or until ctx is done. Implementations should expire locks whose holder died.
+	Lock(ctx context.Context, key string) error
+	// Unlock releases the lock of a key
+	Unlock(ctx context.Context, key string) error
+}
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	locks    map[string]chan struct{}
+}
+
+// NewMemorySessionStore returns an in-memory SessionStore, which shares a session between the clients
+// of a single process
+func NewMemorySessionStore() SessionStore {
+	return &memorySessionStore{
+		sessions: map[string]Session{},
+		locks:    map[string]chan struct{}{},
+	}
+}
+
+func (s *memorySessionStore) Get(_ context.Context, key string) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[key]
+	return session, ok, nil
+}
+
+func (s *memorySessionStore) Set(_ context.Context, key string, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[key] = session
+	return nil
+}
+
+func (s *memorySessionStore) Lock(ctx context.Context, key string) error {
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.locks[key] = lock
+	}
+	s.mu.Unlock()
+	select {
+	case lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *memorySessionStore) Unlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s is not locked", key)
+	}
+	select {
+	case <-lock:
+		return nil
+	default:
+		return fmt.Errorf("session %s is not locked", key)
+	}
+}
+
+// authFlowGrantTypes are the grant types that sessions of each auth flow are refreshed with
+var authFlowGrantTypes = map[AuthFlowType]string{
+	AuthFlowUsernamePassword:  grantTypeUsernamePassword,
+	AuthFlowClientCredentials: grantTypeClientCredentials,
+	AuthFlowAccessToken:       grantTypeAccessToken,
+	AuthFlowJWT:               grantTypeJWT,
+	AuthFlowSoapLogin:         grantTypeSoapLogin,
+}
+
+// authenticateShared uses the session stored in the SessionStore of config, authenticating and storing
+// the session only if none is stored. The store is locked meanwhile, so that processes starting at
+// once authenticate only once.
+func authenticateShared(config *configuration, creds Creds) (*authentication, AuthFlowType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLockTimeout)
+	defer cancel()
+	store, key := config.sessionStore, config.sessionKey
+	if err := store.Lock(ctx, key); err != nil {
+		return nil, AuthFlowUnknown, fmt.Errorf("locking shared session: %w", err)
+	}
+	defer func() {
+		_ = store.Unlock(context.Background(), key)
+	}()
+
+	session, ok, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, AuthFlowUnknown, fmt.Errorf("getting shared session: %w", err)
+	}
+	var auth *authentication
+	var authFlow AuthFlowType
+	if ok && session.AccessToken != "" {
+		if config.credentialProvider != nil {
+			creds, err = config.credentialProvider.GetCreds(ctx)
+			if err != nil {
+				return nil, AuthFlowUnknown, fmt.Errorf("getting credentials: %w", err)
+			}
+		}
+		authFlow = detectAuthFlow(config, creds)
+		if authFlow == AuthFlowUnknown {
+			return nil, authFlow, errors.New("unknown authentication error")
+		}
+		auth = &authentication{
+			AccessToken:        session.AccessToken,
+			InstanceUrl:        session.InstanceUrl,
+			Id:                 session.Id,
+			TokenType:          "Bearer",
+			grantType:          authFlowGrantTypes[authFlow],
+			creds:              creds,
+			credentialProvider: config.credentialProvider,
+		}
+	} else {
+		auth, authFlow, err = authenticateOwn(config, creds)
+		if err != nil {
+			return nil, authFlow, err
+		}
+		if err := store.Set(ctx, key, sessionOf(auth)); err != nil {
+			return nil, authFlow, fmt.Errorf("storing shared session: %w", err)
+		}
+	}
+	auth.sessionStore = store
+	auth.sessionKey = key
+	return auth, authFlow, nil
+}
+
+// refreshSharedSession refreshes a shared session while holding its lock. If another process already
+// refreshed it, its session is used instead of authenticating again, including its instance url unless
+// the client set one. The instance url of the token response is stored either way, so that pinning it
+// in one process does not change the session of the others.
+func refreshSharedSession(auth *authentication, config *configuration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionLockTimeout)
+	defer cancel()
+	store, key := auth.sessionStore, auth.sessionKey
+	if err := store.Lock(ctx, key); err != nil {
+		return fmt.Errorf("locking shared session: %w", err)
+	}
+	defer func() {
+		_ = store.Unlock(context.Background(), key)
+	}()
+
+	session, ok, err := store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("getting shared session: %w", err)
+	}
+	if ok && session.AccessToken != "" && session.AccessToken != auth.AccessToken {
+		auth.AccessToken = session.AccessToken
+		auth.Id = valueOrDefault(session.Id, auth.Id)
+		auth.tokenInstanceUrl = valueOrDefault(session.InstanceUrl, auth.tokenInstanceUrl)
+		// the org may have moved to another instance, such as after a My Domain change
+		if !auth.pinnedInstanceUrl {
+			auth.InstanceUrl = valueOrDefault(session.InstanceUrl, auth.InstanceUrl)
+		}
+		return nil
+	}
+	if err := refreshOwnSession(auth, config); err != nil {
+		return err
+	}
+	if err := store.Set(ctx, key, sessionOf(auth)); err != nil {
+		return fmt.Errorf("storing shared session: %w", err)
+	}
+	return nil
+}
+
+// sessionOf returns the session of auth to share, with the instance url of the token response rather
+// than an instance url pinned by the client
+func sessionOf(auth *authentication) Session {
+	return Session{
+		AccessToken: auth.AccessToken,
+		InstanceUrl: valueOrDefault(auth.tokenInstanceUrl, auth.InstanceUrl),
+		Id:          auth.Id,
+	}
+}
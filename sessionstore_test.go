@@ -0,0 +1,198 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithSessionStore_sharedSession(t *testing.T) {
+	tokens := &atomic.Int32{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/token" {
+			body, _ := json.Marshal(authentication{
+				AccessToken: "token-" + strconv.Itoa(int(tokens.Add(1))),
+				InstanceUrl: server.URL,
+				Id:          "https://login.salesforce.com/id/00D000000000001AAA/005000000000001AAA",
+			})
+			_, _ = w.Write(body)
+			return
+		}
+		// only the latest token is valid
+		if r.Header.Get("Authorization") != "Bearer token-"+strconv.Itoa(int(tokens.Load())) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write(
+				[]byte(`[{"errorCode": "INVALID_SESSION_ID", "message": "Session expired"}]`),
+			)
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalSize": 0, "done": true, "records": []}`))
+	}))
+	defer server.Close()
+
+	store := NewMemorySessionStore()
+	creds := Creds{Domain: server.URL, ConsumerKey: "key", ConsumerSecret: "secret"}
+	first, err := Init(creds, WithSessionStore(store, "salesforce:test"))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	second, err := Init(creds, WithSessionStore(store, "salesforce:test"))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if tokens.Load() != 1 || second.auth.AccessToken != "token-1" ||
+		second.AuthFlow != AuthFlowClientCredentials ||
+		second.auth.grantType != grantTypeClientCredentials {
+		t.Fatalf(
+			"second client = %d tokens, %+v, want the stored session",
+			tokens.Load(),
+			second.auth,
+		)
+	}
+
+	// the session expires, and the first client refreshes it
+	tokens.Add(1)
+	records := []map[string]any{}
+	if err := first.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if first.auth.AccessToken != "token-3" {
+		t.Errorf("first AccessToken = %v, want token-3", first.auth.AccessToken)
+	}
+	// the second client uses the refreshed session instead of authenticating again
+	if err := second.Query("SELECT Id FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if second.auth.AccessToken != "token-3" || tokens.Load() != 3 {
+		t.Errorf(
+			"second AccessToken = %v after %d tokens, want token-3 after 3",
+			second.auth.AccessToken,
+			tokens.Load(),
+		)
+	}
+	session, ok, _ := store.Get(context.Background(), "salesforce:test")
+	if !ok || session.AccessToken != "token-3" || session.InstanceUrl != server.URL {
+		t.Errorf("stored session = %+v", session)
+	}
+}
+
+func TestMemorySessionStore_Lock(t *testing.T) {
+	store := NewMemorySessionStore()
+	ctx := context.Background()
+	if err := store.Unlock(ctx, "key"); err == nil {
+		t.Errorf("Unlock() of an unlocked key expected an error")
+	}
+	if err := store.Lock(ctx, "key"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if err := store.Lock(ctx, "other"); err != nil {
+		t.Errorf("Lock() of another key error = %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := store.Lock(timeout, "key"); err == nil {
+		t.Errorf("Lock() of a locked key expected an error once ctx is done")
+	}
+
+	if err := store.Unlock(ctx, "key"); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := store.Lock(ctx, "key"); err != nil {
+		t.Errorf("Lock() after Unlock() error = %v", err)
+	}
+}
+
+func Test_refreshSharedSession_instanceUrl(t *testing.T) {
+	tests := []struct {
+		name            string
+		pinned          bool
+		wantInstanceUrl string
+	}{
+		{
+			name:            "adopts_stored_instance_url",
+			pinned:          false,
+			wantInstanceUrl: "https://new.my.salesforce.com",
+		},
+		{
+			name:            "keeps_pinned_instance_url",
+			pinned:          true,
+			wantInstanceUrl: "https://example.my.site.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemorySessionStore()
+			_ = store.Set(context.Background(), "salesforce:test", Session{
+				AccessToken: "token-2",
+				InstanceUrl: "https://new.my.salesforce.com",
+			})
+			instanceUrl := "https://old.my.salesforce.com"
+			if tt.pinned {
+				instanceUrl = "https://example.my.site.com"
+			}
+			auth := &authentication{
+				AccessToken:       "token-1",
+				InstanceUrl:       instanceUrl,
+				sessionStore:      store,
+				sessionKey:        "salesforce:test",
+				pinnedInstanceUrl: tt.pinned,
+			}
+
+			if err := refreshSharedSession(auth, getDefaultConfig(t)); err != nil {
+				t.Fatalf("refreshSharedSession() error = %v", err)
+			}
+			if auth.AccessToken != "token-2" || auth.InstanceUrl != tt.wantInstanceUrl {
+				t.Errorf(
+					"refreshSharedSession() = %v %v, want token-2 %v",
+					auth.AccessToken,
+					auth.InstanceUrl,
+					tt.wantInstanceUrl,
+				)
+			}
+			if session := sessionOf(auth); session.InstanceUrl != "https://new.my.salesforce.com" {
+				t.Errorf("sessionOf() = %+v, want the stored instance url", session)
+			}
+		})
+	}
+}
+
+func Test_refreshSharedSession_storesTokenInstanceUrl(t *testing.T) {
+	server, _ := setupTestServer(authentication{
+		AccessToken: "token-2",
+		InstanceUrl: "https://new.my.salesforce.com",
+	}, http.StatusOK)
+	defer server.Close()
+	store := NewMemorySessionStore()
+	auth := &authentication{
+		AccessToken:       "token-1",
+		InstanceUrl:       server.URL,
+		grantType:         grantTypeClientCredentials,
+		creds:             Creds{ConsumerKey: "key", ConsumerSecret: "secret"},
+		sessionStore:      store,
+		sessionKey:        "salesforce:test",
+		pinnedInstanceUrl: true,
+		tokenInstanceUrl:  "https://old.my.salesforce.com",
+	}
+
+	if err := refreshSharedSession(auth, getDefaultConfig(t)); err != nil {
+		t.Fatalf("refreshSharedSession() error = %v", err)
+	}
+	if auth.AccessToken != "token-2" || auth.InstanceUrl != server.URL {
+		t.Errorf(
+			"refreshSharedSession() = %v %v, want token-2 with the pinned instance url",
+			auth.AccessToken,
+			auth.InstanceUrl,
+		)
+	}
+	session, _, _ := store.Get(context.Background(), "salesforce:test")
+	if session.AccessToken != "token-2" || session.InstanceUrl != "https://new.my.salesforce.com" {
+		t.Errorf("stored session = %+v, want the instance url of the token response", session)
+	}
+}
@@ -0,0 +1,210 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ShareAccessLevel is the access a share record grants to a record
+type ShareAccessLevel string
+
+const (
+	ShareAccessNone ShareAccessLevel = "None" // only for related access of account shares
+	ShareAccessRead ShareAccessLevel = "Read"
+	ShareAccessEdit ShareAccessLevel = "Edit"
+	ShareAccessAll  ShareAccessLevel = "All"
+)
+
+// RowCause is the reason a share record exists. Apex sharing reasons of custom objects are their API
+// names, such as RowCause("Project_Member__c").
+type RowCause string
+
+const (
+	RowCauseManual         RowCause = "Manual"
+	RowCauseOwner          RowCause = "Owner"
+	RowCauseRule           RowCause = "Rule"
+	RowCauseTeam           RowCause = "Team"
+	RowCauseTerritory      RowCause = "Territory"
+	RowCauseTerritoryRule  RowCause = "TerritoryRule"
+	RowCauseImplicitChild  RowCause = "ImplicitChild"
+	RowCauseImplicitParent RowCause = "ImplicitParent"
+)
+
+// Share is a sharing record of a standard object share, such as AccountShare, or of a custom object
+// share, such as Project__Share
+type Share struct {
+	Id            string
+	ParentId      string // Id of the shared record
+	UserOrGroupId string // Id of the user or group the record is shared with
+	AccessLevel   ShareAccessLevel
+	RowCause      RowCause // Manual if empty; only custom objects accept other row causes
+	// related access of account shares
+	OpportunityAccessLevel ShareAccessLevel
+	CaseAccessLevel        ShareAccessLevel
+	ContactAccessLevel     ShareAccessLevel
+}
+
+// ShareRecords shares records with users or groups by inserting records into the share object of an
+// sObject, such as AccountShare for Account or Project__Share for Project__c
+func (sf *Salesforce) ShareRecords(sObjectName string, shares []Share) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if sObjectName == "" || len(shares) == 0 {
+		return SalesforceResults{}, errors.New("sObject name and shares are required")
+	}
+	custom := isCustomObject(sObjectName)
+	records := make([]map[string]any, len(shares))
+	for i, share := range shares {
+		if share.ParentId == "" || share.UserOrGroupId == "" || share.AccessLevel == "" {
+			return SalesforceResults{}, errors.New(
+				"shares require a parent id, user or group id, and access level",
+			)
+		}
+		if !custom && share.RowCause != "" && share.RowCause != RowCauseManual {
+			return SalesforceResults{}, fmt.Errorf(
+				"shares of %s can only be created with the %s row cause",
+				sObjectName,
+				RowCauseManual,
+			)
+		}
+		record := map[string]any{"UserOrGroupId": share.UserOrGroupId}
+		parentField, accessField := shareFields(sObjectName)
+		record[parentField] = share.ParentId
+		record[accessField] = share.AccessLevel
+		if custom && share.RowCause != "" {
+			record["RowCause"] = share.RowCause
+		}
+		if strings.EqualFold(sObjectName, "Account") {
+			record["OpportunityAccessLevel"] = accessOrNone(share.OpportunityAccessLevel)
+			record["CaseAccessLevel"] = accessOrNone(share.CaseAccessLevel)
+			if share.ContactAccessLevel != "" {
+				record["ContactAccessLevel"] = share.ContactAccessLevel
+			}
+		}
+		records[i] = record
+	}
+	return sf.InsertCollection(shareObjectName(sObjectName), records, sf.config.batchSizeMax)
+}
+
+// GetShares returns the share records of the given records of an sObject
+func (sf *Salesforce) GetShares(sObjectName string, parentIds ...string) ([]Share, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return nil, authErr
+	}
+	if sObjectName == "" || len(parentIds) == 0 {
+		return nil, errors.New("sObject name and parent ids are required")
+	}
+	parentField, accessField := shareFields(sObjectName)
+	fields := []string{"Id", parentField, "UserOrGroupId", accessField, "RowCause"}
+	isAccount := strings.EqualFold(sObjectName, "Account")
+	if isAccount {
+		fields = append(fields, "OpportunityAccessLevel", "CaseAccessLevel", "ContactAccessLevel")
+	}
+	ids := make([]string, len(parentIds))
+	for i, id := range parentIds {
+		ids[i] = "'" + escapeSoqlString(id) + "'"
+	}
+	query := "SELECT " + strings.Join(fields, ", ") + " FROM " + shareObjectName(sObjectName) +
+		" WHERE " + parentField + " IN (" + strings.Join(ids, ", ") + ")"
+	records, err := queryAllRecords(context.Background(), sf, query)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, len(records))
+	for i, record := range records {
+		r := Record(record)
+		shares[i] = Share{
+			Id:            r.Id(),
+			ParentId:      r.GetString(parentField),
+			UserOrGroupId: r.GetString("UserOrGroupId"),
+			AccessLevel:   ShareAccessLevel(r.GetString(accessField)),
+			RowCause:      RowCause(r.GetString("RowCause")),
+		}
+		if isAccount {
+			shares[i].OpportunityAccessLevel = ShareAccessLevel(
+				r.GetString("OpportunityAccessLevel"),
+			)
+			shares[i].CaseAccessLevel = ShareAccessLevel(r.GetString("CaseAccessLevel"))
+			shares[i].ContactAccessLevel = ShareAccessLevel(r.GetString("ContactAccessLevel"))
+		}
+	}
+	return shares, nil
+}
+
+// UnshareRecords deletes share records by their Id, such as shares returned by GetShares. Only
+// manual shares and shares with Apex sharing reasons can be deleted.
+func (sf *Salesforce) UnshareRecords(
+	sObjectName string,
+	shares []Share,
+) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if sObjectName == "" || len(shares) == 0 {
+		return SalesforceResults{}, errors.New("sObject name and shares are required")
+	}
+	records := make([]map[string]any, len(shares))
+	for i, share := range shares {
+		records[i] = map[string]any{"Id": share.Id}
+	}
+	return sf.DeleteCollection(shareObjectName(sObjectName), records, sf.config.batchSizeMax)
+}
+
+// AssignToTerritory manually assigns records, such as accounts, to an Enterprise Territory Management
+// territory
+func (sf *Salesforce) AssignToTerritory(
+	territory2Id string,
+	objectIds ...string,
+) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if territory2Id == "" || len(objectIds) == 0 {
+		return SalesforceResults{}, errors.New("territory id and object ids are required")
+	}
+	records := make([]map[string]any, len(objectIds))
+	for i, objectId := range objectIds {
+		records[i] = map[string]any{
+			"ObjectId":         objectId,
+			"Territory2Id":     territory2Id,
+			"AssociationCause": "Territory2Manual",
+		}
+	}
+	return sf.InsertCollection("ObjectTerritory2Association", records, sf.config.batchSizeMax)
+}
+
+// shareObjectName returns the share object of an sObject
+func shareObjectName(sObjectName string) string {
+	if isCustomObject(sObjectName) {
+		return strings.TrimSuffix(sObjectName, "__c") + "__Share"
+	}
+	return sObjectName + "Share"
+}
+
+// shareFields returns the parent and access level fields of the share object of an sObject, which are
+// named after the sObject for standard objects, such as AccountId and AccountAccessLevel
+func shareFields(sObjectName string) (string, string) {
+	if isCustomObject(sObjectName) {
+		return "ParentId", "AccessLevel"
+	}
+	return sObjectName + "Id", sObjectName + "AccessLevel"
+}
+
+func isCustomObject(sObjectName string) bool {
+	return strings.HasSuffix(strings.ToLower(sObjectName), "__c")
+}
+
+func accessOrNone(level ShareAccessLevel) ShareAccessLevel {
+	if level == "" {
+		return ShareAccessNone
+	}
+	return level
+}
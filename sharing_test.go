@@ -0,0 +1,184 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_ShareRecords(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = w.Write([]byte(`[{"id":"02c000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name        string
+		sObjectName string
+		share       Share
+		want        map[string]any
+		wantErr     bool
+	}{
+		{
+			name:        "account_share",
+			sObjectName: "Account",
+			share: Share{
+				ParentId:        "001000000000001AAA",
+				UserOrGroupId:   "005000000000001AAA",
+				AccessLevel:     ShareAccessEdit,
+				CaseAccessLevel: ShareAccessRead,
+			},
+			want: map[string]any{
+				"attributes":             map[string]any{"type": "AccountShare"},
+				"AccountId":              "001000000000001AAA",
+				"UserOrGroupId":          "005000000000001AAA",
+				"AccountAccessLevel":     "Edit",
+				"OpportunityAccessLevel": "None",
+				"CaseAccessLevel":        "Read",
+			},
+		},
+		{
+			name:        "custom_object_share",
+			sObjectName: "Project__c",
+			share: Share{
+				ParentId:      "a00000000000001AAA",
+				UserOrGroupId: "00G000000000001AAA",
+				AccessLevel:   ShareAccessRead,
+				RowCause:      RowCause("Project_Member__c"),
+			},
+			want: map[string]any{
+				"attributes":    map[string]any{"type": "Project__Share"},
+				"ParentId":      "a00000000000001AAA",
+				"UserOrGroupId": "00G000000000001AAA",
+				"AccessLevel":   "Read",
+				"RowCause":      "Project_Member__c",
+			},
+		},
+		{
+			name:        "standard_object_row_cause",
+			sObjectName: "Opportunity",
+			share: Share{
+				ParentId:      "006000000000001AAA",
+				UserOrGroupId: "005000000000001AAA",
+				AccessLevel:   ShareAccessRead,
+				RowCause:      RowCauseRule,
+			},
+			wantErr: true,
+		},
+		{
+			name:        "missing_access_level",
+			sObjectName: "Opportunity",
+			share:       Share{ParentId: "006000000000001AAA", UserOrGroupId: "005000000000001AAA"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = nil
+			_, err := sf.ShareRecords(tt.sObjectName, []Share{tt.share})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ShareRecords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			records := body["records"].([]any)
+			if !reflect.DeepEqual(records[0], tt.want) {
+				t.Errorf("ShareRecords() sent %v, want %v", records[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_GetShares(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		_, _ = w.Write([]byte(`{"totalSize":1,"done":true,"records":[{
+			"Id": "02c000000000001AAA",
+			"AccountId": "001000000000001AAA",
+			"UserOrGroupId": "005000000000001AAA",
+			"AccountAccessLevel": "Edit",
+			"RowCause": "Manual",
+			"OpportunityAccessLevel": "None",
+			"CaseAccessLevel": "Read",
+			"ContactAccessLevel": "Edit"
+		}]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	shares, err := sf.GetShares("Account", "001000000000001AAA")
+	if err != nil {
+		t.Fatalf("GetShares() error = %v", err)
+	}
+	want := []Share{{
+		Id:                     "02c000000000001AAA",
+		ParentId:               "001000000000001AAA",
+		UserOrGroupId:          "005000000000001AAA",
+		AccessLevel:            ShareAccessEdit,
+		RowCause:               RowCauseManual,
+		OpportunityAccessLevel: ShareAccessNone,
+		CaseAccessLevel:        ShareAccessRead,
+		ContactAccessLevel:     ShareAccessEdit,
+	}}
+	if !reflect.DeepEqual(shares, want) {
+		t.Errorf("GetShares() = %v, want %v", shares, want)
+	}
+	if !strings.Contains(query, "FROM AccountShare WHERE AccountId IN ('001000000000001AAA')") {
+		t.Errorf("query = %v", query)
+	}
+	if _, err := sf.GetShares("Account"); err == nil {
+		t.Error("GetShares() expected an error without parent ids")
+	}
+}
+
+func TestSalesforce_UnshareRecords(t *testing.T) {
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.URL.RequestURI()
+		_, _ = w.Write([]byte(`[{"id":"02c000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	_, err := sf.UnshareRecords("Project__c", []Share{{Id: "02c000000000001AAA"}})
+	if err != nil {
+		t.Fatalf("UnshareRecords() error = %v", err)
+	}
+	if !strings.Contains(uri, "ids=02c000000000001AAA") {
+		t.Errorf("uri = %v", uri)
+	}
+}
+
+func TestSalesforce_AssignToTerritory(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = w.Write([]byte(`[{"id":"0R5000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	if _, err := sf.AssignToTerritory("0MI000000000001AAA", "001000000000001AAA"); err != nil {
+		t.Fatalf("AssignToTerritory() error = %v", err)
+	}
+	record := body["records"].([]any)[0].(map[string]any)
+	if record["ObjectId"] != "001000000000001AAA" ||
+		record["Territory2Id"] != "0MI000000000001AAA" ||
+		record["AssociationCause"] != "Territory2Manual" {
+		t.Errorf("AssignToTerritory() sent %v", record)
+	}
+	if _, err := sf.AssignToTerritory("0MI000000000001AAA"); err == nil {
+		t.Error("AssignToTerritory() expected an error without object ids")
+	}
+}
@@ -0,0 +1,487 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The SOAP API is only used for the few operations that have no REST equivalent, with the Partner
+// WSDL so that no org specific types are needed
+
+const (
+	soapSObjectNamespace   = "urn:sobject.partner.soap.sforce.com"
+	soapEmptyRecycleBinMax = 200
+)
+
+// SoapFault is returned when a SOAP API call fails as a whole, such as for an invalid session
+type SoapFault struct {
+	Code    string // exception code without its namespace, such as INVALID_SESSION_ID
+	Message string
+}
+
+func (f *SoapFault) Error() string {
+	if f.Code == "" || strings.HasPrefix(f.Message, f.Code) {
+		return f.Message
+	}
+	return f.Code + ": " + f.Message
+}
+
+type soapRequestEnvelope struct {
+	XMLName xml.Name           `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	Header  *soapRequestHeader `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header,omitempty"`
+	Body    struct {
+		Content any
+	} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+}
+
+type soapRequestHeader struct {
+	SessionHeader struct {
+		SessionId string `xml:"sessionId"`
+	} `xml:"urn:partner.soap.sforce.com SessionHeader"`
+}
+
+type soapResponseEnvelope struct {
+	Body struct {
+		Fault *struct {
+			Code   string `xml:"faultcode"`
+			String string `xml:"faultstring"`
+		} `xml:"Fault"`
+		Content []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// soapError is an error of a single record in a SOAP API result
+type soapError struct {
+	Fields     []string `xml:"fields"`
+	Message    string   `xml:"message"`
+	StatusCode string   `xml:"statusCode"`
+}
+
+// soapSObject is a record of the Partner WSDL, whose fields are elements named after the field
+type soapSObject struct {
+	Type         string      `xml:"urn:sobject.partner.soap.sforce.com type"`
+	FieldsToNull []string    `xml:"urn:sobject.partner.soap.sforce.com fieldsToNull,omitempty"`
+	Id           string      `xml:"urn:sobject.partner.soap.sforce.com Id,omitempty"`
+	Fields       []soapField `xml:",any"`
+}
+
+type soapField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type soapLogin struct {
+	XMLName  xml.Name `xml:"urn:partner.soap.sforce.com login"`
+	Username string   `xml:"username"`
+	Password string   `xml:"password"`
+}
+
+type soapLoginResponse struct {
+	Result struct {
+		ServerUrl string `xml:"serverUrl"`
+		SessionId string `xml:"sessionId"`
+		UserId    string `xml:"userId"`
+		UserInfo  struct {
+			OrganizationId string `xml:"organizationId"`
+		} `xml:"userInfo"`
+	} `xml:"result"`
+}
+
+type soapSetPassword struct {
+	XMLName  xml.Name `xml:"urn:partner.soap.sforce.com setPassword"`
+	UserId   string   `xml:"userId"`
+	Password string   `xml:"password"`
+}
+
+type soapEmptyRecycleBin struct {
+	XMLName xml.Name `xml:"urn:partner.soap.sforce.com emptyRecycleBin"`
+	Ids     []string `xml:"ids"`
+}
+
+type soapEmptyRecycleBinResponse struct {
+	Results []struct {
+		Errors  []soapError `xml:"errors"`
+		Id      string      `xml:"id"`
+		Success bool        `xml:"success"`
+	} `xml:"result"`
+}
+
+type soapMergeRequest struct {
+	XMLName xml.Name `xml:"urn:partner.soap.sforce.com merge"`
+	Request struct {
+		MasterRecord     soapSObject `xml:"masterRecord"`
+		RecordToMergeIds []string    `xml:"recordToMergeIds"`
+	} `xml:"request"`
+}
+
+type soapMergeResult struct {
+	Errors            []soapError `xml:"errors"`
+	Id                string      `xml:"id"`
+	MergedRecordIds   []string    `xml:"mergedRecordIds"`
+	Success           bool        `xml:"success"`
+	UpdatedRelatedIds []string    `xml:"updatedRelatedIds"`
+}
+
+type soapMergeResponse struct {
+	Results []soapMergeResult `xml:"result"`
+}
+
+// soapLeadConvert is a LeadConvert of the convertLead call, with its elements in WSDL order
+type soapLeadConvert struct {
+	AccountId              string `xml:"accountId,omitempty"`
+	ContactId              string `xml:"contactId,omitempty"`
+	ConvertedStatus        string `xml:"convertedStatus"`
+	DoNotCreateOpportunity bool   `xml:"doNotCreateOpportunity"`
+	LeadId                 string `xml:"leadId"`
+	OpportunityName        string `xml:"opportunityName,omitempty"`
+	OverwriteLeadSource    bool   `xml:"overwriteLeadSource"`
+	OwnerId                string `xml:"ownerId,omitempty"`
+	SendNotificationEmail  bool   `xml:"sendNotificationEmail"`
+}
+
+type soapConvertLeadRequest struct {
+	XMLName      xml.Name          `xml:"urn:partner.soap.sforce.com convertLead"`
+	LeadConverts []soapLeadConvert `xml:"leadConverts"`
+}
+
+type soapLeadConvertResult struct {
+	AccountId     string      `xml:"accountId"`
+	ContactId     string      `xml:"contactId"`
+	Errors        []soapError `xml:"errors"`
+	LeadId        string      `xml:"leadId"`
+	OpportunityId string      `xml:"opportunityId"`
+	Success       bool        `xml:"success"`
+}
+
+type soapConvertLeadResponse struct {
+	Results []soapLeadConvertResult `xml:"result"`
+}
+
+type soapFindDuplicatesRequest struct {
+	XMLName  xml.Name      `xml:"urn:partner.soap.sforce.com findDuplicates"`
+	SObjects []soapSObject `xml:"sObjects"`
+}
+
+type soapFindDuplicatesByIdsRequest struct {
+	XMLName xml.Name `xml:"urn:partner.soap.sforce.com findDuplicatesByIds"`
+	Ids     []string `xml:"ids"`
+}
+
+type soapMatchRecord struct {
+	MatchConfidence float64     `xml:"matchConfidence"`
+	Record          soapSObject `xml:"record"`
+}
+
+type soapMatchResult struct {
+	EntityType   string            `xml:"entityType"`
+	Errors       []soapError       `xml:"errors"`
+	MatchEngine  string            `xml:"matchEngine"`
+	MatchRecords []soapMatchRecord `xml:"matchRecords"`
+	Rule         string            `xml:"rule"`
+	Success      bool              `xml:"success"`
+}
+
+type soapDuplicateResult struct {
+	AllowSave     bool              `xml:"allowSave"`
+	DuplicateRule string            `xml:"duplicateRule"`
+	MatchResults  []soapMatchResult `xml:"matchResults"`
+}
+
+type soapFindDuplicatesResult struct {
+	DuplicateResults []soapDuplicateResult `xml:"duplicateResults"`
+	Errors           []soapError           `xml:"errors"`
+	Success          bool                  `xml:"success"`
+}
+
+type soapFindDuplicatesResponse struct {
+	Results []soapFindDuplicatesResult `xml:"result"`
+}
+
+func (e soapError) salesforceError() SalesforceErrorMessage {
+	return SalesforceErrorMessage{
+		Message:    e.Message,
+		StatusCode: e.StatusCode,
+		ErrorCode:  e.StatusCode,
+		Fields:     e.Fields,
+	}
+}
+
+func soapErrors(errs []soapError) []SalesforceErrorMessage {
+	messages := make([]SalesforceErrorMessage, len(errs))
+	for i, err := range errs {
+		messages[i] = err.salesforceError()
+	}
+	return messages
+}
+
+// soapEndpoint returns the Partner SOAP API endpoint of a domain for an api version such as v63.0
+func soapEndpoint(domain string, version string) string {
+	return domain + soapPath(version)
+}
+
+// newSoapSObject returns a Partner record, sending nil field values as fields to null. Fields are
+// sorted so that requests are deterministic.
+func newSoapSObject(sObjectName string, id string, fields map[string]any) soapSObject {
+	record := soapSObject{Type: sObjectName, Id: id}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if fields[name] == nil {
+			record.FieldsToNull = append(record.FieldsToNull, name)
+			continue
+		}
+		record.Fields = append(record.Fields, soapField{
+			XMLName: xml.Name{Space: soapSObjectNamespace, Local: name},
+			Value:   fmt.Sprint(fields[name]),
+		})
+	}
+	return record
+}
+
+// soapPath returns the path of the Partner SOAP API endpoint for an api version such as v63.0
+func soapPath(version string) string {
+	return "/services/Soap/u/" + strings.TrimPrefix(version, "v")
+}
+
+// soapEnvelope returns the envelope of a SOAP call, with a session header unless sessionId is empty
+func soapEnvelope(sessionId string, call any) ([]byte, error) {
+	envelope := soapRequestEnvelope{}
+	if sessionId != "" {
+		envelope.Header = &soapRequestHeader{}
+		envelope.Header.SessionHeader.SessionId = sessionId
+	}
+	envelope.Body.Content = call
+	return xml.Marshal(envelope)
+}
+
+// decodeSoapResponse decodes the result of a SOAP response body into response. Faults are returned as
+// a SoapFault, and other responses that are not OK as an error with their status.
+func decodeSoapResponse(resp *http.Response, body []byte, response any) error {
+	parsed := soapResponseEnvelope{}
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return errors.New(resp.Status + ": " + string(body))
+		}
+		return err
+	}
+	if fault := parsed.Body.Fault; fault != nil {
+		code := fault.Code
+		if _, after, found := strings.Cut(code, ":"); found {
+			code = after
+		}
+		return &SoapFault{Code: code, Message: fault.String}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status + ": " + string(body))
+	}
+	return xml.Unmarshal(parsed.Body.Content, response)
+}
+
+// postSoap sends a SOAP call without a session, such as login, and decodes its result into response
+func postSoap(client *http.Client, endpoint string, call any, response any) error {
+	body, err := soapEnvelope("", call)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "go-salesforce")
+	req.Header.Set("Content-Type", xmlType)
+	req.Header.Set("SOAPAction", `""`)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return decodeSoapResponse(resp, respBody, response)
+}
+
+// soapCall sends a SOAP call with the session of sf through the same pipeline as REST requests, and
+// decodes its result into response. The session is in the envelope rather than a header, so an expired
+// session is refreshed here and the envelope is built again with the new session.
+func soapCall(sf *Salesforce, call any, response any) error {
+	send := func() error {
+		body, err := soapEnvelope(sf.auth.AccessToken, call)
+		if err != nil {
+			return err
+		}
+		resp, err := doRequest(sf.auth, sf.config, requestPayload{
+			method:      http.MethodPost,
+			uri:         soapPath(sf.config.apiVersion),
+			content:     xmlType,
+			body:        bytes.NewReader(body),
+			compress:    sf.config.compressionHeaders,
+			instanceUri: true,
+			soap:        true,
+			options:     []RequestOption{WithHeader("SOAPAction", `""`)},
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return decodeSoapResponse(resp, respBody, response)
+	}
+	err := send()
+	var fault *SoapFault
+	if errors.As(err, &fault) && fault.Code == invalidSessionIdError {
+		if refreshErr := refreshSession(sf.auth, sf.config); refreshErr != nil {
+			return err
+		}
+		return send()
+	}
+	return err
+}
+
+func soapLoginFlow(
+	config *configuration,
+	domain string,
+	username string,
+	password string,
+	securityToken string,
+) (*authentication, error) {
+	resp := soapLoginResponse{}
+	err := postSoap(
+		config.httpClient,
+		soapEndpoint(domain, config.apiVersion),
+		soapLogin{Username: username, Password: password + securityToken},
+		&resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	serverUrl, err := url.Parse(resp.Result.ServerUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &authentication{
+		AccessToken: resp.Result.SessionId,
+		InstanceUrl: serverUrl.Scheme + "://" + serverUrl.Host,
+		Id:          domain + "/id/" + resp.Result.UserInfo.OrganizationId + "/" + resp.Result.UserId,
+		TokenType:   "Bearer",
+		grantType:   grantTypeSoapLogin,
+	}, nil
+}
+
+// SetPassword sets the password of a user with the SOAP API, which has no REST equivalent
+func (sf *Salesforce) SetPassword(userId string, password string) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if userId == "" || password == "" {
+		return errors.New("user id and password are required")
+	}
+	return soapCall(sf, soapSetPassword{UserId: userId, Password: password}, &struct{}{})
+}
+
+// EmptyRecycleBin permanently deletes up to 200 records from the recycle bin with the SOAP API. Records
+// that cannot be deleted are reported in the results rather than as an error.
+func (sf *Salesforce) EmptyRecycleBin(recordIds []string) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if len(recordIds) == 0 || len(recordIds) > soapEmptyRecycleBinMax {
+		return SalesforceResults{}, fmt.Errorf(
+			"between 1 and %d record ids are required",
+			soapEmptyRecycleBinMax,
+		)
+	}
+
+	resp := soapEmptyRecycleBinResponse{}
+	if err := soapCall(sf, soapEmptyRecycleBin{Ids: recordIds}, &resp); err != nil {
+		return SalesforceResults{}, err
+	}
+	results := SalesforceResults{}
+	for i, result := range resp.Results {
+		results.Results = append(results.Results, SalesforceResult{
+			Id:      result.Id,
+			Errors:  soapErrors(result.Errors),
+			Success: result.Success,
+			Index:   i,
+		})
+		results.HasSalesforceErrors = results.HasSalesforceErrors || !result.Success
+	}
+	return results, nil
+}
+
+// soapMerge merges up to two records into a master record, updating the master with fields
+func soapMerge(
+	sf *Salesforce,
+	sObjectName string,
+	masterId string,
+	fields map[string]any,
+	mergedIds []string,
+) (soapMergeResult, error) {
+	call := soapMergeRequest{}
+	call.Request.MasterRecord = newSoapSObject(sObjectName, masterId, fields)
+	call.Request.RecordToMergeIds = mergedIds
+	resp := soapMergeResponse{}
+	if err := soapCall(sf, call, &resp); err != nil {
+		return soapMergeResult{}, err
+	}
+	if len(resp.Results) != 1 {
+		return soapMergeResult{}, fmt.Errorf("merge returned %d results, want 1", len(resp.Results))
+	}
+	return resp.Results[0], nil
+}
+
+// soapConvertLead converts leads, returning a result for each in order
+func soapConvertLead(
+	sf *Salesforce,
+	leadConverts []soapLeadConvert,
+) ([]soapLeadConvertResult, error) {
+	resp := soapConvertLeadResponse{}
+	if err := soapCall(sf, soapConvertLeadRequest{LeadConverts: leadConverts}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(leadConverts) {
+		return nil, fmt.Errorf(
+			"convertLead returned %d results, want %d",
+			len(resp.Results),
+			len(leadConverts),
+		)
+	}
+	return resp.Results, nil
+}
+
+// soapFindDuplicates runs the active duplicate rules against records, or against the records of ids
+// if call is a soapFindDuplicatesByIdsRequest, returning a result for each in order
+func soapFindDuplicates(sf *Salesforce, call any, count int) ([]soapFindDuplicatesResult, error) {
+	resp := soapFindDuplicatesResponse{}
+	if err := soapCall(sf, call, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != count {
+		return nil, fmt.Errorf(
+			"findDuplicates returned %d results, want %d",
+			len(resp.Results),
+			count,
+		)
+	}
+	return resp.Results, nil
+}
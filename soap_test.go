@@ -0,0 +1,354 @@
+package salesforce
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupSoapTestServer returns a server that responds to SOAP calls with the given body content, and
+// the request bodies it received
+func setupSoapTestServer(t *testing.T, status int, content string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		if r.URL.Path != "/services/Soap/u/"+strings.TrimPrefix(apiVersion, "v") ||
+			r.Header.Get("SOAPAction") == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", xmlType)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+			`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" ` +
+			`xmlns="urn:partner.soap.sforce.com"><soapenv:Body>` + content +
+			`</soapenv:Body></soapenv:Envelope>`))
+	}))
+	return server, &requests
+}
+
+const invalidSessionFault = `<soapenv:Fault><faultcode>sf:INVALID_SESSION_ID</faultcode>` +
+	`<faultstring>INVALID_SESSION_ID: Invalid Session ID found in SessionHeader</faultstring>` +
+	`</soapenv:Fault>`
+
+func Test_soapLoginFlow(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<loginResponse><result>`+
+		`<serverUrl>https://myorg.my.salesforce.com/services/Soap/u/63.0/00D000000000001</serverUrl>`+
+		`<sessionId>00D!session</sessionId><userId>005000000000001AAA</userId>`+
+		`<userInfo><organizationId>00D000000000001AAA</organizationId></userInfo>`+
+		`</result></loginResponse>`)
+	defer server.Close()
+
+	auth, err := soapLoginFlow(getDefaultConfig(t), server.URL, "user@example.com", "pass", "token")
+	if err != nil {
+		t.Fatalf("soapLoginFlow() error = %v", err)
+	}
+	want := &authentication{
+		AccessToken: "00D!session",
+		InstanceUrl: "https://myorg.my.salesforce.com",
+		Id:          server.URL + "/id/00D000000000001AAA/005000000000001AAA",
+		TokenType:   "Bearer",
+		grantType:   grantTypeSoapLogin,
+	}
+	if !reflect.DeepEqual(auth, want) {
+		t.Errorf("soapLoginFlow() = %+v, want %+v", auth, want)
+	}
+	if !strings.Contains((*requests)[0], "<username>user@example.com</username>") ||
+		!strings.Contains((*requests)[0], "<password>passtoken</password>") ||
+		strings.Contains((*requests)[0], "SessionHeader") {
+		t.Errorf("request = %s", (*requests)[0])
+	}
+
+	faultServer, _ := setupSoapTestServer(t, http.StatusInternalServerError,
+		`<soapenv:Fault><faultcode>INVALID_LOGIN</faultcode>`+
+			`<faultstring>INVALID_LOGIN: Invalid username, password, security token; or user locked out.</faultstring>`+
+			`</soapenv:Fault>`)
+	defer faultServer.Close()
+	_, err = soapLoginFlow(getDefaultConfig(t), faultServer.URL, "user@example.com", "wrong", "")
+	var fault *SoapFault
+	if !errors.As(err, &fault) || fault.Code != "INVALID_LOGIN" {
+		t.Errorf("soapLoginFlow() error = %v, want an INVALID_LOGIN SoapFault", err)
+	}
+
+	var path string
+	versionServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer versionServer.Close()
+	config := getDefaultConfig(t)
+	if err := WithAPIVersion("v60.0")(config); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = soapLoginFlow(config, versionServer.URL, "user@example.com", "pass", "")
+	if path != "/services/Soap/u/60.0" {
+		t.Errorf("soapLoginFlow() sent to %s, want the api version of the client", path)
+	}
+}
+
+func TestInit_soapLogin(t *testing.T) {
+	server, _ := setupSoapTestServer(t, http.StatusOK, `<loginResponse><result>`+
+		`<serverUrl>https://myorg.my.salesforce.com/services/Soap/u/63.0/00D000000000001</serverUrl>`+
+		`<sessionId>00D!session</sessionId></result></loginResponse>`)
+	defer server.Close()
+	creds := Creds{Domain: server.URL, Username: "u", Password: "p"}
+
+	sf, err := Init(creds, WithSoapLogin(true))
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if sf.GetAuthFlow() != AuthFlowSoapLogin || sf.GetAccessToken() != "00D!session" ||
+		sf.auth.creds != creds {
+		t.Errorf("Init() = %+v with auth %+v", sf, sf.auth)
+	}
+
+	if _, err := Init(creds); err == nil {
+		t.Errorf("Init() without WithSoapLogin succeeded, want an error")
+	}
+}
+
+func TestSoapFault_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault SoapFault
+		want  string
+	}{
+		{
+			name:  "message_with_code",
+			fault: SoapFault{Code: "INVALID_ID_FIELD", Message: "INVALID_ID_FIELD: bad id"},
+			want:  "INVALID_ID_FIELD: bad id",
+		},
+		{
+			name:  "message_without_code",
+			fault: SoapFault{Code: "UNKNOWN_EXCEPTION", Message: "something went wrong"},
+			want:  "UNKNOWN_EXCEPTION: something went wrong",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fault.Error(); got != tt.want {
+				t.Errorf("Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_SetPassword(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<setPasswordResponse/>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	if err := sf.SetPassword("005000000000001AAA", "n3w-Passw0rd"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	for _, want := range []string{
+		"<sessionId>1234</sessionId>",
+		`<setPassword xmlns="urn:partner.soap.sforce.com"><userId>005000000000001AAA</userId><password>n3w-Passw0rd</password></setPassword>`,
+	} {
+		if !strings.Contains((*requests)[0], want) {
+			t.Errorf("request = %s, want it to contain %s", (*requests)[0], want)
+		}
+	}
+	if err := sf.SetPassword("", "n3w-Passw0rd"); err == nil {
+		t.Error("SetPassword() expected an error without a user id")
+	}
+
+	faultServer, faultRequests := setupSoapTestServer(
+		t,
+		http.StatusInternalServerError,
+		invalidSessionFault,
+	)
+	defer faultServer.Close()
+	sf = buildSalesforceStruct(&authentication{InstanceUrl: faultServer.URL, AccessToken: "1234"})
+	err := sf.SetPassword("005000000000001AAA", "n3w-Passw0rd")
+	var fault *SoapFault
+	if !errors.As(err, &fault) || fault.Code != invalidSessionIdError {
+		t.Errorf("SetPassword() error = %v, want an invalid session SoapFault", err)
+	}
+	if len(*faultRequests) != 1 {
+		t.Errorf(
+			"sent %d requests, want 1 when the session cannot be refreshed",
+			len(*faultRequests),
+		)
+	}
+}
+
+func Test_soapCall_pipeline(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`<soapenv:Envelope ` +
+			`xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+			`<soapenv:Fault><faultcode>sf:INVALID_ID_FIELD</faultcode>` +
+			`<faultstring>INVALID_ID_FIELD: bad id</faultstring></soapenv:Fault>` +
+			`</soapenv:Body></soapenv:Envelope>`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	if err := WithMaintenanceBackoff(MaintenanceBackoff{
+		InitialWait: time.Millisecond,
+		MaxWait:     time.Millisecond,
+		MaxDuration: time.Second,
+	})(sf.config); err != nil {
+		t.Fatal(err)
+	}
+	if err := WithCircuitBreaker(CircuitBreakerSettings{
+		FailureRate: 0.6,
+		MinRequests: 2,
+	})(sf.config); err != nil {
+		t.Fatal(err)
+	}
+
+	// the maintenance response is retried, and the fault is returned without opening the circuit
+	err := sf.SetPassword("005000000000001AAA", "n3w-Passw0rd")
+	var fault *SoapFault
+	if !errors.As(err, &fault) || fault.Code != "INVALID_ID_FIELD" {
+		t.Errorf("SetPassword() error = %v, want an INVALID_ID_FIELD SoapFault", err)
+	}
+	if calls != 2 {
+		t.Errorf("sent %d requests, want 2", calls)
+	}
+	if state := sf.config.circuitBreaker.currentState(); state != CircuitClosed {
+		t.Errorf("circuit state = %v, want %v", state, CircuitClosed)
+	}
+}
+
+func TestSalesforce_EmptyRecycleBin(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<emptyRecycleBinResponse>`+
+		`<result><id>001000000000001AAA</id><success>true</success></result>`+
+		`<result><errors><message>entity is not in the recycle bin</message>`+
+		`<statusCode>INVALID_ID_FIELD</statusCode></errors>`+
+		`<id>001000000000002AAA</id><success>false</success></result>`+
+		`</emptyRecycleBinResponse>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := sf.EmptyRecycleBin([]string{"001000000000001AAA", "001000000000002AAA"})
+	if err != nil {
+		t.Fatalf("EmptyRecycleBin() error = %v", err)
+	}
+	want := SalesforceResults{
+		Results: []SalesforceResult{
+			{Id: "001000000000001AAA", Errors: []SalesforceErrorMessage{}, Success: true},
+			{
+				Id: "001000000000002AAA",
+				Errors: []SalesforceErrorMessage{{
+					Message:    "entity is not in the recycle bin",
+					StatusCode: "INVALID_ID_FIELD",
+					ErrorCode:  "INVALID_ID_FIELD",
+				}},
+				Index: 1,
+			},
+		},
+		HasSalesforceErrors: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EmptyRecycleBin() = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(
+		(*requests)[0],
+		"<ids>001000000000001AAA</ids><ids>001000000000002AAA</ids>",
+	) {
+		t.Errorf("request = %s", (*requests)[0])
+	}
+
+	if _, err := sf.EmptyRecycleBin(nil); err == nil {
+		t.Error("EmptyRecycleBin() expected an error without ids")
+	}
+	if _, err := sf.EmptyRecycleBin(make([]string, soapEmptyRecycleBinMax+1)); err == nil {
+		t.Error("EmptyRecycleBin() expected an error for too many ids")
+	}
+}
+
+func Test_soapMerge(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<mergeResponse><result>`+
+		`<id>001000000000001AAA</id><mergedRecordIds>001000000000002AAA</mergedRecordIds>`+
+		`<success>true</success><updatedRelatedIds>003000000000001AAA</updatedRelatedIds>`+
+		`</result></mergeResponse>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := soapMerge(
+		sf,
+		"Account",
+		"001000000000001AAA",
+		map[string]any{"Website": nil, "Name": "Acme"},
+		[]string{"001000000000002AAA"},
+	)
+	if err != nil {
+		t.Fatalf("soapMerge() error = %v", err)
+	}
+	want := soapMergeResult{
+		Id:                "001000000000001AAA",
+		MergedRecordIds:   []string{"001000000000002AAA"},
+		Success:           true,
+		UpdatedRelatedIds: []string{"003000000000001AAA"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("soapMerge() = %+v, want %+v", got, want)
+	}
+	wantRequest := `<request><masterRecord>` +
+		`<type xmlns="urn:sobject.partner.soap.sforce.com">Account</type>` +
+		`<fieldsToNull xmlns="urn:sobject.partner.soap.sforce.com">Website</fieldsToNull>` +
+		`<Id xmlns="urn:sobject.partner.soap.sforce.com">001000000000001AAA</Id>` +
+		`<Name xmlns="urn:sobject.partner.soap.sforce.com">Acme</Name>` +
+		`</masterRecord><recordToMergeIds>001000000000002AAA</recordToMergeIds></request>`
+	if !strings.Contains((*requests)[0], wantRequest) {
+		t.Errorf("request = %s, want it to contain %s", (*requests)[0], wantRequest)
+	}
+}
+
+func Test_soapConvertLead(t *testing.T) {
+	server, requests := setupSoapTestServer(t, http.StatusOK, `<convertLeadResponse><result>`+
+		`<accountId>001000000000001AAA</accountId><contactId>003000000000001AAA</contactId>`+
+		`<leadId>00Q000000000001AAA</leadId><opportunityId xsi:nil="true" `+
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"/><success>true</success>`+
+		`</result></convertLeadResponse>`)
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	got, err := soapConvertLead(sf, []soapLeadConvert{{
+		ConvertedStatus:        "Closed - Converted",
+		DoNotCreateOpportunity: true,
+		LeadId:                 "00Q000000000001AAA",
+	}})
+	if err != nil {
+		t.Fatalf("soapConvertLead() error = %v", err)
+	}
+	want := []soapLeadConvertResult{{
+		AccountId: "001000000000001AAA",
+		ContactId: "003000000000001AAA",
+		LeadId:    "00Q000000000001AAA",
+		Success:   true,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("soapConvertLead() = %+v, want %+v", got, want)
+	}
+	wantRequest := `<leadConverts><convertedStatus>Closed - Converted</convertedStatus>` +
+		`<doNotCreateOpportunity>true</doNotCreateOpportunity><leadId>00Q000000000001AAA</leadId>` +
+		`<overwriteLeadSource>false</overwriteLeadSource>` +
+		`<sendNotificationEmail>false</sendNotificationEmail></leadConverts>`
+	if !strings.Contains((*requests)[0], wantRequest) {
+		t.Errorf("request = %s, want it to contain %s", (*requests)[0], wantRequest)
+	}
+
+	if _, err := soapConvertLead(sf, make([]soapLeadConvert, 2)); err == nil {
+		t.Error("soapConvertLead() expected an error when results are missing")
+	}
+}
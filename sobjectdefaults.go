@@ -0,0 +1,99 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SObjectDefaults are options of DML operations on an sObject, registered with WithSObjectDefaults, that
+// apply to every call on it so that call sites don't have to repeat them
+type SObjectDefaults struct {
+	BatchSize              int    // batch size of collection, composite, and bulk operations called with a batch size of 0
+	AssignmentRuleId       string // assignment rule run on inserts, updates, and upserts of Lead or Case records
+	DisableAssignmentRules bool   // don't run the default assignment rule on inserts, updates, and upserts
+	AllowDuplicates        bool   // save records that duplicate rules would block or alert on
+}
+
+func (d SObjectDefaults) validate(sObjectName string) error {
+	if sObjectName == "" {
+		return errors.New("sObject name cannot be empty")
+	}
+	if d.BatchSize < 0 {
+		return fmt.Errorf("batch size of %s defaults cannot be negative", sObjectName)
+	}
+	if d.AssignmentRuleId != "" {
+		if d.DisableAssignmentRules {
+			return fmt.Errorf(
+				"%s defaults cannot both set an assignment rule and disable assignment rules",
+				sObjectName,
+			)
+		}
+		if !strings.EqualFold(sObjectName, "Lead") && !strings.EqualFold(sObjectName, "Case") {
+			return fmt.Errorf("assignment rules are not supported for sObject: %s", sObjectName)
+		}
+	}
+	return nil
+}
+
+// sObjectDefaultsOf returns the defaults registered for the sObject, matching its name case-insensitively
+func sObjectDefaultsOf(config *configuration, sObjectName string) (SObjectDefaults, bool) {
+	defaults, ok := config.sObjectDefaults[strings.ToLower(sObjectName)]
+	return defaults, ok
+}
+
+// defaultBatchSize returns the batch size registered for the sObject of the records when batchSize is 0.
+// An sObject name that cannot be inferred leaves the batch size to be rejected by validation.
+func defaultBatchSize(sf *Salesforce, sObjectName string, records any, batchSize int) int {
+	if batchSize != 0 || len(sf.config.sObjectDefaults) == 0 {
+		return batchSize
+	}
+	sObjectName, err := inferSObjectName(sf, sObjectName, records)
+	if err != nil {
+		return batchSize
+	}
+	if defaults, ok := sObjectDefaultsOf(sf.config, sObjectName); ok && defaults.BatchSize > 0 {
+		return defaults.BatchSize
+	}
+	return batchSize
+}
+
+// defaultBulkOptions returns the batch size and assignment rule of a bulk job on the sObject, replacing
+// a batch size of 0 and an empty assignment rule id with the registered defaults
+func defaultBulkOptions(
+	config *configuration,
+	sObjectName string,
+	batchSize int,
+	assignmentRuleId string,
+) (int, string) {
+	defaults, ok := sObjectDefaultsOf(config, sObjectName)
+	if !ok {
+		return batchSize, assignmentRuleId
+	}
+	if batchSize == 0 && defaults.BatchSize > 0 {
+		batchSize = defaults.BatchSize
+	}
+	if assignmentRuleId == "" {
+		assignmentRuleId = defaults.AssignmentRuleId
+	}
+	return batchSize, assignmentRuleId
+}
+
+// sObjectDefaultOptions returns the headers of inserts, updates, and upserts of the sObject that apply its
+// registered assignment and duplicate rule defaults
+func sObjectDefaultOptions(config *configuration, sObjectName string) []RequestOption {
+	defaults, ok := sObjectDefaultsOf(config, sObjectName)
+	if !ok {
+		return nil
+	}
+	options := []RequestOption{}
+	if defaults.AssignmentRuleId != "" {
+		options = append(options, WithHeader("Sforce-Auto-Assign", defaults.AssignmentRuleId))
+	} else if defaults.DisableAssignmentRules {
+		options = append(options, WithHeader("Sforce-Auto-Assign", "FALSE"))
+	}
+	if defaults.AllowDuplicates {
+		options = append(options, WithHeader("Sforce-Duplicate-Rule-Header", "allowSave=true"))
+	}
+	return options
+}
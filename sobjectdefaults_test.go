@@ -0,0 +1,216 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSObjectDefaults_headers(t *testing.T) {
+	type args struct {
+		sObjectName string
+		defaults    SObjectDefaults
+	}
+	tests := []struct {
+		name           string
+		args           args
+		call           func(sf *Salesforce) error
+		wantAutoAssign string
+		wantDuplicate  string
+	}{
+		{
+			name: "insert_one_without_assignment_rules",
+			args: args{
+				sObjectName: "Lead",
+				defaults:    SObjectDefaults{DisableAssignmentRules: true},
+			},
+			call: func(sf *Salesforce) error {
+				_, err := sf.InsertOne("Lead", map[string]any{"LastName": "Doe"})
+				return err
+			},
+			wantAutoAssign: "FALSE",
+		},
+		{
+			name: "update_one_with_assignment_rule",
+			args: args{
+				sObjectName: "case",
+				defaults:    SObjectDefaults{AssignmentRuleId: "01Q000000000001"},
+			},
+			call: func(sf *Salesforce) error {
+				return sf.UpdateOne("Case", map[string]any{"Id": "500000000000001"})
+			},
+			wantAutoAssign: "01Q000000000001",
+		},
+		{
+			name: "insert_collection_allowing_duplicates",
+			args: args{sObjectName: "Account", defaults: SObjectDefaults{AllowDuplicates: true}},
+			call: func(sf *Salesforce) error {
+				_, err := sf.InsertCollection(
+					"Account",
+					[]map[string]any{{"Name": "Acme"}},
+					200,
+				)
+				return err
+			},
+			wantDuplicate: "allowSave=true",
+		},
+		{
+			name: "insert_composite_allowing_duplicates",
+			args: args{sObjectName: "Account", defaults: SObjectDefaults{AllowDuplicates: true}},
+			call: func(sf *Salesforce) error {
+				_, err := sf.InsertComposite(
+					"Account",
+					[]map[string]any{{"Name": "Acme"}},
+					200,
+					false,
+				)
+				return err
+			},
+			wantDuplicate: "allowSave=true",
+		},
+		{
+			name: "other_sObject",
+			args: args{
+				sObjectName: "Lead",
+				defaults:    SObjectDefaults{DisableAssignmentRules: true},
+			},
+			call: func(sf *Salesforce) error {
+				_, err := sf.InsertOne("Contact", map[string]any{"LastName": "Doe"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var autoAssign, duplicate string
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					autoAssign = r.Header.Get("Sforce-Auto-Assign")
+					duplicate = r.Header.Get("Sforce-Duplicate-Rule-Header")
+					switch {
+					case strings.HasSuffix(r.URL.Path, "/composite"):
+						_, _ = w.Write([]byte(`{"compositeResponse": [{"body": [` +
+							`{"id": "001000000000001", "success": true, "errors": []}` +
+							`], "httpStatusCode": 200, "referenceId": "refObj0"}]}`))
+					case strings.Contains(r.URL.Path, "/composite/sobjects"):
+						_, _ = w.Write(
+							[]byte(`[{"id": "001000000000001", "success": true, "errors": []}]`),
+						)
+					case r.Method == http.MethodPatch:
+						w.WriteHeader(http.StatusNoContent)
+					default:
+						_, _ = w.Write(
+							[]byte(`{"id": "00Q000000000001", "success": true, "errors": []}`),
+						)
+					}
+				}),
+			)
+			defer server.Close()
+
+			sf := buildSalesforceStruct(&authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "accesstokenvalue",
+			})
+			if err := WithSObjectDefaults(tt.args.sObjectName, tt.args.defaults)(sf.config); err != nil {
+				t.Fatalf("WithSObjectDefaults() error = %v", err)
+			}
+			if err := tt.call(sf); err != nil {
+				t.Fatalf("call error = %v", err)
+			}
+			if autoAssign != tt.wantAutoAssign {
+				t.Errorf("Sforce-Auto-Assign = %q, want %q", autoAssign, tt.wantAutoAssign)
+			}
+			if duplicate != tt.wantDuplicate {
+				t.Errorf("Sforce-Duplicate-Rule-Header = %q, want %q", duplicate, tt.wantDuplicate)
+			}
+		})
+	}
+}
+
+func TestSObjectDefaults_batchSize(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`[{"id": "00T000000000001", "success": true, "errors": []}]`))
+	}))
+	defer server.Close()
+
+	sf := buildSalesforceStruct(&authentication{
+		InstanceUrl: server.URL,
+		AccessToken: "accesstokenvalue",
+	})
+	if err := WithSObjectDefaults("Task", SObjectDefaults{BatchSize: 1})(sf.config); err != nil {
+		t.Fatalf("WithSObjectDefaults() error = %v", err)
+	}
+	records := []map[string]any{{"Subject": "Call"}, {"Subject": "Email"}}
+	if _, err := sf.InsertCollection("Task", records, 0); err != nil {
+		t.Fatalf("InsertCollection() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 batches of the default size", requests)
+	}
+
+	// sObjects without a default batch size still require one
+	if _, err := sf.InsertCollection("Event", records, 0); err == nil {
+		t.Error("InsertCollection() without a batch size error = nil, want an error")
+	}
+}
+
+func Test_defaultBulkOptions(t *testing.T) {
+	config := &configuration{}
+	config.setDefaults()
+	_ = WithSObjectDefaults(
+		"Lead",
+		SObjectDefaults{BatchSize: 5000, AssignmentRuleId: "01Q000000000001"},
+	)(config)
+
+	tests := []struct {
+		name             string
+		sObjectName      string
+		batchSize        int
+		assignmentRuleId string
+		wantBatchSize    int
+		wantRuleId       string
+	}{
+		{
+			name:          "defaults",
+			sObjectName:   "Lead",
+			wantBatchSize: 5000,
+			wantRuleId:    "01Q000000000001",
+		},
+		{
+			name:             "explicit_values",
+			sObjectName:      "Lead",
+			batchSize:        100,
+			assignmentRuleId: "01Q000000000002",
+			wantBatchSize:    100,
+			wantRuleId:       "01Q000000000002",
+		},
+		{
+			name:          "no_defaults",
+			sObjectName:   "Account",
+			batchSize:     100,
+			wantBatchSize: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize, ruleId := defaultBulkOptions(
+				config,
+				tt.sObjectName,
+				tt.batchSize,
+				tt.assignmentRuleId,
+			)
+			if batchSize != tt.wantBatchSize || ruleId != tt.wantRuleId {
+				t.Errorf(
+					"defaultBulkOptions() = %v, %v, want %v, %v",
+					batchSize,
+					ruleId,
+					tt.wantBatchSize,
+					tt.wantRuleId,
+				)
+			}
+		})
+	}
+}
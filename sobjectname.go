@@ -0,0 +1,73 @@
+package salesforce
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// sObjectNameTagOption is the salesforce tag option that names the sObject of a struct
+const sObjectNameTagOption = "object="
+
+// inferSObjectName returns the sObject name of a DML operation. An empty name is inferred from the
+// records: from a struct field tagged with the object option, such as
+// _ struct{} `salesforce:"object=Account"`, or else from the struct type name if enabled.
+func inferSObjectName(sf *Salesforce, sObjectName string, records any) (string, error) {
+	if sObjectName != "" {
+		return sObjectName, nil
+	}
+	t := recordStructType(reflect.ValueOf(records))
+	if t == nil {
+		return "", errors.New("sObject name is required for records that are not structs")
+	}
+	for i := 0; i < t.NumField(); i++ {
+		for _, option := range strings.Split(t.Field(i).Tag.Get("salesforce"), ",") {
+			if name, ok := strings.CutPrefix(option, sObjectNameTagOption); ok && name != "" {
+				return name, nil
+			}
+		}
+	}
+	if !sf.config.sObjectNameInference || t.Name() == "" {
+		return "", fmt.Errorf(
+			"sObject name is required, or tag a field of %s with %s",
+			t,
+			`salesforce:"object=<name>"`,
+		)
+	}
+	return t.Name(), nil
+}
+
+// recordStructType returns the struct type of a record or of the first record of a slice
+func recordStructType(v reflect.Value) reflect.Type {
+	for v.IsValid() {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if v.IsNil() {
+				return structElem(v.Type())
+			}
+			v = v.Elem()
+		case reflect.Slice, reflect.Array:
+			if v.Len() == 0 {
+				return structElem(v.Type())
+			}
+			v = v.Index(0)
+		case reflect.Struct:
+			return v.Type()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// structElem returns the struct type that a pointer, slice, or array type holds
+func structElem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,117 @@
+package salesforce
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_inferSObjectName(t *testing.T) {
+	type Account struct {
+		Name string
+	}
+	type contactRecord struct {
+		_        struct{} `salesforce:"object=Contact"`
+		LastName string
+	}
+	tests := []struct {
+		name        string
+		sObjectName string
+		records     any
+		disabled    bool
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "explicit_name",
+			sObjectName: "Lead",
+			records:     Account{},
+			want:        "Lead",
+		},
+		{
+			name:    "type_name",
+			records: Account{},
+			want:    "Account",
+		},
+		{
+			name:    "pointer_slice",
+			records: []*Account{{Name: "a"}},
+			want:    "Account",
+		},
+		{
+			name:    "empty_slice",
+			records: []Account{},
+			want:    "Account",
+		},
+		{
+			name:    "tagged_struct",
+			records: &contactRecord{},
+			want:    "Contact",
+		},
+		{
+			name:     "tagged_struct_with_type_names_disabled",
+			records:  []contactRecord{{}},
+			disabled: true,
+			want:     "Contact",
+		},
+		{
+			name:     "type_names_disabled",
+			records:  Account{},
+			disabled: true,
+			wantErr:  true,
+		},
+		{
+			name:    "interface_slice",
+			records: []any{Account{}},
+			want:    "Account",
+		},
+		{
+			name:    "anonymous_struct",
+			records: struct{ Name string }{},
+			wantErr: true,
+		},
+		{
+			name:    "map",
+			records: map[string]any{"Name": "a"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&authentication{})
+			sf.config.sObjectNameInference = !tt.disabled
+			got, err := inferSObjectName(sf, tt.sObjectName, tt.records)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inferSObjectName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("inferSObjectName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalesforce_InsertOne_inferredSObjectName(t *testing.T) {
+	type contactRecord struct {
+		_        struct{} `salesforce:"object=Contact"`
+		LastName string
+	}
+	server, sfAuth, req := setupTestServerWithCapture(
+		SalesforceResult{Id: "003A", Success: true},
+		http.StatusCreated,
+	)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	got, err := sf.InsertOne("", contactRecord{LastName: "Grimm"})
+	if err != nil {
+		t.Fatalf("InsertOne() error = %v", err)
+	}
+	want := SalesforceResult{Id: "003A", Success: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InsertOne() = %v, want %v", got, want)
+	}
+	if path := (*req).URL.Path; path != "/services/data/"+apiVersion+"/sobjects/Contact" {
+		t.Errorf("InsertOne() request path = %v", path)
+	}
+}
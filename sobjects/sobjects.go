@@ -0,0 +1,264 @@
+// Package sobjects provides structs for common Salesforce standard objects, with the fields of a
+// default org and their parent relationships. Use them with the salesforce package to query and write
+// records without writing models or generating them with GenerateStructs.
+//
+// Fields are tagged with their API names and omitempty, so only fields that are set are sent with DML.
+// Date and datetime fields are strings in the formats returned by the REST API.
+//
+// The values of common picklists of a default org have typed constants, such as CaseStatusEscalated and
+// OpportunityStageNameClosedWon, generated with GeneratePicklistConstants.
+package sobjects
+
+// Account is an organization or person involved with your business
+type Account struct {
+	Id                 string   `json:"Id,omitempty"                 salesforce:"Id,omitempty"`
+	Name               string   `json:"Name,omitempty"               salesforce:"Name,omitempty"`
+	AccountNumber      string   `json:"AccountNumber,omitempty"      salesforce:"AccountNumber,omitempty"`
+	Type               string   `json:"Type,omitempty"               salesforce:"Type,omitempty"`
+	Industry           string   `json:"Industry,omitempty"           salesforce:"Industry,omitempty"`
+	Rating             string   `json:"Rating,omitempty"             salesforce:"Rating,omitempty"`
+	Phone              string   `json:"Phone,omitempty"              salesforce:"Phone,omitempty"`
+	Fax                string   `json:"Fax,omitempty"                salesforce:"Fax,omitempty"`
+	Website            string   `json:"Website,omitempty"            salesforce:"Website,omitempty"`
+	Description        string   `json:"Description,omitempty"        salesforce:"Description,omitempty"`
+	AnnualRevenue      float64  `json:"AnnualRevenue,omitempty"      salesforce:"AnnualRevenue,omitempty"`
+	NumberOfEmployees  int      `json:"NumberOfEmployees,omitempty"  salesforce:"NumberOfEmployees,omitempty"`
+	Ownership          string   `json:"Ownership,omitempty"          salesforce:"Ownership,omitempty"`
+	Sic                string   `json:"Sic,omitempty"                salesforce:"Sic,omitempty"`
+	BillingStreet      string   `json:"BillingStreet,omitempty"      salesforce:"BillingStreet,omitempty"`
+	BillingCity        string   `json:"BillingCity,omitempty"        salesforce:"BillingCity,omitempty"`
+	BillingState       string   `json:"BillingState,omitempty"       salesforce:"BillingState,omitempty"`
+	BillingPostalCode  string   `json:"BillingPostalCode,omitempty"  salesforce:"BillingPostalCode,omitempty"`
+	BillingCountry     string   `json:"BillingCountry,omitempty"     salesforce:"BillingCountry,omitempty"`
+	ShippingStreet     string   `json:"ShippingStreet,omitempty"     salesforce:"ShippingStreet,omitempty"`
+	ShippingCity       string   `json:"ShippingCity,omitempty"       salesforce:"ShippingCity,omitempty"`
+	ShippingState      string   `json:"ShippingState,omitempty"      salesforce:"ShippingState,omitempty"`
+	ShippingPostalCode string   `json:"ShippingPostalCode,omitempty" salesforce:"ShippingPostalCode,omitempty"`
+	ShippingCountry    string   `json:"ShippingCountry,omitempty"    salesforce:"ShippingCountry,omitempty"`
+	ParentId           string   `json:"ParentId,omitempty"           salesforce:"ParentId,omitempty"`
+	Parent             *Account `json:"Parent,omitempty"             salesforce:"Parent,omitempty"`
+	OwnerId            string   `json:"OwnerId,omitempty"            salesforce:"OwnerId,omitempty"`
+	Owner              *User    `json:"Owner,omitempty"              salesforce:"Owner,omitempty"`
+	IsDeleted          bool     `json:"IsDeleted,omitempty"          salesforce:"IsDeleted,omitempty"`
+	CreatedDate        string   `json:"CreatedDate,omitempty"        salesforce:"CreatedDate,omitempty"`
+	CreatedById        string   `json:"CreatedById,omitempty"        salesforce:"CreatedById,omitempty"`
+	LastModifiedDate   string   `json:"LastModifiedDate,omitempty"   salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById   string   `json:"LastModifiedById,omitempty"   salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp     string   `json:"SystemModstamp,omitempty"     salesforce:"SystemModstamp,omitempty"`
+}
+
+// Contact is a person associated with an account
+type Contact struct {
+	Id                 string   `json:"Id,omitempty"                 salesforce:"Id,omitempty"`
+	Salutation         string   `json:"Salutation,omitempty"         salesforce:"Salutation,omitempty"`
+	FirstName          string   `json:"FirstName,omitempty"          salesforce:"FirstName,omitempty"`
+	LastName           string   `json:"LastName,omitempty"           salesforce:"LastName,omitempty"`
+	Name               string   `json:"Name,omitempty"               salesforce:"Name,omitempty"`
+	Title              string   `json:"Title,omitempty"              salesforce:"Title,omitempty"`
+	Department         string   `json:"Department,omitempty"         salesforce:"Department,omitempty"`
+	Email              string   `json:"Email,omitempty"              salesforce:"Email,omitempty"`
+	Phone              string   `json:"Phone,omitempty"              salesforce:"Phone,omitempty"`
+	MobilePhone        string   `json:"MobilePhone,omitempty"        salesforce:"MobilePhone,omitempty"`
+	HomePhone          string   `json:"HomePhone,omitempty"          salesforce:"HomePhone,omitempty"`
+	Birthdate          string   `json:"Birthdate,omitempty"          salesforce:"Birthdate,omitempty"`
+	LeadSource         string   `json:"LeadSource,omitempty"         salesforce:"LeadSource,omitempty"`
+	Description        string   `json:"Description,omitempty"        salesforce:"Description,omitempty"`
+	MailingStreet      string   `json:"MailingStreet,omitempty"      salesforce:"MailingStreet,omitempty"`
+	MailingCity        string   `json:"MailingCity,omitempty"        salesforce:"MailingCity,omitempty"`
+	MailingState       string   `json:"MailingState,omitempty"       salesforce:"MailingState,omitempty"`
+	MailingPostalCode  string   `json:"MailingPostalCode,omitempty"  salesforce:"MailingPostalCode,omitempty"`
+	MailingCountry     string   `json:"MailingCountry,omitempty"     salesforce:"MailingCountry,omitempty"`
+	HasOptedOutOfEmail bool     `json:"HasOptedOutOfEmail,omitempty" salesforce:"HasOptedOutOfEmail,omitempty"`
+	DoNotCall          bool     `json:"DoNotCall,omitempty"          salesforce:"DoNotCall,omitempty"`
+	AccountId          string   `json:"AccountId,omitempty"          salesforce:"AccountId,omitempty"`
+	Account            *Account `json:"Account,omitempty"            salesforce:"Account,omitempty"`
+	ReportsToId        string   `json:"ReportsToId,omitempty"        salesforce:"ReportsToId,omitempty"`
+	ReportsTo          *Contact `json:"ReportsTo,omitempty"          salesforce:"ReportsTo,omitempty"`
+	OwnerId            string   `json:"OwnerId,omitempty"            salesforce:"OwnerId,omitempty"`
+	Owner              *User    `json:"Owner,omitempty"              salesforce:"Owner,omitempty"`
+	IsDeleted          bool     `json:"IsDeleted,omitempty"          salesforce:"IsDeleted,omitempty"`
+	CreatedDate        string   `json:"CreatedDate,omitempty"        salesforce:"CreatedDate,omitempty"`
+	CreatedById        string   `json:"CreatedById,omitempty"        salesforce:"CreatedById,omitempty"`
+	LastModifiedDate   string   `json:"LastModifiedDate,omitempty"   salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById   string   `json:"LastModifiedById,omitempty"   salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp     string   `json:"SystemModstamp,omitempty"     salesforce:"SystemModstamp,omitempty"`
+}
+
+// Lead is a prospect or potential opportunity
+type Lead struct {
+	Id                     string       `json:"Id,omitempty"                     salesforce:"Id,omitempty"`
+	Salutation             string       `json:"Salutation,omitempty"             salesforce:"Salutation,omitempty"`
+	FirstName              string       `json:"FirstName,omitempty"              salesforce:"FirstName,omitempty"`
+	LastName               string       `json:"LastName,omitempty"               salesforce:"LastName,omitempty"`
+	Name                   string       `json:"Name,omitempty"                   salesforce:"Name,omitempty"`
+	Title                  string       `json:"Title,omitempty"                  salesforce:"Title,omitempty"`
+	Company                string       `json:"Company,omitempty"                salesforce:"Company,omitempty"`
+	Email                  string       `json:"Email,omitempty"                  salesforce:"Email,omitempty"`
+	Phone                  string       `json:"Phone,omitempty"                  salesforce:"Phone,omitempty"`
+	MobilePhone            string       `json:"MobilePhone,omitempty"            salesforce:"MobilePhone,omitempty"`
+	Website                string       `json:"Website,omitempty"                salesforce:"Website,omitempty"`
+	Status                 string       `json:"Status,omitempty"                 salesforce:"Status,omitempty"`
+	Rating                 string       `json:"Rating,omitempty"                 salesforce:"Rating,omitempty"`
+	LeadSource             string       `json:"LeadSource,omitempty"             salesforce:"LeadSource,omitempty"`
+	Industry               string       `json:"Industry,omitempty"               salesforce:"Industry,omitempty"`
+	AnnualRevenue          float64      `json:"AnnualRevenue,omitempty"          salesforce:"AnnualRevenue,omitempty"`
+	NumberOfEmployees      int          `json:"NumberOfEmployees,omitempty"      salesforce:"NumberOfEmployees,omitempty"`
+	Description            string       `json:"Description,omitempty"            salesforce:"Description,omitempty"`
+	Street                 string       `json:"Street,omitempty"                 salesforce:"Street,omitempty"`
+	City                   string       `json:"City,omitempty"                   salesforce:"City,omitempty"`
+	State                  string       `json:"State,omitempty"                  salesforce:"State,omitempty"`
+	PostalCode             string       `json:"PostalCode,omitempty"             salesforce:"PostalCode,omitempty"`
+	Country                string       `json:"Country,omitempty"                salesforce:"Country,omitempty"`
+	HasOptedOutOfEmail     bool         `json:"HasOptedOutOfEmail,omitempty"     salesforce:"HasOptedOutOfEmail,omitempty"`
+	DoNotCall              bool         `json:"DoNotCall,omitempty"              salesforce:"DoNotCall,omitempty"`
+	IsConverted            bool         `json:"IsConverted,omitempty"            salesforce:"IsConverted,omitempty"`
+	ConvertedDate          string       `json:"ConvertedDate,omitempty"          salesforce:"ConvertedDate,omitempty"`
+	ConvertedAccountId     string       `json:"ConvertedAccountId,omitempty"     salesforce:"ConvertedAccountId,omitempty"`
+	ConvertedAccount       *Account     `json:"ConvertedAccount,omitempty"       salesforce:"ConvertedAccount,omitempty"`
+	ConvertedContactId     string       `json:"ConvertedContactId,omitempty"     salesforce:"ConvertedContactId,omitempty"`
+	ConvertedContact       *Contact     `json:"ConvertedContact,omitempty"       salesforce:"ConvertedContact,omitempty"`
+	ConvertedOpportunityId string       `json:"ConvertedOpportunityId,omitempty" salesforce:"ConvertedOpportunityId,omitempty"`
+	ConvertedOpportunity   *Opportunity `json:"ConvertedOpportunity,omitempty"   salesforce:"ConvertedOpportunity,omitempty"`
+	OwnerId                string       `json:"OwnerId,omitempty"                salesforce:"OwnerId,omitempty"`
+	IsDeleted              bool         `json:"IsDeleted,omitempty"              salesforce:"IsDeleted,omitempty"`
+	CreatedDate            string       `json:"CreatedDate,omitempty"            salesforce:"CreatedDate,omitempty"`
+	CreatedById            string       `json:"CreatedById,omitempty"            salesforce:"CreatedById,omitempty"`
+	LastModifiedDate       string       `json:"LastModifiedDate,omitempty"       salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById       string       `json:"LastModifiedById,omitempty"       salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp         string       `json:"SystemModstamp,omitempty"         salesforce:"SystemModstamp,omitempty"`
+}
+
+// Opportunity is a sale or pending deal
+type Opportunity struct {
+	Id                   string   `json:"Id,omitempty"                   salesforce:"Id,omitempty"`
+	Name                 string   `json:"Name,omitempty"                 salesforce:"Name,omitempty"`
+	Description          string   `json:"Description,omitempty"          salesforce:"Description,omitempty"`
+	StageName            string   `json:"StageName,omitempty"            salesforce:"StageName,omitempty"`
+	Amount               float64  `json:"Amount,omitempty"               salesforce:"Amount,omitempty"`
+	Probability          float64  `json:"Probability,omitempty"          salesforce:"Probability,omitempty"`
+	ExpectedRevenue      float64  `json:"ExpectedRevenue,omitempty"      salesforce:"ExpectedRevenue,omitempty"`
+	CloseDate            string   `json:"CloseDate,omitempty"            salesforce:"CloseDate,omitempty"`
+	Type                 string   `json:"Type,omitempty"                 salesforce:"Type,omitempty"`
+	NextStep             string   `json:"NextStep,omitempty"             salesforce:"NextStep,omitempty"`
+	LeadSource           string   `json:"LeadSource,omitempty"           salesforce:"LeadSource,omitempty"`
+	ForecastCategoryName string   `json:"ForecastCategoryName,omitempty" salesforce:"ForecastCategoryName,omitempty"`
+	IsClosed             bool     `json:"IsClosed,omitempty"             salesforce:"IsClosed,omitempty"`
+	IsWon                bool     `json:"IsWon,omitempty"                salesforce:"IsWon,omitempty"`
+	Pricebook2Id         string   `json:"Pricebook2Id,omitempty"         salesforce:"Pricebook2Id,omitempty"`
+	CampaignId           string   `json:"CampaignId,omitempty"           salesforce:"CampaignId,omitempty"`
+	AccountId            string   `json:"AccountId,omitempty"            salesforce:"AccountId,omitempty"`
+	Account              *Account `json:"Account,omitempty"              salesforce:"Account,omitempty"`
+	ContactId            string   `json:"ContactId,omitempty"            salesforce:"ContactId,omitempty"`
+	Contact              *Contact `json:"Contact,omitempty"              salesforce:"Contact,omitempty"`
+	OwnerId              string   `json:"OwnerId,omitempty"              salesforce:"OwnerId,omitempty"`
+	Owner                *User    `json:"Owner,omitempty"                salesforce:"Owner,omitempty"`
+	IsDeleted            bool     `json:"IsDeleted,omitempty"            salesforce:"IsDeleted,omitempty"`
+	CreatedDate          string   `json:"CreatedDate,omitempty"          salesforce:"CreatedDate,omitempty"`
+	CreatedById          string   `json:"CreatedById,omitempty"          salesforce:"CreatedById,omitempty"`
+	LastModifiedDate     string   `json:"LastModifiedDate,omitempty"     salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById     string   `json:"LastModifiedById,omitempty"     salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp       string   `json:"SystemModstamp,omitempty"       salesforce:"SystemModstamp,omitempty"`
+}
+
+// Case is a customer issue such as a question, problem, or feedback
+type Case struct {
+	Id               string   `json:"Id,omitempty"               salesforce:"Id,omitempty"`
+	CaseNumber       string   `json:"CaseNumber,omitempty"       salesforce:"CaseNumber,omitempty"`
+	Subject          string   `json:"Subject,omitempty"          salesforce:"Subject,omitempty"`
+	Description      string   `json:"Description,omitempty"      salesforce:"Description,omitempty"`
+	Status           string   `json:"Status,omitempty"           salesforce:"Status,omitempty"`
+	Priority         string   `json:"Priority,omitempty"         salesforce:"Priority,omitempty"`
+	Origin           string   `json:"Origin,omitempty"           salesforce:"Origin,omitempty"`
+	Reason           string   `json:"Reason,omitempty"           salesforce:"Reason,omitempty"`
+	Type             string   `json:"Type,omitempty"             salesforce:"Type,omitempty"`
+	IsClosed         bool     `json:"IsClosed,omitempty"         salesforce:"IsClosed,omitempty"`
+	ClosedDate       string   `json:"ClosedDate,omitempty"       salesforce:"ClosedDate,omitempty"`
+	IsEscalated      bool     `json:"IsEscalated,omitempty"      salesforce:"IsEscalated,omitempty"`
+	SuppliedName     string   `json:"SuppliedName,omitempty"     salesforce:"SuppliedName,omitempty"`
+	SuppliedEmail    string   `json:"SuppliedEmail,omitempty"    salesforce:"SuppliedEmail,omitempty"`
+	SuppliedPhone    string   `json:"SuppliedPhone,omitempty"    salesforce:"SuppliedPhone,omitempty"`
+	SuppliedCompany  string   `json:"SuppliedCompany,omitempty"  salesforce:"SuppliedCompany,omitempty"`
+	AccountId        string   `json:"AccountId,omitempty"        salesforce:"AccountId,omitempty"`
+	Account          *Account `json:"Account,omitempty"          salesforce:"Account,omitempty"`
+	ContactId        string   `json:"ContactId,omitempty"        salesforce:"ContactId,omitempty"`
+	Contact          *Contact `json:"Contact,omitempty"          salesforce:"Contact,omitempty"`
+	ParentId         string   `json:"ParentId,omitempty"         salesforce:"ParentId,omitempty"`
+	Parent           *Case    `json:"Parent,omitempty"           salesforce:"Parent,omitempty"`
+	OwnerId          string   `json:"OwnerId,omitempty"          salesforce:"OwnerId,omitempty"`
+	IsDeleted        bool     `json:"IsDeleted,omitempty"        salesforce:"IsDeleted,omitempty"`
+	CreatedDate      string   `json:"CreatedDate,omitempty"      salesforce:"CreatedDate,omitempty"`
+	CreatedById      string   `json:"CreatedById,omitempty"      salesforce:"CreatedById,omitempty"`
+	LastModifiedDate string   `json:"LastModifiedDate,omitempty" salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById string   `json:"LastModifiedById,omitempty" salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp   string   `json:"SystemModstamp,omitempty"   salesforce:"SystemModstamp,omitempty"`
+}
+
+// Task is an activity such as a call or to-do item
+type Task struct {
+	Id                    string   `json:"Id,omitempty"                    salesforce:"Id,omitempty"`
+	Subject               string   `json:"Subject,omitempty"               salesforce:"Subject,omitempty"`
+	Description           string   `json:"Description,omitempty"           salesforce:"Description,omitempty"`
+	Status                string   `json:"Status,omitempty"                salesforce:"Status,omitempty"`
+	Priority              string   `json:"Priority,omitempty"              salesforce:"Priority,omitempty"`
+	ActivityDate          string   `json:"ActivityDate,omitempty"          salesforce:"ActivityDate,omitempty"`
+	Type                  string   `json:"Type,omitempty"                  salesforce:"Type,omitempty"`
+	TaskSubtype           string   `json:"TaskSubtype,omitempty"           salesforce:"TaskSubtype,omitempty"`
+	CallType              string   `json:"CallType,omitempty"              salesforce:"CallType,omitempty"`
+	CallDurationInSeconds int      `json:"CallDurationInSeconds,omitempty" salesforce:"CallDurationInSeconds,omitempty"`
+	CallDisposition       string   `json:"CallDisposition,omitempty"       salesforce:"CallDisposition,omitempty"`
+	IsClosed              bool     `json:"IsClosed,omitempty"              salesforce:"IsClosed,omitempty"`
+	IsHighPriority        bool     `json:"IsHighPriority,omitempty"        salesforce:"IsHighPriority,omitempty"`
+	IsReminderSet         bool     `json:"IsReminderSet,omitempty"         salesforce:"IsReminderSet,omitempty"`
+	ReminderDateTime      string   `json:"ReminderDateTime,omitempty"      salesforce:"ReminderDateTime,omitempty"`
+	CompletedDateTime     string   `json:"CompletedDateTime,omitempty"     salesforce:"CompletedDateTime,omitempty"`
+	WhoId                 string   `json:"WhoId,omitempty"                 salesforce:"WhoId,omitempty"`
+	WhatId                string   `json:"WhatId,omitempty"                salesforce:"WhatId,omitempty"`
+	AccountId             string   `json:"AccountId,omitempty"             salesforce:"AccountId,omitempty"`
+	Account               *Account `json:"Account,omitempty"               salesforce:"Account,omitempty"`
+	OwnerId               string   `json:"OwnerId,omitempty"               salesforce:"OwnerId,omitempty"`
+	Owner                 *User    `json:"Owner,omitempty"                 salesforce:"Owner,omitempty"`
+	IsDeleted             bool     `json:"IsDeleted,omitempty"             salesforce:"IsDeleted,omitempty"`
+	CreatedDate           string   `json:"CreatedDate,omitempty"           salesforce:"CreatedDate,omitempty"`
+	CreatedById           string   `json:"CreatedById,omitempty"           salesforce:"CreatedById,omitempty"`
+	LastModifiedDate      string   `json:"LastModifiedDate,omitempty"      salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById      string   `json:"LastModifiedById,omitempty"      salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp        string   `json:"SystemModstamp,omitempty"        salesforce:"SystemModstamp,omitempty"`
+}
+
+// User is a user of the org
+type User struct {
+	Id                   string `json:"Id,omitempty"                   salesforce:"Id,omitempty"`
+	Username             string `json:"Username,omitempty"             salesforce:"Username,omitempty"`
+	FirstName            string `json:"FirstName,omitempty"            salesforce:"FirstName,omitempty"`
+	LastName             string `json:"LastName,omitempty"             salesforce:"LastName,omitempty"`
+	Name                 string `json:"Name,omitempty"                 salesforce:"Name,omitempty"`
+	Alias                string `json:"Alias,omitempty"                salesforce:"Alias,omitempty"`
+	CommunityNickname    string `json:"CommunityNickname,omitempty"    salesforce:"CommunityNickname,omitempty"`
+	Email                string `json:"Email,omitempty"                salesforce:"Email,omitempty"`
+	Phone                string `json:"Phone,omitempty"                salesforce:"Phone,omitempty"`
+	MobilePhone          string `json:"MobilePhone,omitempty"          salesforce:"MobilePhone,omitempty"`
+	Title                string `json:"Title,omitempty"                salesforce:"Title,omitempty"`
+	Department           string `json:"Department,omitempty"           salesforce:"Department,omitempty"`
+	Division             string `json:"Division,omitempty"             salesforce:"Division,omitempty"`
+	CompanyName          string `json:"CompanyName,omitempty"          salesforce:"CompanyName,omitempty"`
+	EmployeeNumber       string `json:"EmployeeNumber,omitempty"       salesforce:"EmployeeNumber,omitempty"`
+	FederationIdentifier string `json:"FederationIdentifier,omitempty" salesforce:"FederationIdentifier,omitempty"`
+	IsActive             bool   `json:"IsActive,omitempty"             salesforce:"IsActive,omitempty"`
+	UserType             string `json:"UserType,omitempty"             salesforce:"UserType,omitempty"`
+	TimeZoneSidKey       string `json:"TimeZoneSidKey,omitempty"       salesforce:"TimeZoneSidKey,omitempty"`
+	LocaleSidKey         string `json:"LocaleSidKey,omitempty"         salesforce:"LocaleSidKey,omitempty"`
+	LanguageLocaleKey    string `json:"LanguageLocaleKey,omitempty"    salesforce:"LanguageLocaleKey,omitempty"`
+	EmailEncodingKey     string `json:"EmailEncodingKey,omitempty"     salesforce:"EmailEncodingKey,omitempty"`
+	ProfileId            string `json:"ProfileId,omitempty"            salesforce:"ProfileId,omitempty"`
+	UserRoleId           string `json:"UserRoleId,omitempty"           salesforce:"UserRoleId,omitempty"`
+	ManagerId            string `json:"ManagerId,omitempty"            salesforce:"ManagerId,omitempty"`
+	Manager              *User  `json:"Manager,omitempty"              salesforce:"Manager,omitempty"`
+	ContactId            string `json:"ContactId,omitempty"            salesforce:"ContactId,omitempty"`
+	AccountId            string `json:"AccountId,omitempty"            salesforce:"AccountId,omitempty"`
+	LastLoginDate        string `json:"LastLoginDate,omitempty"        salesforce:"LastLoginDate,omitempty"`
+	CreatedDate          string `json:"CreatedDate,omitempty"          salesforce:"CreatedDate,omitempty"`
+	CreatedById          string `json:"CreatedById,omitempty"          salesforce:"CreatedById,omitempty"`
+	LastModifiedDate     string `json:"LastModifiedDate,omitempty"     salesforce:"LastModifiedDate,omitempty"`
+	LastModifiedById     string `json:"LastModifiedById,omitempty"     salesforce:"LastModifiedById,omitempty"`
+	SystemModstamp       string `json:"SystemModstamp,omitempty"       salesforce:"SystemModstamp,omitempty"`
+}
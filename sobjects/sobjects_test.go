@@ -0,0 +1,44 @@
+package sobjects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStructTags(t *testing.T) {
+	types := []any{Account{}, Contact{}, Lead{}, Opportunity{}, Case{}, Task{}, User{}}
+	for _, value := range types {
+		structType := reflect.TypeOf(value)
+		t.Run(structType.Name(), func(t *testing.T) {
+			for i := 0; i < structType.NumField(); i++ {
+				field := structType.Field(i)
+				want := field.Name + ",omitempty"
+				if tag := field.Tag.Get("json"); tag != want {
+					t.Errorf("%s json tag = %q, want %q", field.Name, tag, want)
+				}
+				if tag := field.Tag.Get("salesforce"); tag != want {
+					t.Errorf("%s salesforce tag = %q, want %q", field.Name, tag, want)
+				}
+				if field.Type.Kind() != reflect.Pointer {
+					continue
+				}
+				if field.Type.Elem().PkgPath() != structType.PkgPath() {
+					t.Errorf("%s relationship type %s is not in package", field.Name, field.Type)
+				}
+				if _, ok := structType.FieldByName(field.Name + "Id"); !ok {
+					t.Errorf("%s relationship has no %sId field", field.Name, field.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestPicklistValues(t *testing.T) {
+	if len(OpportunityStageNameValues) != 10 ||
+		OpportunityStageNameValues[8] != OpportunityStageNameClosedWon {
+		t.Errorf("OpportunityStageNameValues = %v", OpportunityStageNameValues)
+	}
+	if CaseStatusEscalated != "Escalated" || LeadStatusClosedConverted != "Closed - Converted" {
+		t.Errorf("unexpected picklist constant values")
+	}
+}
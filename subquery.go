@@ -0,0 +1,80 @@
+package salesforce
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// subqueryPage is a page of the records of a subquery, decoded as []any like the records of the
+// first page are
+type subqueryPage struct {
+	Done           bool   `json:"done"`
+	NextRecordsUrl string `json:"nextRecordsUrl"`
+	Records        []any  `json:"records"`
+}
+
+// completeSubqueries reads the remaining pages of the subqueries of a record, such as
+// (SELECT Id FROM Contacts) when an account has more contacts than Salesforce returns with it, so
+// that each subquery result holds all of its records. Subqueries of the related records are completed
+// too.
+func completeSubqueries(ctx context.Context, sf *Salesforce, record map[string]any) error {
+	for _, value := range record {
+		result, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		children, ok := subqueryRecords(result)
+		if !ok {
+			continue
+		}
+		nextRecordsUrl, _ := result["nextRecordsUrl"].(string)
+		if done, _ := result["done"].(bool); !done && nextRecordsUrl != "" {
+			for nextRecordsUrl != "" {
+				page := &subqueryPage{}
+				err := readQueryPage(
+					ctx,
+					sf,
+					strings.TrimPrefix(nextRecordsUrl, "/services/data/"+sf.config.apiVersion),
+					page,
+				)
+				if err != nil {
+					return err
+				}
+				children = append(children, page.Records...)
+				nextRecordsUrl = ""
+				if !page.Done {
+					nextRecordsUrl = page.NextRecordsUrl
+				}
+			}
+			result["records"] = children
+			result["totalSize"] = len(children)
+			result["done"] = true
+			delete(result, "nextRecordsUrl")
+		}
+		for _, child := range children {
+			if childRecord, ok := child.(map[string]any); ok {
+				if err := completeSubqueries(ctx, sf, childRecord); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// subqueryDecodeHook decodes the result of a subquery into a slice field, such as Contacts []Contact,
+// from its records
+func subqueryDecodeHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	result, ok := data.(map[string]any)
+	if !ok || to.Kind() != reflect.Slice {
+		return data, nil
+	}
+	if _, ok := result["done"]; !ok {
+		return data, nil
+	}
+	if records, ok := subqueryRecords(result); ok {
+		return records, nil
+	}
+	return data, nil
+}
@@ -0,0 +1,96 @@
+package salesforce
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_Query_subqueries(t *testing.T) {
+	type contact struct {
+		Id       string
+		LastName string
+	}
+	type account struct {
+		Id       string
+		Name     string
+		Contacts []contact
+	}
+	attributes := `"attributes": {"type": "Contact", "url": "/services/data/v63.0/sobjects/Contact/003"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query/01gD0000002HU6KIAW-2000"):
+			_, _ = w.Write([]byte(`{"totalSize": 3, "done": false,
+				"nextRecordsUrl": "/services/data/v63.0/query/01gD0000002HU6KIAW-4000",
+				"records": [{` + attributes + `, "Id": "003000000000002AAA", "LastName": "Rogers"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/query/01gD0000002HU6KIAW-4000"):
+			_, _ = w.Write([]byte(`{"totalSize": 3, "done": true,
+				"records": [{` + attributes + `, "Id": "003000000000003AAA", "LastName": "Banner"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/query/"):
+			_, _ = w.Write([]byte(`{"totalSize": 2, "done": true, "records": [
+				{"Id": "001000000000001AAA", "Name": "Stark Industries", "Contacts": {
+					"totalSize": 3, "done": false,
+					"nextRecordsUrl": "/services/data/v63.0/query/01gD0000002HU6KIAW-2000",
+					"records": [{` + attributes + `, "Id": "003000000000001AAA", "LastName": "Stark"}]}},
+				{"Id": "001000000000002AAA", "Name": "Wayne Enterprises", "Contacts": null}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	want := []account{
+		{
+			Id:   "001000000000001AAA",
+			Name: "Stark Industries",
+			Contacts: []contact{
+				{Id: "003000000000001AAA", LastName: "Stark"},
+				{Id: "003000000000002AAA", LastName: "Rogers"},
+				{Id: "003000000000003AAA", LastName: "Banner"},
+			},
+		},
+		{Id: "001000000000002AAA", Name: "Wayne Enterprises"},
+	}
+	tests := []struct {
+		name   string
+		strict bool
+	}{
+		{name: "decode", strict: false},
+		{name: "strict_decode", strict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := buildSalesforceStruct(&authentication{
+				InstanceUrl: server.URL,
+				AccessToken: "1234",
+			})
+			sf.config.strictDecoding = tt.strict
+			accounts := []account{}
+			err := sf.Query(
+				"SELECT Id, Name, (SELECT Id, LastName FROM Contacts) FROM Account",
+				&accounts,
+			)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if !reflect.DeepEqual(accounts, want) {
+				t.Errorf("Query() = %+v, want %+v", accounts, want)
+			}
+		})
+	}
+
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+	records := []map[string]any{}
+	if err := sf.Query("SELECT Id, (SELECT Id FROM Contacts) FROM Account", &records); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if contacts := Record(records[0]).GetRecords("Contacts"); len(contacts) != 3 {
+		t.Errorf("Query() returned %d contacts, want 3", len(contacts))
+	}
+	contacts, _ := records[0]["Contacts"].(map[string]any)
+	if contacts["totalSize"] != 3 || contacts["done"] != true || contacts["nextRecordsUrl"] != nil {
+		t.Errorf("Query() returned contacts %v, want a complete result of 3 records", contacts)
+	}
+}
@@ -0,0 +1,19 @@
+package salesforce
+
+import "context"
+
+const toolingQueryResource = "/tooling/query/"
+
+// QueryTooling performs a SOQL query against the Tooling API and decodes the records into sObject
+func (sf *Salesforce) QueryTooling(query string, sObject any) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+
+	records, err := queryResourceRecords(context.Background(), sf, toolingQueryResource, query)
+	if err != nil {
+		return err
+	}
+	return decodeQueryRecords(sf.config, records, sObject)
+}
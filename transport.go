@@ -0,0 +1,52 @@
+package salesforce
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportSettings tunes the connections of the default HTTP transport, see WithTransportSettings.
+// Zero values take the defaults noted on each field.
+type TransportSettings struct {
+	MaxIdleConns        int           // idle connections kept across all hosts (default 100)
+	MaxIdleConnsPerHost int           // idle connections kept per host (default 100)
+	MaxConnsPerHost     int           // connections per host, including active ones (default 0, unlimited)
+	IdleConnTimeout     time.Duration // how long an idle connection is kept open (default 90 seconds)
+	DisableHTTP2        bool          // only use HTTP/1.1, such as behind proxies that break HTTP/2
+}
+
+// withDefaults returns the settings with zero values replaced by the defaults
+func (s TransportSettings) withDefaults() TransportSettings {
+	if s.MaxIdleConns == 0 {
+		s.MaxIdleConns = httpDefaultMaxIdleConnections
+	}
+	if s.MaxIdleConnsPerHost == 0 {
+		s.MaxIdleConnsPerHost = httpDefaultMaxIdleConnsPerHost
+	}
+	if s.IdleConnTimeout == 0 {
+		s.IdleConnTimeout = httpDefaultIdleConnTimeout
+	}
+	return s
+}
+
+// newTransport returns the default transport. Every request of a client goes to the same instance, so
+// the idle connections per host default to the total instead of the 2 of http.DefaultTransport, which
+// would otherwise close and reopen connections under parallel composite and bulk workloads.
+func newTransport(settings TransportSettings) *http.Transport {
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        settings.MaxIdleConns,
+		MaxIdleConnsPerHost: settings.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     settings.MaxConnsPerHost,
+		IdleConnTimeout:     settings.IdleConnTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableCompression:  false,
+		ForceAttemptHTTP2:   !settings.DisableHTTP2,
+	}
+	if settings.DisableHTTP2 {
+		// a non-nil empty map disables the automatic HTTP/2 upgrade
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
@@ -0,0 +1,390 @@
+package salesforce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const graphNodesMax = 500
+
+type unitOfWorkOperation struct {
+	method          string
+	sObjectName     string
+	externalIdField string
+	recordId        string
+	record          map[string]any
+	dependencies    []int
+}
+
+// UnitOfWork collects inserts, updates, upserts, and deletes and commits them in a single transaction.
+// Records can reference records inserted in the same unit of work, see RegisterRelationship.
+// Operations are sent with the Composite Graph API, so either all of them are saved or all are rolled back.
+type UnitOfWork struct {
+	sf         *Salesforce
+	operations []unitOfWorkOperation
+	committed  bool
+}
+
+// RecordRef refers to a record registered in a UnitOfWork
+type RecordRef struct {
+	index int
+}
+
+// Index returns the position of the record's operation in the unit of work and its commit results
+func (r RecordRef) Index() int {
+	return r.index
+}
+
+func (r RecordRef) referenceId() string {
+	return "ref" + strconv.Itoa(r.index)
+}
+
+type compositeGraphRequest struct {
+	Graphs []compositeGraph `json:"graphs"`
+}
+
+type compositeGraph struct {
+	GraphId          string                     `json:"graphId"`
+	CompositeRequest []compositeGraphSubRequest `json:"compositeRequest"`
+}
+
+type compositeGraphSubRequest struct {
+	Body        map[string]any `json:"body,omitempty"`
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	ReferenceId string         `json:"referenceId"`
+}
+
+type compositeGraphResponse struct {
+	Graphs []struct {
+		GraphId       string `json:"graphId"`
+		IsSuccessful  bool   `json:"isSuccessful"`
+		GraphResponse struct {
+			CompositeResponse []struct {
+				Body           json.RawMessage `json:"body"`
+				HttpStatusCode int             `json:"httpStatusCode"`
+				ReferenceId    string          `json:"referenceId"`
+			} `json:"compositeResponse"`
+		} `json:"graphResponse"`
+	} `json:"graphs"`
+}
+
+// NewUnitOfWork returns an empty UnitOfWork
+func (sf *Salesforce) NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{sf: sf}
+}
+
+func (u *UnitOfWork) register(operation unitOfWorkOperation) (RecordRef, error) {
+	if u.committed {
+		return RecordRef{}, errors.New("unit of work has already been committed")
+	}
+	if operation.sObjectName == "" {
+		return RecordRef{}, errors.New("sObject name cannot be empty")
+	}
+	if len(u.operations) >= graphNodesMax {
+		return RecordRef{}, fmt.Errorf(
+			"unit of work cannot have more than %d operations",
+			graphNodesMax,
+		)
+	}
+	u.operations = append(u.operations, operation)
+	return RecordRef{index: len(u.operations) - 1}, nil
+}
+
+func (u *UnitOfWork) recordMap(record any) (map[string]any, error) {
+	if err := validateOfTypeStructOrMap(record); err != nil {
+		return nil, err
+	}
+	recordMap, err := convertToMap(record)
+	if err != nil {
+		return nil, err
+	}
+	// copy maps so that relationships do not modify the caller's record
+	copied := make(map[string]any, len(recordMap))
+	for field, value := range recordMap {
+		copied[field] = value
+	}
+	return copied, nil
+}
+
+// RegisterInsert adds a record to insert
+func (u *UnitOfWork) RegisterInsert(sObjectName string, record any) (RecordRef, error) {
+	recordMap, err := u.recordMap(record)
+	if err != nil {
+		return RecordRef{}, err
+	}
+	delete(recordMap, "Id")
+	return u.register(unitOfWorkOperation{
+		method:      http.MethodPost,
+		sObjectName: sObjectName,
+		record:      recordMap,
+	})
+}
+
+// RegisterUpdate adds a record to update, the record must have an Id
+func (u *UnitOfWork) RegisterUpdate(sObjectName string, record any) (RecordRef, error) {
+	recordMap, err := u.recordMap(record)
+	if err != nil {
+		return RecordRef{}, err
+	}
+	recordId, ok := recordMap["Id"].(string)
+	if !ok || recordId == "" {
+		return RecordRef{}, errors.New("salesforce id not found in object data")
+	}
+	delete(recordMap, "Id")
+	return u.register(unitOfWorkOperation{
+		method:      http.MethodPatch,
+		sObjectName: sObjectName,
+		recordId:    recordId,
+		record:      recordMap,
+	})
+}
+
+// RegisterUpsert adds a record to upsert on the given external id field
+func (u *UnitOfWork) RegisterUpsert(
+	sObjectName string,
+	externalIdFieldName string,
+	record any,
+) (RecordRef, error) {
+	recordMap, err := u.recordMap(record)
+	if err != nil {
+		return RecordRef{}, err
+	}
+	externalIdValue, err := checkForExternalId(sObjectName, externalIdFieldName, recordMap)
+	if err != nil {
+		return RecordRef{}, err
+	}
+	delete(recordMap, "Id")
+	delete(recordMap, externalIdFieldName)
+	return u.register(unitOfWorkOperation{
+		method:          http.MethodPatch,
+		sObjectName:     sObjectName,
+		externalIdField: externalIdFieldName,
+		recordId:        externalIdValue.(string),
+		record:          recordMap,
+	})
+}
+
+// RegisterDelete adds a record to delete
+func (u *UnitOfWork) RegisterDelete(sObjectName string, recordId string) (RecordRef, error) {
+	if recordId == "" {
+		return RecordRef{}, errors.New("salesforce id cannot be empty")
+	}
+	return u.register(unitOfWorkOperation{
+		method:      http.MethodDelete,
+		sObjectName: sObjectName,
+		recordId:    recordId,
+	})
+}
+
+// RegisterRelationship sets a lookup field of a registered record to the Id of a parent record that is
+// inserted or upserted in the same unit of work. The parent is saved before the record.
+func (u *UnitOfWork) RegisterRelationship(
+	record RecordRef,
+	fieldName string,
+	parent RecordRef,
+) error {
+	if u.committed {
+		return errors.New("unit of work has already been committed")
+	}
+	if record.index < 0 || record.index >= len(u.operations) ||
+		parent.index < 0 || parent.index >= len(u.operations) {
+		return errors.New("record reference is not part of this unit of work")
+	}
+	if fieldName == "" {
+		return errors.New("relationship field name cannot be empty")
+	}
+	if record.index == parent.index {
+		return errors.New("a record cannot be related to itself")
+	}
+	operation := &u.operations[record.index]
+	if operation.method == http.MethodDelete {
+		return errors.New("relationships cannot be set on deleted records")
+	}
+	parentOperation := u.operations[parent.index]
+	if parentOperation.method != http.MethodPost && parentOperation.externalIdField == "" {
+		return errors.New("parent record must be inserted or upserted")
+	}
+	operation.record[fieldName] = "@{" + parent.referenceId() + ".id}"
+	operation.dependencies = append(operation.dependencies, parent.index)
+	return nil
+}
+
+// order returns the indexes of operations sorted so that parents come before the records that
+// reference them, keeping the registration order otherwise
+func (u *UnitOfWork) order() ([]int, error) {
+	ordered := make([]int, 0, len(u.operations))
+	added := make([]bool, len(u.operations))
+	for len(ordered) < len(u.operations) {
+		progressed := false
+		for i, operation := range u.operations {
+			if added[i] {
+				continue
+			}
+			ready := true
+			for _, dependency := range operation.dependencies {
+				if !added[dependency] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, i)
+				added[i] = true
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return nil, errors.New("unit of work relationships contain a cycle")
+		}
+	}
+	return ordered, nil
+}
+
+func (u *UnitOfWork) subRequest(index int) (compositeGraphSubRequest, error) {
+	operation := u.operations[index]
+	uri := "/services/data/" + u.sf.config.apiVersion + "/sobjects/" + operation.sObjectName
+	switch {
+	case operation.externalIdField != "":
+		uri += "/" + operation.externalIdField + "/" + url.PathEscape(operation.recordId)
+	case operation.recordId != "":
+		uri += "/" + operation.recordId
+	}
+	if operation.record != nil {
+		dmlOperation := updateOperation
+		switch {
+		case operation.method == http.MethodPost:
+			dmlOperation = insertOperation
+		case operation.externalIdField != "":
+			dmlOperation = upsertOperation
+		}
+		if err := prepareRecords(u.sf, operation.sObjectName, dmlOperation, operation.record); err != nil {
+			return compositeGraphSubRequest{}, err
+		}
+	}
+	return compositeGraphSubRequest{
+		Body:        operation.record,
+		Method:      operation.method,
+		Url:         uri,
+		ReferenceId: RecordRef{index: index}.referenceId(),
+	}, nil
+}
+
+// Commit saves all registered operations in a single transaction. Results are in the order the
+// operations were registered. If any operation fails, every operation is rolled back and the results
+// report the errors.
+func (u *UnitOfWork) Commit() (SalesforceResults, error) {
+	authErr := validateAuth(*u.sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if u.committed {
+		return SalesforceResults{}, errors.New("unit of work has already been committed")
+	}
+	if len(u.operations) == 0 {
+		return SalesforceResults{}, errors.New("unit of work has no operations")
+	}
+
+	ordered, err := u.order()
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	graph := compositeGraph{GraphId: "unitOfWork"}
+	for _, index := range ordered {
+		subReq, err := u.subRequest(index)
+		if err != nil {
+			return SalesforceResults{}, err
+		}
+		graph.CompositeRequest = append(graph.CompositeRequest, subReq)
+	}
+
+	body, err := json.Marshal(compositeGraphRequest{Graphs: []compositeGraph{graph}})
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	resp, err := doRequest(u.sf.auth, u.sf.config, requestPayload{
+		method:   http.MethodPost,
+		uri:      "/composite/graph",
+		content:  jsonType,
+		body:     requestBody(body),
+		compress: u.sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	u.committed = true
+	return u.processGraphResponse(resp)
+}
+
+func (u *UnitOfWork) processGraphResponse(resp *http.Response) (SalesforceResults, error) {
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	graphResp := compositeGraphResponse{}
+	if err := json.Unmarshal(responseData, &graphResp); err != nil {
+		return SalesforceResults{}, err
+	}
+	if len(graphResp.Graphs) != 1 {
+		return SalesforceResults{}, errors.New("unexpected composite graph response")
+	}
+	graph := graphResp.Graphs[0]
+
+	results := make([]SalesforceResult, len(u.operations))
+	for i := range results {
+		results[i] = SalesforceResult{Id: u.operations[i].recordId, Index: i}
+		if u.operations[i].externalIdField != "" {
+			results[i].Id = ""
+		}
+	}
+	for _, nodeResp := range graph.GraphResponse.CompositeResponse {
+		reference, found := strings.CutPrefix(nodeResp.ReferenceId, "ref")
+		index, err := strconv.Atoi(reference)
+		if !found || err != nil || index < 0 || index >= len(results) {
+			continue
+		}
+		result := &results[index]
+		body := bytes.TrimSpace(nodeResp.Body)
+		switch {
+		case len(body) > 0 && body[0] == '[':
+			if err := json.Unmarshal(body, &result.Errors); err != nil {
+				return SalesforceResults{}, err
+			}
+		case len(body) > 0 && body[0] == '{':
+			var nodeResult SalesforceResult
+			if err := json.Unmarshal(body, &nodeResult); err != nil {
+				return SalesforceResults{}, err
+			}
+			if nodeResult.Id != "" {
+				result.Id = nodeResult.Id
+			}
+			result.Errors = nodeResult.Errors
+		}
+		result.Success = graph.IsSuccessful && nodeResp.HttpStatusCode < http.StatusMultipleChoices
+	}
+
+	if !graph.IsSuccessful {
+		for i := range results {
+			results[i].Success = false
+			if u.operations[i].method == http.MethodPost {
+				results[i].Id = ""
+			}
+			if len(results[i].Errors) == 0 {
+				results[i].Errors = []SalesforceErrorMessage{{
+					Message:   "The transaction was rolled back since another operation in the same transaction failed.",
+					ErrorCode: ErrorCodeAllOrNoneRolledBack,
+				}}
+			}
+		}
+		return SalesforceResults{Results: results, HasSalesforceErrors: true}, nil
+	}
+	return SalesforceResults{Results: results}, nil
+}
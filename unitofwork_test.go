@@ -0,0 +1,251 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	type contact struct {
+		LastName  string
+		AccountId string
+	}
+
+	t.Run("orders_parents_first", func(t *testing.T) {
+		resp := map[string]any{"graphs": []map[string]any{{
+			"graphId":      "unitOfWork",
+			"isSuccessful": true,
+			"graphResponse": map[string]any{"compositeResponse": []map[string]any{
+				{
+					"body": map[string]any{
+						"id":      "001000000000001AAA",
+						"success": true,
+						"errors":  []any{},
+					},
+					"httpStatusCode": 201,
+					"referenceId":    "ref1",
+				},
+				{
+					"body": map[string]any{
+						"id":      "003000000000001AAA",
+						"success": true,
+						"errors":  []any{},
+					},
+					"httpStatusCode": 201,
+					"referenceId":    "ref0",
+				},
+				{"body": nil, "httpStatusCode": 204, "referenceId": "ref2"},
+				{"body": nil, "httpStatusCode": 204, "referenceId": "ref3"},
+			}},
+		}}}
+		server, sfAuth := setupTestServer(resp, http.StatusOK)
+		defer server.Close()
+		handler := server.Config.Handler
+		var graphReq compositeGraphRequest
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/services/data/"+apiVersion+"/composite/graph" {
+				t.Errorf("Commit() path = %v", r.URL.Path)
+			}
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &graphReq)
+			handler.ServeHTTP(w, r)
+		})
+		sf := buildSalesforceStruct(&sfAuth)
+
+		uow := sf.NewUnitOfWork()
+		contactRef, err := uow.RegisterInsert("Contact", contact{LastName: "Stark"})
+		if err != nil {
+			t.Fatalf("RegisterInsert() error = %v", err)
+		}
+		accountRef, err := uow.RegisterInsert("Account", map[string]any{"Name": "Stark Industries"})
+		if err != nil {
+			t.Fatalf("RegisterInsert() error = %v", err)
+		}
+		if err := uow.RegisterRelationship(contactRef, "AccountId", accountRef); err != nil {
+			t.Fatalf("RegisterRelationship() error = %v", err)
+		}
+		if _, err := uow.RegisterUpdate("Account", map[string]any{"Id": "001000000000002AAA", "Name": "Updated"}); err != nil {
+			t.Fatalf("RegisterUpdate() error = %v", err)
+		}
+		if _, err := uow.RegisterDelete("Account", "001000000000003AAA"); err != nil {
+			t.Fatalf("RegisterDelete() error = %v", err)
+		}
+
+		got, err := uow.Commit()
+		if err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+
+		base := "/services/data/" + apiVersion + "/sobjects/"
+		wantReqs := []compositeGraphSubRequest{
+			{
+				Body:        map[string]any{"Name": "Stark Industries"},
+				Method:      http.MethodPost,
+				Url:         base + "Account",
+				ReferenceId: "ref1",
+			},
+			{
+				Body:        map[string]any{"LastName": "Stark", "AccountId": "@{ref1.id}"},
+				Method:      http.MethodPost,
+				Url:         base + "Contact",
+				ReferenceId: "ref0",
+			},
+			{
+				Body:        map[string]any{"Name": "Updated"},
+				Method:      http.MethodPatch,
+				Url:         base + "Account/001000000000002AAA",
+				ReferenceId: "ref2",
+			},
+			{
+				Method:      http.MethodDelete,
+				Url:         base + "Account/001000000000003AAA",
+				ReferenceId: "ref3",
+			},
+		}
+		if len(graphReq.Graphs) != 1 ||
+			!reflect.DeepEqual(graphReq.Graphs[0].CompositeRequest, wantReqs) {
+			t.Errorf("Commit() request = %v, want %v", graphReq, wantReqs)
+		}
+
+		want := SalesforceResults{Results: []SalesforceResult{
+			{Id: "003000000000001AAA", Success: true, Errors: []SalesforceErrorMessage{}, Index: 0},
+			{Id: "001000000000001AAA", Success: true, Errors: []SalesforceErrorMessage{}, Index: 1},
+			{Id: "001000000000002AAA", Success: true, Index: 2},
+			{Id: "001000000000003AAA", Success: true, Index: 3},
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Commit() = %v, want %v", got, want)
+		}
+
+		if _, err := uow.Commit(); err == nil {
+			t.Errorf("Commit() expected error when committed twice")
+		}
+	})
+
+	t.Run("rolled_back", func(t *testing.T) {
+		resp := map[string]any{"graphs": []map[string]any{{
+			"graphId":      "unitOfWork",
+			"isSuccessful": false,
+			"graphResponse": map[string]any{"compositeResponse": []map[string]any{
+				{
+					"body": map[string]any{
+						"id":      "001000000000001AAA",
+						"success": true,
+						"errors":  []any{},
+					},
+					"httpStatusCode": 201,
+					"referenceId":    "ref0",
+				},
+				{
+					"body": []map[string]any{
+						{
+							"errorCode": "REQUIRED_FIELD_MISSING",
+							"message":   "Required fields are missing: [LastName]",
+						},
+					},
+					"httpStatusCode": 400,
+					"referenceId":    "ref1",
+				},
+			}},
+		}}}
+		server, sfAuth := setupTestServer(resp, http.StatusOK)
+		defer server.Close()
+		sf := buildSalesforceStruct(&sfAuth)
+
+		uow := sf.NewUnitOfWork()
+		accountRef, _ := uow.RegisterInsert("Account", map[string]any{"Name": "Stark Industries"})
+		contactRef, _ := uow.RegisterInsert("Contact", contact{})
+		if err := uow.RegisterRelationship(contactRef, "AccountId", accountRef); err != nil {
+			t.Fatalf("RegisterRelationship() error = %v", err)
+		}
+
+		got, err := uow.Commit()
+		if err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+		if !got.HasSalesforceErrors {
+			t.Errorf("Commit() HasSalesforceErrors = false")
+		}
+		rootCauses := got.RootCauses()
+		if len(rootCauses) != 1 || rootCauses[0].Index != 1 {
+			t.Errorf("Commit() root causes = %v", rootCauses)
+		}
+		if got.Results[0].Id != "" || got.Results[0].Success {
+			t.Errorf("Commit() rolled back result = %v", got.Results[0])
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		uow := sf.NewUnitOfWork()
+		first, _ := uow.RegisterInsert("Account", map[string]any{"Name": "first"})
+		second, _ := uow.RegisterInsert("Account", map[string]any{"Name": "second"})
+		_ = uow.RegisterRelationship(first, "ParentId", second)
+		_ = uow.RegisterRelationship(second, "ParentId", first)
+
+		if _, err := uow.Commit(); err == nil {
+			t.Errorf("Commit() expected error for cycle")
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+		if _, err := sf.NewUnitOfWork().Commit(); err == nil {
+			t.Errorf("Commit() expected error")
+		}
+	})
+}
+
+func TestUnitOfWork_Register(t *testing.T) {
+	sf := buildSalesforceStruct(&authentication{AccessToken: "1234"})
+	uow := sf.NewUnitOfWork()
+
+	if _, err := uow.RegisterUpdate("Account", map[string]any{"Name": "no id"}); err == nil {
+		t.Errorf("RegisterUpdate() expected error without Id")
+	}
+	if _, err := uow.RegisterUpsert("Account", "External_Id__c", map[string]any{"Name": "no id"}); err == nil {
+		t.Errorf("RegisterUpsert() expected error without external id")
+	}
+	if _, err := uow.RegisterDelete("Account", ""); err == nil {
+		t.Errorf("RegisterDelete() expected error without Id")
+	}
+	if _, err := uow.RegisterInsert("", map[string]any{}); err == nil {
+		t.Errorf("RegisterInsert() expected error without sObject name")
+	}
+	if _, err := uow.RegisterInsert("Account", "not a record"); err == nil {
+		t.Errorf("RegisterInsert() expected error for invalid record")
+	}
+
+	record := map[string]any{"LastName": "Stark"}
+	child, _ := uow.RegisterInsert("Contact", record)
+	updated, _ := uow.RegisterUpdate("Account", map[string]any{"Id": "001000000000002AAA"})
+	deleted, _ := uow.RegisterDelete("Account", "001000000000003AAA")
+	upserted, _ := uow.RegisterUpsert(
+		"Account",
+		"External_Id__c",
+		map[string]any{"External_Id__c": "A-1"},
+	)
+
+	if err := uow.RegisterRelationship(child, "AccountId", updated); err == nil {
+		t.Errorf("RegisterRelationship() expected error for updated parent")
+	}
+	if err := uow.RegisterRelationship(deleted, "ParentId", upserted); err == nil {
+		t.Errorf("RegisterRelationship() expected error for deleted record")
+	}
+	if err := uow.RegisterRelationship(child, "AccountId", RecordRef{index: 10}); err == nil {
+		t.Errorf("RegisterRelationship() expected error for unknown reference")
+	}
+	if err := uow.RegisterRelationship(child, "AccountId", upserted); err != nil {
+		t.Errorf("RegisterRelationship() error = %v", err)
+	}
+	if _, ok := record["AccountId"]; ok {
+		t.Errorf("RegisterRelationship() modified the registered record")
+	}
+	if got := uow.operations[upserted.Index()]; got.recordId != "A-1" ||
+		got.externalIdField != "External_Id__c" {
+		t.Errorf("RegisterUpsert() operation = %v", got)
+	}
+}
@@ -0,0 +1,190 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewUser is a user to create with CreateUser. Zero values of the locale settings take the defaults
+// noted on each field.
+type NewUser struct {
+	Username          string // unique across all orgs, in the form of an email address
+	FirstName         string
+	LastName          string
+	Email             string
+	Alias             string // up to 8 characters (default the first 8 characters of the username)
+	ProfileId         string
+	UserRoleId        string
+	TimeZoneSidKey    string         // default GMT
+	LocaleSidKey      string         // default en_US
+	LanguageLocaleKey string         // default en_US
+	EmailEncodingKey  string         // default UTF-8
+	Fields            map[string]any // other User fields, such as custom fields
+}
+
+// CreateUser creates a user. Salesforce sends the user an email to set their password unless the org
+// is configured otherwise.
+func (sf *Salesforce) CreateUser(user NewUser) (SalesforceResult, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResult{}, authErr
+	}
+	if user.Username == "" || user.LastName == "" || user.Email == "" || user.ProfileId == "" {
+		return SalesforceResult{}, errors.New(
+			"users require a username, last name, email, and profile id",
+		)
+	}
+	record := make(map[string]any, len(user.Fields)+11)
+	for name, value := range user.Fields {
+		record[name] = value
+	}
+	record["Username"] = user.Username
+	record["LastName"] = user.LastName
+	record["Email"] = user.Email
+	record["ProfileId"] = user.ProfileId
+	record["Alias"] = valueOrDefault(user.Alias, user.Username[:min(len(user.Username), 8)])
+	record["TimeZoneSidKey"] = valueOrDefault(user.TimeZoneSidKey, "GMT")
+	record["LocaleSidKey"] = valueOrDefault(user.LocaleSidKey, "en_US")
+	record["LanguageLocaleKey"] = valueOrDefault(user.LanguageLocaleKey, "en_US")
+	record["EmailEncodingKey"] = valueOrDefault(user.EmailEncodingKey, "UTF-8")
+	if user.FirstName != "" {
+		record["FirstName"] = user.FirstName
+	}
+	if user.UserRoleId != "" {
+		record["UserRoleId"] = user.UserRoleId
+	}
+	return sf.InsertOne("User", record)
+}
+
+// AssignPermissionSets assigns permission sets to a user
+func (sf *Salesforce) AssignPermissionSets(
+	userId string,
+	permissionSetIds ...string,
+) (SalesforceResults, error) {
+	return assignPermissions(sf, userId, "PermissionSetId", permissionSetIds)
+}
+
+// AssignPermissionSetGroups assigns permission set groups to a user
+func (sf *Salesforce) AssignPermissionSetGroups(
+	userId string,
+	permissionSetGroupIds ...string,
+) (SalesforceResults, error) {
+	return assignPermissions(sf, userId, "PermissionSetGroupId", permissionSetGroupIds)
+}
+
+// UnassignPermissionSets removes permission sets or permission set groups from a user. Ids that are
+// not assigned to the user are ignored.
+func (sf *Salesforce) UnassignPermissionSets(
+	userId string,
+	permissionSetIds ...string,
+) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if userId == "" || len(permissionSetIds) == 0 {
+		return SalesforceResults{}, errors.New("user id and permission set ids are required")
+	}
+	ids := make([]string, len(permissionSetIds))
+	for i, id := range permissionSetIds {
+		ids[i] = "'" + escapeSoqlString(id) + "'"
+	}
+	idList := strings.Join(ids, ", ")
+	query := fmt.Sprintf(
+		"SELECT Id FROM PermissionSetAssignment WHERE AssigneeId = '%s' AND "+
+			"(PermissionSetId IN (%s) OR PermissionSetGroupId IN (%s))",
+		escapeSoqlString(userId),
+		idList,
+		idList,
+	)
+	assignments, err := queryAllRecords(context.Background(), sf, query)
+	if err != nil {
+		return SalesforceResults{}, err
+	}
+	if len(assignments) == 0 {
+		return SalesforceResults{}, nil
+	}
+	return sf.DeleteCollection("PermissionSetAssignment", assignments, sf.config.batchSizeMax)
+}
+
+// FreezeUser freezes or unfreezes a user, which blocks or restores their logins without deactivating
+// them, such as while investigating a compromised account
+func (sf *Salesforce) FreezeUser(userId string, frozen bool) error {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return authErr
+	}
+	if userId == "" {
+		return errors.New("user id is required")
+	}
+	logins, err := queryAllRecords(
+		context.Background(),
+		sf,
+		"SELECT Id FROM UserLogin WHERE UserId = '"+escapeSoqlString(userId)+"'",
+	)
+	if err != nil {
+		return err
+	}
+	if len(logins) == 0 {
+		return fmt.Errorf("no user login found for user %s", userId)
+	}
+	return sf.UpdateOne("UserLogin", map[string]any{"Id": logins[0]["Id"], "IsFrozen": frozen})
+}
+
+// ResetPassword resets the password of a user to a generated password, which is returned
+func (sf *Salesforce) ResetPassword(userId string) (string, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return "", authErr
+	}
+	if userId == "" {
+		return "", errors.New("user id is required")
+	}
+	resp, err := doRequest(sf.auth, sf.config, requestPayload{
+		method:   http.MethodDelete,
+		uri:      "/sobjects/User/" + url.PathEscape(userId) + "/password",
+		content:  jsonType,
+		compress: sf.config.compressionHeaders,
+	})
+	if err != nil {
+		return "", err
+	}
+	password := struct {
+		NewPassword string `json:"NewPassword"`
+	}{}
+	if err := decodeJSONResponse(resp, &password); err != nil {
+		return "", err
+	}
+	return password.NewPassword, nil
+}
+
+func assignPermissions(
+	sf *Salesforce,
+	userId string,
+	idField string,
+	ids []string,
+) (SalesforceResults, error) {
+	authErr := validateAuth(*sf)
+	if authErr != nil {
+		return SalesforceResults{}, authErr
+	}
+	if userId == "" || len(ids) == 0 {
+		return SalesforceResults{}, errors.New("user id and ids to assign are required")
+	}
+	records := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		records[i] = map[string]any{"AssigneeId": userId, idField: id}
+	}
+	return sf.InsertCollection("PermissionSetAssignment", records, sf.config.batchSizeMax)
+}
+
+func valueOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
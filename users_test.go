@@ -0,0 +1,201 @@
+package salesforce
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSalesforce_CreateUser(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"005000000000001AAA","success":true,"errors":[]}`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	tests := []struct {
+		name    string
+		user    NewUser
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			user: NewUser{
+				Username:  "jane.doe@example.com",
+				LastName:  "Doe",
+				Email:     "jane.doe@example.com",
+				ProfileId: "00e000000000001AAA",
+				Fields:    map[string]any{"Department": "Sales"},
+			},
+			want: map[string]any{
+				"Alias":             "jane.doe",
+				"TimeZoneSidKey":    "GMT",
+				"LocaleSidKey":      "en_US",
+				"LanguageLocaleKey": "en_US",
+				"EmailEncodingKey":  "UTF-8",
+				"Department":        "Sales",
+			},
+		},
+		{
+			name: "overrides",
+			user: NewUser{
+				Username:       "jd@example.com",
+				FirstName:      "Jane",
+				LastName:       "Doe",
+				Email:          "jane.doe@example.com",
+				Alias:          "jdoe",
+				ProfileId:      "00e000000000001AAA",
+				TimeZoneSidKey: "America/Chicago",
+			},
+			want: map[string]any{
+				"FirstName":      "Jane",
+				"Alias":          "jdoe",
+				"TimeZoneSidKey": "America/Chicago",
+			},
+		},
+		{
+			name:    "missing_profile",
+			user:    NewUser{Username: "jd@example.com", LastName: "Doe", Email: "jd@example.com"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = nil
+			result, err := sf.CreateUser(tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result.Id != "005000000000001AAA" {
+				t.Errorf("CreateUser() = %v", result)
+			}
+			for name, value := range tt.want {
+				if body[name] != value {
+					t.Errorf("CreateUser() sent %v = %v, want %v", name, body[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestSalesforce_AssignPermissionSets(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = w.Write([]byte(`[{"id":"0Pa000000000001AAA","success":true,"errors":[]}]`))
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	if _, err := sf.AssignPermissionSets("005000000000001AAA", "0PS000000000001AAA"); err != nil {
+		t.Fatalf("AssignPermissionSets() error = %v", err)
+	}
+	record := body["records"].([]any)[0].(map[string]any)
+	if record["AssigneeId"] != "005000000000001AAA" ||
+		record["PermissionSetId"] != "0PS000000000001AAA" {
+		t.Errorf("AssignPermissionSets() sent %v", record)
+	}
+
+	if _, err := sf.AssignPermissionSetGroups("005000000000001AAA", "0PG000000000001AAA"); err != nil {
+		t.Fatalf("AssignPermissionSetGroups() error = %v", err)
+	}
+	record = body["records"].([]any)[0].(map[string]any)
+	if record["PermissionSetGroupId"] != "0PG000000000001AAA" {
+		t.Errorf("AssignPermissionSetGroups() sent %v", record)
+	}
+	if _, err := sf.AssignPermissionSets("005000000000001AAA"); err == nil {
+		t.Error("AssignPermissionSets() expected an error without permission set ids")
+	}
+}
+
+func TestSalesforce_UnassignPermissionSets(t *testing.T) {
+	var query, deleteUri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleteUri = r.URL.RequestURI()
+			_, _ = w.Write([]byte(`[{"id":"0Pa000000000001AAA","success":true,"errors":[]}]`))
+			return
+		}
+		query = r.URL.Query().Get("q")
+		_, _ = w.Write(
+			[]byte(`{"totalSize":1,"done":true,"records":[{"Id":"0Pa000000000001AAA"}]}`),
+		)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	_, err := sf.UnassignPermissionSets("005000000000001AAA", "0PS000000000001AAA")
+	if err != nil {
+		t.Fatalf("UnassignPermissionSets() error = %v", err)
+	}
+	if !strings.Contains(query, "AssigneeId = '005000000000001AAA'") ||
+		!strings.Contains(query, "PermissionSetGroupId IN ('0PS000000000001AAA')") {
+		t.Errorf("query = %v", query)
+	}
+	if !strings.Contains(deleteUri, "ids=0Pa000000000001AAA") {
+		t.Errorf("uri = %v", deleteUri)
+	}
+}
+
+func TestSalesforce_FreezeUser(t *testing.T) {
+	var body map[string]any
+	var patchUri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patchUri = r.URL.Path
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if strings.Contains(r.URL.Query().Get("q"), "005000000000002AAA") {
+			_, _ = w.Write([]byte(`{"totalSize":0,"done":true,"records":[]}`))
+			return
+		}
+		_, _ = w.Write(
+			[]byte(`{"totalSize":1,"done":true,"records":[{"Id":"060000000000001AAA"}]}`),
+		)
+	}))
+	defer server.Close()
+	sf := buildSalesforceStruct(&authentication{InstanceUrl: server.URL, AccessToken: "1234"})
+
+	if err := sf.FreezeUser("005000000000001AAA", true); err != nil {
+		t.Fatalf("FreezeUser() error = %v", err)
+	}
+	if !strings.HasSuffix(patchUri, "/sobjects/UserLogin/060000000000001AAA") ||
+		body["IsFrozen"] != true {
+		t.Errorf("FreezeUser() sent %v to %v", body, patchUri)
+	}
+	if err := sf.FreezeUser("005000000000002AAA", true); err == nil {
+		t.Error("FreezeUser() expected an error without a user login")
+	}
+}
+
+func TestSalesforce_ResetPassword(t *testing.T) {
+	server, sfAuth := setupTestServer(map[string]any{"NewPassword": "abc123"}, http.StatusOK)
+	defer server.Close()
+	sf := buildSalesforceStruct(&sfAuth)
+
+	password, err := sf.ResetPassword("005000000000001AAA")
+	if err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if password != "abc123" {
+		t.Errorf("ResetPassword() = %v, want abc123", password)
+	}
+	if _, err := sf.ResetPassword(""); err == nil {
+		t.Error("ResetPassword() expected an error without a user id")
+	}
+}
@@ -0,0 +1,136 @@
+package salesforce
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// MarketingCloudSignatureHeader is the header used by Marketing Cloud event notifications
+	MarketingCloudSignatureHeader = "X-Sfmc-Ens-Signature"
+	webhookMaxBytes               = 10 << 20
+	canvasAlgorithm               = "HMACSHA256"
+)
+
+// ErrInvalidSignature is returned when a callback signature is missing or does not match its payload
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// VerifyHMACSignature verifies that signature is the HMAC-SHA256 of body using secret.
+// The signature may be base64 or hex encoded.
+func VerifyHMACSignature(body []byte, signature string, secret []byte) error {
+	if len(secret) == 0 {
+		return errors.New("signature secret cannot be empty")
+	}
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return ErrInvalidSignature
+	}
+	decoded, err := decodeSignature(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(decoded, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyCertificateSignature verifies that signature is the SHA256 with RSA signature of body made
+// with the private key of the given certificate. The signature may be base64 or hex encoded.
+func VerifyCertificateSignature(body []byte, signature string, cert *x509.Certificate) error {
+	if cert == nil {
+		return errors.New("certificate cannot be nil")
+	}
+	decoded, err := decodeSignature(strings.TrimSpace(signature))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if err := cert.CheckSignature(x509.SHA256WithRSA, body, decoded); err != nil {
+		return errors.Join(ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+func decodeSignature(signature string) ([]byte, error) {
+	if decoded, err := hex.DecodeString(signature); err == nil && len(decoded) == sha256.Size {
+		return decoded, nil
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(signature); err == nil {
+		return decoded, nil
+	}
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(signature, "="))
+}
+
+// NewSignatureVerifier returns a handler that verifies the HMAC-SHA256 signature found in the given
+// header before passing the request to next. Requests with missing or invalid signatures are rejected
+// with 401 Unauthorized. The body remains readable by next.
+func NewSignatureVerifier(secret []byte, header string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, webhookMaxBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := VerifyHMACSignature(body, r.Header.Get(header), secret); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CanvasRequest is the payload of a verified Canvas signed request
+type CanvasRequest struct {
+	Algorithm string         `json:"algorithm"`
+	IssuedAt  int64          `json:"issuedAt"`
+	UserId    string         `json:"userId"`
+	Client    CanvasClient   `json:"client"`
+	Context   map[string]any `json:"context"`
+}
+
+type CanvasClient struct {
+	OAuthToken   string `json:"oauthToken"`
+	InstanceId   string `json:"instanceId"`
+	InstanceUrl  string `json:"instanceUrl"`
+	TargetOrigin string `json:"targetOrigin"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// VerifyCanvasSignedRequest verifies a Canvas signed request of the form "signature.payload" using the
+// consumer secret of the connected app, and decodes the payload
+func VerifyCanvasSignedRequest(signedRequest string, consumerSecret string) (CanvasRequest, error) {
+	signature, payload, found := strings.Cut(signedRequest, ".")
+	if !found {
+		return CanvasRequest{}, errors.New("signed request must be of the format signature.payload")
+	}
+	if err := VerifyHMACSignature([]byte(payload), signature, []byte(consumerSecret)); err != nil {
+		return CanvasRequest{}, err
+	}
+
+	decodedPayload, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return CanvasRequest{}, fmt.Errorf("decoding signed request payload: %w", err)
+	}
+	canvasRequest := CanvasRequest{}
+	if err := json.Unmarshal(decodedPayload, &canvasRequest); err != nil {
+		return CanvasRequest{}, err
+	}
+	if !strings.EqualFold(canvasRequest.Algorithm, canvasAlgorithm) {
+		return CanvasRequest{}, fmt.Errorf("unsupported algorithm: %s", canvasRequest.Algorithm)
+	}
+	return canvasRequest, nil
+}
@@ -0,0 +1,229 @@
+package salesforce
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signHMAC(body string, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+func TestVerifyHMACSignature(t *testing.T) {
+	body := `{"eventCategoryType":"TransactionalSendEvents.EmailSent"}`
+	signature := signHMAC(body, "secret")
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		secret    string
+		wantErr   bool
+	}{
+		{
+			name:      "valid_base64_signature",
+			body:      body,
+			signature: base64.StdEncoding.EncodeToString(signature),
+			secret:    "secret",
+			wantErr:   false,
+		},
+		{
+			name:      "valid_hex_signature",
+			body:      body,
+			signature: hex.EncodeToString(signature),
+			secret:    "secret",
+			wantErr:   false,
+		},
+		{
+			name:      "tampered_body",
+			body:      body + " ",
+			signature: base64.StdEncoding.EncodeToString(signature),
+			secret:    "secret",
+			wantErr:   true,
+		},
+		{
+			name:      "wrong_secret",
+			body:      body,
+			signature: base64.StdEncoding.EncodeToString(signature),
+			secret:    "other",
+			wantErr:   true,
+		},
+		{
+			name:      "missing_signature",
+			body:      body,
+			signature: "",
+			secret:    "secret",
+			wantErr:   true,
+		},
+		{
+			name:      "empty_secret",
+			body:      body,
+			signature: base64.StdEncoding.EncodeToString(signature),
+			secret:    "",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyHMACSignature([]byte(tt.body), tt.signature, []byte(tt.secret))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyHMACSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCertificateSignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "salesforce"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"data":"value"}`)
+	digest := sha256.Sum256(body)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyCertificateSignature(body, base64.StdEncoding.EncodeToString(signature), cert); err != nil {
+		t.Errorf("VerifyCertificateSignature() error = %v, want nil", err)
+	}
+	if err := VerifyCertificateSignature([]byte("tampered"), base64.StdEncoding.EncodeToString(signature), cert); err == nil {
+		t.Errorf("VerifyCertificateSignature() expected error for tampered body")
+	}
+	if err := VerifyCertificateSignature(body, "signature", nil); err == nil {
+		t.Errorf("VerifyCertificateSignature() expected error for nil certificate")
+	}
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	body := `{"event":"value"}`
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+	}{
+		{
+			name:       "valid_signature",
+			signature:  base64.StdEncoding.EncodeToString(signHMAC(body, "secret")),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid_signature",
+			signature:  base64.StdEncoding.EncodeToString(signHMAC(body, "other")),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				receivedBody = string(data)
+			})
+			handler := NewSignatureVerifier([]byte("secret"), MarketingCloudSignatureHeader, next)
+			request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			request.Header.Set(MarketingCloudSignatureHeader, tt.signature)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %v, want %v", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && receivedBody != body {
+				t.Errorf("ServeHTTP() body = %v, want %v", receivedBody, body)
+			}
+		})
+	}
+}
+
+func TestVerifyCanvasSignedRequest(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{
+		"algorithm": "HMACSHA256",
+		"issuedAt": 1234,
+		"userId": "005xx000001SwR6AAK",
+		"client": {"oauthToken": "token", "instanceUrl": "https://example.my.salesforce.com"},
+		"context": {"user": {"userName": "user@example.com"}}
+	}`))
+	badAlgorithm := base64.StdEncoding.EncodeToString([]byte(`{"algorithm": "NONE"}`))
+	sign := func(p string) string {
+		return base64.StdEncoding.EncodeToString(signHMAC(p, "consumersecret"))
+	}
+
+	tests := []struct {
+		name          string
+		signedRequest string
+		wantUserId    string
+		wantErr       bool
+	}{
+		{
+			name:          "valid_signed_request",
+			signedRequest: sign(payload) + "." + payload,
+			wantUserId:    "005xx000001SwR6AAK",
+			wantErr:       false,
+		},
+		{
+			name:          "invalid_signature",
+			signedRequest: sign("other") + "." + payload,
+			wantErr:       true,
+		},
+		{
+			name:          "invalid_format",
+			signedRequest: payload,
+			wantErr:       true,
+		},
+		{
+			name:          "unsupported_algorithm",
+			signedRequest: sign(badAlgorithm) + "." + badAlgorithm,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyCanvasSignedRequest(tt.signedRequest, "consumersecret")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCanvasSignedRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.UserId != tt.wantUserId {
+				t.Errorf(
+					"VerifyCanvasSignedRequest() userId = %v, want %v",
+					got.UserId,
+					tt.wantUserId,
+				)
+			}
+			if !tt.wantErr && got.Client.InstanceUrl != "https://example.my.salesforce.com" {
+				t.Errorf("VerifyCanvasSignedRequest() instanceUrl = %v", got.Client.InstanceUrl)
+			}
+		})
+	}
+}